@@ -72,6 +72,7 @@ var (
 
 	// We don't support dropping column with index covered now.
 	errCantDropColWithIndex      = terror.ClassDDL.New(mysql.ErrUnsupportedDDLOperation, fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation], "drop column with index"))
+	errCantDropPartitionColumn   = terror.ClassDDL.New(mysql.ErrUnsupportedDDLOperation, fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation], "drop column in partition expression"))
 	errUnsupportedAddColumn      = terror.ClassDDL.New(mysql.ErrUnsupportedDDLOperation, fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation], "add column"))
 	errUnsupportedModifyColumn   = terror.ClassDDL.New(mysql.ErrUnsupportedDDLOperation, fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation], "modify column: %s"))
 	errUnsupportedModifyCharset  = terror.ClassDDL.New(mysql.ErrUnsupportedDDLOperation, fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation], "modify %s"))
@@ -146,12 +147,52 @@ var (
 
 	// ErrPartitionMgmtOnNonpartitioned returns it's not a partition table.
 	ErrPartitionMgmtOnNonpartitioned = terror.ClassDDL.New(mysql.ErrPartitionMgmtOnNonpartitioned, mysql.MySQLErrName[mysql.ErrPartitionMgmtOnNonpartitioned])
+	// ErrDropPartitionNonExistent returns error in list of partition.
+	ErrDropPartitionNonExistent = terror.ClassDDL.New(mysql.ErrDropPartitionNonExistent, mysql.MySQLErrName[mysql.ErrDropPartitionNonExistent])
+	// ErrSameNamePartition returns duplicate partition name.
+	ErrSameNamePartition = terror.ClassDDL.New(mysql.ErrSameNamePartition, mysql.MySQLErrName[mysql.ErrSameNamePartition])
+	// ErrRangeNotIncreasing returns values less than value must be strictly increasing for each partition.
+	ErrRangeNotIncreasing = terror.ClassDDL.New(mysql.ErrRangeNotIncreasing, mysql.MySQLErrName[mysql.ErrRangeNotIncreasing])
+	// ErrPartitionMaxvalue returns maxvalue can only be used in last partition definition.
+	ErrPartitionMaxvalue = terror.ClassDDL.New(mysql.ErrPartitionMaxvalue, mysql.MySQLErrName[mysql.ErrPartitionMaxvalue])
+	// ErrDropLastPartition returns cannot remove all partitions, use drop table instead.
+	ErrDropLastPartition = terror.ClassDDL.New(mysql.ErrDropLastPartition, mysql.MySQLErrName[mysql.ErrDropLastPartition])
+	// ErrTooManyPartitions returns too many partitions were defined.
+	ErrTooManyPartitions = terror.ClassDDL.New(mysql.ErrTooManyPartitions, mysql.MySQLErrName[mysql.ErrTooManyPartitions])
+	// ErrPartitionFunctionIsNotAllowed returns this partition function is not allowed.
+	ErrPartitionFunctionIsNotAllowed = terror.ClassDDL.New(mysql.ErrPartitionFunctionIsNotAllowed, mysql.MySQLErrName[mysql.ErrPartitionFunctionIsNotAllowed])
+	// ErrPartitionFuncNotAllowed returns the partition function returns the wrong type.
+	ErrPartitionFuncNotAllowed = terror.ClassDDL.New(mysql.ErrPartitionFuncNotAllowed, mysql.MySQLErrName[mysql.ErrPartitionFuncNotAllowed])
+	// ErrWrongExprInPartitionFunc returns constant, random or timezone-dependent expressions in partitioning function are not allowed.
+	ErrWrongExprInPartitionFunc = terror.ClassDDL.New(mysql.ErrWrongExprInPartitionFunc, mysql.MySQLErrName[mysql.ErrWrongExprInPartitionFunc])
+	// ErrFieldTypeNotAllowedAsPartitionField returns the field is of a not allowed type for this type of partitioning.
+	ErrFieldTypeNotAllowedAsPartitionField = terror.ClassDDL.New(mysql.ErrFieldTypeNotAllowedAsPartitionField, mysql.MySQLErrName[mysql.ErrFieldTypeNotAllowedAsPartitionField])
+	// ErrPartitionColumnList returns inconsistency in usage of column lists for partitioning.
+	ErrPartitionColumnList = terror.ClassDDL.New(mysql.ErrPartitionColumnList, mysql.MySQLErrName[mysql.ErrPartitionColumnList])
+	// ErrPartitionWrongNoPart returns wrong number of partitions defined.
+	ErrPartitionWrongNoPart = terror.ClassDDL.New(mysql.ErrPartitionWrongNoPart, mysql.MySQLErrName[mysql.ErrPartitionWrongNoPart])
+	// ErrUniqueKeyNeedAllFieldsInPf returns must include all columns in the table's partitioning function.
+	ErrUniqueKeyNeedAllFieldsInPf = terror.ClassDDL.New(mysql.ErrUniqueKeyNeedAllFieldsInPf, mysql.MySQLErrName[mysql.ErrUniqueKeyNeedAllFieldsInPf])
+	// ErrPartitionsMustBeDefined returns each partition must be defined.
+	ErrPartitionsMustBeDefined = terror.ClassDDL.New(mysql.ErrPartitionsMustBeDefined, mysql.MySQLErrName[mysql.ErrPartitionsMustBeDefined])
+	// ErrPartitionRequiresValues returns requires definition of VALUES for each partition.
+	ErrPartitionRequiresValues = terror.ClassDDL.New(mysql.ErrPartitionRequiresValues, mysql.MySQLErrName[mysql.ErrPartitionRequiresValues])
+	// ErrPartitionWrongValues returns only a specific partitioning can use VALUES in partition definition.
+	ErrPartitionWrongValues = terror.ClassDDL.New(mysql.ErrPartitionWrongValues, mysql.MySQLErrName[mysql.ErrPartitionWrongValues])
+	// ErrNoParts returns number of partitions = 0 is not an allowed value.
+	ErrNoParts = terror.ClassDDL.New(mysql.ErrNoParts, mysql.MySQLErrName[mysql.ErrNoParts])
+	// ErrOnlyOnRangeListPartition returns the partition management is only supported on RANGE partitions.
+	ErrOnlyOnRangeListPartition = terror.ClassDDL.New(mysql.ErrOnlyOnRangeListPartition, mysql.MySQLErrName[mysql.ErrOnlyOnRangeListPartition])
+	// ErrValuesIsNotIntType returns VALUES value for partition must have type INT.
+	ErrValuesIsNotIntType = terror.ClassDDL.New(mysql.ErrValuesIsNotIntType, mysql.MySQLErrName[mysql.ErrValuesIsNotIntType])
 	// ErrWarnDataTruncated returns data truncated error.
 	ErrWarnDataTruncated = terror.ClassDDL.New(mysql.WarnDataTruncated, mysql.MySQLErrName[mysql.WarnDataTruncated])
 	// ErrAlterOperationNotSupported returns when alter operations is not supported.
 	ErrAlterOperationNotSupported = terror.ClassDDL.New(mysql.ErrAlterOperationNotSupportedReason, mysql.MySQLErrName[mysql.ErrAlterOperationNotSupportedReason])
 	// ErrTableCantHandleFt returns FULLTEXT keys are not supported by table type
 	ErrTableCantHandleFt = terror.ClassDDL.New(mysql.ErrTableCantHandleFt, mysql.MySQLErrName[mysql.ErrTableCantHandleFt])
+	// ErrWrongObject returns for wrong object.
+	ErrWrongObject = terror.ClassDDL.New(mysql.ErrWrongObject, mysql.MySQLErrName[mysql.ErrWrongObject])
 )
 
 // DDL is responsible for updating schema in data store and maintaining in-memory InfoSchema cache.
@@ -160,6 +201,8 @@ type DDL interface {
 	DropSchema(ctx sessionctx.Context, schema model.CIStr) error
 	CreateTable(ctx sessionctx.Context, stmt *ast.CreateTableStmt) error
 	DropTable(ctx sessionctx.Context, tableIdent ast.Ident) (err error)
+	CreateView(ctx sessionctx.Context, stmt *ast.CreateViewStmt) error
+	DropView(ctx sessionctx.Context, tableIdent ast.Ident) (err error)
 	CreateIndex(ctx sessionctx.Context, tableIdent ast.Ident, keyType ast.IndexKeyType, indexName model.CIStr,
 		columnNames []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error
 	DropIndex(ctx sessionctx.Context, tableIdent ast.Ident, indexName model.CIStr, ifExists bool) error
@@ -404,7 +447,7 @@ func checkJobMaxInterval(job *model.Job) time.Duration {
 	if job.Type == model.ActionAddIndex || job.Type == model.ActionAddPrimaryKey {
 		return 3 * time.Second
 	}
-	if job.Type == model.ActionCreateTable || job.Type == model.ActionCreateSchema {
+	if job.Type == model.ActionCreateTable || job.Type == model.ActionCreateView || job.Type == model.ActionCreateSchema {
 		return 500 * time.Millisecond
 	}
 	return 1 * time.Second
@@ -557,6 +600,7 @@ func init() {
 		mysql.ErrUnsupportedDDLOperation:              mysql.ErrUnsupportedDDLOperation,
 		mysql.ErrUnsupportedOnGeneratedColumn:         mysql.ErrUnsupportedOnGeneratedColumn,
 		mysql.ErrViewWrongList:                        mysql.ErrViewWrongList,
+		mysql.ErrValuesIsNotIntType:                   mysql.ErrValuesIsNotIntType,
 		mysql.ErrWrongColumnName:                      mysql.ErrWrongColumnName,
 		mysql.ErrWrongDBName:                          mysql.ErrWrongDBName,
 		mysql.ErrWrongExprInPartitionFunc:             mysql.ErrWrongExprInPartitionFunc,
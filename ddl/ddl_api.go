@@ -333,7 +333,7 @@ func buildColumnAndConstraint(ctx sessionctx.Context, offset int,
 func checkColumnDefaultValue(ctx sessionctx.Context, col *table.Column, value interface{}) (bool, interface{}, error) {
 	hasDefaultValue := true
 	if value != nil && (col.Tp == mysql.TypeTinyBlob || col.Tp == mysql.TypeMediumBlob ||
-		col.Tp == mysql.TypeLongBlob || col.Tp == mysql.TypeBlob) {
+		col.Tp == mysql.TypeLongBlob || col.Tp == mysql.TypeBlob || col.Tp == mysql.TypeJSON) {
 		// In non-strict SQL mode.
 		if !ctx.GetSessionVars().SQLMode.HasStrictMode() && value == "" {
 			if col.Tp == mysql.TypeBlob || col.Tp == mysql.TypeLongBlob {
@@ -463,6 +463,24 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 }
 
 func getDefaultValue(ctx sessionctx.Context, col *table.Column, c *ast.ColumnOption) (interface{}, error) {
+	if x, ok := c.Expr.(*ast.FuncCallExpr); ok && x.FnName.L == ast.CurrentTimestamp {
+		// CURRENT_TIMESTAMP is kept as it is and evaluated when a row is written.
+		if col.Tp != mysql.TypeTimestamp && col.Tp != mysql.TypeDatetime {
+			return nil, ErrInvalidDefaultValue.GenWithStackByArgs(col.Name.O)
+		}
+		defaultFsp := int64(0)
+		if len(x.Args) == 1 {
+			v, err := expression.EvalAstExpr(ctx, x.Args[0])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			defaultFsp = v.GetInt64()
+		}
+		if defaultFsp != int64(col.Decimal) {
+			return nil, ErrInvalidDefaultValue.GenWithStackByArgs(col.Name.O)
+		}
+		return strings.ToUpper(ast.CurrentTimestamp), nil
+	}
 	v, err := expression.EvalAstExpr(ctx, c.Expr)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -840,6 +858,16 @@ func buildTableInfoWithCheck(ctx sessionctx.Context, d *ddl, s *ast.CreateTableS
 	}
 	tbInfo.Charset, tbInfo.Collate = charset.GetDefaultCharsetAndCollate()
 
+	tbInfo.Partition, err = buildTablePartitionInfo(ctx, d, s)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if tbInfo.Partition != nil {
+		if err = checkPartitionExpr(ctx, tbInfo, s.Partition.Expr); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	return tbInfo, nil
 }
 
@@ -890,6 +918,57 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	return errors.Trace(err)
 }
 
+// CreateView creates a view, the view is stored as a table whose View field is set.
+func (d *ddl) CreateView(ctx sessionctx.Context, s *ast.CreateViewStmt) (err error) {
+	ident := ast.Ident{Schema: s.ViewName.Schema, Name: s.ViewName.Name}
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(ident.Schema)
+	}
+	oldViewTblID := int64(0)
+	if oldView, err := is.TableByName(ident.Schema, ident.Name); err == nil {
+		if !oldView.Meta().IsView() {
+			return ErrWrongObject.GenWithStackByArgs(ident.Schema, ident.Name, "VIEW")
+		}
+		if !s.OrReplace {
+			return infoschema.ErrTableExists.GenWithStackByArgs(ident)
+		}
+		oldViewTblID = oldView.Meta().ID
+	}
+
+	viewCols := s.Cols
+	if len(viewCols) == 0 {
+		viewCols = s.SchemaCols
+	}
+	cols := make([]*table.Column, len(viewCols))
+	for i, name := range viewCols {
+		cols[i] = table.ToColumn(&model.ColumnInfo{
+			Name:   name,
+			Offset: i,
+			State:  model.StatePublic,
+		})
+	}
+	tbInfo, err := buildTableInfo(ctx, d, ident.Name, cols, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo.Charset, tbInfo.Collate = schema.Charset, schema.Collate
+	tbInfo.View = &model.ViewInfo{SelectStmt: s.Select.Text(), Cols: viewCols}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionCreateView,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{tbInfo, s.OrReplace, oldViewTblID},
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func checkCharsetAndCollation(cs string, co string) error {
 	if !charset.ValidCharsetAndCollation(cs, co) {
 		return ErrUnknownCharacterSet.GenWithStackByArgs(cs)
@@ -965,6 +1044,12 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 			err = d.ChangeColumn(ctx, ident, spec)
 		case ast.AlterTableAlterColumn:
 			err = d.AlterColumn(ctx, ident, spec)
+		case ast.AlterTableAddPartitions:
+			err = d.AddTablePartitions(ctx, ident, spec)
+		case ast.AlterTableDropPartition:
+			err = d.DropTablePartition(ctx, ident, spec)
+		case ast.AlterTableTruncatePartition:
+			err = d.TruncateTablePartition(ctx, ident, spec)
 		case ast.AlterTablePartition:
 			// Prevent silent succeed if user executes ALTER TABLE x PARTITION BY ...
 			err = errors.New("alter table partition is unsupported")
@@ -1010,6 +1095,119 @@ func (d *ddl) ShardRowID(ctx sessionctx.Context, tableIdent ast.Ident, uVal uint
 	return errors.Trace(err)
 }
 
+// AddTablePartitions adds partitions to a RANGE partitioned table.
+func (d *ddl) AddTablePartitions(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta := t.Meta()
+	pi, err := getRangePartitionInfo(meta, "ADD")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(spec.PartDefinitions) == 0 {
+		return ErrPartitionsMustBeDefined.GenWithStackByArgs("RANGE")
+	}
+
+	defs, err := buildPartitionDefinitions(ctx, d, pi.Type, spec.PartDefinitions)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	allDefs := make([]model.PartitionDefinition, 0, len(pi.Definitions)+len(defs))
+	allDefs = append(allDefs, pi.Definitions...)
+	allDefs = append(allDefs, defs...)
+	if err = checkPartitionDefinitions(pi, allDefs); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddTablePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{&model.PartitionInfo{Type: pi.Type, Definitions: defs}},
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// DropTablePartition drops partitions from a RANGE partitioned table.
+func (d *ddl) DropTablePartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta := t.Meta()
+	pi, err := getRangePartitionInfo(meta, "DROP")
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	partNames := make([]model.CIStr, 0, len(spec.PartitionNames))
+	for _, name := range spec.PartitionNames {
+		if pi.FindPartitionDefinitionByName(name.L) < 0 {
+			err = ErrDropPartitionNonExistent.GenWithStackByArgs("DROP")
+			if spec.IfExists {
+				ctx.GetSessionVars().StmtCtx.AppendNote(err)
+				continue
+			}
+			return err
+		}
+		partNames = append(partNames, name)
+	}
+	if len(partNames) == 0 {
+		return nil
+	}
+	if len(partNames) >= len(pi.Definitions) {
+		return errors.Trace(ErrDropLastPartition)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionDropTablePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partitionNameList(partNames)},
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// TruncateTablePartition removes all the rows of the partitions.
+func (d *ddl) TruncateTablePartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta := t.Meta()
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	for _, name := range spec.PartitionNames {
+		if pi.FindPartitionDefinitionByName(name.L) < 0 {
+			return table.ErrUnknownPartition.GenWithStackByArgs(name.O, meta.Name.O)
+		}
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionTruncateTablePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partitionNameList(spec.PartitionNames)},
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) getSchemaAndTableByIdent(ctx sessionctx.Context, tableIdent ast.Ident) (dbInfo *model.DBInfo, t table.Table, err error) {
 	is := d.GetInfoSchemaWithInterceptor(ctx)
 	schema, ok := is.SchemaByName(tableIdent.Schema)
@@ -1129,6 +1327,9 @@ func (d *ddl) DropColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTa
 	if err = isDroppableColumn(tblInfo, colName); err != nil {
 		return errors.Trace(err)
 	}
+	if err = checkDropPartitionColumn(ctx, tblInfo, colName); err != nil {
+		return errors.Trace(err)
+	}
 	// We don't support dropping column with PK handle covered now.
 	if col.IsPKHandleColumn(tblInfo) {
 		return errUnsupportedPKHandle
@@ -1757,6 +1958,29 @@ func (d *ddl) DropTable(ctx sessionctx.Context, ti ast.Ident) (err error) {
 	return errors.Trace(err)
 }
 
+// DropView drops a view.
+func (d *ddl) DropView(ctx sessionctx.Context, ti ast.Ident) (err error) {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !tb.Meta().IsView() {
+		return ErrWrongObject.GenWithStackByArgs(ti.Schema, ti.Name, "VIEW")
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tb.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionDropView,
+		BinlogInfo: &model.HistoryInfo{},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func getAnonymousIndex(t table.Table, colName model.CIStr) model.CIStr {
 	id := 2
 	l := len(t.Indices())
@@ -1812,10 +2036,15 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	// to job queue, the fail path logic is super fast.
 	// After DDL job is put to the queue, and if the check fail, TiDB will run the DDL cancel logic.
 	// The recover step causes DDL wait a few seconds, makes the unit test painfully slow.
-	_, err = buildIndexColumns(tblInfo.Columns, idxColNames)
+	idxColumns, err := buildIndexColumns(tblInfo.Columns, idxColNames)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if unique && tblInfo.GetPartitionInfo() != nil {
+		if err = checkUniqueIndexContainPartitionColumns(ctx, tblInfo, idxColumns); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	// May be truncate comment here, when index comment too long and sql_mode is't strict.
 	if _, err = validateCommentLength(ctx.GetSessionVars(), indexName.String(), indexOption); err != nil {
 		return errors.Trace(err)
@@ -1283,8 +1283,72 @@ func (w *worker) addPhysicalTableIndex(t table.PhysicalTable, indexInfo *model.I
 }
 
 // addTableIndex handles the add index reorganization state for a table.
+// For a partitioned table, the partitions are handled one by one from the one recorded in reorgInfo.
 func (w *worker) addTableIndex(t table.Table, idx *model.IndexInfo, reorgInfo *reorgInfo) error {
-	return w.addPhysicalTableIndex(t.(table.PhysicalTable), idx, reorgInfo)
+	tbl, ok := t.(table.PartitionedTable)
+	if !ok {
+		return w.addPhysicalTableIndex(t.(table.PhysicalTable), idx, reorgInfo)
+	}
+
+	for {
+		p := tbl.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, t.Meta().ID)
+		}
+		if err := w.addPhysicalTableIndex(p, idx, reorgInfo); err != nil {
+			return errors.Trace(err)
+		}
+		finish, err := w.updateReorgInfo(tbl, reorgInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if finish {
+			return nil
+		}
+	}
+}
+
+// updateReorgInfo moves reorgInfo to the next partition of the table.
+// It returns true if there are no more partitions to handle.
+func (w *worker) updateReorgInfo(t table.PartitionedTable, reorg *reorgInfo) (bool, error) {
+	pid, err := findNextPartitionID(reorg.PhysicalTableID, t.Meta().GetPartitionInfo().Definitions)
+	if err != nil {
+		// Fatal error, should not run here.
+		logutil.BgLogger().Error("[ddl] find next partition ID failed", zap.Reflect("table", t), zap.Error(err))
+		return false, errors.Trace(err)
+	}
+	if pid == 0 {
+		// Next partition does not exist, all the job done.
+		return true, nil
+	}
+
+	start, end, err := getTableRange(reorg.d, t.GetPartition(pid), reorg.Job.SnapshotVer, reorg.Job.Priority)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	logutil.BgLogger().Info("[ddl] job update reorgInfo", zap.Int64("jobID", reorg.Job.ID), zap.Int64("partitionTableID", pid),
+		zap.Int64("startHandle", start), zap.Int64("endHandle", end))
+	reorg.StartHandle, reorg.EndHandle, reorg.PhysicalTableID = start, end, pid
+
+	// Write the reorg info to store so the whole reorganize process can recover from panic.
+	err = kv.RunInNewTxn(reorg.d.store, true, func(txn kv.Transaction) error {
+		return errors.Trace(reorg.UpdateReorgMeta(txn, reorg.StartHandle, reorg.EndHandle, reorg.PhysicalTableID))
+	})
+	return false, errors.Trace(err)
+}
+
+// findNextPartitionID finds the next partition ID in the PartitionDefinition array.
+// Returns 0 if current partition is already the last one.
+func findNextPartitionID(currentPartition int64, defs []model.PartitionDefinition) (int64, error) {
+	for i, def := range defs {
+		if currentPartition == def.ID {
+			if i == len(defs)-1 {
+				return 0, nil
+			}
+			return defs[i+1].ID, nil
+		}
+	}
+	return 0, errors.Errorf("partition id not found %d", currentPartition)
 }
 
 func allocateIndexID(tblInfo *model.TableInfo) int64 {
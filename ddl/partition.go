@@ -0,0 +1,531 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+)
+
+// partitionCountLimit is limit of the number of partitions in a table.
+const partitionCountLimit = 1024
+
+// allowedPartitionFuncs are the functions that can be used in the partition expression.
+// See https://dev.mysql.com/doc/refman/5.7/en/partitioning-limitations-functions.html
+var allowedPartitionFuncs = map[string]struct{}{
+	"abs":            {},
+	"ceiling":        {},
+	"ceil":           {},
+	"datediff":       {},
+	"day":            {},
+	"dayofmonth":     {},
+	"dayofweek":      {},
+	"dayofyear":      {},
+	"extract":        {},
+	"floor":          {},
+	"hour":           {},
+	"microsecond":    {},
+	"minute":         {},
+	"mod":            {},
+	"month":          {},
+	"quarter":        {},
+	"second":         {},
+	"time_to_sec":    {},
+	"to_days":        {},
+	"to_seconds":     {},
+	"unix_timestamp": {},
+	"weekday":        {},
+	"year":           {},
+	"yearweek":       {},
+}
+
+// buildTablePartitionInfo builds the partition info of the table from the PARTITION BY clause.
+func buildTablePartitionInfo(ctx sessionctx.Context, d *ddl, s *ast.CreateTableStmt) (*model.PartitionInfo, error) {
+	if s.Partition == nil {
+		return nil, nil
+	}
+	var buf strings.Builder
+	s.Partition.Expr.Format(&buf)
+	pi := &model.PartitionInfo{
+		Type: s.Partition.Tp,
+		Expr: buf.String(),
+		Num:  s.Partition.Num,
+	}
+
+	defs := s.Partition.Definitions
+	switch s.Partition.Tp {
+	case model.PartitionTypeRange:
+		if len(defs) == 0 {
+			return nil, ErrPartitionsMustBeDefined.GenWithStackByArgs("RANGE")
+		}
+	case model.PartitionTypeHash:
+		if len(defs) == 0 {
+			num := pi.Num
+			if num == 0 {
+				num = 1
+			}
+			if num > partitionCountLimit {
+				return nil, errors.Trace(ErrTooManyPartitions)
+			}
+			defs = make([]*ast.PartitionDefinition, 0, num)
+			for i := uint64(0); i < num; i++ {
+				defs = append(defs, &ast.PartitionDefinition{Name: model.NewCIStr(fmt.Sprintf("p%d", i))})
+			}
+		}
+	}
+	if pi.Num != 0 && pi.Num != uint64(len(defs)) {
+		return nil, errors.Trace(ErrPartitionWrongNoPart)
+	}
+	pi.Num = uint64(len(defs))
+
+	var err error
+	pi.Definitions, err = buildPartitionDefinitions(ctx, d, pi.Type, defs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = checkPartitionDefinitions(pi, pi.Definitions); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return pi, nil
+}
+
+// buildPartitionDefinitions builds the partition definitions and allocates their physical IDs.
+// The VALUES LESS THAN values of RANGE partitions are evaluated to integers.
+func buildPartitionDefinitions(ctx sessionctx.Context, d *ddl, tp model.PartitionType, defs []*ast.PartitionDefinition) ([]model.PartitionDefinition, error) {
+	if len(defs) > partitionCountLimit {
+		return nil, errors.Trace(ErrTooManyPartitions)
+	}
+	ret := make([]model.PartitionDefinition, 0, len(defs))
+	for _, def := range defs {
+		hasValues := def.MaxValue || len(def.LessThan) > 0
+		if tp == model.PartitionTypeHash && hasValues {
+			return nil, ErrPartitionWrongValues.GenWithStackByArgs("RANGE", "LESS THAN")
+		}
+		if tp == model.PartitionTypeRange && !hasValues {
+			return nil, ErrPartitionRequiresValues.GenWithStackByArgs("RANGE", "LESS THAN")
+		}
+		if len(def.LessThan) > 1 {
+			return nil, errors.Trace(ErrPartitionColumnList)
+		}
+		partDef := model.PartitionDefinition{
+			Name:    def.Name,
+			Comment: def.Comment,
+		}
+		if def.MaxValue {
+			partDef.LessThan = []string{tables.PartitionMaxValue}
+		} else if len(def.LessThan) > 0 {
+			bound, err := evalPartitionBound(ctx, def)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			partDef.LessThan = []string{bound}
+		}
+		ret = append(ret, partDef)
+	}
+
+	// When this function is called by BuildTableInfoFromAST, the `ddl` structure may be nil.
+	if d != nil {
+		genIDs, err := d.genGlobalIDs(len(ret))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for i := range ret {
+			ret[i].ID = genIDs[i]
+		}
+	}
+	return ret, nil
+}
+
+// evalPartitionBound evaluates the VALUES LESS THAN value of the partition, it must be an integer.
+func evalPartitionBound(ctx sessionctx.Context, def *ast.PartitionDefinition) (string, error) {
+	val, err := expression.EvalAstExpr(ctx, def.LessThan[0])
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	switch val.Kind() {
+	case types.KindInt64:
+		return strconv.FormatInt(val.GetInt64(), 10), nil
+	case types.KindUint64:
+		return strconv.FormatInt(int64(val.GetUint64()), 10), nil
+	}
+	return "", ErrValuesIsNotIntType.GenWithStackByArgs(def.Name.O)
+}
+
+// checkPartitionDefinitions checks the partition names are unique, and the VALUES LESS THAN values
+// of RANGE partitions are strictly increasing.
+func checkPartitionDefinitions(pi *model.PartitionInfo, defs []model.PartitionDefinition) error {
+	if len(defs) > partitionCountLimit {
+		return errors.Trace(ErrTooManyPartitions)
+	}
+	names := make(map[string]struct{}, len(defs))
+	for _, def := range defs {
+		if _, ok := names[def.Name.L]; ok {
+			return ErrSameNamePartition.GenWithStackByArgs(def.Name.O)
+		}
+		names[def.Name.L] = struct{}{}
+	}
+	if pi.Type != model.PartitionTypeRange {
+		return nil
+	}
+
+	for i, def := range defs {
+		if strings.EqualFold(def.LessThan[0], tables.PartitionMaxValue) {
+			if i != len(defs)-1 {
+				return errors.Trace(ErrPartitionMaxvalue)
+			}
+			continue
+		}
+		if i == 0 {
+			continue
+		}
+		prev, err := strconv.ParseInt(defs[i-1].LessThan[0], 10, 64)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cur, err := strconv.ParseInt(def.LessThan[0], 10, 64)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if cur <= prev {
+			return errors.Trace(ErrRangeNotIncreasing)
+		}
+	}
+	return nil
+}
+
+// partitionExprChecker checks the partition expression only contains columns, constants,
+// arithmetic operators and the allowed functions.
+type partitionExprChecker struct {
+	err      error
+	colNames []*ast.ColumnName
+}
+
+// Enter implements ast.Visitor interface.
+func (c *partitionExprChecker) Enter(node ast.Node) (ast.Node, bool) {
+	switch x := node.(type) {
+	case *ast.ColumnNameExpr:
+		c.colNames = append(c.colNames, x.Name)
+		return node, true
+	case ast.ValueExpr, *ast.ParenthesesExpr:
+		return node, false
+	case *ast.BinaryOperationExpr:
+		switch x.Op {
+		case opcode.Plus, opcode.Minus, opcode.Mul, opcode.IntDiv, opcode.Mod:
+			return node, false
+		}
+	case *ast.UnaryOperationExpr:
+		if x.Op == opcode.Minus {
+			return node, false
+		}
+	case *ast.FuncCallExpr:
+		if _, ok := allowedPartitionFuncs[x.FnName.L]; ok {
+			return node, false
+		}
+	}
+	c.err = errors.Trace(ErrPartitionFunctionIsNotAllowed)
+	return node, true
+}
+
+// Leave implements ast.Visitor interface.
+func (c *partitionExprChecker) Leave(node ast.Node) (ast.Node, bool) {
+	return node, c.err == nil
+}
+
+// checkPartitionExpr checks the partition expression of the table is valid, which means:
+//  1. It only refers to the columns of the table with the allowed functions and operators.
+//  2. It returns an integer.
+//  3. All the unique keys of the table contain all the columns it refers to.
+func checkPartitionExpr(ctx sessionctx.Context, tbInfo *model.TableInfo, expr ast.ExprNode) error {
+	checker := &partitionExprChecker{}
+	expr.Accept(checker)
+	if checker.err != nil {
+		return checker.err
+	}
+	if len(checker.colNames) == 0 {
+		return errors.Trace(ErrWrongExprInPartitionFunc)
+	}
+
+	partCols := make([]*model.ColumnInfo, 0, len(checker.colNames))
+	for _, name := range checker.colNames {
+		col := model.FindColumnInfo(tbInfo.Columns, name.Name.L)
+		if col == nil {
+			return ErrBadField.GenWithStackByArgs(name.Name.O, "partition function")
+		}
+		partCols = append(partCols, col)
+	}
+
+	columns, names := expression.ColumnInfos2ColumnsAndNames(ctx, model.CIStr{}, tbInfo.Name, tbInfo.Columns)
+	e, err := expression.ParseSimpleExprWithSchema(ctx, tbInfo.Partition.Expr, expression.NewSchema(columns...), names)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if e.GetType().EvalType() != types.ETInt {
+		if _, ok := expr.(*ast.ColumnNameExpr); ok {
+			return ErrFieldTypeNotAllowedAsPartitionField.GenWithStackByArgs(partCols[0].Name.O)
+		}
+		return ErrPartitionFuncNotAllowed.GenWithStackByArgs("PARTITION")
+	}
+	return errors.Trace(checkUniqueKeysContainPartitionColumns(tbInfo, partCols))
+}
+
+// checkUniqueKeysContainPartitionColumns checks every unique key of the table contains all the
+// columns in the partition expression, so the uniqueness can be checked inside one partition.
+func checkUniqueKeysContainPartitionColumns(tbInfo *model.TableInfo, partCols []*model.ColumnInfo) error {
+	if tbInfo.PKIsHandle {
+		pk := tbInfo.GetPkColInfo()
+		for _, col := range partCols {
+			if col.ID != pk.ID {
+				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
+			}
+		}
+	}
+	for _, idx := range tbInfo.Indices {
+		if !idx.Unique {
+			continue
+		}
+		for _, col := range partCols {
+			if indexContainsColumn(idx, col) {
+				continue
+			}
+			if idx.Primary {
+				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
+			}
+			return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
+		}
+	}
+	return nil
+}
+
+// findPartitionColumns returns the columns referred by the partition expression of the table.
+func findPartitionColumns(ctx sessionctx.Context, tbInfo *model.TableInfo) ([]*model.ColumnInfo, error) {
+	columns, names := expression.ColumnInfos2ColumnsAndNames(ctx, model.CIStr{}, tbInfo.Name, tbInfo.Columns)
+	schema := expression.NewSchema(columns...)
+	e, err := expression.ParseSimpleExprWithSchema(ctx, tbInfo.Partition.Expr, schema, names)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cols := expression.ExtractColumns(e)
+	ret := make([]*model.ColumnInfo, 0, len(cols))
+	for _, col := range cols {
+		ret = append(ret, tbInfo.Columns[schema.ColumnIndex(col)])
+	}
+	return ret, nil
+}
+
+// checkUniqueIndexContainPartitionColumns checks the new unique index on the partitioned table
+// contains all the columns in the partition expression.
+func checkUniqueIndexContainPartitionColumns(ctx sessionctx.Context, tbInfo *model.TableInfo, idxColumns []*model.IndexColumn) error {
+	partCols, err := findPartitionColumns(ctx, tbInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	idx := &model.IndexInfo{Columns: idxColumns}
+	for _, col := range partCols {
+		if !indexContainsColumn(idx, col) {
+			return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
+		}
+	}
+	return nil
+}
+
+// checkDropPartitionColumn checks the column to drop is not referred by the partition expression.
+func checkDropPartitionColumn(ctx sessionctx.Context, tbInfo *model.TableInfo, colName model.CIStr) error {
+	if tbInfo.GetPartitionInfo() == nil {
+		return nil
+	}
+	partCols, err := findPartitionColumns(ctx, tbInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, col := range partCols {
+		if col.Name.L == colName.L {
+			return errors.Trace(errCantDropPartitionColumn)
+		}
+	}
+	return nil
+}
+
+func indexContainsColumn(idx *model.IndexInfo, col *model.ColumnInfo) bool {
+	for _, idxCol := range idx.Columns {
+		if idxCol.Name.L == col.Name.L {
+			return true
+		}
+	}
+	return false
+}
+
+func onAddTablePartition(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	partInfo := &model.PartitionInfo{}
+	err := job.DecodeArgs(&partInfo)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+
+	// The table may be changed after the job is submitted, so check the partitions again.
+	defs := make([]model.PartitionDefinition, 0, len(pi.Definitions)+len(partInfo.Definitions))
+	defs = append(defs, pi.Definitions...)
+	defs = append(defs, partInfo.Definitions...)
+	if err = checkPartitionDefinitions(pi, defs); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	pi.Definitions = defs
+	pi.Num = uint64(len(defs))
+
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// Finish this job.
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+func onDropTablePartition(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var partNames []string
+	if err := job.DecodeArgs(&partNames); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+
+	droppedIDs := make([]int64, 0, len(partNames))
+	for _, name := range partNames {
+		idx := pi.FindPartitionDefinitionByName(name)
+		if idx < 0 {
+			job.State = model.JobStateCancelled
+			return ver, ErrDropPartitionNonExistent.GenWithStackByArgs("DROP")
+		}
+		if len(pi.Definitions) == 1 {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(ErrDropLastPartition)
+		}
+		droppedIDs = append(droppedIDs, pi.Definitions[idx].ID)
+		pi.Definitions = append(pi.Definitions[:idx], pi.Definitions[idx+1:]...)
+	}
+	pi.Num = uint64(len(pi.Definitions))
+
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// Finish this job.
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+	// The data of the dropped partitions are left, record their key prefixes like dropping table.
+	for _, pid := range droppedIDs {
+		job.Args = append(job.Args, tablecodec.EncodeTablePrefix(pid))
+	}
+	return ver, nil
+}
+
+func onTruncateTablePartition(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var partNames []string
+	if err := job.DecodeArgs(&partNames); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+
+	// Truncating a partition is done by giving it a new physical ID, so the old data become invisible.
+	oldIDs := make([]int64, 0, len(partNames))
+	for _, name := range partNames {
+		idx := pi.FindPartitionDefinitionByName(name)
+		if idx < 0 {
+			job.State = model.JobStateCancelled
+			return ver, table.ErrUnknownPartition.GenWithStackByArgs(name, tblInfo.Name.O)
+		}
+		pid, err := t.GenGlobalID()
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		oldIDs = append(oldIDs, pi.Definitions[idx].ID)
+		pi.Definitions[idx].ID = pid
+	}
+
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// Finish this job.
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	for _, pid := range oldIDs {
+		job.Args = append(job.Args, tablecodec.EncodeTablePrefix(pid))
+	}
+	return ver, nil
+}
+
+// partitionNameList converts the partition names to the lower case strings used in job args.
+func partitionNameList(names []model.CIStr) []string {
+	ret := make([]string, 0, len(names))
+	for _, name := range names {
+		ret = append(ret, name.L)
+	}
+	return ret
+}
+
+// getRangePartitionInfo returns the partition info of the table for ADD/DROP PARTITION, which
+// are only supported on RANGE partitioned tables.
+func getRangePartitionInfo(tblInfo *model.TableInfo, op string) (*model.PartitionInfo, error) {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		return nil, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if pi.Type != model.PartitionTypeRange {
+		return nil, ErrOnlyOnRangeListPartition.GenWithStackByArgs(op)
+	}
+	return pi, nil
+}
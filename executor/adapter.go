@@ -21,10 +21,12 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/planner"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -148,6 +150,8 @@ type ExecStmt struct {
 
 	// OutputNames will be set if using cached plan
 	OutputNames []*types.FieldName
+
+	retryCount int
 }
 
 // OriginText returns original statement as a string.
@@ -155,8 +159,35 @@ func (a *ExecStmt) OriginText() string {
 	return a.Text
 }
 
+// RebuildPlan rebuilds current execute statement plan.
+// It returns the current information schema version that 'a' is using.
+func (a *ExecStmt) RebuildPlan(ctx context.Context) (int64, error) {
+	is, err := getStmtInfoSchema(a.Ctx, a.StmtNode)
+	if err != nil {
+		return 0, err
+	}
+	a.InfoSchema = is
+	if err = plannercore.Preprocess(a.Ctx, a.StmtNode, is); err != nil {
+		return 0, err
+	}
+	p, names, err := planner.Optimize(ctx, a.Ctx, a.StmtNode, is)
+	if err != nil {
+		return 0, err
+	}
+	a.OutputNames = names
+	a.Plan = p
+	return is.SchemaMetaVersion(), nil
+}
+
 // IsReadOnly returns true if a statement is read only.
 func (a *ExecStmt) IsReadOnly() bool {
+	if execStmt, ok := a.StmtNode.(*ast.ExecuteStmt); ok {
+		s, err := getPreparedStmt(execStmt, a.Ctx.GetSessionVars())
+		if err != nil {
+			return false
+		}
+		return ast.IsReadOnly(s)
+	}
 	return ast.IsReadOnly(a.StmtNode)
 }
 
@@ -177,6 +208,13 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 	}()
 
 	sctx := a.Ctx
+	isPessimistic := a.isPessimisticStmt()
+	if isPessimistic {
+		// Reads and locks of the statement use a fresh for update ts.
+		if err = UpdateForUpdateTS(sctx, 0); err != nil {
+			return nil, err
+		}
+	}
 	e, err := a.buildExecutor()
 	if err != nil {
 		return nil, err
@@ -187,7 +225,7 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 		return nil, err
 	}
 
-	if handled, result, err := a.handleNoDelay(ctx, e); handled {
+	if handled, result, err := a.handleNoDelay(ctx, e, isPessimistic); handled {
 		return result, err
 	}
 
@@ -206,14 +244,23 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 	}, nil
 }
 
-func (a *ExecStmt) handleNoDelay(ctx context.Context, e Executor) (bool, sqlexec.RecordSet, error) {
+func (a *ExecStmt) handleNoDelay(ctx context.Context, e Executor, isPessimistic bool) (bool, sqlexec.RecordSet, error) {
 	toCheck := e
 
 	// If the executor doesn't return any result to the client, we execute it without delay.
 	if toCheck.Schema().Len() == 0 {
+		if isPessimistic {
+			return true, nil, a.handlePessimisticDML(ctx, e)
+		}
 		r, err := a.handleNoDelayExecutor(ctx, e)
 		return true, r, err
 	}
+	// SELECT FOR UPDATE in a pessimistic transaction has to lock all the rows
+	// before returning any of them, so it is executed without delay too.
+	if isPessimistic {
+		r, err := a.handlePessimisticSelectForUpdate(ctx, e)
+		return true, r, err
+	}
 
 	return false, nil, nil
 }
@@ -231,6 +278,198 @@ func (a *ExecStmt) handleNoDelayExecutor(ctx context.Context, e Executor) (sqlex
 	return nil, err
 }
 
+// isPessimisticStmt returns whether the statement locks the rows it writes or
+// reads in a pessimistic transaction.
+func (a *ExecStmt) isPessimisticStmt() bool {
+	if !a.Ctx.GetSessionVars().TxnCtx.IsPessimistic {
+		return false
+	}
+	node := a.StmtNode
+	if execStmt, ok := node.(*ast.ExecuteStmt); ok {
+		s, err := getPreparedStmt(execStmt, a.Ctx.GetSessionVars())
+		if err != nil {
+			return false
+		}
+		node = s
+	}
+	switch x := node.(type) {
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+		return true
+	case *ast.SelectStmt:
+		return x.LockTp != ast.SelectLockNone
+	}
+	return false
+}
+
+type pessimisticTxn interface {
+	kv.Transaction
+	// KeysNeedToLock returns the keys need to be locked.
+	KeysNeedToLock() ([]kv.Key, error)
+}
+
+// handlePessimisticDML executes a DML statement in a pessimistic transaction,
+// the keys written by the statement are locked after it is executed.
+func (a *ExecStmt) handlePessimisticDML(ctx context.Context, e Executor) error {
+	sctx := a.Ctx
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	for {
+		_, err = a.handleNoDelayExecutor(ctx, e)
+		if err != nil {
+			return err
+		}
+		keys, err := txn.(pessimisticTxn).KeysNeedToLock()
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		seVars := sctx.GetSessionVars()
+		err = txn.LockKeys(ctx, newLockCtx(seVars, seVars.LockWaitTimeout), keys...)
+		if err == nil {
+			return nil
+		}
+		e, err = a.handlePessimisticLockError(ctx, err)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handlePessimisticSelectForUpdate executes SELECT FOR UPDATE in a pessimistic
+// transaction, the rows are read and locked before they are returned.
+func (a *ExecStmt) handlePessimisticSelectForUpdate(ctx context.Context, e Executor) (sqlexec.RecordSet, error) {
+	for {
+		rs, err := a.runPessimisticSelectForUpdate(ctx, e)
+		if err == nil {
+			return rs, nil
+		}
+		e, err = a.handlePessimisticLockError(ctx, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (a *ExecStmt) runPessimisticSelectForUpdate(ctx context.Context, e Executor) (sqlexec.RecordSet, error) {
+	defer func() {
+		terror.Log(e.Close())
+	}()
+	var rows []chunk.Row
+	req := newFirstChunk(e)
+	for {
+		err := Next(ctx, e, req)
+		if err != nil {
+			return nil, err
+		}
+		if req.NumRows() == 0 {
+			fields := colNames2ResultFields(e.Schema(), a.OutputNames, a.Ctx.GetSessionVars().CurrentDB)
+			return &chunkRowRecordSet{rows: rows, fields: fields, e: e, stmt: a}, nil
+		}
+		iter := chunk.NewIterator4Chunk(req)
+		for r := iter.Begin(); r != iter.End(); r = iter.Next() {
+			rows = append(rows, r)
+		}
+		req = chunk.Renew(req, a.Ctx.GetSessionVars().MaxChunkSize)
+	}
+}
+
+// maxPessimisticRetryCount is the max times a statement is retried when its
+// pessimistic locks meet write conflicts.
+const maxPessimisticRetryCount = 256
+
+// handlePessimisticLockError handles the error of locking keys in a pessimistic
+// transaction. On a write conflict the statement is rolled back, and a new
+// executor is built to retry it with a newer for update ts.
+func (a *ExecStmt) handlePessimisticLockError(ctx context.Context, err error) (Executor, error) {
+	if !terror.ErrorEqual(kv.ErrWriteConflict, err) {
+		return nil, err
+	}
+	if a.retryCount >= maxPessimisticRetryCount {
+		return nil, errors.New("pessimistic lock retry limit reached")
+	}
+	a.retryCount++
+	logutil.Logger(ctx).Debug("pessimistic write conflict, retry statement",
+		zap.Uint64("txn", a.Ctx.GetSessionVars().TxnCtx.StartTS),
+		zap.Int("retryCount", a.retryCount),
+		zap.Error(err))
+	if err = UpdateForUpdateTS(a.Ctx, 0); err != nil {
+		return nil, err
+	}
+	// Rollback the statement change before retry it.
+	a.Ctx.StmtRollback()
+	a.Ctx.GetSessionVars().StmtCtx.ResetForRetry()
+	e, err := a.buildExecutor()
+	if err != nil {
+		return nil, err
+	}
+	if err = e.Open(ctx); err != nil {
+		terror.Call(e.Close)
+		return nil, err
+	}
+	return e, nil
+}
+
+// UpdateForUpdateTS updates the for update ts of the pessimistic transaction,
+// a new ts is obtained from the store if newForUpdateTS is 0.
+func UpdateForUpdateTS(seCtx sessionctx.Context, newForUpdateTS uint64) error {
+	txn, err := seCtx.Txn(true)
+	if err != nil {
+		return err
+	}
+	if newForUpdateTS == 0 {
+		version, err := seCtx.GetStore().CurrentVersion()
+		if err != nil {
+			return err
+		}
+		newForUpdateTS = version.Ver
+	}
+	txnCtx := seCtx.GetSessionVars().TxnCtx
+	txnCtx.SetForUpdateTS(newForUpdateTS)
+	txn.SetOption(kv.SnapshotTS, txnCtx.GetForUpdateTS())
+	return nil
+}
+
+// chunkRowRecordSet is a RecordSet on the rows read in advance.
+type chunkRowRecordSet struct {
+	rows   []chunk.Row
+	idx    int
+	fields []*ast.ResultField
+	e      Executor
+	stmt   *ExecStmt
+}
+
+func (c *chunkRowRecordSet) Fields() []*ast.ResultField {
+	return c.fields
+}
+
+func (c *chunkRowRecordSet) Next(ctx context.Context, chk *chunk.Chunk) error {
+	chk.Reset()
+	for !chk.IsFull() && c.idx < len(c.rows) {
+		chk.AppendRow(c.rows[c.idx])
+		c.idx++
+	}
+	sessVars := c.stmt.Ctx.GetSessionVars()
+	if chk.NumRows() == 0 {
+		sessVars.LastFoundRows = sessVars.StmtCtx.FoundRows()
+		return nil
+	}
+	sessVars.StmtCtx.AddFoundRows(uint64(chk.NumRows()))
+	return nil
+}
+
+func (c *chunkRowRecordSet) NewChunk() *chunk.Chunk {
+	return newFirstChunk(c.e)
+}
+
+func (c *chunkRowRecordSet) Close() error {
+	c.stmt.Ctx.GetSessionVars().PrevStmt = FormatSQL(c.stmt.OriginText())
+	return nil
+}
+
 // buildExecutor build a executor from plan, prepared statement may need additional procedure.
 func (a *ExecStmt) buildExecutor() (Executor, error) {
 	ctx := a.Ctx
@@ -241,6 +480,15 @@ func (a *ExecStmt) buildExecutor() (Executor, error) {
 		return nil, errors.Trace(b.err)
 	}
 
+	// ExecuteExec is not a real Executor, we only use it to build another Executor from a prepared statement.
+	if executorExec, ok := e.(*ExecuteExec); ok {
+		err := executorExec.Build(b)
+		if err != nil {
+			return nil, err
+		}
+		a.Plan = executorExec.plan
+		e = executorExec.stmtExec
+	}
 	return e, nil
 }
 
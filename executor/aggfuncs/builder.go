@@ -14,6 +14,7 @@
 package aggfuncs
 
 import (
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -28,9 +29,9 @@ func Build(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal
 	case ast.AggFuncCount:
 		return buildCount(aggFuncDesc, ordinal)
 	case ast.AggFuncSum:
-		return buildSum(aggFuncDesc, ordinal)
+		return buildSum(ctx, aggFuncDesc, ordinal)
 	case ast.AggFuncAvg:
-		return buildAvg(aggFuncDesc, ordinal)
+		return buildAvg(ctx, aggFuncDesc, ordinal)
 	case ast.AggFuncFirstRow:
 		return buildFirstRow(aggFuncDesc, ordinal)
 	case ast.AggFuncMax:
@@ -41,6 +42,30 @@ func Build(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal
 	return nil
 }
 
+// BuildWindowFunctions builds specific window function according to function description and order by columns.
+func BuildWindowFunctions(ctx sessionctx.Context, windowFuncDesc *aggregation.AggFuncDesc, ordinal int, orderByCols []*expression.Column) AggFunc {
+	switch windowFuncDesc.Name {
+	case ast.WindowFuncRank:
+		return buildRank(ordinal, orderByCols, false)
+	case ast.WindowFuncDenseRank:
+		return buildRank(ordinal, orderByCols, true)
+	case ast.WindowFuncRowNumber:
+		return buildRowNumber(windowFuncDesc, ordinal)
+	case ast.WindowFuncFirstValue:
+		return buildFirstValue(windowFuncDesc, ordinal)
+	case ast.WindowFuncLastValue:
+		return buildLastValue(windowFuncDesc, ordinal)
+	case ast.WindowFuncNtile:
+		return buildNtile(windowFuncDesc, ordinal)
+	case ast.WindowFuncLead:
+		return &lead{buildLeadLag(ctx, windowFuncDesc, ordinal)}
+	case ast.WindowFuncLag:
+		return &lag{buildLeadLag(ctx, windowFuncDesc, ordinal)}
+	default:
+		return Build(ctx, windowFuncDesc, ordinal)
+	}
+}
+
 // buildCount builds the AggFunc implementation for function "COUNT".
 func buildCount(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	base := baseAggFunc{
@@ -55,6 +80,14 @@ func buildCount(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 			return &countOriginal4Int{baseCount{base}}
 		case types.ETReal:
 			return &countOriginal4Real{baseCount{base}}
+		case types.ETDecimal:
+			return &countOriginal4Decimal{baseCount{base}}
+		case types.ETDatetime, types.ETTimestamp:
+			return &countOriginal4Time{baseCount{base}}
+		case types.ETDuration:
+			return &countOriginal4Duration{baseCount{base}}
+		case types.ETJson:
+			return &countOriginal4JSON{baseCount{base}}
 		case types.ETString:
 			return &countOriginal4String{baseCount{base}}
 		}
@@ -66,7 +99,7 @@ func buildCount(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 }
 
 // buildSum builds the AggFunc implementation for function "SUM".
-func buildSum(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+func buildSum(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	base := baseSumAggFunc{
 		baseAggFunc: baseAggFunc{
 			args:    aggFuncDesc.Args,
@@ -74,15 +107,16 @@ func buildSum(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 		},
 	}
 	switch aggFuncDesc.RetTp.EvalType() {
-	case types.ETInt:
-		return &sum4Int64{base}
+	case types.ETDecimal:
+		base.args = wrapCastAsDecimal(ctx, base.args)
+		return &sum4Decimal{base}
 	default:
 		return &sum4Float64{base}
 	}
 }
 
 // buildAvg builds the AggFunc implementation for function "AVG".
-func buildAvg(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+func buildAvg(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	base := baseAggFunc{
 		args:    aggFuncDesc.Args,
 		ordinal: ordinal,
@@ -92,8 +126,9 @@ func buildAvg(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	// partial results.
 	case aggregation.CompleteMode, aggregation.Partial1Mode:
 		switch aggFuncDesc.RetTp.EvalType() {
-		case types.ETInt:
-			return &avgOriginal4Int64{baseAvgInt64{base}}
+		case types.ETDecimal:
+			base.args = wrapCastAsDecimal(ctx, base.args)
+			return &avgOriginal4Decimal{baseAvgDecimal{base, aggFuncDesc.RetTp.Decimal}}
 		default:
 			return &avgOriginal4Float64{baseAvgFloat64{base}}
 		}
@@ -102,8 +137,8 @@ func buildAvg(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	// functions and update their partial results.
 	case aggregation.Partial2Mode, aggregation.FinalMode:
 		switch aggFuncDesc.RetTp.Tp {
-		case mysql.TypeLonglong:
-			return &avgPartial4Int64{baseAvgInt64{base}}
+		case mysql.TypeNewDecimal:
+			return &avgPartial4Decimal{baseAvgDecimal{base, aggFuncDesc.RetTp.Decimal}}
 		case mysql.TypeDouble:
 			return &avgPartial4Float64{baseAvgFloat64{base}}
 		}
@@ -111,6 +146,16 @@ func buildAvg(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	return nil
 }
 
+// wrapCastAsDecimal makes the original arguments of "SUM" and "AVG" readable
+// by EvalDecimal, since an integer column can not be read as a decimal directly.
+func wrapCastAsDecimal(ctx sessionctx.Context, args []expression.Expression) []expression.Expression {
+	newArgs := make([]expression.Expression, 0, len(args))
+	for _, arg := range args {
+		newArgs = append(newArgs, expression.WrapWithCastAsDecimal(ctx, arg))
+	}
+	return newArgs
+}
+
 // buildFirstRow builds the AggFunc implementation for function "FIRST_ROW".
 func buildFirstRow(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	base := baseAggFunc{
@@ -132,6 +177,14 @@ func buildFirstRow(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 		case mysql.TypeDouble:
 			return &firstRow4Float64{base}
 		}
+	case types.ETDecimal:
+		return &firstRow4Decimal{base}
+	case types.ETDatetime, types.ETTimestamp:
+		return &firstRow4Time{base}
+	case types.ETDuration:
+		return &firstRow4Duration{base}
+	case types.ETJson:
+		return &firstRow4JSON{base}
 	case types.ETString:
 		return &firstRow4String{base}
 	}
@@ -165,8 +218,78 @@ func buildMaxMin(aggFuncDesc *aggregation.AggFuncDesc, ordinal int, isMax bool)
 		case mysql.TypeDouble:
 			return &maxMin4Float64{base}
 		}
+	case types.ETDecimal:
+		return &maxMin4Decimal{base}
+	case types.ETDatetime, types.ETTimestamp:
+		return &maxMin4Time{base}
+	case types.ETDuration:
+		return &maxMin4Duration{base}
+	case types.ETJson:
+		return &maxMin4JSON{base}
 	case types.ETString:
 		return &maxMin4String{base}
 	}
 	return nil
 }
+
+func buildRank(ordinal int, orderByCols []*expression.Column, isDense bool) AggFunc {
+	base := baseAggFunc{
+		ordinal: ordinal,
+	}
+	return &rank{baseAggFunc: base, isDense: isDense, rowComparer: buildRowComparer(orderByCols)}
+}
+
+func buildRowNumber(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &rowNumber{base}
+}
+
+func buildFirstValue(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &firstValue{baseAggFunc: base, tp: aggFuncDesc.RetTp}
+}
+
+func buildLastValue(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &lastValue{baseAggFunc: base, tp: aggFuncDesc.RetTp}
+}
+
+func buildNtile(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	// The argument of NTILE is a constant checked by the planner, NULL is 0 here.
+	n, _, _ := expression.GetUint64FromConstant(aggFuncDesc.Args[0])
+	return &ntile{baseAggFunc: base, n: n}
+}
+
+func buildLeadLag(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) baseLeadLag {
+	offset := uint64(1)
+	if len(aggFuncDesc.Args) >= 2 {
+		offset, _, _ = expression.GetUint64FromConstant(aggFuncDesc.Args[1])
+	}
+	var defaultExpr expression.Expression = expression.Null
+	if len(aggFuncDesc.Args) == 3 {
+		defaultExpr = aggFuncDesc.Args[2]
+	}
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return baseLeadLag{
+		baseAggFunc:    base,
+		valueEvaluator: valueEvaluator{tp: aggFuncDesc.RetTp},
+		defaultExpr:    defaultExpr,
+		offset:         offset,
+	}
+}
@@ -15,6 +15,7 @@ package aggfuncs
 
 import (
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/stringutil"
 )
@@ -45,6 +46,30 @@ type partialResult4FirstRowFloat64 struct {
 	val float64
 }
 
+type partialResult4FirstRowDecimal struct {
+	basePartialResult4FirstRow
+
+	val types.MyDecimal
+}
+
+type partialResult4FirstRowTime struct {
+	basePartialResult4FirstRow
+
+	val types.Time
+}
+
+type partialResult4FirstRowDuration struct {
+	basePartialResult4FirstRow
+
+	val types.Duration
+}
+
+type partialResult4FirstRowJSON struct {
+	basePartialResult4FirstRow
+
+	val types.BinaryJSON
+}
+
 type partialResult4FirstRowString struct {
 	basePartialResult4FirstRow
 
@@ -190,6 +215,197 @@ func (e *firstRow4Float64) AppendFinalResult2Chunk(sctx sessionctx.Context, pr P
 	return nil
 }
 
+type firstRow4Decimal struct {
+	baseAggFunc
+}
+
+func (e *firstRow4Decimal) AllocPartialResult() PartialResult {
+	return PartialResult(new(partialResult4FirstRowDecimal))
+}
+
+func (e *firstRow4Decimal) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4FirstRowDecimal)(pr)
+	p.isNull, p.gotFirstRow = false, false
+}
+
+func (e *firstRow4Decimal) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4FirstRowDecimal)(pr)
+	if p.gotFirstRow {
+		return nil
+	}
+	for _, row := range rowsInGroup {
+		input, isNull, err := e.args[0].EvalDecimal(sctx, row)
+		if err != nil {
+			return err
+		}
+		p.gotFirstRow, p.isNull = true, isNull
+		if input != nil {
+			p.val = *input
+		}
+		break
+	}
+	return nil
+}
+
+func (*firstRow4Decimal) MergePartialResult(sctx sessionctx.Context, src PartialResult, dst PartialResult) error {
+	p1, p2 := (*partialResult4FirstRowDecimal)(src), (*partialResult4FirstRowDecimal)(dst)
+	if !p2.gotFirstRow {
+		*p2 = *p1
+	}
+	return nil
+}
+
+func (e *firstRow4Decimal) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4FirstRowDecimal)(pr)
+	if p.isNull || !p.gotFirstRow {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendMyDecimal(e.ordinal, &p.val)
+	return nil
+}
+
+type firstRow4Time struct {
+	baseAggFunc
+}
+
+func (e *firstRow4Time) AllocPartialResult() PartialResult {
+	return PartialResult(new(partialResult4FirstRowTime))
+}
+
+func (e *firstRow4Time) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4FirstRowTime)(pr)
+	p.isNull, p.gotFirstRow = false, false
+}
+
+func (e *firstRow4Time) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4FirstRowTime)(pr)
+	if p.gotFirstRow {
+		return nil
+	}
+	for _, row := range rowsInGroup {
+		input, isNull, err := e.args[0].EvalTime(sctx, row)
+		if err != nil {
+			return err
+		}
+		p.gotFirstRow, p.isNull, p.val = true, isNull, input
+		break
+	}
+	return nil
+}
+
+func (*firstRow4Time) MergePartialResult(sctx sessionctx.Context, src PartialResult, dst PartialResult) error {
+	p1, p2 := (*partialResult4FirstRowTime)(src), (*partialResult4FirstRowTime)(dst)
+	if !p2.gotFirstRow {
+		*p2 = *p1
+	}
+	return nil
+}
+
+func (e *firstRow4Time) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4FirstRowTime)(pr)
+	if p.isNull || !p.gotFirstRow {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendTime(e.ordinal, p.val)
+	return nil
+}
+
+type firstRow4Duration struct {
+	baseAggFunc
+}
+
+func (e *firstRow4Duration) AllocPartialResult() PartialResult {
+	return PartialResult(new(partialResult4FirstRowDuration))
+}
+
+func (e *firstRow4Duration) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4FirstRowDuration)(pr)
+	p.isNull, p.gotFirstRow = false, false
+}
+
+func (e *firstRow4Duration) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4FirstRowDuration)(pr)
+	if p.gotFirstRow {
+		return nil
+	}
+	for _, row := range rowsInGroup {
+		input, isNull, err := e.args[0].EvalDuration(sctx, row)
+		if err != nil {
+			return err
+		}
+		p.gotFirstRow, p.isNull, p.val = true, isNull, input
+		break
+	}
+	return nil
+}
+
+func (*firstRow4Duration) MergePartialResult(sctx sessionctx.Context, src PartialResult, dst PartialResult) error {
+	p1, p2 := (*partialResult4FirstRowDuration)(src), (*partialResult4FirstRowDuration)(dst)
+	if !p2.gotFirstRow {
+		*p2 = *p1
+	}
+	return nil
+}
+
+func (e *firstRow4Duration) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4FirstRowDuration)(pr)
+	if p.isNull || !p.gotFirstRow {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendDuration(e.ordinal, p.val)
+	return nil
+}
+
+type firstRow4JSON struct {
+	baseAggFunc
+}
+
+func (e *firstRow4JSON) AllocPartialResult() PartialResult {
+	return PartialResult(new(partialResult4FirstRowJSON))
+}
+
+func (e *firstRow4JSON) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4FirstRowJSON)(pr)
+	p.isNull, p.gotFirstRow = false, false
+}
+
+func (e *firstRow4JSON) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4FirstRowJSON)(pr)
+	if p.gotFirstRow {
+		return nil
+	}
+	for _, row := range rowsInGroup {
+		input, isNull, err := e.args[0].EvalJSON(sctx, row)
+		if err != nil {
+			return err
+		}
+		p.gotFirstRow, p.isNull, p.val = true, isNull, input.Copy()
+		break
+	}
+	return nil
+}
+
+func (*firstRow4JSON) MergePartialResult(sctx sessionctx.Context, src PartialResult, dst PartialResult) error {
+	p1, p2 := (*partialResult4FirstRowJSON)(src), (*partialResult4FirstRowJSON)(dst)
+	if !p2.gotFirstRow {
+		*p2 = *p1
+	}
+	return nil
+}
+
+func (e *firstRow4JSON) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4FirstRowJSON)(pr)
+	if p.isNull || !p.gotFirstRow {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendJSON(e.ordinal, p.val)
+	return nil
+}
+
 type firstRow4String struct {
 	baseAggFunc
 }
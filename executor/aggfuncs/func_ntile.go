@@ -0,0 +1,75 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// ntile divides the rows of the partition into n buckets, the first
+// `numRows % n` buckets have one more row than the others.
+type ntile struct {
+	baseAggFunc
+	n uint64
+}
+
+type partialResult4Ntile struct {
+	curIdx      uint64
+	curGroupIdx uint64
+	remainder   uint64
+	quotient    uint64
+	numRows     uint64
+}
+
+func (n *ntile) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4Ntile{curGroupIdx: 1})
+}
+
+func (n *ntile) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4Ntile)(pr)
+	p.curIdx = 0
+	p.curGroupIdx = 1
+	p.numRows = 0
+}
+
+func (n *ntile) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4Ntile)(pr)
+	p.numRows += uint64(len(rowsInGroup))
+	if n.n != 0 {
+		p.quotient = p.numRows / n.n
+		p.remainder = p.numRows % n.n
+	}
+	return nil
+}
+
+func (n *ntile) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4Ntile)(pr)
+	// The argument of NTILE is NULL.
+	if n.n == 0 {
+		chk.AppendNull(n.ordinal)
+		return nil
+	}
+	chk.AppendUint64(n.ordinal, p.curGroupIdx)
+	p.curIdx++
+	curMaxIdx := p.quotient
+	if p.curGroupIdx <= p.remainder {
+		curMaxIdx++
+	}
+	if p.curIdx == curMaxIdx {
+		p.curIdx = 0
+		p.curGroupIdx++
+	}
+	return nil
+}
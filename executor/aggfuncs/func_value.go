@@ -0,0 +1,117 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// valueEvaluator evaluates an expression on a row and keeps the value in the
+// type of the function result.
+type valueEvaluator struct {
+	tp  *types.FieldType
+	val types.Datum
+}
+
+func (v *valueEvaluator) evaluateRow(sctx sessionctx.Context, expr expression.Expression, row chunk.Row) error {
+	d, err := expr.Eval(row)
+	if err != nil {
+		return err
+	}
+	v.val, err = d.ConvertTo(sctx.GetSessionVars().StmtCtx, v.tp)
+	return err
+}
+
+func (v *valueEvaluator) appendResult(chk *chunk.Chunk, colIdx int) {
+	chk.AppendDatum(colIdx, &v.val)
+}
+
+type firstValue struct {
+	baseAggFunc
+	tp *types.FieldType
+}
+
+type partialResult4FirstValue struct {
+	gotFirstValue bool
+	evaluator     valueEvaluator
+}
+
+func (v *firstValue) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4FirstValue{evaluator: valueEvaluator{tp: v.tp}})
+}
+
+func (v *firstValue) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4FirstValue)(pr)
+	p.gotFirstValue = false
+}
+
+func (v *firstValue) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4FirstValue)(pr)
+	if p.gotFirstValue || len(rowsInGroup) == 0 {
+		return nil
+	}
+	p.gotFirstValue = true
+	return p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[0])
+}
+
+func (v *firstValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4FirstValue)(pr)
+	if !p.gotFirstValue {
+		chk.AppendNull(v.ordinal)
+		return nil
+	}
+	p.evaluator.appendResult(chk, v.ordinal)
+	return nil
+}
+
+type lastValue struct {
+	baseAggFunc
+	tp *types.FieldType
+}
+
+type partialResult4LastValue struct {
+	gotLastValue bool
+	evaluator    valueEvaluator
+}
+
+func (v *lastValue) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4LastValue{evaluator: valueEvaluator{tp: v.tp}})
+}
+
+func (v *lastValue) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4LastValue)(pr)
+	p.gotLastValue = false
+}
+
+func (v *lastValue) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4LastValue)(pr)
+	if len(rowsInGroup) == 0 {
+		return nil
+	}
+	p.gotLastValue = true
+	return p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[len(rowsInGroup)-1])
+}
+
+func (v *lastValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4LastValue)(pr)
+	if !p.gotLastValue {
+		chk.AppendNull(v.ordinal)
+		return nil
+	}
+	p.evaluator.appendResult(chk, v.ordinal)
+	return nil
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/set"
+	"github.com/spaolacci/murmur3"
 	"go.uber.org/zap"
 )
 
@@ -319,6 +320,10 @@ func (w *HashAggPartialWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitG
 		if !w.getChildInput() {
 			return
 		}
+		if err := checkKilled(ctx); err != nil {
+			w.globalOutputCh <- &AfFinalResult{err: err}
+			return
+		}
 		if err := w.updatePartialResult(ctx, sc, w.chk, len(w.partialResultsMap)); err != nil {
 			w.globalOutputCh <- &AfFinalResult{err: err}
 			return
@@ -352,7 +357,24 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 // shuffleIntermData shuffles the intermediate data of partial workers to corresponded final workers.
 // We only support parallel execution for single-machine, so process of encode and decode can be skipped.
 func (w *HashAggPartialWorker) shuffleIntermData(sc *stmtctx.StatementContext, finalConcurrency int) {
-	// TODO: implement the method body. Shuffle the data to final workers.
+	groupKeysSlice := make([][]string, finalConcurrency)
+	for groupKey := range w.partialResultsMap {
+		finalWorkerIdx := int(murmur3.Sum32([]byte(groupKey))) % finalConcurrency
+		if groupKeysSlice[finalWorkerIdx] == nil {
+			groupKeysSlice[finalWorkerIdx] = make([]string, 0, len(w.partialResultsMap)/finalConcurrency)
+		}
+		groupKeysSlice[finalWorkerIdx] = append(groupKeysSlice[finalWorkerIdx], groupKey)
+	}
+
+	for i := range groupKeysSlice {
+		if groupKeysSlice[i] == nil {
+			continue
+		}
+		w.outputChs[i] <- &HashAggIntermData{
+			groupKeys:        groupKeysSlice[i],
+			partialResultMap: w.partialResultsMap,
+		}
+	}
 }
 
 // getGroupKey evaluates the group items and args of aggregate functions.
@@ -422,8 +444,44 @@ func (w *HashAggFinalWorker) getPartialInput() (input *HashAggIntermData, ok boo
 }
 
 func (w *HashAggFinalWorker) consumeIntermData(sctx sessionctx.Context) (err error) {
-	// TODO: implement the method body. This method consumes the data given by the partial workers.
-	return nil
+	var (
+		input            *HashAggIntermData
+		ok               bool
+		intermDataBuffer [][]aggfuncs.PartialResult
+		groupKeys        []string
+		sc               = sctx.GetSessionVars().StmtCtx
+	)
+	for {
+		if input, ok = w.getPartialInput(); !ok {
+			return nil
+		}
+		if intermDataBuffer == nil {
+			intermDataBuffer = make([][]aggfuncs.PartialResult, 0, w.maxChunkSize)
+		}
+		// Consume input in batches, size of every batch is less than w.maxChunkSize.
+		for reachEnd := false; !reachEnd; {
+			if err = checkKilled(sctx); err != nil {
+				return err
+			}
+			intermDataBuffer, groupKeys, reachEnd = input.getPartialResultBatch(sc, intermDataBuffer[:0], w.aggFuncs, w.maxChunkSize)
+			w.groupKeys = w.groupKeys[:0]
+			for _, groupKey := range groupKeys {
+				w.groupKeys = append(w.groupKeys, []byte(groupKey))
+			}
+			finalPartialResults := w.getPartialResult(sc, w.groupKeys, w.partialResultMap)
+			for i, groupKey := range groupKeys {
+				if !w.groupSet.Exist(groupKey) {
+					w.groupSet.Insert(groupKey)
+				}
+				prs := intermDataBuffer[i]
+				for j, af := range w.aggFuncs {
+					if err = af.MergePartialResult(sctx, prs[j], finalPartialResults[i][j]); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
 }
 
 func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
@@ -586,3 +644,209 @@ func (e *HashAggExec) parallelExec(ctx context.Context, chk *chunk.Chunk) error
 		}
 	}
 }
+
+// StreamAggExec deals with all the aggregate functions.
+// It assumes all the input data is sorted by group by key.
+// When Next() is called, it will return a result for the same group.
+type StreamAggExec struct {
+	baseExecutor
+
+	executed bool
+	// isChildReturnEmpty indicates whether the child executor only returns an empty input.
+	isChildReturnEmpty bool
+	defaultVal         *chunk.Chunk
+	groupChecker       *groupChecker
+	inputIter          *chunk.Iterator4Chunk
+	inputRow           chunk.Row
+	aggFuncs           []aggfuncs.AggFunc
+	partialResults     []aggfuncs.PartialResult
+	groupRows          []chunk.Row
+	childResult        *chunk.Chunk
+}
+
+// Open implements the Executor Open interface.
+func (e *StreamAggExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.childResult = newFirstChunk(e.children[0])
+	e.executed = false
+	e.isChildReturnEmpty = true
+	e.inputIter = chunk.NewIterator4Chunk(e.childResult)
+	e.inputRow = e.inputIter.End()
+	e.groupChecker.reset()
+
+	e.partialResults = make([]aggfuncs.PartialResult, 0, len(e.aggFuncs))
+	for _, aggFunc := range e.aggFuncs {
+		e.partialResults = append(e.partialResults, aggFunc.AllocPartialResult())
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *StreamAggExec) Close() error {
+	e.childResult = nil
+	e.groupRows = nil
+	return e.baseExecutor.Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *StreamAggExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !e.executed && !req.IsFull() {
+		err := e.consumeOneGroup(ctx, req)
+		if err != nil {
+			e.executed = true
+			return err
+		}
+	}
+	return nil
+}
+
+// consumeOneGroup reads the input rows until a new group is met, then
+// appends the result of the finished group to chk.
+func (e *StreamAggExec) consumeOneGroup(ctx context.Context, chk *chunk.Chunk) error {
+	for !e.executed {
+		if err := e.fetchChildIfNecessary(ctx, chk); err != nil {
+			return err
+		}
+		for ; e.inputRow != e.inputIter.End(); e.inputRow = e.inputIter.Next() {
+			meetNewGroup, err := e.groupChecker.meetNewGroup(e.inputRow)
+			if err != nil {
+				return err
+			}
+			if meetNewGroup {
+				err := e.consumeGroupRows()
+				if err != nil {
+					return err
+				}
+				err = e.appendResult2Chunk(chk)
+				if err != nil {
+					return err
+				}
+			}
+			e.groupRows = append(e.groupRows, e.inputRow)
+			if meetNewGroup {
+				e.inputRow = e.inputIter.Next()
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (e *StreamAggExec) consumeGroupRows() error {
+	if len(e.groupRows) == 0 {
+		return nil
+	}
+
+	for i, aggFunc := range e.aggFuncs {
+		err := aggFunc.UpdatePartialResult(e.ctx, e.groupRows, e.partialResults[i])
+		if err != nil {
+			return err
+		}
+	}
+	e.groupRows = e.groupRows[:0]
+	return nil
+}
+
+func (e *StreamAggExec) fetchChildIfNecessary(ctx context.Context, chk *chunk.Chunk) (err error) {
+	if e.inputRow != e.inputIter.End() {
+		return nil
+	}
+
+	// Before fetching a new batch of input, we should consume the last group.
+	err = e.consumeGroupRows()
+	if err != nil {
+		return err
+	}
+
+	err = Next(ctx, e.children[0], e.childResult)
+	if err != nil {
+		return err
+	}
+
+	// No more data.
+	if e.childResult.NumRows() == 0 {
+		if !e.isChildReturnEmpty {
+			err = e.appendResult2Chunk(chk)
+		} else if e.defaultVal != nil {
+			chk.Append(e.defaultVal, 0, 1)
+		}
+		e.executed = true
+		return err
+	}
+	// Reach here, "e.childResult.NumRows() > 0" is guaranteed.
+	e.isChildReturnEmpty = false
+	e.inputRow = e.inputIter.Begin()
+	return nil
+}
+
+// appendResult2Chunk appends result of all the aggregation functions to the
+// result chunk, and reset the evaluation context for each aggregation.
+func (e *StreamAggExec) appendResult2Chunk(chk *chunk.Chunk) error {
+	for i, aggFunc := range e.aggFuncs {
+		err := aggFunc.AppendFinalResult2Chunk(e.ctx, e.partialResults[i], chk)
+		if err != nil {
+			return err
+		}
+		aggFunc.ResetPartialResult(e.partialResults[i])
+	}
+	return nil
+}
+
+// groupChecker checks whether the rows read in order belong to a new group.
+type groupChecker struct {
+	sc           *stmtctx.StatementContext
+	groupByItems []expression.Expression
+	curGroupKey  []types.Datum
+	tmpGroupKey  []types.Datum
+}
+
+func newGroupChecker(sc *stmtctx.StatementContext, items []expression.Expression) *groupChecker {
+	return &groupChecker{
+		sc:           sc,
+		groupByItems: items,
+	}
+}
+
+// meetNewGroup returns a value that represents if the new group is different from last group.
+func (e *groupChecker) meetNewGroup(row chunk.Row) (bool, error) {
+	if len(e.groupByItems) == 0 {
+		return false, nil
+	}
+	e.tmpGroupKey = e.tmpGroupKey[:0]
+	matched, firstGroup := true, false
+	if len(e.curGroupKey) == 0 {
+		matched, firstGroup = false, true
+	}
+	for i, item := range e.groupByItems {
+		v, err := item.Eval(row)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			c, err := v.CompareDatum(e.sc, &e.curGroupKey[i])
+			if err != nil {
+				return false, err
+			}
+			matched = c == 0
+		}
+		e.tmpGroupKey = append(e.tmpGroupKey, v)
+	}
+	if matched {
+		return false, nil
+	}
+	// The datums may refer to the memory of the input chunk which is reused,
+	// so they are copied.
+	e.curGroupKey = e.curGroupKey[:0]
+	for _, v := range e.tmpGroupKey {
+		e.curGroupKey = append(e.curGroupKey, *v.Copy())
+	}
+	return !firstGroup, nil
+}
+
+func (e *groupChecker) reset() {
+	e.curGroupKey = e.curGroupKey[:0]
+	e.tmpGroupKey = e.tmpGroupKey[:0]
+}
@@ -97,3 +97,45 @@ func (s *testSuiteAgg) TestInjectProjBelowTopN(c *C) {
 		tk.MustQuery(tt).Check(testkit.Rows(output[i]...))
 	}
 }
+
+func (s *testSuiteAgg) TestDecimalSumAvg(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b decimal(10, 2))")
+	tk.MustQuery("select sum(b), avg(b) from t").Check(testkit.Rows("<nil> <nil>"))
+	tk.MustExec("insert into t values(1, 0.10), (1, 0.20), (2, 1.05), (2, NULL), (3, -2.50)")
+	tk.MustQuery("select sum(b), avg(b), max(b), min(b) from t").Check(testkit.Rows("-1.15 -0.287500 1.05 -2.50"))
+	tk.MustQuery("select a, sum(b), avg(b) from t group by a order by a").Check(testkit.Rows(
+		"1 0.30 0.150000", "2 1.05 1.050000", "3 -2.50 -2.500000"))
+	tk.MustQuery("select sum(a), avg(a) from t").Check(testkit.Rows("9 1.8000"))
+	tk.MustQuery("select b from t where b > 0.1 order by b").Check(testkit.Rows("0.20", "1.05"))
+	tk.MustQuery("select b + 1, b * 2, b / 3 from t where a = 3").Check(testkit.Rows("-1.50 -5.00 -0.833333"))
+}
+
+func (s *testSuiteAgg) TestStreamAgg(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, index idx(a, b))")
+	tk.MustExec("insert into t values (1, 1, 1), (1, 2, 2), (2, 3, 3), (null, 4, 4), (3, null, 5), (3, 6, 6), (3, 7, 7), (null, 8, 8)")
+	// HashAgg is cheaper than StreamAgg only because of its concurrency.
+	tk.MustExec("set @@tidb_hashagg_partial_concurrency = 1")
+	tk.MustExec("set @@tidb_hashagg_final_concurrency = 1")
+	// The rows of a group may span several chunks of the child.
+	tk.Se.GetSessionVars().InitChunkSize = 2
+	tk.Se.GetSessionVars().MaxChunkSize = 2
+	rows := tk.MustQuery("explain select a, count(*), sum(b) from t group by a").Rows()
+	c.Assert(rows[1][0], Matches, ".*StreamAgg.*")
+	tk.MustQuery("select a, count(*), sum(b) from t group by a").Check(testkit.Rows(
+		"<nil> 2 12", "1 2 3", "2 1 3", "3 3 13"))
+	tk.MustQuery("select a, count(*), sum(b) from t use index() group by a order by a").Check(testkit.Rows(
+		"<nil> 2 12", "1 2 3", "2 1 3", "3 3 13"))
+	tk.MustQuery("select a, max(b) from t where a > 1 group by a").Check(testkit.Rows("2 3", "3 7"))
+	tk.MustQuery("select a, b, count(*) from t group by a, b").Check(testkit.Rows(
+		"<nil> 4 1", "<nil> 8 1", "1 1 1", "1 2 1", "2 3 1", "3 <nil> 1", "3 6 1", "3 7 1"))
+
+	// Without group-by items, an empty input still outputs the default values.
+	tk.MustQuery("select count(a), sum(b) from t").Check(testkit.Rows("6 31"))
+	tk.MustQuery("select count(a), sum(b) from t where a > 10").Check(testkit.Rows("0 <nil>"))
+	tk.MustQuery("select a, count(*) from t where a > 10 group by a").Check(testkit.Rows())
+}
@@ -15,7 +15,10 @@ package executor_test
 
 import (
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/testkit"
 )
@@ -39,3 +42,37 @@ func (s *testSuite1) TestAnalyzeRestrict(c *C) {
 	ctx.GetSessionVars().InRestrictedSQL = true
 	tk.MustExec("analyze table t")
 }
+
+func (s *testSuite1) TestAnalyzePartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec(`create table t (a int, b int, index idx(b)) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than maxvalue)`)
+	tk.MustExec("insert into t values (1, 1), (2, 2), (11, 11), (21, 21), (22, 22), (23, 23)")
+	tk.MustExec("analyze table t")
+
+	is := tk.Se.(sessionctx.Context).GetSessionVars().TxnCtx.InfoSchema.(infoschema.InfoSchema)
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tblInfo := tbl.Meta()
+	h := domain.GetDomain(tk.Se).StatsHandle()
+	c.Assert(h.Update(is), IsNil)
+	// The stats are collected for every partition.
+	counts := []int64{2, 1, 3}
+	for i, def := range tblInfo.Partition.Definitions {
+		statsTbl := h.GetPartitionStats(tblInfo, def.ID)
+		c.Assert(statsTbl.Pseudo, IsFalse)
+		c.Assert(statsTbl.Count, Equals, counts[i])
+		c.Assert(len(statsTbl.Columns), Equals, 2)
+		c.Assert(len(statsTbl.Indices), Equals, 1)
+	}
+	tk.MustQuery("select partition_name, partition_ordinal_position, partition_method, partition_expression, partition_description " +
+		"from information_schema.partitions where table_schema = 'test' and table_name = 't'").Check(testkit.Rows(
+		"p0 1 RANGE `a` 10",
+		"p1 2 RANGE `a` 20",
+		"p2 3 RANGE `a` MAXVALUE",
+	))
+}
@@ -86,6 +86,9 @@ func getKeysNeedCheck(ctx context.Context, sctx sessionctx.Context, t table.Tabl
 	}
 
 	var err error
+	if p, ok := t.(table.PartitionedTable); ok {
+		return getKeysNeedCheckPartition(sctx, p, rows, nUnique, handleCol, toBeCheckRows)
+	}
 	for _, row := range rows {
 		toBeCheckRows, err = getKeysNeedCheckOneRow(sctx, t, row, nUnique, handleCol, toBeCheckRows)
 		if err != nil {
@@ -95,6 +98,23 @@ func getKeysNeedCheck(ctx context.Context, sctx sessionctx.Context, t table.Tabl
 	return toBeCheckRows, nil
 }
 
+// getKeysNeedCheckPartition gets the keys of every row from the partition it belongs to,
+// because the record keys and the index keys are encoded with the partition ID.
+func getKeysNeedCheckPartition(ctx sessionctx.Context, pt table.PartitionedTable, rows [][]types.Datum, nUnique int, handleCol *table.Column,
+	result []toBeCheckedRow) ([]toBeCheckedRow, error) {
+	for _, row := range rows {
+		p, err := pt.GetPartitionByRow(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		result, err = getKeysNeedCheckOneRow(ctx, p, row, nUnique, handleCol, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
 func getKeysNeedCheckOneRow(ctx sessionctx.Context, t table.Table, row []types.Datum, nUnique int, handleCol *table.Column, result []toBeCheckedRow) ([]toBeCheckedRow, error) {
 	var err error
 	var handleKey *keyValueWithDupInfo
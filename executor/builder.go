@@ -14,6 +14,7 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"math"
 	"sort"
@@ -29,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	plannercore "github.com/pingcap/tidb/planner/core"
@@ -37,6 +39,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/admin"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
@@ -48,6 +51,8 @@ type executorBuilder struct {
 	startTS uint64 // cached when the first time getStartTS() is called
 	// err is set when there is error happened during Executor building process.
 	err error
+	// cteStorages stores the results of the CTEs by their storage identifiers.
+	cteStorages map[int]*cteStorage
 }
 
 func newExecutorBuilder(ctx sessionctx.Context, is infoschema.InfoSchema) *executorBuilder {
@@ -70,14 +75,20 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return nil
 	case *plannercore.DDL:
 		return b.buildDDL(v)
+	case *plannercore.Deallocate:
+		return b.buildDeallocate(v)
 	case *plannercore.Delete:
 		return b.buildDelete(v)
+	case *plannercore.Execute:
+		return b.buildExecute(v)
 	case *plannercore.Explain:
 		return b.buildExplain(v)
 	case *plannercore.Insert:
 		return b.buildInsert(v)
 	case *plannercore.PhysicalLimit:
 		return b.buildLimit(v)
+	case *plannercore.Prepare:
+		return b.buildPrepare(v)
 	case *plannercore.ShowDDL:
 		return b.buildShowDDL(v)
 	case *plannercore.PhysicalShowDDLJobs:
@@ -94,16 +105,36 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildTopN(v)
 	case *plannercore.PhysicalUnionScan:
 		return b.buildUnionScanExec(v)
+	case *plannercore.Update:
+		return b.buildUpdate(v)
 	case *plannercore.PhysicalHashJoin:
 		return b.buildHashJoin(v)
 	case *plannercore.PhysicalMergeJoin:
 		return b.buildMergeJoin(v)
+	case *plannercore.PhysicalIndexJoin:
+		return b.buildIndexLookUpJoin(v)
+	case *plannercore.PhysicalApply:
+		return b.buildApply(v)
+	case *plannercore.PhysicalMaxOneRow:
+		return b.buildMaxOneRow(v)
+	case *plannercore.PhysicalLock:
+		return b.buildSelectLock(v)
 	case *plannercore.PhysicalSelection:
 		return b.buildSelection(v)
 	case *plannercore.PhysicalHashAgg:
 		return b.buildHashAgg(v)
+	case *plannercore.PhysicalStreamAgg:
+		return b.buildStreamAgg(v)
+	case *plannercore.PhysicalWindow:
+		return b.buildWindow(v)
+	case *plannercore.PhysicalCTE:
+		return b.buildCTE(v)
+	case *plannercore.PhysicalCTETable:
+		return b.buildCTETableReader(v)
 	case *plannercore.PhysicalProjection:
 		return b.buildProjection(v)
+	case *plannercore.PhysicalUnionAll:
+		return b.buildUnionAll(v)
 	case *plannercore.PhysicalMemTable:
 		return b.buildMemTable(v)
 	case *plannercore.PhysicalTableDual:
@@ -195,12 +226,42 @@ func (b *executorBuilder) buildShow(v *plannercore.PhysicalShow) Executor {
 		IfNotExists:  v.IfNotExists,
 		Flag:         v.Flag,
 		Full:         v.Full,
+		User:         v.User,
 		GlobalScope:  v.GlobalScope,
 		is:           b.is,
 	}
 	return e
 }
 
+func (b *executorBuilder) buildDeallocate(v *plannercore.Deallocate) Executor {
+	base := newBaseExecutor(b.ctx, nil, v.ExplainID())
+	base.initCap = chunk.ZeroCapacity
+	e := &DeallocateExec{
+		baseExecutor: base,
+		Name:         v.Name,
+	}
+	return e
+}
+
+func (b *executorBuilder) buildPrepare(v *plannercore.Prepare) Executor {
+	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID())
+	base.initCap = chunk.ZeroCapacity
+	return &PrepareExec{
+		baseExecutor: base,
+		is:           b.is,
+		name:         v.Name,
+		sqlText:      v.SQLText,
+	}
+}
+
+func (b *executorBuilder) buildExecute(v *plannercore.Execute) Executor {
+	e := &ExecuteExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+		plan:         v.Plan,
+	}
+	return e
+}
+
 func (b *executorBuilder) buildSimple(v *plannercore.Simple) Executor {
 	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID())
 	base.initCap = chunk.ZeroCapacity
@@ -283,6 +344,9 @@ func (b *executorBuilder) buildExplain(v *plannercore.Explain) Executor {
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
 		explain:      v,
 	}
+	if v.Analyze {
+		explainExec.analyzeExec = b.build(v.TargetPlan)
+	}
 	return explainExec
 }
 
@@ -443,6 +507,7 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 		baseExecutor:      newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), leftExec, rightExec),
 		concurrency:       v.Concurrency,
 		joinType:          v.JoinType,
+		isNullEQ:          v.IsNullEQ,
 		innerSideEstCount: v.Children()[v.InnerChildIdx].StatsCount(),
 	}
 
@@ -483,6 +548,142 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 	return e
 }
 
+func (b *executorBuilder) buildIndexLookUpJoin(v *plannercore.PhysicalIndexJoin) Executor {
+	outerExec := b.build(v.Children()[1-v.InnerChildIdx])
+	if b.err != nil {
+		return nil
+	}
+	outerTypes := retTypes(outerExec)
+	innerPlan := v.Children()[v.InnerChildIdx]
+	innerTypes := make([]*types.FieldType, innerPlan.Schema().Len())
+	for i, col := range innerPlan.Schema().Columns {
+		innerTypes[i] = col.RetType
+	}
+
+	var (
+		outerFilter           []expression.Expression
+		leftTypes, rightTypes []*types.FieldType
+	)
+
+	if v.InnerChildIdx == 0 {
+		leftTypes, rightTypes = innerTypes, outerTypes
+		outerFilter = v.RightConditions
+		if len(v.LeftConditions) > 0 {
+			b.err = errors.Annotate(ErrBuildExecutor, "join's inner condition should be empty")
+			return nil
+		}
+	} else {
+		leftTypes, rightTypes = outerTypes, innerTypes
+		outerFilter = v.LeftConditions
+		if len(v.RightConditions) > 0 {
+			b.err = errors.Annotate(ErrBuildExecutor, "join's inner condition should be empty")
+			return nil
+		}
+	}
+	defaultValues := v.DefaultValues
+	if defaultValues == nil {
+		defaultValues = make([]types.Datum, len(innerTypes))
+	}
+	e := &IndexLookUpJoin{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), outerExec),
+		outerCtx: outerCtx{
+			rowTypes: outerTypes,
+			filter:   outerFilter,
+		},
+		innerCtx: innerCtx{
+			readerBuilder: &dataReaderBuilder{Plan: innerPlan, executorBuilder: b},
+			rowTypes:      innerTypes,
+		},
+		workerWg:      new(sync.WaitGroup),
+		joiner:        newJoiner(b.ctx, v.JoinType, v.InnerChildIdx == 0, defaultValues, v.OtherConditions, leftTypes, rightTypes),
+		isOuterJoin:   v.JoinType.IsOuterJoin(),
+		indexRanges:   v.Ranges,
+		keyOff2IdxOff: v.KeyOff2IdxOff,
+	}
+	outerKeyCols := make([]int, len(v.OuterJoinKeys))
+	for i := 0; i < len(v.OuterJoinKeys); i++ {
+		outerKeyCols[i] = v.OuterJoinKeys[i].Index
+	}
+	e.outerCtx.keyCols = outerKeyCols
+	innerKeyCols := make([]int, len(v.InnerJoinKeys))
+	for i := 0; i < len(v.InnerJoinKeys); i++ {
+		innerKeyCols[i] = v.InnerJoinKeys[i].Index
+	}
+	e.innerCtx.keyCols = innerKeyCols
+	e.joinResult = newFirstChunk(e)
+	return e
+}
+
+func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
+	leftChild := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	rightChild := b.build(v.Children()[1])
+	if b.err != nil {
+		return nil
+	}
+	otherConditions := append(expression.ScalarFuncs2Exprs(v.EqualConditions), v.OtherConditions...)
+	defaultValues := v.DefaultValues
+	if defaultValues == nil {
+		defaultValues = make([]types.Datum, v.Children()[v.InnerChildIdx].Schema().Len())
+	}
+	tupleJoiner := newJoiner(b.ctx, v.JoinType, v.InnerChildIdx == 0,
+		defaultValues, otherConditions, retTypes(leftChild), retTypes(rightChild))
+	outerExec, innerExec := leftChild, rightChild
+	outerFilter, innerFilter := v.LeftConditions, v.RightConditions
+	if v.InnerChildIdx == 0 {
+		outerExec, innerExec = rightChild, leftChild
+		outerFilter, innerFilter = v.RightConditions, v.LeftConditions
+	}
+	e := &NestedLoopApplyExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), outerExec, innerExec),
+		innerExec:    innerExec,
+		outerExec:    outerExec,
+		outerFilter:  outerFilter,
+		innerFilter:  innerFilter,
+		outer:        v.JoinType != plannercore.InnerJoin,
+		joiner:       tupleJoiner,
+		outerSchema:  v.OuterSchema,
+	}
+	return e
+}
+
+func (b *executorBuilder) buildMaxOneRow(v *plannercore.PhysicalMaxOneRow) Executor {
+	childExec := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), childExec)
+	base.initCap = 2
+	base.maxChunkSize = 2
+	e := &MaxOneRowExec{baseExecutor: base}
+	return e
+}
+
+func (b *executorBuilder) buildSelectLock(v *plannercore.PhysicalLock) Executor {
+	sessVars := b.ctx.GetSessionVars()
+	if sessVars.TxnCtx.IsPessimistic {
+		// The rows are read at the for update ts, so the latest committed rows are locked.
+		b.startTS = sessVars.TxnCtx.GetForUpdateTS()
+	}
+	src := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	if !sessVars.InTxn() && sessVars.IsAutocommit() {
+		// Locking of rows for update using SELECT FOR UPDATE only applies when autocommit
+		// is disabled or the statement is in an explicit transaction.
+		return src
+	}
+	e := &SelectLockExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), src),
+		Lock:         v.Lock,
+		tblID2Handle: v.TblID2Handle,
+	}
+	return e
+}
+
 func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor {
 	src := b.build(v.Children()[0])
 	if b.err != nil {
@@ -541,6 +742,34 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 	return e
 }
 
+func (b *executorBuilder) buildStreamAgg(v *plannercore.PhysicalStreamAgg) Executor {
+	src := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	e := &StreamAggExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), src),
+		groupChecker: newGroupChecker(b.ctx.GetSessionVars().StmtCtx, v.GroupByItems),
+		aggFuncs:     make([]aggfuncs.AggFunc, 0, len(v.AggFuncs)),
+	}
+	// The default value is needed only when there is no group-by item, see
+	// the comments in buildHashAgg.
+	if len(v.GroupByItems) != 0 || aggregation.IsAllFirstRow(v.AggFuncs) {
+		e.defaultVal = nil
+	} else {
+		e.defaultVal = chunk.NewChunkWithCapacity(retTypes(e), 1)
+	}
+	for i, aggDesc := range v.AggFuncs {
+		aggFunc := aggfuncs.Build(b.ctx, aggDesc, i)
+		e.aggFuncs = append(e.aggFuncs, aggFunc)
+		if e.defaultVal != nil {
+			value := aggDesc.GetDefaultValue()
+			e.defaultVal.AppendDatum(i, &value)
+		}
+	}
+	return e
+}
+
 func (b *executorBuilder) buildSelection(v *plannercore.PhysicalSelection) Executor {
 	childExec := b.build(v.Children()[0])
 	if b.err != nil {
@@ -561,7 +790,7 @@ func (b *executorBuilder) buildProjection(v *plannercore.PhysicalProjection) Exe
 	e := &ProjectionExec{
 		baseExecutor:  newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), childExec),
 		numWorkers:    b.ctx.GetSessionVars().ProjectionConcurrency,
-		evaluatorSuit: expression.NewEvaluatorSuite(v.Exprs),
+		evaluatorSuit: expression.NewEvaluatorSuite(v.Exprs, v.AvoidColumnEvaluator),
 	}
 
 	// If the calculation row count for this Projection operator is smaller
@@ -573,6 +802,20 @@ func (b *executorBuilder) buildProjection(v *plannercore.PhysicalProjection) Exe
 	return e
 }
 
+func (b *executorBuilder) buildUnionAll(v *plannercore.PhysicalUnionAll) Executor {
+	childExecs := make([]Executor, len(v.Children()))
+	for i, child := range v.Children() {
+		childExecs[i] = b.build(child)
+		if b.err != nil {
+			return nil
+		}
+	}
+	e := &UnionExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), childExecs...),
+	}
+	return e
+}
+
 func (b *executorBuilder) buildTableDual(v *plannercore.PhysicalTableDual) Executor {
 	if v.RowCount != 0 && v.RowCount != 1 {
 		b.err = errors.Errorf("buildTableDual failed, invalid row count for dual table: %v", v.RowCount)
@@ -593,6 +836,12 @@ func (b *executorBuilder) getStartTS() (uint64, error) {
 		return b.startTS, nil
 	}
 
+	// The statement reads the history data at the snapshot.
+	if snapshotTS := b.ctx.GetSessionVars().StmtCtx.SnapshotTS; snapshotTS != 0 {
+		b.startTS = snapshotTS
+		return b.startTS, nil
+	}
+
 	txn, err := b.ctx.Txn(true)
 	if err != nil {
 		return 0, err
@@ -646,6 +895,138 @@ func (b *executorBuilder) buildTopN(v *plannercore.PhysicalTopN) Executor {
 	}
 }
 
+func (b *executorBuilder) buildCTE(v *plannercore.PhysicalCTE) Executor {
+	storage, ok := b.cteStorages[v.CTE.IDForStorage]
+	if !ok {
+		seedExec := b.build(v.SeedPlan)
+		if b.err != nil {
+			return nil
+		}
+		storage = newCTEStorage(b.ctx, retTypes(seedExec), v.CTE.IsDistinct)
+		storage.seedExec = seedExec
+		if b.cteStorages == nil {
+			b.cteStorages = make(map[int]*cteStorage)
+		}
+		// The storage is registered before building the recursive part, whose
+		// CTETableReaderExec reads the storage.
+		b.cteStorages[v.CTE.IDForStorage] = storage
+		if v.RecurPlan != nil {
+			storage.recursiveExec = b.build(v.RecurPlan)
+			if b.err != nil {
+				return nil
+			}
+		}
+	}
+	return &CTEExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+		storage:      storage,
+	}
+}
+
+func (b *executorBuilder) buildCTETableReader(v *plannercore.PhysicalCTETable) Executor {
+	storage, ok := b.cteStorages[v.IDForStorage]
+	if !ok {
+		b.err = errors.Errorf("buildCTETableReader failed, the storage of CTE_%d is not found", v.IDForStorage)
+		return nil
+	}
+	return &CTETableReaderExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+		storage:      storage,
+	}
+}
+
+func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
+	childExec := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), childExec)
+	groupByItems := make([]expression.Expression, 0, len(v.PartitionBy))
+	for _, item := range v.PartitionBy {
+		groupByItems = append(groupByItems, item.Col)
+	}
+	orderByCols := make([]*expression.Column, 0, len(v.OrderBy))
+	for _, item := range v.OrderBy {
+		orderByCols = append(orderByCols, item.Col)
+	}
+	windowFuncs := make([]aggfuncs.AggFunc, 0, len(v.WindowFuncDescs))
+	partialResults := make([]aggfuncs.PartialResult, 0, len(v.WindowFuncDescs))
+	// The results of the window functions follow the columns of the child.
+	resultColIdx := childExec.Schema().Len()
+	for _, desc := range v.WindowFuncDescs {
+		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, desc.Name, desc.Args)
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		agg := aggfuncs.BuildWindowFunctions(b.ctx, aggDesc, resultColIdx, orderByCols)
+		if agg == nil {
+			b.err = errors.Errorf("unsupported window function %s", desc.Name)
+			return nil
+		}
+		windowFuncs = append(windowFuncs, agg)
+		partialResults = append(partialResults, agg.AllocPartialResult())
+		resultColIdx++
+	}
+	processor := baseWindowProcessor{
+		windowFuncs:    windowFuncs,
+		partialResults: partialResults,
+	}
+	e := &WindowExec{
+		baseExecutor: base,
+		groupChecker: newGroupChecker(b.ctx.GetSessionVars().StmtCtx, groupByItems),
+	}
+	switch {
+	case v.Frame == nil:
+		e.processor = &aggWindowProcessor{baseWindowProcessor: processor}
+	case v.Frame.Type == ast.Rows:
+		e.processor = &rowFrameWindowProcessor{
+			baseWindowProcessor: processor,
+			start:               v.Frame.Start,
+			end:                 v.Frame.End,
+		}
+	default:
+		expectedCmpResults := make([]int64, 0, len(v.OrderBy))
+		for _, item := range v.OrderBy {
+			if item.Desc {
+				expectedCmpResults = append(expectedCmpResults, 1)
+			} else {
+				expectedCmpResults = append(expectedCmpResults, -1)
+			}
+		}
+		e.processor = &rangeFrameWindowProcessor{
+			baseWindowProcessor: processor,
+			start:               v.Frame.Start,
+			end:                 v.Frame.End,
+			orderByCols:         orderByCols,
+			expectedCmpResults:  expectedCmpResults,
+		}
+	}
+	return e
+}
+
+func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
+	tblID2table := make(map[int64]table.Table)
+	for _, info := range v.TblColPosInfos {
+		tblID2table[info.TblID], _ = b.is.TableByID(info.TblID)
+	}
+	b.startTS = b.ctx.GetSessionVars().TxnCtx.GetForUpdateTS()
+	selExec := b.build(v.SelectPlan)
+	if b.err != nil {
+		return nil
+	}
+	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), selExec)
+	base.initCap = chunk.ZeroCapacity
+	updateExec := &UpdateExec{
+		baseExecutor:              base,
+		OrderedList:               v.OrderedList,
+		allAssignmentsAreConstant: v.AllAssignmentsAreConstant,
+		tblID2table:               tblID2table,
+		tblColPosInfos:            v.TblColPosInfos,
+	}
+	return updateExec
+}
+
 func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 	tblID2table := make(map[int64]table.Table)
 	for _, info := range v.TblColPosInfos {
@@ -757,11 +1138,22 @@ func constructDistExec(sctx sessionctx.Context, plans []plannercore.PhysicalPlan
 func (b *executorBuilder) constructDAGReq(plans []plannercore.PhysicalPlan) (dagReq *tipb.DAGRequest, err error) {
 	dagReq = &tipb.DAGRequest{}
 	sc := b.ctx.GetSessionVars().StmtCtx
+	dagReq.TimeZoneName, dagReq.TimeZoneOffset = zone(sc.TimeZone)
 	dagReq.Flags = sc.PushDownFlags()
 	dagReq.Executors, err = constructDistExec(b.ctx, plans)
 	return dagReq, err
 }
 
+// zone returns the name and the offset of the location, the coprocessor
+// prefers the name and falls back to the offset for anonymous fixed zones.
+func zone(loc *time.Location) (string, int64) {
+	if loc == nil {
+		loc = time.Local
+	}
+	_, offset := time.Now().In(loc).Zone()
+	return loc.String(), int64(offset)
+}
+
 func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableReader) (*TableReaderExecutor, error) {
 	dagReq, err := b.constructDAGReq(v.TablePlans)
 	if err != nil {
@@ -769,6 +1161,10 @@ func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableRea
 	}
 	ts := v.TablePlans[0].(*plannercore.PhysicalTableScan)
 	tbl, _ := b.is.TableByID(ts.Table.ID)
+	if isPartition, physicalTableID := ts.IsPartition(); isPartition {
+		pt := tbl.(table.PartitionedTable)
+		tbl = pt.GetPartition(physicalTableID)
+	}
 	startTS, err := b.getStartTS()
 	if err != nil {
 		return nil, err
@@ -814,6 +1210,13 @@ func buildNoRangeIndexReader(b *executorBuilder, v *plannercore.PhysicalIndexRea
 	}
 	is := v.IndexPlans[0].(*plannercore.PhysicalIndexScan)
 	tbl, _ := b.is.TableByID(is.Table.ID)
+	isPartition, physicalTableID := is.IsPartition()
+	if isPartition {
+		pt := tbl.(table.PartitionedTable)
+		tbl = pt.GetPartition(physicalTableID)
+	} else {
+		physicalTableID = is.Table.ID
+	}
 	startTS, err := b.getStartTS()
 	if err != nil {
 		return nil, err
@@ -822,7 +1225,7 @@ func buildNoRangeIndexReader(b *executorBuilder, v *plannercore.PhysicalIndexRea
 		baseExecutor:    newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
 		dagPB:           dagReq,
 		startTS:         startTS,
-		physicalTableID: is.Table.ID,
+		physicalTableID: physicalTableID,
 		table:           tbl,
 		index:           is.Index,
 		keepOrder:       is.KeepOrder,
@@ -867,6 +1270,10 @@ func buildNoRangeIndexLookUpReader(b *executorBuilder, v *plannercore.PhysicalIn
 	is := v.IndexPlans[0].(*plannercore.PhysicalIndexScan)
 	indexReq.OutputOffsets = []uint32{uint32(len(is.Index.Columns))}
 	tbl, _ := b.is.TableByID(is.Table.ID)
+	if isPartition, physicalTableID := is.IsPartition(); isPartition {
+		pt := tbl.(table.PartitionedTable)
+		tbl = pt.GetPartition(physicalTableID)
+	}
 
 	for i := 0; i < v.Schema().Len(); i++ {
 		tableReq.OutputOffsets = append(tableReq.OutputOffsets, uint32(i))
@@ -959,6 +1366,85 @@ func (builder *dataReaderBuilder) buildTableReaderFromHandles(ctx context.Contex
 	return e, nil
 }
 
+func (builder *dataReaderBuilder) buildExecutorForIndexJoin(ctx context.Context, lookUpContents []*indexJoinLookUpContent,
+	indexRanges []*ranger.Range, keyOff2IdxOff []int) (Executor, error) {
+	switch v := builder.Plan.(type) {
+	case *plannercore.PhysicalTableReader:
+		return builder.buildTableReaderForIndexJoin(ctx, v, lookUpContents)
+	case *plannercore.PhysicalIndexReader:
+		return builder.buildIndexReaderForIndexJoin(ctx, v, lookUpContents, indexRanges, keyOff2IdxOff)
+	case *plannercore.PhysicalIndexLookUpReader:
+		return builder.buildIndexLookUpReaderForIndexJoin(ctx, v, lookUpContents, indexRanges, keyOff2IdxOff)
+	}
+	return nil, errors.New("Wrong plan type for dataReaderBuilder")
+}
+
+func (builder *dataReaderBuilder) buildTableReaderForIndexJoin(ctx context.Context, v *plannercore.PhysicalTableReader, lookUpContents []*indexJoinLookUpContent) (Executor, error) {
+	e, err := buildNoRangeTableReader(builder.executorBuilder, v)
+	if err != nil {
+		return nil, err
+	}
+	handles := make([]int64, 0, len(lookUpContents))
+	for _, content := range lookUpContents {
+		handles = append(handles, content.keys[0].GetInt64())
+	}
+	return builder.buildTableReaderFromHandles(ctx, e, handles)
+}
+
+func (builder *dataReaderBuilder) buildIndexReaderForIndexJoin(ctx context.Context, v *plannercore.PhysicalIndexReader,
+	lookUpContents []*indexJoinLookUpContent, indexRanges []*ranger.Range, keyOff2IdxOff []int) (Executor, error) {
+	e, err := buildNoRangeIndexReader(builder.executorBuilder, v)
+	if err != nil {
+		return nil, err
+	}
+	kvRanges, err := buildKvRangesForIndexJoin(e.ctx, e.physicalTableID, e.index.ID, lookUpContents, indexRanges, keyOff2IdxOff)
+	if err != nil {
+		return nil, err
+	}
+	err = e.open(ctx, kvRanges)
+	return e, err
+}
+
+func (builder *dataReaderBuilder) buildIndexLookUpReaderForIndexJoin(ctx context.Context, v *plannercore.PhysicalIndexLookUpReader,
+	lookUpContents []*indexJoinLookUpContent, indexRanges []*ranger.Range, keyOff2IdxOff []int) (Executor, error) {
+	e, err := buildNoRangeIndexLookUpReader(builder.executorBuilder, v)
+	if err != nil {
+		return nil, err
+	}
+	e.kvRanges, err = buildKvRangesForIndexJoin(e.ctx, getPhysicalTableID(e.table), e.index.ID, lookUpContents, indexRanges, keyOff2IdxOff)
+	if err != nil {
+		return nil, err
+	}
+	err = e.open(ctx)
+	return e, err
+}
+
+// buildKvRangesForIndexJoin builds kv ranges for index join when the inner plan is index scan plan.
+// The positions of the join keys in the template ranges are filled with the lookup keys one by one.
+func buildKvRangesForIndexJoin(ctx sessionctx.Context, tableID, indexID int64, lookUpContents []*indexJoinLookUpContent,
+	ranges []*ranger.Range, keyOff2IdxOff []int) ([]kv.KeyRange, error) {
+	kvRanges := make([]kv.KeyRange, 0, len(ranges)*len(lookUpContents))
+	sc := ctx.GetSessionVars().StmtCtx
+	for _, content := range lookUpContents {
+		for _, ran := range ranges {
+			for keyOff, idxOff := range keyOff2IdxOff {
+				ran.LowVal[idxOff] = content.keys[keyOff]
+				ran.HighVal[idxOff] = content.keys[keyOff]
+			}
+		}
+		tmpKvRanges, err := distsql.IndexRangesToKVRanges(sc, tableID, indexID, ranges)
+		if err != nil {
+			return nil, err
+		}
+		kvRanges = append(kvRanges, tmpKvRanges...)
+	}
+	// kvRanges don't overlap each other. So compare StartKey is enough.
+	sort.Slice(kvRanges, func(i, j int) bool {
+		return bytes.Compare(kvRanges[i].StartKey, kvRanges[j].StartKey) < 0
+	})
+	return kvRanges, nil
+}
+
 func getPhysicalTableID(t table.Table) int64 {
 	if p, ok := t.(table.PhysicalTable); ok {
 		return p.GetPhysicalID()
@@ -0,0 +1,220 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+)
+
+// cteStorage holds the result of a common table expression, it is shared by all the
+// CTEExecs reading the same CTE, so the CTE is only computed once in a statement.
+//
+// A recursive CTE is computed as follows:
+//  1. Run the seed part, put its rows into resTbl and iterInTbl.
+//  2. Run the recursive part, whose CTETableReaderExec reads iterInTbl, and put the
+//     new rows into resTbl and iterOutTbl.
+//  3. Swap iterInTbl and iterOutTbl, stop if iterInTbl is empty, otherwise go to 2.
+type cteStorage struct {
+	ctx           sessionctx.Context
+	seedExec      Executor
+	recursiveExec Executor
+	// isDistinct indicates the duplicated rows should be removed from the result.
+	isDistinct bool
+
+	resTbl     *chunk.List
+	iterInTbl  *chunk.List
+	iterOutTbl *chunk.List
+	fieldTypes []*types.FieldType
+	// hashTbl stores the encoded rows in resTbl, it is only used when isDistinct is true.
+	hashTbl map[string]struct{}
+	hashBuf *bytes.Buffer
+	colIdx  []int
+
+	computed bool
+}
+
+func newCTEStorage(ctx sessionctx.Context, fieldTypes []*types.FieldType, isDistinct bool) *cteStorage {
+	initCap, maxChunkSize := ctx.GetSessionVars().InitChunkSize, ctx.GetSessionVars().MaxChunkSize
+	s := &cteStorage{
+		ctx:        ctx,
+		isDistinct: isDistinct,
+		resTbl:     chunk.NewList(fieldTypes, initCap, maxChunkSize),
+		iterInTbl:  chunk.NewList(fieldTypes, initCap, maxChunkSize),
+		iterOutTbl: chunk.NewList(fieldTypes, initCap, maxChunkSize),
+		fieldTypes: fieldTypes,
+	}
+	if isDistinct {
+		s.hashTbl = make(map[string]struct{})
+		s.hashBuf = new(bytes.Buffer)
+		s.colIdx = make([]int, len(fieldTypes))
+		for i := range s.colIdx {
+			s.colIdx[i] = i
+		}
+	}
+	return s
+}
+
+// compute fills resTbl with the result of the CTE if it is not computed yet.
+func (s *cteStorage) compute(ctx context.Context) error {
+	if s.computed {
+		return nil
+	}
+	s.computed = true
+	if err := s.fill(ctx, s.seedExec, s.iterInTbl); err != nil {
+		return err
+	}
+	if s.recursiveExec == nil {
+		return nil
+	}
+	maxDepth := s.ctx.GetSessionVars().CTEMaxRecursionDepth
+	for iter := 1; s.iterInTbl.Len() > 0; iter++ {
+		if iter > maxDepth {
+			return ErrCTEMaxRecursionDepth.GenWithStackByArgs(iter)
+		}
+		if err := s.fill(ctx, s.recursiveExec, s.iterOutTbl); err != nil {
+			return err
+		}
+		s.iterInTbl, s.iterOutTbl = s.iterOutTbl, s.iterInTbl
+		s.iterOutTbl.Reset()
+	}
+	return nil
+}
+
+// fill runs the executor and appends its new rows to both resTbl and the list.
+func (s *cteStorage) fill(ctx context.Context, e Executor, list *chunk.List) (err error) {
+	if err = e.Open(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := e.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	chk := newFirstChunk(e)
+	for {
+		if err = Next(ctx, e, chk); err != nil {
+			return err
+		}
+		if chk.NumRows() == 0 {
+			return nil
+		}
+		for i := 0; i < chk.NumRows(); i++ {
+			row := chk.GetRow(i)
+			if s.isDistinct {
+				isNew, err := s.addToHashTbl(row)
+				if err != nil {
+					return err
+				}
+				if !isNew {
+					continue
+				}
+			}
+			s.resTbl.AppendRow(row)
+			list.AppendRow(row)
+		}
+	}
+}
+
+// addToHashTbl adds the row to hashTbl and reports whether the row is not added before.
+func (s *cteStorage) addToHashTbl(row chunk.Row) (bool, error) {
+	s.hashBuf.Reset()
+	err := codec.HashChunkRow(s.ctx.GetSessionVars().StmtCtx, s.hashBuf, row, s.fieldTypes, s.colIdx, make([]byte, 1))
+	if err != nil {
+		return false, err
+	}
+	key := s.hashBuf.String()
+	if _, ok := s.hashTbl[key]; ok {
+		return false, nil
+	}
+	s.hashTbl[key] = struct{}{}
+	return true, nil
+}
+
+// listCursor points to the next row to read in a chunk.List.
+type listCursor struct {
+	chkIdx int
+	rowIdx int
+}
+
+// read appends the rows after the cursor to req until req is full.
+func (c *listCursor) read(list *chunk.List, req *chunk.Chunk) {
+	for !req.IsFull() && c.chkIdx < list.NumChunks() {
+		chk := list.GetChunk(c.chkIdx)
+		if c.rowIdx >= chk.NumRows() {
+			c.chkIdx++
+			c.rowIdx = 0
+			continue
+		}
+		req.AppendRow(chk.GetRow(c.rowIdx))
+		c.rowIdx++
+	}
+}
+
+// CTEExec reads the result of a common table expression.
+type CTEExec struct {
+	baseExecutor
+
+	storage *cteStorage
+	cursor  listCursor
+}
+
+// Open implements the Executor Open interface.
+func (e *CTEExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.cursor = listCursor{}
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *CTEExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if err := e.storage.compute(ctx); err != nil {
+		return err
+	}
+	e.cursor.read(e.storage.resTbl, req)
+	return nil
+}
+
+// CTETableReaderExec reads the rows produced by the previous iteration of a recursive
+// common table expression.
+type CTETableReaderExec struct {
+	baseExecutor
+
+	storage *cteStorage
+	cursor  listCursor
+}
+
+// Open implements the Executor Open interface.
+func (e *CTETableReaderExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.cursor = listCursor{}
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *CTETableReaderExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	e.cursor.read(e.storage.iterInTbl, req)
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite) TestCTE(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 10), (2, 20), (3, 30)")
+
+	// Non-recursive CTEs referenced once are inlined, the others are materialized.
+	tk.MustQuery("with c as (select a, b from t where a > 1) select * from c order by a").Check(testkit.Rows("2 20", "3 30"))
+	tk.MustQuery("with c(x, y) as (select a, b from t) select x + y from c where x = 1").Check(testkit.Rows("11"))
+	tk.MustQuery("with c as (select a from t) select c1.a, c2.a from c c1 join c c2 on c1.a + 1 = c2.a order by c1.a").Check(testkit.Rows("1 2", "2 3"))
+	tk.MustQuery("with c1 as (select a from t), c2 as (select a * 2 as a from c1) select * from c2 order by a").Check(testkit.Rows("2", "4", "6"))
+	tk.MustQuery("with c as (select 1 as a) select * from c union all select * from c").Check(testkit.Rows("1", "1"))
+	tk.MustQuery("with t as (select 100 as a) select * from t").Check(testkit.Rows("100"))
+	tk.MustQuery("select (with c as (select b from t where t.a = t2.a) select b from c) from t t2 order by a").Check(testkit.Rows("10", "20", "30"))
+	tk.MustQuery("select * from (with c as (select a from t where a = 2) select * from c) d").Check(testkit.Rows("2"))
+	tk.MustQuery("with c as (select 1 as a) select * from (with c as (select 2 as a) select * from c) d, c").Check(testkit.Rows("2 1"))
+
+	// Recursive CTEs.
+	tk.MustQuery("with recursive c(n) as (select 1 union all select n + 1 from c where n < 5) select * from c").Check(testkit.Rows("1", "2", "3", "4", "5"))
+	tk.MustQuery("with recursive c(n) as (select 1 union all select n + 1 from c where n < 3) select c1.n, c2.n from c c1, c c2 where c1.n = c2.n order by c1.n").Check(testkit.Rows("1 1", "2 2", "3 3"))
+	tk.MustQuery("with recursive c(n) as (select 1 union select 4 - n from c) select * from c order by n").Check(testkit.Rows("1", "3"))
+	tk.MustQuery("with recursive c(n) as (select 1 union all select 1 union select n + 1 from c where n < 2) select * from c order by n").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("with recursive c(n, s) as (select 1, 1 union all select n + 1, s + n + 1 from c where n < 4 union all select n + 10, s from c where n < 2) select * from c order by n").Check(testkit.Rows(
+		"1 1", "2 3", "3 6", "4 10", "11 1"))
+	tk.MustQuery("with recursive c as (select 1 as n) select * from c").Check(testkit.Rows("1"))
+	// The rows of the recursive part are converted to the types of the seed part.
+	tk.MustQuery("with recursive c(n) as (select 1 union all select n + 1.5 from c where n < 5) select * from c").Check(testkit.Rows("1", "3", "5"))
+	tk.MustQuery("with recursive c(n) as (select 1.5 union all select n * 2e0 from c where n < 5) select n, n + 0.01 from c").Check(testkit.Rows("1.5 1.51", "3.0 3.01", "6.0 6.01"))
+	tk.MustQuery("with recursive c(n, s) as (select 1, '1' union all select n + 1, s + n from c where n < 3) select * from c").Check(testkit.Rows("1 1", "2 2", "3 4"))
+	tk.MustQuery("with recursive c(n) as (select 1e0 union all select n + 1 from c where n < 3) select * from c").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("with recursive c(n, s) as (select 1, 'a' union all select n + 1, concat(s, 'a') from c where n < 3) select * from c").Check(testkit.Rows("1 a", "2 a", "3 a"))
+
+	// Hierarchical queries.
+	tk.MustExec("drop table if exists emp")
+	tk.MustExec("create table emp (id int, name varchar(20), manager_id int)")
+	tk.MustExec("insert into emp values (1, 'ceo', null), (2, 'cto', 1), (3, 'cfo', 1), (4, 'dev', 2), (5, 'intern', 4)")
+	tk.MustQuery(`with recursive chain(id, name, depth) as (
+		select id, name, 0 from emp where manager_id is null
+		union all
+		select e.id, e.name, chain.depth + 1 from emp e join chain on e.manager_id = chain.id)
+		select * from chain order by depth, id`).Check(testkit.Rows("1 ceo 0", "2 cto 1", "3 cfo 1", "4 dev 2", "5 intern 3"))
+	tk.MustQuery(`with recursive reports(id) as (
+		select id from emp where name = 'cto'
+		union all
+		select e.id from emp e, reports r where e.manager_id = r.id)
+		select count(*) from reports`).Check(testkit.Rows("3"))
+
+	// The recursion depth is limited by cte_max_recursion_depth.
+	tk.MustExec("set @@cte_max_recursion_depth = 10")
+	tk.MustQuery("with recursive c(n) as (select 1 union all select n + 1 from c where n < 10) select count(*) from c").Check(testkit.Rows("10"))
+	err := tk.QueryToErr("with recursive c(n) as (select 1 union all select n + 1 from c where n < 11) select count(*) from c")
+	c.Assert(terror.ErrorEqual(err, executor.ErrCTEMaxRecursionDepth), IsTrue, Commentf("err %v", err))
+	err = tk.QueryToErr("with recursive c(n) as (select 1 union all select n + 1 from c) select * from c")
+	c.Assert(terror.ErrorEqual(err, executor.ErrCTEMaxRecursionDepth), IsTrue, Commentf("err %v", err))
+	tk.MustExec("set @@cte_max_recursion_depth = 0")
+	err = tk.QueryToErr("with recursive c(n) as (select 1 union all select n + 1 from c where n < 1) select * from c")
+	c.Assert(terror.ErrorEqual(err, executor.ErrCTEMaxRecursionDepth), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("with recursive c(n) as (select a from t where a > 3 union all select n + 1 from c) select count(*) from c").Check(testkit.Rows("0"))
+}
+
+func (s *testSuite) TestCTEErrors(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+
+	tests := []struct {
+		sql string
+		err *terror.Error
+	}{
+		{"with c as (select 1), c as (select 2) select * from c", plannercore.ErrNonUniqTable},
+		{"with c(a, b) as (select 1) select * from c", plannercore.ErrViewWrongList},
+		{"with c as (select 1 as a, 2 as a) select * from c", plannercore.ErrDupFieldName},
+		{"with recursive c as (select a from c) select * from c", plannercore.ErrCTERecursiveRequiresUnion},
+		{"with recursive c(n) as (select 1 except select n from c) select * from c", plannercore.ErrCTERecursiveRequiresUnion},
+		{"with recursive c(n) as (select n from c union all select 1) select * from c", plannercore.ErrCTERecursiveRequiresNonRecursiveFirst},
+		{"with recursive c(n) as (select 1 union all select n from c union all select 2) select * from c", plannercore.ErrCTERecursiveRequiresNonRecursiveFirst},
+		{"with recursive c(n) as (select 1 union all select count(n) from c) select * from c", plannercore.ErrCTERecursiveForbidsAggregation},
+		{"with recursive c(n) as (select 1 union all select row_number() over () from c) select * from c", plannercore.ErrCTERecursiveForbidsAggregation},
+		{"with recursive c(n) as (select 1 union all select c1.n from c c1, c c2) select * from c", plannercore.ErrInvalidRequiresSingleReference},
+		{"with recursive c(n) as (select 1 union all select a from t where a in (select n from c)) select * from c", plannercore.ErrInvalidRequiresSingleReference},
+		{"with recursive c(n) as (select 1 union all select n from c limit 1) select * from c", plannercore.ErrNotSupportedYet},
+		{"with recursive c(n) as (select 1 union all select distinct n from c) select * from c", plannercore.ErrNotSupportedYet},
+		{"with recursive c(n) as (select 1 union all select n, n from c) select * from c", plannercore.ErrWrongNumberOfColumnsInSelect},
+	}
+	for _, t := range tests {
+		_, err := tk.Exec(t.sql)
+		c.Assert(terror.ErrorEqual(err, t.err), IsTrue, Commentf("sql %s, err %v", t.sql, err))
+	}
+}
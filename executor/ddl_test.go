@@ -16,8 +16,11 @@ package executor_test
 import (
 	"fmt"
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/ddl"
 	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
@@ -56,6 +59,114 @@ func (s *testSuite6) TestCreateDropTable(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (s *testSuite6) TestCreateDropView(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t2")
+	tk.MustExec("drop view if exists v, v2, v3")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 10), (2, 20), (3, 30)")
+
+	tk.MustExec("create view v as select a, b from t where a > 1")
+	tk.MustQuery("select * from v order by a").Check(testkit.Rows("2 20", "3 30"))
+	tk.MustQuery("select v.b from v where v.a = 3").Check(testkit.Rows("30"))
+	tk.MustQuery("select x.a, t.b from v x join t on x.a = t.a order by x.a").Check(testkit.Rows("2 20", "3 30"))
+	tk.MustExec("insert into t values (4, 40)")
+	tk.MustQuery("select count(*) from v").Check(testkit.Rows("3"))
+
+	// The column list of the view renames the columns of the SELECT statement.
+	tk.MustExec("create view v2 (x, y) as select a, a + b from t")
+	tk.MustQuery("select y from v2 where x = 1").Check(testkit.Rows("11"))
+	tk.MustExec("create view v3 as select count(*) as cnt from v2 union all select 1")
+	tk.MustQuery("select * from v3").Check(testkit.Rows("4", "1"))
+	tk.MustQuery("select * from v, (select x from test.v2 where x = 2) d where v.a = d.x").Check(testkit.Rows("2 20 2"))
+
+	// The unqualified tables in a view belong to the database of the view.
+	tk.MustExec("drop database if exists view_db")
+	tk.MustExec("create database view_db")
+	tk.MustExec("create table view_db.t (c int)")
+	tk.MustExec("insert into view_db.t values (100)")
+	tk.MustExec("create view view_db.v as select c from t")
+	tk.MustQuery("select * from view_db.v").Check(testkit.Rows("100"))
+	tk.MustExec("drop database view_db")
+
+	tk.MustQuery("show create view v").Check(testkit.Rows(
+		"v CREATE VIEW `v` (`a`, `b`) AS select a, b from t where a > 1 utf8mb4 utf8mb4_bin"))
+	tk.MustQuery("show create table v2").Check(testkit.Rows(
+		"v2 CREATE VIEW `v2` (`x`, `y`) AS select a, a + b from t utf8mb4 utf8mb4_bin"))
+	tk.MustQuery("show full tables").Check(testkit.Rows("t BASE TABLE", "v VIEW", "v2 VIEW", "v3 VIEW"))
+	tk.MustQuery("select table_name, table_type from information_schema.tables where table_schema = 'test' and table_type = 'VIEW' order by table_name").Check(
+		testkit.Rows("v VIEW", "v2 VIEW", "v3 VIEW"))
+	tk.MustQuery("select table_name, view_definition from information_schema.views where table_schema = 'test' and table_name = 'v2'").Check(
+		testkit.Rows("v2 select a, a + b from t"))
+
+	// Replace the view.
+	err := tk.ExecToErr("create view v as select 1")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableExists), IsTrue, Commentf("err %v", err))
+	tk.MustExec("create or replace view v as select b from t where a = 1")
+	tk.MustQuery("select * from v").Check(testkit.Rows("10"))
+	err = tk.ExecToErr("create or replace view t as select 1")
+	c.Assert(terror.ErrorEqual(err, ddl.ErrWrongObject), IsTrue, Commentf("err %v", err))
+
+	// Views are read only.
+	for _, sql := range []string{"insert into v values (1)", "update v set b = 1", "delete from v"} {
+		err = tk.ExecToErr(sql)
+		c.Assert(terror.ErrorEqual(err, plannercore.ErrNonUpdatableTable), IsTrue, Commentf("sql %s, err %v", sql, err))
+	}
+
+	// A view whose underlying table is dropped can't be queried.
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("create view v4 as select * from t2")
+	tk.MustExec("drop table t2")
+	err = tk.ExecToErr("select * from v4")
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrViewInvalid), IsTrue, Commentf("err %v", err))
+	c.Assert(err.Error(), Equals, "[planner:1356]View 'test.v4' references invalid table(s) or column(s) or function(s) or definer/invoker of view lack rights to use them")
+
+	// DROP TABLE can't drop a view and DROP VIEW can't drop a table.
+	err = tk.ExecToErr("drop table v")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableDropExists), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("drop view t")
+	c.Assert(terror.ErrorEqual(err, ddl.ErrWrongObject), IsTrue, Commentf("err %v", err))
+	err = tk.QueryToErr("show create view t")
+	c.Assert(terror.ErrorEqual(err, executor.ErrWrongObject), IsTrue, Commentf("err %v", err))
+	tk.MustExec("drop view v, v2, v3, v4")
+	tk.MustExec("drop view if exists v")
+	err = tk.ExecToErr("drop view v")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableDropExists), IsTrue, Commentf("err %v", err))
+}
+
+func (s *testSuite6) TestCreateViewErrors(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("drop view if exists v, v1, v2")
+	tk.MustExec("create table t (a int, b int)")
+
+	tests := []struct {
+		sql string
+		err *terror.Error
+	}{
+		{"create view v (x) as select a, b from t", plannercore.ErrViewWrongList},
+		{"create view v as select a, a from t", plannercore.ErrDupFieldName},
+		{"create view v (x, x) as select a, b from t", plannercore.ErrDupFieldName},
+		{"create view v as select c from t", plannercore.ErrUnknownColumn},
+		{"create view v as select * from t_not_exists", infoschema.ErrTableNotExists},
+		{"create view v as select * from v", infoschema.ErrTableNotExists},
+	}
+	for _, t := range tests {
+		err := tk.ExecToErr(t.sql)
+		c.Assert(terror.ErrorEqual(err, t.err), IsTrue, Commentf("sql %s, err %v", t.sql, err))
+	}
+
+	// A view can't refer to itself even through other views.
+	tk.MustExec("create view v1 as select a from t")
+	tk.MustExec("create view v2 as select a from v1")
+	err := tk.ExecToErr("create or replace view v1 as select a from v2")
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrViewRecursive), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("select * from v2").Check(testkit.Rows())
+	tk.MustExec("drop view v1, v2")
+}
+
 func (s *testSuite6) TestCreateDropIndex(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -41,6 +41,14 @@ var (
 	ErrWrongObject                 = terror.ClassExecutor.New(mysql.ErrWrongObject, mysql.MySQLErrName[mysql.ErrWrongObject])
 	ErrRoleNotGranted              = terror.ClassPrivilege.New(mysql.ErrRoleNotGranted, mysql.MySQLErrName[mysql.ErrRoleNotGranted])
 	ErrQueryInterrupted            = terror.ClassExecutor.New(mysql.ErrQueryInterrupted, mysql.MySQLErrName[mysql.ErrQueryInterrupted])
+	ErrSubqueryMoreThan1Row        = terror.ClassExecutor.New(mysql.ErrSubqueryNo1Row, mysql.MySQLErrName[mysql.ErrSubqueryNo1Row])
+	ErrUserAlreadyExists           = terror.ClassExecutor.New(mysql.ErrUserAlreadyExists, mysql.MySQLErrName[mysql.ErrUserAlreadyExists])
+	ErrNonexistingGrant            = terror.ClassExecutor.New(mysql.ErrNonexistingGrant, mysql.MySQLErrName[mysql.ErrNonexistingGrant])
+	ErrIllegalGrantForTable        = terror.ClassExecutor.New(mysql.ErrIllegalGrantForTable, mysql.MySQLErrName[mysql.ErrIllegalGrantForTable])
+	ErrNoSuchThread                = terror.ClassExecutor.New(mysql.ErrNoSuchThread, mysql.MySQLErrName[mysql.ErrNoSuchThread])
+	ErrAsOf                        = terror.ClassExecutor.New(mysql.ErrAsOf, mysql.MySQLErrName[mysql.ErrAsOf])
+	ErrWriteOnSnapshot             = terror.ClassExecutor.New(mysql.ErrWriteOnSnapshot, mysql.MySQLErrName[mysql.ErrWriteOnSnapshot])
+	ErrCTEMaxRecursionDepth        = terror.ClassExecutor.New(mysql.ErrCTEMaxRecursionDepth, mysql.MySQLErrName[mysql.ErrCTEMaxRecursionDepth])
 )
 
 func init() {
@@ -68,6 +76,14 @@ func init() {
 		mysql.ErrRoleNotGranted:              mysql.ErrRoleNotGranted,
 		mysql.ErrQueryInterrupted:            mysql.ErrQueryInterrupted,
 		mysql.ErrWrongValueCountOnRow:        mysql.ErrWrongValueCountOnRow,
+		mysql.ErrSubqueryNo1Row:              mysql.ErrSubqueryNo1Row,
+		mysql.ErrUserAlreadyExists:           mysql.ErrUserAlreadyExists,
+		mysql.ErrNonexistingGrant:            mysql.ErrNonexistingGrant,
+		mysql.ErrIllegalGrantForTable:        mysql.ErrIllegalGrantForTable,
+		mysql.ErrNoSuchThread:                mysql.ErrNoSuchThread,
+		mysql.ErrAsOf:                        mysql.ErrAsOf,
+		mysql.ErrWriteOnSnapshot:             mysql.ErrWriteOnSnapshot,
+		mysql.ErrCTEMaxRecursionDepth:        mysql.ErrCTEMaxRecursionDepth,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassExecutor] = tableMySQLErrCodes
 }
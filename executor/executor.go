@@ -16,7 +16,10 @@ package executor
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
@@ -25,12 +28,21 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/admin"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/stringutil"
+	"go.uber.org/zap"
 )
 
 var (
@@ -40,6 +52,8 @@ var (
 	_ Executor = &IndexLookUpExecutor{}
 	_ Executor = &IndexReaderExecutor{}
 	_ Executor = &LimitExec{}
+	_ Executor = &MaxOneRowExec{}
+	_ Executor = &SelectLockExec{}
 	_ Executor = &MergeJoinExec{}
 	_ Executor = &ProjectionExec{}
 	_ Executor = &SelectionExec{}
@@ -50,6 +64,7 @@ var (
 	_ Executor = &TableReaderExecutor{}
 	_ Executor = &TableScanExec{}
 	_ Executor = &TopNExec{}
+	_ Executor = &UnionExec{}
 )
 
 type baseExecutor struct {
@@ -60,6 +75,7 @@ type baseExecutor struct {
 	maxChunkSize  int
 	children      []Executor
 	retFieldTypes []*types.FieldType
+	runtimeStats  *execdetails.RuntimeStats
 }
 
 // base returns the baseExecutor of an executor, don't override this method!
@@ -123,6 +139,9 @@ func newBaseExecutor(ctx sessionctx.Context, schema *expression.Schema, id fmt.S
 		initCap:      ctx.GetSessionVars().InitChunkSize,
 		maxChunkSize: ctx.GetSessionVars().MaxChunkSize,
 	}
+	if coll := ctx.GetSessionVars().StmtCtx.RuntimeStatsColl; coll != nil && id != nil {
+		e.runtimeStats = coll.Get(id.String())
+	}
 	if schema != nil {
 		cols := schema.Columns
 		e.retFieldTypes = make([]*types.FieldType, len(cols))
@@ -154,13 +173,25 @@ type Executor interface {
 // Next is a wrapper function on e.Next(), it handles some common codes.
 func Next(ctx context.Context, e Executor, req *chunk.Chunk) error {
 	base := e.base()
-	sessVars := base.ctx.GetSessionVars()
-	if atomic.CompareAndSwapUint32(&sessVars.Killed, 1, 0) {
-		return ErrQueryInterrupted
+	if err := checkKilled(base.ctx); err != nil {
+		return err
+	}
+	if base.runtimeStats != nil {
+		start := time.Now()
+		defer func() { base.runtimeStats.Record(time.Since(start), req.NumRows()) }()
 	}
 	return e.Next(ctx, req)
 }
 
+// checkKilled returns ErrQueryInterrupted if the running statement of the session is killed.
+// The loops that don't call Next of their children for a long time check it to stop promptly.
+func checkKilled(sctx sessionctx.Context) error {
+	if atomic.LoadUint32(&sctx.GetSessionVars().Killed) == 1 {
+		return ErrQueryInterrupted
+	}
+	return nil
+}
+
 // ShowDDLExec represents a show DDL executor.
 type ShowDDLExec struct {
 	baseExecutor
@@ -310,6 +341,160 @@ func (e *LimitExec) adjustRequiredRows(chk *chunk.Chunk) *chunk.Chunk {
 	return chk.SetRequiredRows(mathutil.Min(limitTotal, limitRequired), e.maxChunkSize)
 }
 
+func init() {
+	// While doing optimization in the plan package, we need to execute uncorrelated subquery,
+	// but the plan package cannot import the executor package because of the dependency cycle.
+	// So we assign a function implemented in the executor package to the plan package to avoid the dependency cycle.
+	plannercore.EvalSubqueryFirstRow = func(ctx context.Context, p plannercore.PhysicalPlan, is infoschema.InfoSchema, sctx sessionctx.Context) ([]types.Datum, error) {
+		e := newExecutorBuilder(sctx, is)
+		exec := e.build(p)
+		if e.err != nil {
+			return nil, e.err
+		}
+		err := exec.Open(ctx)
+		defer terror.Call(exec.Close)
+		if err != nil {
+			return nil, err
+		}
+		chk := newFirstChunk(exec)
+		err = Next(ctx, exec, chk)
+		if err != nil {
+			return nil, err
+		}
+		if chk.NumRows() == 0 {
+			return nil, nil
+		}
+		return chk.GetRow(0).GetDatumRow(retTypes(exec)), nil
+	}
+}
+
+// MaxOneRowExec checks if the number of rows that a query returns is at maximum one.
+// It's built from subquery expression.
+type MaxOneRowExec struct {
+	baseExecutor
+
+	evaluated bool
+}
+
+// Open implements the Executor Open interface.
+func (e *MaxOneRowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.evaluated = false
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *MaxOneRowExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.evaluated {
+		return nil
+	}
+	e.evaluated = true
+	err := Next(ctx, e.children[0], req)
+	if err != nil {
+		return err
+	}
+
+	if num := req.NumRows(); num == 0 {
+		for i := range e.schema.Columns {
+			req.AppendNull(i)
+		}
+		return nil
+	} else if num != 1 {
+		return ErrSubqueryMoreThan1Row
+	}
+
+	childChunk := newFirstChunk(e.children[0])
+	err = Next(ctx, e.children[0], childChunk)
+	if err != nil {
+		return err
+	}
+	if childChunk.NumRows() != 0 {
+		return ErrSubqueryMoreThan1Row
+	}
+
+	return nil
+}
+
+// SelectLockExec represents a select lock executor.
+// It is built from the "SELECT .. FOR UPDATE" statement, it collects the row
+// keys read from the child executor and locks them after all the rows are read.
+// In a pessimistic transaction the keys are locked in KV right away, otherwise
+// they are buffered in the transaction and checked for conflicts on commit.
+type SelectLockExec struct {
+	baseExecutor
+
+	Lock ast.SelectLockType
+	keys []kv.Key
+
+	tblID2Handle map[int64][]*expression.Column
+}
+
+// Open implements the Executor Open interface.
+func (e *SelectLockExec) Open(ctx context.Context) error {
+	e.keys = e.keys[:0]
+	return e.baseExecutor.Open(ctx)
+}
+
+// Next implements the Executor Next interface.
+func (e *SelectLockExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.GrowAndReset(e.maxChunkSize)
+	err := Next(ctx, e.children[0], req)
+	if err != nil {
+		return err
+	}
+	if len(e.tblID2Handle) == 0 {
+		return nil
+	}
+	if req.NumRows() > 0 {
+		iter := chunk.NewIterator4Chunk(req)
+		for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+			for id, cols := range e.tblID2Handle {
+				for _, col := range cols {
+					e.keys = append(e.keys, tablecodec.EncodeRowKeyWithHandle(id, row.GetInt64(col.Index)))
+				}
+			}
+		}
+		return nil
+	}
+	if len(e.keys) == 0 {
+		return nil
+	}
+	lockWaitTime := e.ctx.GetSessionVars().LockWaitTimeout
+	if e.Lock == ast.SelectLockForUpdateNoWait {
+		lockWaitTime = kv.LockNoWait
+	}
+	keys := e.keys
+	e.keys = nil
+	return doLockKeys(ctx, e.ctx, newLockCtx(e.ctx.GetSessionVars(), lockWaitTime), keys...)
+}
+
+func newLockCtx(seVars *variable.SessionVars, lockWaitTime int64) *kv.LockCtx {
+	lockCtx := &kv.LockCtx{
+		Killed:        &seVars.Killed,
+		LockWaitTime:  lockWaitTime,
+		WaitStartTime: time.Now(),
+	}
+	// Only a pessimistic transaction locks the keys in KV before commit.
+	if seVars.TxnCtx.IsPessimistic {
+		lockCtx.ForUpdateTS = seVars.TxnCtx.GetForUpdateTS()
+	}
+	return lockCtx
+}
+
+func doLockKeys(ctx context.Context, se sessionctx.Context, lockCtx *kv.LockCtx, keys ...kv.Key) error {
+	// The rows read by SELECT FOR UPDATE may change when the transaction is replayed,
+	// so the transaction can't be retried.
+	se.GetSessionVars().TxnCtx.ForUpdate = true
+	txn, err := se.Txn(true)
+	if err != nil {
+		return err
+	}
+	return txn.LockKeys(ctx, lockCtx, keys...)
+}
+
 // TableDualExec represents a dual table executor.
 type TableDualExec struct {
 	baseExecutor
@@ -389,11 +574,17 @@ func (e *SelectionExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			1. the `req` chunk` is full.
 			2. there is no further results from child.
 			3. meets any error.
-	 */
+	*/
 	for {
 		// Fill in the `req` util it is full or the `inputIter` is fully processed.
 		for ; e.inputRow != e.inputIter.End(); e.inputRow = e.inputIter.Next() {
-			// Your code here.
+			if !e.selected[e.inputRow.Idx()] {
+				continue
+			}
+			if req.IsFull() {
+				return nil
+			}
+			req.AppendRow(e.inputRow)
 		}
 		err := Next(ctx, e.children[0], e.childResult)
 		if err != nil {
@@ -403,9 +594,11 @@ func (e *SelectionExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		if e.childResult.NumRows() == 0 {
 			return nil
 		}
-		/* Your code here.
-		   Process and filter the child result using `expression.VectorizedFilter`.
-		 */
+		e.selected, err = expression.VectorizedFilter(e.ctx, e.filters, e.inputIter, e.selected)
+		if err != nil {
+			return err
+		}
+		e.inputRow = e.inputIter.Begin()
 	}
 }
 
@@ -533,10 +726,159 @@ func (e *TableScanExec) Open(ctx context.Context) error {
 	return nil
 }
 
+// UnionExec pulls all it's children's result and returns to its parent directly.
+// A "resultPuller" is started for every child to pull result from that child and push it to the "resultPool", the used
+// "Chunk" is obtained from the corresponding "resourcePool". All resultPullers are running concurrently.
+//
+//	                          +----------------+
+//	+---> resourcePool 1 ---> | resultPuller 1 |-----+
+//	|                         +----------------+     |
+//	|                                                |
+//	|                         +----------------+     v
+//	+---> resourcePool 2 ---> | resultPuller 2 |-----> resultPool ---+
+//	|                         +----------------+     ^               |
+//	|                               ......           |               |
+//	|                         +----------------+     |               |
+//	+---> resourcePool n ---> | resultPuller n |-----+               |
+//	|                         +----------------+                     |
+//	|                                                                |
+//	|                          +-------------+                       |
+//	|--------------------------| main thread | <---------------------+
+//	                           +-------------+
+type UnionExec struct {
+	baseExecutor
+
+	stopFetchData atomic.Value
+
+	finished      chan struct{}
+	resourcePools []chan *chunk.Chunk
+	resultPool    chan *unionWorkerResult
+
+	childrenResults []*chunk.Chunk
+	wg              sync.WaitGroup
+	initialized     bool
+}
+
+// unionWorkerResult stores the result for a union worker.
+// A "resultPuller" is started for every child to pull result from that child, unionWorkerResult is used to store that pulled result.
+// "src" is used for Chunk reuse: after pulling result from "resultPool", main-thread must push a valid unused Chunk to "src" to
+// enable the corresponding "resultPuller" continue to work.
+type unionWorkerResult struct {
+	chk *chunk.Chunk
+	err error
+	src chan<- *chunk.Chunk
+}
+
+func (e *UnionExec) waitAllFinished() {
+	e.wg.Wait()
+	close(e.resultPool)
+}
+
+// Open implements the Executor Open interface.
+func (e *UnionExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	for _, child := range e.children {
+		e.childrenResults = append(e.childrenResults, newFirstChunk(child))
+	}
+	e.stopFetchData.Store(false)
+	e.initialized = false
+	e.finished = make(chan struct{})
+	return nil
+}
+
+func (e *UnionExec) initialize(ctx context.Context) {
+	e.resultPool = make(chan *unionWorkerResult, len(e.children))
+	e.resourcePools = make([]chan *chunk.Chunk, len(e.children))
+	for i := range e.children {
+		e.resourcePools[i] = make(chan *chunk.Chunk, 1)
+		e.resourcePools[i] <- e.childrenResults[i]
+		e.wg.Add(1)
+		go e.resultPuller(ctx, i)
+	}
+	go e.waitAllFinished()
+}
+
+func (e *UnionExec) resultPuller(ctx context.Context, childID int) {
+	result := &unionWorkerResult{
+		err: nil,
+		chk: nil,
+		src: e.resourcePools[childID],
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			buf := make([]byte, 4096)
+			stackSize := runtime.Stack(buf, false)
+			buf = buf[:stackSize]
+			logutil.Logger(ctx).Error("resultPuller panicked", zap.String("stack", string(buf)))
+			result.err = errors.Errorf("%v", r)
+			e.resultPool <- result
+			e.stopFetchData.Store(true)
+		}
+		e.wg.Done()
+	}()
+	for {
+		if e.stopFetchData.Load().(bool) {
+			return
+		}
+		select {
+		case <-e.finished:
+			return
+		case result.chk = <-e.resourcePools[childID]:
+		}
+		result.err = Next(ctx, e.children[childID], result.chk)
+		if result.err == nil && result.chk.NumRows() == 0 {
+			return
+		}
+		e.resultPool <- result
+		if result.err != nil {
+			e.stopFetchData.Store(true)
+			return
+		}
+	}
+}
+
+// Next implements the Executor Next interface.
+func (e *UnionExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.GrowAndReset(e.maxChunkSize)
+	if !e.initialized {
+		e.initialize(ctx)
+		e.initialized = true
+	}
+	result, ok := <-e.resultPool
+	if !ok {
+		return nil
+	}
+	if result.err != nil {
+		return errors.Trace(result.err)
+	}
+
+	req.SwapColumns(result.chk)
+	result.src <- result.chk
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *UnionExec) Close() error {
+	if e.finished != nil {
+		close(e.finished)
+	}
+	e.childrenResults = nil
+	if e.resultPool != nil {
+		for range e.resultPool {
+		}
+	}
+	e.resourcePools = nil
+	return e.baseExecutor.Close()
+}
+
 func extractStmtHintsFromStmtNode(stmtNode ast.StmtNode) []*ast.TableOptimizerHint {
 	switch x := stmtNode.(type) {
 	case *ast.SelectStmt:
 		return x.TableHints
+	case *ast.UpdateStmt:
+		return nil
 	case *ast.DeleteStmt:
 		return nil
 	// TODO: support hint for InsertStmt
@@ -608,16 +950,32 @@ func handleStmtHints(hints []*ast.TableOptimizerHint) (stmtHints stmtctx.StmtHin
 // ResetContextOfStmt resets the StmtContext and session variables.
 // Before every execution, we must clear statement context.
 func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
+	vars := ctx.GetSessionVars()
+	// The context of an EXECUTE statement is the context of the statement it executes.
+	if execStmt, ok := s.(*ast.ExecuteStmt); ok {
+		s, err = getPreparedStmt(execStmt, vars)
+		if err != nil {
+			return
+		}
+	}
 	hints := extractStmtHintsFromStmtNode(s)
 	stmtHints, hintWarns := handleStmtHints(hints)
-	vars := ctx.GetSessionVars()
 	sc := &stmtctx.StatementContext{
 		StmtHints: stmtHints,
 		TimeZone:  vars.Location(),
 	}
+	memQuota := vars.MemQuotaQuery
+	if sc.HasMemQuotaHint {
+		memQuota = sc.MemQuotaQuery
+	}
+	sc.MemTracker = memory.NewTracker(stringutil.StringerStr("query"), memQuota)
+	sc.MemTracker.SetActionOnExceed(&memory.LogOnExceed{ConnID: vars.ConnectionID})
 	if explainStmt, ok := s.(*ast.ExplainStmt); ok {
 		sc.InExplainStmt = true
 		sc.CastStrToIntStrict = true
+		if explainStmt.Analyze {
+			sc.RuntimeStatsColl = execdetails.NewRuntimeStatsColl()
+		}
 		s = explainStmt.Stmt
 	}
 	// TODO: Many same bool variables here.
@@ -625,6 +983,13 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	// IgnoreErr and StrictSQLMode) to avoid setting the same bool variables and
 	// pushing them down to TiKV as flags.
 	switch stmt := s.(type) {
+	case *ast.UpdateStmt:
+		sc.InUpdateStmt = true
+		sc.BadNullAsWarning = !vars.StrictSQLMode
+		sc.TruncateAsWarning = !vars.StrictSQLMode
+		sc.DividedByZeroAsWarning = !vars.StrictSQLMode
+		sc.AllowInvalidDate = vars.SQLMode.HasAllowInvalidDatesMode()
+		sc.IgnoreZeroInDate = !vars.StrictSQLMode || sc.AllowInvalidDate
 	case *ast.DeleteStmt:
 		sc.InDeleteStmt = true
 		sc.BadNullAsWarning = !vars.StrictSQLMode
@@ -660,6 +1025,14 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 		}
 		sc.PadCharToFullLength = ctx.GetSessionVars().SQLMode.HasPadCharToFullLengthMode()
 		sc.CastStrToIntStrict = true
+	case *ast.SetOprStmt:
+		sc.InSelectStmt = true
+		sc.OverflowAsWarning = true
+		sc.TruncateAsWarning = true
+		sc.IgnoreZeroInDate = true
+		sc.AllowInvalidDate = vars.SQLMode.HasAllowInvalidDatesMode()
+		sc.PadCharToFullLength = ctx.GetSessionVars().SQLMode.HasPadCharToFullLengthMode()
+		sc.CastStrToIntStrict = true
 	case *ast.ShowStmt:
 		sc.IgnoreTruncate = true
 		sc.IgnoreZeroInDate = true
@@ -679,7 +1052,7 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 		sc.PrevLastInsertID = vars.StmtCtx.PrevLastInsertID
 	}
 	sc.PrevAffectedRows = 0
-	if vars.StmtCtx.InDeleteStmt || vars.StmtCtx.InInsertStmt {
+	if vars.StmtCtx.InUpdateStmt || vars.StmtCtx.InDeleteStmt || vars.StmtCtx.InInsertStmt {
 		sc.PrevAffectedRows = int64(vars.StmtCtx.AffectedRows())
 	} else if vars.StmtCtx.InSelectStmt {
 		sc.PrevAffectedRows = -1
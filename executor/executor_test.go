@@ -638,6 +638,58 @@ func (s *testSuiteP1) TestIn(c *C) {
 	tk.MustQuery(queryStr).Check(testkit.Rows("7"))
 }
 
+func (s *testSuiteP1) TestSetOperation(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3, t4")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("create table t2 (a int, b int)")
+	tk.MustExec("create table t3 (a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1), (1, 1), (2, 2), (3, null), (null, null)")
+	tk.MustExec("insert into t2 values (1, 1), (3, null), (4, 4), (null, null)")
+
+	tk.MustQuery("select a from t1 union select a from t2 order by a").Check(testkit.Rows("<nil>", "1", "2", "3", "4"))
+	tk.MustQuery("select a from t1 union all select a from t2 order by a").Check(testkit.Rows("<nil>", "<nil>", "1", "1", "1", "2", "3", "3", "4"))
+	tk.MustQuery("select a, b from t1 intersect select a, b from t2 order by a").Check(testkit.Rows("<nil> <nil>", "1 1", "3 <nil>"))
+	tk.MustQuery("select a, b from t1 except select a, b from t2").Check(testkit.Rows("2 2"))
+	tk.MustQuery("select a, b from t2 except select a, b from t1").Check(testkit.Rows("4 4"))
+	tk.MustQuery("select a from t1 except select a from t3 order by a").Check(testkit.Rows("<nil>", "1", "2", "3"))
+	tk.MustQuery("select a from t1 intersect select a from t3").Check(testkit.Rows())
+
+	// INTERSECT has a higher precedence than UNION and EXCEPT.
+	tk.MustQuery("select a from t3 union select a from t1 intersect select a from t2 order by a").Check(testkit.Rows("<nil>", "1", "3"))
+	tk.MustQuery("select a from t1 except select a from t1 union select 5").Check(testkit.Rows("5"))
+	// A DISTINCT union overrides any ALL union to its left.
+	tk.MustQuery("select 1 union all select 1 union select 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select 1 union select 1 union all select 1").Check(testkit.Rows("1", "1"))
+
+	tk.MustQuery("select a from t1 union all select a from t2 order by a desc limit 2, 3").Check(testkit.Rows("3", "2", "1"))
+	tk.MustQuery("(select a from t1 order by a limit 1) union all (select a from t2 order by a desc limit 1) order by a").Check(testkit.Rows("<nil>", "4"))
+	tk.MustQuery("select x.a from (select a from t1 union select a from t2) x where x.a > 2 order by x.a").Check(testkit.Rows("3", "4"))
+	tk.MustQuery("select t1.a from t1 where t1.a in (select a from t2 intersect select b from t2) order by t1.a").Check(testkit.Rows("1", "1"))
+
+	tk.MustQuery("select a from t1 intersect select 1 from t2").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t1 except select 1 from t2 order by a").Check(testkit.Rows("<nil>", "2", "3"))
+
+	tk.MustExec("insert into t3 select a, b from t1 except select a, b from t2")
+	tk.MustQuery("select * from t3").Check(testkit.Rows("2 2"))
+
+	// Columns of different types are converted to the unified type.
+	tk.MustQuery("select 1 union select 'a'").Sort().Check(testkit.Rows("1", "a"))
+	tk.MustQuery("select 1 union select 1.5").Sort().Check(testkit.Rows("1.0", "1.5"))
+	tk.MustQuery("select 1.5 union all select 2e0").Sort().Check(testkit.Rows("1.5", "2"))
+	tk.MustQuery("select a from t2 where a = 4 union select 'x' intersect select 'x'").Sort().Check(testkit.Rows("4", "x"))
+	tk.MustExec("create table t4 (a int, d decimal(5, 2))")
+	tk.MustExec("insert into t4 values (1, 2.5), (3, 3)")
+	tk.MustQuery("select a from t4 union select d from t4 order by a").Check(testkit.Rows("1.00", "2.50", "3.00"))
+	tk.MustQuery("select d from t4 except select a from t4").Check(testkit.Rows("2.50"))
+
+	_, err := tk.Exec("select a from t1 union select a, b from t2")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("select a from t1 intersect select a, b from t2")
+	c.Assert(err, NotNil)
+}
+
 func (s *testSuiteP1) TestTablePKisHandleScan(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -978,6 +1030,194 @@ func (s *testSuite) TestSimpleDAG(c *C) {
 	tk.MustQuery("select * from t where b = 1 and a > 1 limit 1").Check(testkit.Rows("2 1 1"))
 }
 
+func (s *testSuite) TestLikeAndRegexp(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b varchar(20), index idx_b(b))")
+	tk.MustExec("insert into t values (1, 'abc'), (2, 'abd'), (3, 'ab%'), (4, 'xabc'), (5, null), (6, 'ABC')")
+	tk.MustQuery("select a from t where b like 'ab%' order by a").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select a from t where b like 'ab_' order by a").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select a from t where b like '%bc' order by a").Check(testkit.Rows("1", "4"))
+	tk.MustQuery("select a from t where b like 'ab\\%' order by a").Check(testkit.Rows("3"))
+	tk.MustQuery("select a from t where b like 'ab|%' escape '|' order by a").Check(testkit.Rows("3"))
+	tk.MustQuery("select a from t where b like 'abc' order by a").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where b not like 'ab%' order by a").Check(testkit.Rows("4", "6"))
+	tk.MustQuery("select a from t where b regexp '^ab' order by a").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select a from t where b rlike 'c$' order by a").Check(testkit.Rows("1", "4"))
+	tk.MustQuery("select a from t where b not regexp 'b' order by a").Check(testkit.Rows("6"))
+	tk.MustQuery("select a from t where regexp_like(b, '^abc$', 'i') order by a").Check(testkit.Rows("1", "6"))
+	tk.MustQuery("select regexp_substr(b, '[a-c]+', 2), regexp_replace(b, 'b', '-') from t where a = 4").Check(testkit.Rows("abc xa-c"))
+	tk.MustQuery("select 'abc' like 'a%', 'abc' like null, 'abc' regexp 'd', null regexp 'a'").Check(testkit.Rows("1 <nil> 0 <nil>"))
+
+	// The prefix of the pattern is used to build the index range.
+	c.Assert(explainContains(tk, "select b from t where b like 'ab%'", "IndexReader"), IsTrue)
+	c.Assert(explainContains(tk, "select b from t where b like 'ab%'", "range:[\"ab\",\"ac\")"), IsTrue)
+	// The LIKE and REGEXP filters are pushed down to the coprocessor.
+	c.Assert(explainContains(tk, "select a from t use index() where b like '%b%'", "like(test.t.b, \"%b%\", 92)"), IsTrue)
+	c.Assert(explainContains(tk, "select a from t use index() where b regexp 'b'", "regexp(test.t.b, \"b\")"), IsTrue)
+	tk.MustQuery("select a from t use index() where b like '%b%' order by a").Check(testkit.Rows("1", "2", "3", "4"))
+	tk.MustQuery("select a from t use index() where b regexp 'c$' order by a").Check(testkit.Rows("1", "4"))
+
+	err := tk.QueryToErr("select a from t where b regexp '('")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("select a from t where b like 'a' escape 'ab'")
+	c.Assert(err, NotNil)
+
+	tk.MustExec("drop table if exists t_like1, t_like2, t_other")
+	tk.MustExec("create table t_like1(a int)")
+	tk.MustExec("create table t_like2(a int)")
+	tk.MustExec("create table t_other(a int)")
+	tk.MustQuery("show tables like 't_like%'").Check(testkit.Rows("t_like1", "t_like2"))
+	tk.MustQuery("show tables like 't_other'").Check(testkit.Rows("t_other"))
+	tk.MustQuery("show databases like 'tes_'").Check(testkit.Rows("test"))
+	tk.MustQuery("show variables like 'autocommit'").Check(testkit.Rows("autocommit 1"))
+}
+
+func (s *testSuite) TestStringFunctions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b varchar(20), c varchar(20))")
+	tk.MustExec("insert into t values (1, ' Hello ', 'world'), (2, '你好', '世界'), (3, null, 'x')")
+	tk.MustQuery("select concat(b, c), concat_ws('-', b, c) from t order by a").Check(testkit.Rows(" Hello world  Hello -world", "你好世界 你好-世界", "<nil> x"))
+	tk.MustQuery("select substring(c, 2), substr(c from 2 for 2), left(c, 1), right(c, 1) from t where a = 1").Check(testkit.Rows("orld or w d"))
+	tk.MustQuery("select substring(b, -1), left(b, 1), char_length(b), reverse(b) from t where a = 2").Check(testkit.Rows("好 你 2 好你"))
+	tk.MustQuery("select upper(c), lower(b), ucase(b), lcase(c) from t where a = 1").Check(testkit.Rows("WORLD  hello   HELLO  world"))
+	tk.MustQuery("select trim(b), ltrim(b), rtrim(b), trim(leading ' ' from b), trim(trailing from b), trim(both 'l' from c) from t where a = 1").
+		Check(testkit.Rows("Hello Hello   Hello Hello   Hello world"))
+	tk.MustQuery("select trim('x' from 'xxaxx'), trim(both 'x' from 'xxaxx'), trim(leading 'x' from 'xxaxx'), trim(trailing 'x' from 'xxaxx')").
+		Check(testkit.Rows("a a axx xxa"))
+	tk.MustQuery("select replace(c, 'o', '0'), locate('o', c), locate('o', c, 3), position('r' in c), instr(c, 'ld') from t where a = 1").
+		Check(testkit.Rows("w0rld 2 0 3 4"))
+	tk.MustQuery("select lpad(c, 7, '*'), rpad(c, 3, '*'), repeat(c, 2) from t where a = 2").Check(testkit.Rows("*****世界 世界* 世界世界"))
+	tk.MustQuery("select hex('abc'), hex(255), unhex('616263'), unhex('zz')").Check(testkit.Rows("616263 FF abc <nil>"))
+	tk.MustQuery("select format(12332.123456, 4), format(12332.2, 0), format(12332.1, 2, 'en_US')").Check(testkit.Rows("12,332.1235 12,332 12,332.10"))
+	tk.MustQuery("select field('b', 'a', 'b', 'c'), field(2, 1, 2), field(null, 'a'), elt(2, 'a', 'b'), elt(3, 'a', 'b')").Check(testkit.Rows("2 2 0 b <nil>"))
+
+	// The string functions are pushed down to the coprocessor.
+	c.Assert(explainContains(tk, "select a from t use index() where upper(c) = 'WORLD'", "eq(upper(test.t.c), \"WORLD\")"), IsTrue)
+	c.Assert(explainContains(tk, "select a from t use index() where locate('o', c) > 0", "gt(locate(\"o\", test.t.c), 0)"), IsTrue)
+	tk.MustQuery("select a from t use index() where upper(c) = 'WORLD'").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t use index() where concat(c, '!') = '世界!'").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t use index() where char_length(c) = 1").Check(testkit.Rows("3"))
+	tk.MustQuery("select a from t use index() where lpad(c, 3, '-') = '--x'").Check(testkit.Rows("3"))
+	tk.MustQuery("select a from t use index() where trim(leading ' ' from b) = 'Hello '").Check(testkit.Rows("1"))
+
+	tk.MustExec("set @@max_allowed_packet = 1024")
+	tk.MustQuery("select repeat('a', 2000)").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1301 Result of repeat() was larger than max_allowed_packet (1024) - truncated"))
+	tk.MustExec("set @@max_allowed_packet = default")
+}
+
+func (s *testSuite) TestMathFunctionsAndOperators(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t(a int primary key, b double, c decimal(10, 2), d bigint unsigned)")
+	tk.MustExec("insert into t values (-7, 2.5, -3.45, 18446744073709551615), (6, -0.5, 12.34, 3), (9, null, null, null)")
+	tk.MustQuery("select a div 2, a mod 4, a % -4, c div 2, c mod 2, b mod 2, d div 2, d % 10 from t order by a").Check(testkit.Rows(
+		"-3 -3 -3 -1 -1.45 0.5 9223372036854775807 5",
+		"3 2 2 6 0.34 -0.5 1 3",
+		"4 1 1 <nil> <nil> <nil> <nil> <nil>"))
+	tk.MustQuery("select a & 3, a | 1, a ^ 5, a << 2, d >> 62, ~a from t where a = 6").Check(testkit.Rows("2 7 3 24 0 18446744073709551609"))
+	tk.MustQuery("select 1 << 64, 1 >> 64, ~0, 5 & null").Check(testkit.Rows("0 0 18446744073709551615 <nil>"))
+	tk.MustQuery("select abs(a), abs(b), abs(c), ceil(c), floor(c), ceil(b), floor(b), round(c), round(c, 1), truncate(c, 1), truncate(a, -1) from t where a = -7").
+		Check(testkit.Rows("7 2.5 3.45 -3 -4 3 2 -3 -3.5 -3.4 0"))
+	tk.MustQuery("select round(1234.5678, -2), round(-2.5), truncate(1234.5678, 2), truncate(-1.999e0, 1)").Check(testkit.Rows("1200 -3 1234.56 -1.9"))
+	tk.MustQuery("select pow(2, 10), power(4, 0.5), sqrt(16), sqrt(-1), exp(0), ln(1), log(2, 1024), log(0), log2(8), log10(1000)").
+		Check(testkit.Rows("1024 2 4 <nil> 1 0 10 <nil> 3 3"))
+	tk.MustQuery("select sign(-2.5), sign(0), sign('3'), crc32('MySQL'), conv('a', 16, 2), conv(-17, 10, -18), conv('zz', 1, 10)").
+		Check(testkit.Rows("-1 0 1 3259397556 1010 -H <nil>"))
+	tk.MustQuery("select pi(), degrees(pi()), radians(180), cos(0), sin(0), tan(0), acos(1), asin(2), atan(0), atan2(0, 1)").
+		Check(testkit.Rows("3.141592653589793 180 3.141592653589793 1 0 0 0 <nil> 0 0"))
+	tk.MustQuery("select rand(1), rand(1), rand(null)").Check(testkit.Rows("0.40540353712197724 0.40540353712197724 0.15522042769493574"))
+	tk.MustQuery("select rand(3) from t order by a").Check(testkit.Rows("0.9057697559760601", "0.37307905813034536", "0.14808605345719125"))
+	tk.MustQuery("select rand(a) from t order by a").Check(testkit.Rows("0.4039386654309357", "0.6563190842571847", "0.406868412538309"))
+
+	// Division by zero returns NULL with a warning.
+	tk.MustQuery("select 5 div 0, 5 mod 0, 5.5 % 0").Check(testkit.Rows("<nil> <nil> <nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1365 Division by 0", "Warning 1365 Division by 0", "Warning 1365 Division by 0"))
+
+	// Out of range results report the same errors as MySQL.
+	err := tk.QueryToErr("select abs(-9223372036854775808)")
+	c.Assert(err.Error(), Equals, "[types:1690]BIGINT value is out of range in 'abs(-9223372036854775808)'")
+	err = tk.QueryToErr("select -9223372036854775808 div -1")
+	c.Assert(err.Error(), Equals, "[types:1690]BIGINT value is out of range in '(-9223372036854775808 DIV -1)'")
+	err = tk.QueryToErr("select pow(10, 400)")
+	c.Assert(err.Error(), Equals, "[types:1690]DOUBLE value is out of range in 'pow(10, 400)'")
+	err = tk.QueryToErr("select exp(a * 200) from t where a = 6")
+	c.Assert(err.Error(), Equals, "[types:1690]DOUBLE value is out of range in 'exp(cast(mul(test.t.a, 200)))'")
+
+	// The math functions and operators are pushed down to the coprocessor, RAND is not.
+	c.Assert(explainContains(tk, "select a from t where a % 2 = 0", "eq(mod(test.t.a, 2), 0)"), IsTrue)
+	c.Assert(explainContains(tk, "select a from t where abs(c) > 10", "gt(abs(test.t.c), 10)"), IsTrue)
+	c.Assert(explainContains(tk, "select a from t where a & 1 = 1", "eq(bitand(test.t.a, 1), 1)"), IsTrue)
+	c.Assert(explainContains(tk, "select a from t where rand() < 2", "root"), IsTrue)
+	tk.MustQuery("select a from t where a % 2 = 0").Check(testkit.Rows("6"))
+	tk.MustQuery("select a from t where a div 3 = 3").Check(testkit.Rows("9"))
+	tk.MustQuery("select a from t where abs(c) > 10").Check(testkit.Rows("6"))
+	tk.MustQuery("select a from t where floor(b) = -1").Check(testkit.Rows("6"))
+	tk.MustQuery("select a from t where round(sqrt(a)) = 3").Check(testkit.Rows("9"))
+	tk.MustQuery("select a from t where a & 1 = 1 order by a").Check(testkit.Rows("-7", "9"))
+	tk.MustQuery("select a from t where ~a = 18446744073709551606").Check(testkit.Rows("9"))
+	tk.MustExec("create table t1(s varchar(10))")
+	tk.MustExec("insert into t1 values ('110'), ('9'), (null)")
+	tk.MustQuery("select s from t1 where conv(s, 2, 10) = '6'").Check(testkit.Rows("110"))
+	tk.MustQuery("select s from t1 where crc32(s) = 2366072709").Check(testkit.Rows("9"))
+}
+
+func (s *testSuite) TestControlFunctionsAndCast(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b varchar(10), c decimal(10, 2), d datetime)")
+	tk.MustExec("insert into t values (1, 'x', 1.5, '2020-01-02 10:11:12'), (2, null, null, null), (3, '10', -2.25, '2019-12-31 00:00:00')")
+	tk.MustQuery("select a, case a when 1 then 'one' when 2 then 'two' else 'many' end, case when c > 0 then c when c < 0 then -c end from t order by a").Check(testkit.Rows(
+		"1 one 1.50", "2 two <nil>", "3 many 2.25"))
+	tk.MustQuery("select case 1 when 1.0 then 'a' end, case when 0.4 then 1 else 0 end, case when null then 1 end, case 'a' when 'b' then 1 end").
+		Check(testkit.Rows("a 1 <nil> <nil>"))
+	tk.MustQuery("select a, coalesce(b, c, a), coalesce(c, 0), ifnull(b, 'none'), nullif(a, 2) from t order by a").Check(testkit.Rows(
+		"1 x 1.50 x 1", "2 2 0.00 none <nil>", "3 10 -2.25 10 3"))
+	tk.MustQuery("select coalesce(null, null), coalesce(null, 1, 2), nullif(1, 1), nullif('a', 'b'), nullif(null, 1)").Check(testkit.Rows("<nil> 1 <nil> a <nil>"))
+	tk.MustQuery("select greatest(1, 3, 2), least(1, 3, 2), greatest(1.5, 2), least('b', 'a', 'c'), greatest(1, null), greatest('10', 9), least(-1, 18446744073709551615)").
+		Check(testkit.Rows("3 1 2.0 a <nil> 10 -1"))
+	tk.MustQuery("select greatest(d, '2020-01-01'), least(d, '2020-01-01') from t where a = 1").Check(testkit.Rows("2020-01-02 10:11:12 2020-01-01"))
+	tk.MustQuery("select greatest(d, cast('2020-01-01' as date)) from t where a = 3").Check(testkit.Rows("2020-01-01 00:00:00"))
+	tk.MustQuery("select if(0.4, 'yes', 'no'), if('0.4', 'yes', 'no'), 0.4 and 1, 0 or 0.4").Check(testkit.Rows("yes yes 1 1"))
+
+	// CAST and CONVERT.
+	tk.MustQuery("select cast(1.5 as signed), cast(-1 as unsigned), cast('12abc' as signed), cast(3.14159 as decimal(5, 2)), cast(12 as char), cast('abcdef' as char(3))").
+		Check(testkit.Rows("2 18446744073709551615 12 3.14 12 abc"))
+	tk.MustQuery("select cast('2020-01-02 10:11:12.5' as datetime), cast('2020-01-02 10:11:12.5' as datetime(1)), cast('2020-01-02' as date), cast('10:11:12' as time), cast(1.5 as double)").
+		Check(testkit.Rows("2020-01-02 10:11:13 2020-01-02 10:11:12.5 2020-01-02 10:11:12 1.5"))
+	tk.MustQuery("select convert(1.5, signed), convert('abc' using utf8mb4), convert(d, date) from t where a = 1").Check(testkit.Rows("2 abc 2020-01-02"))
+	tk.MustQuery("select cast(b as signed), cast(c as char), cast(a as decimal(4, 1)) from t order by a").Check(testkit.Rows(
+		"0 1.50 1.0", "<nil> <nil> 2.0", "10 -2.25 3.0"))
+	_, err := tk.Exec("select cast(1 as decimal(66, 2))")
+	c.Assert(err.Error(), Equals, "[types:1426]Too big precision 66 specified for column 'CAST'. Maximum is 65.")
+	_, err = tk.Exec("select cast(1 as decimal(10, 31))")
+	c.Assert(err.Error(), Equals, "[types:1425]Too big scale 31 specified for column 'CAST'. Maximum is 30.")
+	_, err = tk.Exec("select cast(1 as decimal(2, 3))")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("select cast(now() as datetime(7))")
+	c.Assert(err.Error(), Equals, "[types:1426]Too big precision 7 specified for column 'CAST'. Maximum is 6.")
+
+	// The constant branches are folded without evaluating the others.
+	c.Assert(explainContains(tk, "select if(1, 2, a) from t", "if("), IsFalse)
+	tk.MustQuery("select case when 1 then 2 else a end, coalesce(1, a) from t where a = 1").Check(testkit.Rows("2 1"))
+
+	// The control functions are pushed down to the coprocessor.
+	c.Assert(explainContains(tk, "select a from t where case when c > 0 then 1 else 0 end = 1", "case(gt(test.t.c, 0), 1, 0)"), IsTrue)
+	c.Assert(explainContains(tk, "select a from t where coalesce(b, 'z') = 'z'", "coalesce(test.t.b, \"z\")"), IsTrue)
+	tk.MustQuery("select a from t where case when c > 0 then 1 else 0 end = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where coalesce(b, 'z') = 'z'").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t where greatest(a, 2) = 2 order by a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t where least(c, 0) < 0").Check(testkit.Rows("3"))
+	tk.MustQuery("select a from t where cast(c as signed) = 2").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where cast(a as char) = '3'").Check(testkit.Rows("3"))
+}
+
 type testSuite1 struct {
 	store kv.Storage
 	dom   *domain.Domain
@@ -1150,6 +1390,108 @@ func (s *testSuite) TestLimit(c *C) {
 	))
 }
 
+func (s *testSuite) TestTimeTypes(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a datetime, b date, c timestamp, d time, key(a))")
+	tk.MustExec("set @@time_zone = '+00:00'")
+	tk.MustExec(`insert into t values("2020-01-01 10:00:00", "2020-01-01", "2020-01-01 10:00:00", "10:00:00"),
+		("2020-03-31 23:59:59", "2020-03-31", "2020-03-31 23:59:59", "-01:30:00"),
+		("2019-12-31 00:00:01", "2019-12-31", "2019-12-31 00:00:01", "838:59:59")`)
+	tk.MustQuery("select * from t where a > '2020-01-01' order by a").Check(testkit.Rows(
+		"2020-01-01 10:00:00 2020-01-01 2020-01-01 10:00:00 10:00:00",
+		"2020-03-31 23:59:59 2020-03-31 2020-03-31 23:59:59 -01:30:00",
+	))
+	tk.MustQuery("select b from t where d < '00:00:00'").Check(testkit.Rows("2020-03-31"))
+	tk.MustQuery("select max(a), min(b), max(d) from t").Check(testkit.Rows("2020-03-31 23:59:59 2019-12-31 838:59:59"))
+
+	tk.MustExec("set @@time_zone = '+08:00'")
+	tk.MustQuery("select c from t where b = '2020-01-01'").Check(testkit.Rows("2020-01-01 18:00:00"))
+
+	tk.MustQuery("select date_format(a, '%Y/%m/%d %H:%i') from t where b = '2020-03-31'").Check(testkit.Rows("2020/03/31 23:59"))
+	tk.MustQuery("select date_add(b, interval 1 month), date_sub(a, interval 1 day) from t where b = '2020-03-31'").Check(testkit.Rows(
+		"2020-04-30 2020-03-30 23:59:59",
+	))
+	tk.MustQuery("select b + interval 1 month, a - interval 1 second, interval 1 day + b from t where b = '2020-03-31'").Check(testkit.Rows(
+		"2020-04-30 2020-03-31 23:59:58 2020-04-01",
+	))
+	tk.MustQuery("select b from t where a - interval 1 day < '2020-01-01' order by b").Check(testkit.Rows("2019-12-31", "2020-01-01"))
+	tk.MustQuery("select datediff(a, '2019-12-01'), extract(year_month from b) from t where b = '2020-01-01'").Check(testkit.Rows("31 202001"))
+	tk.MustQuery("select unix_timestamp('2020-01-01 08:00:00'), from_unixtime(0)").Check(testkit.Rows("1577836800 1970-01-01 08:00:00"))
+
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec("create table t1(id int, ts timestamp default current_timestamp)")
+	tk.MustExec("insert into t1(id) values(1)")
+	tk.MustQuery("select count(*) from t1 where ts is not null and ts <= now()").Check(testkit.Rows("1"))
+	tk.MustQuery("select count(*) from t1 where ts > now() - interval 1 minute").Check(testkit.Rows("1"))
+}
+
+func (s *testSuite) TestJSON(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, j json)")
+	tk.MustExec(`insert into t values (1, '{"name": "a", "n": 3, "tags": ["x", "y"]}'), (2, '[1, 2, 3]'), (3, '"str"'), (4, 'null'), (5, null), (6, '1.5')`)
+	tk.MustQuery("select a, j from t order by a").Check(testkit.Rows(
+		`1 {"n": 3, "name": "a", "tags": ["x", "y"]}`, "2 [1, 2, 3]", `3 "str"`, "4 null", "5 <nil>", "6 1.5"))
+	_, err := tk.Exec(`insert into t values (7, '{"a":')`)
+	c.Assert(err, NotNil)
+
+	// JSON_EXTRACT and the -> and ->> operators.
+	tk.MustQuery(`select j->'$.name', j->>'$.name', json_extract(j, '$.tags[1]'), j->'$.tags[*]' from t where a = 1`).Check(testkit.Rows(`"a" a "y" ["x", "y"]`))
+	tk.MustQuery(`select a from t where j->'$.n' = 3`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select a from t where j->>'$.name' = 'a'`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select json_extract(j, '$[0]', '$[2]'), j->'$[5]' from t where a = 2`).Check(testkit.Rows("[1, 3] <nil>"))
+	err = tk.QueryToErr(`select json_extract(j, '$a') from t`)
+	c.Assert(err, NotNil)
+
+	// Attribute functions.
+	tk.MustQuery("select a, json_type(j), json_length(j), json_keys(j) from t order by a").Check(testkit.Rows(
+		`1 OBJECT 3 ["n", "name", "tags"]`, "2 ARRAY 3 <nil>", "3 STRING 1 <nil>", "4 NULL 1 <nil>", "5 <nil> <nil> <nil>", "6 DOUBLE 1 <nil>"))
+	tk.MustQuery(`select json_length(j, '$.tags'), json_keys(j, '$.tags'), json_contains(j, '"x"', '$.tags'), json_contains(j, '{"n": 3}'), json_contains(j, '{"n": 4}') from t where a = 1`).
+		Check(testkit.Rows("2 <nil> 1 1 0"))
+
+	// Creation and modification functions.
+	tk.MustQuery(`select json_array(1, 'a', null, true), json_object('k', 'v', 'n', 1.5), json_array(json_object('a', 1)), json_unquote('"a\\tb"'), json_unquote('abc')`).
+		Check(testkit.Rows(`[1, "a", null, true] {"k": "v", "n": 1.5} [{"a": 1}] a	b abc`))
+	tk.MustQuery(`select json_set(j, '$.n', 4, '$.m', 'new'), json_insert(j, '$.n', 4, '$.m', 'new'), json_replace(j, '$.n', 4, '$.m', 'new'), json_remove(j, '$.tags', '$.n') from t where a = 1`).
+		Check(testkit.Rows(`{"m": "new", "n": 4, "name": "a", "tags": ["x", "y"]} {"m": "new", "n": 3, "name": "a", "tags": ["x", "y"]} {"n": 4, "name": "a", "tags": ["x", "y"]} {"name": "a"}`))
+	tk.MustQuery(`select json_set(j, '$[1]', 'b', '$[5]', 6), json_remove(j, '$[0]') from t where a = 2`).Check(testkit.Rows(`[1, "b", 3, 6] [2, 3]`))
+	tk.MustExec(`update t set j = json_set(j, '$.n', j->'$.n' + 1) where a = 1`)
+	tk.MustQuery(`select j->'$.n' from t where a = 1`).Check(testkit.Rows("4.0"))
+	err = tk.QueryToErr(`select json_set(j, '$.*', 1) from t`)
+	c.Assert(err, NotNil)
+	err = tk.QueryToErr(`select a, json_object('a', 1, null, 2) from t`)
+	c.Assert(err.Error(), Equals, "[expression:3158]JSON documents may not contain NULL member names.")
+	_, err = tk.Exec(`select json_object('a')`)
+	c.Assert(err, NotNil)
+
+	// Comparison and sorting follow the JSON type precedences.
+	tk.MustQuery("select a from t order by j, a").Check(testkit.Rows("5", "4", "6", "3", "1", "2"))
+	tk.MustQuery("select a from t where j > cast('1' as json) order by a").Check(testkit.Rows("1", "2", "3", "6"))
+	tk.MustQuery(`select cast('[1, 2]' as json) = cast('[1, 2]' as json), cast('1' as json) = 1, cast('1.0' as json) = cast('1' as json), cast('"a"' as json) = 'a', cast('{"a": 1}' as json) < cast('[1]' as json)`).
+		Check(testkit.Rows("1 1 1 1 1"))
+	tk.MustQuery("select max(j), min(j), count(j) from t").Check(testkit.Rows("[1, 2, 3] null 5"))
+	tk.MustQuery("select a from t where j is null").Check(testkit.Rows("5"))
+	tk.MustQuery("select a from t where j in (cast('[1, 2, 3]' as json), cast('1.5' as json)) order by a").Check(testkit.Rows("2", "6"))
+
+	// Casts between JSON and other types.
+	tk.MustQuery(`select cast(j->'$.n' as signed), cast(j->'$.name' as char), cast(cast('2020-01-02' as date) as json), cast(cast(1 as unsigned) as json), cast(true as json) from t where a = 1`).
+		Check(testkit.Rows(`4 "a" "2020-01-02" 1 true`))
+	// Decimals are kept in JSON values instead of being converted to doubles.
+	tk.MustQuery(`select cast(cast(1.5 as decimal(5,2)) as json), json_type(cast(cast(1.5 as decimal(5,2)) as json)), json_extract(json_array(cast(1.5 as decimal(5,2))), '$[0]')`).
+		Check(testkit.Rows("1.50 DECIMAL 1.50"))
+	tk.MustQuery(`select cast(cast(1.5 as decimal(5,2)) as json) = cast('1.5' as json), cast(cast(12345678901234567890.5 as decimal(30,1)) as json) > cast(12345678901234567890 as json)`).
+		Check(testkit.Rows("1 1"))
+	tk.MustQuery(`select cast(cast(2.5 as decimal(5,2)) as json) + 0, cast(cast(cast(2.5 as decimal(5,2)) as json) as signed), cast(cast(cast(2.55 as decimal(5,2)) as json) as decimal(5,2))`).
+		Check(testkit.Rows("2.5 3 2.55"))
+	tk.MustQuery(`select a from t where j = cast(cast(1.5 as decimal(5,2)) as json)`).Check(testkit.Rows("6"))
+	tk.MustQuery(`select coalesce(j, cast('[]' as json)), ifnull(j, json_array()), if(a = 5, j, json_object()) from t where a = 5`).Check(testkit.Rows("[] [] <nil>"))
+	_, err = tk.Exec("create table t1(j json, key(j))")
+	c.Assert(err, NotNil)
+}
+
 type testSuite2 struct {
 	*baseTestSuite
 }
@@ -1216,7 +1558,11 @@ func (s *testSuite6) TearDownTest(c *C) {
 	r := tk.MustQuery("show full tables")
 	for _, tb := range r.Rows() {
 		tableName := tb[0]
-		tk.MustExec(fmt.Sprintf("drop table %v", tableName))
+		if tb[1] == "VIEW" {
+			tk.MustExec(fmt.Sprintf("drop view %v", tableName))
+		} else {
+			tk.MustExec(fmt.Sprintf("drop table %v", tableName))
+		}
 	}
 }
 
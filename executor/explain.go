@@ -25,9 +25,10 @@ import (
 type ExplainExec struct {
 	baseExecutor
 
-	explain *core.Explain
-	rows    [][]string
-	cursor  int
+	explain     *core.Explain
+	analyzeExec Executor
+	rows        [][]string
+	cursor      int
 }
 
 // Open implements the Executor Open interface.
@@ -67,8 +68,35 @@ func (e *ExplainExec) Next(ctx context.Context, req *chunk.Chunk) error {
 }
 
 func (e *ExplainExec) generateExplainInfo(ctx context.Context) ([][]string, error) {
+	if e.analyzeExec != nil {
+		if err := e.executeAnalyzeExec(ctx); err != nil {
+			return nil, err
+		}
+	}
 	if err := e.explain.RenderResult(); err != nil {
 		return nil, err
 	}
 	return e.explain.Rows, nil
 }
+
+// executeAnalyzeExec runs the explained statement to the end, so that the
+// runtime stats of its executors are collected for EXPLAIN ANALYZE.
+func (e *ExplainExec) executeAnalyzeExec(ctx context.Context) (err error) {
+	if err = e.analyzeExec.Open(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := e.analyzeExec.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	chk := newFirstChunk(e.analyzeExec)
+	for {
+		if err = Next(ctx, e.analyzeExec, chk); err != nil {
+			return err
+		}
+		if chk.NumRows() == 0 {
+			return nil
+		}
+	}
+}
@@ -50,6 +50,9 @@ type hashContext struct {
 	buf       []byte
 	hashVals  []hash.Hash64
 	hasNull   []bool
+	// isNullEQ indicates whether each key column is compared by `<=>`,
+	// rows with NULL values in such columns can still be matched.
+	isNullEQ []bool
 }
 
 func (hc *hashContext) initHash(rows int) {
@@ -72,7 +75,6 @@ func (hc *hashContext) initHash(rows int) {
 }
 
 // hashRowContainer handles the rows and the hash map of a table.
-// TODO: support spilling out to disk when memory is limited.
 type hashRowContainer struct {
 	records   *chunk.List
 	hashTable *rowHashMap
@@ -151,8 +153,9 @@ func (c *hashRowContainer) PutChunk(chk *chunk.Chunk) error {
 	c.hCtx.initHash(numRows)
 
 	hCtx := c.hCtx
-	for _, colIdx := range c.hCtx.keyColIdx {
-		err := codec.HashChunkColumns(c.sc, hCtx.hashVals, chk, hCtx.allTypes[colIdx], colIdx, hCtx.buf, hCtx.hasNull)
+	for keyIdx, colIdx := range c.hCtx.keyColIdx {
+		ignoreNull := len(hCtx.isNullEQ) > keyIdx && hCtx.isNullEQ[keyIdx]
+		err := codec.HashChunkSelected(c.sc, hCtx.hashVals, chk, hCtx.allTypes[colIdx], colIdx, hCtx.buf, hCtx.hasNull, nil, ignoreNull)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -173,6 +176,81 @@ func (c hashRowContainer) Len() int {
 	return c.hashTable.Len()
 }
 
+// hashPartitioner partitions rows by the hash values of their join keys and
+// writes every partition to disk, it's used by the grace hash join when the
+// build side can't be held in memory. Both sides of the join are partitioned
+// by the same number of partitions, so a pair of partitions can be joined
+// independently.
+type hashPartitioner struct {
+	sc         *stmtctx.StatementContext
+	hCtx       *hashContext
+	partitions []*chunk.ListInDisk
+	// buffers collect the rows of every partition until they are full.
+	buffers []*chunk.Chunk
+}
+
+func newHashPartitioner(sc *stmtctx.StatementContext, hCtx *hashContext, partitionNum, maxChunkSize int) *hashPartitioner {
+	p := &hashPartitioner{
+		sc:         sc,
+		hCtx:       hCtx,
+		partitions: make([]*chunk.ListInDisk, partitionNum),
+		buffers:    make([]*chunk.Chunk, partitionNum),
+	}
+	for i := range p.partitions {
+		p.partitions[i] = chunk.NewListInDisk(hCtx.allTypes)
+		p.buffers[i] = chunk.New(hCtx.allTypes, maxChunkSize, maxChunkSize)
+	}
+	return p
+}
+
+// Add appends the rows of chk to their partitions. Rows with NULL join keys
+// can never be matched, they are dropped unless keepNull is set, in which
+// case they are put into the first partition.
+func (p *hashPartitioner) Add(chk *chunk.Chunk, keepNull bool) error {
+	hCtx := p.hCtx
+	hCtx.initHash(chk.NumRows())
+	for keyIdx, colIdx := range hCtx.keyColIdx {
+		ignoreNull := len(hCtx.isNullEQ) > keyIdx && hCtx.isNullEQ[keyIdx]
+		err := codec.HashChunkSelected(p.sc, hCtx.hashVals, chk, hCtx.allTypes[colIdx], colIdx, hCtx.buf, hCtx.hasNull, nil, ignoreNull)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for i := 0; i < chk.NumRows(); i++ {
+		partIdx := 0
+		if hCtx.hasNull[i] {
+			if !keepNull {
+				continue
+			}
+		} else {
+			partIdx = int(hCtx.hashVals[i].Sum64() % uint64(len(p.partitions)))
+		}
+		buf := p.buffers[partIdx]
+		buf.AppendRow(chk.GetRow(i))
+		if buf.IsFull() {
+			if err := p.partitions[partIdx].Add(buf); err != nil {
+				return err
+			}
+			buf.Reset()
+		}
+	}
+	return nil
+}
+
+// Finish writes the rows left in the buffers to disk.
+func (p *hashPartitioner) Finish() error {
+	for i, buf := range p.buffers {
+		if buf.NumRows() == 0 {
+			continue
+		}
+		if err := p.partitions[i].Add(buf); err != nil {
+			return err
+		}
+	}
+	p.buffers = nil
+	return nil
+}
+
 const (
 	initialEntrySliceLen = 64
 	maxEntrySliceLen     = 8 * 1024
@@ -16,16 +16,27 @@ package executor
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/terror"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/memory"
 )
 
-var _ Executor = &HashJoinExec{}
+var (
+	_ Executor = &HashJoinExec{}
+	_ Executor = &NestedLoopApplyExec{}
+)
+
+// hashJoinPartitionNum is the number of partitions the rows of both sides
+// are split into once the build side of HashJoinExec is spilled to disk.
+const hashJoinPartitionNum = 16
 
 // HashJoinExec implements the hash join algorithm.
 type HashJoinExec struct {
@@ -37,6 +48,8 @@ type HashJoinExec struct {
 	outerSideFilter   expression.CNFExprs
 	outerKeys         []*expression.Column
 	innerKeys         []*expression.Column
+	// isNullEQ is used for cases like Except statement where null key should be matched with null key.
+	isNullEQ []bool
 
 	// concurrency is the number of partition, build and join workers.
 	concurrency  uint
@@ -56,6 +69,23 @@ type HashJoinExec struct {
 	joinChkResourceCh  []chan *chunk.Chunk
 	joinResultCh       chan *hashjoinWorkerResult
 
+	// memTracker tracks the memory used by the build side rows.
+	memTracker *memory.Tracker
+	// spillAction is registered on the memory tracker of the statement, it
+	// asks HashJoinExec to spill the build side to disk when the quota is
+	// exceeded.
+	spillAction *spillDiskAction
+	// innerPartitioner partitions the build side rows to disk once the
+	// build side is spilled, innerPartitions and outerPartitions are the
+	// partitions of both sides, they are joined pair by pair.
+	innerPartitioner *hashPartitioner
+	innerPartitions  []*chunk.ListInDisk
+	outerPartitions  []*chunk.ListInDisk
+	// partitionIdx is the index of the partition pair being joined.
+	partitionIdx int
+	// outerChkIdx is the index of the next chunk read from the outer partition.
+	outerChkIdx int
+
 	prepared bool
 }
 
@@ -102,7 +132,34 @@ func (e *HashJoinExec) Close() error {
 		e.outerChkResourceCh = nil
 		e.joinChkResourceCh = nil
 	}
-	err := e.baseExecutor.Close()
+	err := e.closePartitions()
+	e.rowContainer = nil
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.memTracker.BytesConsumed())
+		e.memTracker.Detach()
+		e.memTracker = nil
+	}
+	if e.spillAction != nil {
+		atomic.StoreUint32(&e.spillAction.fetching, 0)
+		e.spillAction = nil
+	}
+	if closeErr := e.baseExecutor.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (e *HashJoinExec) closePartitions() (err error) {
+	for _, partitions := range [][]*chunk.ListInDisk{e.innerPartitions, e.outerPartitions} {
+		for _, partition := range partitions {
+			if closeErr := partition.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+	e.innerPartitioner = nil
+	e.innerPartitions = nil
+	e.outerPartitions = nil
 	return err
 }
 
@@ -115,6 +172,12 @@ func (e *HashJoinExec) Open(ctx context.Context) error {
 	e.prepared = false
 	e.closeCh = make(chan struct{})
 	e.joinWorkerWaitGroup = sync.WaitGroup{}
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.spillAction = &spillDiskAction{}
+	if stmtTracker := e.ctx.GetSessionVars().StmtCtx.MemTracker; stmtTracker != nil {
+		e.memTracker.AttachTo(stmtTracker)
+		stmtTracker.FallbackOldAndSetNewAction(e.spillAction)
+	}
 	return nil
 }
 
@@ -122,38 +185,193 @@ func (e *HashJoinExec) Open(ctx context.Context) error {
 // hash join constructs the result following these steps:
 // step 1. fetch data from build side child and build a hash table;
 // step 2. fetch data from outer child in a background goroutine and outer the hash table in multiple join workers.
+// If the build side is spilled to disk in step 1, the rows of both sides are
+// partitioned by the hash values of join keys, then step 2 is done for every
+// pair of partitions in turn.
 func (e *HashJoinExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	if !e.prepared {
 		err := e.fetchAndBuildHashTable(ctx)
 		if err != nil {
 			return err
 		}
-		e.fetchAndProbeHashTable(ctx)
+		if e.innerPartitions != nil {
+			if err = e.partitionOuterSide(ctx); err != nil {
+				return err
+			}
+			e.partitionIdx = -1
+		} else {
+			e.fetchAndProbeHashTable(ctx)
+		}
 		e.prepared = true
 	}
 	req.Reset()
 
-	result, ok := <-e.joinResultCh
-	if !ok {
-		return nil
+	for {
+		if e.joinResultCh != nil {
+			result, ok := <-e.joinResultCh
+			if ok {
+				if result.err != nil {
+					return result.err
+				}
+				req.SwapColumns(result.chk)
+				result.src <- result.chk
+				return nil
+			}
+		}
+		if e.innerPartitions == nil {
+			return nil
+		}
+		ok, err := e.joinNextPartition(ctx)
+		if err != nil || !ok {
+			return err
+		}
 	}
-	if result.err != nil {
-		return result.err
+}
+
+func (e *HashJoinExec) newHashContext(allTypes []*types.FieldType, keys []*expression.Column) *hashContext {
+	keyColIdx := make([]int, len(keys))
+	for i := range keys {
+		keyColIdx[i] = keys[i].Index
+	}
+	return &hashContext{
+		allTypes:  allTypes,
+		keyColIdx: keyColIdx,
+		isNullEQ:  e.isNullEQ,
 	}
-	req.SwapColumns(result.chk)
-	result.src <- result.chk
-	return nil
 }
 
 func (e *HashJoinExec) fetchAndBuildHashTable(ctx context.Context) error {
-	// TODO: Implementing the building hash table stage.
+	allTypes := retTypes(e.innerSideExec)
+	hCtx := e.newHashContext(allTypes, e.innerKeys)
+	initList := chunk.NewList(allTypes, e.initCap, e.maxChunkSize)
+	e.rowContainer = newHashRowContainer(e.ctx, int(e.innerSideEstCount), hCtx, initList)
+	atomic.StoreUint32(&e.spillAction.fetching, 1)
+	defer atomic.StoreUint32(&e.spillAction.fetching, 0)
+	for {
+		chk := newFirstChunk(e.innerSideExec)
+		err := Next(ctx, e.innerSideExec, chk)
+		if err != nil {
+			return err
+		}
+		if chk.NumRows() == 0 {
+			break
+		}
+		if e.innerPartitioner != nil {
+			if err = e.innerPartitioner.Add(chk, false); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = e.rowContainer.PutChunk(chk); err != nil {
+			return err
+		}
+		e.memTracker.Consume(chk.MemoryUsage())
+		if atomic.CompareAndSwapUint32(&e.spillAction.triggered, 1, 0) {
+			if err = e.spillBuildSide(); err != nil {
+				return err
+			}
+		}
+	}
+	if e.innerPartitioner != nil {
+		return e.innerPartitioner.Finish()
+	}
+	return nil
+}
+
+// spillBuildSide moves the build side rows in memory to the partitions on
+// disk, the rows fetched later are partitioned to disk directly.
+func (e *HashJoinExec) spillBuildSide() error {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	hCtx := e.newHashContext(retTypes(e.innerSideExec), e.innerKeys)
+	e.innerPartitioner = newHashPartitioner(sc, hCtx, hashJoinPartitionNum, e.maxChunkSize)
+	e.innerPartitions = e.innerPartitioner.partitions
+	records := e.rowContainer.records
+	for i := 0; i < records.NumChunks(); i++ {
+		if err := e.innerPartitioner.Add(records.GetChunk(i), false); err != nil {
+			return err
+		}
+	}
+	e.rowContainer = nil
+	e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	if e.runtimeStats != nil {
+		e.runtimeStats.RecordSpill()
+	}
+	return nil
+}
+
+// partitionOuterSide fetches all the outer side rows and partitions them to
+// disk in the same way as the build side.
+func (e *HashJoinExec) partitionOuterSide(ctx context.Context) error {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	hCtx := e.newHashContext(retTypes(e.outerSideExec), e.outerKeys)
+	partitioner := newHashPartitioner(sc, hCtx, len(e.innerPartitions), e.maxChunkSize)
+	e.outerPartitions = partitioner.partitions
+	chk := newFirstChunk(e.outerSideExec)
+	for {
+		err := Next(ctx, e.outerSideExec, chk)
+		if err != nil {
+			return err
+		}
+		if chk.NumRows() == 0 {
+			return partitioner.Finish()
+		}
+		// Outer side rows with NULL join keys still need to be output by
+		// outer joins, keep them in the first partition.
+		if err = partitioner.Add(chk, true); err != nil {
+			return err
+		}
+	}
+}
 
-	// In this stage, you'll read the data from the inner side executor of the join operator and
-	// then use its data to build hash table.
+// joinNextPartition builds the hash table of the next partition pair which
+// has outer side rows and starts to probe it. It returns false when all the
+// partition pairs have been joined.
+func (e *HashJoinExec) joinNextPartition(ctx context.Context) (bool, error) {
+	if e.partitionIdx >= 0 {
+		// The previous partition pair has been joined, release its files.
+		if err := e.innerPartitions[e.partitionIdx].Close(); err != nil {
+			return false, err
+		}
+		if err := e.outerPartitions[e.partitionIdx].Close(); err != nil {
+			return false, err
+		}
+	}
+	for e.partitionIdx+1 < len(e.outerPartitions) {
+		e.partitionIdx++
+		if e.outerPartitions[e.partitionIdx].Len() == 0 {
+			continue
+		}
+		if err := e.buildPartitionHashTable(); err != nil {
+			return false, err
+		}
+		e.fetchAndProbeHashTable(ctx)
+		return true, nil
+	}
+	return false, nil
+}
 
-	// You'll need to store the hash table in `e.rowContainer`
-	// and you can call `newHashRowContainer` in `executor/hash_table.go` to build it.
-	// In this stage you can only assign value for `e.rowContainer` without changing any value of the `HashJoinExec`.
+// buildPartitionHashTable builds the hash table from the build side rows of
+// the partition being joined.
+func (e *HashJoinExec) buildPartitionHashTable() error {
+	e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	allTypes := retTypes(e.innerSideExec)
+	hCtx := e.newHashContext(allTypes, e.innerKeys)
+	e.rowContainer = newHashRowContainer(e.ctx, 0, hCtx, chunk.NewList(allTypes, e.initCap, e.maxChunkSize))
+	e.outerChkIdx = 0
+	partition := e.innerPartitions[e.partitionIdx]
+	for i := 0; i < partition.NumChunks(); i++ {
+		if err := checkKilled(e.ctx); err != nil {
+			return err
+		}
+		chk, err := partition.GetChunk(i)
+		if err != nil {
+			return err
+		}
+		if err = e.rowContainer.PutChunk(chk); err != nil {
+			return err
+		}
+		e.memTracker.Consume(chk.MemoryUsage())
+	}
 	return nil
 }
 
@@ -204,7 +422,7 @@ func (e *HashJoinExec) fetchOuterSideChunks(ctx context.Context) {
 			}
 		}
 		outerSideResult := outerSideResource.chk
-		err := Next(ctx, e.outerSideExec, outerSideResult)
+		err := e.fetchOuterSideChunk(ctx, outerSideResult)
 		if err != nil {
 			e.joinResultCh <- &hashjoinWorkerResult{
 				err: err,
@@ -220,6 +438,29 @@ func (e *HashJoinExec) fetchOuterSideChunks(ctx context.Context) {
 	}
 }
 
+// fetchOuterSideChunk fetches the next chunk of the outer side, it reads the
+// outer partition being joined once the build side is spilled.
+func (e *HashJoinExec) fetchOuterSideChunk(ctx context.Context, chk *chunk.Chunk) error {
+	if e.outerPartitions == nil {
+		return Next(ctx, e.outerSideExec, chk)
+	}
+	chk.Reset()
+	if err := checkKilled(e.ctx); err != nil {
+		return err
+	}
+	partition := e.outerPartitions[e.partitionIdx]
+	if e.outerChkIdx >= partition.NumChunks() {
+		return nil
+	}
+	src, err := partition.GetChunk(e.outerChkIdx)
+	if err != nil {
+		return err
+	}
+	e.outerChkIdx++
+	chk.Append(src, 0, src.NumRows())
+	return nil
+}
+
 func (e *HashJoinExec) fetchAndProbeHashTable(ctx context.Context) {
 	e.initializeForOuter()
 	e.joinWorkerWaitGroup.Add(1)
@@ -241,15 +482,46 @@ func (e *HashJoinExec) fetchAndProbeHashTable(ctx context.Context) {
 }
 
 func (e *HashJoinExec) runJoinWorker(workerID uint, outerKeyColIdx []int) {
-	// TODO: Implement the worker of probing stage.
-
-	// In this method, you read the data from the channel e.outerResultChs[workerID].
-	// Then use `e.join2Chunk` method get the joined result `joinResult`,
-	// and put the `joinResult` into the channel `e.joinResultCh`.
+	var (
+		outerSideResult *chunk.Chunk
+		selected        = make([]bool, 0, chunk.InitialCapacity)
+	)
+	ok, joinResult := e.getNewJoinResult(workerID)
+	if !ok {
+		return
+	}
 
-	// You may pay attention to:
-	// 
-	// - e.closeCh, this is a channel tells that the join can be terminated as soon as possible.
+	// Read and filter outerSideResult, and join the outerSideResult with the inner side rows.
+	emptyOuterSideResult := &outerChkResource{
+		dest: e.outerResultChs[workerID],
+	}
+	hCtx := &hashContext{
+		allTypes:  retTypes(e.outerSideExec),
+		keyColIdx: outerKeyColIdx,
+		isNullEQ:  e.isNullEQ,
+	}
+	for ok := true; ok; {
+		select {
+		case <-e.closeCh:
+			return
+		case outerSideResult, ok = <-e.outerResultChs[workerID]:
+		}
+		if !ok {
+			break
+		}
+		ok, joinResult = e.join2Chunk(workerID, outerSideResult, hCtx, joinResult, selected)
+		if !ok {
+			break
+		}
+		outerSideResult.Reset()
+		emptyOuterSideResult.chk = outerSideResult
+		e.outerChkResourceCh <- emptyOuterSideResult
+	}
+	if joinResult == nil {
+		return
+	} else if joinResult.err != nil || (joinResult.chk != nil && joinResult.chk.NumRows() > 0) {
+		e.joinResultCh <- joinResult
+	}
 }
 
 func (e *HashJoinExec) getNewJoinResult(workerID uint) (bool, *hashjoinWorkerResult) {
@@ -295,35 +567,41 @@ func (e *HashJoinExec) joinMatchedOuterSideRow2Chunk(workerID uint, outerKey uin
 		return false, joinResult
 	}
 	if len(buildSideRows) == 0 {
-		e.joiners[workerID].onMissMatch(outerSideRow, joinResult.chk)
+		e.joiners[workerID].onMissMatch(false, outerSideRow, joinResult.chk)
 		return true, joinResult
 	}
 	iter := chunk.NewIterator4Slice(buildSideRows)
-	hasMatch := false
+	hasMatch, hasNull := false, false
 	for iter.Begin(); iter.Current() != iter.End(); {
-		matched, _, err := e.joiners[workerID].tryToMatchInners(outerSideRow, iter, joinResult.chk)
+		matched, isNull, err := e.joiners[workerID].tryToMatchInners(outerSideRow, iter, joinResult.chk)
 		if err != nil {
 			joinResult.err = err
 			return false, joinResult
 		}
 		hasMatch = hasMatch || matched
+		hasNull = hasNull || isNull
 
 		if joinResult.chk.IsFull() {
 			e.joinResultCh <- joinResult
-			ok, joinResult := e.getNewJoinResult(workerID)
+			var ok bool
+			ok, joinResult = e.getNewJoinResult(workerID)
 			if !ok {
 				return false, joinResult
 			}
 		}
 	}
 	if !hasMatch {
-		e.joiners[workerID].onMissMatch(outerSideRow, joinResult.chk)
+		e.joiners[workerID].onMissMatch(hasNull, outerSideRow, joinResult.chk)
 	}
 	return true, joinResult
 }
 
 func (e *HashJoinExec) join2Chunk(workerID uint, outerSideChk *chunk.Chunk, hCtx *hashContext, joinResult *hashjoinWorkerResult,
 	selected []bool) (ok bool, _ *hashjoinWorkerResult) {
+	if err := checkKilled(e.ctx); err != nil {
+		joinResult.err = err
+		return false, joinResult
+	}
 	var err error
 	selected, err = expression.VectorizedFilter(e.ctx, e.outerSideFilter, chunk.NewIterator4Chunk(outerSideChk), selected)
 	if err != nil {
@@ -332,8 +610,9 @@ func (e *HashJoinExec) join2Chunk(workerID uint, outerSideChk *chunk.Chunk, hCtx
 	}
 
 	hCtx.initHash(outerSideChk.NumRows())
-	for _, i := range hCtx.keyColIdx {
-		err = codec.HashChunkSelected(e.rowContainer.sc, hCtx.hashVals, outerSideChk, hCtx.allTypes[i], i, hCtx.buf, hCtx.hasNull, selected)
+	for keyIdx, i := range hCtx.keyColIdx {
+		ignoreNull := len(hCtx.isNullEQ) > keyIdx && hCtx.isNullEQ[keyIdx]
+		err = codec.HashChunkSelected(e.rowContainer.sc, hCtx.hashVals, outerSideChk, hCtx.allTypes[i], i, hCtx.buf, hCtx.hasNull, selected, ignoreNull)
 		if err != nil {
 			joinResult.err = err
 			return false, joinResult
@@ -342,7 +621,7 @@ func (e *HashJoinExec) join2Chunk(workerID uint, outerSideChk *chunk.Chunk, hCtx
 
 	for i := range selected {
 		if !selected[i] || hCtx.hasNull[i] { // process unmatched outer side rows
-			e.joiners[workerID].onMissMatch(outerSideChk.GetRow(i), joinResult.chk)
+			e.joiners[workerID].onMissMatch(false, outerSideChk.GetRow(i), joinResult.chk)
 		} else { // process matched outer side rows
 			outerKey, outerRow := hCtx.hashVals[i].Sum64(), outerSideChk.GetRow(i)
 			ok, joinResult = e.joinMatchedOuterSideRow2Chunk(workerID, outerKey, outerRow, hCtx, joinResult)
@@ -360,3 +639,148 @@ func (e *HashJoinExec) join2Chunk(workerID uint, outerSideChk *chunk.Chunk, hCtx
 	}
 	return true, joinResult
 }
+
+// NestedLoopApplyExec is the executor for apply.
+type NestedLoopApplyExec struct {
+	baseExecutor
+
+	innerRows   []chunk.Row
+	cursor      int
+	innerExec   Executor
+	outerExec   Executor
+	innerFilter expression.CNFExprs
+	outerFilter expression.CNFExprs
+	outer       bool
+
+	joiner joiner
+
+	outerSchema []*expression.CorrelatedColumn
+
+	outerChunk       *chunk.Chunk
+	outerChunkCursor int
+	outerSelected    []bool
+	innerList        *chunk.List
+	innerChunk       *chunk.Chunk
+	innerSelected    []bool
+	innerIter        chunk.Iterator
+	outerRow         *chunk.Row
+	hasMatch         bool
+	hasNull          bool
+}
+
+// Close implements the Executor interface.
+func (e *NestedLoopApplyExec) Close() error {
+	e.innerRows = nil
+	return e.outerExec.Close()
+}
+
+// Open implements the Executor interface.
+func (e *NestedLoopApplyExec) Open(ctx context.Context) error {
+	err := e.outerExec.Open(ctx)
+	if err != nil {
+		return err
+	}
+	e.cursor = 0
+	e.innerRows = e.innerRows[:0]
+	e.outerChunk = newFirstChunk(e.outerExec)
+	e.innerChunk = newFirstChunk(e.innerExec)
+	e.innerList = chunk.NewList(retTypes(e.innerExec), e.initCap, e.maxChunkSize)
+	return nil
+}
+
+func (e *NestedLoopApplyExec) fetchSelectedOuterRow(ctx context.Context, chk *chunk.Chunk) (*chunk.Row, error) {
+	outerIter := chunk.NewIterator4Chunk(e.outerChunk)
+	for {
+		if e.outerChunkCursor >= e.outerChunk.NumRows() {
+			err := Next(ctx, e.outerExec, e.outerChunk)
+			if err != nil {
+				return nil, err
+			}
+			if e.outerChunk.NumRows() == 0 {
+				return nil, nil
+			}
+			e.outerSelected, err = expression.VectorizedFilter(e.ctx, e.outerFilter, outerIter, e.outerSelected)
+			if err != nil {
+				return nil, err
+			}
+			e.outerChunkCursor = 0
+		}
+		outerRow := e.outerChunk.GetRow(e.outerChunkCursor)
+		selected := e.outerSelected[e.outerChunkCursor]
+		e.outerChunkCursor++
+		if selected {
+			return &outerRow, nil
+		} else if e.outer {
+			e.joiner.onMissMatch(false, outerRow, chk)
+			if chk.IsFull() {
+				return nil, nil
+			}
+		}
+	}
+}
+
+// fetchAllInners reads all data from the inner table and stores them in a List.
+func (e *NestedLoopApplyExec) fetchAllInners(ctx context.Context) error {
+	err := e.innerExec.Open(ctx)
+	defer terror.Call(e.innerExec.Close)
+	if err != nil {
+		return err
+	}
+	e.innerList.Reset()
+	innerIter := chunk.NewIterator4Chunk(e.innerChunk)
+	for {
+		err := Next(ctx, e.innerExec, e.innerChunk)
+		if err != nil {
+			return err
+		}
+		if e.innerChunk.NumRows() == 0 {
+			return nil
+		}
+
+		e.innerSelected, err = expression.VectorizedFilter(e.ctx, e.innerFilter, innerIter, e.innerSelected)
+		if err != nil {
+			return err
+		}
+		for row := innerIter.Begin(); row != innerIter.End(); row = innerIter.Next() {
+			if e.innerSelected[row.Idx()] {
+				e.innerList.AppendRow(row)
+			}
+		}
+	}
+}
+
+// Next implements the Executor interface.
+func (e *NestedLoopApplyExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
+	req.Reset()
+	for {
+		if e.innerIter == nil || e.innerIter.Current() == e.innerIter.End() {
+			if e.outerRow != nil && !e.hasMatch {
+				e.joiner.onMissMatch(e.hasNull, *e.outerRow, req)
+			}
+			e.outerRow, err = e.fetchSelectedOuterRow(ctx, req)
+			if e.outerRow == nil || err != nil {
+				return err
+			}
+			e.hasMatch = false
+			e.hasNull = false
+
+			for _, col := range e.outerSchema {
+				*col.Data = e.outerRow.GetDatum(col.Index, col.RetType)
+			}
+			err = e.fetchAllInners(ctx)
+			if err != nil {
+				return err
+			}
+			e.innerIter = chunk.NewIterator4List(e.innerList)
+			e.innerIter.Begin()
+		}
+
+		matched, isNull, err := e.joiner.tryToMatchInners(*e.outerRow, e.innerIter, req)
+		e.hasMatch = e.hasMatch || matched
+		e.hasNull = e.hasNull || isNull
+
+		if err != nil || req.IsFull() {
+			return err
+		}
+	}
+}
@@ -15,6 +15,8 @@ package executor_test
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -412,3 +414,239 @@ func (s *testSuiteJoin1) TestInjectProjOnTopN(c *C) {
 		"2",
 	))
 }
+
+func (s *testSuiteJoin1) TestSubquery(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (c int, d int)")
+	tk.MustExec("insert t values (1, 1)")
+	tk.MustExec("insert t values (2, 2)")
+	tk.MustExec("insert t values (3, 4)")
+	tk.MustExec("commit")
+
+	result := tk.MustQuery("select * from t where exists(select * from t k where t.c = k.c having sum(c) = 1)")
+	result.Check(testkit.Rows("1 1"))
+	result = tk.MustQuery("select * from t where exists(select k.c, k.d from t k, t p where t.c = k.d)")
+	result.Check(testkit.Rows("1 1", "2 2"))
+	result = tk.MustQuery("select 1 = (select count(*) from t where t.c = k.d) from t k")
+	result.Check(testkit.Rows("1", "1", "0"))
+	result = tk.MustQuery("select 1 = (select count(*) from t where exists( select * from t m where t.c = k.d)) from t k")
+	result.Sort().Check(testkit.Rows("0", "1", "1"))
+	result = tk.MustQuery("select t.c = any (select count(*) from t) from t")
+	result.Sort().Check(testkit.Rows("0", "0", "1"))
+	result = tk.MustQuery("select * from t where t.c = all (select count(*) from t)")
+	result.Check(testkit.Rows("3 4"))
+	result = tk.MustQuery("select t.c from t where (t.c) < all (select count(*) from t)")
+	result.Check(testkit.Rows("1", "2"))
+	result = tk.MustQuery("select t.c from t where (t.c, t.d) = any (select * from t)")
+	result.Check(testkit.Rows("1", "2", "3"))
+	result = tk.MustQuery("select t.c from t where (t.c, t.d) != all (select * from t)")
+	result.Check(testkit.Rows())
+	result = tk.MustQuery("select (select count(*) from t where t.c = k.d) from t k")
+	result.Check(testkit.Rows("1", "1", "0"))
+	result = tk.MustQuery("select t.c from t where t.c > all (select d from t where d < 3)")
+	result.Check(testkit.Rows("3"))
+	result = tk.MustQuery("select t.c from t where t.c != any (select d from t where d < 3)")
+	result.Check(testkit.Rows("1", "2", "3"))
+	result = tk.MustQuery("select t.c from t where t.c = all (select d from t where d = 2)")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select (select c from t where c = k.c) from t k")
+	result.Check(testkit.Rows("1", "2", "3"))
+	result = tk.MustQuery("select (select c from t where c > 5)")
+	result.Check(testkit.Rows("<nil>"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, v int)")
+	tk.MustExec("insert into t values(1, 1), (2, 2), (3, 3)")
+	result = tk.MustQuery("select * from t where exists(select * from t k where t.id = k.id)")
+	result.Check(testkit.Rows("1 1", "2 2", "3 3"))
+	result = tk.MustQuery("select * from t where not exists(select * from t k where t.id = k.id + 1)")
+	result.Check(testkit.Rows("1 1"))
+	result = tk.MustQuery("select (select 1 from dual where 0)")
+	result.Check(testkit.Rows("<nil>"))
+	_, err := tk.Exec("select (select v from t)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[executor:1242]Subquery returns more than 1 row")
+	_, err = tk.Exec("select * from t where id = (select v from t)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[executor:1242]Subquery returns more than 1 row")
+}
+
+func (s *testSuiteJoin1) TestInSubquery(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert t values (1, 1), (2, 1)")
+	result := tk.MustQuery("select m1.a from t as m1 where m1.a in (select m2.b from t as m2)")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select m1.a from t as m1 where (3, m1.b) not in (select * from t as m2)")
+	result.Sort().Check(testkit.Rows("1", "2"))
+	result = tk.MustQuery("select m1.a from t as m1 where m1.a in (select m2.b+1 from t as m2)")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select m1.a from t as m1 where m1.a in (select m2.b from t as m2 where m2.a = m1.a)")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select m1.a from t as m1 where m1.a not in (select m2.b from t as m2)")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select m1.a, m1.a in (select m2.a from t as m2 where m2.b = 2) from t as m1")
+	result.Check(testkit.Rows("1 0", "2 0"))
+
+	tk.MustExec("drop table if exists s")
+	tk.MustExec("create table s (a int, c int)")
+	tk.MustExec("insert s values (1, 3), (1, 4), (3, 5)")
+	// The columns of the subquery can't be referenced from the outer query.
+	tk.MustQuery("select a from t where a in (select a from s)").Check(testkit.Rows("1"))
+	tk.MustQuery("select * from t where a in (select a from s)").Check(testkit.Rows("1 1"))
+	_, err := tk.Exec("select c from t where a in (select a from s)")
+	c.Assert(err.Error(), Equals, "[planner:1054]Unknown column 'c' in 'field list'")
+	tk.MustQuery("with w as (select a from t where a in (select a from s)) select a from w").Check(testkit.Rows("1"))
+	tk.MustExec("prepare stmt from 'select a from t where a in (select a from s where c > ?)'")
+	tk.MustExec("set @c = 3")
+	tk.MustQuery("execute stmt using @c").Check(testkit.Rows("1"))
+	tk.MustExec("set @c = 4")
+	tk.MustQuery("execute stmt using @c").Check(testkit.Rows())
+
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int)")
+	tk.MustExec("create table t2(a int)")
+	tk.MustExec("insert into t1 values(1), (2), (null)")
+	tk.MustExec("insert into t2 values(1), (null)")
+	result = tk.MustQuery("select a in (select a from t2) from t1")
+	result.Check(testkit.Rows("1", "<nil>", "<nil>"))
+	result = tk.MustQuery("select a not in (select a from t2) from t1")
+	result.Check(testkit.Rows("0", "<nil>", "<nil>"))
+	result = tk.MustQuery("select * from t1 where a not in (select a from t2)")
+	result.Check(testkit.Rows())
+	result = tk.MustQuery("select * from t1 where a in (select a from t2 where a is not null)")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select * from t1 where a not in (select a from t2 where a is not null)")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select a, a not in (select a from t2 where t2.a < t1.a) from t1")
+	result.Check(testkit.Rows("1 1", "2 1", "<nil> 1"))
+	result = tk.MustQuery("select a, a in (select a from t2 where t2.a is not null) from t1")
+	result.Check(testkit.Rows("1 1", "2 0", "<nil> <nil>"))
+	result = tk.MustQuery("select a, (select count(*) from t2 where t2.a < t1.a) from t1")
+	result.Check(testkit.Rows("1 0", "2 1", "<nil> 0"))
+	result = tk.MustQuery("select a, (select max(a) from t2 where t2.a <= t1.a) from t1")
+	result.Check(testkit.Rows("1 1", "2 1", "<nil> <nil>"))
+	result = tk.MustQuery("select * from t1 where exists (select 1 from t2 where t2.a < t1.a)")
+	result.Check(testkit.Rows("2"))
+}
+
+func (s *testSuiteJoin1) TestHashJoinInDisk(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	values1 := make([]string, 0, 512)
+	values2 := make([]string, 0, 512)
+	for i := 0; i < 512; i++ {
+		values1 = append(values1, fmt.Sprintf("(%d, %d)", i%300, i))
+		if i%10 == 0 {
+			values2 = append(values2, fmt.Sprintf("(null, %d)", i))
+		} else {
+			values2 = append(values2, fmt.Sprintf("(%d, %d)", i%200, i))
+		}
+	}
+	values1 = append(values1, "(null, 512)")
+	tk.MustExec("insert into t1 values " + strings.Join(values1, ","))
+	tk.MustExec("insert into t2 values " + strings.Join(values2, ","))
+
+	sqls := []string{
+		"select /*+ hash_join(t1, t2) */ t1.b, t2.b from t1 join t2 on t1.a = t2.a",
+		"select /*+ hash_join(t1, t2) */ t1.b, t2.b from t1 left join t2 on t1.a = t2.a and t2.b > 100",
+		"select /*+ hash_join(t1, t2) */ t1.b, t2.b from t1 right join t2 on t1.a = t2.a where t1.b < 400 or t1.b is null",
+		"select b from t1 where a in (select a from t2)",
+		"select b from t1 where a not in (select a from t2 where a is not null)",
+		"select b, a in (select a from t2 where b > 300) from t1",
+	}
+	results := make([][][]interface{}, 0, len(sqls))
+	for _, sql := range sqls {
+		results = append(results, tk.MustQuery(sql).Sort().Rows())
+	}
+
+	// Every chunk of the build side exceeds the quota, so the build side is
+	// spilled to disk and the partitions are joined one by one.
+	tk.MustExec("set @@tidb_mem_quota_query = 1")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	for i, sql := range sqls {
+		tk.MustQuery(sql).Sort().Check(results[i])
+	}
+
+	rows := tk.MustQuery("explain analyze " + sqls[0]).Rows()
+	found := false
+	for _, row := range rows {
+		if strings.Contains(row[0].(string), "HashLeftJoin") || strings.Contains(row[0].(string), "HashRightJoin") {
+			found = true
+			c.Assert(row[4].(string), Matches, ".*spill:1")
+		}
+	}
+	c.Assert(found, IsTrue)
+}
+
+func (s *testSuiteJoin1) TestIndexLookupJoin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int, c int, key idx(a, b))")
+	tk.MustExec("create table t3(a int primary key, b int)")
+	values1 := make([]string, 0, 300)
+	values2 := make([]string, 0, 300)
+	values3 := make([]string, 0, 100)
+	for i := 0; i < 300; i++ {
+		if i%20 == 0 {
+			values1 = append(values1, fmt.Sprintf("(null, %d)", i))
+		} else {
+			values1 = append(values1, fmt.Sprintf("(%d, %d)", i%120, i%7))
+		}
+		values2 = append(values2, fmt.Sprintf("(%d, %d, %d)", i%90, i%5, i))
+		if i < 100 {
+			values3 = append(values3, fmt.Sprintf("(%d, %d)", i*2, i))
+		}
+	}
+	tk.MustExec("insert into t1 values " + strings.Join(values1, ","))
+	tk.MustExec("insert into t2 values " + strings.Join(values2, ","))
+	tk.MustExec("insert into t3 values " + strings.Join(values3, ","))
+
+	joinConds := []string{
+		"t1 join t2 on t1.a = t2.a",
+		"t1 join t2 on t1.a = t2.a and t1.b = t2.b",
+		"t1 join t2 on t1.a = t2.a and t2.b > 2",
+		"t1 join t2 on t1.a = t2.a and t1.b < t2.c",
+		"t1 left join t2 on t1.a = t2.a and t2.c > 100",
+		"t2 right join t1 on t1.a = t2.a and t1.b = t2.b",
+		"t1 join t3 on t1.a = t3.a",
+		"t1 left join t3 on t1.a = t3.a and t3.b > 10",
+	}
+	// The inner side looks up one outer row at a time to build many small tasks.
+	tk.MustExec("set @@tidb_index_join_batch_size = 1")
+	for _, cond := range joinConds {
+		inner := "t2"
+		if strings.Contains(cond, "t3") {
+			inner = "t3"
+		}
+		expected := tk.MustQuery("select /*+ hash_join(t1, " + inner + ") */ * from " + cond).Sort().Rows()
+		sql := "select /*+ inl_join(" + inner + ") */ * from " + cond
+		tk.MustQuery(sql).Sort().Check(expected)
+
+		found := false
+		for _, row := range tk.MustQuery("explain " + sql).Rows() {
+			if strings.Contains(row[0].(string), "IndexJoin") {
+				found = true
+			}
+		}
+		c.Assert(found, IsTrue, Commentf("sql: %s", sql))
+	}
+
+	// The index join keeps the order of the outer side.
+	tk.MustExec("set @@tidb_index_join_batch_size = default")
+	tk.MustQuery("select /*+ inl_join(t3) */ t3.a, t3.b from t3 join t1 on t1.a = t3.a where t1.b = 1 order by t1.a limit 3").
+		Check(testkit.Rows("6 3", "8 4", "14 7"))
+
+	// t1 has no index to look up, the hint can't be applied.
+	tk.MustQuery("select /*+ inl_join(t1) */ count(*) from t1 join t2 on t1.a = t2.a and t1.b = t2.b").Check(testkit.Rows("109"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1815 Optimizer Hint /*+ INL_JOIN(t1) */ or /*+ TIDB_INLJ(t1) */ is inapplicable"))
+}
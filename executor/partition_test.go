@@ -0,0 +1,238 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testSuite) TestRangePartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec(`create table t (a int, b int, index idx(b)) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30))`)
+	tk.MustExec("insert into t values (1, 1), (11, 11), (21, 21), (null, 0)")
+	tk.MustQuery("select * from t order by b").Check(testkit.Rows("<nil> 0", "1 1", "11 11", "21 21"))
+	_, err := tk.Exec("insert into t values (30, 30)")
+	c.Assert(table.ErrNoPartitionForGivenValue.Equal(err), IsTrue, Commentf("err %v", err))
+
+	// The partitions are pruned by the filters.
+	tk.MustQuery("select * from t where a = 11").Check(testkit.Rows("11 11"))
+	tk.MustQuery("select * from t where a < 10 order by b").Check(testkit.Rows("1 1"))
+	tk.MustQuery("select * from t where a >= 10 and a < 25 order by a").Check(testkit.Rows("11 11", "21 21"))
+	tk.MustQuery("select * from t where a is null").Check(testkit.Rows("<nil> 0"))
+	tk.MustQuery("select * from t where a > 100").Check(testkit.Rows())
+	tk.MustQuery("select b from t use index(idx) where b > 5 order by b").Check(testkit.Rows("11", "21"))
+	c.Assert(explainContains(tk, "select * from t where a = 11", "partition:p1"), IsTrue)
+	c.Assert(explainContains(tk, "select * from t where a = 11", "partition:p0"), IsFalse)
+	c.Assert(explainContains(tk, "select * from t where a > 100", "TableDual"), IsTrue)
+
+	// The rows are moved between the partitions when the partition column is updated.
+	tk.MustExec("update t set a = 25 where a = 1")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(1))
+	tk.MustQuery("select * from t where a > 20 order by b").Check(testkit.Rows("25 1", "21 21"))
+	tk.MustQuery("select count(*) from t where a < 10").Check(testkit.Rows("0"))
+	tk.MustExec("update t set a = a + 1 where b in (1, 11)")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(2))
+	tk.MustExec("update t set a = a - 1 where b in (1, 11)")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(2))
+	tk.MustExec("drop table if exists pr")
+	tk.MustExec("create table pr (id int primary key, v int) partition by range (id) (partition p0 values less than (20), partition p1 values less than (30))")
+	tk.MustExec("insert into pr values (15, 1)")
+	tk.MustExec("update pr set id = 25 where id = 15")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(1))
+	tk.MustQuery("select * from pr").Check(testkit.Rows("25 1"))
+	tk.MustExec("delete from t where a = 21")
+	tk.MustQuery("select * from t order by b").Check(testkit.Rows("<nil> 0", "25 1", "11 11"))
+
+	// The uncommitted rows are read by the union scan of every partition.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (2, 2), (12, 12)")
+	tk.MustQuery("select * from t where a < 20 order by b").Check(testkit.Rows("2 2", "11 11", "12 12"))
+	tk.MustQuery("select * from t order by b").Check(testkit.Rows("<nil> 0", "25 1", "2 2", "11 11", "12 12"))
+	tk.MustExec("rollback")
+
+	tk.MustExec("alter table t add index idx_a(a)")
+	tk.MustQuery("select a from t use index(idx_a) where a > 10 order by a").Check(testkit.Rows("11", "25"))
+}
+
+func (s *testSuite) TestHashPartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int) partition by hash (a) partitions 4")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (-6, -6)")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("-6 -6", "1 1", "2 2", "3 3", "4 4", "5 5"))
+	tk.MustQuery("select * from t where a = 5").Check(testkit.Rows("5 5"))
+	tk.MustQuery("select * from t where a in (1, 2) order by a").Check(testkit.Rows("1 1", "2 2"))
+	tk.MustQuery("select * from t where a = -6").Check(testkit.Rows("-6 -6"))
+	c.Assert(explainContains(tk, "select * from t where a = 5", "partition:p1"), IsTrue)
+	c.Assert(explainContains(tk, "select * from t where a = 5", "partition:p0"), IsFalse)
+
+	_, err := tk.Exec("insert into t values (5, 50)")
+	c.Assert(err, NotNil)
+	tk.MustExec("replace into t values (5, 55)")
+	tk.MustQuery("select * from t where a = 5").Check(testkit.Rows("5 55"))
+	tk.MustExec("replace into t values (4, 40)")
+	tk.MustQuery("select * from t where a = 4").Check(testkit.Rows("4 40"))
+	tk.MustExec("update t set a = 6 where a = 5")
+	tk.MustQuery("select * from t where a > 3 order by a").Check(testkit.Rows("4 40", "6 55"))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("6"))
+
+	tk.MustExec("drop table if exists t2")
+	tk.MustExec("create table t2 (a int, b int) partition by hash (a) (partition x, partition y)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2)")
+	tk.MustQuery("select * from t2 order by a").Check(testkit.Rows("1 1", "2 2"))
+	c.Assert(explainContains(tk, "select * from t2 where a = 2", "partition:x"), IsTrue)
+}
+
+func (s *testSuite) TestPartitionDDL(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec(`create table t (a int, b int) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20))`)
+	tk.MustExec("insert into t values (1, 1), (11, 11)")
+
+	tk.MustExec("alter table t add partition (partition p2 values less than (30), partition p3 values less than maxvalue)")
+	tk.MustExec("insert into t values (21, 21), (100, 100)")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1", "11 11", "21 21", "100 100"))
+	tk.MustQuery("select * from t where a > 50").Check(testkit.Rows("100 100"))
+	_, err := tk.Exec("alter table t add partition (partition p4 values less than (200))")
+	c.Assert(ddl.ErrPartitionMaxvalue.Equal(err), IsTrue, Commentf("err %v", err))
+
+	tk.MustExec("alter table t truncate partition p1")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1", "21 21", "100 100"))
+	tk.MustExec("insert into t values (12, 12)")
+	tk.MustQuery("select * from t where a between 10 and 19").Check(testkit.Rows("12 12"))
+
+	tk.MustExec("alter table t drop partition p0, p3")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("12 12", "21 21"))
+	tk.MustExec("insert into t values (5, 5)")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("5 5", "12 12", "21 21"))
+	_, err = tk.Exec("insert into t values (30, 30)")
+	c.Assert(table.ErrNoPartitionForGivenValue.Equal(err), IsTrue, Commentf("err %v", err))
+
+	_, err = tk.Exec("alter table t drop partition p5")
+	c.Assert(ddl.ErrDropPartitionNonExistent.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("alter table t drop partition if exists p5")
+	_, err = tk.Exec("alter table t drop partition p1, p2")
+	c.Assert(ddl.ErrDropLastPartition.Equal(err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("alter table t truncate partition p5")
+	c.Assert(table.ErrUnknownPartition.Equal(err), IsTrue, Commentf("err %v", err))
+
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec("create table t1 (a int) partition by hash (a) partitions 2")
+	_, err = tk.Exec("alter table t1 drop partition p0")
+	c.Assert(ddl.ErrOnlyOnRangeListPartition.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("insert into t1 values (1), (2)")
+	tk.MustExec("alter table t1 truncate partition p1")
+	tk.MustQuery("select * from t1").Check(testkit.Rows("2"))
+	_, err = tk.Exec("alter table t1 add partition (partition p2 values less than (10))")
+	c.Assert(ddl.ErrOnlyOnRangeListPartition.Equal(err), IsTrue, Commentf("err %v", err))
+
+	tk.MustExec("drop table if exists t2")
+	tk.MustExec("create table t2 (a int)")
+	_, err = tk.Exec("alter table t2 truncate partition p0")
+	c.Assert(ddl.ErrPartitionMgmtOnNonpartitioned.Equal(err), IsTrue, Commentf("err %v", err))
+}
+
+func (s *testSuite) TestCreatePartitionTableErrors(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+
+	tests := []struct {
+		sql string
+		err *terror.Error
+	}{
+		{"create table t (a int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (5))", ddl.ErrRangeNotIncreasing},
+		{"create table t (a int) partition by range (a) (partition p0 values less than maxvalue, partition p1 values less than (5))", ddl.ErrPartitionMaxvalue},
+		{"create table t (a int) partition by range (a) (partition p0 values less than (1), partition p0 values less than (5))", ddl.ErrSameNamePartition},
+		{"create table t (a int) partition by range (a)", ddl.ErrPartitionsMustBeDefined},
+		{"create table t (a int) partition by range (a) (partition p0)", ddl.ErrPartitionRequiresValues},
+		{"create table t (a int) partition by hash (a) (partition p0 values less than (10))", ddl.ErrPartitionWrongValues},
+		{"create table t (a int) partition by hash (a) partitions 3 (partition p0, partition p1)", ddl.ErrPartitionWrongNoPart},
+		{"create table t (a int) partition by range (a) (partition p0 values less than ('a'))", ddl.ErrValuesIsNotIntType},
+		{"create table t (a varchar(10)) partition by hash (a) partitions 2", ddl.ErrFieldTypeNotAllowedAsPartitionField},
+		{"create table t (a int) partition by hash (10) partitions 2", ddl.ErrWrongExprInPartitionFunc},
+		{"create table t (a int) partition by hash (a > 1) partitions 2", ddl.ErrPartitionFunctionIsNotAllowed},
+		{"create table t (a int) partition by hash (c) partitions 2", ddl.ErrBadField},
+		{"create table t (a int, b int, unique key (b)) partition by hash (a) partitions 2", ddl.ErrUniqueKeyNeedAllFieldsInPf},
+		{"create table t (a int, b int primary key) partition by hash (a) partitions 2", ddl.ErrUniqueKeyNeedAllFieldsInPf},
+		{"create table t (a int) partition by hash (a) partitions 1025", ddl.ErrTooManyPartitions},
+	}
+	for _, tt := range tests {
+		_, err := tk.Exec(tt.sql)
+		c.Assert(tt.err.Equal(err), IsTrue, Commentf("sql %s, err %v", tt.sql, err))
+	}
+
+	tk.MustExec("create table t (a int, b int, unique key (a, b)) partition by hash (a + b) partitions 2")
+	_, err := tk.Exec("alter table t add unique index idx_b(b)")
+	c.Assert(ddl.ErrUniqueKeyNeedAllFieldsInPf.Equal(err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("alter table t drop column b")
+	c.Assert(err, NotNil)
+}
+
+// explainContains checks if any row of the execution plan of sql contains str.
+func explainContains(tk *testkit.TestKit, sql string, str string) bool {
+	for _, row := range tk.MustQuery("explain " + sql).Rows() {
+		if strings.Contains(fmt.Sprint(row...), str) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *testSuite) TestShowCreatePartitionTable(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int) partition by range (a) (partition p0 values less than (10), partition p1 values less than maxvalue)")
+	tk.MustQuery("show create table t").Check(testutil.RowsWithSep("|",
+		"t|CREATE TABLE `t` (\n"+
+			"  `a` int(11) DEFAULT NULL,\n"+
+			"  `b` int(11) DEFAULT NULL\n"+
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n"+
+			"PARTITION BY RANGE ( `a` ) (\n"+
+			"  PARTITION `p0` VALUES LESS THAN (10),\n"+
+			"  PARTITION `p1` VALUES LESS THAN MAXVALUE\n"+
+			")",
+	))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int) partition by hash (a + b) partitions 2")
+	tk.MustQuery("show create table t").Check(testutil.RowsWithSep("|",
+		"t|CREATE TABLE `t` (\n"+
+			"  `a` int(11) DEFAULT NULL,\n"+
+			"  `b` int(11) DEFAULT NULL\n"+
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n"+
+			"PARTITION BY HASH ( `a` + `b` ) (\n"+
+			"  PARTITION `p0`,\n"+
+			"  PARTITION `p1`\n"+
+			")",
+	))
+}
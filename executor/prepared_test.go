@@ -0,0 +1,117 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"context"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite1) TestPrepared(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists prepare_test")
+	tk.MustExec("create table prepare_test (id int primary key, c1 int, c2 varchar(20))")
+	tk.MustExec("insert prepare_test values (1, 1, 'a'), (2, 2, 'b'), (3, 3, 'c')")
+
+	tk.MustExec(`prepare stmt_test_1 from 'select c2 from prepare_test where id > ? order by id'`)
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt_test_1 using @a").Check(testkit.Rows("b", "c"))
+	tk.MustExec("set @a = 2")
+	tk.MustQuery("execute stmt_test_1 using @a").Check(testkit.Rows("c"))
+
+	// The statement can be read from a user variable, and the parameters are bound in position order.
+	tk.MustExec(`set @sql = 'select id from prepare_test where id >= ? and c2 <= ? order by id limit ?'`)
+	tk.MustExec("prepare stmt_test_2 from @sql")
+	tk.MustExec("set @a = 2, @b = 'c', @c = 1")
+	tk.MustQuery("execute stmt_test_2 using @a, @b, @c").Check(testkit.Rows("2"))
+
+	// Write statements can be prepared too.
+	tk.MustExec("prepare stmt_test_3 from 'update prepare_test set c2 = ? where id = ?'")
+	tk.MustExec("set @a = 'x', @b = 3")
+	tk.MustExec("execute stmt_test_3 using @a, @b")
+	tk.MustQuery("select c2 from prepare_test where id = 3").Check(testkit.Rows("x"))
+
+	// Parameter count mismatch.
+	err := tk.ExecToErr("execute stmt_test_1")
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrWrongParamCount), IsTrue, Commentf("err %v", err))
+
+	// Unknown statement.
+	err = tk.ExecToErr("execute stmt_not_exists using @a")
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrStmtNotFound), IsTrue, Commentf("err %v", err))
+
+	// Prepare multiple statements or DDL.
+	err = tk.ExecToErr("prepare stmt_err from 'select 1; select 2'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrPrepareMulti), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("prepare stmt_err from 'create table t (a int)'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrPrepareDDL), IsTrue, Commentf("err %v", err))
+
+	// Parameter markers are only allowed in prepared statements.
+	_, err = tk.Exec("select ?")
+	c.Assert(err, NotNil)
+
+	tk.MustExec("deallocate prepare stmt_test_1")
+	err = tk.ExecToErr("execute stmt_test_1 using @a")
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrStmtNotFound), IsTrue, Commentf("err %v", err))
+	tk.MustExec("drop prepare stmt_test_2")
+	err = tk.ExecToErr("deallocate prepare stmt_test_2")
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrStmtNotFound), IsTrue, Commentf("err %v", err))
+
+	// Binary protocol prepared statements.
+	ctx := context.Background()
+	stmtID, paramCount, fields, err := tk.Se.PrepareStmt("select c1, c2 from prepare_test where id = ?")
+	c.Assert(err, IsNil)
+	c.Assert(paramCount, Equals, 1)
+	c.Assert(fields, HasLen, 2)
+	c.Assert(fields[1].ColumnAsName.O, Equals, "c2")
+	rs, err := tk.Se.ExecutePreparedStmt(ctx, stmtID, []types.Datum{types.NewDatum(2)})
+	c.Assert(err, IsNil)
+	tk.ResultSetToResult(rs, Commentf("%v", rs)).Check(testkit.Rows("2 b"))
+	rs, err = tk.Se.ExecutePreparedStmt(ctx, stmtID, []types.Datum{types.NewDatum("1")})
+	c.Assert(err, IsNil)
+	tk.ResultSetToResult(rs, Commentf("%v", rs)).Check(testkit.Rows("1 a"))
+	_, err = tk.Se.ExecutePreparedStmt(ctx, stmtID, []types.Datum{types.NewDatum(1), types.NewDatum(2)})
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrWrongParamCount), IsTrue, Commentf("err %v", err))
+
+	c.Assert(tk.Se.DropPreparedStmt(stmtID), IsNil)
+	_, err = tk.Se.ExecutePreparedStmt(ctx, stmtID, []types.Datum{types.NewDatum(1)})
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrStmtNotFound), IsTrue, Commentf("err %v", err))
+	c.Assert(terror.ErrorEqual(tk.Se.DropPreparedStmt(stmtID), plannercore.ErrStmtNotFound), IsTrue)
+}
+
+func (s *testSuite1) TestPreparedStmtCount(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	vars := tk.Se.GetSessionVars()
+	c.Assert(vars.SetSystemVar(variable.MaxPreparedStmtCount, "0"), IsNil)
+	err := tk.ExecToErr("prepare stmt1 from 'select 1'")
+	c.Assert(terror.ErrorEqual(err, variable.ErrMaxPreparedStmtCountReached), IsTrue, Commentf("err %v", err))
+	_, _, _, err = tk.Se.PrepareStmt("select 1")
+	c.Assert(terror.ErrorEqual(err, variable.ErrMaxPreparedStmtCountReached), IsTrue, Commentf("err %v", err))
+
+	c.Assert(vars.SetSystemVar(variable.MaxPreparedStmtCount, "-1"), IsNil)
+	tk.MustExec("prepare stmt1 from 'select 1'")
+	// Preparing a statement with an existing name replaces it.
+	tk.MustExec("prepare stmt1 from 'select 2'")
+	c.Assert(vars.PreparedStmts, HasLen, 1)
+	tk.MustQuery("execute stmt1").Check(testkit.Rows("2"))
+	tk.Se.Close()
+}
@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -129,10 +130,16 @@ func (e *SetExecutor) setSysVariable(name string, v *expression.VarAssignment) e
 		if name == variable.TxnIsolationOneShot && sessionVars.InTxn() {
 			return errors.Trace(ErrCantChangeTxCharacteristics)
 		}
+		oldSnapshotTS := sessionVars.SnapshotTS
 		err = variable.SetSessionSystemVar(sessionVars, name, value)
 		if err != nil {
 			return err
 		}
+		err = e.loadSnapshotInfoSchemaIfNeeded(name)
+		if err != nil {
+			sessionVars.SnapshotTS = oldSnapshotTS
+			return err
+		}
 		if value.IsNull() {
 			valStr = "NULL"
 		} else {
@@ -152,6 +159,28 @@ func (e *SetExecutor) setSysVariable(name string, v *expression.VarAssignment) e
 	return nil
 }
 
+func (e *SetExecutor) loadSnapshotInfoSchemaIfNeeded(name string) error {
+	if name != variable.TiDBSnapshot {
+		return nil
+	}
+	vars := e.ctx.GetSessionVars()
+	if vars.SnapshotTS == 0 {
+		vars.SnapshotInfoschema = nil
+		return nil
+	}
+	if err := validateSnapshot(e.ctx, vars.SnapshotTS); err != nil {
+		return err
+	}
+	logutil.BgLogger().Info("load snapshot info schema", zap.Uint64("conn", vars.ConnectionID), zap.Uint64("SnapshotTS", vars.SnapshotTS))
+	dom := domain.GetDomain(e.ctx)
+	snapInfo, err := dom.GetSnapshotInfoSchema(vars.SnapshotTS)
+	if err != nil {
+		return err
+	}
+	vars.SnapshotInfoschema = snapInfo
+	return nil
+}
+
 func (e *SetExecutor) getVarValue(v *expression.VarAssignment, sysVar *variable.SysVar) (value types.Datum, err error) {
 	if v.IsDefault {
 		// To set a SESSION variable to the GLOBAL value or a GLOBAL value
@@ -26,14 +26,17 @@ import (
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/format"
@@ -45,10 +48,11 @@ type ShowExec struct {
 
 	Tp        ast.ShowStmtType // Databases/Tables/Columns/....
 	DBName    model.CIStr
-	Table     *ast.TableName  // Used for showing columns.
-	Column    *ast.ColumnName // Used for `desc table column`.
-	IndexName model.CIStr     // Used for show table regions.
-	Flag      int             // Some flag parsed from sql, such as FULL.
+	Table     *ast.TableName     // Used for showing columns.
+	Column    *ast.ColumnName    // Used for `desc table column`.
+	IndexName model.CIStr        // Used for show table regions.
+	Flag      int                // Some flag parsed from sql, such as FULL.
+	User      *auth.UserIdentity // Used for show grants.
 
 	is infoschema.InfoSchema
 
@@ -95,6 +99,8 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 	switch e.Tp {
 	case ast.ShowCreateTable:
 		return e.fetchShowCreateTable()
+	case ast.ShowCreateView:
+		return e.fetchShowCreateView()
 	case ast.ShowCreateDatabase:
 		return e.fetchShowCreateDatabase()
 	case ast.ShowDatabases:
@@ -107,6 +113,8 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowWarnings(false)
 	case ast.ShowErrors:
 		return e.fetchShowWarnings(true)
+	case ast.ShowGrants:
+		return e.fetchShowGrants()
 	}
 	return nil
 }
@@ -129,7 +137,11 @@ func (e *ShowExec) fetchShowDatabases() error {
 	sort.Strings(dbs)
 	// let information_schema be the first database
 	moveInfoSchemaToFront(dbs)
+	checker := privilege.GetPrivilegeManager(e.ctx)
 	for _, d := range dbs {
+		if checker != nil && !checker.DBIsVisible(d) {
+			continue
+		}
 		e.appendRow([]interface{}{
 			d,
 		})
@@ -141,12 +153,25 @@ func (e *ShowExec) fetchShowTables() error {
 	if !e.is.SchemaExists(e.DBName) {
 		return ErrBadDB.GenWithStackByArgs(e.DBName)
 	}
+	checker := privilege.GetPrivilegeManager(e.ctx)
+	if checker != nil && !checker.DBIsVisible(e.DBName.O) {
+		user := e.ctx.GetSessionVars().User
+		return ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, e.DBName.O)
+	}
 	// sort for tables
 	tableNames := make([]string, 0, len(e.is.SchemaTables(e.DBName)))
 	var tableTypes = make(map[string]string)
 	for _, v := range e.is.SchemaTables(e.DBName) {
+		// Only the tables with any privilege are shown.
+		if checker != nil && !checker.RequestVerification(e.DBName.L, v.Meta().Name.L, "", mysql.AllPrivMask) {
+			continue
+		}
 		tableNames = append(tableNames, v.Meta().Name.O)
-		tableTypes[v.Meta().Name.O] = "BASE TABLE"
+		if v.Meta().IsView() {
+			tableTypes[v.Meta().Name.O] = "VIEW"
+		} else {
+			tableTypes[v.Meta().Name.O] = "BASE TABLE"
+		}
 	}
 	sort.Strings(tableNames)
 	for _, v := range tableNames {
@@ -273,6 +298,9 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 					if !mysql.HasNotNullFlag(col.Flag) {
 						buf.WriteString(" DEFAULT NULL")
 					}
+				case "CURRENT_TIMESTAMP":
+					buf.WriteString(" DEFAULT CURRENT_TIMESTAMP")
+					buf.WriteString(table.OptionalFsp(&col.FieldType))
 				default:
 					defaultValStr := fmt.Sprintf("%v", defaultValue)
 
@@ -374,14 +402,47 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 	if len(tableInfo.Comment) > 0 {
 		fmt.Fprintf(buf, " COMMENT='%s'", format.OutputFormat(tableInfo.Comment))
 	}
+	appendPartitionInfo(tableInfo.Partition, buf)
 	return nil
 }
 
+// appendPartitionInfo appends the PARTITION BY clause of a partitioned table to buf.
+func appendPartitionInfo(partitionInfo *model.PartitionInfo, buf *bytes.Buffer) {
+	if partitionInfo == nil {
+		return
+	}
+	switch partitionInfo.Type {
+	case model.PartitionTypeRange:
+		fmt.Fprintf(buf, "\nPARTITION BY RANGE ( %s ) (\n", partitionInfo.Expr)
+	case model.PartitionTypeHash:
+		fmt.Fprintf(buf, "\nPARTITION BY HASH ( %s ) (\n", partitionInfo.Expr)
+	}
+	for i, def := range partitionInfo.Definitions {
+		fmt.Fprintf(buf, "  PARTITION `%s`", def.Name.O)
+		if partitionInfo.Type == model.PartitionTypeRange {
+			if strings.EqualFold(def.LessThan[0], tables.PartitionMaxValue) {
+				buf.WriteString(" VALUES LESS THAN MAXVALUE")
+			} else {
+				fmt.Fprintf(buf, " VALUES LESS THAN (%s)", strings.Join(def.LessThan, ","))
+			}
+		}
+		if i < len(partitionInfo.Definitions)-1 {
+			buf.WriteString(",\n")
+		} else {
+			buf.WriteString("\n")
+		}
+	}
+	buf.WriteString(")")
+}
+
 func (e *ShowExec) fetchShowCreateTable() error {
 	tb, err := e.getTable()
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if tb.Meta().IsView() {
+		return e.fetchShowCreateView()
+	}
 
 	allocator := tb.Allocator(e.ctx)
 	var buf bytes.Buffer
@@ -394,6 +455,35 @@ func (e *ShowExec) fetchShowCreateTable() error {
 	return nil
 }
 
+// ConstructResultOfShowCreateView constructs the result for show create view.
+func ConstructResultOfShowCreateView(ctx sessionctx.Context, tableInfo *model.TableInfo, buf *bytes.Buffer) {
+	sqlMode := ctx.GetSessionVars().SQLMode
+	fmt.Fprintf(buf, "CREATE VIEW %s (", escape(tableInfo.Name, sqlMode))
+	for i, col := range tableInfo.View.Cols {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(escape(col, sqlMode))
+	}
+	fmt.Fprintf(buf, ") AS %s", tableInfo.View.SelectStmt)
+}
+
+func (e *ShowExec) fetchShowCreateView() error {
+	tb, err := e.getTable()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !tb.Meta().IsView() {
+		return ErrWrongObject.GenWithStackByArgs(e.DBName.O, tb.Meta().Name.O, "VIEW")
+	}
+
+	var buf bytes.Buffer
+	ConstructResultOfShowCreateView(e.ctx, tb.Meta(), &buf)
+	charset, collation := infoschema.ViewCharsetAndCollation(tb.Meta())
+	e.appendRow([]interface{}{tb.Meta().Name.O, buf.String(), charset, collation})
+	return nil
+}
+
 // ConstructResultOfShowCreateDatabase constructs the result for show create database.
 func ConstructResultOfShowCreateDatabase(ctx sessionctx.Context, dbInfo *model.DBInfo, ifNotExists bool, buf *bytes.Buffer) (err error) {
 	sqlMode := ctx.GetSessionVars().SQLMode
@@ -424,6 +514,29 @@ func (e *ShowExec) fetchShowCreateDatabase() error {
 	return nil
 }
 
+func (e *ShowExec) fetchShowGrants() error {
+	checker := privilege.GetPrivilegeManager(e.ctx)
+	if checker == nil {
+		return errors.New("miss privilege checker")
+	}
+	// Get the current user when no user or CURRENT_USER is specified.
+	user := e.User
+	if user == nil || user.CurrentUser {
+		user = e.ctx.GetSessionVars().User
+		if user == nil {
+			return errors.New("no user for show grants")
+		}
+	}
+	grants, err := checker.ShowGrants(e.ctx, user)
+	if err != nil {
+		return err
+	}
+	for _, g := range grants {
+		e.appendRow([]interface{}{g})
+	}
+	return nil
+}
+
 func (e *ShowExec) fetchShowWarnings(errOnly bool) error {
 	warns := e.ctx.GetSessionVars().StmtCtx.GetWarnings()
 	for _, w := range warns {
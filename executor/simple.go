@@ -15,21 +15,31 @@ package executor
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/ngaut/pools"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
 	"go.uber.org/zap"
 )
 
 // SimpleExec represents simple statement executor.
 // For statements do simple execution.
-// includes `UseStmt`,`BeginStmt`, `CommitStmt` and `RollbackStmt`.
+// includes `UseStmt`,`BeginStmt`, `CommitStmt`, `RollbackStmt` and the account management statements.
 type SimpleExec struct {
 	baseExecutor
 
@@ -53,11 +63,33 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		e.executeCommit(x)
 	case *ast.RollbackStmt:
 		err = e.executeRollback(x)
+	case *ast.CreateUserStmt:
+		err = e.executeCreateUser(x)
+	case *ast.DropUserStmt:
+		err = e.executeDropUser(x)
+	case *ast.GrantStmt:
+		err = e.executeGrant(x)
+	case *ast.RevokeStmt:
+		err = e.executeRevoke(x)
+	case *ast.KillStmt:
+		err = e.executeKillStmt(x)
 	}
 	e.done = true
 	return err
 }
 
+func (e *SimpleExec) executeKillStmt(s *ast.KillStmt) error {
+	sm := e.ctx.GetSessionManager()
+	if sm == nil {
+		return nil
+	}
+	if _, ok := sm.GetProcessInfo(s.ConnectionID); !ok {
+		return ErrNoSuchThread.GenWithStackByArgs(s.ConnectionID)
+	}
+	sm.Kill(s.ConnectionID, s.Query)
+	return nil
+}
+
 func (e *SimpleExec) executeUse(s *ast.UseStmt) error {
 	dbname := model.NewCIStr(s.DBName)
 
@@ -65,6 +97,10 @@ func (e *SimpleExec) executeUse(s *ast.UseStmt) error {
 	if !exists {
 		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(dbname)
 	}
+	if pm := privilege.GetPrivilegeManager(e.ctx); pm != nil && !pm.DBIsVisible(dbname.O) {
+		user := e.ctx.GetSessionVars().User
+		return ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, dbname.O)
+	}
 	e.ctx.GetSessionVars().CurrentDB = dbname.O
 	// character_set_database is the character set used by the default database.
 	// The server sets this variable whenever the default database changes.
@@ -89,6 +125,11 @@ func (e *SimpleExec) executeBegin(ctx context.Context, s *ast.BeginStmt) error {
 	// the transaction with COMMIT or ROLLBACK. The autocommit mode then
 	// reverts to its previous state.
 	e.ctx.GetSessionVars().SetStatusFlag(mysql.ServerStatusInTrans, true)
+	txnMode := s.Mode
+	if txnMode == "" {
+		txnMode = e.ctx.GetSessionVars().TxnMode
+	}
+	e.ctx.GetSessionVars().TxnCtx.IsPessimistic = txnMode == ast.Pessimistic
 	// Call ctx.Txn(true) to active pending txn.
 	_, err := e.ctx.Txn(true)
 	return err
@@ -112,3 +153,402 @@ func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
 	}
 	return nil
 }
+
+// getSysSession gets a session from the system session pool, the account management
+// statements run in it so that they are not checked against the privileges of the
+// current user and do not disturb its transaction.
+func (e *SimpleExec) getSysSession() (sessionctx.Context, error) {
+	ctx, err := domain.GetDomain(e.ctx).SysSessionPool().Get()
+	if err != nil {
+		return nil, err
+	}
+	restrictedCtx := ctx.(sessionctx.Context)
+	restrictedCtx.GetSessionVars().InRestrictedSQL = true
+	return restrictedCtx, nil
+}
+
+func (e *SimpleExec) releaseSysSession(ctx sessionctx.Context) {
+	if ctx == nil {
+		return
+	}
+	if _, err := ctx.(sqlexec.SQLExecutor).Execute(context.Background(), "rollback"); err != nil {
+		ctx.(pools.Resource).Close()
+		return
+	}
+	domain.GetDomain(e.ctx).SysSessionPool().Put(ctx.(pools.Resource))
+}
+
+// execInSysSession executes the sqls in a single transaction of a system session.
+func (e *SimpleExec) execInSysSession(sqls []string) error {
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(sysSession)
+
+	exec := sysSession.(sqlexec.SQLExecutor)
+	if _, err = exec.Execute(context.Background(), "begin"); err != nil {
+		return err
+	}
+	for _, sql := range sqls {
+		if _, err = exec.Execute(context.Background(), sql); err != nil {
+			return err
+		}
+	}
+	_, err = exec.Execute(context.Background(), "commit")
+	return err
+}
+
+// resolveUser replaces CURRENT_USER with the account the session is authenticated as.
+func (e *SimpleExec) resolveUser(user *auth.UserIdentity) *auth.UserIdentity {
+	if !user.CurrentUser {
+		return user
+	}
+	if current := e.ctx.GetSessionVars().User; current != nil {
+		return &auth.UserIdentity{Username: current.AuthUsername, Hostname: current.AuthHostname}
+	}
+	return user
+}
+
+func (e *SimpleExec) executeCreateUser(s *ast.CreateUserStmt) error {
+	users := make([]string, 0, len(s.Specs))
+	var failedUsers []string
+	for _, spec := range s.Specs {
+		exists, err := userExists(e.ctx, spec.User.Username, spec.User.Hostname)
+		if err != nil {
+			return err
+		}
+		if exists {
+			if !s.IfNotExists {
+				failedUsers = append(failedUsers, spec.User.String())
+			} else {
+				e.ctx.GetSessionVars().StmtCtx.AppendNote(ErrUserAlreadyExists.GenWithStackByArgs(spec.User.String()))
+			}
+			continue
+		}
+		pwd, ok := spec.EncodedPassword()
+		if !ok {
+			return ErrPasswordFormat
+		}
+		user := fmt.Sprintf(`('%s', '%s', '%s', '%s')`, spec.User.Hostname, spec.User.Username, pwd, authPluginOfSpec(spec))
+		users = append(users, user)
+	}
+	if len(failedUsers) > 0 {
+		return ErrCannotUser.GenWithStackByArgs("CREATE USER", strings.Join(failedUsers, ","))
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s.%s (Host, User, authentication_string, plugin) VALUES %s;`,
+		mysql.SystemDB, mysql.UserTable, strings.Join(users, ", "))
+	if err := e.execInSysSession([]string{sql}); err != nil {
+		return err
+	}
+	domain.GetDomain(e.ctx).NotifyUpdatePrivilege(e.ctx)
+	return nil
+}
+
+func (e *SimpleExec) executeDropUser(s *ast.DropUserStmt) error {
+	var failedUsers []string
+	sqls := make([]string, 0, len(s.UserList)*3)
+	for _, user := range s.UserList {
+		user = e.resolveUser(user)
+		exists, err := userExists(e.ctx, user.Username, user.Hostname)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if !s.IfExists {
+				failedUsers = append(failedUsers, user.String())
+			}
+			continue
+		}
+		for _, tbl := range []string{mysql.UserTable, mysql.DBTable, mysql.TablePrivTable} {
+			sqls = append(sqls, fmt.Sprintf(`DELETE FROM %s.%s WHERE Host = '%s' AND User = '%s';`,
+				mysql.SystemDB, tbl, user.Hostname, user.Username))
+		}
+	}
+	if len(failedUsers) > 0 {
+		return ErrCannotUser.GenWithStackByArgs("DROP USER", strings.Join(failedUsers, ","))
+	}
+	if len(sqls) == 0 {
+		return nil
+	}
+
+	if err := e.execInSysSession(sqls); err != nil {
+		return err
+	}
+	domain.GetDomain(e.ctx).NotifyUpdatePrivilege(e.ctx)
+	return nil
+}
+
+func (e *SimpleExec) executeGrant(s *ast.GrantStmt) error {
+	dbName, tableName, err := e.checkGrantLevel(s.Level)
+	if err != nil {
+		return err
+	}
+	privs, err := expandPrivileges(s.Level.Level, s.Privs)
+	if err != nil {
+		return err
+	}
+	if s.WithGrant {
+		privs = append(privs, mysql.GrantPriv)
+	}
+
+	var sqls []string
+	for _, spec := range s.Users {
+		user := e.resolveUser(spec.User)
+		exists, err := userExists(e.ctx, user.Username, user.Hostname)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			// With NO_AUTO_CREATE_USER, GRANT can only create the user if an authentication is given.
+			if spec.AuthOpt == nil && e.ctx.GetSessionVars().SQLMode.HasNoAutoCreateUserMode() {
+				return ErrCantCreateUserWithGrant
+			}
+			pwd, ok := spec.EncodedPassword()
+			if !ok {
+				return ErrPasswordFormat
+			}
+			sqls = append(sqls, fmt.Sprintf(`INSERT INTO %s.%s (Host, User, authentication_string, plugin) VALUES ('%s', '%s', '%s', '%s');`,
+				mysql.SystemDB, mysql.UserTable, user.Hostname, user.Username, pwd, authPluginOfSpec(spec)))
+		}
+
+		switch s.Level.Level {
+		case ast.GrantLevelGlobal:
+			sqls = append(sqls, composeUserPrivUpdate(user, privs, "Y"))
+		case ast.GrantLevelDB:
+			exists, err := dbPrivExists(e.ctx, user, dbName)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				sqls = append(sqls, fmt.Sprintf(`INSERT INTO %s.%s (Host, DB, User) VALUES ('%s', '%s', '%s');`,
+					mysql.SystemDB, mysql.DBTable, user.Hostname, dbName, user.Username))
+			}
+			sqls = append(sqls, composeDBPrivUpdate(user, dbName, privs, "Y"))
+		case ast.GrantLevelTable:
+			current, exists, err := getTablePriv(e.ctx, user, dbName, tableName)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				sqls = append(sqls, fmt.Sprintf(`INSERT INTO %s.%s (Host, DB, User, Table_name) VALUES ('%s', '%s', '%s', '%s');`,
+					mysql.SystemDB, mysql.TablePrivTable, user.Hostname, dbName, user.Username, tableName))
+			}
+			for _, priv := range privs {
+				current |= priv
+			}
+			sqls = append(sqls, e.composeTablePrivUpdate(user, dbName, tableName, current))
+		}
+	}
+
+	if err := e.execInSysSession(sqls); err != nil {
+		return err
+	}
+	domain.GetDomain(e.ctx).NotifyUpdatePrivilege(e.ctx)
+	return nil
+}
+
+func (e *SimpleExec) executeRevoke(s *ast.RevokeStmt) error {
+	dbName, tableName, err := e.checkGrantLevel(s.Level)
+	if err != nil {
+		return err
+	}
+	privs, err := expandPrivileges(s.Level.Level, s.Privs)
+	if err != nil {
+		return err
+	}
+
+	var sqls []string
+	for _, spec := range s.Users {
+		user := e.resolveUser(spec.User)
+		exists, err := userExists(e.ctx, user.Username, user.Hostname)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrNonexistingGrant.GenWithStackByArgs(user.Username, user.Hostname)
+		}
+
+		switch s.Level.Level {
+		case ast.GrantLevelGlobal:
+			sqls = append(sqls, composeUserPrivUpdate(user, privs, "N"))
+		case ast.GrantLevelDB:
+			exists, err := dbPrivExists(e.ctx, user, dbName)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return ErrNonexistingGrant.GenWithStackByArgs(user.Username, user.Hostname)
+			}
+			sqls = append(sqls, composeDBPrivUpdate(user, dbName, privs, "N"))
+		case ast.GrantLevelTable:
+			current, exists, err := getTablePriv(e.ctx, user, dbName, tableName)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return ErrNonexistingGrant.GenWithStackByArgs(user.Username, user.Hostname)
+			}
+			for _, priv := range privs {
+				current &^= priv
+			}
+			sqls = append(sqls, e.composeTablePrivUpdate(user, dbName, tableName, current))
+		}
+	}
+
+	if err := e.execInSysSession(sqls); err != nil {
+		return err
+	}
+	domain.GetDomain(e.ctx).NotifyUpdatePrivilege(e.ctx)
+	return nil
+}
+
+// checkGrantLevel resolves the database of the grant level and checks the object exists.
+func (e *SimpleExec) checkGrantLevel(level *ast.GrantLevel) (dbName, tableName string, err error) {
+	if level.Level == ast.GrantLevelGlobal {
+		return "", "", nil
+	}
+	dbName = level.DBName
+	if dbName == "" {
+		dbName = e.ctx.GetSessionVars().CurrentDB
+		if dbName == "" {
+			return "", "", core.ErrNoDB
+		}
+	}
+	if level.Level == ast.GrantLevelTable {
+		tableName = level.TableName
+		if _, err = e.is.TableByName(model.NewCIStr(dbName), model.NewCIStr(tableName)); err != nil {
+			return "", "", err
+		}
+	}
+	return dbName, tableName, nil
+}
+
+// grantablePrivs returns the privileges that can be granted at the level, GRANT OPTION
+// can be granted at every level.
+func grantablePrivs(level ast.GrantLevelType) []mysql.PrivilegeType {
+	switch level {
+	case ast.GrantLevelGlobal:
+		return mysql.AllGlobalPrivs
+	case ast.GrantLevelDB:
+		return mysql.AllDBPrivs
+	default:
+		return mysql.AllTablePrivs
+	}
+}
+
+// expandPrivileges expands ALL PRIVILEGES and checks every privilege is legal at the level.
+func expandPrivileges(level ast.GrantLevelType, elems []*ast.PrivElem) ([]mysql.PrivilegeType, error) {
+	allPrivs := grantablePrivs(level)
+	privs := make([]mysql.PrivilegeType, 0, len(elems))
+	for _, elem := range elems {
+		if elem.Priv == mysql.AllPriv {
+			privs = append(privs, allPrivs...)
+			continue
+		}
+		if elem.Priv != mysql.GrantPriv && !containsPriv(allPrivs, elem.Priv) {
+			return nil, ErrIllegalGrantForTable
+		}
+		privs = append(privs, elem.Priv)
+	}
+	return privs, nil
+}
+
+func containsPriv(privs []mysql.PrivilegeType, priv mysql.PrivilegeType) bool {
+	for _, p := range privs {
+		if p == priv {
+			return true
+		}
+	}
+	return false
+}
+
+func composePrivAssignments(privs []mysql.PrivilegeType, value string) string {
+	assignments := make([]string, 0, len(privs))
+	for _, priv := range privs {
+		assignments = append(assignments, fmt.Sprintf(`%s = '%s'`, mysql.Priv2UserCol[priv], value))
+	}
+	return strings.Join(assignments, ", ")
+}
+
+func composeUserPrivUpdate(user *auth.UserIdentity, privs []mysql.PrivilegeType, value string) string {
+	return fmt.Sprintf(`UPDATE %s.%s SET %s WHERE Host = '%s' AND User = '%s';`,
+		mysql.SystemDB, mysql.UserTable, composePrivAssignments(privs, value), user.Hostname, user.Username)
+}
+
+func composeDBPrivUpdate(user *auth.UserIdentity, dbName string, privs []mysql.PrivilegeType, value string) string {
+	return fmt.Sprintf(`UPDATE %s.%s SET %s WHERE Host = '%s' AND DB = '%s' AND User = '%s';`,
+		mysql.SystemDB, mysql.DBTable, composePrivAssignments(privs, value), user.Hostname, dbName, user.Username)
+}
+
+func (e *SimpleExec) composeTablePrivUpdate(user *auth.UserIdentity, dbName, tableName string, privs mysql.PrivilegeType) string {
+	var setStrs []string
+	for _, priv := range mysql.AllTablePrivs {
+		if privs&priv > 0 {
+			setStrs = append(setStrs, mysql.Priv2SetStr[priv])
+		}
+	}
+	if privs&mysql.GrantPriv > 0 {
+		setStrs = append(setStrs, mysql.Priv2SetStr[mysql.GrantPriv])
+	}
+	var grantor string
+	if current := e.ctx.GetSessionVars().User; current != nil {
+		grantor = current.String()
+	}
+	return fmt.Sprintf(`UPDATE %s.%s SET Grantor = '%s', Table_priv = '%s' WHERE Host = '%s' AND DB = '%s' AND User = '%s' AND Table_name = '%s';`,
+		mysql.SystemDB, mysql.TablePrivTable, grantor, strings.Join(setStrs, ","), user.Hostname, dbName, user.Username, tableName)
+}
+
+func authPluginOfSpec(spec *ast.UserSpec) string {
+	if spec.AuthOpt != nil && spec.AuthOpt.AuthPlugin != "" {
+		return spec.AuthOpt.AuthPlugin
+	}
+	return mysql.AuthNativePassword
+}
+
+func userExists(ctx sessionctx.Context, name string, host string) (bool, error) {
+	sql := fmt.Sprintf(`SELECT * FROM %s.%s WHERE User = '%s' AND Host = '%s';`, mysql.SystemDB, mysql.UserTable, name, host)
+	rows, _, err := ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(sql)
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func dbPrivExists(ctx sessionctx.Context, user *auth.UserIdentity, dbName string) (bool, error) {
+	sql := fmt.Sprintf(`SELECT * FROM %s.%s WHERE User = '%s' AND Host = '%s' AND DB = '%s';`,
+		mysql.SystemDB, mysql.DBTable, user.Username, user.Hostname, dbName)
+	rows, _, err := ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(sql)
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+// getTablePriv returns the table privileges currently granted to the user.
+func getTablePriv(ctx sessionctx.Context, user *auth.UserIdentity, dbName, tableName string) (mysql.PrivilegeType, bool, error) {
+	sql := fmt.Sprintf(`SELECT Table_priv FROM %s.%s WHERE User = '%s' AND Host = '%s' AND DB = '%s' AND Table_name = '%s';`,
+		mysql.SystemDB, mysql.TablePrivTable, user.Username, user.Hostname, dbName, tableName)
+	rows, _, err := ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(sql)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(rows) == 0 {
+		return 0, false, nil
+	}
+	var privs mysql.PrivilegeType
+	if setStr := rows[0].GetString(0); setStr != "" {
+		for _, str := range strings.Split(setStr, ",") {
+			priv, ok := mysql.SetStr2Priv[str]
+			if !ok {
+				return 0, false, errors.Errorf("unknown privilege %s in %s.%s", str, mysql.SystemDB, mysql.TablePrivTable)
+			}
+			privs |= priv
+		}
+	}
+	return privs, true, nil
+}
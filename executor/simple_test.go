@@ -14,11 +14,17 @@
 package executor_test
 
 import (
+	"sync/atomic"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -82,3 +88,145 @@ func (s *testSuite3) TestUseDB(c *C) {
 	_, err = tk.Exec("USE ``")
 	c.Assert(terror.ErrorEqual(core.ErrNoDB, err), IsTrue, Commentf("err %v", err))
 }
+
+func (s *testSuite3) TestUser(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`CREATE USER 'test'@'localhost' IDENTIFIED BY '123'`)
+	tk.MustQuery(`SELECT authentication_string, plugin FROM mysql.user WHERE User = 'test' AND Host = 'localhost'`).
+		Check(testkit.Rows(auth.EncodePassword("123") + " mysql_native_password"))
+
+	// Create an existing user.
+	_, err := tk.Exec(`CREATE USER 'test'@'localhost'`)
+	c.Assert(terror.ErrorEqual(executor.ErrCannotUser, err), IsTrue, Commentf("err %v", err))
+	tk.MustExec(`CREATE USER IF NOT EXISTS 'test'@'localhost'`)
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
+
+	// Create users with hashed passwords and other plugins.
+	tk.MustExec(`CREATE USER 'test1'@'%' IDENTIFIED BY PASSWORD '*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9', 'test2'@'%' IDENTIFIED WITH 'caching_sha2_password' BY '123'`)
+	tk.MustQuery(`SELECT authentication_string FROM mysql.user WHERE User = 'test1'`).
+		Check(testkit.Rows("*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9"))
+	rows := tk.MustQuery(`SELECT authentication_string, plugin FROM mysql.user WHERE User = 'test2'`).Rows()
+	c.Assert(rows, HasLen, 1)
+	pwd := rows[0][0].(string)
+	c.Assert(pwd, HasLen, mysql.SHAPWDHashLen)
+	ok, err := auth.CheckShaPassword([]byte(pwd), "123")
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsTrue)
+	c.Assert(rows[0][1], Equals, mysql.AuthCachingSha2Password)
+	_, err = tk.Exec(`CREATE USER 'test3'@'%' IDENTIFIED BY PASSWORD 'not a hash'`)
+	c.Assert(terror.ErrorEqual(executor.ErrPasswordFormat, err), IsTrue, Commentf("err %v", err))
+
+	// Drop users, together with their privileges.
+	tk.MustExec(`GRANT SELECT ON test.* TO 'test1'@'%'`)
+	tk.MustExec(`DROP USER 'test'@'localhost', 'test1'@'%'`)
+	tk.MustQuery(`SELECT * FROM mysql.user WHERE User IN ('test', 'test1')`).Check(testkit.Rows())
+	tk.MustQuery(`SELECT * FROM mysql.db WHERE User = 'test1'`).Check(testkit.Rows())
+	_, err = tk.Exec(`DROP USER 'test1'@'%'`)
+	c.Assert(terror.ErrorEqual(executor.ErrCannotUser, err), IsTrue, Commentf("err %v", err))
+	tk.MustExec(`DROP USER IF EXISTS 'test1'@'%', 'test2'@'%'`)
+	tk.MustQuery(`SELECT * FROM mysql.user WHERE User = 'test2'`).Check(testkit.Rows())
+}
+
+func (s *testSuite3) TestGrantRevoke(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`CREATE USER 'grantee'@'%'`)
+	tk.MustExec("use test")
+	tk.MustExec("create table grant_t (a int)")
+
+	// Global level.
+	tk.MustExec(`GRANT SELECT, INSERT ON *.* TO 'grantee'@'%' WITH GRANT OPTION`)
+	tk.MustQuery(`SELECT Select_priv, Insert_priv, Update_priv, Grant_priv FROM mysql.user WHERE User = 'grantee'`).
+		Check(testkit.Rows("Y Y N Y"))
+	tk.MustExec(`REVOKE INSERT ON *.* FROM 'grantee'@'%'`)
+	tk.MustQuery(`SELECT Select_priv, Insert_priv FROM mysql.user WHERE User = 'grantee'`).Check(testkit.Rows("Y N"))
+
+	// Database level, the current database is used when it is omitted.
+	tk.MustExec(`GRANT ALL ON * TO 'grantee'@'%'`)
+	tk.MustQuery(`SELECT DB, Select_priv, Drop_priv, Grant_priv FROM mysql.db WHERE User = 'grantee'`).
+		Check(testkit.Rows("test Y Y N"))
+	tk.MustExec(`REVOKE DROP ON test.* FROM 'grantee'@'%'`)
+	tk.MustQuery(`SELECT Select_priv, Drop_priv FROM mysql.db WHERE User = 'grantee'`).Check(testkit.Rows("Y N"))
+	_, err := tk.Exec(`REVOKE SELECT ON nodb.* FROM 'grantee'@'%'`)
+	c.Assert(terror.ErrorEqual(executor.ErrNonexistingGrant, err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec(`GRANT SUPER ON test.* TO 'grantee'@'%'`)
+	c.Assert(terror.ErrorEqual(executor.ErrIllegalGrantForTable, err), IsTrue, Commentf("err %v", err))
+
+	// Table level.
+	tk.MustExec(`GRANT SELECT, UPDATE ON grant_t TO 'grantee'@'%'`)
+	tk.MustQuery(`SELECT DB, Table_name, Table_priv FROM mysql.tables_priv WHERE User = 'grantee'`).
+		Check(testkit.Rows("test grant_t Select,Update"))
+	tk.MustExec(`GRANT INSERT ON test.grant_t TO 'grantee'@'%'`)
+	tk.MustExec(`REVOKE SELECT ON test.grant_t FROM 'grantee'@'%'`)
+	tk.MustQuery(`SELECT Table_priv FROM mysql.tables_priv WHERE User = 'grantee'`).Check(testkit.Rows("Insert,Update"))
+	_, err = tk.Exec(`GRANT SELECT ON test.no_such_table TO 'grantee'@'%'`)
+	c.Assert(terror.ErrorEqual(infoschema.ErrTableNotExists, err), IsTrue, Commentf("err %v", err))
+
+	// NO_AUTO_CREATE_USER is in the default sql mode, GRANT can't create a user without authentication.
+	_, err = tk.Exec(`GRANT SELECT ON *.* TO 'nobody'@'%'`)
+	c.Assert(terror.ErrorEqual(executor.ErrCantCreateUserWithGrant, err), IsTrue, Commentf("err %v", err))
+	tk.MustExec(`GRANT SELECT ON *.* TO 'nobody'@'%' IDENTIFIED BY '123'`)
+	tk.MustQuery(`SELECT Select_priv FROM mysql.user WHERE User = 'nobody'`).Check(testkit.Rows("Y"))
+	tk.MustExec(`DROP USER 'grantee'@'%', 'nobody'@'%'`)
+}
+
+type mockSessionManager struct {
+	processes map[uint64]*util.ProcessInfo
+	killed    map[uint64]bool
+}
+
+func (sm *mockSessionManager) GetProcessInfo(id uint64) (*util.ProcessInfo, bool) {
+	pi, ok := sm.processes[id]
+	return pi, ok
+}
+
+func (sm *mockSessionManager) Kill(connectionID uint64, query bool) {
+	sm.killed[connectionID] = query
+}
+
+func (s *testSuite3) TestKillStmt(c *C) {
+	sm := &mockSessionManager{
+		processes: map[uint64]*util.ProcessInfo{
+			1: {ID: 1, User: "root", Host: "localhost"},
+			2: {ID: 2, User: "killer", Host: "localhost"},
+		},
+		killed: make(map[uint64]bool),
+	}
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.Se.SetSessionManager(sm)
+	tk.MustExec("kill query 1")
+	tk.MustExec("kill connection 2")
+	c.Assert(sm.killed, DeepEquals, map[uint64]bool{1: true, 2: false})
+	_, err := tk.Exec("kill 3")
+	c.Assert(terror.ErrorEqual(executor.ErrNoSuchThread, err), IsTrue, Commentf("err %v", err))
+
+	// Killing the connections of other users requires the SUPER privilege.
+	tk.MustExec(`CREATE USER 'killer'@'%'`)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "killer", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk1.Se.SetSessionManager(sm)
+	sm.killed = make(map[uint64]bool)
+	tk1.MustExec("kill 2")
+	_, err = tk1.Exec("kill 1")
+	c.Assert(terror.ErrorEqual(core.ErrPrivilegeCheckFail, err), IsTrue, Commentf("err %v", err))
+	c.Assert(sm.killed, DeepEquals, map[uint64]bool{2: false})
+	tk.MustExec(`GRANT SUPER ON *.* TO 'killer'@'%'`)
+	tk1.MustExec("kill 1")
+	c.Assert(sm.killed, DeepEquals, map[uint64]bool{1: false, 2: false})
+	tk.MustExec(`DROP USER 'killer'@'%'`)
+
+	// A killed statement stops with the interrupted error.
+	tk.MustExec("use test")
+	tk.MustExec("create table kill_t (a int, b int)")
+	tk.MustExec("insert into kill_t values (1, 1), (2, 1), (3, 2)")
+	atomic.StoreUint32(&tk.Se.GetSessionVars().Killed, 1)
+	for _, sql := range []string{
+		"select * from kill_t order by b",
+		"select b, count(*) from kill_t group by b",
+		"select * from kill_t t1 join kill_t t2 on t1.a = t2.b",
+	} {
+		err = tk.QueryToErr(sql)
+		c.Assert(terror.ErrorEqual(executor.ErrQueryInterrupted, err), IsTrue, Commentf("sql %s, err %v", sql, err))
+	}
+	atomic.StoreUint32(&tk.Se.GetSessionVars().Killed, 0)
+	tk.MustQuery("select b, count(*) from kill_t group by b order by b").Check(testkit.Rows("1 2", "2 1"))
+}
@@ -17,10 +17,12 @@ import (
 	"container/heap"
 	"context"
 	"sort"
+	"sync/atomic"
 
 	"github.com/pingcap/tidb/expression"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 // SortExec represents sorting executor.
@@ -40,10 +42,38 @@ type SortExec struct {
 	rowChunks *chunk.List
 	// rowPointer store the chunk index and row index for each row.
 	rowPtrs []chunk.RowPtr
+
+	// memTracker tracks the memory used by rowChunks.
+	memTracker *memory.Tracker
+	// spillAction is registered on the memory tracker of the statement, it
+	// asks SortExec to spill rowChunks to disk when the quota is exceeded.
+	spillAction *spillDiskAction
+	// partitions are the sorted runs spilled to disk.
+	partitions []*chunk.ListInDisk
+	// multiWayMerge merges the sorted partitions into the final result.
+	multiWayMerge *multiWayMerge
 }
 
 // Close implements the Executor Close interface.
 func (e *SortExec) Close() error {
+	for _, partition := range e.partitions {
+		if err := partition.Close(); err != nil {
+			return err
+		}
+	}
+	e.partitions = nil
+	e.multiWayMerge = nil
+	e.rowChunks = nil
+	e.rowPtrs = nil
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.memTracker.BytesConsumed())
+		e.memTracker.Detach()
+		e.memTracker = nil
+	}
+	if e.spillAction != nil {
+		atomic.StoreUint32(&e.spillAction.fetching, 0)
+		e.spillAction = nil
+	}
 	return e.children[0].Close()
 }
 
@@ -51,6 +81,12 @@ func (e *SortExec) Close() error {
 func (e *SortExec) Open(ctx context.Context) error {
 	e.fetched = false
 	e.Idx = 0
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.spillAction = &spillDiskAction{}
+	if stmtTracker := e.ctx.GetSessionVars().StmtCtx.MemTracker; stmtTracker != nil {
+		e.memTracker.AttachTo(stmtTracker)
+		stmtTracker.FallbackOldAndSetNewAction(e.spillAction)
+	}
 	return e.children[0].Open(ctx)
 }
 
@@ -58,16 +94,29 @@ func (e *SortExec) Open(ctx context.Context) error {
 func (e *SortExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
 	if !e.fetched {
+		e.initCompareFuncs()
+		e.buildKeyColumns()
 		err := e.fetchRowChunks(ctx)
 		if err != nil {
 			return err
 		}
-		e.initPointers()
-		e.initCompareFuncs()
-		e.buildKeyColumns()
-		sort.Slice(e.rowPtrs, e.keyColumnsLess)
+		if len(e.partitions) == 0 {
+			e.initPointers()
+			sort.Slice(e.rowPtrs, e.keyColumnsLess)
+			if err = checkKilled(e.ctx); err != nil {
+				return err
+			}
+		} else {
+			err = e.initMultiWayMerge()
+			if err != nil {
+				return err
+			}
+		}
 		e.fetched = true
 	}
+	if e.multiWayMerge != nil {
+		return e.externalSorting(req)
+	}
 	for !req.IsFull() && e.Idx < len(e.rowPtrs) {
 		rowPtr := e.rowPtrs[e.Idx]
 		req.AppendRow(e.rowChunks.GetRow(rowPtr))
@@ -79,6 +128,8 @@ func (e *SortExec) Next(ctx context.Context, req *chunk.Chunk) error {
 func (e *SortExec) fetchRowChunks(ctx context.Context) error {
 	fields := retTypes(e)
 	e.rowChunks = chunk.NewList(fields, e.initCap, e.maxChunkSize)
+	atomic.StoreUint32(&e.spillAction.fetching, 1)
+	defer atomic.StoreUint32(&e.spillAction.fetching, 0)
 	for {
 		chk := newFirstChunk(e.children[0])
 		err := Next(ctx, e.children[0], chk)
@@ -90,10 +141,181 @@ func (e *SortExec) fetchRowChunks(ctx context.Context) error {
 			break
 		}
 		e.rowChunks.Add(chk)
+		e.memTracker.Consume(chk.MemoryUsage())
+		if atomic.CompareAndSwapUint32(&e.spillAction.triggered, 1, 0) {
+			err = e.spillToDisk()
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
+// spillToDisk sorts the rows in rowChunks and writes them to a new partition
+// on disk, then releases the memory held by rowChunks.
+func (e *SortExec) spillToDisk() error {
+	e.initPointers()
+	sort.Slice(e.rowPtrs, e.keyColumnsLess)
+	fields := retTypes(e)
+	partition := chunk.NewListInDisk(fields)
+	e.partitions = append(e.partitions, partition)
+	chk := chunk.New(fields, e.maxChunkSize, e.maxChunkSize)
+	for _, rowPtr := range e.rowPtrs {
+		chk.AppendRow(e.rowChunks.GetRow(rowPtr))
+		if chk.IsFull() {
+			if err := checkKilled(e.ctx); err != nil {
+				return err
+			}
+			if err := partition.Add(chk); err != nil {
+				return err
+			}
+			chk.Reset()
+		}
+	}
+	if chk.NumRows() > 0 {
+		if err := partition.Add(chk); err != nil {
+			return err
+		}
+	}
+	e.rowChunks = chunk.NewList(fields, e.initCap, e.maxChunkSize)
+	e.rowPtrs = nil
+	e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	if e.runtimeStats != nil {
+		e.runtimeStats.RecordSpill()
+	}
+	return nil
+}
+
+// initMultiWayMerge spills the remaining rows in memory as the last partition,
+// then builds the heap which merges all the sorted partitions.
+func (e *SortExec) initMultiWayMerge() error {
+	if e.rowChunks.Len() > 0 {
+		if err := e.spillToDisk(); err != nil {
+			return err
+		}
+	}
+	e.multiWayMerge = &multiWayMerge{lessRowFunction: e.lessRow}
+	for _, partition := range e.partitions {
+		cursor := &partitionCursor{partition: partition, chkIdx: -1}
+		ok, err := cursor.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			e.multiWayMerge.cursors = append(e.multiWayMerge.cursors, cursor)
+		}
+	}
+	heap.Init(e.multiWayMerge)
+	return nil
+}
+
+// externalSorting outputs the rows of the sorted partitions in order.
+func (e *SortExec) externalSorting(req *chunk.Chunk) error {
+	for !req.IsFull() && e.multiWayMerge.Len() > 0 {
+		cursor := e.multiWayMerge.cursors[0]
+		req.AppendRow(cursor.current())
+		ok, err := cursor.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(e.multiWayMerge, 0)
+		} else {
+			heap.Remove(e.multiWayMerge, 0)
+		}
+	}
+	return nil
+}
+
+// spillDiskAction is the memory.ActionOnExceed of the executors which can
+// spill their rows to disk, such as SortExec and HashJoinExec. It can only
+// make the executor spill while it is fetching rows from its child, otherwise
+// the fallback action is taken.
+type spillDiskAction struct {
+	// fetching is 1 while the executor is fetching rows from its child.
+	fetching uint32
+	// triggered is set to 1 when the memory quota is exceeded, the executor
+	// resets it after spilling its rows to disk.
+	triggered uint32
+	fallback  memory.ActionOnExceed
+}
+
+// Action implements the memory.ActionOnExceed interface.
+func (a *spillDiskAction) Action(t *memory.Tracker) {
+	if atomic.LoadUint32(&a.fetching) == 1 {
+		atomic.StoreUint32(&a.triggered, 1)
+		return
+	}
+	if a.fallback != nil {
+		a.fallback.Action(t)
+	}
+}
+
+// SetFallback implements the memory.ActionOnExceed interface.
+func (a *spillDiskAction) SetFallback(fallback memory.ActionOnExceed) {
+	a.fallback = fallback
+}
+
+// partitionCursor iterates the rows of a sorted partition on disk.
+type partitionCursor struct {
+	partition *chunk.ListInDisk
+	chkIdx    int
+	chk       *chunk.Chunk
+	rowIdx    int
+}
+
+func (c *partitionCursor) current() chunk.Row {
+	return c.chk.GetRow(c.rowIdx)
+}
+
+// next moves the cursor to the next row, it returns false when there is no
+// more row in the partition.
+func (c *partitionCursor) next() (bool, error) {
+	if c.chk != nil && c.rowIdx+1 < c.chk.NumRows() {
+		c.rowIdx++
+		return true, nil
+	}
+	if c.chkIdx+1 >= c.partition.NumChunks() {
+		return false, nil
+	}
+	c.chkIdx++
+	chk, err := c.partition.GetChunk(c.chkIdx)
+	if err != nil {
+		return false, err
+	}
+	c.chk, c.rowIdx = chk, 0
+	return true, nil
+}
+
+// multiWayMerge implements heap.Interface, it is a min heap of the partition
+// cursors ordered by their current rows.
+type multiWayMerge struct {
+	lessRowFunction func(rowI chunk.Row, rowJ chunk.Row) bool
+	cursors         []*partitionCursor
+}
+
+func (h *multiWayMerge) Less(i, j int) bool {
+	return h.lessRowFunction(h.cursors[i].current(), h.cursors[j].current())
+}
+
+func (h *multiWayMerge) Len() int {
+	return len(h.cursors)
+}
+
+func (h *multiWayMerge) Push(x interface{}) {
+	// Should never be called.
+}
+
+func (h *multiWayMerge) Pop() interface{} {
+	h.cursors = h.cursors[:len(h.cursors)-1]
+	return nil
+}
+
+func (h *multiWayMerge) Swap(i, j int) {
+	h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i]
+}
+
 func (e *SortExec) initPointers() {
 	e.rowPtrs = make([]chunk.RowPtr, 0, e.rowChunks.Len())
 	for chkIdx := 0; chkIdx < e.rowChunks.NumChunks(); chkIdx++ {
@@ -202,7 +424,9 @@ func (h *topNChunkHeap) Swap(i, j int) {
 
 // Open implements the Executor Open interface.
 func (e *TopNExec) Open(ctx context.Context) error {
-	return e.SortExec.Open(ctx)
+	e.fetched = false
+	e.Idx = 0
+	return e.children[0].Open(ctx)
 }
 
 // Next implements the Executor Next interface.
@@ -0,0 +1,68 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite) TestSortInDisk(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(c1 int, c2 int)")
+	values := make([]string, 0, 1024)
+	for i := 1023; i >= 0; i-- {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i%7))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+
+	// Every chunk fetched by Sort exceeds the quota and is spilled to disk,
+	// so the result is merged from many sorted partitions.
+	tk.MustExec("set @@tidb_mem_quota_query = 1")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	rows := tk.MustQuery("select c1 from t order by c1").Rows()
+	c.Assert(len(rows), Equals, 1024)
+	for i, row := range rows {
+		c.Assert(row[0].(string), Equals, fmt.Sprint(i))
+	}
+	rows = tk.MustQuery("select c2, c1 from t order by c2 desc, c1 limit 1000").Rows()
+	c.Assert(len(rows), Equals, 1000)
+	c.Assert(rows[0][0].(string), Equals, "6")
+	c.Assert(rows[0][1].(string), Equals, "6")
+
+	rows = tk.MustQuery("explain analyze select c1 from t order by c2, c1").Rows()
+	found := false
+	for _, row := range rows {
+		if strings.Contains(row[0].(string), "Sort") {
+			found = true
+			c.Assert(row[4].(string), Matches, ".*rows:1024, spill:([2-9]|[1-9][0-9]+)")
+		}
+	}
+	c.Assert(found, IsTrue)
+
+	// Without a quota, Sort never spills.
+	tk.MustExec("set @@tidb_mem_quota_query = 0")
+	rows = tk.MustQuery("explain analyze select c1 from t order by c2, c1").Rows()
+	for _, row := range rows {
+		if strings.Contains(row[0].(string), "Sort") {
+			c.Assert(row[4].(string), Not(Matches), ".*spill.*")
+		}
+	}
+	tk.MustQuery("select c1 from t order by c1 desc limit 2").Check(testkit.Rows("1023", "1022"))
+}
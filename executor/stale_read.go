@@ -0,0 +1,166 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/tikv"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/types"
+)
+
+// getStmtInfoSchema returns the information schema to compile the statement with.
+// If the statement reads history data, by AS OF TIMESTAMP or tidb_snapshot, it
+// sets the snapshot TS of the statement and returns the schema at that time.
+func getStmtInfoSchema(sctx sessionctx.Context, node ast.StmtNode) (infoschema.InfoSchema, error) {
+	sessVars := sctx.GetSessionVars()
+	if execStmt, ok := node.(*ast.ExecuteStmt); ok {
+		// The planner reports the error if the prepared statement doesn't exist.
+		if prepared, err := getPreparedStmt(execStmt, sessVars); err == nil {
+			node = prepared
+		}
+	}
+
+	snapshotTS, err := getAsOfTimestamp(sctx, node)
+	if err != nil {
+		return nil, err
+	}
+	if snapshotTS == 0 {
+		if sessVars.SnapshotTS != 0 && isWriteStmt(node) {
+			return nil, errors.Trace(ErrWriteOnSnapshot)
+		}
+		sessVars.StmtCtx.SnapshotTS = sessVars.SnapshotTS
+		return infoschema.GetInfoSchema(sctx), nil
+	}
+
+	if isWriteStmt(node) {
+		return nil, ErrAsOf.GenWithStackByArgs("AS OF TIMESTAMP can only be used in read-only statements")
+	}
+	if err = validateSnapshot(sctx, snapshotTS); err != nil {
+		return nil, err
+	}
+	is, err := domain.GetDomain(sctx).GetSnapshotInfoSchema(snapshotTS)
+	if err != nil {
+		return nil, err
+	}
+	sessVars.StmtCtx.SnapshotTS = snapshotTS
+	return is, nil
+}
+
+// isWriteStmt checks whether the statement writes data or schema, which can't
+// be done on a history snapshot.
+func isWriteStmt(node ast.StmtNode) bool {
+	switch x := node.(type) {
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt, ast.DDLNode:
+		return true
+	case *ast.SelectStmt:
+		return x.LockTp != ast.SelectLockNone
+	}
+	return false
+}
+
+// asOfCollector collects the AS OF TIMESTAMP clauses in a statement.
+type asOfCollector struct {
+	clauses []*ast.AsOfClause
+}
+
+// Enter implements ast.Visitor interface.
+func (c *asOfCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if asOf, ok := in.(*ast.AsOfClause); ok {
+		c.clauses = append(c.clauses, asOf)
+		return in, true
+	}
+	return in, false
+}
+
+// Leave implements ast.Visitor interface.
+func (c *asOfCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// getAsOfTimestamp returns the snapshot TS of the AS OF TIMESTAMP clauses in the
+// statement, or 0 if there are none. All the tables must be read at the same time.
+func getAsOfTimestamp(sctx sessionctx.Context, node ast.StmtNode) (uint64, error) {
+	collector := &asOfCollector{}
+	node.Accept(collector)
+	var snapshotTS uint64
+	for _, asOf := range collector.clauses {
+		ts, err := calculateAsOfTS(sctx, asOf)
+		if err != nil {
+			return 0, err
+		}
+		if snapshotTS != 0 && ts != snapshotTS {
+			return 0, ErrAsOf.GenWithStackByArgs("can not read the tables at different timestamps")
+		}
+		snapshotTS = ts
+	}
+	return snapshotTS, nil
+}
+
+// calculateAsOfTS evaluates the timestamp of an AS OF TIMESTAMP clause in the
+// session time zone and converts it to a TS.
+func calculateAsOfTS(sctx sessionctx.Context, asOf *ast.AsOfClause) (uint64, error) {
+	tsVal, err := expression.EvalAstExpr(sctx, asOf.TsExpr)
+	if err != nil {
+		return 0, err
+	}
+	if tsVal.IsNull() {
+		return 0, ErrAsOf.GenWithStackByArgs("the timestamp can not be NULL")
+	}
+	sessVars := sctx.GetSessionVars()
+	tp := types.NewFieldType(mysql.TypeTimestamp)
+	tp.Decimal = int(types.MaxFsp)
+	tsVal, err = tsVal.ConvertTo(sessVars.StmtCtx, tp)
+	if err != nil {
+		return 0, err
+	}
+	t, err := tsVal.GetMysqlTime().GoTime(sessVars.Location())
+	if err != nil {
+		return 0, err
+	}
+	ts := variable.GoTimeToTS(t)
+
+	ver, err := sctx.GetStore().CurrentVersion()
+	if err != nil {
+		return 0, err
+	}
+	if ts > ver.Ver {
+		return 0, ErrAsOf.GenWithStackByArgs("can not read data in the future")
+	}
+	return ts, nil
+}
+
+// validateSnapshot checks that the snapshot isn't older than the GC safe point,
+// the versions it reads may have been removed.
+func validateSnapshot(sctx sessionctx.Context, snapshotTS uint64) error {
+	store, ok := sctx.GetStore().(tikv.Storage)
+	if !ok {
+		return nil
+	}
+	safePoint, err := tikv.LoadSafePoint(store.GetSafePointKV())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if snapshotTS < safePoint {
+		return variable.ErrSnapshotTooOld.GenWithStackByArgs(oracle.GetTimeFromTS(safePoint).String())
+	}
+	return nil
+}
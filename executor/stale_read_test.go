@@ -0,0 +1,128 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/tikv"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+// mustGetTime returns the current time in the format of datetime, the data
+// written before it is visible to the snapshot of it.
+func (s *testSuite) mustGetTime(c *C) string {
+	time.Sleep(5 * time.Millisecond)
+	ver, err := s.store.CurrentVersion()
+	c.Assert(err, IsNil)
+	time.Sleep(5 * time.Millisecond)
+	return oracle.GetTimeFromTS(ver.Ver).Format("2006-01-02 15:04:05.000000")
+}
+
+func (s *testSuite) mustSetSafePoint(c *C, safePoint uint64) {
+	err := s.store.(tikv.Storage).GetSafePointKV().Put(tikv.GcSavedSafePoint, strconv.FormatUint(safePoint, 10))
+	c.Assert(err, IsNil)
+}
+
+func (s *testSuite) TestAsOfTimestamp(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t (a int primary key, b int, index idx(b))")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("insert t values (1, 1)")
+	t1 := s.mustGetTime(c)
+	tk.MustExec("update t set b = 2 where a = 1")
+	tk.MustExec("insert t values (2, 2)")
+	t2 := s.mustGetTime(c)
+	tk.MustExec("alter table t add column c int default 3")
+	tk.MustExec("delete from t where a = 2")
+
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 2 3"))
+	tk.MustQuery(fmt.Sprintf("select * from t as of timestamp '%s'", t1)).Check(testkit.Rows("1 1"))
+	// The schema at the timestamp is used.
+	tk.MustQuery(fmt.Sprintf("select * from t as of timestamp '%s'", t2)).Check(testkit.Rows("1 2", "2 2"))
+	tk.MustQuery(fmt.Sprintf("select b from t as of timestamp '%s' use index(idx) where b > 0", t1)).Check(testkit.Rows("1"))
+	tk.MustQuery(fmt.Sprintf("select * from t as of timestamp '%s' use index(idx) where b > 1", t2)).Check(testkit.Rows("1 2", "2 2"))
+	tk.MustQuery(fmt.Sprintf("select x.a from t as x as of timestamp '%s' join t as of timestamp '%s' on x.a = t.a", t2, t2)).Check(testkit.Rows("1", "2"))
+	tk.MustQuery(fmt.Sprintf("select x.b from t as of timestamp '%s' x where x.a = 1", t1)).Check(testkit.Rows("1"))
+
+	// The modifications of the current transaction are invisible.
+	tk.MustExec("begin")
+	tk.MustExec("insert t values (3, 3, 3)")
+	tk.MustQuery(fmt.Sprintf("select a from t as of timestamp '%s'", t2)).Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t").Check(testkit.Rows("1", "3"))
+	tk.MustExec("rollback")
+
+	_, err := tk.Exec(fmt.Sprintf("select * from t as of timestamp '%s', t1 as of timestamp '%s'", t1, t2))
+	c.Assert(terror.ErrorEqual(err, executor.ErrAsOf), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("select * from t as of timestamp '2037-01-01 00:00:00'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrAsOf), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("select * from t as of timestamp NULL")
+	c.Assert(terror.ErrorEqual(err, executor.ErrAsOf), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec(fmt.Sprintf("select * from t as of timestamp '%s' for update", t1))
+	c.Assert(terror.ErrorEqual(err, executor.ErrAsOf), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec(fmt.Sprintf("update t as of timestamp '%s' set b = 1", t1))
+	c.Assert(terror.ErrorEqual(err, executor.ErrAsOf), IsTrue, Commentf("err %v", err))
+
+	// The snapshot older than the GC safe point is rejected.
+	ver, err := s.store.CurrentVersion()
+	c.Assert(err, IsNil)
+	s.mustSetSafePoint(c, ver.Ver)
+	defer s.mustSetSafePoint(c, 0)
+	_, err = tk.Exec(fmt.Sprintf("select * from t as of timestamp '%s'", t1))
+	c.Assert(terror.ErrorEqual(err, variable.ErrSnapshotTooOld), IsTrue, Commentf("err %v", err))
+}
+
+func (s *testSuite) TestHistoryRead(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists history_read")
+	tk.MustExec("create table history_read (a int primary key, b int)")
+	tk.MustExec("insert history_read values (1, 1)")
+	t1 := s.mustGetTime(c)
+	tk.MustExec("alter table history_read add column c int default 3")
+	tk.MustExec("insert history_read values (2, 2, 2)")
+	ver, err := s.store.CurrentVersion()
+	c.Assert(err, IsNil)
+	tk.MustExec("drop table history_read")
+
+	// The snapshot can be set by datetime or TSO.
+	tk.MustExec(fmt.Sprintf("set @@tidb_snapshot = '%s'", t1))
+	tk.MustQuery("select * from history_read").Check(testkit.Rows("1 1"))
+	tk.MustExec(fmt.Sprintf("set @@tidb_snapshot = '%d'", ver.Ver))
+	tk.MustQuery("select * from history_read").Check(testkit.Rows("1 1 3", "2 2 2"))
+	_, err = tk.Exec("insert history_read values (3, 3, 3)")
+	c.Assert(terror.ErrorEqual(err, executor.ErrWriteOnSnapshot), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create table t_snapshot (a int)")
+	c.Assert(terror.ErrorEqual(err, executor.ErrWriteOnSnapshot), IsTrue, Commentf("err %v", err))
+
+	tk.MustExec("set @@tidb_snapshot = ''")
+	_, err = tk.Exec("select * from history_read")
+	c.Assert(err, NotNil)
+
+	// The snapshot older than the GC safe point is rejected.
+	s.mustSetSafePoint(c, ver.Ver)
+	defer s.mustSetSafePoint(c, 0)
+	_, err = tk.Exec(fmt.Sprintf("set @@tidb_snapshot = '%s'", t1))
+	c.Assert(terror.ErrorEqual(err, variable.ErrSnapshotTooOld), IsTrue, Commentf("err %v", err))
+	c.Assert(tk.Se.GetSessionVars().SnapshotTS, Equals, uint64(0))
+}
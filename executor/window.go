@@ -0,0 +1,346 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// WindowExec is the executor for window functions. It requires the rows of its
+// child sorted by the partition by items and the order by items, reads the rows
+// of a whole partition, and then evaluates the window functions on them.
+type WindowExec struct {
+	baseExecutor
+
+	groupChecker *groupChecker
+	// childResult is the chunk being read. A new chunk is allocated for every
+	// fetch because the rows of the partition refer to the former chunks.
+	childResult *chunk.Chunk
+	inputIter   *chunk.Iterator4Chunk
+	inputRow    chunk.Row
+	// partitionRows stores the rows of the current partition.
+	partitionRows []chunk.Row
+	// resultChk stores the results of the last partition, the ones before
+	// cursor are returned.
+	resultChk *chunk.Chunk
+	cursor    int
+	executed  bool
+
+	processor windowProcessor
+}
+
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.childResult = newFirstChunk(e.children[0])
+	e.inputIter = chunk.NewIterator4Chunk(e.childResult)
+	e.inputRow = e.inputIter.End()
+	e.resultChk = newFirstChunk(e)
+	e.cursor = 0
+	e.executed = false
+	e.groupChecker.reset()
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *WindowExec) Close() error {
+	e.childResult = nil
+	e.partitionRows = nil
+	e.resultChk = nil
+	return e.baseExecutor.Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *WindowExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !req.IsFull() {
+		if e.cursor < e.resultChk.NumRows() {
+			req.AppendRow(e.resultChk.GetRow(e.cursor))
+			e.cursor++
+			continue
+		}
+		if e.executed {
+			break
+		}
+		if err := e.consumeOnePartition(ctx); err != nil {
+			e.executed = true
+			return err
+		}
+	}
+	return nil
+}
+
+// consumeOnePartition reads the rows until a new partition is met, then
+// evaluates the window functions on the finished partition.
+func (e *WindowExec) consumeOnePartition(ctx context.Context) error {
+	for {
+		if e.inputRow == e.inputIter.End() {
+			eof, err := e.fetchChild(ctx)
+			if err != nil {
+				return err
+			}
+			if eof {
+				e.executed = true
+				return e.finishPartition()
+			}
+		}
+		for ; e.inputRow != e.inputIter.End(); e.inputRow = e.inputIter.Next() {
+			meetNewGroup, err := e.groupChecker.meetNewGroup(e.inputRow)
+			if err != nil {
+				return err
+			}
+			if meetNewGroup {
+				return e.finishPartition()
+			}
+			e.partitionRows = append(e.partitionRows, e.inputRow)
+		}
+	}
+}
+
+func (e *WindowExec) fetchChild(ctx context.Context) (eof bool, err error) {
+	e.childResult = newFirstChunk(e.children[0])
+	err = Next(ctx, e.children[0], e.childResult)
+	if err != nil {
+		return false, err
+	}
+	if e.childResult.NumRows() == 0 {
+		return true, nil
+	}
+	e.inputIter = chunk.NewIterator4Chunk(e.childResult)
+	e.inputRow = e.inputIter.Begin()
+	return false, nil
+}
+
+// finishPartition appends the rows of the partition with the results of the
+// window functions to resultChk.
+func (e *WindowExec) finishPartition() error {
+	if len(e.partitionRows) == 0 {
+		return nil
+	}
+	e.resultChk.Reset()
+	e.cursor = 0
+	for _, row := range e.partitionRows {
+		e.resultChk.AppendPartialRow(0, row)
+	}
+	err := e.processor.appendResult2Chunk(e.ctx, e.partitionRows, e.resultChk)
+	e.partitionRows = e.partitionRows[:0]
+	return err
+}
+
+// windowProcessor evaluates the window functions on the rows of a partition.
+type windowProcessor interface {
+	// appendResult2Chunk appends the results of the window functions of every
+	// row in the partition to chk.
+	appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk) error
+}
+
+type baseWindowProcessor struct {
+	windowFuncs    []aggfuncs.AggFunc
+	partialResults []aggfuncs.PartialResult
+}
+
+// aggWindowProcessor evaluates the window functions on the whole partition.
+type aggWindowProcessor struct {
+	baseWindowProcessor
+}
+
+func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk) error {
+	for i, windowFunc := range p.windowFuncs {
+		windowFunc.ResetPartialResult(p.partialResults[i])
+		err := windowFunc.UpdatePartialResult(ctx, rows, p.partialResults[i])
+		if err != nil {
+			return err
+		}
+		for range rows {
+			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// appendFrameResult evaluates the window functions on the rows in [start, end)
+// of the partition, which is the frame of a row.
+func (p *baseWindowProcessor) appendFrameResult(ctx sessionctx.Context, rows []chunk.Row, start, end uint64, chk *chunk.Chunk) error {
+	for i, windowFunc := range p.windowFuncs {
+		windowFunc.ResetPartialResult(p.partialResults[i])
+		if start < end {
+			err := windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+			if err != nil {
+				return err
+			}
+		}
+		err := windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rowFrameWindowProcessor evaluates the window functions on the ROWS frames.
+type rowFrameWindowProcessor struct {
+	baseWindowProcessor
+	start *plannercore.FrameBound
+	end   *plannercore.FrameBound
+}
+
+func (p *rowFrameWindowProcessor) getStartOffset(numRows, curRowIdx uint64) uint64 {
+	if p.start.UnBounded {
+		return 0
+	}
+	switch p.start.Type {
+	case ast.Preceding:
+		if curRowIdx >= p.start.Num {
+			return curRowIdx - p.start.Num
+		}
+		return 0
+	case ast.Following:
+		offset := curRowIdx + p.start.Num
+		if offset >= numRows {
+			return numRows
+		}
+		return offset
+	}
+	return curRowIdx
+}
+
+func (p *rowFrameWindowProcessor) getEndOffset(numRows, curRowIdx uint64) uint64 {
+	if p.end.UnBounded {
+		return numRows
+	}
+	switch p.end.Type {
+	case ast.Preceding:
+		if curRowIdx >= p.end.Num {
+			return curRowIdx - p.end.Num + 1
+		}
+		return 0
+	case ast.Following:
+		offset := curRowIdx + p.end.Num
+		if offset >= numRows {
+			return numRows
+		}
+		return offset + 1
+	}
+	return curRowIdx + 1
+}
+
+func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk) error {
+	numRows := uint64(len(rows))
+	for curRowIdx := uint64(0); curRowIdx < numRows; curRowIdx++ {
+		start, end := p.getStartOffset(numRows, curRowIdx), p.getEndOffset(numRows, curRowIdx)
+		if err := p.appendFrameResult(ctx, rows, start, end, chk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rangeFrameWindowProcessor evaluates the window functions on the RANGE frames,
+// the rows are in the frame if their order by items are between the bounds
+// calculated from the current row.
+type rangeFrameWindowProcessor struct {
+	baseWindowProcessor
+	start       *plannercore.FrameBound
+	end         *plannercore.FrameBound
+	orderByCols []*expression.Column
+	// expectedCmpResults are the results of comparing a row with a following
+	// row in the partition, -1 for asc order and 1 for desc order. They are
+	// used to turn the compare results into the order of the partition.
+	expectedCmpResults []int64
+
+	lastStartOffset uint64
+	lastEndOffset   uint64
+}
+
+// compareBound compares lhs with rhs item by item, a positive result means lhs
+// comes before rhs in the order of the partition.
+func (p *rangeFrameWindowProcessor) compareBound(ctx sessionctx.Context, lhs, rhs []expression.Expression, cmpFuncs []expression.CompareFunc, lhsRow, rhsRow chunk.Row) (int64, error) {
+	for i := range p.orderByCols {
+		res, _, err := cmpFuncs[i](ctx, lhs[i], rhs[i], lhsRow, rhsRow)
+		if err != nil {
+			return 0, err
+		}
+		if res != 0 {
+			return res * p.expectedCmpResults[i], nil
+		}
+	}
+	return 0, nil
+}
+
+func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row, curRowIdx uint64) (uint64, error) {
+	if p.start.UnBounded {
+		return 0, nil
+	}
+	lhs := expression.Column2Exprs(p.orderByCols)
+	for ; p.lastStartOffset < uint64(len(rows)); p.lastStartOffset++ {
+		res, err := p.compareBound(ctx, lhs, p.start.CalcFuncs, p.start.CmpFuncs, rows[p.lastStartOffset], rows[curRowIdx])
+		if err != nil {
+			return 0, err
+		}
+		// The row isn't before the start bound.
+		if res <= 0 {
+			break
+		}
+	}
+	return p.lastStartOffset, nil
+}
+
+func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []chunk.Row, curRowIdx uint64) (uint64, error) {
+	numRows := uint64(len(rows))
+	if p.end.UnBounded {
+		return numRows, nil
+	}
+	lhs := expression.Column2Exprs(p.orderByCols)
+	for ; p.lastEndOffset < numRows; p.lastEndOffset++ {
+		res, err := p.compareBound(ctx, p.end.CalcFuncs, lhs, p.end.CmpFuncs, rows[curRowIdx], rows[p.lastEndOffset])
+		if err != nil {
+			return 0, err
+		}
+		// The row is after the end bound.
+		if res > 0 {
+			break
+		}
+	}
+	return p.lastEndOffset, nil
+}
+
+func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk) error {
+	p.lastStartOffset, p.lastEndOffset = 0, 0
+	for curRowIdx := uint64(0); curRowIdx < uint64(len(rows)); curRowIdx++ {
+		start, err := p.getStartOffset(ctx, rows, curRowIdx)
+		if err != nil {
+			return err
+		}
+		end, err := p.getEndOffset(ctx, rows, curRowIdx)
+		if err != nil {
+			return err
+		}
+		if err = p.appendFrameResult(ctx, rows, start, end, chk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
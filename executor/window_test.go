@@ -0,0 +1,194 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite) TestWindowFunctions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 1), (1, 2, 2), (1, 2, 3), (2, 1, 4), (2, 3, 5), (2, 4, NULL)")
+
+	tk.MustQuery("select a, b, row_number() over (partition by a order by b, c) from t").Check(testkit.Rows(
+		"1 1 1", "1 2 2", "1 2 3", "2 1 1", "2 3 2", "2 4 3"))
+	tk.MustQuery("select a, b, rank() over (partition by a order by b), dense_rank() over (partition by a order by b) from t order by a, b").Check(testkit.Rows(
+		"1 1 1 1", "1 2 2 2", "1 2 2 2", "2 1 1 1", "2 3 2 2", "2 4 3 3"))
+	tk.MustQuery("select c, rank() over (order by b desc) from t order by c").Check(testkit.Rows(
+		"<nil> 1", "1 5", "2 3", "3 3", "4 5", "5 2"))
+	tk.MustQuery("select c, ntile(4) over (order by c) from t order by c").Check(testkit.Rows(
+		"<nil> 1", "1 1", "2 2", "3 2", "4 3", "5 4"))
+	tk.MustQuery("select c, ntile(null) over (order by c) from t order by c limit 1").Check(testkit.Rows("<nil> <nil>"))
+
+	tk.MustQuery("select c, lead(c) over (order by c), lag(c, 2, -1) over (order by c) from t order by c").Check(testkit.Rows(
+		"<nil> 1 -1", "1 2 -1", "2 3 <nil>", "3 4 1", "4 5 2", "5 <nil> 3"))
+	tk.MustQuery("select a, c, first_value(c) over (partition by a order by c), last_value(c) over (partition by a order by c) from t order by a, c").Check(testkit.Rows(
+		"1 1 1 1", "1 2 1 2", "1 3 1 3", "2 <nil> <nil> <nil>", "2 4 <nil> 4", "2 5 <nil> 5"))
+	tk.MustQuery("select a, c, last_value(c) over (partition by a order by c rows between unbounded preceding and unbounded following) from t order by a, c").Check(testkit.Rows(
+		"1 1 3", "1 2 3", "1 3 3", "2 <nil> 5", "2 4 5", "2 5 5"))
+
+	// Aggregate functions over the whole partition and the default frame.
+	tk.MustQuery("select a, c, sum(c) over (partition by a), count(c) over (partition by a) from t order by a, c").Check(testkit.Rows(
+		"1 1 6 3", "1 2 6 3", "1 3 6 3", "2 <nil> 9 2", "2 4 9 2", "2 5 9 2"))
+	tk.MustQuery("select a, b, sum(c) over (partition by a order by b) from t order by a, b, c").Check(testkit.Rows(
+		"1 1 1", "1 2 6", "1 2 6", "2 1 4", "2 3 9", "2 4 9"))
+	tk.MustQuery("select a, sum(c) over () from t where a = 1").Check(testkit.Rows("1 6", "1 6", "1 6"))
+
+	// ROWS frames.
+	tk.MustQuery("select c, sum(c) over (order by c rows between 1 preceding and 1 following) from t where c is not null order by c").Check(testkit.Rows(
+		"1 3", "2 6", "3 9", "4 12", "5 9"))
+	tk.MustQuery("select c, avg(c) over (order by c rows 2 preceding) from t where c is not null order by c").Check(testkit.Rows(
+		"1 1.0000", "2 1.5000", "3 2.0000", "4 3.0000", "5 4.0000"))
+	tk.MustQuery("select c, count(c) over (order by c rows between 1 following and 3 following) from t where c is not null order by c").Check(testkit.Rows(
+		"1 3", "2 3", "3 2", "4 1", "5 0"))
+	tk.MustQuery("select c, max(c) over (order by c rows between current row and unbounded following), min(c) over (order by c desc rows unbounded preceding) from t where c is not null order by c").Check(testkit.Rows(
+		"1 5 1", "2 5 2", "3 5 3", "4 5 4", "5 5 5"))
+
+	// RANGE frames.
+	tk.MustQuery("select b, sum(b) over (order by b range between 1 preceding and 1 following) from t order by b, c").Check(testkit.Rows(
+		"1 6", "1 6", "2 9", "2 9", "3 11", "4 7"))
+	tk.MustQuery("select b, count(*) over (order by b desc range between current row and 1 following) from t order by b, c").Check(testkit.Rows(
+		"1 2", "1 2", "2 4", "2 4", "3 3", "4 2"))
+	tk.MustQuery("select b, sum(b) over (order by b range between unbounded preceding and current row) from t order by b, c").Check(testkit.Rows(
+		"1 2", "1 2", "2 6", "2 6", "3 9", "4 13"))
+
+	// Window functions in the ORDER BY clause and on the aggregation results.
+	tk.MustQuery("select a, b from t order by row_number() over (order by c desc) limit 2").Check(testkit.Rows("2 3", "2 1"))
+	tk.MustQuery("select a, sum(c), rank() over (order by sum(c) desc) from t group by a order by a").Check(testkit.Rows("1 6 2", "2 9 1"))
+	// The predicates on the partition by columns are pushed through the window.
+	tk.MustQuery("select * from (select a, c, row_number() over (partition by a order by c) as r from t) t1 where a = 2 and r > 1").Check(testkit.Rows(
+		"2 4 2", "2 5 3"))
+
+	// The function ignoring the frame raises a note.
+	tk.MustQuery("select c, row_number() over (order by c rows 1 preceding) from t where c = 1").Check(testkit.Rows("1 1"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
+}
+
+func (s *testSuite) TestStackedWindows(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 10), (2, 20), (3, 30), (4, NULL)")
+
+	// Every window appends its result to the output of the window below it.
+	tk.MustQuery("select a, sum(b) over (partition by a), count(*) over () from t").Check(testkit.Rows(
+		"1 10 4", "2 20 4", "3 30 4", "4 <nil> 4"))
+	tk.MustQuery("select a, row_number() over (order by a desc), sum(b) over (partition by a), count(*) over () from t").Check(testkit.Rows(
+		"1 4 10 4", "2 3 20 4", "3 2 30 4", "4 1 <nil> 4"))
+	tk.MustQuery("select a, b, rank() over (order by b), sum(a) over (partition by b is null), avg(b) over () from t order by a").Check(testkit.Rows(
+		"1 10 2 6 20.0000", "2 20 3 6 20.0000", "3 30 4 6 20.0000", "4 <nil> 1 4 20.0000"))
+	tk.MustQuery("select a, sum(b) over (partition by a % 2), max(b + 1) over (order by a rows 1 preceding), lag(b * 2) over (order by b desc) from t order by a").Check(testkit.Rows(
+		"1 40 11 40", "2 20 21 60", "3 40 31 <nil>", "4 20 31 20"))
+	tk.MustQuery("select a, first_value(b) over (order by a), last_value(b) over (order by a desc), ntile(2) over (order by a) from t order by a").Check(testkit.Rows(
+		"1 10 10 1", "2 10 20 1", "3 10 30 2", "4 10 <nil> 2"))
+	tk.MustQuery("select * from (select a, row_number() over (order by a) r, sum(b) over (partition by a > 2) s from t) x where r > 1 order by a").Check(testkit.Rows(
+		"2 2 30", "3 3 30", "4 4 30"))
+}
+
+func (s *testSuite) TestWindowOverUnorderedInputs(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 10), (2, 20), (3, 30)")
+
+	// An empty input is planned as a table dual.
+	tk.MustQuery("select a, sum(b) over (partition by a order by b) from t where 1 = 0").Check(testkit.Rows())
+	tk.MustQuery("select a, row_number() over (order by a) from (select * from t limit 0) x").Check(testkit.Rows())
+
+	// A sort is added above the CTE.
+	tk.MustQuery("with c as (select a, b from t) select a, row_number() over (order by b desc) from c order by a").Check(testkit.Rows(
+		"1 3", "2 2", "3 1"))
+	tk.MustQuery("with c as (select a, b from t) select x.a, rank() over (partition by x.a % 2 order by y.b) from c x join c y on x.a = y.a order by x.a").Check(testkit.Rows(
+		"1 1", "2 1", "3 2"))
+	tk.MustQuery("with recursive c(n) as (select 1 union all select n + 1 from c where n < 4) select n, sum(n) over (order by n desc) from c order by n").Check(testkit.Rows(
+		"1 10", "2 9", "3 7", "4 4"))
+	tk.MustQuery("with recursive c(n) as (select 1 union all select n + 1 from c where n < 4) select n, count(*) over (partition by n % 2) from c order by n").Check(testkit.Rows(
+		"1 2", "2 2", "3 2", "4 2"))
+}
+
+func (s *testSuite) TestNamedWindows(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (1, 2), (2, 3), (2, 4)")
+
+	tk.MustQuery("select a, b, sum(b) over w, row_number() over w from t window w as (partition by a order by b desc) order by a, b").Check(testkit.Rows(
+		"1 1 3 2", "1 2 2 1", "2 3 7 2", "2 4 4 1"))
+	// A window can add the ORDER BY clause and the frame to the window it refers to.
+	tk.MustQuery("select b, sum(b) over (w order by b rows 1 preceding), count(*) over w2, first_value(b) over w2 from t window w as (partition by a), w2 as (w order by b desc) order by b").Check(testkit.Rows(
+		"1 1 2 2", "2 3 1 2", "3 3 2 4", "4 7 1 4"))
+	tk.MustQuery("select a, sum(sum(b)) over w from t group by a window w as (order by sum(b) desc) order by a").Check(testkit.Rows(
+		"1 10", "2 7"))
+	// The unused windows are checked too.
+	tk.MustQuery("select a from t where a = 1 and b = 1 window w as ()").Check(testkit.Rows("1"))
+
+	tests := []struct {
+		sql string
+		err *terror.Error
+	}{
+		{"select sum(a) over w from t", plannercore.ErrWindowNoSuchWindow},
+		{"select sum(a) over (w2) from t window w as ()", plannercore.ErrWindowNoSuchWindow},
+		{"select sum(a) over w from t window w as (), w as (order by a)", plannercore.ErrWindowDuplicateName},
+		{"select sum(a) over w from t window w as (w2), w2 as (w)", plannercore.ErrWindowCircularityInWindowGraph},
+		{"select sum(a) over w from t window w as (w)", plannercore.ErrWindowCircularityInWindowGraph},
+		{"select sum(a) over (w partition by b) from t window w as ()", plannercore.ErrWindowNoChildPartitioning},
+		{"select sum(a) over (w) from t window w as (rows 1 preceding)", plannercore.ErrWindowNoInherentFrame},
+		{"select sum(a) over (w order by b) from t window w as (order by a)", plannercore.ErrWindowNoRedefineOrderBy},
+		{"select a from t window w as (rows between 1 following and 1 preceding)", plannercore.ErrWindowFrameIllegal},
+	}
+	for _, t := range tests {
+		_, err := tk.Exec(t.sql)
+		c.Assert(terror.ErrorEqual(err, t.err), IsTrue, Commentf("sql %s, err %v", t.sql, err))
+	}
+	_, err := tk.Exec("select sum(a) over (w order by b) from t window w as (order by a)")
+	c.Assert(err.Error(), Equals, "[planner:3583]Window '<unnamed window>' cannot inherit 'w' since both contain an ORDER BY clause.")
+}
+
+func (s *testSuite) TestWindowFunctionErrors(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(10))")
+
+	tests := []struct {
+		sql string
+		err *terror.Error
+	}{
+		{"select a from t where row_number() over () > 1", plannercore.ErrWindowInvalidWindowFuncUse},
+		{"select a from t group by a having row_number() over () > 1", plannercore.ErrWindowInvalidWindowFuncUse},
+		{"select sum(a) over (order by row_number() over ()) from t", plannercore.ErrWindowNestedWindowFuncUseInWindowSpec},
+		{"select sum(a) over (rows between unbounded following and current row) from t", plannercore.ErrWindowFrameStartIllegal},
+		{"select sum(a) over (rows between current row and unbounded preceding) from t", plannercore.ErrWindowFrameEndIllegal},
+		{"select sum(a) over (rows between 1 following and 1 preceding) from t", plannercore.ErrWindowFrameIllegal},
+		{"select sum(a) over (rows between 1.5 preceding and current row) from t", plannercore.ErrWindowFrameIllegal},
+		{"select sum(a) over (order by a, b range 1 preceding) from t", plannercore.ErrWindowRangeFrameOrderType},
+		{"select sum(a) over (order by b range 1 preceding) from t", plannercore.ErrWindowRangeFrameOrderType},
+		{"select ntile(0) over (order by a) from t", plannercore.ErrWrongArguments},
+		{"select lag(a, 1.5) over (order by a) from t", plannercore.ErrWrongArguments},
+		{"select ntile(a) over (order by a) from t", plannercore.ErrWrongArguments},
+	}
+	for _, t := range tests {
+		_, err := tk.Exec(t.sql)
+		c.Assert(terror.ErrorEqual(err, t.err), IsTrue, Commentf("sql %s, err %v", t.sql, err))
+	}
+}
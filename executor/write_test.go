@@ -481,6 +481,60 @@ func (s *testSuite) TestDelete(c *C) {
 	tk.MustExec("commit")
 }
 
+func (s *testSuite) TestUpdate(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	s.fillData(tk, "update_test")
+
+	tk.MustExec(`update update_test set name = "abc" where id > 0;`)
+	tk.CheckExecResult(2, 0)
+
+	// Test update with false condition
+	tk.MustExec(`update update_test set name = "abc" where 0;`)
+	tk.CheckExecResult(0, 0)
+
+	// Test update with the same value
+	tk.MustExec(`update update_test set name = "abc" where id = 1;`)
+	tk.CheckExecResult(0, 0)
+
+	tk.MustExec(`update update_test set name = "abcd" where id = 2 limit 1;`)
+	tk.CheckExecResult(1, 0)
+	tk.MustQuery("select * from update_test").Check(testkit.Rows("1 abc", "2 abcd"))
+
+	// Test update the handle column
+	tk.MustExec("update update_test set id = id + 10 order by id desc limit 1")
+	tk.CheckExecResult(1, 0)
+	tk.MustQuery("select * from update_test").Check(testkit.Rows("1 abc", "12 abcd"))
+	_, err := tk.Exec("update update_test set id = 12 where id = 1")
+	c.Assert(err, NotNil)
+
+	// Test update with index
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, index idx_b(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("update t set b = b + 10 where b >= 2")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select a from t use index(idx_b) where b > 10").Check(testkit.Rows("2", "3"))
+
+	// Test multiple-table update
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (id int primary key, v int)")
+	tk.MustExec("create table t2 (id int primary key, v int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2)")
+	tk.MustExec("insert into t2 values (1, 10), (3, 30)")
+	tk.MustExec("update t1, t2 set t1.v = t2.v, t2.v = t2.v + 1 where t1.id = t2.id")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select * from t1").Check(testkit.Rows("1 10", "2 2"))
+	tk.MustQuery("select * from t2").Check(testkit.Rows("1 11", "3 30"))
+	tk.MustExec("update t1 left join t2 on t1.id = t2.id set t1.v = 0, t2.v = 0")
+	tk.MustQuery("select * from t1").Check(testkit.Rows("1 0", "2 0"))
+	tk.MustQuery("select * from t2").Check(testkit.Rows("1 0", "3 30"))
+
+	_, err = tk.Exec("update t1 a, t1 b set a.id = 3, b.id = 4 where a.id = b.id")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("update t1 set c = 1")
+	c.Assert(err, NotNil)
+}
+
 func (s *testSuite4) TestNotNullDefault(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test; drop table if exists t1,t2;")
@@ -76,11 +76,11 @@ func (s *testAggFuncSuit) TestAvg(c *C) {
 		c.Assert(err, IsNil)
 	}
 	result = avgFunc.GetResult(evalCtx)
-	c.Assert(result.GetInt64(), Equals, int64(67))
+	c.Assert(result.GetMysqlDecimal().Compare(types.NewDecFromInt(67)), Equals, 0)
 	err = avgFunc.Update(evalCtx, s.ctx.GetSessionVars().StmtCtx, s.nullRow)
 	c.Assert(err, IsNil)
 	result = avgFunc.GetResult(evalCtx)
-	c.Assert(result.GetInt64(), Equals, int64(67))
+	c.Assert(result.GetMysqlDecimal().Compare(types.NewDecFromInt(67)), Equals, 0)
 }
 
 func (s *testAggFuncSuit) TestAvgFinalMode(c *C) {
@@ -108,7 +108,7 @@ func (s *testAggFuncSuit) TestAvgFinalMode(c *C) {
 		c.Assert(err, IsNil)
 	}
 	result := avgFunc.GetResult(evalCtx)
-	c.Assert(result.GetInt64(), Equals, int64(67))
+	c.Assert(result.GetMysqlDecimal().Compare(types.NewDecFromInt(67)), Equals, 0)
 }
 
 func (s *testAggFuncSuit) TestSum(c *C) {
@@ -130,13 +130,13 @@ func (s *testAggFuncSuit) TestSum(c *C) {
 		c.Assert(err, IsNil)
 	}
 	result = sumFunc.GetResult(evalCtx)
-	c.Assert(result.GetInt64(), Equals, int64(338350))
+	c.Assert(result.GetMysqlDecimal().Compare(types.NewDecFromInt(338350)), Equals, 0)
 	err = sumFunc.Update(evalCtx, s.ctx.GetSessionVars().StmtCtx, s.nullRow)
 	c.Assert(err, IsNil)
 	result = sumFunc.GetResult(evalCtx)
-	c.Assert(result.GetInt64(), Equals, int64(338350))
+	c.Assert(result.GetMysqlDecimal().Compare(types.NewDecFromInt(338350)), Equals, 0)
 	partialResult := sumFunc.GetPartialResult(evalCtx)
-	c.Assert(partialResult[0].GetInt64(), Equals, int64(338350))
+	c.Assert(partialResult[0].GetMysqlDecimal().Compare(types.NewDecFromInt(338350)), Equals, 0)
 }
 
 func (s *testAggFuncSuit) TestCount(c *C) {
@@ -14,6 +14,8 @@
 package aggregation
 
 import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -67,9 +69,19 @@ func (af *avgFunction) GetResult(evalCtx *AggEvaluateContext) (d types.Datum) {
 		sum := evalCtx.Value.GetFloat64()
 		d.SetFloat64(sum / float64(evalCtx.Count))
 		return
-	case types.KindInt64:
-		sum := evalCtx.Value.GetInt64()
-		d.SetInt64(sum / evalCtx.Count)
+	case types.KindMysqlDecimal:
+		x := evalCtx.Value.GetMysqlDecimal()
+		y := types.NewDecFromInt(evalCtx.Count)
+		to := new(types.MyDecimal)
+		err := types.DecimalDiv(x, y, to, types.DivFracIncr)
+		terror.Log(err)
+		frac := af.RetTp.Decimal
+		if frac == types.UnspecifiedLength || frac > mysql.MaxDecimalScale {
+			frac = mysql.MaxDecimalScale
+		}
+		err = to.Round(to, frac, types.ModeHalfEven)
+		terror.Log(err)
+		d.SetMysqlDecimal(to)
 		return
 	}
 	return
@@ -47,6 +47,10 @@ func newBaseFuncDesc(ctx sessionctx.Context, name string, args []expression.Expr
 		return b, nil
 	}
 	for _, arg := range args {
+		// The integer arguments of a decimal "SUM" or "AVG" are casted by the executor.
+		if arg.GetType().EvalType() == types.ETInt && b.RetTp.EvalType() == types.ETDecimal {
+			continue
+		}
 		if arg.GetType().EvalType() != b.RetTp.EvalType() {
 			log.Warn(fmt.Sprintf("unmatched arg tp %v with return tp %v", arg.GetType().EvalType(), b.RetTp.EvalType()))
 		}
@@ -102,6 +106,12 @@ func (a *baseFuncDesc) typeInfer(ctx sessionctx.Context) error {
 		a.typeInfer4Avg(ctx)
 	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow:
 		a.typeInfer4MaxMin(ctx)
+	case ast.WindowFuncRowNumber, ast.WindowFuncRank, ast.WindowFuncDenseRank:
+		a.typeInfer4NumberFuncs()
+	case ast.WindowFuncNtile:
+		a.typeInfer4Ntile()
+	case ast.WindowFuncLead, ast.WindowFuncLag, ast.WindowFuncFirstValue, ast.WindowFuncLastValue:
+		a.typeInfer4ValueFuncs()
 	default:
 		return errors.Errorf("unsupported agg function: %s", a.Name)
 	}
@@ -119,7 +129,14 @@ func (a *baseFuncDesc) typeInfer4Count(ctx sessionctx.Context) {
 func (a *baseFuncDesc) typeInfer4Sum(ctx sessionctx.Context) {
 	switch a.Args[0].GetType().Tp {
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
-		a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+		a.RetTp = types.NewFieldType(mysql.TypeNewDecimal)
+		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxDecimalWidth, 0
+	case mysql.TypeNewDecimal:
+		a.RetTp = types.NewFieldType(mysql.TypeNewDecimal)
+		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxDecimalWidth, a.Args[0].GetType().Decimal
+		if a.RetTp.Decimal < 0 || a.RetTp.Decimal > mysql.MaxDecimalScale {
+			a.RetTp.Decimal = mysql.MaxDecimalScale
+		}
 	case mysql.TypeDouble, mysql.TypeFloat:
 		a.RetTp = types.NewFieldType(mysql.TypeDouble)
 		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxRealWidth, a.Args[0].GetType().Decimal
@@ -134,8 +151,12 @@ func (a *baseFuncDesc) typeInfer4Sum(ctx sessionctx.Context) {
 // Because child returns integer or decimal type.
 func (a *baseFuncDesc) typeInfer4Avg(ctx sessionctx.Context) {
 	switch a.Args[0].GetType().Tp {
-	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
-		a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeNewDecimal:
+		a.RetTp = types.NewFieldType(mysql.TypeNewDecimal)
+		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxDecimalWidth, a.Args[0].GetType().Decimal+types.DivFracIncr
+		if a.Args[0].GetType().Decimal < 0 || a.RetTp.Decimal > mysql.MaxDecimalScale {
+			a.RetTp.Decimal = mysql.MaxDecimalScale
+		}
 	case mysql.TypeDouble, mysql.TypeFloat:
 		a.RetTp = types.NewFieldType(mysql.TypeDouble)
 		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxRealWidth, a.Args[0].GetType().Decimal
@@ -157,6 +178,34 @@ func (a *baseFuncDesc) typeInfer4MaxMin(ctx sessionctx.Context) {
 	}
 }
 
+func (a *baseFuncDesc) typeInfer4NumberFuncs() {
+	a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	a.RetTp.Flen = 21
+	types.SetBinChsClnFlag(a.RetTp)
+}
+
+func (a *baseFuncDesc) typeInfer4Ntile() {
+	a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	a.RetTp.Flen = 21
+	types.SetBinChsClnFlag(a.RetTp)
+	a.RetTp.Flag |= mysql.UnsignedFlag
+}
+
+// typeInfer4ValueFuncs infers the type of the window functions returning the
+// value of another row, the value is NULL if the row doesn't exist.
+func (a *baseFuncDesc) typeInfer4ValueFuncs() {
+	if len(a.Args) > 2 {
+		// The default value of LEAD and LAG is merged.
+		a.RetTp = expression.InferType4ControlFuncs(a.Args[0].GetType(), a.Args[2].GetType())
+	} else {
+		a.RetTp = a.Args[0].GetType().Clone()
+	}
+	a.RetTp.Flag &^= mysql.NotNullFlag
+	if a.RetTp.Tp == mysql.TypeEnum || a.RetTp.Tp == mysql.TypeSet {
+		a.RetTp = &types.FieldType{Tp: mysql.TypeString, Flen: mysql.MaxFieldCharLength}
+	}
+}
+
 // GetDefaultValue gets the default value when the function's input is null.
 // According to MySQL, default values of the function are listed as follows:
 // e.g.
@@ -191,4 +240,10 @@ var noNeedCastAggFuncs = map[string]struct{}{
 	ast.AggFuncMax:      {},
 	ast.AggFuncMin:      {},
 	ast.AggFuncFirstRow: {},
+
+	ast.WindowFuncNtile:      {},
+	ast.WindowFuncLead:       {},
+	ast.WindowFuncLag:        {},
+	ast.WindowFuncFirstValue: {},
+	ast.WindowFuncLastValue:  {},
 }
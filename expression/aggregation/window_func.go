@@ -0,0 +1,75 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregation
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// WindowFuncDesc describes a window function signature, only used in planner.
+type WindowFuncDesc struct {
+	baseFuncDesc
+}
+
+// NewWindowFuncDesc creates a window function signature descriptor. It returns
+// nil if the arguments which must be constant are invalid.
+func NewWindowFuncDesc(ctx sessionctx.Context, name string, args []expression.Expression) (*WindowFuncDesc, error) {
+	switch strings.ToLower(name) {
+	case ast.WindowFuncNtile:
+		val, isNull, ok := expression.GetUint64FromConstant(args[0])
+		// NTILE doesn't allow 0, but allows NULL.
+		if !ok || (val == 0 && !isNull) {
+			return nil, nil
+		}
+	case ast.WindowFuncLead, ast.WindowFuncLag:
+		if len(args) < 2 {
+			break
+		}
+		_, isNull, ok := expression.GetUint64FromConstant(args[1])
+		if !ok || isNull {
+			return nil, nil
+		}
+	}
+	b, err := newBaseFuncDesc(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
+	return &WindowFuncDesc{baseFuncDesc: b}, nil
+}
+
+// Clone copies a window function signature totally.
+func (w *WindowFuncDesc) Clone() *WindowFuncDesc {
+	return &WindowFuncDesc{baseFuncDesc: *w.baseFuncDesc.clone()}
+}
+
+// noFrameWindowFuncs are the functions that operate on the entire partition,
+// the frame clauses of them are ignored.
+var noFrameWindowFuncs = map[string]struct{}{
+	ast.WindowFuncRowNumber: {},
+	ast.WindowFuncRank:      {},
+	ast.WindowFuncDenseRank: {},
+	ast.WindowFuncNtile:     {},
+	ast.WindowFuncLead:      {},
+	ast.WindowFuncLag:       {},
+}
+
+// NeedFrame checks whether the window function is evaluated on a frame.
+func NeedFrame(name string) bool {
+	_, ok := noFrameWindowFuncs[strings.ToLower(name)]
+	return !ok
+}
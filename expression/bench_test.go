@@ -73,8 +73,32 @@ func (g *defaultGener) gen() interface{} {
 			return -rand.Float64() * 1000000
 		}
 		return rand.Float64() * 1000000
+	case types.ETDecimal:
+		d := new(types.MyDecimal)
+		var f float64
+		if rand.Float64() < 0.5 {
+			f = rand.Float64() * 100000
+		} else {
+			f = -rand.Float64() * 100000
+		}
+		if err := d.FromFloat64(f); err != nil {
+			panic(err)
+		}
+		return d
+	case types.ETDatetime, types.ETTimestamp:
+		gt := types.FromDate(rand.Intn(2200), rand.Intn(10)+1, rand.Intn(20)+1, rand.Intn(12), rand.Intn(60), rand.Intn(60), rand.Intn(1000000))
+		t := types.NewTime(gt, convertETType(g.eType), 0)
+		return t
+	case types.ETDuration:
+		d := types.Duration{
+			// use rand.Int32() to make it not overflow when AddDuration
+			Duration: time.Duration(rand.Int31()),
+		}
+		return d
 	case types.ETString:
 		return randString()
+	case types.ETJson:
+		return randJSON()
 	}
 	return nil
 }
@@ -206,8 +230,16 @@ func fillColumnWithGener(eType types.EvalType, chk *chunk.Chunk, colIdx int, gen
 			col.AppendInt64(v.(int64))
 		case types.ETReal:
 			col.AppendFloat64(v.(float64))
+		case types.ETDecimal:
+			col.AppendMyDecimal(v.(*types.MyDecimal))
+		case types.ETDatetime, types.ETTimestamp:
+			col.AppendTime(v.(types.Time))
+		case types.ETDuration:
+			col.AppendDuration(v.(types.Duration))
 		case types.ETString:
 			col.AppendString(v.(string))
+		case types.ETJson:
+			col.AppendJSON(v.(types.BinaryJSON))
 		}
 	}
 }
@@ -228,14 +260,40 @@ func randString() string {
 	return string(buf)
 }
 
+func randJSON() types.BinaryJSON {
+	switch rand.Intn(4) {
+	case 0:
+		return types.CreateBinaryJSON(map[string]interface{}{
+			"a": rand.Int63n(100),
+			"b": randString(),
+			"c": []interface{}{rand.Float64(), nil, rand.Intn(2) == 0},
+		})
+	case 1:
+		return types.CreateBinaryJSON([]interface{}{rand.Int63n(100), randString()})
+	case 2:
+		return types.CreateBinaryJSON(randString())
+	}
+	return types.CreateBinaryJSON(rand.Int63n(1000))
+}
+
 func eType2FieldType(eType types.EvalType) *types.FieldType {
 	switch eType {
 	case types.ETInt:
 		return types.NewFieldType(mysql.TypeLonglong)
 	case types.ETReal:
 		return types.NewFieldType(mysql.TypeDouble)
+	case types.ETDecimal:
+		return types.NewFieldType(mysql.TypeNewDecimal)
+	case types.ETDatetime:
+		return types.NewFieldType(mysql.TypeDatetime)
+	case types.ETTimestamp:
+		return types.NewFieldType(mysql.TypeTimestamp)
+	case types.ETDuration:
+		return types.NewFieldType(mysql.TypeDuration)
 	case types.ETString:
 		return types.NewFieldType(mysql.TypeVarString)
+	case types.ETJson:
+		return types.NewFieldType(mysql.TypeJSON)
 	default:
 		panic(fmt.Sprintf("EvalType=%v is not supported.", eType))
 	}
@@ -305,6 +363,27 @@ func testVectorizedEvalOneVec(c *C, vecExprCases vecExprBenchCases) {
 						c.Assert(c1.GetFloat64(i), Equals, c2.GetFloat64(i), commentf(i))
 					}
 				}
+			case types.ETDecimal:
+				for i := 0; i < input.NumRows(); i++ {
+					c.Assert(c1.IsNull(i), Equals, c2.IsNull(i), commentf(i))
+					if !c1.IsNull(i) {
+						c.Assert(c1.GetDecimal(i), DeepEquals, c2.GetDecimal(i), commentf(i))
+					}
+				}
+			case types.ETDatetime, types.ETTimestamp:
+				for i := 0; i < input.NumRows(); i++ {
+					c.Assert(c1.IsNull(i), Equals, c2.IsNull(i), commentf(i))
+					if !c1.IsNull(i) {
+						c.Assert(c1.GetTime(i).Compare(c2.GetTime(i)), Equals, 0, commentf(i))
+					}
+				}
+			case types.ETDuration:
+				for i := 0; i < input.NumRows(); i++ {
+					c.Assert(c1.IsNull(i), Equals, c2.IsNull(i), commentf(i))
+					if !c1.IsNull(i) {
+						c.Assert(c1.GetDuration(i, 0), Equals, c2.GetDuration(i, 0), commentf(i))
+					}
+				}
 			case types.ETString:
 				for i := 0; i < input.NumRows(); i++ {
 					c.Assert(c1.IsNull(i), Equals, c2.IsNull(i), commentf(i))
@@ -312,6 +391,13 @@ func testVectorizedEvalOneVec(c *C, vecExprCases vecExprBenchCases) {
 						c.Assert(c1.GetString(i), Equals, c2.GetString(i), commentf(i))
 					}
 				}
+			case types.ETJson:
+				for i := 0; i < input.NumRows(); i++ {
+					c.Assert(c1.IsNull(i), Equals, c2.IsNull(i), commentf(i))
+					if !c1.IsNull(i) {
+						c.Assert(types.CompareBinaryJSON(c1.GetJSON(i), c2.GetJSON(i)), Equals, 0, commentf(i))
+					}
+				}
 			}
 		}
 	}
@@ -379,7 +465,12 @@ func genVecBuiltinFuncBenchCase(ctx sessionctx.Context, funcName string, testCas
 		input.SetNumVirtualRows(testCase.chunkSize)
 	}
 
-	baseFunc, err := funcs[funcName].getFunction(ctx, cols)
+	var err error
+	if funcName == ast.Cast {
+		baseFunc, err = getCastFunctionClass(eType2FieldType(testCase.retEvalType)).getFunction(ctx, cols)
+	} else {
+		baseFunc, err = funcs[funcName].getFunction(ctx, cols)
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -473,6 +564,54 @@ func testVectorizedBuiltinFunc(c *C, vecExprCases vecExprBenchCases) {
 					}
 					i++
 				}
+			case types.ETDecimal:
+				err := baseFunc.vecEvalDecimal(input, output)
+				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
+				// do not forget to call ResizeXXX/ReserveXXX
+				c.Assert(getColumnLen(output, testCase.retEvalType), Equals, input.NumRows())
+				vecWarnCnt = ctx.GetSessionVars().StmtCtx.WarningCount()
+				d64s := output.Decimals()
+				for row := it.Begin(); row != it.End(); row = it.Next() {
+					val, isNull, err := baseFunc.evalDecimal(row)
+					c.Assert(err, IsNil, commentf(i))
+					c.Assert(isNull, Equals, output.IsNull(i), commentf(i))
+					if !isNull {
+						c.Assert(val.Compare(&d64s[i]), Equals, 0, commentf(i))
+					}
+					i++
+				}
+			case types.ETDatetime, types.ETTimestamp:
+				err := baseFunc.vecEvalTime(input, output)
+				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
+				// do not forget to call ResizeXXX/ReserveXXX
+				c.Assert(getColumnLen(output, testCase.retEvalType), Equals, input.NumRows())
+				vecWarnCnt = ctx.GetSessionVars().StmtCtx.WarningCount()
+				t64s := output.Times()
+				for row := it.Begin(); row != it.End(); row = it.Next() {
+					val, isNull, err := baseFunc.evalTime(row)
+					c.Assert(err, IsNil, commentf(i))
+					c.Assert(isNull, Equals, output.IsNull(i), commentf(i))
+					if !isNull {
+						c.Assert(val.Compare(t64s[i]), Equals, 0, commentf(i))
+					}
+					i++
+				}
+			case types.ETDuration:
+				err := baseFunc.vecEvalDuration(input, output)
+				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
+				// do not forget to call ResizeXXX/ReserveXXX
+				c.Assert(getColumnLen(output, testCase.retEvalType), Equals, input.NumRows())
+				vecWarnCnt = ctx.GetSessionVars().StmtCtx.WarningCount()
+				d64s := output.GoDurations()
+				for row := it.Begin(); row != it.End(); row = it.Next() {
+					val, isNull, err := baseFunc.evalDuration(row)
+					c.Assert(err, IsNil, commentf(i))
+					c.Assert(isNull, Equals, output.IsNull(i), commentf(i))
+					if !isNull {
+						c.Assert(val.Duration, Equals, d64s[i], commentf(i))
+					}
+					i++
+				}
 			case types.ETString:
 				err := baseFunc.vecEvalString(input, output)
 				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
@@ -488,6 +627,21 @@ func testVectorizedBuiltinFunc(c *C, vecExprCases vecExprBenchCases) {
 					}
 					i++
 				}
+			case types.ETJson:
+				err := baseFunc.vecEvalJSON(input, output)
+				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
+				// do not forget to call ResizeXXX/ReserveXXX
+				c.Assert(getColumnLen(output, testCase.retEvalType), Equals, input.NumRows())
+				vecWarnCnt = ctx.GetSessionVars().StmtCtx.WarningCount()
+				for row := it.Begin(); row != it.End(); row = it.Next() {
+					val, isNull, err := baseFunc.evalJSON(row)
+					c.Assert(err, IsNil, commentf(i))
+					c.Assert(isNull, Equals, output.IsNull(i), commentf(i))
+					if !isNull {
+						c.Assert(types.CompareBinaryJSON(val, output.GetJSON(i)), Equals, 0, commentf(i))
+					}
+					i++
+				}
 			default:
 				c.Fatal(fmt.Sprintf("evalType=%v is not supported", testCase.retEvalType))
 			}
@@ -543,12 +697,36 @@ func benchmarkVectorizedBuiltinFunc(b *testing.B, vecExprCases vecExprBenchCases
 							b.Fatal(err)
 						}
 					}
+				case types.ETDecimal:
+					for i := 0; i < b.N; i++ {
+						if err := baseFunc.vecEvalDecimal(input, output); err != nil {
+							b.Fatal(err)
+						}
+					}
+				case types.ETDatetime, types.ETTimestamp:
+					for i := 0; i < b.N; i++ {
+						if err := baseFunc.vecEvalTime(input, output); err != nil {
+							b.Fatal(err)
+						}
+					}
+				case types.ETDuration:
+					for i := 0; i < b.N; i++ {
+						if err := baseFunc.vecEvalDuration(input, output); err != nil {
+							b.Fatal(err)
+						}
+					}
 				case types.ETString:
 					for i := 0; i < b.N; i++ {
 						if err := baseFunc.vecEvalString(input, output); err != nil {
 							b.Fatal(err)
 						}
 					}
+				case types.ETJson:
+					for i := 0; i < b.N; i++ {
+						if err := baseFunc.vecEvalJSON(input, output); err != nil {
+							b.Fatal(err)
+						}
+					}
 				default:
 					b.Fatal(fmt.Sprintf("evalType=%v is not supported", testCase.retEvalType))
 				}
@@ -587,6 +765,51 @@ func benchmarkVectorizedBuiltinFunc(b *testing.B, vecExprCases vecExprBenchCases
 							}
 						}
 					}
+				case types.ETDecimal:
+					for i := 0; i < b.N; i++ {
+						output.Reset(testCase.retEvalType)
+						for row := it.Begin(); row != it.End(); row = it.Next() {
+							v, isNull, err := baseFunc.evalDecimal(row)
+							if err != nil {
+								b.Fatal(err)
+							}
+							if isNull {
+								output.AppendNull()
+							} else {
+								output.AppendMyDecimal(v)
+							}
+						}
+					}
+				case types.ETDatetime, types.ETTimestamp:
+					for i := 0; i < b.N; i++ {
+						output.Reset(testCase.retEvalType)
+						for row := it.Begin(); row != it.End(); row = it.Next() {
+							v, isNull, err := baseFunc.evalTime(row)
+							if err != nil {
+								b.Fatal(err)
+							}
+							if isNull {
+								output.AppendNull()
+							} else {
+								output.AppendTime(v)
+							}
+						}
+					}
+				case types.ETDuration:
+					for i := 0; i < b.N; i++ {
+						output.Reset(testCase.retEvalType)
+						for row := it.Begin(); row != it.End(); row = it.Next() {
+							v, isNull, err := baseFunc.evalDuration(row)
+							if err != nil {
+								b.Fatal(err)
+							}
+							if isNull {
+								output.AppendNull()
+							} else {
+								output.AppendDuration(v)
+							}
+						}
+					}
 				case types.ETString:
 					for i := 0; i < b.N; i++ {
 						output.Reset(testCase.retEvalType)
@@ -602,6 +825,21 @@ func benchmarkVectorizedBuiltinFunc(b *testing.B, vecExprCases vecExprBenchCases
 							}
 						}
 					}
+				case types.ETJson:
+					for i := 0; i < b.N; i++ {
+						output.Reset(testCase.retEvalType)
+						for row := it.Begin(); row != it.End(); row = it.Next() {
+							v, isNull, err := baseFunc.evalJSON(row)
+							if err != nil {
+								b.Fatal(err)
+							}
+							if isNull {
+								output.AppendNull()
+							} else {
+								output.AppendJSON(v)
+							}
+						}
+					}
 				default:
 					b.Fatal(fmt.Sprintf("evalType=%v is not supported", testCase.retEvalType))
 				}
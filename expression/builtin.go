@@ -18,6 +18,7 @@
 //go:generate go run generator/compare_vec.go
 //go:generate go run generator/control_vec.go
 //go:generate go run generator/other_vec.go
+//go:generate go run generator/string_vec.go
 
 package expression
 
@@ -94,6 +95,22 @@ func newBaseBuiltinFuncWithTp(ctx sessionctx.Context, args []Expression, retType
 		panic("ctx should not be nil")
 	}
 	for i := range args {
+		switch argTps[i] {
+		case types.ETInt:
+			args[i] = WrapWithCastAsInt(ctx, args[i])
+		case types.ETReal:
+			args[i] = WrapWithCastAsReal(ctx, args[i])
+		case types.ETDecimal:
+			args[i] = WrapWithCastAsDecimal(ctx, args[i])
+		case types.ETString:
+			args[i] = WrapWithCastAsString(ctx, args[i])
+		case types.ETDatetime, types.ETTimestamp:
+			args[i] = WrapWithCastAsTime(ctx, args[i], &types.FieldType{Tp: mysql.TypeDatetime, Decimal: int(types.MaxFsp)})
+		case types.ETDuration:
+			args[i] = WrapWithCastAsDuration(ctx, args[i])
+		case types.ETJson:
+			args[i] = WrapWithCastAsJSON(ctx, args[i])
+		}
 		if argTps[i] != args[i].GetType().EvalType() {
 			log.Warn(fmt.Sprintf("unmatched arg type %v with %v", argTps[i], args[i].GetType().EvalType()))
 		}
@@ -114,12 +131,47 @@ func newBaseBuiltinFuncWithTp(ctx sessionctx.Context, args []Expression, retType
 			Decimal: types.UnspecifiedLength,
 			Flag:    mysql.BinaryFlag,
 		}
+	case types.ETDecimal:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeNewDecimal,
+			Flen:    11,
+			Decimal: 0,
+			Flag:    mysql.BinaryFlag,
+		}
 	case types.ETString:
 		fieldType = &types.FieldType{
 			Tp:      mysql.TypeVarString,
 			Flen:    0,
 			Decimal: types.UnspecifiedLength,
 		}
+	case types.ETDatetime:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeDatetime,
+			Flen:    mysql.MaxDatetimeWidthWithFsp,
+			Decimal: int(types.MaxFsp),
+			Flag:    mysql.BinaryFlag,
+		}
+	case types.ETTimestamp:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeTimestamp,
+			Flen:    mysql.MaxDatetimeWidthWithFsp,
+			Decimal: int(types.MaxFsp),
+			Flag:    mysql.BinaryFlag,
+		}
+	case types.ETDuration:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeDuration,
+			Flen:    mysql.MaxDurationWidthWithFsp,
+			Decimal: int(types.MaxFsp),
+			Flag:    mysql.BinaryFlag,
+		}
+	case types.ETJson:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeJSON,
+			Flen:    mysql.MaxBlobWidth,
+			Decimal: 0,
+			Flag:    mysql.BinaryFlag,
+		}
 	}
 	if mysql.HasBinaryFlag(fieldType.Flag) {
 		fieldType.Charset, fieldType.Collate = charset.CharsetBin, charset.CollationBin
@@ -180,6 +232,22 @@ func (b *baseBuiltinFunc) evalString(row chunk.Row) (string, bool, error) {
 	return "", false, errors.Errorf("baseBuiltinFunc.evalString() should never be called, please contact the TiDB team for help")
 }
 
+func (b *baseBuiltinFunc) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	return nil, false, errors.Errorf("baseBuiltinFunc.evalDecimal() should never be called, please contact the TiDB team for help")
+}
+
+func (b *baseBuiltinFunc) evalTime(row chunk.Row) (types.Time, bool, error) {
+	return types.ZeroTime, false, errors.Errorf("baseBuiltinFunc.evalTime() should never be called, please contact the TiDB team for help")
+}
+
+func (b *baseBuiltinFunc) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	return types.Duration{}, false, errors.Errorf("baseBuiltinFunc.evalDuration() should never be called, please contact the TiDB team for help")
+}
+
+func (b *baseBuiltinFunc) evalJSON(row chunk.Row) (types.BinaryJSON, bool, error) {
+	return types.BinaryJSON{}, false, errors.Errorf("baseBuiltinFunc.evalJSON() should never be called, please contact the TiDB team for help")
+}
+
 func (b *baseBuiltinFunc) vectorized() bool {
 	return false
 }
@@ -285,6 +353,14 @@ type builtinFunc interface {
 	evalReal(row chunk.Row) (val float64, isNull bool, err error)
 	// evalString evaluates string representation of builtinFunc by given row.
 	evalString(row chunk.Row) (val string, isNull bool, err error)
+	// evalDecimal evaluates decimal representation of builtinFunc by given row.
+	evalDecimal(row chunk.Row) (val *types.MyDecimal, isNull bool, err error)
+	// evalTime evaluates DATE/DATETIME/TIMESTAMP representation of builtinFunc by given row.
+	evalTime(row chunk.Row) (val types.Time, isNull bool, err error)
+	// evalDuration evaluates duration representation of builtinFunc by given row.
+	evalDuration(row chunk.Row) (val types.Duration, isNull bool, err error)
+	// evalJSON evaluates JSON representation of builtinFunc by given row.
+	evalJSON(row chunk.Row) (val types.BinaryJSON, isNull bool, err error)
 	// getArgs returns the arguments expressions.
 	getArgs() []Expression
 	// equal check if this function equals to another function.
@@ -333,12 +409,112 @@ var funcs = map[string]functionClass{
 	// common functions
 	ast.IsNull: &isNullFunctionClass{baseFunctionClass{ast.IsNull, 1, 1}},
 
+	// math functions
+	ast.Abs:      &absFunctionClass{baseFunctionClass{ast.Abs, 1, 1}},
+	ast.Acos:     &acosFunctionClass{baseFunctionClass{ast.Acos, 1, 1}},
+	ast.Asin:     &asinFunctionClass{baseFunctionClass{ast.Asin, 1, 1}},
+	ast.Atan:     &atanFunctionClass{baseFunctionClass{ast.Atan, 1, 2}},
+	ast.Atan2:    &atanFunctionClass{baseFunctionClass{ast.Atan2, 2, 2}},
+	ast.Ceil:     &ceilFunctionClass{baseFunctionClass{ast.Ceil, 1, 1}},
+	ast.Ceiling:  &ceilFunctionClass{baseFunctionClass{ast.Ceiling, 1, 1}},
+	ast.Conv:     &convFunctionClass{baseFunctionClass{ast.Conv, 3, 3}},
+	ast.Cos:      &cosFunctionClass{baseFunctionClass{ast.Cos, 1, 1}},
+	ast.Cot:      &cotFunctionClass{baseFunctionClass{ast.Cot, 1, 1}},
+	ast.CRC32:    &crc32FunctionClass{baseFunctionClass{ast.CRC32, 1, 1}},
+	ast.Degrees:  &degreesFunctionClass{baseFunctionClass{ast.Degrees, 1, 1}},
+	ast.Exp:      &expFunctionClass{baseFunctionClass{ast.Exp, 1, 1}},
+	ast.Floor:    &floorFunctionClass{baseFunctionClass{ast.Floor, 1, 1}},
+	ast.Ln:       &logFunctionClass{baseFunctionClass{ast.Ln, 1, 1}},
+	ast.Log:      &logFunctionClass{baseFunctionClass{ast.Log, 1, 2}},
+	ast.Log2:     &log2FunctionClass{baseFunctionClass{ast.Log2, 1, 1}},
+	ast.Log10:    &log10FunctionClass{baseFunctionClass{ast.Log10, 1, 1}},
+	ast.PI:       &piFunctionClass{baseFunctionClass{ast.PI, 0, 0}},
+	ast.Pow:      &powFunctionClass{baseFunctionClass{ast.Pow, 2, 2}},
+	ast.Power:    &powFunctionClass{baseFunctionClass{ast.Power, 2, 2}},
+	ast.Radians:  &radiansFunctionClass{baseFunctionClass{ast.Radians, 1, 1}},
+	ast.Rand:     &randFunctionClass{baseFunctionClass{ast.Rand, 0, 1}},
+	ast.Round:    &roundFunctionClass{baseFunctionClass{ast.Round, 1, 2}},
+	ast.Sign:     &signFunctionClass{baseFunctionClass{ast.Sign, 1, 1}},
+	ast.Sin:      &sinFunctionClass{baseFunctionClass{ast.Sin, 1, 1}},
+	ast.Sqrt:     &sqrtFunctionClass{baseFunctionClass{ast.Sqrt, 1, 1}},
+	ast.Tan:      &tanFunctionClass{baseFunctionClass{ast.Tan, 1, 1}},
+	ast.Truncate: &truncateFunctionClass{baseFunctionClass{ast.Truncate, 2, 2}},
+
 	// string functions
-	ast.Length:      &lengthFunctionClass{baseFunctionClass{ast.Length, 1, 1}},
-	ast.OctetLength: &lengthFunctionClass{baseFunctionClass{ast.OctetLength, 1, 1}},
-	ast.Strcmp:      &strcmpFunctionClass{baseFunctionClass{ast.Strcmp, 2, 2}},
+	ast.Length:          &lengthFunctionClass{baseFunctionClass{ast.Length, 1, 1}},
+	ast.OctetLength:     &lengthFunctionClass{baseFunctionClass{ast.OctetLength, 1, 1}},
+	ast.Strcmp:          &strcmpFunctionClass{baseFunctionClass{ast.Strcmp, 2, 2}},
+	ast.CharLength:      &charLengthFunctionClass{baseFunctionClass{ast.CharLength, 1, 1}},
+	ast.CharacterLength: &charLengthFunctionClass{baseFunctionClass{ast.CharacterLength, 1, 1}},
+	ast.Concat:          &concatFunctionClass{baseFunctionClass{ast.Concat, 1, -1}},
+	ast.ConcatWS:        &concatWSFunctionClass{baseFunctionClass{ast.ConcatWS, 2, -1}},
+	ast.Convert:         &convertFunctionClass{baseFunctionClass{ast.Convert, 2, 2}},
+	ast.Elt:             &eltFunctionClass{baseFunctionClass{ast.Elt, 2, -1}},
+	ast.Field:           &fieldFunctionClass{baseFunctionClass{ast.Field, 2, -1}},
+	ast.Format:          &formatFunctionClass{baseFunctionClass{ast.Format, 2, 3}},
+	ast.Hex:             &hexFunctionClass{baseFunctionClass{ast.Hex, 1, 1}},
+	ast.Instr:           &instrFunctionClass{baseFunctionClass{ast.Instr, 2, 2}},
+	ast.Lcase:           &lowerFunctionClass{baseFunctionClass{ast.Lcase, 1, 1}},
+	ast.Left:            &leftFunctionClass{baseFunctionClass{ast.Left, 2, 2}},
+	ast.Locate:          &locateFunctionClass{baseFunctionClass{ast.Locate, 2, 3}},
+	ast.Lower:           &lowerFunctionClass{baseFunctionClass{ast.Lower, 1, 1}},
+	ast.Lpad:            &lpadFunctionClass{baseFunctionClass{ast.Lpad, 3, 3}},
+	ast.LTrim:           &lTrimFunctionClass{baseFunctionClass{ast.LTrim, 1, 1}},
+	ast.Position:        &locateFunctionClass{baseFunctionClass{ast.Position, 2, 2}},
+	ast.Repeat:          &repeatFunctionClass{baseFunctionClass{ast.Repeat, 2, 2}},
+	ast.Replace:         &replaceFunctionClass{baseFunctionClass{ast.Replace, 3, 3}},
+	ast.Reverse:         &reverseFunctionClass{baseFunctionClass{ast.Reverse, 1, 1}},
+	ast.Right:           &rightFunctionClass{baseFunctionClass{ast.Right, 2, 2}},
+	ast.RTrim:           &rTrimFunctionClass{baseFunctionClass{ast.RTrim, 1, 1}},
+	ast.Rpad:            &rpadFunctionClass{baseFunctionClass{ast.Rpad, 3, 3}},
+	ast.Substr:          &substringFunctionClass{baseFunctionClass{ast.Substr, 2, 3}},
+	ast.Substring:       &substringFunctionClass{baseFunctionClass{ast.Substring, 2, 3}},
+	ast.Trim:            &trimFunctionClass{baseFunctionClass{ast.Trim, 1, 3}},
+	ast.Ucase:           &upperFunctionClass{baseFunctionClass{ast.Ucase, 1, 1}},
+	ast.Unhex:           &unhexFunctionClass{baseFunctionClass{ast.Unhex, 1, 1}},
+	ast.Upper:           &upperFunctionClass{baseFunctionClass{ast.Upper, 1, 1}},
+
+	// like and regexp functions
+	ast.Like:          &likeFunctionClass{baseFunctionClass{ast.Like, 3, 3}},
+	ast.Regexp:        &regexpFunctionClass{baseFunctionClass{ast.Regexp, 2, 2}},
+	ast.RegexpLike:    &regexpLikeFunctionClass{baseFunctionClass{ast.RegexpLike, 2, 3}},
+	ast.RegexpSubstr:  &regexpSubstrFunctionClass{baseFunctionClass{ast.RegexpSubstr, 2, 5}},
+	ast.RegexpReplace: &regexpReplaceFunctionClass{baseFunctionClass{ast.RegexpReplace, 3, 6}},
+
+	// json functions
+	ast.JSONType:     &jsonTypeFunctionClass{baseFunctionClass{ast.JSONType, 1, 1}},
+	ast.JSONExtract:  &jsonExtractFunctionClass{baseFunctionClass{ast.JSONExtract, 2, -1}},
+	ast.JSONUnquote:  &jsonUnquoteFunctionClass{baseFunctionClass{ast.JSONUnquote, 1, 1}},
+	ast.JSONArray:    &jsonArrayFunctionClass{baseFunctionClass{ast.JSONArray, 0, -1}},
+	ast.JSONObject:   &jsonObjectFunctionClass{baseFunctionClass{ast.JSONObject, 0, -1}},
+	ast.JSONSet:      &jsonSetFunctionClass{baseFunctionClass{ast.JSONSet, 3, -1}},
+	ast.JSONInsert:   &jsonInsertFunctionClass{baseFunctionClass{ast.JSONInsert, 3, -1}},
+	ast.JSONReplace:  &jsonReplaceFunctionClass{baseFunctionClass{ast.JSONReplace, 3, -1}},
+	ast.JSONRemove:   &jsonRemoveFunctionClass{baseFunctionClass{ast.JSONRemove, 2, -1}},
+	ast.JSONContains: &jsonContainsFunctionClass{baseFunctionClass{ast.JSONContains, 2, 3}},
+	ast.JSONKeys:     &jsonKeysFunctionClass{baseFunctionClass{ast.JSONKeys, 1, 2}},
+	ast.JSONLength:   &jsonLengthFunctionClass{baseFunctionClass{ast.JSONLength, 1, 2}},
+
+	// time functions
+	ast.Now:              &nowFunctionClass{baseFunctionClass{ast.Now, 0, 1}},
+	ast.CurrentTimestamp: &nowFunctionClass{baseFunctionClass{ast.CurrentTimestamp, 0, 1}},
+	ast.DateFormat:       &dateFormatFunctionClass{baseFunctionClass{ast.DateFormat, 2, 2}},
+	ast.DateAdd:          &dateArithFunctionClass{baseFunctionClass{ast.DateAdd, 3, 3}, false},
+	ast.AddDate:          &dateArithFunctionClass{baseFunctionClass{ast.AddDate, 3, 3}, false},
+	ast.DateSub:          &dateArithFunctionClass{baseFunctionClass{ast.DateSub, 3, 3}, true},
+	ast.SubDate:          &dateArithFunctionClass{baseFunctionClass{ast.SubDate, 3, 3}, true},
+	ast.DateDiff:         &dateDiffFunctionClass{baseFunctionClass{ast.DateDiff, 2, 2}},
+	ast.Extract:          &extractFunctionClass{baseFunctionClass{ast.Extract, 2, 2}},
+	ast.UnixTimestamp:    &unixTimestampFunctionClass{baseFunctionClass{ast.UnixTimestamp, 0, 1}},
+	ast.FromUnixTime:     &fromUnixTimeFunctionClass{baseFunctionClass{ast.FromUnixTime, 1, 2}},
+
+	// compare functions
+	ast.Coalesce: &coalesceFunctionClass{baseFunctionClass{ast.Coalesce, 1, -1}},
+	ast.Greatest: &greatestFunctionClass{baseFunctionClass{ast.Greatest, 2, -1}},
+	ast.Least:    &leastFunctionClass{baseFunctionClass{ast.Least, 2, -1}},
 
 	// control functions
+	ast.Case:   &caseWhenFunctionClass{baseFunctionClass{ast.Case, 2, -1}},
 	ast.If:     &ifFunctionClass{baseFunctionClass{ast.If, 3, 3}},
 	ast.Ifnull: &ifNullFunctionClass{baseFunctionClass{ast.Ifnull, 2, 2}},
 
@@ -348,12 +524,21 @@ var funcs = map[string]functionClass{
 	ast.LE:         &compareFunctionClass{baseFunctionClass{ast.LE, 2, 2}, opcode.LE},
 	ast.EQ:         &compareFunctionClass{baseFunctionClass{ast.EQ, 2, 2}, opcode.EQ},
 	ast.NE:         &compareFunctionClass{baseFunctionClass{ast.NE, 2, 2}, opcode.NE},
+	ast.NullEQ:     &compareFunctionClass{baseFunctionClass{ast.NullEQ, 2, 2}, opcode.NullEQ},
 	ast.LT:         &compareFunctionClass{baseFunctionClass{ast.LT, 2, 2}, opcode.LT},
 	ast.GT:         &compareFunctionClass{baseFunctionClass{ast.GT, 2, 2}, opcode.GT},
 	ast.Plus:       &arithmeticPlusFunctionClass{baseFunctionClass{ast.Plus, 2, 2}},
 	ast.Minus:      &arithmeticMinusFunctionClass{baseFunctionClass{ast.Minus, 2, 2}},
 	ast.Div:        &arithmeticDivideFunctionClass{baseFunctionClass{ast.Div, 2, 2}},
 	ast.Mul:        &arithmeticMultiplyFunctionClass{baseFunctionClass{ast.Mul, 2, 2}},
+	ast.IntDiv:     &arithmeticIntDivideFunctionClass{baseFunctionClass{ast.IntDiv, 2, 2}},
+	ast.Mod:        &arithmeticModFunctionClass{baseFunctionClass{ast.Mod, 2, 2}},
+	ast.And:        &bitAndFunctionClass{baseFunctionClass{ast.And, 2, 2}},
+	ast.Or:         &bitOrFunctionClass{baseFunctionClass{ast.Or, 2, 2}},
+	ast.Xor:        &bitXorFunctionClass{baseFunctionClass{ast.Xor, 2, 2}},
+	ast.LeftShift:  &leftShiftFunctionClass{baseFunctionClass{ast.LeftShift, 2, 2}},
+	ast.RightShift: &rightShiftFunctionClass{baseFunctionClass{ast.RightShift, 2, 2}},
+	ast.BitNeg:     &bitNegFunctionClass{baseFunctionClass{ast.BitNeg, 1, 1}},
 	ast.UnaryNot:   &unaryNotFunctionClass{baseFunctionClass{ast.UnaryNot, 1, 1}},
 	ast.UnaryMinus: &unaryMinusFunctionClass{baseFunctionClass{ast.UnaryMinus, 1, 1}},
 	ast.In:         &inFunctionClass{baseFunctionClass{ast.In, 2, -1}},
@@ -30,17 +30,28 @@ var (
 	_ functionClass = &arithmeticMinusFunctionClass{}
 	_ functionClass = &arithmeticDivideFunctionClass{}
 	_ functionClass = &arithmeticMultiplyFunctionClass{}
+	_ functionClass = &arithmeticIntDivideFunctionClass{}
+	_ functionClass = &arithmeticModFunctionClass{}
 )
 
 var (
 	_ builtinFunc = &builtinArithmeticPlusRealSig{}
+	_ builtinFunc = &builtinArithmeticPlusDecimalSig{}
 	_ builtinFunc = &builtinArithmeticPlusIntSig{}
 	_ builtinFunc = &builtinArithmeticMinusRealSig{}
+	_ builtinFunc = &builtinArithmeticMinusDecimalSig{}
 	_ builtinFunc = &builtinArithmeticMinusIntSig{}
 	_ builtinFunc = &builtinArithmeticDivideRealSig{}
+	_ builtinFunc = &builtinArithmeticDivideDecimalSig{}
 	_ builtinFunc = &builtinArithmeticMultiplyRealSig{}
+	_ builtinFunc = &builtinArithmeticMultiplyDecimalSig{}
 	_ builtinFunc = &builtinArithmeticMultiplyIntUnsignedSig{}
 	_ builtinFunc = &builtinArithmeticMultiplyIntSig{}
+	_ builtinFunc = &builtinArithmeticIntDivideIntSig{}
+	_ builtinFunc = &builtinArithmeticIntDivideDecimalSig{}
+	_ builtinFunc = &builtinArithmeticModIntSig{}
+	_ builtinFunc = &builtinArithmeticModRealSig{}
+	_ builtinFunc = &builtinArithmeticModDecimalSig{}
 )
 
 // numericContextResultType returns types.EvalType for numeric function's parameters.
@@ -52,7 +63,7 @@ func numericContextResultType(ft *types.FieldType) types.EvalType {
 	evalTp4Ft := types.ETReal
 	if !ft.Hybrid() {
 		evalTp4Ft = ft.EvalType()
-		if evalTp4Ft != types.ETInt {
+		if evalTp4Ft != types.ETDecimal && evalTp4Ft != types.ETInt {
 			evalTp4Ft = types.ETReal
 		}
 	}
@@ -100,6 +111,28 @@ func setFlenDecimal4RealOrDecimal(retTp, a, b *types.FieldType, isReal bool, isM
 	}
 }
 
+func (c *arithmeticDivideFunctionClass) setType4DivDecimal(retTp, a, b *types.FieldType) {
+	var deca, decb = a.Decimal, b.Decimal
+	if deca == types.UnspecifiedLength {
+		deca = 0
+	}
+	if decb == types.UnspecifiedLength {
+		decb = 0
+	}
+	retTp.Decimal = deca + types.DivFracIncr
+	if retTp.Decimal > mysql.MaxDecimalScale {
+		retTp.Decimal = mysql.MaxDecimalScale
+	}
+	if a.Flen == types.UnspecifiedLength {
+		retTp.Flen = mysql.MaxDecimalWidth
+		return
+	}
+	retTp.Flen = a.Flen + decb + types.DivFracIncr
+	if retTp.Flen > mysql.MaxDecimalWidth {
+		retTp.Flen = mysql.MaxDecimalWidth
+	}
+}
+
 func (c *arithmeticDivideFunctionClass) setType4DivReal(retTp *types.FieldType) {
 	retTp.Decimal = types.UnspecifiedLength
 	retTp.Flen = mysql.MaxRealWidth
@@ -121,6 +154,12 @@ func (c *arithmeticPlusFunctionClass) getFunction(ctx sessionctx.Context, args [
 		sig := &builtinArithmeticPlusRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_PlusReal)
 		return sig, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), false, false)
+		sig := &builtinArithmeticPlusDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_PlusDecimal)
+		return sig, nil
 	}
 	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
 	if mysql.HasUnsignedFlag(args[0].GetType().Flag) || mysql.HasUnsignedFlag(args[1].GetType().Flag) {
@@ -184,6 +223,36 @@ func (s *builtinArithmeticPlusIntSig) evalInt(row chunk.Row) (val int64, isNull
 	return a + b, false, nil
 }
 
+type builtinArithmeticPlusDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticPlusDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticPlusDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticPlusDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	c := &types.MyDecimal{}
+	err = types.DecimalAdd(a, b, c)
+	if err != nil {
+		if err == types.ErrOverflow {
+			err = types.ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%s + %s)", s.args[0].String(), s.args[1].String()))
+		}
+		return nil, true, err
+	}
+	return c, false, nil
+}
+
 type builtinArithmeticPlusRealSig struct {
 	baseBuiltinFunc
 }
@@ -225,6 +294,12 @@ func (c *arithmeticMinusFunctionClass) getFunction(ctx sessionctx.Context, args
 		sig := &builtinArithmeticMinusRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_MinusReal)
 		return sig, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), false, false)
+		sig := &builtinArithmeticMinusDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_MinusDecimal)
+		return sig, nil
 	}
 	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
 	setFlenDecimal4Int(bf.tp, args[0].GetType(), args[1].GetType())
@@ -261,6 +336,36 @@ func (s *builtinArithmeticMinusRealSig) evalReal(row chunk.Row) (float64, bool,
 	return a - b, false, nil
 }
 
+type builtinArithmeticMinusDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticMinusDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticMinusDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticMinusDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	c := &types.MyDecimal{}
+	err = types.DecimalSub(a, b, c)
+	if err != nil {
+		if err == types.ErrOverflow {
+			err = types.ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%s - %s)", s.args[0].String(), s.args[1].String()))
+		}
+		return nil, true, err
+	}
+	return c, false, nil
+}
+
 type builtinArithmeticMinusIntSig struct {
 	baseBuiltinFunc
 }
@@ -339,6 +444,12 @@ func (c *arithmeticMultiplyFunctionClass) getFunction(ctx sessionctx.Context, ar
 		sig := &builtinArithmeticMultiplyRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_MultiplyReal)
 		return sig, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), false, true)
+		sig := &builtinArithmeticMultiplyDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_MultiplyDecimal)
+		return sig, nil
 	}
 	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
 	if mysql.HasUnsignedFlag(lhsTp.Flag) || mysql.HasUnsignedFlag(rhsTp.Flag) {
@@ -362,6 +473,14 @@ func (s *builtinArithmeticMultiplyRealSig) Clone() builtinFunc {
 	return newSig
 }
 
+type builtinArithmeticMultiplyDecimalSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticMultiplyDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticMultiplyDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
 type builtinArithmeticMultiplyIntUnsignedSig struct{ baseBuiltinFunc }
 
 func (s *builtinArithmeticMultiplyIntUnsignedSig) Clone() builtinFunc {
@@ -394,6 +513,26 @@ func (s *builtinArithmeticMultiplyRealSig) evalReal(row chunk.Row) (float64, boo
 	return result, false, nil
 }
 
+func (s *builtinArithmeticMultiplyDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	c := &types.MyDecimal{}
+	err = types.DecimalMul(a, b, c)
+	if err != nil && err != types.ErrTruncated {
+		if err == types.ErrOverflow {
+			err = types.ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%s * %s)", s.args[0].String(), s.args[1].String()))
+		}
+		return nil, true, err
+	}
+	return c, false, nil
+}
+
 func (s *builtinArithmeticMultiplyIntUnsignedSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
 	a, isNull, err := s.args[0].EvalInt(s.ctx, row)
 	if isNull || err != nil {
@@ -436,10 +575,19 @@ func (c *arithmeticDivideFunctionClass) getFunction(ctx sessionctx.Context, args
 	if err := c.verifyArgs(args); err != nil {
 		return nil, err
 	}
-	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
-	c.setType4DivReal(bf.tp)
-	sig := &builtinArithmeticDivideRealSig{bf}
-	sig.setPbCode(tipb.ScalarFuncSig_DivideReal)
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	if lhsEvalTp == types.ETReal || rhsEvalTp == types.ETReal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
+		c.setType4DivReal(bf.tp)
+		sig := &builtinArithmeticDivideRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_DivideReal)
+		return sig, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+	c.setType4DivDecimal(bf.tp, lhsTp, rhsTp)
+	sig := &builtinArithmeticDivideDecimalSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_DivideDecimal)
 	return sig, nil
 }
 
@@ -469,3 +617,347 @@ func (s *builtinArithmeticDivideRealSig) evalReal(row chunk.Row) (float64, bool,
 	}
 	return result, false, nil
 }
+
+type builtinArithmeticDivideDecimalSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticDivideDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticDivideDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticDivideDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	c := &types.MyDecimal{}
+	err = types.DecimalDiv(a, b, c, types.DivFracIncr)
+	if err == types.ErrDivByZero {
+		return c, true, handleDivisionByZeroError(s.ctx)
+	} else if err == nil {
+		_, frac := c.PrecisionAndFrac()
+		if frac < s.baseBuiltinFunc.tp.Decimal {
+			err = c.Round(c, s.baseBuiltinFunc.tp.Decimal, types.ModeHalfEven)
+		}
+	} else if err == types.ErrOverflow {
+		err = types.ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%s / %s)", s.args[0].String(), s.args[1].String()))
+	}
+	return c, false, err
+}
+
+type arithmeticIntDivideFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *arithmeticIntDivideFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	if lhsEvalTp == types.ETInt && rhsEvalTp == types.ETInt {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+		if mysql.HasUnsignedFlag(lhsTp.Flag) || mysql.HasUnsignedFlag(rhsTp.Flag) {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+		sig := &builtinArithmeticIntDivideIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IntDivideInt)
+		return sig, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDecimal, types.ETDecimal)
+	if mysql.HasUnsignedFlag(lhsTp.Flag) || mysql.HasUnsignedFlag(rhsTp.Flag) {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	sig := &builtinArithmeticIntDivideDecimalSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_IntDivideDecimal)
+	return sig, nil
+}
+
+type builtinArithmeticIntDivideIntSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticIntDivideIntSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticIntDivideIntSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticIntDivideIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	a, isNull, err := s.args[0].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return s.intDivide(a, b)
+}
+
+func (s *builtinArithmeticIntDivideIntSig) intDivide(a, b int64) (int64, bool, error) {
+	if b == 0 {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+
+	var (
+		err error
+		ret int64
+		res uint64
+	)
+	isLHSUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	isRHSUnsigned := mysql.HasUnsignedFlag(s.args[1].GetType().Flag)
+
+	switch {
+	case isLHSUnsigned && isRHSUnsigned:
+		ret = int64(uint64(a) / uint64(b))
+	case isLHSUnsigned && !isRHSUnsigned:
+		res, err = types.DivUintWithInt(uint64(a), b)
+		ret = int64(res)
+	case !isLHSUnsigned && isRHSUnsigned:
+		res, err = types.DivIntWithUint(a, uint64(b))
+		ret = int64(res)
+	default:
+		ret, err = types.DivInt64(a, b)
+	}
+
+	if err != nil {
+		return 0, true, s.overflowError()
+	}
+	return ret, false, nil
+}
+
+func (s *builtinArithmeticIntDivideIntSig) overflowError() error {
+	tp := "BIGINT"
+	if mysql.HasUnsignedFlag(s.tp.Flag) {
+		tp = "BIGINT UNSIGNED"
+	}
+	return types.ErrOverflow.GenWithStackByArgs(tp, fmt.Sprintf("(%s DIV %s)", s.args[0].String(), s.args[1].String()))
+}
+
+type builtinArithmeticIntDivideDecimalSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticIntDivideDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticIntDivideDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticIntDivideDecimalSig) evalInt(row chunk.Row) (int64, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return s.intDivide(a, b)
+}
+
+func (s *builtinArithmeticIntDivideDecimalSig) intDivide(a, b *types.MyDecimal) (int64, bool, error) {
+	sc := s.ctx.GetSessionVars().StmtCtx
+	c := &types.MyDecimal{}
+	err := types.DecimalDiv(a, b, c, types.DivFracIncr)
+	if err == types.ErrDivByZero {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+	if err == types.ErrTruncated {
+		err = sc.HandleTruncate(types.ErrTruncatedWrongVal.GenWithStackByArgs("DECIMAL", c))
+	}
+	if err == types.ErrOverflow {
+		newErr := types.ErrTruncatedWrongVal.GenWithStackByArgs("DECIMAL", c)
+		err = sc.HandleOverflow(newErr, newErr)
+	}
+	if err != nil {
+		return 0, true, err
+	}
+
+	var ret int64
+	if mysql.HasUnsignedFlag(s.tp.Flag) {
+		var res uint64
+		res, err = c.ToUint()
+		// err returned by ToUint may be ErrTruncated or ErrOverflow, only handle ErrOverflow, ignore ErrTruncated.
+		if err == types.ErrOverflow {
+			// A negative quotient which is truncated to zero is not an overflow.
+			if c.IsNegative() && types.NewDecFromInt(-1).Compare(c) < 0 {
+				return 0, false, nil
+			}
+			return 0, true, types.ErrOverflow.GenWithStackByArgs("BIGINT UNSIGNED", fmt.Sprintf("(%s DIV %s)", s.args[0].String(), s.args[1].String()))
+		}
+		ret = int64(res)
+	} else {
+		ret, err = c.ToInt()
+		// err returned by ToInt may be ErrTruncated or ErrOverflow, only handle ErrOverflow, ignore ErrTruncated.
+		if err == types.ErrOverflow {
+			return 0, true, types.ErrOverflow.GenWithStackByArgs("BIGINT", fmt.Sprintf("(%s DIV %s)", s.args[0].String(), s.args[1].String()))
+		}
+	}
+	return ret, false, nil
+}
+
+type arithmeticModFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *arithmeticModFunctionClass) setType4ModRealOrDecimal(retTp, a, b *types.FieldType, isDecimal bool) {
+	if a.Decimal == types.UnspecifiedLength || b.Decimal == types.UnspecifiedLength {
+		retTp.Decimal = types.UnspecifiedLength
+	} else {
+		retTp.Decimal = mathutil.Max(a.Decimal, b.Decimal)
+		if isDecimal && retTp.Decimal > mysql.MaxDecimalScale {
+			retTp.Decimal = mysql.MaxDecimalScale
+		}
+	}
+
+	if a.Flen == types.UnspecifiedLength || b.Flen == types.UnspecifiedLength {
+		retTp.Flen = types.UnspecifiedLength
+	} else {
+		retTp.Flen = mathutil.Max(a.Flen, b.Flen)
+		if isDecimal {
+			retTp.Flen = mathutil.Min(retTp.Flen, mysql.MaxDecimalWidth)
+			return
+		}
+		retTp.Flen = mathutil.Min(retTp.Flen, mysql.MaxRealWidth)
+	}
+}
+
+func (c *arithmeticModFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	if lhsEvalTp == types.ETReal || rhsEvalTp == types.ETReal {
+		bf := newBaseBuiltinFuncWithRealArgs(ctx, args, types.ETReal)
+		c.setType4ModRealOrDecimal(bf.tp, lhsTp, rhsTp, false)
+		if mysql.HasUnsignedFlag(lhsTp.Flag) {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+		sig := &builtinArithmeticModRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_ModReal)
+		return sig, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		c.setType4ModRealOrDecimal(bf.tp, lhsTp, rhsTp, true)
+		if mysql.HasUnsignedFlag(lhsTp.Flag) {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+		sig := &builtinArithmeticModDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_ModDecimal)
+		return sig, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+	if mysql.HasUnsignedFlag(lhsTp.Flag) {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	sig := &builtinArithmeticModIntSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_ModInt)
+	return sig, nil
+}
+
+type builtinArithmeticModRealSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticModRealSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticModRealSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticModRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	a, isNull, err := s.args[0].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+
+	b, isNull, err := s.args[1].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+
+	if b == 0 {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+
+	return math.Mod(a, b), false, nil
+}
+
+type builtinArithmeticModDecimalSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticModDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticModDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticModDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, isNull, err := s.args[0].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalDecimal(s.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	c := &types.MyDecimal{}
+	err = types.DecimalMod(a, b, c)
+	if err == types.ErrDivByZero {
+		return c, true, handleDivisionByZeroError(s.ctx)
+	}
+	return c, err != nil, err
+}
+
+type builtinArithmeticModIntSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticModIntSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticModIntSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticModIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	a, isNull, err := s.args[0].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+
+	b, isNull, err := s.args[1].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+
+	if b == 0 {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+
+	return s.mod(a, b), false, nil
+}
+
+// mod returns a MOD b, the sign of the result is the same as the sign of a.
+func (s *builtinArithmeticModIntSig) mod(a, b int64) int64 {
+	isLHSUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	isRHSUnsigned := mysql.HasUnsignedFlag(s.args[1].GetType().Flag)
+	switch {
+	case isLHSUnsigned && isRHSUnsigned:
+		return int64(uint64(a) % uint64(b))
+	case isLHSUnsigned && !isRHSUnsigned:
+		if b < 0 {
+			return int64(uint64(a) % uint64(-b))
+		}
+		return int64(uint64(a) % uint64(b))
+	case !isLHSUnsigned && isRHSUnsigned:
+		if a < 0 {
+			return -int64(uint64(-a) % uint64(b))
+		}
+		return int64(uint64(a) % uint64(b))
+	default:
+		if b == -1 {
+			// MinInt64 % -1 panics in go, the result is always 0.
+			return 0
+		}
+		return a % b
+	}
+}
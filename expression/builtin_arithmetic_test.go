@@ -268,6 +268,14 @@ func (s *testEvaluatorSuite) TestArithmeticMultiply(c *C) {
 			args:   []interface{}{float64(11), float64(11)},
 			expect: float64(121),
 		},
+		{
+			args:   []interface{}{types.NewDecFromStringForTest("1.1"), types.NewDecFromStringForTest("-1.1")},
+			expect: types.NewDecFromStringForTest("-1.21"),
+		},
+		{
+			args:   []interface{}{types.NewDecFromStringForTest("0.5"), int64(3)},
+			expect: types.NewDecFromStringForTest("1.5"),
+		},
 		{
 			args:   []interface{}{nil, float64(-0.11101)},
 			expect: nil,
@@ -291,3 +299,40 @@ func (s *testEvaluatorSuite) TestArithmeticMultiply(c *C) {
 		c.Assert(val, testutil.DatumEquals, types.NewDatum(tc.expect))
 	}
 }
+
+func (s *testEvaluatorSuite) TestArithmeticDivide(c *C) {
+	testCases := []struct {
+		args   []interface{}
+		expect interface{}
+	}{
+		{
+			args:   []interface{}{int64(10), int64(4)},
+			expect: types.NewDecFromStringForTest("2.5000"),
+		},
+		{
+			args:   []interface{}{types.NewDecFromStringForTest("1.50"), int64(-3)},
+			expect: types.NewDecFromStringForTest("-0.5"),
+		},
+		{
+			args:   []interface{}{types.NewDecFromStringForTest("10"), float64(4)},
+			expect: float64(2.5),
+		},
+		{
+			args:   []interface{}{types.NewDecFromStringForTest("1.1"), int64(0)},
+			expect: nil,
+		},
+		{
+			args:   []interface{}{nil, types.NewDecFromStringForTest("1.1")},
+			expect: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		sig, err := funcs[ast.Div].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(tc.args...)))
+		c.Assert(err, IsNil)
+		c.Assert(sig, NotNil)
+		val, err := evalBuiltinFunc(sig, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(val, testutil.DatumEquals, types.NewDatum(tc.expect))
+	}
+}
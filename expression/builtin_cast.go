@@ -0,0 +1,1687 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// We implement 7 CastAsXXFunctionClass for `cast` built-in functions.
+// XX means the return type of the `cast` built-in functions.
+// XX contains the following 7 types:
+// Int, Decimal, Real, String, Time, Duration, JSON.
+
+// We implement 7 XXAsYYFunctionClass for every CastAsYYFunctionClass.
+// XX means the argument type of the `cast` built-in function.
+// XX contains the following 7 types:
+// Int, Decimal, Real, String, Time, Duration, JSON.
+
+package expression
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &castAsIntFunctionClass{}
+	_ functionClass = &castAsRealFunctionClass{}
+	_ functionClass = &castAsDecimalFunctionClass{}
+	_ functionClass = &castAsStringFunctionClass{}
+	_ functionClass = &castAsTimeFunctionClass{}
+	_ functionClass = &castAsDurationFunctionClass{}
+	_ functionClass = &castAsJSONFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinCastIntAsIntSig{}
+	_ builtinFunc = &builtinCastRealAsIntSig{}
+	_ builtinFunc = &builtinCastDecimalAsIntSig{}
+	_ builtinFunc = &builtinCastStringAsIntSig{}
+	_ builtinFunc = &builtinCastTimeAsIntSig{}
+	_ builtinFunc = &builtinCastDurationAsIntSig{}
+	_ builtinFunc = &builtinCastJSONAsIntSig{}
+
+	_ builtinFunc = &builtinCastIntAsRealSig{}
+	_ builtinFunc = &builtinCastRealAsRealSig{}
+	_ builtinFunc = &builtinCastDecimalAsRealSig{}
+	_ builtinFunc = &builtinCastStringAsRealSig{}
+	_ builtinFunc = &builtinCastTimeAsRealSig{}
+	_ builtinFunc = &builtinCastDurationAsRealSig{}
+	_ builtinFunc = &builtinCastJSONAsRealSig{}
+
+	_ builtinFunc = &builtinCastIntAsDecimalSig{}
+	_ builtinFunc = &builtinCastRealAsDecimalSig{}
+	_ builtinFunc = &builtinCastDecimalAsDecimalSig{}
+	_ builtinFunc = &builtinCastStringAsDecimalSig{}
+	_ builtinFunc = &builtinCastTimeAsDecimalSig{}
+	_ builtinFunc = &builtinCastDurationAsDecimalSig{}
+	_ builtinFunc = &builtinCastJSONAsDecimalSig{}
+
+	_ builtinFunc = &builtinCastIntAsStringSig{}
+	_ builtinFunc = &builtinCastRealAsStringSig{}
+	_ builtinFunc = &builtinCastDecimalAsStringSig{}
+	_ builtinFunc = &builtinCastStringAsStringSig{}
+	_ builtinFunc = &builtinCastTimeAsStringSig{}
+	_ builtinFunc = &builtinCastDurationAsStringSig{}
+	_ builtinFunc = &builtinCastJSONAsStringSig{}
+
+	_ builtinFunc = &builtinCastIntAsTimeSig{}
+	_ builtinFunc = &builtinCastRealAsTimeSig{}
+	_ builtinFunc = &builtinCastDecimalAsTimeSig{}
+	_ builtinFunc = &builtinCastStringAsTimeSig{}
+	_ builtinFunc = &builtinCastTimeAsTimeSig{}
+	_ builtinFunc = &builtinCastDurationAsTimeSig{}
+	_ builtinFunc = &builtinCastJSONAsTimeSig{}
+
+	_ builtinFunc = &builtinCastIntAsDurationSig{}
+	_ builtinFunc = &builtinCastRealAsDurationSig{}
+	_ builtinFunc = &builtinCastDecimalAsDurationSig{}
+	_ builtinFunc = &builtinCastStringAsDurationSig{}
+	_ builtinFunc = &builtinCastTimeAsDurationSig{}
+	_ builtinFunc = &builtinCastDurationAsDurationSig{}
+	_ builtinFunc = &builtinCastJSONAsDurationSig{}
+
+	_ builtinFunc = &builtinCastIntAsJSONSig{}
+	_ builtinFunc = &builtinCastRealAsJSONSig{}
+	_ builtinFunc = &builtinCastDecimalAsJSONSig{}
+	_ builtinFunc = &builtinCastStringAsJSONSig{}
+	_ builtinFunc = &builtinCastTimeAsJSONSig{}
+	_ builtinFunc = &builtinCastDurationAsJSONSig{}
+	_ builtinFunc = &builtinCastJSONAsJSONSig{}
+)
+
+type castAsIntFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsIntFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	argTp := args[0].GetType().EvalType()
+	switch argTp {
+	case types.ETInt:
+		sig = &builtinCastIntAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsInt)
+	case types.ETReal:
+		sig = &builtinCastRealAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsInt)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsInt)
+	case types.ETString:
+		sig = &builtinCastStringAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsInt)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsInt)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsInt)
+	case types.ETJson:
+		sig = &builtinCastJSONAsIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsInt)
+	default:
+		return nil, errors.Errorf("cannot cast from %v to %s", argTp, "Int")
+	}
+	return sig, nil
+}
+
+type castAsRealFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsRealFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	argTp := args[0].GetType().EvalType()
+	switch argTp {
+	case types.ETInt:
+		sig = &builtinCastIntAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsReal)
+	case types.ETReal:
+		sig = &builtinCastRealAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsReal)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsReal)
+	case types.ETString:
+		sig = &builtinCastStringAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsReal)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsReal)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsReal)
+	case types.ETJson:
+		sig = &builtinCastJSONAsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsReal)
+	default:
+		return nil, errors.Errorf("cannot cast from %v to %s", argTp, "Real")
+	}
+	return sig, nil
+}
+
+type castAsDecimalFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsDecimalFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	argTp := args[0].GetType().EvalType()
+	switch argTp {
+	case types.ETInt:
+		sig = &builtinCastIntAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsDecimal)
+	case types.ETReal:
+		sig = &builtinCastRealAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsDecimal)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsDecimal)
+	case types.ETString:
+		sig = &builtinCastStringAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsDecimal)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsDecimal)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsDecimal)
+	case types.ETJson:
+		sig = &builtinCastJSONAsDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsDecimal)
+	default:
+		return nil, errors.Errorf("cannot cast from %v to %s", argTp, "Decimal")
+	}
+	return sig, nil
+}
+
+type castAsStringFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsStringFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	argTp := args[0].GetType().EvalType()
+	switch argTp {
+	case types.ETInt:
+		sig = &builtinCastIntAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsString)
+	case types.ETReal:
+		sig = &builtinCastRealAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsString)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsString)
+	case types.ETString:
+		sig = &builtinCastStringAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsString)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsString)
+	case types.ETJson:
+		sig = &builtinCastJSONAsStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsString)
+	default:
+		return nil, errors.Errorf("cannot cast from %v to %s", argTp, "String")
+	}
+	return sig, nil
+}
+
+type castAsTimeFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsTimeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	argTp := args[0].GetType().EvalType()
+	switch argTp {
+	case types.ETInt:
+		sig = &builtinCastIntAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsTime)
+	case types.ETReal:
+		sig = &builtinCastRealAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsTime)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsTime)
+	case types.ETString:
+		sig = &builtinCastStringAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsTime)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsTime)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsTime)
+	case types.ETJson:
+		sig = &builtinCastJSONAsTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsTime)
+	default:
+		return nil, errors.Errorf("cannot cast from %v to %s", argTp, "Datetime")
+	}
+	return sig, nil
+}
+
+type castAsDurationFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsDurationFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	argTp := args[0].GetType().EvalType()
+	switch argTp {
+	case types.ETInt:
+		sig = &builtinCastIntAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsDuration)
+	case types.ETReal:
+		sig = &builtinCastRealAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsDuration)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsDuration)
+	case types.ETString:
+		sig = &builtinCastStringAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsDuration)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsDuration)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsDuration)
+	case types.ETJson:
+		sig = &builtinCastJSONAsDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsDuration)
+	default:
+		return nil, errors.Errorf("cannot cast from %v to %s", argTp, "Time")
+	}
+	return sig, nil
+}
+
+type castAsJSONFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsJSONFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc(ctx, args)
+	bf.tp = c.tp
+	argTp := args[0].GetType().EvalType()
+	switch argTp {
+	case types.ETInt:
+		sig = &builtinCastIntAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastIntAsJson)
+	case types.ETReal:
+		sig = &builtinCastRealAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastRealAsJson)
+	case types.ETDecimal:
+		sig = &builtinCastDecimalAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDecimalAsJson)
+	case types.ETString:
+		// A string is parsed as a JSON text unless the caller says otherwise,
+		// see DisableParseJSONFlag4Expr.
+		sig = &builtinCastStringAsJSONSig{bf}
+		sig.getRetTp().Flag |= mysql.ParseToJSONFlag
+		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsJson)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCastTimeAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastTimeAsJson)
+	case types.ETDuration:
+		sig = &builtinCastDurationAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastDurationAsJson)
+	case types.ETJson:
+		sig = &builtinCastJSONAsJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CastJsonAsJson)
+	default:
+		return nil, errors.Errorf("cannot cast from %v to %s", argTp, "Json")
+	}
+	return sig, nil
+}
+
+type builtinCastIntAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinCastRealAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = castRealAsInt(b.ctx, val, b.tp)
+	return res, false, err
+}
+
+// castRealAsInt rounds val to the nearest integer of the signedness of tp.
+func castRealAsInt(ctx sessionctx.Context, val float64, tp *types.FieldType) (res int64, err error) {
+	if !mysql.HasUnsignedFlag(tp.Flag) {
+		res, err = types.ConvertFloatToInt(val, types.IntergerSignedLowerBound(mysql.TypeLonglong), types.IntergerSignedUpperBound(mysql.TypeLonglong), mysql.TypeLonglong)
+	} else {
+		var uintVal uint64
+		uintVal, err = types.ConvertFloatToUint(ctx.GetSessionVars().StmtCtx, val, types.IntergerUnsignedUpperBound(mysql.TypeLonglong), mysql.TypeLonglong)
+		res = int64(uintVal)
+	}
+	if types.ErrOverflow.Equal(err) {
+		err = ctx.GetSessionVars().StmtCtx.HandleOverflow(err, err)
+	}
+	return res, err
+}
+
+type builtinCastDecimalAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = castDecimalAsInt(b.ctx, val, b.tp)
+	return res, false, err
+}
+
+// castDecimalAsInt rounds val to the nearest integer of the signedness of tp.
+func castDecimalAsInt(ctx sessionctx.Context, val *types.MyDecimal, tp *types.FieldType) (res int64, err error) {
+	var to types.MyDecimal
+	if err = val.Round(&to, 0, types.ModeHalfEven); err != nil {
+		return 0, err
+	}
+	if !mysql.HasUnsignedFlag(tp.Flag) {
+		res, err = to.ToInt()
+	} else {
+		var uintRes uint64
+		uintRes, err = to.ToUint()
+		res = int64(uintRes)
+	}
+	if types.ErrOverflow.Equal(err) {
+		warnErr := types.ErrTruncatedWrongVal.GenWithStackByArgs("DECIMAL", val)
+		err = ctx.GetSessionVars().StmtCtx.HandleOverflow(err, warnErr)
+	}
+	return res, err
+}
+
+type builtinCastStringAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	if b.args[0].GetType().Hybrid() {
+		return b.args[0].EvalInt(b.ctx, row)
+	}
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = castStringAsInt(b.ctx, val, b.tp)
+	return res, false, err
+}
+
+// castStringAsInt parses the longest numeric prefix of val. A string out of
+// the range of BIGINT is clamped to its bound with a warning.
+func castStringAsInt(ctx sessionctx.Context, val string, tp *types.FieldType) (res int64, err error) {
+	sc := ctx.GetSessionVars().StmtCtx
+	val = strings.TrimSpace(val)
+	isNegative := len(val) > 1 && val[0] == '-'
+	if !isNegative {
+		var ures uint64
+		ures, err = types.StrToUint(sc, val)
+		res = int64(ures)
+		if err == nil && !mysql.HasUnsignedFlag(tp.Flag) && ures > uint64(math.MaxInt64) {
+			sc.AppendWarning(types.ErrCastAsSignedOverflow)
+		}
+	} else {
+		res, err = types.StrToInt(sc, val)
+		if err == nil && mysql.HasUnsignedFlag(tp.Flag) {
+			sc.AppendWarning(types.ErrCastNegIntAsUnsigned)
+		}
+	}
+	if types.ErrOverflow.Equal(err) {
+		if isNegative {
+			res = math.MinInt64
+		} else {
+			uval := uint64(math.MaxUint64)
+			res = int64(uval)
+		}
+		warnErr := types.ErrTruncatedWrongVal.GenWithStackByArgs("INTEGER", val)
+		err = sc.HandleOverflow(err, warnErr)
+	}
+	return res, err
+}
+
+type builtinCastTimeAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	t, err := val.RoundFrac(types.DefaultFsp)
+	if err != nil {
+		return res, false, err
+	}
+	res, err = t.ToNumber().ToInt()
+	return res, false, err
+}
+
+type builtinCastDurationAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	dur, err := val.RoundFrac(types.DefaultFsp)
+	if err != nil {
+		return res, false, err
+	}
+	res, err = dur.ToNumber().ToInt()
+	return res, false, err
+}
+
+type builtinCastIntAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		return float64(uint64(val)), false, nil
+	}
+	return float64(val), false, nil
+}
+
+type builtinCastRealAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	return b.args[0].EvalReal(b.ctx, row)
+}
+
+type builtinCastDecimalAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = val.ToFloat64()
+	return res, false, err
+}
+
+type builtinCastStringAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.StrToFloat(b.ctx.GetSessionVars().StmtCtx, val)
+	return res, false, err
+}
+
+type builtinCastIntAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		res = types.NewDecFromUint(uint64(val))
+	} else {
+		res = types.NewDecFromInt(val)
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, b.ctx.GetSessionVars().StmtCtx)
+	return res, false, err
+}
+
+type builtinCastRealAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res = new(types.MyDecimal)
+	if err = res.FromFloat64(val); err != nil {
+		return res, false, err
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, b.ctx.GetSessionVars().StmtCtx)
+	return res, false, err
+}
+
+type builtinCastDecimalAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	evalDecimal, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res = &types.MyDecimal{}
+	*res = *evalDecimal
+	res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, b.ctx.GetSessionVars().StmtCtx)
+	return res, false, err
+}
+
+type builtinCastStringAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res = new(types.MyDecimal)
+	if err = res.FromString([]byte(val)); err == types.ErrTruncated || err == types.ErrBadNumber {
+		err = sc.HandleTruncate(types.ErrTruncatedWrongVal.GenWithStackByArgs("DECIMAL", val))
+	}
+	if err != nil {
+		return res, false, err
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, sc)
+	return res, false, err
+}
+
+type builtinCastTimeAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = val.ToNumber().ToFloat64()
+	return res, false, err
+}
+
+type builtinCastDurationAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = val.ToNumber().ToFloat64()
+	return res, false, err
+}
+
+type builtinCastTimeAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(val.ToNumber(), b.tp, b.ctx.GetSessionVars().StmtCtx)
+	return res, false, err
+}
+
+type builtinCastDurationAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(val.ToNumber(), b.tp, b.ctx.GetSessionVars().StmtCtx)
+	return res, false, err
+}
+
+type builtinCastIntAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		res = strconv.FormatUint(uint64(val), 10)
+	} else {
+		res = strconv.FormatInt(val, 10)
+	}
+	res, err = types.ProduceStrWithSpecifiedTp(res, b.tp, b.ctx.GetSessionVars().StmtCtx, true)
+	return res, false, err
+}
+
+type builtinCastRealAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	bits := 64
+	if b.args[0].GetType().Tp == mysql.TypeFloat {
+		// A FLOAT is widened to float64 when it is read, format it with the
+		// precision it was stored with.
+		bits = 32
+	}
+	res = strconv.FormatFloat(val, 'f', -1, bits)
+	res, err = types.ProduceStrWithSpecifiedTp(res, b.tp, b.ctx.GetSessionVars().StmtCtx, true)
+	return res, false, err
+}
+
+type builtinCastDecimalAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ProduceStrWithSpecifiedTp(val.String(), b.tp, b.ctx.GetSessionVars().StmtCtx, true)
+	return res, false, err
+}
+
+type builtinCastStringAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ProduceStrWithSpecifiedTp(res, b.tp, b.ctx.GetSessionVars().StmtCtx, true)
+	return res, false, err
+}
+
+type builtinCastTimeAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ProduceStrWithSpecifiedTp(val.String(), b.tp, b.ctx.GetSessionVars().StmtCtx, true)
+	return res, false, err
+}
+
+type builtinCastDurationAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ProduceStrWithSpecifiedTp(val.String(), b.tp, b.ctx.GetSessionVars().StmtCtx, true)
+	return res, false, err
+}
+
+// truncateToDate drops the clock part of t when the target type is DATE.
+func truncateToDate(t types.Time, tp byte) types.Time {
+	if tp == mysql.TypeDate {
+		t.SetCoreTime(types.FromDate(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0))
+	}
+	return t
+}
+
+type builtinCastIntAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ParseTimeFromNum(b.ctx.GetSessionVars().StmtCtx, val, b.tp.Tp, int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return truncateToDate(res, b.tp.Tp), false, nil
+}
+
+type builtinCastRealAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	fv := strconv.FormatFloat(val, 'f', -1, 64)
+	res, err = types.ParseTimeFromFloatString(b.ctx.GetSessionVars().StmtCtx, fv, b.tp.Tp, int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return truncateToDate(res, b.tp.Tp), false, nil
+}
+
+type builtinCastDecimalAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ParseTimeFromFloatString(b.ctx.GetSessionVars().StmtCtx, val.String(), b.tp.Tp, int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return truncateToDate(res, b.tp.Tp), false, nil
+}
+
+type builtinCastStringAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ParseTime(b.ctx.GetSessionVars().StmtCtx, val, b.tp.Tp, int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return truncateToDate(res, b.tp.Tp), false, nil
+}
+
+type builtinCastTimeAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if res, err = res.Convert(b.ctx.GetSessionVars().StmtCtx, b.tp.Tp); err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	res, err = res.RoundFrac(int8(b.tp.Decimal))
+	return truncateToDate(res, b.tp.Tp), false, err
+}
+
+type builtinCastDurationAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = val.ConvertToTime(b.ctx.GetSessionVars().StmtCtx, b.tp.Tp)
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	res, err = res.RoundFrac(int8(b.tp.Decimal))
+	return truncateToDate(res, b.tp.Tp), false, err
+}
+
+type builtinCastIntAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	dur, err := types.NumberToDuration(val, int8(b.tp.Decimal))
+	if err != nil {
+		if types.ErrOverflow.Equal(err) {
+			err = b.ctx.GetSessionVars().StmtCtx.HandleOverflow(err, err)
+		}
+		return res, true, err
+	}
+	return dur, false, nil
+}
+
+type builtinCastRealAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ParseDuration(b.ctx.GetSessionVars().StmtCtx, strconv.FormatFloat(val, 'f', -1, 64), int8(b.tp.Decimal))
+	if types.ErrTruncatedWrongVal.Equal(err) {
+		err = b.ctx.GetSessionVars().StmtCtx.HandleTruncate(err)
+	}
+	return res, false, err
+}
+
+type builtinCastDecimalAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ParseDuration(b.ctx.GetSessionVars().StmtCtx, val.String(), int8(b.tp.Decimal))
+	if types.ErrTruncatedWrongVal.Equal(err) {
+		err = b.ctx.GetSessionVars().StmtCtx.HandleTruncate(err)
+	}
+	return res, false, err
+}
+
+type builtinCastStringAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ParseDuration(b.ctx.GetSessionVars().StmtCtx, val, int8(b.tp.Decimal))
+	if types.ErrTruncatedWrongVal.Equal(err) {
+		err = b.ctx.GetSessionVars().StmtCtx.HandleTruncate(err)
+	}
+	return res, false, err
+}
+
+type builtinCastTimeAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = val.ConvertToDuration()
+	if err != nil {
+		return res, false, err
+	}
+	res, err = res.RoundFrac(int8(b.tp.Decimal))
+	return res, false, err
+}
+
+type builtinCastDurationAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = res.RoundFrac(int8(b.tp.Decimal))
+	return res, false, err
+}
+
+type builtinCastJSONAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ConvertJSONToInt(sc, val, mysql.HasUnsignedFlag(b.tp.Flag))
+	return res, false, err
+}
+
+type builtinCastJSONAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ConvertJSONToFloat(b.ctx.GetSessionVars().StmtCtx, val)
+	return res, false, err
+}
+
+type builtinCastJSONAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ConvertJSONToDecimal(sc, val)
+	if err != nil {
+		return res, false, err
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, sc)
+	return res, false, err
+}
+
+type builtinCastJSONAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	return val.String(), false, nil
+}
+
+type builtinCastJSONAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ParseTime(b.ctx.GetSessionVars().StmtCtx, val.Unquote(), b.tp.Tp, int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return truncateToDate(res, b.tp.Tp), false, nil
+}
+
+type builtinCastJSONAsDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsDurationSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = types.ParseDuration(b.ctx.GetSessionVars().StmtCtx, val.Unquote(), int8(b.tp.Decimal))
+	if types.ErrTruncatedWrongVal.Equal(err) {
+		err = b.ctx.GetSessionVars().StmtCtx.HandleTruncate(err)
+	}
+	return res, false, err
+}
+
+type builtinCastIntAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastIntAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastIntAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastIntAsJSONSig) evalJSON(row chunk.Row) (res types.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	return castIntAsJSON(b.args[0].GetType(), val), false, nil
+}
+
+// castIntAsJSON turns val into a JSON boolean or number according to the
+// flags of its type tp.
+func castIntAsJSON(tp *types.FieldType, val int64) types.BinaryJSON {
+	if mysql.HasIsBooleanFlag(tp.Flag) {
+		return types.CreateBinaryJSON(val != 0)
+	} else if mysql.HasUnsignedFlag(tp.Flag) {
+		return types.CreateBinaryJSON(uint64(val))
+	}
+	return types.CreateBinaryJSON(val)
+}
+
+type builtinCastRealAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastRealAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastRealAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastRealAsJSONSig) evalJSON(row chunk.Row) (res types.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	return types.CreateBinaryJSON(val), false, nil
+}
+
+type builtinCastDecimalAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDecimalAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastDecimalAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDecimalAsJSONSig) evalJSON(row chunk.Row) (res types.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	return types.CreateBinaryJSON(val), false, nil
+}
+
+type builtinCastStringAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastStringAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastStringAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastStringAsJSONSig) evalJSON(row chunk.Row) (res types.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if mysql.HasParseToJSONFlag(b.tp.Flag) {
+		res, err = types.ParseBinaryJSONFromString(val)
+		return res, err != nil, err
+	}
+	return types.CreateBinaryJSON(val), false, nil
+}
+
+type builtinCastTimeAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastTimeAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastTimeAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastTimeAsJSONSig) evalJSON(row chunk.Row) (res types.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if val.Type() == mysql.TypeDatetime || val.Type() == mysql.TypeTimestamp {
+		val.SetFsp(types.MaxFsp)
+	}
+	return types.CreateBinaryJSON(val.String()), false, nil
+}
+
+type builtinCastDurationAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastDurationAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastDurationAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastDurationAsJSONSig) evalJSON(row chunk.Row) (res types.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	val.Fsp = types.MaxFsp
+	return types.CreateBinaryJSON(val.String()), false, nil
+}
+
+type builtinCastJSONAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsJSONSig) evalJSON(row chunk.Row) (res types.BinaryJSON, isNull bool, err error) {
+	return b.args[0].EvalJSON(b.ctx, row)
+}
+
+// getCastFunctionClass returns the `cast` function class producing the type
+// tp, or nil if there is no cast to it.
+func getCastFunctionClass(tp *types.FieldType) functionClass {
+	switch tp.EvalType() {
+	case types.ETInt:
+		return &castAsIntFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETReal:
+		return &castAsRealFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETDecimal:
+		return &castAsDecimalFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETString:
+		return &castAsStringFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETDatetime, types.ETTimestamp:
+		return &castAsTimeFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETDuration:
+		return &castAsDurationFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETJson:
+		return &castAsJSONFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	}
+	return nil
+}
+
+// BuildCastFunction builds a CAST ScalarFunction from the Expression.
+func BuildCastFunction(ctx sessionctx.Context, expr Expression, tp *types.FieldType) (res Expression) {
+	fc := getCastFunctionClass(tp)
+	if fc == nil {
+		terror.Log(errors.Errorf("cannot cast to %v", tp.EvalType()))
+		return expr
+	}
+	f, err := fc.getFunction(ctx, []Expression{expr})
+	if err != nil {
+		terror.Log(err)
+		return expr
+	}
+	res = &ScalarFunction{
+		FuncName: model.NewCIStr(ast.Cast),
+		RetType:  tp,
+		Function: f,
+	}
+	// A CAST to JSON is not folded here, since the ParseToJSONFlag of its
+	// type may still be cleared by the caller and change the result.
+	if tp.EvalType() == types.ETJson {
+		return res
+	}
+	return FoldConstant(res)
+}
+
+// WrapWithCastAsInt wraps `expr` with `cast` if the return type of expr is not
+// type int, otherwise, returns `expr` directly.
+func WrapWithCastAsInt(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().EvalType() == types.ETInt {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeLonglong)
+	tp.Flen, tp.Decimal = expr.GetType().Flen, 0
+	types.SetBinChsClnFlag(tp)
+	tp.Flag |= expr.GetType().Flag & mysql.UnsignedFlag
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsReal wraps `expr` with `cast` if the return type of expr is
+// not type real, otherwise, returns `expr` directly.
+func WrapWithCastAsReal(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().EvalType() == types.ETReal {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeDouble)
+	tp.Flen, tp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+	types.SetBinChsClnFlag(tp)
+	tp.Flag |= expr.GetType().Flag & mysql.UnsignedFlag
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsDecimal wraps `expr` with `cast` if the return type of expr is
+// not type decimal, otherwise, returns `expr` directly.
+func WrapWithCastAsDecimal(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().EvalType() == types.ETDecimal {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeNewDecimal)
+	tp.Flen, tp.Decimal = expr.GetType().Flen, expr.GetType().Decimal
+	if expr.GetType().EvalType() == types.ETInt {
+		tp.Flen = mysql.MaxIntWidth
+	}
+	types.SetBinChsClnFlag(tp)
+	tp.Flag |= expr.GetType().Flag & mysql.UnsignedFlag
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsString wraps `expr` with `cast` if the return type of expr is
+// not type string, otherwise, returns `expr` directly.
+func WrapWithCastAsString(ctx sessionctx.Context, expr Expression) Expression {
+	exprTp := expr.GetType()
+	if exprTp.EvalType() == types.ETString {
+		return expr
+	}
+	argLen := exprTp.Flen
+	// A decimal needs room for its sign and its point, and an int as long as
+	// the widest BIGINT when its length is unknown.
+	if exprTp.EvalType() == types.ETDecimal && argLen != types.UnspecifiedLength {
+		argLen += 2
+	}
+	if exprTp.EvalType() == types.ETInt && argLen == types.UnspecifiedLength {
+		argLen = mysql.MaxIntWidth
+	}
+	tp := types.NewFieldType(mysql.TypeVarString)
+	tp.Charset, tp.Collate = charset.GetDefaultCharsetAndCollate()
+	tp.Flen, tp.Decimal = argLen, types.UnspecifiedLength
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsTime wraps `expr` with `cast` if the return type of expr is not
+// same as type of the specified `tp` , otherwise, returns `expr` directly.
+func WrapWithCastAsTime(ctx sessionctx.Context, expr Expression, tp *types.FieldType) Expression {
+	exprTp := expr.GetType().Tp
+	if tp.Tp == exprTp {
+		return expr
+	} else if (exprTp == mysql.TypeDate || exprTp == mysql.TypeTimestamp) && tp.Tp == mysql.TypeDatetime {
+		return expr
+	}
+	switch x := expr.GetType(); x.Tp {
+	case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDate, mysql.TypeDuration:
+		tp.Decimal = x.Decimal
+	default:
+		tp.Decimal = int(types.MaxFsp)
+	}
+	switch tp.Tp {
+	case mysql.TypeDate:
+		tp.Flen = mysql.MaxDateWidth
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		tp.Flen = mysql.MaxDatetimeWidthNoFsp
+		if tp.Decimal > 0 {
+			tp.Flen = tp.Flen + 1 + tp.Decimal
+		}
+	}
+	types.SetBinChsClnFlag(tp)
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsDuration wraps `expr` with `cast` if the return type of expr is
+// not type duration, otherwise, returns `expr` directly.
+func WrapWithCastAsDuration(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().Tp == mysql.TypeDuration {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeDuration)
+	switch x := expr.GetType(); x.Tp {
+	case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDate:
+		tp.Decimal = x.Decimal
+	default:
+		tp.Decimal = int(types.MaxFsp)
+	}
+	tp.Flen = mysql.MaxDurationWidthNoFsp
+	if tp.Decimal > 0 {
+		tp.Flen = tp.Flen + 1 + tp.Decimal
+	}
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsJSON wraps `expr` with `cast` if the return type of expr is not
+// type json, otherwise, returns `expr` directly.
+func WrapWithCastAsJSON(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().Tp == mysql.TypeJSON && !mysql.HasParseToJSONFlag(expr.GetType().Flag) {
+		return expr
+	}
+	tp := &types.FieldType{
+		Tp:      mysql.TypeJSON,
+		Flen:    mysql.MaxBlobWidth,
+		Decimal: 0,
+		Charset: charset.CharsetBin,
+		Collate: charset.CollationBin,
+		Flag:    mysql.BinaryFlag,
+	}
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// DisableParseJSONFlag4Expr disables ParseToJSONFlag for `expr` except Column,
+// so that a string argument becomes a JSON string instead of being parsed as
+// a JSON text. We should not *PARSE* a string as JSON under some scenarios,
+// e.g. the values of JSON_ARRAY or the comparison with a JSON.
+func DisableParseJSONFlag4Expr(expr Expression) {
+	if _, isColumn := expr.(*Column); isColumn {
+		return
+	}
+	if _, isCorCol := expr.(*CorrelatedColumn); isCorCol {
+		return
+	}
+	expr.GetType().Flag &= ^mysql.ParseToJSONFlag
+}
@@ -0,0 +1,143 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestCastAsInt(c *C) {
+	sc := s.ctx.GetSessionVars().StmtCtx
+	oldTruncate, oldOverflow := sc.TruncateAsWarning, sc.OverflowAsWarning
+	sc.TruncateAsWarning, sc.OverflowAsWarning = true, true
+	defer func() {
+		sc.TruncateAsWarning, sc.OverflowAsWarning = oldTruncate, oldOverflow
+	}()
+
+	tm, err := types.ParseTime(sc, "2020-01-02 10:11:12.6", mysql.TypeDatetime, 1)
+	c.Assert(err, IsNil)
+	dur, err := types.ParseDuration(sc, "10:11:12.5", 1)
+	c.Assert(err, IsNil)
+
+	tbl := []struct {
+		arg      interface{}
+		unsigned bool
+		ret      interface{}
+		warnings int
+	}{
+		{nil, false, nil, 0},
+		{int64(-3), false, int64(-3), 0},
+		{1.5, false, int64(2), 0},
+		{-1.5, false, int64(-2), 0},
+		{1e20, false, int64(math.MaxInt64), 1},
+		{types.NewDecFromStringForTest("2.5"), false, int64(3), 0},
+		{types.NewDecFromStringForTest("-2.5"), false, int64(-3), 0},
+		{" 42 ", false, int64(42), 0},
+		{"12abc", false, int64(12), 1},
+		{"-1", true, uint64(math.MaxUint64), 1},
+		{"18446744073709551616", false, int64(-1), 1},
+		{tm, false, int64(20200102101113), 0},
+		{dur, false, int64(101113), 0},
+	}
+	for _, t := range tbl {
+		tp := types.NewFieldType(mysql.TypeLonglong)
+		if t.unsigned {
+			tp.Flag |= mysql.UnsignedFlag
+		}
+		sc.SetWarnings(nil)
+		f := BuildCastFunction(s.ctx, s.primitiveValsToConstants([]interface{}{t.arg})[0], tp)
+		v, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil, Commentf("%v", t.arg))
+		c.Assert(v, testutil.DatumEquals, types.NewDatum(t.ret), Commentf("%v", t.arg))
+		c.Assert(int(sc.WarningCount()), Equals, t.warnings, Commentf("%v", t.arg))
+	}
+}
+
+func (s *testEvaluatorSuite) TestCastAsString(c *C) {
+	sc := s.ctx.GetSessionVars().StmtCtx
+	oldTruncate := sc.TruncateAsWarning
+	sc.TruncateAsWarning = true
+	defer func() {
+		sc.TruncateAsWarning = oldTruncate
+	}()
+
+	tm, err := types.ParseTime(sc, "2020-01-02 10:11:12.6", mysql.TypeDatetime, 1)
+	c.Assert(err, IsNil)
+	dur, err := types.ParseDuration(sc, "10:11:12.5", 1)
+	c.Assert(err, IsNil)
+
+	binaryTp := types.NewFieldType(mysql.TypeString)
+	binaryTp.Flen = 4
+	types.SetBinChsClnFlag(binaryTp)
+	tbl := []struct {
+		arg  interface{}
+		flen int
+		ret  interface{}
+	}{
+		{nil, types.UnspecifiedLength, nil},
+		{int64(-12), types.UnspecifiedLength, "-12"},
+		{uint64(math.MaxUint64), types.UnspecifiedLength, "18446744073709551615"},
+		{1.25, types.UnspecifiedLength, "1.25"},
+		{types.NewDecFromStringForTest("3.140"), types.UnspecifiedLength, "3.140"},
+		{"abcdef", types.UnspecifiedLength, "abcdef"},
+		{"abcdef", 3, "abc"},
+		{"你好世界", 2, "你好"},
+		{int64(12345), 2, "12"},
+		{tm, types.UnspecifiedLength, "2020-01-02 10:11:12.6"},
+		{dur, types.UnspecifiedLength, "10:11:12.5"},
+	}
+	for _, t := range tbl {
+		tp := types.NewFieldType(mysql.TypeVarString)
+		tp.Charset, tp.Collate = charset.CharsetUTF8MB4, charset.CollationUTF8MB4
+		tp.Flen = t.flen
+		f := BuildCastFunction(s.ctx, s.primitiveValsToConstants([]interface{}{t.arg})[0], tp)
+		v, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil, Commentf("%v", t.arg))
+		c.Assert(v, testutil.DatumEquals, types.NewDatum(t.ret), Commentf("%v", t.arg))
+	}
+
+	// BINARY(N) pads the value with zero bytes.
+	f := BuildCastFunction(s.ctx, s.primitiveValsToConstants([]interface{}{"ab"})[0], binaryTp)
+	v, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v.GetString(), Equals, "ab\x00\x00")
+}
+
+func (s *testEvaluatorSuite) TestWrapWithCastAsString(c *C) {
+	intTp := types.NewFieldType(mysql.TypeLonglong)
+	decTp := types.NewFieldType(mysql.TypeNewDecimal)
+	decTp.Flen, decTp.Decimal = 10, 2
+	cases := []struct {
+		tp   *types.FieldType
+		flen int
+	}{
+		{intTp, mysql.MaxIntWidth},
+		{decTp, 12},
+	}
+	for _, t := range cases {
+		expr := WrapWithCastAsString(s.ctx, &Column{RetType: t.tp})
+		c.Assert(expr.GetType().EvalType(), Equals, types.ETString)
+		c.Assert(expr.GetType().Flen, Equals, t.flen)
+	}
+
+	col := &Column{RetType: types.NewFieldType(mysql.TypeVarString)}
+	c.Assert(WrapWithCastAsString(s.ctx, col), Equals, col)
+}
@@ -0,0 +1,499 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"strconv"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *builtinCastIntAsRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastIntAsRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeFloat64(n, false)
+	result.MergeNulls(buf)
+	i64s := buf.Int64s()
+	f64s := result.Float64s()
+	unsigned := mysql.HasUnsignedFlag(b.args[0].GetType().Flag)
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if unsigned {
+			f64s[i] = float64(uint64(i64s[i]))
+		} else {
+			f64s[i] = float64(i64s[i])
+		}
+	}
+	return nil
+}
+
+func (b *builtinCastRealAsRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastRealAsRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalReal(b.ctx, input, result)
+}
+
+func (b *builtinCastDecimalAsRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastDecimalAsRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeFloat64(n, false)
+	result.MergeNulls(buf)
+	d64s := buf.Decimals()
+	f64s := result.Float64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if f64s[i], err = d64s[i].ToFloat64(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinCastStringAsRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastStringAsRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeFloat64(n, false)
+	result.MergeNulls(buf)
+	f64s := result.Float64s()
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if f64s[i], err = types.StrToFloat(sc, buf.GetString(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinCastIntAsDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastIntAsDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeDecimal(n, false)
+	result.MergeNulls(buf)
+	i64s := buf.Int64s()
+	d64s := result.Decimals()
+	unsigned := mysql.HasUnsignedFlag(b.args[0].GetType().Flag)
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if unsigned {
+			d64s[i].FromUint(uint64(i64s[i]))
+		} else {
+			d64s[i].FromInt(i64s[i])
+		}
+		dec, err := types.ProduceDecWithSpecifiedTp(&d64s[i], b.tp, sc)
+		if err != nil {
+			return err
+		}
+		d64s[i] = *dec
+	}
+	return nil
+}
+
+func (b *builtinCastRealAsDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastRealAsDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalReal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeDecimal(n, false)
+	result.MergeNulls(buf)
+	f64s := buf.Float64s()
+	d64s := result.Decimals()
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if err := d64s[i].FromFloat64(f64s[i]); err != nil {
+			return err
+		}
+		dec, err := types.ProduceDecWithSpecifiedTp(&d64s[i], b.tp, sc)
+		if err != nil {
+			return err
+		}
+		d64s[i] = *dec
+	}
+	return nil
+}
+
+func (b *builtinCastDecimalAsDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastDecimalAsDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, result); err != nil {
+		return err
+	}
+	n := input.NumRows()
+	d64s := result.Decimals()
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		dec, err := types.ProduceDecWithSpecifiedTp(&d64s[i], b.tp, sc)
+		if err != nil {
+			return err
+		}
+		d64s[i] = *dec
+	}
+	return nil
+}
+
+func (b *builtinCastStringAsDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastStringAsDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeDecimal(n, false)
+	result.MergeNulls(buf)
+	d64s := result.Decimals()
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		val := buf.GetString(i)
+		if err := d64s[i].FromString([]byte(val)); err == types.ErrTruncated || err == types.ErrBadNumber {
+			if err = sc.HandleTruncate(types.ErrTruncatedWrongVal.GenWithStackByArgs("DECIMAL", val)); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+		dec, err := types.ProduceDecWithSpecifiedTp(&d64s[i], b.tp, sc)
+		if err != nil {
+			return err
+		}
+		d64s[i] = *dec
+	}
+	return nil
+}
+
+func (b *builtinCastIntAsIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastIntAsIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalInt(b.ctx, input, result)
+}
+
+func (b *builtinCastRealAsIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastRealAsIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalReal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	f64s := buf.Float64s()
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if i64s[i], err = castRealAsInt(b.ctx, f64s[i], b.tp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinCastDecimalAsIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastDecimalAsIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	d64s := buf.Decimals()
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if i64s[i], err = castDecimalAsInt(b.ctx, &d64s[i], b.tp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinCastStringAsIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastStringAsIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if b.args[0].GetType().Hybrid() {
+		return b.args[0].VecEvalInt(b.ctx, input, result)
+	}
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if i64s[i], err = castStringAsInt(b.ctx, buf.GetString(i), b.tp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinCastIntAsStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastIntAsStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	i64s := buf.Int64s()
+	unsigned := mysql.HasUnsignedFlag(b.args[0].GetType().Flag)
+	sc := b.ctx.GetSessionVars().StmtCtx
+	var str string
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		if unsigned {
+			str = strconv.FormatUint(uint64(i64s[i]), 10)
+		} else {
+			str = strconv.FormatInt(i64s[i], 10)
+		}
+		if str, err = types.ProduceStrWithSpecifiedTp(str, b.tp, sc, true); err != nil {
+			return err
+		}
+		result.AppendString(str)
+	}
+	return nil
+}
+
+func (b *builtinCastRealAsStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastRealAsStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalReal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	f64s := buf.Float64s()
+	bits := 64
+	if b.args[0].GetType().Tp == mysql.TypeFloat {
+		bits = 32
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str, err := types.ProduceStrWithSpecifiedTp(strconv.FormatFloat(f64s[i], 'f', -1, bits), b.tp, sc, true)
+		if err != nil {
+			return err
+		}
+		result.AppendString(str)
+	}
+	return nil
+}
+
+func (b *builtinCastDecimalAsStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastDecimalAsStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	d64s := buf.Decimals()
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str, err := types.ProduceStrWithSpecifiedTp(d64s[i].String(), b.tp, sc, true)
+		if err != nil {
+			return err
+		}
+		result.AppendString(str)
+	}
+	return nil
+}
+
+func (b *builtinCastStringAsStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastStringAsStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str, err := types.ProduceStrWithSpecifiedTp(buf.GetString(i), b.tp, sc, true)
+		if err != nil {
+			return err
+		}
+		result.AppendString(str)
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ package expression
 import (
 	"math"
 
+	"github.com/cznic/mathutil"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/opcode"
 	"github.com/pingcap/tidb/sessionctx"
@@ -25,411 +26,1787 @@ import (
 )
 
 var (
+	_ functionClass = &coalesceFunctionClass{}
+	_ functionClass = &greatestFunctionClass{}
+	_ functionClass = &leastFunctionClass{}
 	_ functionClass = &compareFunctionClass{}
 )
 
 var (
+	_ builtinFunc = &builtinCoalesceIntSig{}
+	_ builtinFunc = &builtinCoalesceRealSig{}
+	_ builtinFunc = &builtinCoalesceDecimalSig{}
+	_ builtinFunc = &builtinCoalesceStringSig{}
+	_ builtinFunc = &builtinCoalesceTimeSig{}
+	_ builtinFunc = &builtinCoalesceDurationSig{}
+	_ builtinFunc = &builtinCoalesceJSONSig{}
+
+	_ builtinFunc = &builtinGreatestIntSig{}
+	_ builtinFunc = &builtinGreatestRealSig{}
+	_ builtinFunc = &builtinGreatestDecimalSig{}
+	_ builtinFunc = &builtinGreatestStringSig{}
+	_ builtinFunc = &builtinGreatestTimeSig{}
+
+	_ builtinFunc = &builtinLeastIntSig{}
+	_ builtinFunc = &builtinLeastRealSig{}
+	_ builtinFunc = &builtinLeastDecimalSig{}
+	_ builtinFunc = &builtinLeastStringSig{}
+	_ builtinFunc = &builtinLeastTimeSig{}
+
 	_ builtinFunc = &builtinLTIntSig{}
 	_ builtinFunc = &builtinLTRealSig{}
+	_ builtinFunc = &builtinLTDecimalSig{}
 	_ builtinFunc = &builtinLTStringSig{}
+	_ builtinFunc = &builtinLTTimeSig{}
+	_ builtinFunc = &builtinLTDurationSig{}
+	_ builtinFunc = &builtinLTJSONSig{}
 
 	_ builtinFunc = &builtinLEIntSig{}
 	_ builtinFunc = &builtinLERealSig{}
+	_ builtinFunc = &builtinLEDecimalSig{}
 	_ builtinFunc = &builtinLEStringSig{}
+	_ builtinFunc = &builtinLETimeSig{}
+	_ builtinFunc = &builtinLEDurationSig{}
+	_ builtinFunc = &builtinLEJSONSig{}
 
 	_ builtinFunc = &builtinGTIntSig{}
 	_ builtinFunc = &builtinGTRealSig{}
+	_ builtinFunc = &builtinGTDecimalSig{}
 	_ builtinFunc = &builtinGTStringSig{}
+	_ builtinFunc = &builtinGTTimeSig{}
+	_ builtinFunc = &builtinGTDurationSig{}
+	_ builtinFunc = &builtinGTJSONSig{}
 
 	_ builtinFunc = &builtinGEIntSig{}
 	_ builtinFunc = &builtinGERealSig{}
+	_ builtinFunc = &builtinGEDecimalSig{}
 	_ builtinFunc = &builtinGEStringSig{}
+	_ builtinFunc = &builtinGETimeSig{}
+	_ builtinFunc = &builtinGEDurationSig{}
+	_ builtinFunc = &builtinGEJSONSig{}
 
 	_ builtinFunc = &builtinNEIntSig{}
 	_ builtinFunc = &builtinNERealSig{}
+	_ builtinFunc = &builtinNEDecimalSig{}
 	_ builtinFunc = &builtinNEStringSig{}
+	_ builtinFunc = &builtinNETimeSig{}
+	_ builtinFunc = &builtinNEDurationSig{}
+	_ builtinFunc = &builtinNEJSONSig{}
+	_ builtinFunc = &builtinEQJSONSig{}
+
+	_ builtinFunc = &builtinNullEQIntSig{}
+	_ builtinFunc = &builtinNullEQRealSig{}
+	_ builtinFunc = &builtinNullEQDecimalSig{}
+	_ builtinFunc = &builtinNullEQStringSig{}
+	_ builtinFunc = &builtinNullEQTimeSig{}
+	_ builtinFunc = &builtinNullEQDurationSig{}
+	_ builtinFunc = &builtinNullEQJSONSig{}
 )
 
-type compareFunctionClass struct {
+// coalesceFunctionClass returns the first non-NULL value in the list,
+// or NULL if there are no non-NULL values.
+type coalesceFunctionClass struct {
 	baseFunctionClass
+}
 
-	op opcode.Op
+func (c *coalesceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	fieldTps := make([]*types.FieldType, 0, len(args))
+	notNull := false
+	for _, arg := range args {
+		fieldTps = append(fieldTps, arg.GetType())
+		notNull = notNull || mysql.HasNotNullFlag(arg.GetType().Flag)
+	}
+	resultFieldType := inferType4MultiArgControlFuncs(fieldTps)
+	// The result is NOT NULL as long as one of the arguments is.
+	resultFieldType.Flag &^= mysql.NotNullFlag
+	if notNull {
+		resultFieldType.Flag |= mysql.NotNullFlag
+	}
+
+	retEvalTp := resultFieldType.EvalType()
+	argTps := make([]types.EvalType, 0, len(args))
+	for i := range args {
+		if retEvalTp == types.ETDecimal {
+			args[i] = wrapWithCastAsDecimalOfTp(ctx, args[i], resultFieldType)
+		}
+		argTps = append(argTps, retEvalTp)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, retEvalTp, argTps...)
+	bf.tp = resultFieldType
+
+	switch retEvalTp {
+	case types.ETInt:
+		sig = &builtinCoalesceIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceInt)
+	case types.ETReal:
+		sig = &builtinCoalesceRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceReal)
+	case types.ETDecimal:
+		sig = &builtinCoalesceDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceDecimal)
+	case types.ETString:
+		sig = &builtinCoalesceStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCoalesceTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceTime)
+	case types.ETDuration:
+		sig = &builtinCoalesceDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceDuration)
+	case types.ETJson:
+		sig = &builtinCoalesceJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceJson)
+	}
+	return sig, nil
 }
 
-// getBaseCmpType gets the EvalType that the two args will be treated as when comparing.
-func getBaseCmpType(lhs, rhs types.EvalType, lft, rft *types.FieldType) types.EvalType {
-	if lft.Tp == mysql.TypeUnspecified || rft.Tp == mysql.TypeUnspecified {
-		if lft.Tp == rft.Tp {
-			return types.ETString
+// builtinCoalesceIntSig is builtin function coalesce signature which return type int
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceIntSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalInt(b.ctx, row)
+		if err != nil || !isNull {
+			break
 		}
-		if lft.Tp == mysql.TypeUnspecified {
-			lhs = rhs
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceRealSig is builtin function coalesce signature which return type real
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceRealSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalReal(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceDecimalSig is builtin function coalesce signature which return type decimal
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalDecimal(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceStringSig is builtin function coalesce signature which return type string
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceStringSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalString(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceTimeSig is builtin function coalesce signature which return type time
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceTimeSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalTime(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceDurationSig is builtin function coalesce signature which return type duration
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceDurationSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalDuration(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceJSONSig is builtin function coalesce signature which return type json.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceJSONSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceJSONSig) evalJSON(row chunk.Row) (res types.BinaryJSON, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalJSON(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// getCmpTp4MinMax gets the EvalType that the arguments of GREATEST and LEAST
+// are compared as.
+func getCmpTp4MinMax(args []Expression) types.EvalType {
+	lft := args[0].GetType()
+	cmpTp, allTemporal := lft.EvalType(), true
+	allUnsigned, allSigned := true, true
+	for _, arg := range args {
+		rft := arg.GetType()
+		switch rft.EvalType() {
+		case types.ETDatetime, types.ETTimestamp:
+		default:
+			allTemporal = false
+		}
+		if mysql.HasUnsignedFlag(rft.Flag) {
+			allSigned = false
 		} else {
-			rhs = lhs
+			allUnsigned = false
 		}
+		cmpTp = getBaseCmpType(cmpTp, rft.EvalType(), lft, rft)
+		lft = rft
 	}
-	if lhs.IsStringKind() && rhs.IsStringKind() {
+	switch {
+	case allTemporal:
+		return types.ETDatetime
+	case cmpTp == types.ETJson:
+		// JSON arguments are compared as strings, the same as MySQL does.
 		return types.ETString
-	} else if (lhs == types.ETInt || lft.Hybrid()) && (rhs == types.ETInt || rft.Hybrid()) {
-		return types.ETInt
+	case cmpTp == types.ETInt && !allUnsigned && !allSigned:
+		// Mixed signed and unsigned integers can not be compared as int64 or uint64.
+		return types.ETDecimal
 	}
-	return types.ETReal
+	return cmpTp
 }
 
-// GetAccurateCmpType uses a more complex logic to decide the EvalType of the two args when compare with each other than
-// getBaseCmpType does.
-func GetAccurateCmpType(lhs, rhs Expression) types.EvalType {
-	lhsFieldType, rhsFieldType := lhs.GetType(), rhs.GetType()
-	lhsEvalType, rhsEvalType := lhsFieldType.EvalType(), rhsFieldType.EvalType()
-	cmpType := getBaseCmpType(lhsEvalType, rhsEvalType, lhsFieldType, rhsFieldType)
-	return cmpType
+// getFunction4MinMax builds the signature argument of GREATEST and LEAST,
+// the arguments are compared and returned as the type given by getCmpTp4MinMax.
+func getFunction4MinMax(ctx sessionctx.Context, args []Expression) (bf baseBuiltinFunc, cmpTp types.EvalType) {
+	cmpTp = getCmpTp4MinMax(args)
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, cmpTp)
+	}
+	bf = newBaseBuiltinFuncWithTp(ctx, args, cmpTp, argTps...)
+	switch cmpTp {
+	case types.ETInt:
+		if mysql.HasUnsignedFlag(args[0].GetType().Flag) {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+	case types.ETDecimal:
+		flen, decimal := 0, 0
+		for _, arg := range args {
+			argTp := arg.GetType()
+			if argTp.Flen == types.UnspecifiedLength || argTp.Decimal == types.UnspecifiedLength {
+				flen, decimal = types.UnspecifiedLength, types.UnspecifiedLength
+				break
+			}
+			flen = mathutil.Max(flen, argTp.Flen-argTp.Decimal)
+			decimal = mathutil.Max(decimal, argTp.Decimal)
+		}
+		if decimal == types.UnspecifiedLength {
+			bf.tp.Flen, bf.tp.Decimal = types.UnspecifiedLength, types.UnspecifiedLength
+			break
+		}
+		bf.tp.Flen, bf.tp.Decimal = mathutil.Min(flen+decimal, mysql.MaxDecimalWidth), mathutil.Min(decimal, mysql.MaxDecimalScale)
+		for i := range bf.args {
+			bf.args[i] = wrapWithCastAsDecimalOfTp(ctx, bf.args[i], bf.tp)
+		}
+	case types.ETString:
+		isBinary := false
+		for _, arg := range args {
+			bf.tp.Flen = mathutil.Max(bf.tp.Flen, arg.GetType().Flen)
+			isBinary = isBinary || types.IsBinaryStr(arg.GetType())
+		}
+		if isBinary {
+			types.SetBinChsClnFlag(bf.tp)
+		}
+	case types.ETDatetime:
+		// The result keeps the type of the arguments if they are all the same,
+		// e.g. GREATEST of two DATEs is a DATE.
+		bf.tp.Tp, bf.tp.Decimal = args[0].GetType().Tp, 0
+		for _, arg := range args {
+			if arg.GetType().Tp != bf.tp.Tp {
+				bf.tp.Tp = mysql.TypeDatetime
+			}
+			bf.tp.Decimal = mathutil.Max(bf.tp.Decimal, arg.GetType().Decimal)
+		}
+		if bf.tp.Tp == mysql.TypeDate {
+			bf.tp.Flen, bf.tp.Decimal = mysql.MaxDateWidth, 0
+		} else {
+			bf.tp.Flen = mysql.MaxDatetimeWidthNoFsp
+			if bf.tp.Decimal > 0 {
+				bf.tp.Flen += 1 + bf.tp.Decimal
+			}
+		}
+	}
+	return bf, cmpTp
 }
 
-// GetCmpFunction get the compare function according to two arguments.
-func GetCmpFunction(lhs, rhs Expression) CompareFunc {
-	switch GetAccurateCmpType(lhs, rhs) {
+type greatestFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *greatestFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, cmpTp := getFunction4MinMax(ctx, args)
+	switch cmpTp {
 	case types.ETInt:
-		return CompareInt
+		sig = &builtinGreatestIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestInt)
 	case types.ETReal:
-		return CompareReal
+		sig = &builtinGreatestRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestReal)
+	case types.ETDecimal:
+		sig = &builtinGreatestDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestDecimal)
 	case types.ETString:
-		return CompareString
+		sig = &builtinGreatestStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestString)
+	case types.ETDatetime:
+		sig = &builtinGreatestTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestTime)
 	}
-	return nil
+	return sig, nil
+}
+
+// builtinGreatestIntSig is builtin function greatest signature which compares the arguments as int.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+type builtinGreatestIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestIntSig) Clone() builtinFunc {
+	newSig := &builtinGreatestIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinGreatestIntSig, it returns the largest argument, or NULL if any argument is NULL.
+func (b *builtinGreatestIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	isUnsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || isUnsigned && uint64(v) > uint64(res) || !isUnsigned && v > res {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+// builtinGreatestRealSig is builtin function greatest signature which compares the arguments as real.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+type builtinGreatestRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestRealSig) Clone() builtinFunc {
+	newSig := &builtinGreatestRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinGreatestRealSig, it returns the largest argument, or NULL if any argument is NULL.
+func (b *builtinGreatestRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || v > res {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+// builtinGreatestDecimalSig is builtin function greatest signature which compares the arguments as decimal.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+type builtinGreatestDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestDecimalSig) Clone() builtinFunc {
+	newSig := &builtinGreatestDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinGreatestDecimalSig, it returns the largest argument, or NULL if any argument is NULL.
+func (b *builtinGreatestDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalDecimal(b.ctx, row)
+		if isNull || err != nil {
+			return nil, true, err
+		}
+		if i == 0 || v.Compare(res) > 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+// builtinGreatestStringSig is builtin function greatest signature which compares the arguments as string.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+type builtinGreatestStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestStringSig) Clone() builtinFunc {
+	newSig := &builtinGreatestStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinGreatestStringSig, it returns the largest argument, or NULL if any argument is NULL.
+func (b *builtinGreatestStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+		if i == 0 || types.CompareString(v, res) > 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+// builtinGreatestTimeSig is builtin function greatest signature which compares the arguments as time.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+type builtinGreatestTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestTimeSig) Clone() builtinFunc {
+	newSig := &builtinGreatestTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinGreatestTimeSig, it returns the largest argument, or NULL if any argument is NULL.
+func (b *builtinGreatestTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return types.ZeroTime, true, err
+		}
+		if i == 0 || v.Compare(res) > 0 {
+			res = v
+		}
+	}
+	if res.Type() != b.tp.Tp {
+		res.SetType(b.tp.Tp)
+	}
+	return res, false, nil
+}
+
+type leastFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *leastFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, cmpTp := getFunction4MinMax(ctx, args)
+	switch cmpTp {
+	case types.ETInt:
+		sig = &builtinLeastIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastInt)
+	case types.ETReal:
+		sig = &builtinLeastRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastReal)
+	case types.ETDecimal:
+		sig = &builtinLeastDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastDecimal)
+	case types.ETString:
+		sig = &builtinLeastStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastString)
+	case types.ETDatetime:
+		sig = &builtinLeastTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastTime)
+	}
+	return sig, nil
+}
+
+// builtinLeastIntSig is builtin function least signature which compares the arguments as int.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+type builtinLeastIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastIntSig) Clone() builtinFunc {
+	newSig := &builtinLeastIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinLeastIntSig, it returns the smallest argument, or NULL if any argument is NULL.
+func (b *builtinLeastIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	isUnsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || isUnsigned && uint64(v) < uint64(res) || !isUnsigned && v < res {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+// builtinLeastRealSig is builtin function least signature which compares the arguments as real.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+type builtinLeastRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastRealSig) Clone() builtinFunc {
+	newSig := &builtinLeastRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLeastRealSig, it returns the smallest argument, or NULL if any argument is NULL.
+func (b *builtinLeastRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || v < res {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+// builtinLeastDecimalSig is builtin function least signature which compares the arguments as decimal.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+type builtinLeastDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastDecimalSig) Clone() builtinFunc {
+	newSig := &builtinLeastDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinLeastDecimalSig, it returns the smallest argument, or NULL if any argument is NULL.
+func (b *builtinLeastDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalDecimal(b.ctx, row)
+		if isNull || err != nil {
+			return nil, true, err
+		}
+		if i == 0 || v.Compare(res) < 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+// builtinLeastStringSig is builtin function least signature which compares the arguments as string.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+type builtinLeastStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastStringSig) Clone() builtinFunc {
+	newSig := &builtinLeastStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinLeastStringSig, it returns the smallest argument, or NULL if any argument is NULL.
+func (b *builtinLeastStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+		if i == 0 || types.CompareString(v, res) < 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+// builtinLeastTimeSig is builtin function least signature which compares the arguments as time.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+type builtinLeastTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastTimeSig) Clone() builtinFunc {
+	newSig := &builtinLeastTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinLeastTimeSig, it returns the smallest argument, or NULL if any argument is NULL.
+func (b *builtinLeastTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return types.ZeroTime, true, err
+		}
+		if i == 0 || v.Compare(res) < 0 {
+			res = v
+		}
+	}
+	if res.Type() != b.tp.Tp {
+		res.SetType(b.tp.Tp)
+	}
+	return res, false, nil
+}
+
+type compareFunctionClass struct {
+	baseFunctionClass
+
+	op opcode.Op
+}
+
+// isUntypedArg checks whether ft is the type of a parameter or a NULL literal.
+func isUntypedArg(ft *types.FieldType) bool {
+	return ft.Tp == mysql.TypeUnspecified || ft.Tp == mysql.TypeNull
+}
+
+// getBaseCmpType gets the EvalType that the two args will be treated as when comparing.
+func getBaseCmpType(lhs, rhs types.EvalType, lft, rft *types.FieldType) types.EvalType {
+	// A parameter or a NULL literal takes the type of the other side, so the other side needs no cast.
+	if isUntypedArg(lft) || isUntypedArg(rft) {
+		if isUntypedArg(lft) && isUntypedArg(rft) {
+			return types.ETString
+		}
+		if isUntypedArg(lft) {
+			lhs = rhs
+		} else {
+			rhs = lhs
+		}
+	}
+	// A value compared with a JSON is converted to JSON.
+	if lhs == types.ETJson || rhs == types.ETJson {
+		return types.ETJson
+	}
+	if lhs.IsStringKind() && rhs.IsStringKind() {
+		return types.ETString
+	} else if (lhs == types.ETInt || lft.Hybrid()) && (rhs == types.ETInt || rft.Hybrid()) {
+		return types.ETInt
+	} else if ((lhs == types.ETInt || lft.Hybrid()) || lhs == types.ETDecimal) &&
+		((rhs == types.ETInt || rft.Hybrid()) || rhs == types.ETDecimal) {
+		return types.ETDecimal
+	}
+	return types.ETReal
+}
+
+// GetAccurateCmpType uses a more complex logic to decide the EvalType of the two args when compare with each other than
+// getBaseCmpType does.
+func GetAccurateCmpType(lhs, rhs Expression) types.EvalType {
+	lhsFieldType, rhsFieldType := lhs.GetType(), rhs.GetType()
+	lhsEvalType, rhsEvalType := lhsFieldType.EvalType(), rhsFieldType.EvalType()
+	cmpType := getBaseCmpType(lhsEvalType, rhsEvalType, lhsFieldType, rhsFieldType)
+	if cmpType == types.ETString && (types.IsTypeTime(lhsFieldType.Tp) || types.IsTypeTime(rhsFieldType.Tp)) {
+		// date[time] <cmp> date[time]
+		// string <cmp> date[time]
+		// compare as time
+		if lhsFieldType.Tp == rhsFieldType.Tp {
+			cmpType = lhsFieldType.EvalType()
+		} else {
+			cmpType = types.ETDatetime
+		}
+	} else if lhsFieldType.Tp == mysql.TypeDuration && rhsFieldType.Tp == mysql.TypeDuration {
+		// duration <cmp> duration
+		// compare as duration
+		cmpType = types.ETDuration
+	} else if cmpType == types.ETReal || cmpType == types.ETString {
+		_, isLHSConst := lhs.(*Constant)
+		_, isRHSConst := rhs.(*Constant)
+		if isTemporalColumn(lhs) && isRHSConst ||
+			isTemporalColumn(rhs) && isLHSConst {
+			/*
+				<temporal column> <cmp> <non-temporal constant>
+				or
+				<non-temporal constant> <cmp> <temporal column>
+
+				Convert the constant to temporal type.
+			*/
+			col, isLHSColumn := lhs.(*Column)
+			if !isLHSColumn {
+				col = rhs.(*Column)
+			}
+			if col.GetType().Tp == mysql.TypeDuration {
+				cmpType = types.ETDuration
+			} else {
+				cmpType = types.ETDatetime
+			}
+		}
+	}
+	return cmpType
+}
+
+// isTemporalColumn checks if a expression is a temporal column,
+// temporal column indicates time column or duration column.
+func isTemporalColumn(expr Expression) bool {
+	if _, isCol := expr.(*Column); !isCol {
+		return false
+	}
+	tp := expr.GetType().Tp
+	return types.IsTypeTime(tp) || tp == mysql.TypeDuration
+}
+
+// GetCmpFunction get the compare function according to two arguments.
+func GetCmpFunction(lhs, rhs Expression) CompareFunc {
+	switch GetAccurateCmpType(lhs, rhs) {
+	case types.ETInt:
+		return CompareInt
+	case types.ETReal:
+		return CompareReal
+	case types.ETDecimal:
+		return CompareDecimal
+	case types.ETString:
+		return CompareString
+	case types.ETDatetime, types.ETTimestamp:
+		return CompareTime
+	case types.ETDuration:
+		return CompareDuration
+	case types.ETJson:
+		return CompareJSON
+	}
+	return nil
+}
+
+// getFunction sets compare built-in function signatures for various types.
+func (c *compareFunctionClass) getFunction(ctx sessionctx.Context, rawArgs []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(rawArgs); err != nil {
+		return nil, err
+	}
+	args := c.refineArgs(ctx, rawArgs)
+	cmpType := GetAccurateCmpType(args[0], args[1])
+	if cmpType == types.ETJson {
+		// A string compared with a JSON is a JSON string, not a JSON text.
+		for i := range args {
+			args[i] = WrapWithCastAsJSON(ctx, args[i])
+			DisableParseJSONFlag4Expr(args[i])
+		}
+	}
+	sig, err = c.generateCmpSigs(ctx, args, cmpType)
+	return sig, err
+}
+
+// symmetricOp is the op of "b op a" which has the same result as "a op b".
+var symmetricOp = map[opcode.Op]opcode.Op{
+	opcode.LT:     opcode.GT,
+	opcode.GE:     opcode.LE,
+	opcode.GT:     opcode.LT,
+	opcode.LE:     opcode.GE,
+	opcode.EQ:     opcode.EQ,
+	opcode.NE:     opcode.NE,
+	opcode.NullEQ: opcode.NullEQ,
+}
+
+// refineArgs rewrites the decimal constant compared with an integer expression to an
+// integer constant, so that the integer side needs no cast and ranges can still be built on it.
+func (c *compareFunctionClass) refineArgs(ctx sessionctx.Context, args []Expression) []Expression {
+	arg0IsInt := args[0].GetType().EvalType() == types.ETInt
+	arg1IsInt := args[1].GetType().EvalType() == types.ETInt
+	arg0, arg0IsCon := args[0].(*Constant)
+	arg1, arg1IsCon := args[1].(*Constant)
+	if arg0IsInt && !arg0IsCon && arg1IsCon {
+		return []Expression{args[0], refineDecimalConstant(ctx, arg1, c.op)}
+	}
+	if arg1IsInt && !arg1IsCon && arg0IsCon {
+		return []Expression{refineDecimalConstant(ctx, arg0, symmetricOp[c.op]), args[1]}
+	}
+	return args
+}
+
+// refineDecimalConstant finds the integer constant which keeps the result of "int_expr op con".
+// e.g. "a < 2.1" is the same as "a < 3" and "a > -2.1" is the same as "a > -3".
+// The constant is returned unchanged if there is no such integer.
+func refineDecimalConstant(ctx sessionctx.Context, con *Constant, op opcode.Op) *Constant {
+	if con.GetType().EvalType() != types.ETDecimal || con.Value.IsNull() {
+		return con
+	}
+	dec, err := con.Value.ToDecimal(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return con
+	}
+	truncated := new(types.MyDecimal)
+	if err = dec.Round(truncated, 0, types.ModeTruncate); err != nil {
+		return con
+	}
+	val, err := truncated.ToInt()
+	if err != nil {
+		return con
+	}
+	if truncated.Compare(dec) != 0 {
+		switch op {
+		case opcode.LT, opcode.GE:
+			// Use the ceiling of the constant.
+			if !dec.IsNegative() {
+				if val == math.MaxInt64 {
+					return con
+				}
+				val++
+			}
+		case opcode.LE, opcode.GT:
+			// Use the floor of the constant.
+			if dec.IsNegative() {
+				if val == math.MinInt64 {
+					return con
+				}
+				val--
+			}
+		default:
+			return con
+		}
+	}
+	return &Constant{Value: types.NewIntDatum(val), RetType: types.NewFieldType(mysql.TypeLonglong)}
+}
+
+// generateCmpSigs generates compare function signatures.
+func (c *compareFunctionClass) generateCmpSigs(ctx sessionctx.Context, args []Expression, tp types.EvalType) (sig builtinFunc, err error) {
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, tp, tp)
+	bf.tp.Flen = 1
+	switch tp {
+	case types.ETInt:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTInt)
+		case opcode.LE:
+			sig = &builtinLEIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEInt)
+		case opcode.GT:
+			sig = &builtinGTIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTInt)
+		case opcode.EQ:
+			sig = &builtinEQIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQInt)
+		case opcode.GE:
+			sig = &builtinGEIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEInt)
+		case opcode.NE:
+			sig = &builtinNEIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEInt)
+		case opcode.NullEQ:
+			sig = &builtinNullEQIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQInt)
+		}
+	case types.ETReal:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTReal)
+		case opcode.LE:
+			sig = &builtinLERealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEReal)
+		case opcode.GT:
+			sig = &builtinGTRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTReal)
+		case opcode.GE:
+			sig = &builtinGERealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEReal)
+		case opcode.EQ:
+			sig = &builtinEQRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQReal)
+		case opcode.NE:
+			sig = &builtinNERealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEReal)
+		case opcode.NullEQ:
+			sig = &builtinNullEQRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQReal)
+		}
+	case types.ETDecimal:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTDecimal)
+		case opcode.LE:
+			sig = &builtinLEDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEDecimal)
+		case opcode.GT:
+			sig = &builtinGTDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTDecimal)
+		case opcode.GE:
+			sig = &builtinGEDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEDecimal)
+		case opcode.EQ:
+			sig = &builtinEQDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQDecimal)
+		case opcode.NE:
+			sig = &builtinNEDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEDecimal)
+		case opcode.NullEQ:
+			sig = &builtinNullEQDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQDecimal)
+		}
+	case types.ETString:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTString)
+		case opcode.LE:
+			sig = &builtinLEStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEString)
+		case opcode.GT:
+			sig = &builtinGTStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTString)
+		case opcode.GE:
+			sig = &builtinGEStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEString)
+		case opcode.EQ:
+			sig = &builtinEQStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQString)
+		case opcode.NE:
+			sig = &builtinNEStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEString)
+		case opcode.NullEQ:
+			sig = &builtinNullEQStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQString)
+		}
+	case types.ETDatetime, types.ETTimestamp:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTTimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTTime)
+		case opcode.LE:
+			sig = &builtinLETimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LETime)
+		case opcode.GT:
+			sig = &builtinGTTimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTTime)
+		case opcode.GE:
+			sig = &builtinGETimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GETime)
+		case opcode.EQ:
+			sig = &builtinEQTimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQTime)
+		case opcode.NE:
+			sig = &builtinNETimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NETime)
+		case opcode.NullEQ:
+			sig = &builtinNullEQTimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQTime)
+		}
+	case types.ETDuration:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTDuration)
+		case opcode.LE:
+			sig = &builtinLEDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEDuration)
+		case opcode.GT:
+			sig = &builtinGTDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTDuration)
+		case opcode.GE:
+			sig = &builtinGEDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEDuration)
+		case opcode.EQ:
+			sig = &builtinEQDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQDuration)
+		case opcode.NE:
+			sig = &builtinNEDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEDuration)
+		case opcode.NullEQ:
+			sig = &builtinNullEQDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQDuration)
+		}
+	case types.ETJson:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTJson)
+		case opcode.LE:
+			sig = &builtinLEJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEJson)
+		case opcode.GT:
+			sig = &builtinGTJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTJson)
+		case opcode.GE:
+			sig = &builtinGEJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEJson)
+		case opcode.EQ:
+			sig = &builtinEQJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQJson)
+		case opcode.NE:
+			sig = &builtinNEJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEJson)
+		case opcode.NullEQ:
+			sig = &builtinNullEQJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQJson)
+		}
+	}
+	return
+}
+
+type builtinLTIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTIntSig) Clone() builtinFunc {
+	newSig := &builtinLTIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTRealSig) Clone() builtinFunc {
+	newSig := &builtinLTRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTDecimalSig) Clone() builtinFunc {
+	newSig := &builtinLTDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTStringSig) Clone() builtinFunc {
+	newSig := &builtinLTStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTTimeSig) Clone() builtinFunc {
+	newSig := &builtinLTTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTTimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTDurationSig) Clone() builtinFunc {
+	newSig := &builtinLTDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTJSONSig) Clone() builtinFunc {
+	newSig := &builtinLTJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEIntSig) Clone() builtinFunc {
+	newSig := &builtinLEIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLERealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLERealSig) Clone() builtinFunc {
+	newSig := &builtinLERealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEDecimalSig) Clone() builtinFunc {
+	newSig := &builtinLEDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEStringSig) Clone() builtinFunc {
+	newSig := &builtinLEStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLETimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLETimeSig) Clone() builtinFunc {
+	newSig := &builtinLETimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLETimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEDurationSig) Clone() builtinFunc {
+	newSig := &builtinLEDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEJSONSig) Clone() builtinFunc {
+	newSig := &builtinLEJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTIntSig) Clone() builtinFunc {
+	newSig := &builtinGTIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTRealSig) Clone() builtinFunc {
+	newSig := &builtinGTRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTDecimalSig) Clone() builtinFunc {
+	newSig := &builtinGTDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTStringSig) Clone() builtinFunc {
+	newSig := &builtinGTStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTTimeSig) Clone() builtinFunc {
+	newSig := &builtinGTTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTTimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTDurationSig) Clone() builtinFunc {
+	newSig := &builtinGTDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTJSONSig) Clone() builtinFunc {
+	newSig := &builtinGTJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGEIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGEIntSig) Clone() builtinFunc {
+	newSig := &builtinGEIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGERealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGERealSig) Clone() builtinFunc {
+	newSig := &builtinGERealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGEDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGEDecimalSig) Clone() builtinFunc {
+	newSig := &builtinGEDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGEDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGEStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGEStringSig) Clone() builtinFunc {
+	newSig := &builtinGEStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGETimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGETimeSig) Clone() builtinFunc {
+	newSig := &builtinGETimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGETimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGEDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGEDurationSig) Clone() builtinFunc {
+	newSig := &builtinGEDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGEDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGEJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGEJSONSig) Clone() builtinFunc {
+	newSig := &builtinGEJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGEJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinEQIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinEQIntSig) Clone() builtinFunc {
+	newSig := &builtinEQIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinEQIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinEQRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinEQRealSig) Clone() builtinFunc {
+	newSig := &builtinEQRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinEQRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-// getFunction sets compare built-in function signatures for various types.
-func (c *compareFunctionClass) getFunction(ctx sessionctx.Context, rawArgs []Expression) (sig builtinFunc, err error) {
-	if err = c.verifyArgs(rawArgs); err != nil {
-		return nil, err
-	}
-	cmpType := GetAccurateCmpType(rawArgs[0], rawArgs[1])
-	sig, err = c.generateCmpSigs(ctx, rawArgs, cmpType)
-	return sig, err
+type builtinEQDecimalSig struct {
+	baseBuiltinFunc
 }
 
-// generateCmpSigs generates compare function signatures.
-func (c *compareFunctionClass) generateCmpSigs(ctx sessionctx.Context, args []Expression, tp types.EvalType) (sig builtinFunc, err error) {
-	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, tp, tp)
-	bf.tp.Flen = 1
-	switch tp {
-	case types.ETInt:
-		switch c.op {
-		case opcode.LT:
-			sig = &builtinLTIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LTInt)
-		case opcode.LE:
-			sig = &builtinLEIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LEInt)
-		case opcode.GT:
-			sig = &builtinGTIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GTInt)
-		case opcode.EQ:
-			sig = &builtinEQIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_EQInt)
-		case opcode.GE:
-			sig = &builtinGEIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GEInt)
-		case opcode.NE:
-			sig = &builtinNEIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_NEInt)
-		}
-	case types.ETReal:
-		switch c.op {
-		case opcode.LT:
-			sig = &builtinLTRealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LTReal)
-		case opcode.LE:
-			sig = &builtinLERealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LEReal)
-		case opcode.GT:
-			sig = &builtinGTRealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GTReal)
-		case opcode.GE:
-			sig = &builtinGERealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GEReal)
-		case opcode.EQ:
-			sig = &builtinEQRealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_EQReal)
-		case opcode.NE:
-			sig = &builtinNERealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_NEReal)
-		}
-	case types.ETString:
-		switch c.op {
-		case opcode.LT:
-			sig = &builtinLTStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LTString)
-		case opcode.LE:
-			sig = &builtinLEStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LEString)
-		case opcode.GT:
-			sig = &builtinGTStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GTString)
-		case opcode.GE:
-			sig = &builtinGEStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GEString)
-		case opcode.EQ:
-			sig = &builtinEQStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_EQString)
-		case opcode.NE:
-			sig = &builtinNEStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_NEString)
-		}
-	}
-	return
+func (b *builtinEQDecimalSig) Clone() builtinFunc {
+	newSig := &builtinEQDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
 }
 
-type builtinLTIntSig struct {
+func (b *builtinEQDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinEQStringSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLTIntSig) Clone() builtinFunc {
-	newSig := &builtinLTIntSig{}
+func (b *builtinEQStringSig) Clone() builtinFunc {
+	newSig := &builtinEQStringSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLTIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLT(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinEQStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareString(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLTRealSig struct {
+type builtinEQTimeSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLTRealSig) Clone() builtinFunc {
-	newSig := &builtinLTRealSig{}
+func (b *builtinEQTimeSig) Clone() builtinFunc {
+	newSig := &builtinEQTimeSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLTRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLT(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinEQTimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLTStringSig struct {
+type builtinEQDurationSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLTStringSig) Clone() builtinFunc {
-	newSig := &builtinLTStringSig{}
+func (b *builtinEQDurationSig) Clone() builtinFunc {
+	newSig := &builtinEQDurationSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinEQDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLEIntSig struct {
+type builtinEQJSONSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLEIntSig) Clone() builtinFunc {
-	newSig := &builtinLEIntSig{}
+func (b *builtinEQJSONSig) Clone() builtinFunc {
+	newSig := &builtinEQJSONSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinEQJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLERealSig struct {
+type builtinNEIntSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLERealSig) Clone() builtinFunc {
-	newSig := &builtinLERealSig{}
+func (b *builtinNEIntSig) Clone() builtinFunc {
+	newSig := &builtinNEIntSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLEStringSig struct {
+type builtinNERealSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLEStringSig) Clone() builtinFunc {
-	newSig := &builtinLEStringSig{}
+func (b *builtinNERealSig) Clone() builtinFunc {
+	newSig := &builtinNERealSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGTIntSig struct {
+type builtinNEDecimalSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGTIntSig) Clone() builtinFunc {
-	newSig := &builtinGTIntSig{}
+func (b *builtinNEDecimalSig) Clone() builtinFunc {
+	newSig := &builtinNEDecimalSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGTIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGT(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNEDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGTRealSig struct {
+type builtinNEStringSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGTRealSig) Clone() builtinFunc {
-	newSig := &builtinGTRealSig{}
+func (b *builtinNEStringSig) Clone() builtinFunc {
+	newSig := &builtinNEStringSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGTRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGT(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGTStringSig struct {
+type builtinNETimeSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGTStringSig) Clone() builtinFunc {
-	newSig := &builtinGTStringSig{}
+func (b *builtinNETimeSig) Clone() builtinFunc {
+	newSig := &builtinNETimeSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNETimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGEIntSig struct {
+type builtinNEDurationSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGEIntSig) Clone() builtinFunc {
-	newSig := &builtinGEIntSig{}
+func (b *builtinNEDurationSig) Clone() builtinFunc {
+	newSig := &builtinNEDurationSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNEDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGERealSig struct {
+type builtinNEJSONSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGERealSig) Clone() builtinFunc {
-	newSig := &builtinGERealSig{}
+func (b *builtinNEJSONSig) Clone() builtinFunc {
+	newSig := &builtinNEJSONSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNEJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGEStringSig struct {
+type builtinNullEQIntSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGEStringSig) Clone() builtinFunc {
-	newSig := &builtinGEStringSig{}
+func (b *builtinNullEQIntSig) Clone() builtinFunc {
+	newSig := &builtinNullEQIntSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNullEQIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinEQIntSig struct {
+type builtinNullEQRealSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinEQIntSig) Clone() builtinFunc {
-	newSig := &builtinEQIntSig{}
+func (b *builtinNullEQRealSig) Clone() builtinFunc {
+	newSig := &builtinNullEQRealSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinEQIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfEQ(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNullEQRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinEQRealSig struct {
+type builtinNullEQDecimalSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinEQRealSig) Clone() builtinFunc {
-	newSig := &builtinEQRealSig{}
+func (b *builtinNullEQDecimalSig) Clone() builtinFunc {
+	newSig := &builtinNullEQDecimalSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinEQRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfEQ(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNullEQDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinEQStringSig struct {
+type builtinNullEQStringSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinEQStringSig) Clone() builtinFunc {
-	newSig := &builtinEQStringSig{}
+func (b *builtinNullEQStringSig) Clone() builtinFunc {
+	newSig := &builtinNullEQStringSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinEQStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfEQ(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNullEQStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareString(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinNEIntSig struct {
+type builtinNullEQTimeSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinNEIntSig) Clone() builtinFunc {
-	newSig := &builtinNEIntSig{}
+func (b *builtinNullEQTimeSig) Clone() builtinFunc {
+	newSig := &builtinNullEQTimeSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinNEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfNE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNullEQTimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareTime(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinNERealSig struct {
+type builtinNullEQDurationSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinNERealSig) Clone() builtinFunc {
-	newSig := &builtinNERealSig{}
+func (b *builtinNullEQDurationSig) Clone() builtinFunc {
+	newSig := &builtinNullEQDurationSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinNERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfNE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNullEQDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinNEStringSig struct {
+type builtinNullEQJSONSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinNEStringSig) Clone() builtinFunc {
-	newSig := &builtinNEStringSig{}
+func (b *builtinNullEQJSONSig) Clone() builtinFunc {
+	newSig := &builtinNullEQJSONSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinNEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfNE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNullEQJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
 }
 
 func resOfLT(val int64, isNull bool, err error) (int64, bool, error) {
@@ -504,6 +1881,19 @@ func resOfNE(val int64, isNull bool, err error) (int64, bool, error) {
 	return val, false, nil
 }
 
+// resOfNullEQ treats NULL as equal to NULL, so it never returns NULL.
+func resOfNullEQ(val int64, isNull bool, err error) (int64, bool, error) {
+	if err != nil {
+		return 0, true, err
+	}
+	if val == 0 {
+		val = 1
+	} else {
+		val = 0
+	}
+	return val, false, nil
+}
+
 // compareNull compares null values based on the following rules.
 // 1. NULL is considered to be equal to NULL
 // 2. NULL is considered to be smaller than a non-NULL value.
@@ -596,3 +1986,75 @@ func CompareReal(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhs
 	}
 	return int64(types.CompareFloat64(arg0, arg1)), false, nil
 }
+
+// CompareDecimal compares two decimals.
+func CompareDecimal(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalDecimal(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalDecimal(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(arg0.Compare(arg1)), false, nil
+}
+
+// CompareTime compares two datetime or timestamps.
+func CompareTime(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalTime(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalTime(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(arg0.Compare(arg1)), false, nil
+}
+
+// CompareDuration compares two durations.
+func CompareDuration(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalDuration(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalDuration(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(arg0.Compare(arg1)), false, nil
+}
+
+// CompareJSON compares two JSONs.
+func CompareJSON(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalJSON(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalJSON(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(types.CompareBinaryJSON(arg0, arg1)), false, nil
+}
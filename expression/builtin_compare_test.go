@@ -14,14 +14,18 @@
 package expression
 
 import (
+	"math"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
 )
 
 func (s *testEvaluatorSuite) TestCompare(c *C) {
-	intVal, uintVal, realVal, stringVal := 1, uint64(1), 1.1, "123"
+	intVal, uintVal, realVal, stringVal, decimalVal := 1, uint64(1), 1.1, "123", types.NewDecFromStringForTest("1.1")
 	// test cases for generating function signatures.
 	tests := []struct {
 		arg0     interface{}
@@ -34,6 +38,12 @@ func (s *testEvaluatorSuite) TestCompare(c *C) {
 		{stringVal, stringVal, ast.LT, mysql.TypeVarString, 0},
 		{realVal, realVal, ast.LT, mysql.TypeDouble, 0},
 		{uintVal, uintVal, ast.EQ, mysql.TypeLonglong, 1},
+		{intVal, intVal, ast.NullEQ, mysql.TypeLonglong, 1},
+		{stringVal, "abc", ast.NullEQ, mysql.TypeVarString, 0},
+		{realVal, realVal, ast.NullEQ, mysql.TypeDouble, 1},
+		{decimalVal, decimalVal, ast.LE, mysql.TypeNewDecimal, 1},
+		{intVal, decimalVal, ast.LT, mysql.TypeNewDecimal, 1},
+		{decimalVal, intVal, ast.NullEQ, mysql.TypeNewDecimal, 0},
 	}
 
 	for _, t := range tests {
@@ -48,3 +58,94 @@ func (s *testEvaluatorSuite) TestCompare(c *C) {
 		c.Assert(res, Equals, t.expected)
 	}
 }
+
+func (s *testEvaluatorSuite) TestNullEQ(c *C) {
+	tests := []struct {
+		arg0     interface{}
+		arg1     interface{}
+		expected int64
+	}{
+		{nil, nil, 1},
+		{1, nil, 0},
+		{nil, "abc", 0},
+		{1.5, 1.5, 1},
+	}
+	for _, t := range tests {
+		bf, err := funcs[ast.NullEQ].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{t.arg0, t.arg1}))
+		c.Assert(err, IsNil)
+		res, isNil, err := bf.evalInt(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(isNil, IsFalse)
+		c.Assert(res, Equals, t.expected)
+	}
+}
+
+func (s *testEvaluatorSuite) TestCoalesce(c *C) {
+	tm, err := types.ParseTime(s.ctx.GetSessionVars().StmtCtx, "2020-01-02 10:11:12", mysql.TypeDatetime, 0)
+	c.Assert(err, IsNil)
+	tests := []struct {
+		args     []interface{}
+		expected interface{}
+		retTp    byte
+	}{
+		{[]interface{}{nil}, nil, mysql.TypeString},
+		{[]interface{}{nil, nil}, nil, mysql.TypeString},
+		{[]interface{}{nil, nil, int64(2)}, int64(2), mysql.TypeLonglong},
+		{[]interface{}{nil, 1.5, int64(2)}, 1.5, mysql.TypeDouble},
+		{[]interface{}{nil, types.NewDecFromStringForTest("1.5"), int64(2)}, types.NewDecFromStringForTest("1.5"), mysql.TypeNewDecimal},
+		{[]interface{}{nil, "abc", int64(2)}, "abc", mysql.TypeVarchar},
+		{[]interface{}{nil, int64(2), "abc"}, "2", mysql.TypeVarchar},
+		{[]interface{}{tm, nil}, tm, mysql.TypeDatetime},
+	}
+	for _, t := range tests {
+		f, err := funcs[ast.Coalesce].getFunction(s.ctx, s.primitiveValsToConstants(t.args))
+		c.Assert(err, IsNil)
+		c.Assert(f.getRetTp().Tp, Equals, t.retTp, Commentf("%v", t.args))
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expected), Commentf("%v", t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestGreatestLeast(c *C) {
+	sc := s.ctx.GetSessionVars().StmtCtx
+	date, err := types.ParseDate(sc, "2020-01-02")
+	c.Assert(err, IsNil)
+	datetime, err := types.ParseTime(sc, "2020-01-01 10:11:12", mysql.TypeDatetime, 0)
+	c.Assert(err, IsNil)
+	date2, err := types.ParseDate(sc, "2019-12-31")
+	c.Assert(err, IsNil)
+	greatestDate, err := types.ParseTime(sc, "2020-01-02", mysql.TypeDatetime, 0)
+	c.Assert(err, IsNil)
+	tests := []struct {
+		args     []interface{}
+		greatest interface{}
+		least    interface{}
+		retTp    byte
+	}{
+		{[]interface{}{int64(1), int64(3), int64(-2)}, int64(3), int64(-2), mysql.TypeLonglong},
+		{[]interface{}{uint64(math.MaxUint64), uint64(1)}, uint64(math.MaxUint64), uint64(1), mysql.TypeLonglong},
+		{[]interface{}{uint64(math.MaxUint64), int64(-1)}, types.NewDecFromStringForTest("18446744073709551615"), types.NewDecFromStringForTest("-1"), mysql.TypeNewDecimal},
+		{[]interface{}{int64(1), 2.5, int64(-2)}, 2.5, float64(-2), mysql.TypeDouble},
+		{[]interface{}{int64(1), types.NewDecFromStringForTest("2.5")}, types.NewDecFromStringForTest("2.5"), types.NewDecFromStringForTest("1"), mysql.TypeNewDecimal},
+		{[]interface{}{"abc", "abd", "ab"}, "abd", "ab", mysql.TypeVarString},
+		{[]interface{}{"10", int64(9)}, float64(10), float64(9), mysql.TypeDouble},
+		{[]interface{}{int64(1), nil, int64(2)}, nil, nil, mysql.TypeLonglong},
+		{[]interface{}{date, date2}, date, date2, mysql.TypeDate},
+		{[]interface{}{date, datetime}, greatestDate, datetime, mysql.TypeDatetime},
+	}
+	for _, t := range tests {
+		for _, fn := range []string{ast.Greatest, ast.Least} {
+			f, err := funcs[fn].getFunction(s.ctx, s.primitiveValsToConstants(t.args))
+			c.Assert(err, IsNil)
+			c.Assert(f.getRetTp().Tp, Equals, t.retTp, Commentf("%s%v", fn, t.args))
+			d, err := evalBuiltinFunc(f, chunk.Row{})
+			c.Assert(err, IsNil)
+			expected := t.greatest
+			if fn == ast.Least {
+				expected = t.least
+			}
+			c.Assert(d, testutil.DatumEquals, types.NewDatum(expected), Commentf("%s%v", fn, t.args))
+		}
+	}
+}
@@ -290,3 +290,391 @@ func vecCompareInt(isUnsigned0, isUnsigned1 bool, largs, rargs, result *chunk.Co
 		types.VecCompareII(largs.Int64s(), rargs.Int64s(), result.Int64s())
 	}
 }
+
+func (b *builtinCoalesceIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCoalesceIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	res := result.Int64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalInt(b.ctx, input, buf); err != nil {
+			return err
+		}
+		args := buf.Int64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) && !buf.IsNull(i) {
+				result.SetNull(i, false)
+				res[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinCoalesceRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCoalesceRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	res := result.Float64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalReal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		args := buf.Float64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) && !buf.IsNull(i) {
+				result.SetNull(i, false)
+				res[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinCoalesceDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCoalesceDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	res := result.Decimals()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalDecimal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		args := buf.Decimals()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) && !buf.IsNull(i) {
+				result.SetNull(i, false)
+				res[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinCoalesceStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCoalesceStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for j := range b.args {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := b.args[j].VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs[j] = buf
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		isNull := true
+		for _, buf := range bufs {
+			if !buf.IsNull(i) {
+				result.AppendString(buf.GetString(i))
+				isNull = false
+				break
+			}
+		}
+		if isNull {
+			result.AppendNull()
+		}
+	}
+	return nil
+}
+
+func (b *builtinGreatestIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	isUnsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	res := result.Int64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalInt(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		args := buf.Int64s()
+		for i := 0; i < n; i++ {
+			if !result.IsNull(i) && (isUnsigned && uint64(args[i]) > uint64(res[i]) || !isUnsigned && args[i] > res[i]) {
+				res[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinGreatestRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	res := result.Float64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalReal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		args := buf.Float64s()
+		for i := 0; i < n; i++ {
+			if !result.IsNull(i) && (args[i] > res[i]) {
+				res[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinGreatestDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	res := result.Decimals()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalDecimal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		args := buf.Decimals()
+		for i := 0; i < n; i++ {
+			if !result.IsNull(i) && (args[i].Compare(&res[i]) > 0) {
+				res[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinGreatestStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for j := range b.args {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := b.args[j].VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs[j] = buf
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		isNull, res := false, ""
+		for j, buf := range bufs {
+			if buf.IsNull(i) {
+				isNull = true
+				break
+			}
+			if v := buf.GetString(i); j == 0 || types.CompareString(v, res) > 0 {
+				res = v
+			}
+		}
+		if isNull {
+			result.AppendNull()
+		} else {
+			result.AppendString(res)
+		}
+	}
+	return nil
+}
+
+func (b *builtinLeastIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	isUnsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	res := result.Int64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalInt(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		args := buf.Int64s()
+		for i := 0; i < n; i++ {
+			if !result.IsNull(i) && (isUnsigned && uint64(args[i]) < uint64(res[i]) || !isUnsigned && args[i] < res[i]) {
+				res[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinLeastRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	res := result.Float64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalReal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		args := buf.Float64s()
+		for i := 0; i < n; i++ {
+			if !result.IsNull(i) && (args[i] < res[i]) {
+				res[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinLeastDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, result); err != nil {
+		return err
+	}
+	buf, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	res := result.Decimals()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalDecimal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		args := buf.Decimals()
+		for i := 0; i < n; i++ {
+			if !result.IsNull(i) && (args[i].Compare(&res[i]) < 0) {
+				res[i] = args[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinLeastStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for j := range b.args {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := b.args[j].VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs[j] = buf
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		isNull, res := false, ""
+		for j, buf := range bufs {
+			if buf.IsNull(i) {
+				isNull = true
+				break
+			}
+			if v := buf.GetString(i); j == 0 || types.CompareString(v, res) < 0 {
+				res = v
+			}
+		}
+		if isNull {
+			result.AppendNull()
+		} else {
+			result.AppendString(res)
+		}
+	}
+	return nil
+}
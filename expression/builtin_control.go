@@ -24,17 +24,33 @@ import (
 )
 
 var (
+	_ functionClass = &caseWhenFunctionClass{}
 	_ functionClass = &ifFunctionClass{}
 	_ functionClass = &ifNullFunctionClass{}
 )
 
 var (
+	_ builtinFunc = &builtinCaseWhenIntSig{}
+	_ builtinFunc = &builtinCaseWhenRealSig{}
+	_ builtinFunc = &builtinCaseWhenDecimalSig{}
+	_ builtinFunc = &builtinCaseWhenStringSig{}
+	_ builtinFunc = &builtinCaseWhenTimeSig{}
+	_ builtinFunc = &builtinCaseWhenDurationSig{}
+	_ builtinFunc = &builtinCaseWhenJSONSig{}
 	_ builtinFunc = &builtinIfNullIntSig{}
 	_ builtinFunc = &builtinIfNullRealSig{}
+	_ builtinFunc = &builtinIfNullDecimalSig{}
 	_ builtinFunc = &builtinIfNullStringSig{}
+	_ builtinFunc = &builtinIfNullTimeSig{}
+	_ builtinFunc = &builtinIfNullDurationSig{}
+	_ builtinFunc = &builtinIfNullJSONSig{}
 	_ builtinFunc = &builtinIfIntSig{}
 	_ builtinFunc = &builtinIfRealSig{}
+	_ builtinFunc = &builtinIfDecimalSig{}
 	_ builtinFunc = &builtinIfStringSig{}
+	_ builtinFunc = &builtinIfTimeSig{}
+	_ builtinFunc = &builtinIfDurationSig{}
+	_ builtinFunc = &builtinIfJSONSig{}
 )
 
 // InferType4ControlFuncs infer result type for builtin IF, IFNULL, NULLIF, LEAD and LAG.
@@ -111,6 +127,264 @@ func InferType4ControlFuncs(lhs, rhs *types.FieldType) *types.FieldType {
 	return resultFieldType
 }
 
+type caseWhenFunctionClass struct {
+	baseFunctionClass
+}
+
+// inferType4MultiArgControlFuncs infers the result type of CASE and COALESCE by
+// merging the types of the arguments the result may come from one by one.
+func inferType4MultiArgControlFuncs(tps []*types.FieldType) *types.FieldType {
+	// NULL arguments do not take part in the merging unless all of them are NULL,
+	// otherwise an earlier pair of NULLs would turn the result into BINARY(0).
+	notNullTps := make([]*types.FieldType, 0, len(tps))
+	for _, tp := range tps {
+		if tp.Tp != mysql.TypeNull {
+			notNullTps = append(notNullTps, tp)
+		}
+	}
+	if len(notNullTps) == 0 {
+		return InferType4ControlFuncs(tps[0], tps[0])
+	}
+	resultFieldType := &types.FieldType{}
+	*resultFieldType = *notNullTps[0]
+	for _, tp := range notNullTps[1:] {
+		resultFieldType = InferType4ControlFuncs(resultFieldType, tp)
+	}
+	return resultFieldType
+}
+
+// inferType4CaseWhen infers the result type of CASE from the types of its
+// THEN and ELSE clauses.
+func inferType4CaseWhen(args []Expression) *types.FieldType {
+	l := len(args)
+	resultTps := make([]*types.FieldType, 0, (l+1)/2)
+	for i := 1; i < l; i += 2 {
+		resultTps = append(resultTps, args[i].GetType())
+	}
+	if l%2 == 1 {
+		resultTps = append(resultTps, args[l-1].GetType())
+	}
+	fieldTp := inferType4MultiArgControlFuncs(resultTps)
+	// The result is NULL when no WHEN clause is true and there is no ELSE.
+	fieldTp.Flag &^= mysql.NotNullFlag
+	return fieldTp
+}
+
+// wrapWithCastAsDecimalOfTp casts arg to the decimal type tp unless it is a
+// decimal of the same scale already, so a function returning one of its
+// arguments returns all the values with the scale of its result type.
+func wrapWithCastAsDecimalOfTp(ctx sessionctx.Context, arg Expression, tp *types.FieldType) Expression {
+	argTp := arg.GetType()
+	if tp.Decimal == types.UnspecifiedLength || argTp.EvalType() == types.ETDecimal && argTp.Decimal == tp.Decimal {
+		return arg
+	}
+	castTp := tp.Clone()
+	castTp.Flag &^= mysql.NotNullFlag
+	return BuildCastFunction(ctx, arg, castTp)
+}
+
+// getFunction see https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+// The arguments are the WHEN and THEN clauses in pairs, followed by the ELSE
+// clause if there is one.
+func (c *caseWhenFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	l := len(args)
+	fieldTp := inferType4CaseWhen(args)
+	tp := fieldTp.EvalType()
+	argTps := make([]types.EvalType, 0, l)
+	for i := 0; i < l-1; i += 2 {
+		if args[i], err = wrapWithIsTrue(ctx, args[i]); err != nil {
+			return nil, err
+		}
+		argTps = append(argTps, types.ETInt, tp)
+	}
+	if l%2 == 1 {
+		argTps = append(argTps, tp)
+	}
+	if tp == types.ETDecimal {
+		for i := 1; i < l; i += 2 {
+			args[i] = wrapWithCastAsDecimalOfTp(ctx, args[i], fieldTp)
+		}
+		if l%2 == 1 {
+			args[l-1] = wrapWithCastAsDecimalOfTp(ctx, args[l-1], fieldTp)
+		}
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, tp, argTps...)
+	bf.tp = fieldTp
+
+	switch tp {
+	case types.ETInt:
+		sig = &builtinCaseWhenIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenInt)
+	case types.ETReal:
+		sig = &builtinCaseWhenRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenReal)
+	case types.ETDecimal:
+		sig = &builtinCaseWhenDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenDecimal)
+	case types.ETString:
+		sig = &builtinCaseWhenStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCaseWhenTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenTime)
+	case types.ETDuration:
+		sig = &builtinCaseWhenDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenDuration)
+	case types.ETJson:
+		sig = &builtinCaseWhenJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenJson)
+	}
+	return sig, nil
+}
+
+// caseWhenBranch returns the index of the argument that gives the result of
+// CASE for row, or -1 if the result is NULL for missing an ELSE clause.
+func caseWhenBranch(ctx sessionctx.Context, args []Expression, row chunk.Row) (int, error) {
+	l := len(args)
+	for i := 0; i < l-1; i += 2 {
+		cond, isNull, err := args[i].EvalInt(ctx, row)
+		if err != nil {
+			return -1, err
+		}
+		if !isNull && cond != 0 {
+			return i + 1, nil
+		}
+	}
+	if l%2 == 1 {
+		return l - 1, nil
+	}
+	return -1, nil
+}
+
+type builtinCaseWhenIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenIntSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenIntSig) evalInt(row chunk.Row) (ret int64, isNull bool, err error) {
+	idx, err := caseWhenBranch(b.ctx, b.args, row)
+	if idx < 0 || err != nil {
+		return 0, true, err
+	}
+	return b.args[idx].EvalInt(b.ctx, row)
+}
+
+type builtinCaseWhenRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenRealSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenRealSig) evalReal(row chunk.Row) (ret float64, isNull bool, err error) {
+	idx, err := caseWhenBranch(b.ctx, b.args, row)
+	if idx < 0 || err != nil {
+		return 0, true, err
+	}
+	return b.args[idx].EvalReal(b.ctx, row)
+}
+
+type builtinCaseWhenDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenDecimalSig) evalDecimal(row chunk.Row) (ret *types.MyDecimal, isNull bool, err error) {
+	idx, err := caseWhenBranch(b.ctx, b.args, row)
+	if idx < 0 || err != nil {
+		return nil, true, err
+	}
+	return b.args[idx].EvalDecimal(b.ctx, row)
+}
+
+type builtinCaseWhenStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenStringSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenStringSig) evalString(row chunk.Row) (ret string, isNull bool, err error) {
+	idx, err := caseWhenBranch(b.ctx, b.args, row)
+	if idx < 0 || err != nil {
+		return "", true, err
+	}
+	return b.args[idx].EvalString(b.ctx, row)
+}
+
+type builtinCaseWhenTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenTimeSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenTimeSig) evalTime(row chunk.Row) (ret types.Time, isNull bool, err error) {
+	idx, err := caseWhenBranch(b.ctx, b.args, row)
+	if idx < 0 || err != nil {
+		return ret, true, err
+	}
+	return b.args[idx].EvalTime(b.ctx, row)
+}
+
+type builtinCaseWhenDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenDurationSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenDurationSig) evalDuration(row chunk.Row) (ret types.Duration, isNull bool, err error) {
+	idx, err := caseWhenBranch(b.ctx, b.args, row)
+	if idx < 0 || err != nil {
+		return ret, true, err
+	}
+	return b.args[idx].EvalDuration(b.ctx, row)
+}
+
+type builtinCaseWhenJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenJSONSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenJSONSig) evalJSON(row chunk.Row) (ret types.BinaryJSON, isNull bool, err error) {
+	idx, err := caseWhenBranch(b.ctx, b.args, row)
+	if idx < 0 || err != nil {
+		return ret, true, err
+	}
+	return b.args[idx].EvalJSON(b.ctx, row)
+}
+
 type ifFunctionClass struct {
 	baseFunctionClass
 }
@@ -120,6 +394,9 @@ func (c *ifFunctionClass) getFunction(ctx sessionctx.Context, args []Expression)
 	if err = c.verifyArgs(args); err != nil {
 		return nil, err
 	}
+	if args[0], err = wrapWithIsTrue(ctx, args[0]); err != nil {
+		return nil, err
+	}
 	retTp := InferType4ControlFuncs(args[1].GetType(), args[2].GetType())
 	evalTps := retTp.EvalType()
 	bf := newBaseBuiltinFuncWithTp(ctx, args, evalTps, types.ETInt, evalTps, evalTps)
@@ -132,9 +409,21 @@ func (c *ifFunctionClass) getFunction(ctx sessionctx.Context, args []Expression)
 	case types.ETReal:
 		sig = &builtinIfRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfReal)
+	case types.ETDecimal:
+		sig = &builtinIfDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfDecimal)
 	case types.ETString:
 		sig = &builtinIfStringSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinIfTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfTime)
+	case types.ETDuration:
+		sig = &builtinIfDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfDuration)
+	case types.ETJson:
+		sig = &builtinIfJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfJson)
 	}
 	return sig, nil
 }
@@ -154,12 +443,10 @@ func (b *builtinIfIntSig) evalInt(row chunk.Row) (ret int64, isNull bool, err er
 	if err != nil {
 		return 0, true, err
 	}
-	arg1, isNull1, err := b.args[1].EvalInt(b.ctx, row)
-	if (!isNull0 && arg0 != 0) || err != nil {
-		return arg1, isNull1, err
+	if !isNull0 && arg0 != 0 {
+		return b.args[1].EvalInt(b.ctx, row)
 	}
-	arg2, isNull2, err := b.args[2].EvalInt(b.ctx, row)
-	return arg2, isNull2, err
+	return b.args[2].EvalInt(b.ctx, row)
 }
 
 type builtinIfRealSig struct {
@@ -177,12 +464,31 @@ func (b *builtinIfRealSig) evalReal(row chunk.Row) (ret float64, isNull bool, er
 	if err != nil {
 		return 0, true, err
 	}
-	arg1, isNull1, err := b.args[1].EvalReal(b.ctx, row)
-	if (!isNull0 && arg0 != 0) || err != nil {
-		return arg1, isNull1, err
+	if !isNull0 && arg0 != 0 {
+		return b.args[1].EvalReal(b.ctx, row)
+	}
+	return b.args[2].EvalReal(b.ctx, row)
+}
+
+type builtinIfDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfDecimalSig) Clone() builtinFunc {
+	newSig := &builtinIfDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfDecimalSig) evalDecimal(row chunk.Row) (ret *types.MyDecimal, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return nil, true, err
+	}
+	if !isNull0 && arg0 != 0 {
+		return b.args[1].EvalDecimal(b.ctx, row)
 	}
-	arg2, isNull2, err := b.args[2].EvalReal(b.ctx, row)
-	return arg2, isNull2, err
+	return b.args[2].EvalDecimal(b.ctx, row)
 }
 
 type builtinIfStringSig struct {
@@ -200,12 +506,73 @@ func (b *builtinIfStringSig) evalString(row chunk.Row) (ret string, isNull bool,
 	if err != nil {
 		return "", true, err
 	}
-	arg1, isNull1, err := b.args[1].EvalString(b.ctx, row)
-	if (!isNull0 && arg0 != 0) || err != nil {
-		return arg1, isNull1, err
+	if !isNull0 && arg0 != 0 {
+		return b.args[1].EvalString(b.ctx, row)
+	}
+	return b.args[2].EvalString(b.ctx, row)
+}
+
+type builtinIfTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfTimeSig) Clone() builtinFunc {
+	newSig := &builtinIfTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfTimeSig) evalTime(row chunk.Row) (ret types.Time, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return ret, true, err
+	}
+	if !isNull0 && arg0 != 0 {
+		return b.args[1].EvalTime(b.ctx, row)
+	}
+	return b.args[2].EvalTime(b.ctx, row)
+}
+
+type builtinIfDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfDurationSig) Clone() builtinFunc {
+	newSig := &builtinIfDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfDurationSig) evalDuration(row chunk.Row) (ret types.Duration, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return ret, true, err
+	}
+	if !isNull0 && arg0 != 0 {
+		return b.args[1].EvalDuration(b.ctx, row)
+	}
+	return b.args[2].EvalDuration(b.ctx, row)
+}
+
+type builtinIfJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfJSONSig) Clone() builtinFunc {
+	newSig := &builtinIfJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfJSONSig) evalJSON(row chunk.Row) (ret types.BinaryJSON, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return ret, true, err
+	}
+	if !isNull0 && arg0 != 0 {
+		return b.args[1].EvalJSON(b.ctx, row)
 	}
-	arg2, isNull2, err := b.args[2].EvalString(b.ctx, row)
-	return arg2, isNull2, err
+	return b.args[2].EvalJSON(b.ctx, row)
 }
 
 type ifNullFunctionClass struct {
@@ -234,9 +601,21 @@ func (c *ifNullFunctionClass) getFunction(ctx sessionctx.Context, args []Express
 	case types.ETReal:
 		sig = &builtinIfNullRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfNullReal)
+	case types.ETDecimal:
+		sig = &builtinIfNullDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullDecimal)
 	case types.ETString:
 		sig = &builtinIfNullStringSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfNullString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinIfNullTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullTime)
+	case types.ETDuration:
+		sig = &builtinIfNullDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullDuration)
+	case types.ETJson:
+		sig = &builtinIfNullJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullJson)
 	}
 	return sig, nil
 }
@@ -279,6 +658,25 @@ func (b *builtinIfNullRealSig) evalReal(row chunk.Row) (float64, bool, error) {
 	return arg1, isNull || err != nil, err
 }
 
+type builtinIfNullDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullDecimalSig) Clone() builtinFunc {
+	newSig := &builtinIfNullDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	arg0, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalDecimal(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
+
 type builtinIfNullStringSig struct {
 	baseBuiltinFunc
 }
@@ -297,3 +695,60 @@ func (b *builtinIfNullStringSig) evalString(row chunk.Row) (string, bool, error)
 	arg1, isNull, err := b.args[1].EvalString(b.ctx, row)
 	return arg1, isNull || err != nil, err
 }
+
+type builtinIfNullTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullTimeSig) Clone() builtinFunc {
+	newSig := &builtinIfNullTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullTimeSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	arg0, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalTime(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
+
+type builtinIfNullDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullDurationSig) Clone() builtinFunc {
+	newSig := &builtinIfNullDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullDurationSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	arg0, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalDuration(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
+
+type builtinIfNullJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullJSONSig) Clone() builtinFunc {
+	newSig := &builtinIfNullJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullJSONSig) evalJSON(row chunk.Row) (types.BinaryJSON, bool, error) {
+	arg0, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalJSON(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
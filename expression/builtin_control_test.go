@@ -0,0 +1,111 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestCaseWhen(c *C) {
+	tbl := []struct {
+		Arg []interface{}
+		Ret interface{}
+	}{
+		{[]interface{}{true, int64(1), true, int64(2), int64(3)}, int64(1)},
+		{[]interface{}{false, int64(1), true, int64(2), int64(3)}, int64(2)},
+		{[]interface{}{nil, int64(1), false, int64(2), int64(3)}, int64(3)},
+		{[]interface{}{false, int64(1), false, int64(2)}, nil},
+		{[]interface{}{false, int64(1), nil, int64(2)}, nil},
+		{[]interface{}{0.4, int64(1), int64(3)}, int64(1)},
+		{[]interface{}{"0.4", int64(1), int64(3)}, int64(1)},
+		{[]interface{}{false, 1.5, true, int64(2)}, float64(2)},
+		{[]interface{}{true, types.NewDecFromStringForTest("1.1"), true, int64(2)}, types.NewDecFromStringForTest("1.1")},
+		{[]interface{}{false, int64(1), true, "abc"}, "abc"},
+		{[]interface{}{true, nil, false, int64(2)}, nil},
+	}
+	fc := funcs[ast.Case]
+	for _, t := range tbl {
+		f, err := fc.getFunction(s.ctx, s.primitiveValsToConstants(t.Arg))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.Ret), Commentf("%v", t.Arg))
+	}
+	f, err := fc.getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{true, int64(1)}))
+	c.Assert(err, IsNil)
+	c.Assert(mysql.HasNotNullFlag(f.getRetTp().Flag), IsFalse)
+}
+
+func (s *testEvaluatorSuite) TestInferType4MultiArgControlFuncs(c *C) {
+	nullTp := types.NewFieldType(mysql.TypeNull)
+	intTp := types.NewFieldType(mysql.TypeLonglong)
+	intTp.Flen, intTp.Decimal = 4, 0
+	uintTp := types.NewFieldType(mysql.TypeLonglong)
+	uintTp.Flen, uintTp.Decimal, uintTp.Flag = 3, 0, mysql.UnsignedFlag
+	realTp := types.NewFieldType(mysql.TypeDouble)
+	realTp.Flen, realTp.Decimal = 22, types.UnspecifiedLength
+	decTp := types.NewFieldType(mysql.TypeNewDecimal)
+	decTp.Flen, decTp.Decimal = 6, 2
+
+	tests := []struct {
+		tps     []*types.FieldType
+		tp      byte
+		flen    int
+		decimal int
+	}{
+		{[]*types.FieldType{nullTp, nullTp}, mysql.TypeString, 0, 0},
+		{[]*types.FieldType{nullTp, nullTp, intTp}, mysql.TypeLonglong, 4, 0},
+		{[]*types.FieldType{intTp, nullTp, uintTp}, mysql.TypeLonglong, 4, 0},
+		{[]*types.FieldType{intTp, decTp}, mysql.TypeNewDecimal, 6, 2},
+		{[]*types.FieldType{decTp, realTp}, mysql.TypeDouble, 22, types.UnspecifiedLength},
+	}
+	for i, t := range tests {
+		tp := inferType4MultiArgControlFuncs(t.tps)
+		c.Assert(tp.Tp, Equals, t.tp, Commentf("%d", i))
+		c.Assert(tp.Flen, Equals, t.flen, Commentf("%d", i))
+		c.Assert(tp.Decimal, Equals, t.decimal, Commentf("%d", i))
+	}
+}
+
+func (s *testEvaluatorSuite) TestIfWithNonIntCond(c *C) {
+	// A condition which is not an int is compared with 0 instead of being cast to an int.
+	tbl := []struct {
+		cond interface{}
+		ret  interface{}
+	}{
+		{0.4, int64(1)},
+		{"0.4", int64(1)},
+		{types.NewDecFromStringForTest("0.4"), int64(1)},
+		{0.0, int64(2)},
+		{nil, int64(2)},
+	}
+	for _, t := range tbl {
+		f, err := funcs[ast.If].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{t.cond, int64(1), int64(2)}))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.ret), Commentf("%v", t.cond))
+	}
+
+	f, err := funcs[ast.LogicAnd].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{0.4, int64(1)}))
+	c.Assert(err, IsNil)
+	d, err := evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d, testutil.DatumEquals, types.NewDatum(int64(1)))
+}
@@ -0,0 +1,663 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &jsonTypeFunctionClass{}
+	_ functionClass = &jsonExtractFunctionClass{}
+	_ functionClass = &jsonUnquoteFunctionClass{}
+	_ functionClass = &jsonArrayFunctionClass{}
+	_ functionClass = &jsonObjectFunctionClass{}
+	_ functionClass = &jsonSetFunctionClass{}
+	_ functionClass = &jsonInsertFunctionClass{}
+	_ functionClass = &jsonReplaceFunctionClass{}
+	_ functionClass = &jsonRemoveFunctionClass{}
+	_ functionClass = &jsonContainsFunctionClass{}
+	_ functionClass = &jsonKeysFunctionClass{}
+	_ functionClass = &jsonLengthFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinJSONTypeSig{}
+	_ builtinFunc = &builtinJSONExtractSig{}
+	_ builtinFunc = &builtinJSONUnquoteSig{}
+	_ builtinFunc = &builtinJSONArraySig{}
+	_ builtinFunc = &builtinJSONObjectSig{}
+	_ builtinFunc = &builtinJSONSetSig{}
+	_ builtinFunc = &builtinJSONInsertSig{}
+	_ builtinFunc = &builtinJSONReplaceSig{}
+	_ builtinFunc = &builtinJSONRemoveSig{}
+	_ builtinFunc = &builtinJSONContainsSig{}
+	_ builtinFunc = &builtinJSONKeysSig{}
+	_ builtinFunc = &builtinJSONLengthSig{}
+)
+
+// evalJSONPathExprs evaluates args as JSON path expressions, isNull is true
+// if any of the paths is NULL.
+func evalJSONPathExprs(ctx sessionctx.Context, args []Expression, row chunk.Row) ([]types.JSONPathExpression, bool, error) {
+	pathExprs := make([]types.JSONPathExpression, 0, len(args))
+	for _, arg := range args {
+		s, isNull, err := arg.EvalString(ctx, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		pathExpr, err := types.ParseJSONPathExpr(s)
+		if err != nil {
+			return nil, true, err
+		}
+		pathExprs = append(pathExprs, pathExpr)
+	}
+	return pathExprs, false, nil
+}
+
+// evalJSONOrNull evaluates arg as a JSON value, a NULL value becomes the JSON null literal.
+func evalJSONOrNull(ctx sessionctx.Context, arg Expression, row chunk.Row) (types.BinaryJSON, error) {
+	value, isNull, err := arg.EvalJSON(ctx, row)
+	if err != nil {
+		return value, err
+	}
+	if isNull {
+		value = types.CreateBinaryJSON(nil)
+	}
+	return value, nil
+}
+
+// extractJSONByPathArg extracts the value located by the path in args[idx] from obj.
+// The path must not contain any wildcard.
+func extractJSONByPathArg(ctx sessionctx.Context, obj types.BinaryJSON, args []Expression, idx int, row chunk.Row) (types.BinaryJSON, bool, error) {
+	pathExprs, isNull, err := evalJSONPathExprs(ctx, args[idx:idx+1], row)
+	if isNull || err != nil {
+		return obj, isNull, err
+	}
+	if pathExprs[0].ContainsAnyAsterisk() {
+		return obj, true, types.ErrInvalidJSONPathWildcard
+	}
+	ret, found := obj.Extract(pathExprs)
+	return ret, !found, nil
+}
+
+type jsonTypeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonTypeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETJson)
+	bf.tp.Flen = 51 // The longest type name is "UNSIGNED INTEGER".
+	sig := &builtinJSONTypeSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonTypeSig)
+	return sig, nil
+}
+
+type builtinJSONTypeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONTypeSig) Clone() builtinFunc {
+	newSig := &builtinJSONTypeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinJSONTypeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-attribute-functions.html#function_json-type
+func (b *builtinJSONTypeSig) evalString(row chunk.Row) (string, bool, error) {
+	obj, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return obj.Type(), false, nil
+}
+
+type jsonExtractFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonExtractFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETJson)
+	for range args[1:] {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONExtractSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonExtractSig)
+	return sig, nil
+}
+
+type builtinJSONExtractSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONExtractSig) Clone() builtinFunc {
+	newSig := &builtinJSONExtractSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONExtractSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-extract
+func (b *builtinJSONExtractSig) evalJSON(row chunk.Row) (types.BinaryJSON, bool, error) {
+	obj, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return obj, isNull, err
+	}
+	pathExprs, isNull, err := evalJSONPathExprs(b.ctx, b.args[1:], row)
+	if isNull || err != nil {
+		return obj, isNull, err
+	}
+	ret, found := obj.Extract(pathExprs)
+	return ret, !found, nil
+}
+
+type jsonUnquoteFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonUnquoteFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	bf.tp.Flen = mysql.MaxFieldVarCharLength
+	sig := &builtinJSONUnquoteSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonUnquoteSig)
+	return sig, nil
+}
+
+type builtinJSONUnquoteSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONUnquoteSig) Clone() builtinFunc {
+	newSig := &builtinJSONUnquoteSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// unquoteJSONText removes the double quotes of a JSON string text and
+// unescapes it, any other text is returned as it is.
+func unquoteJSONText(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s, nil
+	}
+	str, err := types.UnquoteJSONString(s[1 : len(s)-1])
+	if err != nil {
+		return "", types.ErrInvalidJSONText.GenWithStackByArgs(err)
+	}
+	return str, nil
+}
+
+// evalString evals a builtinJSONUnquoteSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-unquote
+func (b *builtinJSONUnquoteSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	str, err = unquoteJSONText(str)
+	if err != nil {
+		return "", true, err
+	}
+	return str, false, nil
+}
+
+type jsonArrayFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonArrayFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETJson)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	for i := range args {
+		DisableParseJSONFlag4Expr(args[i])
+	}
+	sig := &builtinJSONArraySig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonArraySig)
+	return sig, nil
+}
+
+type builtinJSONArraySig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONArraySig) Clone() builtinFunc {
+	newSig := &builtinJSONArraySig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONArraySig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-creation-functions.html#function_json-array
+func (b *builtinJSONArraySig) evalJSON(row chunk.Row) (types.BinaryJSON, bool, error) {
+	jsons := make([]interface{}, 0, len(b.args))
+	for _, arg := range b.args {
+		j, err := evalJSONOrNull(b.ctx, arg, row)
+		if err != nil {
+			return j, true, err
+		}
+		jsons = append(jsons, j)
+	}
+	return types.CreateBinaryJSON(jsons), false, nil
+}
+
+type jsonObjectFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonObjectFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args)&1 != 0 {
+		return nil, ErrIncorrectParameterCount.GenWithStackByArgs(c.funcName)
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for i := 0; i < len(args)-1; i += 2 {
+		argTps = append(argTps, types.ETString, types.ETJson)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	for i := 1; i < len(args); i += 2 {
+		DisableParseJSONFlag4Expr(args[i])
+	}
+	sig := &builtinJSONObjectSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonObjectSig)
+	return sig, nil
+}
+
+type builtinJSONObjectSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONObjectSig) Clone() builtinFunc {
+	newSig := &builtinJSONObjectSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONObjectSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-creation-functions.html#function_json-object
+func (b *builtinJSONObjectSig) evalJSON(row chunk.Row) (types.BinaryJSON, bool, error) {
+	jsons := make(map[string]interface{}, len(b.args)>>1)
+	for i := 0; i < len(b.args); i += 2 {
+		key, isNull, err := b.args[i].EvalString(b.ctx, row)
+		if err != nil {
+			return types.BinaryJSON{}, true, err
+		}
+		if isNull {
+			return types.BinaryJSON{}, true, errJSONDocumentNULLKey
+		}
+		value, err := evalJSONOrNull(b.ctx, b.args[i+1], row)
+		if err != nil {
+			return value, true, err
+		}
+		jsons[key] = value
+	}
+	return types.CreateBinaryJSON(jsons), false, nil
+}
+
+// getJSONModifyFunction builds the function for JSON_SET, JSON_INSERT and JSON_REPLACE,
+// whose arguments are a JSON document followed by (path, value) pairs.
+func getJSONModifyFunction(ctx sessionctx.Context, c *baseFunctionClass, args []Expression) (baseBuiltinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return baseBuiltinFunc{}, err
+	}
+	if len(args)&1 != 1 {
+		return baseBuiltinFunc{}, ErrIncorrectParameterCount.GenWithStackByArgs(c.funcName)
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETJson)
+	for i := 1; i < len(args)-1; i += 2 {
+		argTps = append(argTps, types.ETString, types.ETJson)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	for i := 2; i < len(args); i += 2 {
+		DisableParseJSONFlag4Expr(args[i])
+	}
+	return bf, nil
+}
+
+// evalJSONModify evaluates the arguments of a JSON modify function and applies the modification.
+func evalJSONModify(b *baseBuiltinFunc, row chunk.Row, mt types.JSONModifyType) (types.BinaryJSON, bool, error) {
+	obj, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return obj, isNull, err
+	}
+	pathExprs := make([]types.JSONPathExpression, 0, (len(b.args)-1)/2)
+	values := make([]types.BinaryJSON, 0, (len(b.args)-1)/2)
+	for i := 1; i < len(b.args); i += 2 {
+		exprs, isNull, err := evalJSONPathExprs(b.ctx, b.args[i:i+1], row)
+		if isNull || err != nil {
+			return obj, isNull, err
+		}
+		value, err := evalJSONOrNull(b.ctx, b.args[i+1], row)
+		if err != nil {
+			return obj, true, err
+		}
+		pathExprs = append(pathExprs, exprs[0])
+		values = append(values, value)
+	}
+	ret, err := obj.Modify(pathExprs, values, mt)
+	if err != nil {
+		return obj, true, err
+	}
+	return ret, false, nil
+}
+
+type jsonSetFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonSetFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	bf, err := getJSONModifyFunction(ctx, &c.baseFunctionClass, args)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinJSONSetSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonSetSig)
+	return sig, nil
+}
+
+type builtinJSONSetSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONSetSig) Clone() builtinFunc {
+	newSig := &builtinJSONSetSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONSetSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-set
+func (b *builtinJSONSetSig) evalJSON(row chunk.Row) (types.BinaryJSON, bool, error) {
+	return evalJSONModify(&b.baseBuiltinFunc, row, types.JSONModifySet)
+}
+
+type jsonInsertFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonInsertFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	bf, err := getJSONModifyFunction(ctx, &c.baseFunctionClass, args)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinJSONInsertSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonInsertSig)
+	return sig, nil
+}
+
+type builtinJSONInsertSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONInsertSig) Clone() builtinFunc {
+	newSig := &builtinJSONInsertSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONInsertSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-insert
+func (b *builtinJSONInsertSig) evalJSON(row chunk.Row) (types.BinaryJSON, bool, error) {
+	return evalJSONModify(&b.baseBuiltinFunc, row, types.JSONModifyInsert)
+}
+
+type jsonReplaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonReplaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	bf, err := getJSONModifyFunction(ctx, &c.baseFunctionClass, args)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinJSONReplaceSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonReplaceSig)
+	return sig, nil
+}
+
+type builtinJSONReplaceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONReplaceSig) Clone() builtinFunc {
+	newSig := &builtinJSONReplaceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONReplaceSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-replace
+func (b *builtinJSONReplaceSig) evalJSON(row chunk.Row) (types.BinaryJSON, bool, error) {
+	return evalJSONModify(&b.baseBuiltinFunc, row, types.JSONModifyReplace)
+}
+
+type jsonRemoveFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonRemoveFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETJson)
+	for range args[1:] {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONRemoveSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonRemoveSig)
+	return sig, nil
+}
+
+type builtinJSONRemoveSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONRemoveSig) Clone() builtinFunc {
+	newSig := &builtinJSONRemoveSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONRemoveSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-remove
+func (b *builtinJSONRemoveSig) evalJSON(row chunk.Row) (types.BinaryJSON, bool, error) {
+	obj, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return obj, isNull, err
+	}
+	pathExprs, isNull, err := evalJSONPathExprs(b.ctx, b.args[1:], row)
+	if isNull || err != nil {
+		return obj, isNull, err
+	}
+	ret, err := obj.Remove(pathExprs)
+	if err != nil {
+		return obj, true, err
+	}
+	return ret, false, nil
+}
+
+type jsonContainsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonContainsFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETJson, types.ETJson}
+	if len(args) == 3 {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 1
+	sig := &builtinJSONContainsSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonContainsSig)
+	return sig, nil
+}
+
+type builtinJSONContainsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONContainsSig) Clone() builtinFunc {
+	newSig := &builtinJSONContainsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinJSONContainsSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-contains
+func (b *builtinJSONContainsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	obj, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	target, isNull, err := b.args[1].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if len(b.args) == 3 {
+		obj, isNull, err = extractJSONByPathArg(b.ctx, obj, b.args, 2, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+	}
+	if types.ContainsBinaryJSON(obj, target) {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+type jsonKeysFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonKeysFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETJson}
+	if len(args) == 2 {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONKeysSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonKeysSig)
+	return sig, nil
+}
+
+type builtinJSONKeysSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONKeysSig) Clone() builtinFunc {
+	newSig := &builtinJSONKeysSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONKeysSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-keys
+func (b *builtinJSONKeysSig) evalJSON(row chunk.Row) (types.BinaryJSON, bool, error) {
+	obj, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return obj, isNull, err
+	}
+	if len(b.args) == 2 {
+		obj, isNull, err = extractJSONByPathArg(b.ctx, obj, b.args, 1, row)
+		if isNull || err != nil {
+			return obj, isNull, err
+		}
+	}
+	if obj.TypeCode != types.JSONTypeCodeObject {
+		return obj, true, nil
+	}
+	return obj.GetKeys(), false, nil
+}
+
+type jsonLengthFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonLengthFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETJson}
+	if len(args) == 2 {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	sig := &builtinJSONLengthSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonLengthSig)
+	return sig, nil
+}
+
+type builtinJSONLengthSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONLengthSig) Clone() builtinFunc {
+	newSig := &builtinJSONLengthSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinJSONLengthSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-attribute-functions.html#function_json-length
+func (b *builtinJSONLengthSig) evalInt(row chunk.Row) (int64, bool, error) {
+	obj, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if len(b.args) == 2 {
+		obj, isNull, err = extractJSONByPathArg(b.ctx, obj, b.args, 1, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+	}
+	return jsonLength(obj), false, nil
+}
+
+// jsonLength returns the number of elements of an array or an object, a scalar has length 1.
+func jsonLength(obj types.BinaryJSON) int64 {
+	if obj.TypeCode != types.JSONTypeCodeObject && obj.TypeCode != types.JSONTypeCodeArray {
+		return 1
+	}
+	return int64(obj.GetElemCount())
+}
@@ -0,0 +1,207 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+type jsonFuncTestCase struct {
+	args     []interface{}
+	expected interface{}
+	err      error
+}
+
+func (s *testEvaluatorSuite) runJSONFuncTests(c *C, funcName string, tests []jsonFuncTestCase) {
+	for _, t := range tests {
+		f, err := newFunctionForTest(s.ctx, funcName, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if t.err != nil {
+			c.Assert(terror.ErrorEqual(err, t.err), IsTrue, Commentf("%v: %v", funcName, t.args))
+			continue
+		}
+		c.Assert(err, IsNil, Commentf("%v: %v", funcName, t.args))
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue, Commentf("%v: %v", funcName, t.args))
+			continue
+		}
+		str, err := d.ToString()
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, t.expected, Commentf("%v: %v", funcName, t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestJSONTypeAndUnquote(c *C) {
+	s.runJSONFuncTests(c, ast.JSONType, []jsonFuncTestCase{
+		{[]interface{}{nil}, nil, nil},
+		{[]interface{}{`3`}, "INTEGER", nil},
+		{[]interface{}{`18446744073709551615`}, "UNSIGNED INTEGER", nil},
+		{[]interface{}{`3.0`}, "DOUBLE", nil},
+		{[]interface{}{`null`}, "NULL", nil},
+		{[]interface{}{`true`}, "BOOLEAN", nil},
+		{[]interface{}{`"a"`}, "STRING", nil},
+		{[]interface{}{`[]`}, "ARRAY", nil},
+		{[]interface{}{`{}`}, "OBJECT", nil},
+		{[]interface{}{`{"a":`}, nil, types.ErrInvalidJSONText},
+	})
+	s.runJSONFuncTests(c, ast.JSONUnquote, []jsonFuncTestCase{
+		{[]interface{}{nil}, nil, nil},
+		{[]interface{}{``}, "", nil},
+		{[]interface{}{`"a"`}, "a", nil},
+		{[]interface{}{`"a\"b"`}, `a"b`, nil},
+		{[]interface{}{`"é\n"`}, "é\n", nil},
+		{[]interface{}{`abc`}, "abc", nil},
+		{[]interface{}{`[1, "a"]`}, `[1, "a"]`, nil},
+	})
+}
+
+func (s *testEvaluatorSuite) TestJSONExtract(c *C) {
+	doc := `{"a": [1, "2", {"aa": "bb"}, 4.0, {"aa": "cc"}], "b": true, "c": ["d"], "\"hello\"": "world"}`
+	s.runJSONFuncTests(c, ast.JSONExtract, []jsonFuncTestCase{
+		{[]interface{}{nil, `$`}, nil, nil},
+		{[]interface{}{doc, nil}, nil, nil},
+		{[]interface{}{doc, `$.a[2].aa`}, `"bb"`, nil},
+		{[]interface{}{doc, `$.a[*].aa`}, `["bb", "cc"]`, nil},
+		{[]interface{}{doc, `$.*[0]`}, `[1, true, "d", "world"]`, nil},
+		{[]interface{}{doc, `$**.aa`}, `["bb", "cc"]`, nil},
+		{[]interface{}{doc, `$."\"hello\""`}, `"world"`, nil},
+		{[]interface{}{doc, `$.a[5]`}, nil, nil},
+		{[]interface{}{doc, `$.b`, `$.c`}, `[true, ["d"]]`, nil},
+		{[]interface{}{doc, `$.b`, `$.x`}, `[true]`, nil},
+		{[]interface{}{`"s"`, `$[0]`}, `"s"`, nil},
+		{[]interface{}{doc, `$.a[`}, nil, types.ErrInvalidJSONPath},
+		{[]interface{}{doc, `a`}, nil, types.ErrInvalidJSONPath},
+		{[]interface{}{doc, `$**`}, nil, types.ErrInvalidJSONPath},
+	})
+}
+
+func (s *testEvaluatorSuite) TestJSONArrayAndObject(c *C) {
+	s.runJSONFuncTests(c, ast.JSONArray, []jsonFuncTestCase{
+		{[]interface{}{}, `[]`, nil},
+		{[]interface{}{1, nil, "a", 1.5}, `[1, null, "a", 1.5]`, nil},
+		{[]interface{}{`[1]`, `{"a": 1}`}, `["[1]", "{\"a\": 1}"]`, nil},
+	})
+	s.runJSONFuncTests(c, ast.JSONObject, []jsonFuncTestCase{
+		{[]interface{}{}, `{}`, nil},
+		{[]interface{}{"b", 1, "a", nil, "b", "x"}, `{"a": null, "b": "x"}`, nil},
+		{[]interface{}{1, `[1]`}, `{"1": "[1]"}`, nil},
+		{[]interface{}{nil, 1}, nil, errJSONDocumentNULLKey},
+	})
+	_, err := newFunctionForTest(s.ctx, ast.JSONObject, s.primitiveValsToConstants([]interface{}{"a"})...)
+	c.Assert(ErrIncorrectParameterCount.Equal(err), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestJSONModify(c *C) {
+	doc := `{"a": 1, "b": [1, 2]}`
+	s.runJSONFuncTests(c, ast.JSONSet, []jsonFuncTestCase{
+		{[]interface{}{nil, `$.a`, 1}, nil, nil},
+		{[]interface{}{doc, nil, 1}, nil, nil},
+		{[]interface{}{doc, `$.a`, nil}, `{"a": null, "b": [1, 2]}`, nil},
+		{[]interface{}{doc, `$.a`, 2, `$.c`, "x"}, `{"a": 2, "b": [1, 2], "c": "x"}`, nil},
+		{[]interface{}{doc, `$.b[5]`, 3}, `{"a": 1, "b": [1, 2, 3]}`, nil},
+		{[]interface{}{doc, `$.a[0]`, 3}, `{"a": 3, "b": [1, 2]}`, nil},
+		{[]interface{}{doc, `$.a[1]`, 3}, `{"a": [1, 3], "b": [1, 2]}`, nil},
+		{[]interface{}{doc, `$`, `[1]`}, `"[1]"`, nil},
+		{[]interface{}{doc, `$.*`, 3}, nil, types.ErrInvalidJSONPathWildcard},
+	})
+	s.runJSONFuncTests(c, ast.JSONInsert, []jsonFuncTestCase{
+		{[]interface{}{doc, `$.a`, 2, `$.c`, "x"}, `{"a": 1, "b": [1, 2], "c": "x"}`, nil},
+		{[]interface{}{doc, `$.b[0]`, 3, `$.b[2]`, 3}, `{"a": 1, "b": [1, 2, 3]}`, nil},
+	})
+	s.runJSONFuncTests(c, ast.JSONReplace, []jsonFuncTestCase{
+		{[]interface{}{doc, `$.a`, 2, `$.c`, "x"}, `{"a": 2, "b": [1, 2]}`, nil},
+		{[]interface{}{doc, `$.b[0]`, 3, `$.b[2]`, 3}, `{"a": 1, "b": [3, 2]}`, nil},
+	})
+	s.runJSONFuncTests(c, ast.JSONRemove, []jsonFuncTestCase{
+		{[]interface{}{nil, `$.a`}, nil, nil},
+		{[]interface{}{doc, nil}, nil, nil},
+		{[]interface{}{doc, `$.a`}, `{"b": [1, 2]}`, nil},
+		{[]interface{}{doc, `$.b[0]`, `$.c`}, `{"a": 1, "b": [2]}`, nil},
+		{[]interface{}{doc, `$`}, nil, types.ErrInvalidJSONPath},
+		{[]interface{}{doc, `$.b[*]`}, nil, types.ErrInvalidJSONPathWildcard},
+	})
+	_, err := newFunctionForTest(s.ctx, ast.JSONSet, s.primitiveValsToConstants([]interface{}{doc, `$.a`, 1, `$.b`})...)
+	c.Assert(ErrIncorrectParameterCount.Equal(err), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestJSONContainsKeysLength(c *C) {
+	doc := `{"a": {"b": [1, 2, {"c": 3}]}, "d": "e"}`
+	s.runJSONFuncTests(c, ast.JSONContains, []jsonFuncTestCase{
+		{[]interface{}{nil, `1`}, nil, nil},
+		{[]interface{}{doc, nil}, nil, nil},
+		{[]interface{}{doc, `{"d": "e"}`}, "1", nil},
+		{[]interface{}{doc, `{"a": {"b": [2]}}`}, "1", nil},
+		{[]interface{}{doc, `{"a": {"b": 4}}`}, "0", nil},
+		{[]interface{}{doc, `[1, {"c": 3}]`, `$.a.b`}, "1", nil},
+		{[]interface{}{doc, `1`, `$.a.b`}, "1", nil},
+		{[]interface{}{doc, `"e"`, `$.d`}, "1", nil},
+		{[]interface{}{doc, `1`, `$.x`}, nil, nil},
+		{[]interface{}{`[1, [2, 3]]`, `[[3]]`}, "1", nil},
+		{[]interface{}{`[1, [2, 3]]`, `[3]`}, "1", nil},
+		{[]interface{}{`[1, [2, 3]]`, `[4]`}, "0", nil},
+		{[]interface{}{doc, `1`, `$.*`}, nil, types.ErrInvalidJSONPathWildcard},
+		{[]interface{}{doc, `{"d":`}, nil, types.ErrInvalidJSONText},
+	})
+	s.runJSONFuncTests(c, ast.JSONKeys, []jsonFuncTestCase{
+		{[]interface{}{nil}, nil, nil},
+		{[]interface{}{doc}, `["a", "d"]`, nil},
+		{[]interface{}{`[1]`}, nil, nil},
+		{[]interface{}{doc, `$.a`}, `["b"]`, nil},
+		{[]interface{}{doc, `$.d`}, nil, nil},
+		{[]interface{}{doc, `$.x`}, nil, nil},
+		{[]interface{}{doc, `$.a.b[2]`}, `["c"]`, nil},
+	})
+	s.runJSONFuncTests(c, ast.JSONLength, []jsonFuncTestCase{
+		{[]interface{}{nil}, nil, nil},
+		{[]interface{}{doc}, "2", nil},
+		{[]interface{}{`1`}, "1", nil},
+		{[]interface{}{`[]`}, "0", nil},
+		{[]interface{}{doc, `$.a.b`}, "3", nil},
+		{[]interface{}{doc, `$.d`}, "1", nil},
+		{[]interface{}{doc, `$.x`}, nil, nil},
+		{[]interface{}{doc, `$.a.*`}, nil, types.ErrInvalidJSONPathWildcard},
+	})
+}
+
+func (s *testEvaluatorSuite) TestJSONCompare(c *C) {
+	tests := []struct {
+		funcName string
+		left     string
+		right    interface{}
+		expected int64
+	}{
+		{ast.EQ, `1`, 1, 1},
+		{ast.EQ, `1.0`, 1, 1},
+		{ast.EQ, `"a"`, "a", 1},
+		{ast.EQ, `[1, 2]`, `[1, 2]`, 0},
+		{ast.LT, `null`, 1, 1},
+		{ast.LT, `1`, "a", 1},
+		{ast.GT, `true`, 1, 1},
+		{ast.GT, `[1, 2]`, `"a"`, 1},
+		{ast.NE, `"a"`, "b", 1},
+	}
+	for _, t := range tests {
+		left := WrapWithCastAsJSON(s.ctx, s.primitiveValsToConstants([]interface{}{t.left})[0])
+		f, err := newFunctionForTest(s.ctx, t.funcName, left, s.primitiveValsToConstants([]interface{}{t.right})[0])
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetInt64(), Equals, t.expected, Commentf("%v", t))
+	}
+}
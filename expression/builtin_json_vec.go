@@ -0,0 +1,354 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// vecParseJSONPath parses the path in the i-th row of buf, the path must not contain any wildcard.
+func vecParseJSONPath(buf *chunk.Column, i int) (types.JSONPathExpression, error) {
+	pathExpr, err := types.ParseJSONPathExpr(buf.GetString(i))
+	if err != nil {
+		return pathExpr, err
+	}
+	if pathExpr.ContainsAnyAsterisk() {
+		return pathExpr, types.ErrInvalidJSONPathWildcard
+	}
+	return pathExpr, nil
+}
+
+func (b *builtinJSONTypeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONTypeSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalJSON(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(buf.GetJSON(i).Type())
+	}
+	return nil
+}
+
+func (b *builtinJSONExtractSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONExtractSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	jsonBuf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(jsonBuf)
+	if err := b.args[0].VecEvalJSON(b.ctx, input, jsonBuf); err != nil {
+		return err
+	}
+	pathBufs := make([]*chunk.Column, 0, len(b.args)-1)
+	for _, arg := range b.args[1:] {
+		pathBuf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(pathBuf)
+		if err := arg.VecEvalString(b.ctx, input, pathBuf); err != nil {
+			return err
+		}
+		pathBufs = append(pathBufs, pathBuf)
+	}
+
+	result.ReserveJSON(n)
+	pathExprs := make([]types.JSONPathExpression, 0, len(pathBufs))
+	for i := 0; i < n; i++ {
+		if jsonBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		pathExprs = pathExprs[:0]
+		hasNullPath := false
+		for _, pathBuf := range pathBufs {
+			if pathBuf.IsNull(i) {
+				hasNullPath = true
+				break
+			}
+			pathExpr, err := types.ParseJSONPathExpr(pathBuf.GetString(i))
+			if err != nil {
+				return err
+			}
+			pathExprs = append(pathExprs, pathExpr)
+		}
+		if hasNullPath {
+			result.AppendNull()
+			continue
+		}
+		if ret, found := jsonBuf.GetJSON(i).Extract(pathExprs); found {
+			result.AppendJSON(ret)
+		} else {
+			result.AppendNull()
+		}
+	}
+	return nil
+}
+
+func (b *builtinJSONUnquoteSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONUnquoteSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str, err := unquoteJSONText(buf.GetString(i))
+		if err != nil {
+			return err
+		}
+		result.AppendString(str)
+	}
+	return nil
+}
+
+func (b *builtinJSONArraySig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONArraySig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, 0, len(b.args))
+	for _, arg := range b.args {
+		buf, err := b.bufAllocator.get(types.ETJson, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := arg.VecEvalJSON(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs = append(bufs, buf)
+	}
+
+	result.ReserveJSON(n)
+	jsonNull := types.CreateBinaryJSON(nil)
+	for i := 0; i < n; i++ {
+		jsons := make([]interface{}, 0, len(bufs))
+		for _, buf := range bufs {
+			if buf.IsNull(i) {
+				jsons = append(jsons, jsonNull)
+			} else {
+				jsons = append(jsons, buf.GetJSON(i))
+			}
+		}
+		result.AppendJSON(types.CreateBinaryJSON(jsons))
+	}
+	return nil
+}
+
+func (b *builtinJSONKeysSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONKeysSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	jsonBuf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(jsonBuf)
+	if err := b.args[0].VecEvalJSON(b.ctx, input, jsonBuf); err != nil {
+		return err
+	}
+	var pathBuf *chunk.Column
+	if len(b.args) == 2 {
+		if pathBuf, err = b.bufAllocator.get(types.ETString, n); err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(pathBuf)
+		if err := b.args[1].VecEvalString(b.ctx, input, pathBuf); err != nil {
+			return err
+		}
+	}
+
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		if jsonBuf.IsNull(i) || (pathBuf != nil && pathBuf.IsNull(i)) {
+			result.AppendNull()
+			continue
+		}
+		obj := jsonBuf.GetJSON(i)
+		if pathBuf != nil {
+			pathExpr, err := vecParseJSONPath(pathBuf, i)
+			if err != nil {
+				return err
+			}
+			var found bool
+			if obj, found = obj.Extract([]types.JSONPathExpression{pathExpr}); !found {
+				result.AppendNull()
+				continue
+			}
+		}
+		if obj.TypeCode != types.JSONTypeCodeObject {
+			result.AppendNull()
+			continue
+		}
+		result.AppendJSON(obj.GetKeys())
+	}
+	return nil
+}
+
+func (b *builtinJSONLengthSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONLengthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	jsonBuf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(jsonBuf)
+	if err := b.args[0].VecEvalJSON(b.ctx, input, jsonBuf); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	if len(b.args) == 2 {
+		pathBuf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(pathBuf)
+		if err := b.args[1].VecEvalString(b.ctx, input, pathBuf); err != nil {
+			return err
+		}
+		result.MergeNulls(jsonBuf, pathBuf)
+		i64s := result.Int64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			pathExpr, err := vecParseJSONPath(pathBuf, i)
+			if err != nil {
+				return err
+			}
+			obj, found := jsonBuf.GetJSON(i).Extract([]types.JSONPathExpression{pathExpr})
+			if !found {
+				result.SetNull(i, true)
+				continue
+			}
+			i64s[i] = jsonLength(obj)
+		}
+		return nil
+	}
+
+	result.MergeNulls(jsonBuf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = jsonLength(jsonBuf.GetJSON(i))
+	}
+	return nil
+}
+
+func (b *builtinJSONContainsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONContainsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	objBuf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(objBuf)
+	if err := b.args[0].VecEvalJSON(b.ctx, input, objBuf); err != nil {
+		return err
+	}
+	targetBuf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(targetBuf)
+	if err := b.args[1].VecEvalJSON(b.ctx, input, targetBuf); err != nil {
+		return err
+	}
+	var pathBuf *chunk.Column
+	if len(b.args) == 3 {
+		if pathBuf, err = b.bufAllocator.get(types.ETString, n); err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(pathBuf)
+		if err := b.args[2].VecEvalString(b.ctx, input, pathBuf); err != nil {
+			return err
+		}
+	}
+
+	result.ResizeInt64(n, false)
+	if pathBuf != nil {
+		result.MergeNulls(objBuf, targetBuf, pathBuf)
+	} else {
+		result.MergeNulls(objBuf, targetBuf)
+	}
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		obj := objBuf.GetJSON(i)
+		if pathBuf != nil {
+			pathExpr, err := vecParseJSONPath(pathBuf, i)
+			if err != nil {
+				return err
+			}
+			var found bool
+			if obj, found = obj.Extract([]types.JSONPathExpression{pathExpr}); !found {
+				result.SetNull(i, true)
+				continue
+			}
+		}
+		if types.ContainsBinaryJSON(obj, targetBuf.GetJSON(i)) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinJSONCases = map[string][]vecExprBenchCase{
+	ast.JSONType: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETJson}},
+	},
+	ast.JSONExtract: {
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson, types.ETString},
+			geners: []dataGenerator{nil, &selectStringGener{candidates: []string{"$", "$.a", "$.c[1]", "$[0]", "$[*]", "$.*", "$**.a"}}},
+		},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson, types.ETString, types.ETString},
+			geners: []dataGenerator{nil,
+				&selectStringGener{candidates: []string{"$.a", "$[1]"}},
+				&selectStringGener{candidates: []string{"$.b", "$[0]"}},
+			},
+		},
+	},
+	ast.JSONUnquote: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{`"abc"`, `"a\tb"`, `"你"`, `abc`, `[1, 2]`, `"`, ""}}},
+		},
+	},
+	ast.JSONArray: {
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson, types.ETJson, types.ETJson}},
+	},
+	ast.JSONKeys: {
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson, types.ETString},
+			geners: []dataGenerator{nil, &selectStringGener{candidates: []string{"$", "$.a", "$.c"}}},
+		},
+	},
+	ast.JSONLength: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETJson}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETJson, types.ETString},
+			geners: []dataGenerator{nil, &selectStringGener{candidates: []string{"$", "$.c", "$[1]", "$.d"}}},
+		},
+	},
+	ast.JSONContains: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETJson, types.ETJson}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETJson, types.ETJson, types.ETString},
+			geners: []dataGenerator{nil, nil, &selectStringGener{candidates: []string{"$", "$.a", "$.c", "$[0]"}}},
+		},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinJSONEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinJSONCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinJSONFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinJSONCases)
+}
+
+func BenchmarkVectorizedBuiltinJSONEvalOneVec(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltinJSONCases)
+}
+
+func BenchmarkVectorizedBuiltinJSONFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinJSONCases)
+}
@@ -0,0 +1,530 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/stringutil"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &likeFunctionClass{}
+	_ functionClass = &regexpFunctionClass{}
+	_ functionClass = &regexpLikeFunctionClass{}
+	_ functionClass = &regexpSubstrFunctionClass{}
+	_ functionClass = &regexpReplaceFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinLikeSig{}
+	_ builtinFunc = &builtinRegexpSig{}
+	_ builtinFunc = &builtinRegexpLikeSig{}
+	_ builtinFunc = &builtinRegexpSubstrSig{}
+	_ builtinFunc = &builtinRegexpReplaceSig{}
+)
+
+type likeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *likeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := []types.EvalType{types.ETString, types.ETString, types.ETInt}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTp...)
+	bf.tp.Flen = 1
+	sig := &builtinLikeSig{baseBuiltinFunc: bf}
+	sig.setPbCode(tipb.ScalarFuncSig_LikeSig)
+	return sig, nil
+}
+
+type builtinLikeSig struct {
+	baseBuiltinFunc
+
+	// patChars and patTypes are the compiled pattern. They are memorized when the
+	// pattern and the escape character are constants, so the pattern is compiled once.
+	patChars           []byte
+	patTypes           []byte
+	isMemorizedPattern bool
+	once               sync.Once
+}
+
+func (b *builtinLikeSig) Clone() builtinFunc {
+	newSig := &builtinLikeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// compilePattern compiles the LIKE pattern, the result is memorized if the pattern is a constant.
+func (b *builtinLikeSig) compilePattern(pattern string, escape int64) (patChars, patTypes []byte) {
+	b.once.Do(func() {
+		if b.args[1].ConstItem() && b.args[2].ConstItem() {
+			b.patChars, b.patTypes = stringutil.CompilePattern(pattern, byte(escape))
+			b.isMemorizedPattern = true
+		}
+	})
+	if b.isMemorizedPattern {
+		return b.patChars, b.patTypes
+	}
+	return stringutil.CompilePattern(pattern, byte(escape))
+}
+
+// evalInt evals a builtinLikeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-comparison-functions.html#operator_like
+func (b *builtinLikeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	valStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	patternStr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	escape, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	patChars, patTypes := b.compilePattern(patternStr, escape)
+	if stringutil.DoMatch(valStr, patChars, patTypes) {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+// regexpMemorizedSig compiles the regular expressions for the regexp functions. The compiled
+// regular expression is memorized when the pattern and the match type are constants.
+type regexpMemorizedSig struct {
+	memorizedRegexp *regexp.Regexp
+	memorizedErr    error
+	once            sync.Once
+}
+
+func (m *regexpMemorizedSig) compile(isConstPattern bool, pattern string) (*regexp.Regexp, error) {
+	if !isConstPattern {
+		return compileRegexp(pattern)
+	}
+	m.once.Do(func() {
+		m.memorizedRegexp, m.memorizedErr = compileRegexp(pattern)
+	})
+	return m.memorizedRegexp, m.memorizedErr
+}
+
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, ErrRegexp.GenWithStackByArgs(err.Error())
+	}
+	return re, nil
+}
+
+// getRegexpFlags converts the match_type argument of the regexp functions to the flags of
+// the Go regular expression. The strings are compared case-sensitively by default, because
+// only the binary collations are supported.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func getRegexpFlags(funcName string, matchType string) (string, error) {
+	var caseInsensitive, multiLine, dotAll bool
+	for _, c := range matchType {
+		switch c {
+		case 'c':
+			caseInsensitive = false
+		case 'i':
+			caseInsensitive = true
+		case 'm':
+			multiLine = true
+		case 'n':
+			dotAll = true
+		case 'u':
+			// Unix-only line endings, which is the only mode of the Go regular expression.
+		default:
+			return "", errIncorrectArgs.GenWithStackByArgs(funcName)
+		}
+	}
+	flags := ""
+	if caseInsensitive {
+		flags += "i"
+	}
+	if multiLine {
+		flags += "m"
+	}
+	if dotAll {
+		flags += "s"
+	}
+	if flags == "" {
+		return "", nil
+	}
+	return "(?" + flags + ")", nil
+}
+
+// regexpPosToOffset converts the 1-based character position of the regexp functions to the
+// byte offset in str. The position of a binary string is counted in bytes.
+func regexpPosToOffset(funcName string, str string, pos int64, isBinary bool) (int, error) {
+	if pos < 1 {
+		return 0, errIncorrectArgs.GenWithStackByArgs(funcName)
+	}
+	if isBinary {
+		if pos > int64(len(str))+1 {
+			return 0, errIncorrectArgs.GenWithStackByArgs(funcName)
+		}
+		return int(pos - 1), nil
+	}
+	offset := 0
+	for i := int64(1); i < pos; i++ {
+		if offset >= len(str) {
+			return 0, errIncorrectArgs.GenWithStackByArgs(funcName)
+		}
+		_, size := utf8.DecodeRuneInString(str[offset:])
+		offset += size
+	}
+	return offset, nil
+}
+
+type regexpFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETString)
+	bf.tp.Flen = 1
+	sig := &builtinRegexpSig{baseBuiltinFunc: bf}
+	if types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[1].GetType()) {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpUTF8Sig)
+	}
+	return sig, nil
+}
+
+// builtinRegexpSig is the REGEXP and RLIKE operator.
+type builtinRegexpSig struct {
+	baseBuiltinFunc
+	regexpMemorizedSig
+}
+
+func (b *builtinRegexpSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals `expr REGEXP pat`, or `expr RLIKE pat`.
+// See https://dev.mysql.com/doc/refman/5.7/en/regexp.html#operator_regexp
+func (b *builtinRegexpSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	re, err := b.compile(b.args[1].ConstItem(), pat)
+	if err != nil {
+		return 0, true, err
+	}
+	if re.MatchString(expr) {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+type regexpLikeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpLikeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 1
+	sig := &builtinRegexpLikeSig{baseBuiltinFunc: bf}
+	return sig, nil
+}
+
+// builtinRegexpLikeSig is the REGEXP_LIKE(expr, pat[, match_type]) function.
+type builtinRegexpLikeSig struct {
+	baseBuiltinFunc
+	regexpMemorizedSig
+}
+
+func (b *builtinRegexpLikeSig) Clone() builtinFunc {
+	newSig := &builtinRegexpLikeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinRegexpLikeSig) isConstPattern() bool {
+	return b.args[1].ConstItem() && (len(b.args) < 3 || b.args[2].ConstItem())
+}
+
+// evalInt evals a builtinRegexpLikeSig.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *builtinRegexpLikeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	matchType := ""
+	if len(b.args) == 3 {
+		matchType, isNull, err = b.args[2].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+	}
+	matched, err := b.match(expr, pat, matchType)
+	if err != nil {
+		return 0, true, err
+	}
+	if matched {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+func (b *builtinRegexpLikeSig) match(expr, pat, matchType string) (bool, error) {
+	flags, err := getRegexpFlags("regexp_like", matchType)
+	if err != nil {
+		return false, err
+	}
+	re, err := b.compile(b.isConstPattern(), flags+pat)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(expr), nil
+}
+
+// regexpSearchArgs are the optional arguments of REGEXP_SUBSTR and REGEXP_REPLACE.
+type regexpSearchArgs struct {
+	pos        int64
+	occurrence int64
+	matchType  string
+}
+
+// evalRegexpSearchArgs evaluates the pos, occurrence and match_type arguments which start
+// from args[first]. isNull is true if any of them is NULL.
+func evalRegexpSearchArgs(ctx sessionctx.Context, args []Expression, first int, row chunk.Row) (searchArgs regexpSearchArgs, isNull bool, err error) {
+	searchArgs = regexpSearchArgs{pos: 1}
+	if len(args) > first {
+		searchArgs.pos, isNull, err = args[first].EvalInt(ctx, row)
+		if isNull || err != nil {
+			return searchArgs, true, err
+		}
+	}
+	if len(args) > first+1 {
+		searchArgs.occurrence, isNull, err = args[first+1].EvalInt(ctx, row)
+		if isNull || err != nil {
+			return searchArgs, true, err
+		}
+	}
+	if len(args) > first+2 {
+		searchArgs.matchType, isNull, err = args[first+2].EvalString(ctx, row)
+		if isNull || err != nil {
+			return searchArgs, true, err
+		}
+	}
+	return searchArgs, false, nil
+}
+
+// getRegexpSearchArgTps returns the types of the arguments of REGEXP_SUBSTR and REGEXP_REPLACE,
+// the first nStr arguments are strings and they are followed by pos, occurrence and match_type.
+func getRegexpSearchArgTps(nArgs int, nStr int) []types.EvalType {
+	argTps := make([]types.EvalType, 0, nArgs)
+	for i := 0; i < nArgs; i++ {
+		if i < nStr || i == nStr+2 {
+			argTps = append(argTps, types.ETString)
+		} else {
+			argTps = append(argTps, types.ETInt)
+		}
+	}
+	return argTps
+}
+
+type regexpSubstrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpSubstrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, getRegexpSearchArgTps(len(args), 2)...)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := &builtinRegexpSubstrSig{baseBuiltinFunc: bf}
+	return sig, nil
+}
+
+// builtinRegexpSubstrSig is the REGEXP_SUBSTR(expr, pat[, pos[, occurrence[, match_type]]]) function.
+type builtinRegexpSubstrSig struct {
+	baseBuiltinFunc
+	regexpMemorizedSig
+}
+
+func (b *builtinRegexpSubstrSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSubstrSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinRegexpSubstrSig) isConstPattern() bool {
+	return b.args[1].ConstItem() && (len(b.args) < 5 || b.args[4].ConstItem())
+}
+
+// evalString evals a builtinRegexpSubstrSig, it returns NULL if there is no match.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-substr
+func (b *builtinRegexpSubstrSig) evalString(row chunk.Row) (string, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	searchArgs, isNull, err := evalRegexpSearchArgs(b.ctx, b.args, 2, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return b.substr(expr, pat, searchArgs)
+}
+
+func (b *builtinRegexpSubstrSig) substr(expr, pat string, searchArgs regexpSearchArgs) (string, bool, error) {
+	offset, err := regexpPosToOffset("regexp_substr", expr, searchArgs.pos, types.IsBinaryStr(b.args[0].GetType()))
+	if err != nil {
+		return "", true, err
+	}
+	flags, err := getRegexpFlags("regexp_substr", searchArgs.matchType)
+	if err != nil {
+		return "", true, err
+	}
+	re, err := b.compile(b.isConstPattern(), flags+pat)
+	if err != nil {
+		return "", true, err
+	}
+	occurrence := searchArgs.occurrence
+	if occurrence < 1 {
+		occurrence = 1
+	}
+	matches := re.FindAllString(expr[offset:], int(occurrence))
+	if int64(len(matches)) < occurrence {
+		return "", true, nil
+	}
+	return matches[occurrence-1], false, nil
+}
+
+type regexpReplaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpReplaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, getRegexpSearchArgTps(len(args), 3)...)
+	bf.tp.Flen = mysql.MaxBlobWidth
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	sig := &builtinRegexpReplaceSig{baseBuiltinFunc: bf}
+	return sig, nil
+}
+
+// builtinRegexpReplaceSig is the REGEXP_REPLACE(expr, pat, repl[, pos[, occurrence[, match_type]]]) function.
+type builtinRegexpReplaceSig struct {
+	baseBuiltinFunc
+	regexpMemorizedSig
+}
+
+func (b *builtinRegexpReplaceSig) Clone() builtinFunc {
+	newSig := &builtinRegexpReplaceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinRegexpReplaceSig) isConstPattern() bool {
+	return b.args[1].ConstItem() && (len(b.args) < 6 || b.args[5].ConstItem())
+}
+
+// evalString evals a builtinRegexpReplaceSig. All the matches are replaced if occurrence is 0,
+// and "$1" in the replacement refers to the text of the first group of the match.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-replace
+func (b *builtinRegexpReplaceSig) evalString(row chunk.Row) (string, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	repl, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	searchArgs, isNull, err := evalRegexpSearchArgs(b.ctx, b.args, 3, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return b.replace(expr, pat, repl, searchArgs)
+}
+
+func (b *builtinRegexpReplaceSig) replace(expr, pat, repl string, searchArgs regexpSearchArgs) (string, bool, error) {
+	offset, err := regexpPosToOffset("regexp_replace", expr, searchArgs.pos, types.IsBinaryStr(b.args[0].GetType()))
+	if err != nil {
+		return "", true, err
+	}
+	flags, err := getRegexpFlags("regexp_replace", searchArgs.matchType)
+	if err != nil {
+		return "", true, err
+	}
+	re, err := b.compile(b.isConstPattern(), flags+pat)
+	if err != nil {
+		return "", true, err
+	}
+	head, tail := expr[:offset], expr[offset:]
+	if searchArgs.occurrence <= 0 {
+		return head + re.ReplaceAllString(tail, repl), false, nil
+	}
+	matches := re.FindAllStringSubmatchIndex(tail, int(searchArgs.occurrence))
+	if int64(len(matches)) < searchArgs.occurrence {
+		return expr, false, nil
+	}
+	match := matches[searchArgs.occurrence-1]
+	var sb strings.Builder
+	sb.WriteString(head)
+	sb.WriteString(tail[:match[0]])
+	sb.Write(re.ExpandString(nil, repl, tail, match))
+	sb.WriteString(tail[match[1]:])
+	return sb.String(), false, nil
+}
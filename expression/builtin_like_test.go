@@ -0,0 +1,194 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestLike(c *C) {
+	cases := []struct {
+		input   string
+		pattern string
+		escape  int
+		match   int64
+	}{
+		{"a", "", '\\', 0},
+		{"a", "a", '\\', 1},
+		{"a", "b", '\\', 0},
+		{"aA", "Aa", '\\', 0},
+		{"abc", "a%", '\\', 1},
+		{"abc", "%c", '\\', 1},
+		{"abc", "a_c", '\\', 1},
+		{"abc", "a_", '\\', 0},
+		{"a%c", "a\\%c", '\\', 1},
+		{"abc", "a\\%c", '\\', 0},
+		{"a%c", "a|%c", '|', 1},
+		{"a_c", "a|_c", '|', 1},
+		{"abc", "a|_c", '|', 0},
+	}
+	for _, tt := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Like, s.primitiveValsToConstants([]interface{}{tt.input, tt.pattern, tt.escape})...)
+		c.Assert(err, IsNil)
+		r, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(r, testutil.DatumEquals, types.NewDatum(tt.match), Commentf("%v", tt))
+	}
+
+	f, err := newFunctionForTest(s.ctx, ast.Like, s.primitiveValsToConstants([]interface{}{nil, "a%", '\\'})...)
+	c.Assert(err, IsNil)
+	r, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(r.IsNull(), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestRegexp(c *C) {
+	tests := []struct {
+		pattern string
+		input   string
+		match   int64
+		err     error
+	}{
+		{"^$", "a", 0, nil},
+		{"a", "a", 1, nil},
+		{"a", "b", 0, nil},
+		{"aA", "aA", 1, nil},
+		{"aA", "aa", 0, nil},
+		{".", "a", 1, nil},
+		{"^.$", "ab", 0, nil},
+		{"..", "b", 0, nil},
+		{".ab", "aab", 1, nil},
+		{"ab.", "abcd", 1, nil},
+		{".*", "abcd", 1, nil},
+		{"(", "", 0, ErrRegexp},
+		{"(*", "", 0, ErrRegexp},
+		{"[a", "", 0, ErrRegexp},
+		{"\\", "", 0, ErrRegexp},
+	}
+	for _, tt := range tests {
+		f, err := newFunctionForTest(s.ctx, ast.Regexp, s.primitiveValsToConstants([]interface{}{tt.input, tt.pattern})...)
+		c.Assert(err, IsNil)
+		match, err := f.Eval(chunk.Row{})
+		if tt.err == nil {
+			c.Assert(err, IsNil)
+			c.Assert(match, testutil.DatumEquals, types.NewDatum(tt.match), Commentf("%v", tt))
+		} else {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue)
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexpLike(c *C) {
+	tests := []struct {
+		args  []interface{}
+		match interface{}
+		err   error
+	}{
+		{[]interface{}{"abc", "^a"}, int64(1), nil},
+		{[]interface{}{"abc", "^A"}, int64(0), nil},
+		{[]interface{}{"abc", "^A", "i"}, int64(1), nil},
+		{[]interface{}{"abc", "^A", "ic"}, int64(0), nil},
+		{[]interface{}{"a\nb", "^b", ""}, int64(0), nil},
+		{[]interface{}{"a\nb", "^b", "m"}, int64(1), nil},
+		{[]interface{}{"a\nb", "a.b", ""}, int64(0), nil},
+		{[]interface{}{"a\nb", "a.b", "n"}, int64(1), nil},
+		{[]interface{}{"abc", nil}, nil, nil},
+		{[]interface{}{"abc", "a", nil}, nil, nil},
+		{[]interface{}{"abc", "a", "x"}, nil, errIncorrectArgs},
+		{[]interface{}{"abc", "("}, nil, ErrRegexp},
+	}
+	for _, tt := range tests {
+		f, err := newFunctionForTest(s.ctx, ast.RegexpLike, s.primitiveValsToConstants(tt.args)...)
+		c.Assert(err, IsNil)
+		match, err := f.Eval(chunk.Row{})
+		if tt.err == nil {
+			c.Assert(err, IsNil)
+			c.Assert(match, testutil.DatumEquals, types.NewDatum(tt.match), Commentf("%v", tt))
+		} else {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue, Commentf("%v", tt))
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexpSubstr(c *C) {
+	tests := []struct {
+		args []interface{}
+		res  interface{}
+		err  error
+	}{
+		{[]interface{}{"abc def ghi", "[a-z]+"}, "abc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 3}, "ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 2}, "bc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 5, 2}, "ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 4}, nil, nil},
+		{[]interface{}{"abc def ghi", "X", 1, 1}, nil, nil},
+		{[]interface{}{"abc def ghi", "D.F", 1, 1, "i"}, "def", nil},
+		{[]interface{}{"你好世界", "世.", 3}, "世界", nil},
+		{[]interface{}{"你好世界", "世.", 4}, nil, nil},
+		{[]interface{}{nil, "a"}, nil, nil},
+		{[]interface{}{"abc", "a", nil}, nil, nil},
+		{[]interface{}{"abc", "a", 0}, nil, errIncorrectArgs},
+		{[]interface{}{"abc", "a", 5}, nil, errIncorrectArgs},
+		{[]interface{}{"abc", "a", 1, 1, "z"}, nil, errIncorrectArgs},
+	}
+	for _, tt := range tests {
+		f, err := newFunctionForTest(s.ctx, ast.RegexpSubstr, s.primitiveValsToConstants(tt.args)...)
+		c.Assert(err, IsNil)
+		res, err := f.Eval(chunk.Row{})
+		if tt.err == nil {
+			c.Assert(err, IsNil)
+			c.Assert(res, testutil.DatumEquals, types.NewDatum(tt.res), Commentf("%v", tt))
+		} else {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue, Commentf("%v", tt))
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexpReplace(c *C) {
+	tests := []struct {
+		args []interface{}
+		res  interface{}
+		err  error
+	}{
+		{[]interface{}{"a b c", "b", "X"}, "a X c", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X"}, "X X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 0}, "X X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 3}, "abc def X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 5}, "abc X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 4}, "abc def ghi", nil},
+		{[]interface{}{"abc def", "([a-z])([a-z]+)", "$2$1"}, "bca efd", nil},
+		{[]interface{}{"abc DEF", "d", "X", 1, 0, "i"}, "abc XEF", nil},
+		{[]interface{}{"你好世界", ".", "X", 3}, "你好XX", nil},
+		{[]interface{}{nil, "a", "X"}, nil, nil},
+		{[]interface{}{"abc", "a", nil}, nil, nil},
+		{[]interface{}{"abc", "a", "X", 0}, nil, errIncorrectArgs},
+		{[]interface{}{"abc", "(", "X"}, nil, ErrRegexp},
+	}
+	for _, tt := range tests {
+		f, err := newFunctionForTest(s.ctx, ast.RegexpReplace, s.primitiveValsToConstants(tt.args)...)
+		c.Assert(err, IsNil)
+		res, err := f.Eval(chunk.Row{})
+		if tt.err == nil {
+			c.Assert(err, IsNil)
+			c.Assert(res, testutil.DatumEquals, types.NewDatum(tt.res), Commentf("%v", tt))
+		} else {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue, Commentf("%v", tt))
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/stringutil"
+)
+
+func (b *builtinLikeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLikeSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufVal, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufVal)
+	if err = b.args[0].VecEvalString(b.ctx, input, bufVal); err != nil {
+		return err
+	}
+	bufPattern, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufPattern)
+	if err = b.args[1].VecEvalString(b.ctx, input, bufPattern); err != nil {
+		return err
+	}
+	bufEscape, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufEscape)
+	if err = b.args[2].VecEvalInt(b.ctx, input, bufEscape); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufVal, bufPattern, bufEscape)
+	escapes := bufEscape.Int64s()
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		patChars, patTypes := b.compilePattern(bufPattern.GetString(i), escapes[i])
+		if stringutil.DoMatch(bufVal.GetString(i), patChars, patTypes) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+func (b *builtinRegexpSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufExpr, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufExpr)
+	if err = b.args[0].VecEvalString(b.ctx, input, bufExpr); err != nil {
+		return err
+	}
+	bufPat, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufPat)
+	if err = b.args[1].VecEvalString(b.ctx, input, bufPat); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufExpr, bufPat)
+	isConstPattern := b.args[1].ConstItem()
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		re, err := b.compile(isConstPattern, bufPat.GetString(i))
+		if err != nil {
+			return err
+		}
+		if re.MatchString(bufExpr.GetString(i)) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+func (b *builtinRegexpLikeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpLikeSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(&b.baseBuiltinFunc, input, getRegexpSearchArgTps(len(b.args), len(b.args)))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(&b.baseBuiltinFunc, bufs)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufs...)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		matchType := ""
+		if len(bufs) == 3 {
+			matchType = bufs[2].GetString(i)
+		}
+		matched, err := b.match(bufs[0].GetString(i), bufs[1].GetString(i), matchType)
+		if err != nil {
+			return err
+		}
+		if matched {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+// getRegexpSearchArgs gets the pos, occurrence and match_type arguments which start from bufs[first] in row i.
+func getRegexpSearchArgs(bufs []*chunk.Column, first int, i int) regexpSearchArgs {
+	searchArgs := regexpSearchArgs{pos: 1}
+	if len(bufs) > first {
+		searchArgs.pos = bufs[first].GetInt64(i)
+	}
+	if len(bufs) > first+1 {
+		searchArgs.occurrence = bufs[first+1].GetInt64(i)
+	}
+	if len(bufs) > first+2 {
+		searchArgs.matchType = bufs[first+2].GetString(i)
+	}
+	return searchArgs
+}
+
+func (b *builtinRegexpSubstrSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpSubstrSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(&b.baseBuiltinFunc, input, getRegexpSearchArgTps(len(b.args), 2))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(&b.baseBuiltinFunc, bufs)
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if hasNullArg(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull, err := b.substr(bufs[0].GetString(i), bufs[1].GetString(i), getRegexpSearchArgs(bufs, 2, i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinRegexpReplaceSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpReplaceSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(&b.baseBuiltinFunc, input, getRegexpSearchArgTps(len(b.args), 3))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(&b.baseBuiltinFunc, bufs)
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if hasNullArg(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull, err := b.replace(bufs[0].GetString(i), bufs[1].GetString(i), bufs[2].GetString(i), getRegexpSearchArgs(bufs, 3, i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinLikeCases = map[string][]vecExprBenchCase{
+	ast.Like: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners: []dataGenerator{
+				&randLenStrGener{1, 5},
+				&selectStringGener{candidates: []string{"a%", "%b", "_c%", "a\\%", "%", ""}},
+			},
+			constants: []*Constant{nil, nil, {Value: types.NewIntDatum('\\'), RetType: types.NewFieldType(mysql.TypeLonglong)}},
+		},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners: []dataGenerator{&randLenStrGener{1, 5}},
+			constants: []*Constant{nil,
+				{Value: types.NewStringDatum("%a_"), RetType: types.NewFieldType(mysql.TypeString)},
+				{Value: types.NewIntDatum('\\'), RetType: types.NewFieldType(mysql.TypeLonglong)}},
+		},
+	},
+	ast.Regexp: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{
+				&randLenStrGener{1, 10},
+				&selectStringGener{candidates: []string{"^a", "b$", "[0-9]+", "a.c", "", "x|y"}},
+			},
+		},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners:    []dataGenerator{&randLenStrGener{1, 10}},
+			constants: []*Constant{nil, {Value: types.NewStringDatum("[a-c]{2}"), RetType: types.NewFieldType(mysql.TypeString)}},
+		},
+	},
+	ast.RegexpLike: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{
+				&randLenStrGener{1, 10},
+				&selectStringGener{candidates: []string{"^a", "B$", "[0-9]+", "a.c"}},
+				&selectStringGener{candidates: []string{"", "i", "c", "m", "n", "ci"}},
+			},
+		},
+	},
+	ast.RegexpSubstr: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{
+				&randLenStrGener{10, 20},
+				&selectStringGener{candidates: []string{"[a-z]+", "[0-9]", "A.", "x"}},
+				&rangeInt64Gener{1, 10},
+				&rangeInt64Gener{0, 4},
+				&selectStringGener{candidates: []string{"", "i", "c"}},
+			},
+		},
+	},
+	ast.RegexpReplace: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{
+				&randLenStrGener{10, 20},
+				&selectStringGener{candidates: []string{"([a-z])[0-9]", "[0-9]", "A.", "x"}},
+				&selectStringGener{candidates: []string{"-", "<$1>", ""}},
+				&rangeInt64Gener{1, 10},
+				&rangeInt64Gener{0, 4},
+				&selectStringGener{candidates: []string{"", "i", "c"}},
+			},
+		},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinLikeEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinLikeCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinLikeFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinLikeCases)
+}
+
+func BenchmarkVectorizedBuiltinLikeEvalOneVec(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltinLikeCases)
+}
+
+func BenchmarkVectorizedBuiltinLikeFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinLikeCases)
+}
@@ -0,0 +1,305 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestAbs(c *C) {
+	tbl := []struct {
+		Arg interface{}
+		Ret interface{}
+	}{
+		{nil, nil},
+		{int64(1), int64(1)},
+		{uint64(1), uint64(1)},
+		{int64(-1), int64(1)},
+		{float64(3.14), float64(3.14)},
+		{float64(-3.14), float64(3.14)},
+		{types.NewDecFromStringForTest("-3.14"), types.NewDecFromStringForTest("3.14")},
+		{"-1.5", float64(1.5)},
+	}
+
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, ast.Abs, s.primitiveValsToConstants([]interface{}{t.Arg})...)
+		c.Assert(err, IsNil)
+		v, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(v, testutil.DatumEquals, types.NewDatum(t.Ret))
+	}
+
+	f, err := newFunctionForTest(s.ctx, ast.Abs, s.primitiveValsToConstants([]interface{}{int64(math.MinInt64)})...)
+	c.Assert(err, IsNil)
+	_, err = f.Eval(chunk.Row{})
+	c.Assert(terror.ErrorEqual(err, types.ErrOverflow), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestCeilAndFloor(c *C) {
+	type testCase struct {
+		arg   interface{}
+		ceil  interface{}
+		floor interface{}
+	}
+	tbl := []testCase{
+		{nil, nil, nil},
+		{int64(1), int64(1), int64(1)},
+		{float64(1.23), float64(2), float64(1)},
+		{float64(-1.23), float64(-1), float64(-2)},
+		{types.NewDecFromStringForTest("1.23"), int64(2), int64(1)},
+		{types.NewDecFromStringForTest("-1.23"), int64(-1), int64(-2)},
+		{types.NewDecFromStringForTest("-1.00"), int64(-1), int64(-1)},
+		{"1.23", float64(2), float64(1)},
+	}
+	for _, t := range tbl {
+		args := s.primitiveValsToConstants([]interface{}{t.arg})
+		f, err := newFunctionForTest(s.ctx, ast.Ceil, args...)
+		c.Assert(err, IsNil)
+		v, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(v, testutil.DatumEquals, types.NewDatum(t.ceil))
+
+		f, err = newFunctionForTest(s.ctx, ast.Floor, args...)
+		c.Assert(err, IsNil)
+		v, err = f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(v, testutil.DatumEquals, types.NewDatum(t.floor))
+	}
+
+	// A decimal which is too wide for BIGINT keeps the decimal type.
+	wide := &Constant{Value: types.NewDecimalDatum(types.NewDecFromStringForTest("-123456789012345678901.5")), RetType: &types.FieldType{Tp: mysql.TypeNewDecimal, Flen: 22, Decimal: 1}}
+	ceil, err := ceilDecimal(wide.Value.GetMysqlDecimal())
+	c.Assert(err, IsNil)
+	c.Assert(ceil.String(), Equals, "-123456789012345678901")
+	f, err := funcs[ast.Floor].getFunction(s.ctx, []Expression{wide})
+	c.Assert(err, IsNil)
+	c.Assert(f.getRetTp().EvalType(), Equals, types.ETDecimal)
+	v, err := evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v.GetMysqlDecimal().String(), Equals, "-123456789012345678902")
+}
+
+func (s *testEvaluatorSuite) TestRoundAndTruncate(c *C) {
+	tbl := []struct {
+		fn   string
+		args []interface{}
+		ret  interface{}
+	}{
+		{ast.Round, []interface{}{nil}, nil},
+		{ast.Round, []interface{}{int64(-3)}, int64(-3)},
+		{ast.Round, []interface{}{float64(1.5)}, float64(2)},
+		{ast.Round, []interface{}{types.NewDecFromStringForTest("-1.5")}, types.NewDecFromStringForTest("-2")},
+		{ast.Round, []interface{}{float64(1.2345), int64(2)}, float64(1.23)},
+		{ast.Round, []interface{}{types.NewDecFromStringForTest("1.298"), int64(1)}, types.NewDecFromStringForTest("1.3")},
+		{ast.Round, []interface{}{int64(1234), int64(-2)}, int64(1200)},
+		{ast.Round, []interface{}{int64(1250), int64(-2)}, int64(1300)},
+		{ast.Round, []interface{}{float64(1234.5), nil}, nil},
+		{ast.Truncate, []interface{}{float64(1.999), int64(1)}, float64(1.9)},
+		{ast.Truncate, []interface{}{float64(-1.999), int64(0)}, float64(-1)},
+		{ast.Truncate, []interface{}{types.NewDecFromStringForTest("-1.999"), int64(2)}, types.NewDecFromStringForTest("-1.99")},
+		{ast.Truncate, []interface{}{int64(1299), int64(-2)}, int64(1200)},
+		{ast.Truncate, []interface{}{int64(math.MaxInt64), int64(-19)}, int64(0)},
+		{ast.Truncate, []interface{}{uint64(math.MaxUint64), int64(-19)}, uint64(10000000000000000000)},
+		{ast.Truncate, []interface{}{uint64(math.MaxUint64), int64(-20)}, uint64(0)},
+		{ast.Truncate, []interface{}{nil, int64(1)}, nil},
+	}
+	for _, t := range tbl {
+		f, err := funcs[t.fn].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(t.args...)))
+		c.Assert(err, IsNil)
+		v, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(v, testutil.DatumEquals, types.NewDatum(t.ret), Commentf("%s%v", t.fn, t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestRealMathFunctions(c *C) {
+	tbl := []struct {
+		fn   string
+		args []interface{}
+		ret  interface{}
+	}{
+		{ast.Pow, []interface{}{int64(2), int64(10)}, float64(1024)},
+		{ast.Power, []interface{}{float64(4), float64(0.5)}, float64(2)},
+		{ast.Pow, []interface{}{"2", "-1"}, float64(0.5)},
+		{ast.Sqrt, []interface{}{int64(16)}, float64(4)},
+		{ast.Sqrt, []interface{}{int64(-16)}, nil},
+		{ast.Exp, []interface{}{int64(0)}, float64(1)},
+		{ast.Ln, []interface{}{math.E}, float64(1)},
+		{ast.Ln, []interface{}{int64(0)}, nil},
+		{ast.Log, []interface{}{int64(2), int64(8)}, float64(3)},
+		{ast.Log, []interface{}{int64(1), int64(8)}, nil},
+		{ast.Log, []interface{}{int64(2), int64(-8)}, nil},
+		{ast.Log2, []interface{}{int64(8)}, float64(3)},
+		{ast.Log10, []interface{}{"100"}, float64(2)},
+		{ast.Sign, []interface{}{float64(-0.5)}, int64(-1)},
+		{ast.Sign, []interface{}{int64(0)}, int64(0)},
+		{ast.Sign, []interface{}{"12"}, int64(1)},
+		{ast.Acos, []interface{}{int64(1)}, float64(0)},
+		{ast.Acos, []interface{}{int64(2)}, nil},
+		{ast.Asin, []interface{}{int64(0)}, float64(0)},
+		{ast.Asin, []interface{}{int64(-2)}, nil},
+		{ast.Atan, []interface{}{int64(0)}, float64(0)},
+		{ast.Atan, []interface{}{int64(0), int64(-1)}, math.Pi},
+		{ast.Atan2, []interface{}{int64(1), int64(0)}, math.Pi / 2},
+		{ast.Cos, []interface{}{int64(0)}, float64(1)},
+		{ast.Sin, []interface{}{int64(0)}, float64(0)},
+		{ast.Tan, []interface{}{int64(0)}, float64(0)},
+		{ast.Cot, []interface{}{math.Pi / 4}, 1 / math.Tan(math.Pi/4)},
+		{ast.Degrees, []interface{}{math.Pi}, float64(180)},
+		{ast.Radians, []interface{}{int64(180)}, math.Pi},
+		{ast.Sqrt, []interface{}{nil}, nil},
+		{ast.Atan, []interface{}{int64(1), nil}, nil},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, t.fn, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		v, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(v, testutil.DatumEquals, types.NewDatum(t.ret), Commentf("%s%v", t.fn, t.args))
+	}
+
+	f, err := newFunctionForTest(s.ctx, ast.PI)
+	c.Assert(err, IsNil)
+	c.Assert(f.GetType().Decimal, Equals, 6)
+	v, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v.GetFloat64(), Equals, math.Pi)
+
+	errTbl := []struct {
+		fn   string
+		args []interface{}
+	}{
+		{ast.Pow, []interface{}{int64(10), int64(400)}},
+		{ast.Pow, []interface{}{int64(-8), float64(0.5)}},
+		{ast.Exp, []interface{}{int64(1000)}},
+		{ast.Cot, []interface{}{int64(0)}},
+	}
+	for _, t := range errTbl {
+		f, err := newFunctionForTest(s.ctx, t.fn, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		_, err = f.Eval(chunk.Row{})
+		c.Assert(terror.ErrorEqual(err, types.ErrOverflow), IsTrue, Commentf("%s%v", t.fn, t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestRand(c *C) {
+	f, err := newFunctionForTest(s.ctx, ast.Rand)
+	c.Assert(err, IsNil)
+	v, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v.GetFloat64(), Less, float64(1))
+	c.Assert(v.GetFloat64(), GreaterEqual, float64(0))
+
+	// RAND(N) with a constant N generates the same sequence as MySQL.
+	f, err = newFunctionForTest(s.ctx, ast.Rand, s.primitiveValsToConstants([]interface{}{int64(1)})...)
+	c.Assert(err, IsNil)
+	for _, expected := range []float64{0.40540353712197724, 0.8716141803857071} {
+		v, err = f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(v.GetFloat64(), Equals, expected)
+	}
+
+	// A NULL seed is the same as 0.
+	f, err = newFunctionForTest(s.ctx, ast.Rand, s.primitiveValsToConstants([]interface{}{nil})...)
+	c.Assert(err, IsNil)
+	v, err = f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v.GetFloat64(), Equals, 0.15522042769493574)
+
+	// RAND(N) with a non-constant N restarts from the seed for each row.
+	col := &Column{Index: 0, RetType: types.NewFieldType(mysql.TypeLonglong)}
+	sig, err := funcs[ast.Rand].getFunction(s.ctx, []Expression{col})
+	c.Assert(err, IsNil)
+	row := chunk.MutRowFromDatums(types.MakeDatums(int64(1))).ToRow()
+	for i := 0; i < 2; i++ {
+		res, isNull, err := sig.evalReal(row)
+		c.Assert(err, IsNil)
+		c.Assert(isNull, IsFalse)
+		c.Assert(res, Equals, 0.40540353712197724)
+	}
+}
+
+func (s *testEvaluatorSuite) TestCRC32(c *C) {
+	tbl := []struct {
+		input  interface{}
+		result interface{}
+	}{
+		{nil, nil},
+		{"", uint64(0)},
+		{"MySQL", uint64(3259397556)},
+		{"mysql", uint64(2501908538)},
+		{int64(123), uint64(2286445522)},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, ast.CRC32, s.primitiveValsToConstants([]interface{}{t.input})...)
+		c.Assert(err, IsNil)
+		v, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(v, testutil.DatumEquals, types.NewDatum(t.result))
+	}
+}
+
+func (s *testEvaluatorSuite) TestConv(c *C) {
+	tbl := []struct {
+		args []interface{}
+		ret  interface{}
+	}{
+		{[]interface{}{"a", 16, 2}, "1010"},
+		{[]interface{}{"6E", 18, 8}, "172"},
+		{[]interface{}{"-17", 10, -18}, "-H"},
+		{[]interface{}{"-17", 10, 18}, "2D3FGB0B9CG4BD1H"},
+		{[]interface{}{"-17", -10, -18}, "-H"},
+		{[]interface{}{"10", 10, 10}, "10"},
+		{[]interface{}{"+18aZ", 7, 36}, "1"},
+		{[]interface{}{"18446744073709551615", -10, 16}, "7FFFFFFFFFFFFFFF"},
+		{[]interface{}{"12F", -10, 16}, "C"},
+		{[]interface{}{"  FF ", 16, 10}, "255"},
+		{[]interface{}{"TIDB", 10, 8}, "0"},
+		{[]interface{}{"aa", 10, 2}, "0"},
+		{[]interface{}{" A", -10, 16}, "0"},
+		{[]interface{}{"a6a", 10, 8}, "0"},
+		{[]interface{}{"a6a", 1, 8}, nil},
+		{[]interface{}{"a6a", 10, 37}, nil},
+		{[]interface{}{nil, 10, 8}, nil},
+		{[]interface{}{"a", nil, 8}, nil},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, ast.Conv, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		v, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(v, testutil.DatumEquals, types.NewDatum(t.ret), Commentf("%v", t.args))
+	}
+
+	v := []struct {
+		s    string
+		base int64
+		ret  string
+	}{
+		{"-123456D1f", 5, "-1234"},
+		{"+12azD", 16, "12a"},
+		{"+", 12, ""},
+	}
+	for _, t := range v {
+		c.Assert(getValidPrefix(t.s, t.base), Equals, t.ret)
+	}
+}
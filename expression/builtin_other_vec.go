@@ -46,6 +46,14 @@ func (b *builtinValuesRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Col
 	return errors.Errorf("not implemented")
 }
 
+func (b *builtinValuesDecimalSig) vectorized() bool {
+	return false
+}
+
+func (b *builtinValuesDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	return errors.Errorf("not implemented")
+}
+
 func (b *builtinValuesStringSig) vectorized() bool {
 	return false
 }
@@ -125,3 +133,19 @@ func (b *builtinGetVarSig) vecEvalString(input *chunk.Chunk, result *chunk.Colum
 	}
 	return nil
 }
+
+func (b *builtinValuesTimeSig) vectorized() bool {
+	return false
+}
+
+func (b *builtinValuesTimeSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	return errors.Errorf("not implemented")
+}
+
+func (b *builtinValuesDurationSig) vectorized() bool {
+	return false
+}
+
+func (b *builtinValuesDurationSig) vecEvalDuration(input *chunk.Chunk, result *chunk.Column) error {
+	return errors.Errorf("not implemented")
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
@@ -45,6 +46,16 @@ func (g inGener) gen() interface{} {
 			return -float64(randNum)
 		}
 		return float64(randNum)
+	case types.ETDecimal:
+		if rand.Float64() < 0.5 {
+			return types.NewDecFromInt(-randNum)
+		}
+		return types.NewDecFromInt(randNum)
+	case types.ETDatetime, types.ETTimestamp:
+		gt := types.FromDate(2000+int(randNum), int(randNum)+1, int(randNum)+1, 0, 0, 0, 0)
+		return types.NewTime(gt, convertETType(g.eType), 0)
+	case types.ETDuration:
+		return types.Duration{Duration: time.Duration(randNum) * time.Second}
 	case types.ETString:
 		return fmt.Sprint(randNum)
 	}
@@ -101,6 +112,54 @@ var vecBuiltinOtherGeneratedCases = map[string][]vecExprBenchCase{
 				inGener{defaultGener{eType: types.ETReal, nullRation: 0.2}},
 			},
 		},
+		// builtinInDecimalSig
+		{
+			retEvalType: types.ETInt,
+			childrenTypes: []types.EvalType{
+				types.ETDecimal,
+				types.ETDecimal,
+				types.ETDecimal,
+				types.ETDecimal,
+			},
+			geners: []dataGenerator{
+				inGener{defaultGener{eType: types.ETDecimal, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDecimal, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDecimal, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDecimal, nullRation: 0.2}},
+			},
+		},
+		// builtinInTimeSig
+		{
+			retEvalType: types.ETInt,
+			childrenTypes: []types.EvalType{
+				types.ETDatetime,
+				types.ETDatetime,
+				types.ETDatetime,
+				types.ETDatetime,
+			},
+			geners: []dataGenerator{
+				inGener{defaultGener{eType: types.ETDatetime, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDatetime, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDatetime, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDatetime, nullRation: 0.2}},
+			},
+		},
+		// builtinInDurationSig
+		{
+			retEvalType: types.ETInt,
+			childrenTypes: []types.EvalType{
+				types.ETDuration,
+				types.ETDuration,
+				types.ETDuration,
+				types.ETDuration,
+			},
+			geners: []dataGenerator{
+				inGener{defaultGener{eType: types.ETDuration, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDuration, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDuration, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDuration, nullRation: 0.2}},
+			},
+		},
 	},
 }
 
@@ -18,8 +18,19 @@
 package expression
 
 import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tipb/go-tipb"
@@ -28,11 +39,77 @@ import (
 var (
 	_ functionClass = &lengthFunctionClass{}
 	_ functionClass = &strcmpFunctionClass{}
+	_ functionClass = &concatFunctionClass{}
+	_ functionClass = &concatWSFunctionClass{}
+	_ functionClass = &substringFunctionClass{}
+	_ functionClass = &leftFunctionClass{}
+	_ functionClass = &rightFunctionClass{}
+	_ functionClass = &upperFunctionClass{}
+	_ functionClass = &lowerFunctionClass{}
+	_ functionClass = &trimFunctionClass{}
+	_ functionClass = &lTrimFunctionClass{}
+	_ functionClass = &rTrimFunctionClass{}
+	_ functionClass = &replaceFunctionClass{}
+	_ functionClass = &locateFunctionClass{}
+	_ functionClass = &instrFunctionClass{}
+	_ functionClass = &lpadFunctionClass{}
+	_ functionClass = &rpadFunctionClass{}
+	_ functionClass = &repeatFunctionClass{}
+	_ functionClass = &reverseFunctionClass{}
+	_ functionClass = &charLengthFunctionClass{}
+	_ functionClass = &hexFunctionClass{}
+	_ functionClass = &unhexFunctionClass{}
+	_ functionClass = &formatFunctionClass{}
+	_ functionClass = &fieldFunctionClass{}
+	_ functionClass = &eltFunctionClass{}
 )
 
 var (
 	_ builtinFunc = &builtinLengthSig{}
 	_ builtinFunc = &builtinStrcmpSig{}
+	_ builtinFunc = &builtinConcatSig{}
+	_ builtinFunc = &builtinConcatWSSig{}
+	_ builtinFunc = &builtinSubstring2ArgsSig{}
+	_ builtinFunc = &builtinSubstring3ArgsSig{}
+	_ builtinFunc = &builtinSubstring2ArgsUTF8Sig{}
+	_ builtinFunc = &builtinSubstring3ArgsUTF8Sig{}
+	_ builtinFunc = &builtinLeftSig{}
+	_ builtinFunc = &builtinLeftUTF8Sig{}
+	_ builtinFunc = &builtinRightSig{}
+	_ builtinFunc = &builtinRightUTF8Sig{}
+	_ builtinFunc = &builtinUpperSig{}
+	_ builtinFunc = &builtinUpperUTF8Sig{}
+	_ builtinFunc = &builtinLowerSig{}
+	_ builtinFunc = &builtinTrim1ArgSig{}
+	_ builtinFunc = &builtinTrim2ArgsSig{}
+	_ builtinFunc = &builtinTrim3ArgsSig{}
+	_ builtinFunc = &builtinLTrimSig{}
+	_ builtinFunc = &builtinRTrimSig{}
+	_ builtinFunc = &builtinReplaceSig{}
+	_ builtinFunc = &builtinLocate2ArgsSig{}
+	_ builtinFunc = &builtinLocate3ArgsSig{}
+	_ builtinFunc = &builtinLocate2ArgsUTF8Sig{}
+	_ builtinFunc = &builtinLocate3ArgsUTF8Sig{}
+	_ builtinFunc = &builtinInstrSig{}
+	_ builtinFunc = &builtinInstrUTF8Sig{}
+	_ builtinFunc = &builtinLpadSig{}
+	_ builtinFunc = &builtinLpadUTF8Sig{}
+	_ builtinFunc = &builtinRpadSig{}
+	_ builtinFunc = &builtinRpadUTF8Sig{}
+	_ builtinFunc = &builtinRepeatSig{}
+	_ builtinFunc = &builtinReverseSig{}
+	_ builtinFunc = &builtinReverseUTF8Sig{}
+	_ builtinFunc = &builtinCharLengthSig{}
+	_ builtinFunc = &builtinCharLengthUTF8Sig{}
+	_ builtinFunc = &builtinHexStrArgSig{}
+	_ builtinFunc = &builtinHexIntArgSig{}
+	_ builtinFunc = &builtinUnHexSig{}
+	_ builtinFunc = &builtinFormatSig{}
+	_ builtinFunc = &builtinFormatWithLocaleSig{}
+	_ builtinFunc = &builtinFieldIntSig{}
+	_ builtinFunc = &builtinFieldRealSig{}
+	_ builtinFunc = &builtinFieldStringSig{}
+	_ builtinFunc = &builtinEltSig{}
 )
 
 // SetBinFlagOrBinStr sets resTp to binary string if argTp is a binary string,
@@ -126,3 +203,2126 @@ func (b *builtinStrcmpSig) evalInt(row chunk.Row) (int64, bool, error) {
 	res := types.CompareString(left, right)
 	return int64(res), false, nil
 }
+
+// getMaxAllowedPacket gets the max_allowed_packet of the session, which limits the length of the result
+// of functions like CONCAT, REPEAT and LPAD.
+func getMaxAllowedPacket(ctx sessionctx.Context) (uint64, error) {
+	valStr, _ := ctx.GetSessionVars().GetSystemVar(variable.MaxAllowedPacket)
+	maxAllowedPacket, err := strconv.ParseUint(valStr, 10, 64)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return maxAllowedPacket, nil
+}
+
+// handleAllowedPacketOverflowed reports error or warning depend on the context.
+// The result of the function is NULL in both cases.
+func handleAllowedPacketOverflowed(ctx sessionctx.Context, exprName string, maxAllowedPacket uint64) error {
+	err := errWarnAllowedPacketOverflowed.GenWithStackByArgs(exprName, maxAllowedPacket)
+	sc := ctx.GetSessionVars().StmtCtx
+	if (sc.InInsertStmt || sc.InUpdateStmt || sc.InDeleteStmt) && !sc.TruncateAsWarning {
+		return err
+	}
+	sc.AppendWarning(err)
+	return nil
+}
+
+type concatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *concatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	bf.tp.Flen = 0
+	for i := range args {
+		argType := args[i].GetType()
+		SetBinFlagOrBinStr(argType, bf.tp)
+		if argType.Flen < 0 {
+			bf.tp.Flen = mysql.MaxBlobWidth
+			break
+		}
+		bf.tp.Flen += argType.Flen
+	}
+	if bf.tp.Flen >= mysql.MaxBlobWidth {
+		bf.tp.Flen = mysql.MaxBlobWidth
+	}
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinConcatSig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_Concat)
+	return sig, nil
+}
+
+type builtinConcatSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinConcatSig) Clone() builtinFunc {
+	newSig := &builtinConcatSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals a builtinConcatSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat
+func (b *builtinConcatSig) evalString(row chunk.Row) (string, bool, error) {
+	var s []byte
+	for _, a := range b.args {
+		d, isNull, err := a.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		if uint64(len(s)+len(d)) > b.maxAllowedPacket {
+			return "", true, handleAllowedPacketOverflowed(b.ctx, "concat", b.maxAllowedPacket)
+		}
+		s = append(s, []byte(d)...)
+	}
+	return string(s), false, nil
+}
+
+type concatWSFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *concatWSFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	bf.tp.Flen = 0
+	for i := range args {
+		argType := args[i].GetType()
+		SetBinFlagOrBinStr(argType, bf.tp)
+		if argType.Flen < 0 {
+			bf.tp.Flen = mysql.MaxBlobWidth
+			break
+		}
+		// The separator is added between each pair of the following arguments.
+		if i == 0 {
+			bf.tp.Flen += (len(args) - 2) * argType.Flen
+		} else {
+			bf.tp.Flen += argType.Flen
+		}
+	}
+	if bf.tp.Flen >= mysql.MaxBlobWidth {
+		bf.tp.Flen = mysql.MaxBlobWidth
+	}
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinConcatWSSig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_ConcatWS)
+	return sig, nil
+}
+
+type builtinConcatWSSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinConcatWSSig) Clone() builtinFunc {
+	newSig := &builtinConcatWSSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals a builtinConcatWSSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat-ws
+func (b *builtinConcatWSSig) evalString(row chunk.Row) (string, bool, error) {
+	sep, isNull, err := b.args[0].EvalString(b.ctx, row)
+	// If the separator is NULL, the result is NULL.
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	var s []byte
+	first := true
+	for _, a := range b.args[1:] {
+		d, isNull, err := a.EvalString(b.ctx, row)
+		if err != nil {
+			return "", true, err
+		}
+		// CONCAT_WS() does not skip empty strings. However, it does skip any NULL values after the separator argument.
+		if isNull {
+			continue
+		}
+		if !first {
+			s = append(s, sep...)
+		}
+		first = false
+		if uint64(len(s)+len(d)) > b.maxAllowedPacket {
+			return "", true, handleAllowedPacketOverflowed(b.ctx, "concat_ws", b.maxAllowedPacket)
+		}
+		s = append(s, d...)
+	}
+	return string(s), false, nil
+}
+
+// getSubstringRange gets the range [start, end) of SUBSTRING(str, pos, length) in a string of n characters,
+// a negative length means the substring lasts to the end of the string.
+func getSubstringRange(n, pos, length int64) (start, end int64) {
+	switch {
+	case pos < 0:
+		pos += n
+	case pos > 0:
+		pos--
+	default:
+		// SUBSTRING(str, 0) is always an empty string.
+		return 0, 0
+	}
+	if pos < 0 || pos >= n {
+		return 0, 0
+	}
+	end = n
+	if length >= 0 && length < end-pos {
+		end = pos + length
+	}
+	return pos, end
+}
+
+// substringBinary gets the substring of str, pos and length are counted in bytes.
+func substringBinary(str string, pos, length int64) string {
+	start, end := getSubstringRange(int64(len(str)), pos, length)
+	return str[start:end]
+}
+
+// substringUTF8 gets the substring of str, pos and length are counted in characters.
+func substringUTF8(str string, pos, length int64) string {
+	runes := []rune(str)
+	start, end := getSubstringRange(int64(len(runes)), pos, length)
+	return string(runes[start:end])
+}
+
+type substringFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *substringFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETInt}
+	if len(args) == 3 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+
+	var sig builtinFunc
+	switch {
+	case len(args) == 3 && types.IsBinaryStr(argType):
+		sig = &builtinSubstring3ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring3Args)
+	case len(args) == 3:
+		sig = &builtinSubstring3ArgsUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring3ArgsUTF8)
+	case len(args) == 2 && types.IsBinaryStr(argType):
+		sig = &builtinSubstring2ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring2Args)
+	case len(args) == 2:
+		sig = &builtinSubstring2ArgsUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring2ArgsUTF8)
+	default:
+		// Should never happens.
+		return nil, errors.Errorf("SUBSTR invalid arg length, expect 2 or 3 but got: %v", len(args))
+	}
+	return sig, nil
+}
+
+// evalSubstringArgs evaluates the arguments of SUBSTRING, length is -1 if it's not given.
+func evalSubstringArgs(b *baseBuiltinFunc, row chunk.Row) (str string, pos, length int64, isNull bool, err error) {
+	str, isNull, err = b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return
+	}
+	pos, isNull, err = b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return
+	}
+	length = -1
+	if len(b.args) == 3 {
+		length, isNull, err = b.args[2].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return
+		}
+		// SUBSTRING(str, pos, len) returns an empty string if len is less than 1.
+		if length < 0 {
+			length = 0
+		}
+	}
+	return
+}
+
+type builtinSubstring2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinSubstring2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos), SUBSTR(str FROM pos), SUBSTR() is a synonym for SUBSTRING().
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring2ArgsSig) evalString(row chunk.Row) (string, bool, error) {
+	str, pos, length, isNull, err := evalSubstringArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return substringBinary(str, pos, length), false, nil
+}
+
+type builtinSubstring3ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring3ArgsSig) Clone() builtinFunc {
+	newSig := &builtinSubstring3ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos,len), SUBSTR(str FROM pos FOR len), SUBSTR() is a synonym for SUBSTRING().
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring3ArgsSig) evalString(row chunk.Row) (string, bool, error) {
+	str, pos, length, isNull, err := evalSubstringArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return substringBinary(str, pos, length), false, nil
+}
+
+type builtinSubstring2ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring2ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinSubstring2ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos), SUBSTR(str FROM pos), SUBSTR() is a synonym for SUBSTRING().
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring2ArgsUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, pos, length, isNull, err := evalSubstringArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return substringUTF8(str, pos, length), false, nil
+}
+
+type builtinSubstring3ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring3ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinSubstring3ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos,len), SUBSTR(str FROM pos FOR len), SUBSTR() is a synonym for SUBSTRING().
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring3ArgsUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, pos, length, isNull, err := evalSubstringArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return substringUTF8(str, pos, length), false, nil
+}
+
+// leftBinary returns the leftmost n bytes of str.
+func leftBinary(str string, n int64) string {
+	if n < 0 {
+		n = 0
+	}
+	if n > int64(len(str)) {
+		return str
+	}
+	return str[:n]
+}
+
+// leftUTF8 returns the leftmost n characters of str.
+func leftUTF8(str string, n int64) string {
+	if n < 0 {
+		n = 0
+	}
+	runes := []rune(str)
+	if n > int64(len(runes)) {
+		return str
+	}
+	return string(runes[:n])
+}
+
+// rightBinary returns the rightmost n bytes of str.
+func rightBinary(str string, n int64) string {
+	if n < 0 {
+		n = 0
+	}
+	if n > int64(len(str)) {
+		return str
+	}
+	return str[int64(len(str))-n:]
+}
+
+// rightUTF8 returns the rightmost n characters of str.
+func rightUTF8(str string, n int64) string {
+	if n < 0 {
+		n = 0
+	}
+	runes := []rune(str)
+	if n > int64(len(runes)) {
+		return str
+	}
+	return string(runes[int64(len(runes))-n:])
+}
+
+type leftFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *leftFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	if types.IsBinaryStr(argType) {
+		sig := &builtinLeftSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Left)
+		return sig, nil
+	}
+	sig := &builtinLeftUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_LeftUTF8)
+	return sig, nil
+}
+
+// evalStringIntArgs evaluates the (string, int) arguments of functions like LEFT and REPEAT.
+func evalStringIntArgs(b *baseBuiltinFunc, row chunk.Row) (string, int64, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", 0, isNull, err
+	}
+	n, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", 0, isNull, err
+	}
+	return str, n, false, nil
+}
+
+type builtinLeftSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeftSig) Clone() builtinFunc {
+	newSig := &builtinLeftSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals LEFT(str,len).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_left
+func (b *builtinLeftSig) evalString(row chunk.Row) (string, bool, error) {
+	str, n, isNull, err := evalStringIntArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return leftBinary(str, n), false, nil
+}
+
+type builtinLeftUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeftUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLeftUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals LEFT(str,len).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_left
+func (b *builtinLeftUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, n, isNull, err := evalStringIntArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return leftUTF8(str, n), false, nil
+}
+
+type rightFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rightFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	if types.IsBinaryStr(argType) {
+		sig := &builtinRightSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Right)
+		return sig, nil
+	}
+	sig := &builtinRightUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_RightUTF8)
+	return sig, nil
+}
+
+type builtinRightSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRightSig) Clone() builtinFunc {
+	newSig := &builtinRightSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals RIGHT(str,len).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_right
+func (b *builtinRightSig) evalString(row chunk.Row) (string, bool, error) {
+	str, n, isNull, err := evalStringIntArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return rightBinary(str, n), false, nil
+}
+
+type builtinRightUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRightUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinRightUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals RIGHT(str,len).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_right
+func (b *builtinRightUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, n, isNull, err := evalStringIntArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return rightUTF8(str, n), false, nil
+}
+
+type upperFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *upperFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argTp := args[0].GetType()
+	bf.tp.Flen = argTp.Flen
+	SetBinFlagOrBinStr(argTp, bf.tp)
+	if types.IsBinaryStr(argTp) {
+		sig := &builtinUpperSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Upper)
+		return sig, nil
+	}
+	sig := &builtinUpperUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_UpperUTF8)
+	return sig, nil
+}
+
+type builtinUpperSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUpperSig) Clone() builtinFunc {
+	newSig := &builtinUpperSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinUpperSig, UPPER() is ineffective when applied to binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_upper
+func (b *builtinUpperSig) evalString(row chunk.Row) (string, bool, error) {
+	return b.args[0].EvalString(b.ctx, row)
+}
+
+type builtinUpperUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUpperUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinUpperUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinUpperUTF8Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_upper
+func (b *builtinUpperUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	d, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return strings.ToUpper(d), false, nil
+}
+
+type lowerFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lowerFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argTp := args[0].GetType()
+	bf.tp.Flen = argTp.Flen
+	SetBinFlagOrBinStr(argTp, bf.tp)
+	sig := &builtinLowerSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Lower)
+	return sig, nil
+}
+
+type builtinLowerSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLowerSig) Clone() builtinFunc {
+	newSig := &builtinLowerSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinLowerSig, LOWER() is ineffective when applied to binary strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lower
+func (b *builtinLowerSig) evalString(row chunk.Row) (string, bool, error) {
+	d, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	if types.IsBinaryStr(b.args[0].GetType()) {
+		return d, false, nil
+	}
+	return strings.ToLower(d), false, nil
+}
+
+// trimLeft removes all the leading remstr prefixes from str.
+func trimLeft(str, remstr string) string {
+	if len(remstr) == 0 {
+		return str
+	}
+	for strings.HasPrefix(str, remstr) {
+		str = str[len(remstr):]
+	}
+	return str
+}
+
+// trimRight removes all the trailing remstr suffixes from str.
+func trimRight(str, remstr string) string {
+	if len(remstr) == 0 {
+		return str
+	}
+	for strings.HasSuffix(str, remstr) {
+		str = str[:len(str)-len(remstr)]
+	}
+	return str
+}
+
+// trimByDirection removes remstr from str by the TRIM direction.
+func trimByDirection(str, remstr string, direction ast.TrimDirectionType) string {
+	switch direction {
+	case ast.TrimLeading:
+		return trimLeft(str, remstr)
+	case ast.TrimTrailing:
+		return trimRight(str, remstr)
+	default:
+		return trimLeft(trimRight(str, remstr), remstr)
+	}
+}
+
+type trimFunctionClass struct {
+	baseFunctionClass
+}
+
+// getFunction sets trim built-in function signature.
+// The syntax of trim in mysql is 'TRIM([{BOTH | LEADING | TRAILING} [remstr] FROM] str), TRIM([remstr FROM] str)',
+// but we wil convert it into trim(str), trim(str, remstr) and trim(str, remstr, direction) in AST.
+func (c *trimFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	switch len(args) {
+	case 1:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+		argType := args[0].GetType()
+		bf.tp.Flen = argType.Flen
+		SetBinFlagOrBinStr(argType, bf.tp)
+		sig := &builtinTrim1ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Trim1Arg)
+		return sig, nil
+
+	case 2:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString)
+		argType := args[0].GetType()
+		SetBinFlagOrBinStr(argType, bf.tp)
+		bf.tp.Flen = argType.Flen
+		sig := &builtinTrim2ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Trim2Args)
+		return sig, nil
+
+	case 3:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString, types.ETInt)
+		argType := args[0].GetType()
+		bf.tp.Flen = argType.Flen
+		SetBinFlagOrBinStr(argType, bf.tp)
+		sig := &builtinTrim3ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Trim3Args)
+		return sig, nil
+
+	default:
+		// Should never happens.
+		return nil, errors.Errorf("TRIM invalid arg length, expect 1, 2 or 3 but got: %v", len(args))
+	}
+}
+
+type builtinTrim1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTrim1ArgSig) Clone() builtinFunc {
+	newSig := &builtinTrim1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTrim1ArgSig, corresponding to trim(str)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_trim
+func (b *builtinTrim1ArgSig) evalString(row chunk.Row) (string, bool, error) {
+	d, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return strings.Trim(d, " "), false, nil
+}
+
+type builtinTrim2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTrim2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinTrim2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTrim2ArgsSig, corresponding to trim(str, remstr)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_trim
+func (b *builtinTrim2ArgsSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	remstr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return trimByDirection(str, remstr, ast.TrimBoth), false, nil
+}
+
+type builtinTrim3ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTrim3ArgsSig) Clone() builtinFunc {
+	newSig := &builtinTrim3ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTrim3ArgsSig, corresponding to trim(str, remstr, direction)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_trim
+func (b *builtinTrim3ArgsSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	remstr, isRemStrNull, err := b.args[1].EvalString(b.ctx, row)
+	if err != nil {
+		return "", true, err
+	}
+	// The remstr is omitted in TRIM(direction FROM str), which trims spaces.
+	if isRemStrNull {
+		remstr = " "
+	}
+	direction, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return trimByDirection(str, remstr, ast.TrimDirectionType(direction)), false, nil
+}
+
+type lTrimFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lTrimFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := &builtinLTrimSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_LTrim)
+	return sig, nil
+}
+
+type builtinLTrimSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTrimSig) Clone() builtinFunc {
+	newSig := &builtinLTrimSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinLTrimSig
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_ltrim
+func (b *builtinLTrimSig) evalString(row chunk.Row) (string, bool, error) {
+	d, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return strings.TrimLeft(d, " "), false, nil
+}
+
+type rTrimFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rTrimFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := &builtinRTrimSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_RTrim)
+	return sig, nil
+}
+
+type builtinRTrimSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRTrimSig) Clone() builtinFunc {
+	newSig := &builtinRTrimSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinRTrimSig
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rtrim
+func (b *builtinRTrimSig) evalString(row chunk.Row) (string, bool, error) {
+	d, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return strings.TrimRight(d, " "), false, nil
+}
+
+type replaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *replaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString, types.ETString)
+	bf.tp.Flen = c.fixLength(args)
+	for _, a := range args {
+		SetBinFlagOrBinStr(a.GetType(), bf.tp)
+	}
+	sig := &builtinReplaceSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Replace)
+	return sig, nil
+}
+
+// fixLength calculate the Flen of the return type.
+func (c *replaceFunctionClass) fixLength(args []Expression) int {
+	charLen := args[0].GetType().Flen
+	oldStrLen := args[1].GetType().Flen
+	diff := args[2].GetType().Flen - oldStrLen
+	if diff > 0 && oldStrLen > 0 {
+		charLen += (charLen / oldStrLen) * diff
+	}
+	if charLen > mysql.MaxBlobWidth {
+		charLen = mysql.MaxBlobWidth
+	}
+	return charLen
+}
+
+type builtinReplaceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReplaceSig) Clone() builtinFunc {
+	newSig := &builtinReplaceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinReplaceSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_replace
+func (b *builtinReplaceSig) evalString(row chunk.Row) (string, bool, error) {
+	var str, oldStr, newStr string
+	var isNull bool
+	var err error
+
+	str, isNull, err = b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	oldStr, isNull, err = b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	newStr, isNull, err = b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	if oldStr == "" {
+		return str, false, nil
+	}
+	return strings.Replace(str, oldStr, newStr, -1), false, nil
+}
+
+// locateBinary returns the position of the first occurrence of subStr in str starting at pos,
+// the positions are counted in bytes.
+func locateBinary(subStr, str string, pos int64) int64 {
+	if pos < 1 || pos > int64(len(str)-len(subStr)+1) {
+		return 0
+	}
+	idx := strings.Index(str[pos-1:], subStr)
+	if idx == -1 {
+		return 0
+	}
+	return pos + int64(idx)
+}
+
+// locateUTF8 returns the position of the first occurrence of subStr in str starting at pos,
+// the positions are counted in characters.
+func locateUTF8(subStr, str string, pos int64) int64 {
+	if pos < 1 || pos > int64(utf8.RuneCountInString(str)-utf8.RuneCountInString(subStr)+1) {
+		return 0
+	}
+	slice := string([]rune(str)[pos-1:])
+	idx := strings.Index(slice, subStr)
+	if idx == -1 {
+		return 0
+	}
+	return pos + int64(utf8.RuneCountInString(slice[:idx]))
+}
+
+type locateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *locateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	hasStartPos, argTps := len(args) == 3, []types.EvalType{types.ETString, types.ETString}
+	if hasStartPos {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 11
+	var sig builtinFunc
+	// The positions are counted in bytes if at least one argument is a binary string.
+	isBinary := types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[1].GetType())
+	switch {
+	case hasStartPos && isBinary:
+		sig = &builtinLocate3ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Locate3Args)
+	case hasStartPos:
+		sig = &builtinLocate3ArgsUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Locate3ArgsUTF8)
+	case isBinary:
+		sig = &builtinLocate2ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Locate2Args)
+	default:
+		sig = &builtinLocate2ArgsUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Locate2ArgsUTF8)
+	}
+	return sig, nil
+}
+
+// evalLocateArgs evaluates the arguments of LOCATE, pos is 1 if it's not given.
+func evalLocateArgs(b *baseBuiltinFunc, row chunk.Row) (subStr, str string, pos int64, isNull bool, err error) {
+	subStr, isNull, err = b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return
+	}
+	str, isNull, err = b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return
+	}
+	pos = 1
+	if len(b.args) == 3 {
+		pos, isNull, err = b.args[2].EvalInt(b.ctx, row)
+	}
+	return
+}
+
+type builtinLocate2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinLocate2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str), case-sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate2ArgsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, str, pos, isNull, err := evalLocateArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateBinary(subStr, str, pos), false, nil
+}
+
+type builtinLocate3ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate3ArgsSig) Clone() builtinFunc {
+	newSig := &builtinLocate3ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str,pos), case-sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate3ArgsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, str, pos, isNull, err := evalLocateArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateBinary(subStr, str, pos), false, nil
+}
+
+type builtinLocate2ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate2ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLocate2ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str), multibyte safe.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate2ArgsUTF8Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, str, pos, isNull, err := evalLocateArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateUTF8(subStr, str, pos), false, nil
+}
+
+type builtinLocate3ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate3ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLocate3ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str,pos), multibyte safe.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate3ArgsUTF8Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, str, pos, isNull, err := evalLocateArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateUTF8(subStr, str, pos), false, nil
+}
+
+type instrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *instrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETString)
+	bf.tp.Flen = 11
+	if types.IsBinaryStr(bf.args[0].GetType()) || types.IsBinaryStr(bf.args[1].GetType()) {
+		sig := &builtinInstrSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Instr)
+		return sig, nil
+	}
+	sig := &builtinInstrUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_InstrUTF8)
+	return sig, nil
+}
+
+// evalInstrArgs evaluates the arguments of INSTR(str,substr).
+func evalInstrArgs(b *baseBuiltinFunc, row chunk.Row) (str, subStr string, isNull bool, err error) {
+	str, isNull, err = b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return
+	}
+	subStr, isNull, err = b.args[1].EvalString(b.ctx, row)
+	return
+}
+
+type builtinInstrSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinInstrSig) Clone() builtinFunc {
+	newSig := &builtinInstrSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals INSTR(str,substr), case-sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_instr
+func (b *builtinInstrSig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, subStr, isNull, err := evalInstrArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateBinary(subStr, str, 1), false, nil
+}
+
+type builtinInstrUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinInstrUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinInstrUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals INSTR(str,substr), multibyte safe.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_instr
+func (b *builtinInstrUTF8Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, subStr, isNull, err := evalInstrArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return locateUTF8(subStr, str, 1), false, nil
+}
+
+// getFlen4LpadAndRpad gets the Flen of the result of LPAD and RPAD, which is the length argument if it's a constant.
+func getFlen4LpadAndRpad(ctx sessionctx.Context, arg Expression) int {
+	if constant, ok := arg.(*Constant); ok {
+		length, isNull, err := constant.EvalInt(ctx, chunk.Row{})
+		if isNull || err != nil || length > mysql.MaxBlobWidth {
+			return mysql.MaxBlobWidth
+		}
+		if length < 0 {
+			return 0
+		}
+		return int(length)
+	}
+	return mysql.MaxBlobWidth
+}
+
+// padBinary pads str to length bytes with padStr, the padding is on the left if isLeft is true.
+// The result is NULL if str needs padding but padStr is empty.
+func padBinary(str string, length int64, padStr string, isLeft bool) (string, bool) {
+	if length <= int64(len(str)) {
+		return str[:length], false
+	}
+	if len(padStr) == 0 {
+		return "", true
+	}
+	tailLen := int(length) - len(str)
+	pad := strings.Repeat(padStr, tailLen/len(padStr)+1)[:tailLen]
+	if isLeft {
+		return pad + str, false
+	}
+	return str + pad, false
+}
+
+// padUTF8 pads str to length characters with padStr, the padding is on the left if isLeft is true.
+// The result is NULL if str needs padding but padStr is empty.
+func padUTF8(str string, length int64, padStr string, isLeft bool) (string, bool) {
+	runeLength := int64(utf8.RuneCountInString(str))
+	if length <= runeLength {
+		return string([]rune(str)[:length]), false
+	}
+	padLength := utf8.RuneCountInString(padStr)
+	if padLength == 0 {
+		return "", true
+	}
+	tailLen := int(length - runeLength)
+	pad := string([]rune(strings.Repeat(padStr, tailLen/padLength+1))[:tailLen])
+	if isLeft {
+		return pad + str, false
+	}
+	return str + pad, false
+}
+
+// getPadFunction gets the signature of LPAD or RPAD by the charset of the arguments.
+func getPadFunction(ctx sessionctx.Context, args []Expression, isLeft bool) (builtinFunc, error) {
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt, types.ETString)
+	bf.tp.Flen = getFlen4LpadAndRpad(ctx, args[1])
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	SetBinFlagOrBinStr(args[2].GetType(), bf.tp)
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	isBinary := types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[2].GetType())
+	var sig builtinFunc
+	switch {
+	case isLeft && isBinary:
+		sig = &builtinLpadSig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_Lpad)
+	case isLeft:
+		sig = &builtinLpadUTF8Sig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_LpadUTF8)
+	case isBinary:
+		sig = &builtinRpadSig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_Rpad)
+	default:
+		sig = &builtinRpadUTF8Sig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_RpadUTF8)
+	}
+	return sig, nil
+}
+
+// evalPadArgs evaluates the arguments of LPAD and RPAD.
+func evalPadArgs(b *baseBuiltinFunc, row chunk.Row) (str string, length int64, padStr string, isNull bool, err error) {
+	str, length, isNull, err = evalStringIntArgs(b, row)
+	if isNull || err != nil {
+		return
+	}
+	padStr, isNull, err = b.args[2].EvalString(b.ctx, row)
+	return
+}
+
+// pad evaluates LPAD or RPAD with the arguments, which is NULL if the length is negative or too large.
+func pad(b *baseBuiltinFunc, funcName string, maxAllowedPacket uint64, str string, length int64, padStr string, isLeft, isUTF8 bool) (string, bool, error) {
+	if length < 0 {
+		return "", true, nil
+	}
+	if uint64(length) > maxAllowedPacket {
+		return "", true, handleAllowedPacketOverflowed(b.ctx, funcName, maxAllowedPacket)
+	}
+	var res string
+	var isNull bool
+	if isUTF8 {
+		res, isNull = padUTF8(str, length, padStr, isLeft)
+	} else {
+		res, isNull = padBinary(str, length, padStr, isLeft)
+	}
+	return res, isNull, nil
+}
+
+type lpadFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lpadFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return getPadFunction(ctx, args, true)
+}
+
+type builtinLpadSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinLpadSig) Clone() builtinFunc {
+	newSig := &builtinLpadSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals LPAD(str,len,padstr).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lpad
+func (b *builtinLpadSig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return pad(&b.baseBuiltinFunc, "lpad", b.maxAllowedPacket, str, length, padStr, true, false)
+}
+
+type builtinLpadUTF8Sig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinLpadUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLpadUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals LPAD(str,len,padstr).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lpad
+func (b *builtinLpadUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return pad(&b.baseBuiltinFunc, "lpad", b.maxAllowedPacket, str, length, padStr, true, true)
+}
+
+type rpadFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rpadFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return getPadFunction(ctx, args, false)
+}
+
+type builtinRpadSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinRpadSig) Clone() builtinFunc {
+	newSig := &builtinRpadSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals RPAD(str,len,padstr).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rpad
+func (b *builtinRpadSig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return pad(&b.baseBuiltinFunc, "rpad", b.maxAllowedPacket, str, length, padStr, false, false)
+}
+
+type builtinRpadUTF8Sig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinRpadUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinRpadUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evals RPAD(str,len,padstr).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rpad
+func (b *builtinRpadUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return pad(&b.baseBuiltinFunc, "rpad", b.maxAllowedPacket, str, length, padStr, false, true)
+}
+
+type repeatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *repeatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt)
+	bf.tp.Flen = mysql.MaxBlobWidth
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinRepeatSig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_Repeat)
+	return sig, nil
+}
+
+type builtinRepeatSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinRepeatSig) Clone() builtinFunc {
+	newSig := &builtinRepeatSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// repeat repeats str num times, the result is NULL if it's larger than max_allowed_packet.
+func (b *builtinRepeatSig) repeat(str string, num int64) (string, bool, error) {
+	if num < 1 || len(str) == 0 {
+		return "", false, nil
+	}
+	if num > math.MaxInt32 {
+		num = math.MaxInt32
+	}
+	if uint64(len(str))*uint64(num) > b.maxAllowedPacket {
+		return "", true, handleAllowedPacketOverflowed(b.ctx, "repeat", b.maxAllowedPacket)
+	}
+	return strings.Repeat(str, int(num)), false, nil
+}
+
+// evalString evals a builtinRepeatSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_repeat
+func (b *builtinRepeatSig) evalString(row chunk.Row) (string, bool, error) {
+	str, num, isNull, err := evalStringIntArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return b.repeat(str, num)
+}
+
+// reverseBytes reverses str byte by byte.
+func reverseBytes(str string) string {
+	bs := []byte(str)
+	for i, j := 0, len(bs)-1; i < j; i, j = i+1, j-1 {
+		bs[i], bs[j] = bs[j], bs[i]
+	}
+	return string(bs)
+}
+
+// reverseRunes reverses str character by character.
+func reverseRunes(str string) string {
+	runes := []rune(str)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+type reverseFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *reverseFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argTp := args[0].GetType()
+	bf.tp.Flen = argTp.Flen
+	SetBinFlagOrBinStr(argTp, bf.tp)
+	if types.IsBinaryStr(argTp) {
+		sig := &builtinReverseSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Reverse)
+		return sig, nil
+	}
+	sig := &builtinReverseUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_ReverseUTF8)
+	return sig, nil
+}
+
+type builtinReverseSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReverseSig) Clone() builtinFunc {
+	newSig := &builtinReverseSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a REVERSE(str).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_reverse
+func (b *builtinReverseSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return reverseBytes(str), false, nil
+}
+
+type builtinReverseUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReverseUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinReverseUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a REVERSE(str).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_reverse
+func (b *builtinReverseUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return reverseRunes(str), false, nil
+}
+
+type charLengthFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *charLengthFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString)
+	bf.tp.Flen = 10
+	if types.IsBinaryStr(args[0].GetType()) {
+		sig := &builtinCharLengthSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CharLength)
+		return sig, nil
+	}
+	sig := &builtinCharLengthUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_CharLengthUTF8)
+	return sig, nil
+}
+
+type builtinCharLengthSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCharLengthSig) Clone() builtinFunc {
+	newSig := &builtinCharLengthSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinCharLengthSig, a multibyte character counts as multiple characters in a binary string.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_char-length
+func (b *builtinCharLengthSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return int64(len(val)), false, nil
+}
+
+type builtinCharLengthUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCharLengthUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinCharLengthUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinCharLengthUTF8Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_char-length
+func (b *builtinCharLengthUTF8Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return int64(utf8.RuneCountInString(val)), false, nil
+}
+
+type convertFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *convertFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	// The parser makes sure the character set is a constant of a supported one.
+	charsetArg, ok := args[1].(*Constant)
+	if !ok {
+		return nil, errIncorrectArgs.GenWithStackByArgs(c.funcName)
+	}
+	cs := charsetArg.Value.GetString()
+	collate, err := charset.GetDefaultCollation(cs)
+	if err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString)
+	bf.tp.Flen = args[0].GetType().Flen
+	bf.tp.Charset, bf.tp.Collate = cs, collate
+	if cs == charset.CharsetBin {
+		bf.tp.Flag |= mysql.BinaryFlag
+	}
+	sig := &builtinConvertSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Convert)
+	return sig, nil
+}
+
+type builtinConvertSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinConvertSig) Clone() builtinFunc {
+	newSig := &builtinConvertSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals CONVERT(expr USING transcoding_name).
+// All the supported character sets are stored as UTF-8, so only the type of the value changes.
+// See https://dev.mysql.com/doc/refman/5.7/en/cast-functions.html#function_convert
+func (b *builtinConvertSig) evalString(row chunk.Row) (string, bool, error) {
+	return b.args[0].EvalString(b.ctx, row)
+}
+
+type hexFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *hexFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	argTp := args[0].GetType().EvalType()
+	switch argTp {
+	case types.ETString, types.ETDatetime, types.ETTimestamp, types.ETDuration:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+		// Use UTF8MB4 as default.
+		bf.tp.Flen = args[0].GetType().Flen * 4 * 2
+		sig := &builtinHexStrArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_HexStrArg)
+		return sig, nil
+	case types.ETInt, types.ETReal, types.ETDecimal:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETInt)
+		bf.tp.Flen = args[0].GetType().Flen * 2
+		sig := &builtinHexIntArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_HexIntArg)
+		return sig, nil
+	default:
+		return nil, errors.Errorf("Hex invalid args, need int or string but get %T", args[0].GetType())
+	}
+}
+
+type builtinHexStrArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinHexStrArgSig) Clone() builtinFunc {
+	newSig := &builtinHexStrArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinHexStrArgSig, corresponding to hex(str)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_hex
+func (b *builtinHexStrArgSig) evalString(row chunk.Row) (string, bool, error) {
+	d, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return d, isNull, err
+	}
+	return strings.ToUpper(hex.EncodeToString([]byte(d))), false, nil
+}
+
+type builtinHexIntArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinHexIntArgSig) Clone() builtinFunc {
+	newSig := &builtinHexIntArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinHexIntArgSig, corresponding to hex(N)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_hex
+func (b *builtinHexIntArgSig) evalString(row chunk.Row) (string, bool, error) {
+	x, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return strings.ToUpper(fmt.Sprintf("%x", uint64(x))), false, nil
+}
+
+type unhexFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *unhexFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	var retFlen int
+
+	argType := args[0].GetType()
+	argEvalTp := argType.EvalType()
+	switch argEvalTp {
+	case types.ETString, types.ETDatetime, types.ETTimestamp, types.ETDuration:
+		// Use UTF8MB4 as default charset, so there're (Flen * 4 + 1) / 2 byte-pairs
+		retFlen = (argType.Flen*4 + 1) / 2
+	case types.ETInt, types.ETReal, types.ETDecimal:
+		// For number value, there're (Flen + 1) / 2 byte-pairs
+		retFlen = (argType.Flen + 1) / 2
+	default:
+		return nil, errors.Errorf("Unhex invalid args, need int or string but get %s", argType)
+	}
+
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	bf.tp.Flen = retFlen
+	types.SetBinChsClnFlag(bf.tp)
+	sig := &builtinUnHexSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_UnHex)
+	return sig, nil
+}
+
+// unhex decodes the hexadecimal string str, the result is NULL if str contains any non-hexadecimal digits.
+func unhex(str string) (string, bool) {
+	// Add a '0' to the front, if the length is not the multiple of 2
+	if len(str)%2 != 0 {
+		str = "0" + str
+	}
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return "", true
+	}
+	return string(bs), false
+}
+
+type builtinUnHexSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnHexSig) Clone() builtinFunc {
+	newSig := &builtinUnHexSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinUnHexSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_unhex
+func (b *builtinUnHexSig) evalString(row chunk.Row) (string, bool, error) {
+	d, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return d, isNull, err
+	}
+	res, isNull := unhex(d)
+	return res, isNull, nil
+}
+
+const formatMaxDecimals int64 = 30
+
+type formatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *formatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 2, 3)
+	argTps[1] = types.ETInt
+	argTp := args[0].GetType().EvalType()
+	if argTp == types.ETDecimal || argTp == types.ETInt {
+		argTps[0] = types.ETDecimal
+	} else {
+		argTps[0] = types.ETReal
+		args[0] = WrapWithCastAsReal(ctx, args[0])
+	}
+	if len(args) == 3 {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	charset, collate := ctx.GetSessionVars().GetCharsetInfo()
+	bf.tp.Charset, bf.tp.Collate = charset, collate
+	bf.tp.Flen = mysql.MaxBlobWidth
+	if len(args) == 3 {
+		sig := &builtinFormatWithLocaleSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FormatWithLocale)
+		return sig, nil
+	}
+	sig := &builtinFormatSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Format)
+	return sig, nil
+}
+
+// formatDecimal rounds the number string xStr to d decimal places and formats it like '#,###,###.##'.
+func formatDecimal(xStr string, d int64) (string, error) {
+	if d < 0 {
+		d = 0
+	} else if d > formatMaxDecimals {
+		d = formatMaxDecimals
+	}
+	xStr = roundFormatArgs(xStr, int(d))
+	return mysql.GetLocaleFormatFunction("en_US")(xStr, strconv.FormatInt(d, 10))
+}
+
+// roundFormatArgs rounds the number string xStr half away from zero to maxNumDecimals decimal places.
+func roundFormatArgs(xStr string, maxNumDecimals int) string {
+	if !strings.Contains(xStr, ".") {
+		return xStr
+	}
+
+	sign := false
+	// xStr cannot have '+' prefix now, it's built by the decimal or float formatting.
+	if strings.HasPrefix(xStr, "-") {
+		xStr = strings.Trim(xStr, "-")
+		sign = true
+	}
+
+	xArr := strings.Split(xStr, ".")
+	integerPart := xArr[0]
+	decimalPart := xArr[1]
+
+	if len(decimalPart) > maxNumDecimals {
+		t := []byte(decimalPart)
+		carry := false
+		if t[maxNumDecimals] >= '5' {
+			carry = true
+		}
+		for i := maxNumDecimals - 1; i >= 0 && carry; i-- {
+			if t[i] == '9' {
+				t[i] = '0'
+			} else {
+				t[i] = t[i] + 1
+				carry = false
+			}
+		}
+		decimalPart = string(t)
+		t = []byte(integerPart)
+		for i := len(integerPart) - 1; i >= 0 && carry; i-- {
+			if t[i] == '9' {
+				t[i] = '0'
+			} else {
+				t[i] = t[i] + 1
+				carry = false
+			}
+		}
+		if carry {
+			integerPart = "1" + string(t)
+		} else {
+			integerPart = string(t)
+		}
+	}
+
+	xStr = integerPart + "." + decimalPart
+	if sign {
+		xStr = "-" + xStr
+	}
+	return xStr
+}
+
+// evalNumDecArgsForFormat evaluates the number X and the decimal places D of FORMAT(X,D).
+func evalNumDecArgsForFormat(b *baseBuiltinFunc, row chunk.Row) (string, int64, bool, error) {
+	var xStr string
+	if b.args[0].GetType().EvalType() == types.ETDecimal {
+		x, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+		if isNull || err != nil {
+			return "", 0, isNull, err
+		}
+		xStr = x.String()
+	} else {
+		x, isNull, err := b.args[0].EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return "", 0, isNull, err
+		}
+		xStr = strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	d, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", 0, isNull, err
+	}
+	return xStr, d, false, nil
+}
+
+type builtinFormatSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFormatSig) Clone() builtinFunc {
+	newSig := &builtinFormatSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals FORMAT(X,D).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_format
+func (b *builtinFormatSig) evalString(row chunk.Row) (string, bool, error) {
+	x, d, isNull, err := evalNumDecArgsForFormat(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	formatString, err := formatDecimal(x, d)
+	return formatString, false, err
+}
+
+// checkFormatLocale checks the locale of FORMAT, only en_US is supported and other locales are treated as en_US.
+func checkFormatLocale(ctx sessionctx.Context, locale string, isNull bool) {
+	if isNull {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errUnknownLocale.GenWithStackByArgs("NULL"))
+	} else if locale != "en_US" {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errUnknownLocale.GenWithStackByArgs(locale))
+	}
+}
+
+type builtinFormatWithLocaleSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFormatWithLocaleSig) Clone() builtinFunc {
+	newSig := &builtinFormatWithLocaleSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals FORMAT(X,D,locale).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_format
+func (b *builtinFormatWithLocaleSig) evalString(row chunk.Row) (string, bool, error) {
+	x, d, isNull, err := evalNumDecArgsForFormat(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	locale, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if err != nil {
+		return "", false, err
+	}
+	checkFormatLocale(b.ctx, locale, isNull)
+	formatString, err := formatDecimal(x, d)
+	return formatString, false, err
+}
+
+type fieldFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *fieldFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	isAllString, isAllNumber := true, true
+	for i, length := 0, len(args); i < length; i++ {
+		argTp := args[i].GetType().EvalType()
+		isAllString = isAllString && (argTp == types.ETString)
+		isAllNumber = isAllNumber && (argTp == types.ETInt)
+	}
+
+	argTps := make([]types.EvalType, len(args))
+	argTp := types.ETReal
+	if isAllString {
+		argTp = types.ETString
+	} else if isAllNumber {
+		argTp = types.ETInt
+	}
+	for i, length := 0, len(args); i < length; i++ {
+		argTps[i] = argTp
+		if argTp == types.ETReal {
+			args[i] = WrapWithCastAsReal(ctx, args[i])
+		}
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 3
+	var sig builtinFunc
+	switch argTp {
+	case types.ETReal:
+		sig = &builtinFieldRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FieldReal)
+	case types.ETInt:
+		sig = &builtinFieldIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FieldInt)
+	case types.ETString:
+		sig = &builtinFieldStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FieldString)
+	}
+	return sig, nil
+}
+
+type builtinFieldIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFieldIntSig) Clone() builtinFunc {
+	newSig := &builtinFieldIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals FIELD(str,str1,str2,str3,...).
+// Returns the index (position) of str in the str1, str2, str3, ... list. Returns 0 if str is not found.
+// If all arguments to FIELD() are integers, all arguments are compared as integers.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_field
+func (b *builtinFieldIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, err != nil, err
+	}
+	for i, length := 1, len(b.args); i < length; i++ {
+		stri, isNull, err := b.args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if !isNull && str == stri {
+			return int64(i), false, nil
+		}
+	}
+	return 0, false, nil
+}
+
+type builtinFieldRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFieldRealSig) Clone() builtinFunc {
+	newSig := &builtinFieldRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals FIELD(str,str1,str2,str3,...).
+// Returns the index (position) of str in the str1, str2, str3, ... list. Returns 0 if str is not found.
+// If all arguments to FIELD() are not all strings or integers, they are compared as double.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_field
+func (b *builtinFieldRealSig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, err != nil, err
+	}
+	for i, length := 1, len(b.args); i < length; i++ {
+		stri, isNull, err := b.args[i].EvalReal(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if !isNull && str == stri {
+			return int64(i), false, nil
+		}
+	}
+	return 0, false, nil
+}
+
+type builtinFieldStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFieldStringSig) Clone() builtinFunc {
+	newSig := &builtinFieldStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals FIELD(str,str1,str2,str3,...).
+// Returns the index (position) of str in the str1, str2, str3, ... list. Returns 0 if str is not found.
+// If all arguments to FIELD() are strings, all arguments are compared as strings.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_field
+func (b *builtinFieldStringSig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, err != nil, err
+	}
+	for i, length := 1, len(b.args); i < length; i++ {
+		stri, isNull, err := b.args[i].EvalString(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if !isNull && str == stri {
+			return int64(i), false, nil
+		}
+	}
+	return 0, false, nil
+}
+
+type eltFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *eltFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if argsErr := c.verifyArgs(args); argsErr != nil {
+		return nil, argsErr
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETInt)
+	for i := 1; i < len(args); i++ {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	for _, arg := range args[1:] {
+		argType := arg.GetType()
+		if types.IsBinaryStr(argType) {
+			types.SetBinChsClnFlag(bf.tp)
+		}
+		if argType.Flen > bf.tp.Flen {
+			bf.tp.Flen = argType.Flen
+		}
+	}
+	sig := &builtinEltSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Elt)
+	return sig, nil
+}
+
+type builtinEltSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinEltSig) Clone() builtinFunc {
+	newSig := &builtinEltSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a ELT(N,str1,str2,str3,...).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_elt
+func (b *builtinEltSig) evalString(row chunk.Row) (string, bool, error) {
+	idx, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if idx < 1 || idx >= int64(len(b.args)) {
+		return "", true, nil
+	}
+	arg, isNull, err := b.args[idx].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return arg, false, nil
+}
@@ -14,11 +14,16 @@
 package expression
 
 import (
+	"strings"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
 )
 
 func (s *testEvaluatorSuite) TestLengthAndOctetLength(c *C) {
@@ -98,3 +103,359 @@ func (s *testEvaluatorSuite) TestStrcmp(c *C) {
 		}
 	}
 }
+
+type stringFuncCase struct {
+	args []interface{}
+	res  interface{}
+}
+
+// checkStringFuncCases evaluates funcName on the constant args of each case, and compares with the expected result.
+func (s *testEvaluatorSuite) checkStringFuncCases(c *C, funcName string, cases []stringFuncCase) {
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, funcName, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.res), Commentf("%s%v", funcName, t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestConcat(c *C) {
+	s.checkStringFuncCases(c, ast.Concat, []stringFuncCase{
+		{[]interface{}{"abc"}, "abc"},
+		{[]interface{}{"a", "b", "c"}, "abc"},
+		{[]interface{}{"a", 1, 1.1}, "a11.1"},
+		{[]interface{}{"a", nil, "c"}, nil},
+		{[]interface{}{"你", "好"}, "你好"},
+	})
+	s.checkStringFuncCases(c, ast.ConcatWS, []stringFuncCase{
+		{[]interface{}{",", "a", "b"}, "a,b"},
+		{[]interface{}{",", "a", nil, "b", ""}, "a,b,"},
+		{[]interface{}{",", nil, nil}, ""},
+		{[]interface{}{nil, "a", "b"}, nil},
+		{[]interface{}{"--", 1, 2.5}, "1--2.5"},
+	})
+}
+
+func (s *testEvaluatorSuite) TestConcatMaxAllowedPacket(c *C) {
+	ctx := s.ctx
+	sc := ctx.GetSessionVars().StmtCtx
+	c.Assert(ctx.GetSessionVars().SetSystemVar("max_allowed_packet", "1024"), IsNil)
+	defer func() {
+		c.Assert(ctx.GetSessionVars().SetSystemVar("max_allowed_packet", "67108864"), IsNil)
+	}()
+	for _, funcName := range []string{ast.Concat, ast.Repeat, ast.Lpad} {
+		var args []interface{}
+		switch funcName {
+		case ast.Concat:
+			args = []interface{}{strings.Repeat("a", 1000), strings.Repeat("b", 100)}
+		case ast.Repeat:
+			args = []interface{}{"ab", 1000}
+		case ast.Lpad:
+			args = []interface{}{"a", 2000, "b"}
+		}
+		warnCnt := sc.WarningCount()
+		f, err := newFunctionForTest(ctx, funcName, s.primitiveValsToConstants(args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.IsNull(), IsTrue, Commentf("%s", funcName))
+		warnings := sc.GetWarnings()
+		c.Assert(len(warnings), Equals, int(warnCnt)+1)
+		c.Assert(terror.ErrorEqual(warnings[warnCnt].Err, errWarnAllowedPacketOverflowed), IsTrue)
+	}
+}
+
+func (s *testEvaluatorSuite) TestSubstring(c *C) {
+	cases := []stringFuncCase{
+		{[]interface{}{"Quadratically", 5}, "ratically"},
+		{[]interface{}{"Sakila", -3}, "ila"},
+		{[]interface{}{"Sakila", 0}, ""},
+		{[]interface{}{"Sakila", 7}, ""},
+		{[]interface{}{"Sakila", -7}, ""},
+		{[]interface{}{"Quadratically", 5, 6}, "ratica"},
+		{[]interface{}{"Sakila", -5, 3}, "aki"},
+		{[]interface{}{"Sakila", 2, 0}, ""},
+		{[]interface{}{"Sakila", 2, -1}, ""},
+		{[]interface{}{"Sakila", 2, 100}, "akila"},
+		{[]interface{}{"你好世界", 2, 2}, "好世"},
+		{[]interface{}{[]byte("你好"), 4}, []byte("好")},
+		{[]interface{}{nil, 1}, nil},
+		{[]interface{}{"Sakila", nil}, nil},
+		{[]interface{}{"Sakila", 1, nil}, nil},
+	}
+	s.checkStringFuncCases(c, ast.Substring, cases)
+	s.checkStringFuncCases(c, ast.Substr, cases)
+}
+
+func (s *testEvaluatorSuite) TestLeftRight(c *C) {
+	s.checkStringFuncCases(c, ast.Left, []stringFuncCase{
+		{[]interface{}{"abcdefg", 3}, "abc"},
+		{[]interface{}{"abcdefg", 10}, "abcdefg"},
+		{[]interface{}{"abcdefg", -1}, ""},
+		{[]interface{}{"你好世界", 2}, "你好"},
+		{[]interface{}{[]byte("你好"), 3}, []byte("你")},
+		{[]interface{}{nil, 1}, nil},
+		{[]interface{}{"abc", nil}, nil},
+	})
+	s.checkStringFuncCases(c, ast.Right, []stringFuncCase{
+		{[]interface{}{"abcdefg", 3}, "efg"},
+		{[]interface{}{"abcdefg", 10}, "abcdefg"},
+		{[]interface{}{"abcdefg", -1}, ""},
+		{[]interface{}{"你好世界", 2}, "世界"},
+		{[]interface{}{[]byte("你好"), 3}, []byte("好")},
+		{[]interface{}{nil, 1}, nil},
+	})
+}
+
+func (s *testEvaluatorSuite) TestUpperLower(c *C) {
+	for _, funcName := range []string{ast.Upper, ast.Ucase} {
+		s.checkStringFuncCases(c, funcName, []stringFuncCase{
+			{[]interface{}{"abc"}, "ABC"},
+			{[]interface{}{"aBc1é"}, "ABC1É"},
+			{[]interface{}{[]byte("abc")}, []byte("abc")},
+			{[]interface{}{1}, "1"},
+			{[]interface{}{nil}, nil},
+		})
+	}
+	for _, funcName := range []string{ast.Lower, ast.Lcase} {
+		s.checkStringFuncCases(c, funcName, []stringFuncCase{
+			{[]interface{}{"ABC"}, "abc"},
+			{[]interface{}{"aBc1É"}, "abc1é"},
+			{[]interface{}{[]byte("ABC")}, []byte("ABC")},
+			{[]interface{}{nil}, nil},
+		})
+	}
+}
+
+func (s *testEvaluatorSuite) TestTrim(c *C) {
+	s.checkStringFuncCases(c, ast.Trim, []stringFuncCase{
+		{[]interface{}{"  bar   "}, "bar"},
+		{[]interface{}{"\t bar \n"}, "\t bar \n"},
+		{[]interface{}{"xxxbarxxx", "x"}, "bar"},
+		{[]interface{}{"xyxybarxyx", "xy"}, "barxyx"},
+		{[]interface{}{"barxxyz", "xyz", int(ast.TrimTrailing)}, "barx"},
+		{[]interface{}{"bar", ""}, "bar"},
+		{[]interface{}{"xxxbarxxx", "x", int(ast.TrimLeading)}, "barxxx"},
+		{[]interface{}{"xxxbarxxx", "x", int(ast.TrimTrailing)}, "xxxbar"},
+		{[]interface{}{"xxxbarxxx", "x", int(ast.TrimBoth)}, "bar"},
+		{[]interface{}{"  bar  ", nil, int(ast.TrimLeading)}, "bar  "},
+		{[]interface{}{"  bar  ", nil, int(ast.TrimBothDefault)}, "bar"},
+		{[]interface{}{nil}, nil},
+		{[]interface{}{"bar", nil}, nil},
+	})
+	s.checkStringFuncCases(c, ast.LTrim, []stringFuncCase{
+		{[]interface{}{"  bar  "}, "bar  "},
+		{[]interface{}{"bar"}, "bar"},
+		{[]interface{}{nil}, nil},
+	})
+	s.checkStringFuncCases(c, ast.RTrim, []stringFuncCase{
+		{[]interface{}{"  bar  "}, "  bar"},
+		{[]interface{}{"bar"}, "bar"},
+		{[]interface{}{nil}, nil},
+	})
+}
+
+func (s *testEvaluatorSuite) TestReplace(c *C) {
+	s.checkStringFuncCases(c, ast.Replace, []stringFuncCase{
+		{[]interface{}{"www.mysql.com", "mysql", "pingcap"}, "www.pingcap.com"},
+		{[]interface{}{"www.mysql.com", "w", "Ww"}, "WwWwWw.mysql.com"},
+		{[]interface{}{"www.mysql.com", "", "x"}, "www.mysql.com"},
+		{[]interface{}{"www.mysql.com", "W", "x"}, "www.mysql.com"},
+		{[]interface{}{1234, 2, 55}, "15534"},
+		{[]interface{}{nil, "a", "b"}, nil},
+		{[]interface{}{"a", nil, "b"}, nil},
+		{[]interface{}{"a", "a", nil}, nil},
+	})
+}
+
+func (s *testEvaluatorSuite) TestLocateAndInstr(c *C) {
+	s.checkStringFuncCases(c, ast.Locate, []stringFuncCase{
+		{[]interface{}{"bar", "foobarbar"}, 4},
+		{[]interface{}{"xbar", "foobar"}, 0},
+		{[]interface{}{"bar", "foobarbar", 5}, 7},
+		{[]interface{}{"", "foobar"}, 1},
+		{[]interface{}{"", "foobar", 7}, 7},
+		{[]interface{}{"", "foobar", 8}, 0},
+		{[]interface{}{"bar", "foobarbar", 0}, 0},
+		{[]interface{}{"bar", "foobarbar", -1}, 0},
+		{[]interface{}{"BAR", "foobar"}, 0},
+		{[]interface{}{"世", "你好世界"}, 3},
+		{[]interface{}{"界", "你好世界", 3}, 4},
+		{[]interface{}{[]byte("世"), "你好世界"}, 7},
+		{[]interface{}{nil, "foobar"}, nil},
+		{[]interface{}{"bar", nil}, nil},
+		{[]interface{}{"bar", "foobar", nil}, nil},
+	})
+	s.checkStringFuncCases(c, ast.Position, []stringFuncCase{
+		{[]interface{}{"bar", "foobarbar"}, 4},
+		{[]interface{}{"世", "你好世界"}, 3},
+	})
+	s.checkStringFuncCases(c, ast.Instr, []stringFuncCase{
+		{[]interface{}{"foobarbar", "bar"}, 4},
+		{[]interface{}{"xbar", "foobar"}, 0},
+		{[]interface{}{"foobar", ""}, 1},
+		{[]interface{}{"你好世界", "世"}, 3},
+		{[]interface{}{[]byte("你好世界"), "世"}, 7},
+		{[]interface{}{nil, "bar"}, nil},
+		{[]interface{}{"foobar", nil}, nil},
+	})
+}
+
+func (s *testEvaluatorSuite) TestLpadAndRpad(c *C) {
+	s.checkStringFuncCases(c, ast.Lpad, []stringFuncCase{
+		{[]interface{}{"hi", 4, "??"}, "??hi"},
+		{[]interface{}{"hi", 5, "ab"}, "abahi"},
+		{[]interface{}{"hi", 1, "??"}, "h"},
+		{[]interface{}{"hi", 0, "??"}, ""},
+		{[]interface{}{"hi", -1, "??"}, nil},
+		{[]interface{}{"hi", 4, ""}, nil},
+		{[]interface{}{"hi", 2, ""}, "hi"},
+		{[]interface{}{"你好", 4, "世界"}, "世界你好"},
+		{[]interface{}{[]byte("你好"), 7, "x"}, []byte("x你好")},
+		{[]interface{}{nil, 4, "?"}, nil},
+		{[]interface{}{"hi", nil, "?"}, nil},
+		{[]interface{}{"hi", 4, nil}, nil},
+	})
+	s.checkStringFuncCases(c, ast.Rpad, []stringFuncCase{
+		{[]interface{}{"hi", 5, "?"}, "hi???"},
+		{[]interface{}{"hi", 5, "ab"}, "hiaba"},
+		{[]interface{}{"hi", 1, "?"}, "h"},
+		{[]interface{}{"hi", -1, "?"}, nil},
+		{[]interface{}{"hi", 4, ""}, nil},
+		{[]interface{}{"你好", 3, "世界"}, "你好世"},
+		{[]interface{}{nil, 4, "?"}, nil},
+	})
+}
+
+func (s *testEvaluatorSuite) TestRepeatAndReverse(c *C) {
+	s.checkStringFuncCases(c, ast.Repeat, []stringFuncCase{
+		{[]interface{}{"ab", 3}, "ababab"},
+		{[]interface{}{"ab", 0}, ""},
+		{[]interface{}{"ab", -1}, ""},
+		{[]interface{}{"", 3}, ""},
+		{[]interface{}{1, 3}, "111"},
+		{[]interface{}{nil, 3}, nil},
+		{[]interface{}{"ab", nil}, nil},
+	})
+	s.checkStringFuncCases(c, ast.Reverse, []stringFuncCase{
+		{[]interface{}{"abc"}, "cba"},
+		{[]interface{}{"你好"}, "好你"},
+		{[]interface{}{""}, ""},
+		{[]interface{}{123}, "321"},
+		{[]interface{}{[]byte("abc")}, []byte("cba")},
+		{[]interface{}{nil}, nil},
+	})
+}
+
+func (s *testEvaluatorSuite) TestCharLength(c *C) {
+	for _, funcName := range []string{ast.CharLength, ast.CharacterLength} {
+		s.checkStringFuncCases(c, funcName, []stringFuncCase{
+			{[]interface{}{"abc"}, 3},
+			{[]interface{}{"你好"}, 2},
+			{[]interface{}{[]byte("你好")}, 6},
+			{[]interface{}{""}, 0},
+			{[]interface{}{12.5}, 4},
+			{[]interface{}{nil}, nil},
+		})
+	}
+}
+
+func (s *testEvaluatorSuite) TestHexAndUnhex(c *C) {
+	s.checkStringFuncCases(c, ast.Hex, []stringFuncCase{
+		{[]interface{}{"abc"}, "616263"},
+		{[]interface{}{"你好"}, "E4BDA0E5A5BD"},
+		{[]interface{}{255}, "FF"},
+		{[]interface{}{-1}, "FFFFFFFFFFFFFFFF"},
+		{[]interface{}{12.5}, "D"},
+		{[]interface{}{""}, ""},
+		{[]interface{}{nil}, nil},
+	})
+	s.checkStringFuncCases(c, ast.Unhex, []stringFuncCase{
+		{[]interface{}{"4D7953514C"}, []byte("MySQL")},
+		{[]interface{}{"e4bda0"}, []byte("你")},
+		{[]interface{}{"a"}, []byte("\n")},
+		{[]interface{}{"GG"}, nil},
+		{[]interface{}{""}, []byte("")},
+		{[]interface{}{1267}, []byte{0x12, 0x67}},
+		{[]interface{}{nil}, nil},
+	})
+}
+
+func (s *testEvaluatorSuite) TestConvert(c *C) {
+	s.checkStringFuncCases(c, ast.Convert, []stringFuncCase{
+		{[]interface{}{"abc", "utf8mb4"}, "abc"},
+		{[]interface{}{"你好", "binary"}, "你好"},
+		{[]interface{}{12, "latin1"}, "12"},
+		{[]interface{}{nil, "utf8"}, nil},
+	})
+
+	tbl := []struct {
+		cs      string
+		collate string
+		binary  bool
+	}{
+		{"utf8mb4", "utf8mb4_bin", false},
+		{"latin1", "latin1_bin", false},
+		{"binary", "binary", true},
+	}
+	for _, t := range tbl {
+		f, err := funcs[ast.Convert].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{"abc", t.cs}))
+		c.Assert(err, IsNil)
+		tp := f.getRetTp()
+		c.Assert(tp.Charset, Equals, t.cs)
+		c.Assert(tp.Collate, Equals, t.collate)
+		c.Assert(mysql.HasBinaryFlag(tp.Flag), Equals, t.binary)
+	}
+}
+
+func (s *testEvaluatorSuite) TestFormat(c *C) {
+	s.checkStringFuncCases(c, ast.Format, []stringFuncCase{
+		{[]interface{}{12332.123456, 4}, "12,332.1235"},
+		{[]interface{}{12332.1, 4}, "12,332.1000"},
+		{[]interface{}{12332.2, 0}, "12,332"},
+		{[]interface{}{-12332.2, 0}, "-12,332"},
+		{[]interface{}{999.9999, 2}, "1,000.00"},
+		{[]interface{}{1234567, 2}, "1,234,567.00"},
+		{[]interface{}{types.NewDecFromStringForTest("-1234.5678"), 3}, "-1,234.568"},
+		{[]interface{}{0.5, -1}, "1"},
+		{[]interface{}{"12332.123456", 2}, "12,332.12"},
+		{[]interface{}{nil, 2}, nil},
+		{[]interface{}{1.5, nil}, nil},
+	})
+
+	sc := s.ctx.GetSessionVars().StmtCtx
+	warnCnt := sc.WarningCount()
+	s.checkStringFuncCases(c, ast.Format, []stringFuncCase{
+		{[]interface{}{12332.123456, 2, "en_US"}, "12,332.12"},
+		{[]interface{}{12332.123456, 2, "de_DE"}, "12,332.12"},
+		{[]interface{}{12332.123456, 2, nil}, "12,332.12"},
+	})
+	warnings := sc.GetWarnings()
+	c.Assert(len(warnings), Equals, int(warnCnt)+2)
+	for _, warn := range warnings[warnCnt:] {
+		c.Assert(terror.ErrorEqual(warn.Err, errUnknownLocale), IsTrue)
+	}
+}
+
+func (s *testEvaluatorSuite) TestFieldAndElt(c *C) {
+	s.checkStringFuncCases(c, ast.Field, []stringFuncCase{
+		{[]interface{}{"ej", "Hej", "ej", "Heja", "hej", "foo"}, 2},
+		{[]interface{}{"fo", "Hej", "ej", "Heja", "hej", "foo"}, 0},
+		{[]interface{}{"Ej", "ej"}, 0},
+		{[]interface{}{1, 2, 1, 1}, 2},
+		{[]interface{}{1, "1.0", 2}, 1},
+		{[]interface{}{1.5, 1, 1.5}, 2},
+		{[]interface{}{nil, nil, "a"}, 0},
+		{[]interface{}{"a", nil, "a"}, 2},
+	})
+	s.checkStringFuncCases(c, ast.Elt, []stringFuncCase{
+		{[]interface{}{1, "Aa", "Bb", "Cc"}, "Aa"},
+		{[]interface{}{3, "Aa", "Bb", "Cc"}, "Cc"},
+		{[]interface{}{0, "Aa", "Bb", "Cc"}, nil},
+		{[]interface{}{4, "Aa", "Bb", "Cc"}, nil},
+		{[]interface{}{2, "Aa", nil, "Cc"}, nil},
+		{[]interface{}{2, 1, 2.5}, "2.5"},
+		{[]interface{}{nil, "Aa"}, nil},
+	})
+}
@@ -14,10 +14,26 @@
 package expression
 
 import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
+// getArgEvalTps gets the eval types of args, which have been wrapped by cast functions if needed.
+func getArgEvalTps(args []Expression) []types.EvalType {
+	argTps := make([]types.EvalType, len(args))
+	for i, arg := range args {
+		argTps[i] = arg.GetType().EvalType()
+	}
+	return argTps
+}
+
 func (b *builtinStringIsNullSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	buf, err := b.bufAllocator.get(types.ETString, n)
@@ -90,3 +106,757 @@ func (b *builtinLengthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column)
 	/* Your code here */
 	return nil
 }
+
+func (b *builtinConcatSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a CONCAT(str1,str2,...)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat
+func (b *builtinConcatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(&b.baseBuiltinFunc, input, getArgEvalTps(b.args))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(&b.baseBuiltinFunc, bufs)
+
+	var s []byte
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if hasNullArg(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		s = s[:0]
+		isOverflowed := false
+		for _, buf := range bufs {
+			str := buf.GetString(i)
+			if uint64(len(s)+len(str)) > b.maxAllowedPacket {
+				isOverflowed = true
+				break
+			}
+			s = append(s, str...)
+		}
+		if isOverflowed {
+			if err := handleAllowedPacketOverflowed(b.ctx, "concat", b.maxAllowedPacket); err != nil {
+				return err
+			}
+			result.AppendNull()
+			continue
+		}
+		result.AppendBytes(s)
+	}
+	return nil
+}
+
+func (b *builtinConcatWSSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a CONCAT_WS(separator,str1,str2,...).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat-ws
+func (b *builtinConcatWSSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(&b.baseBuiltinFunc, input, getArgEvalTps(b.args))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(&b.baseBuiltinFunc, bufs)
+
+	var s []byte
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		// If the separator is NULL, the result is NULL.
+		if bufs[0].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		sep := bufs[0].GetString(i)
+		s = s[:0]
+		first, isOverflowed := true, false
+		for _, buf := range bufs[1:] {
+			if buf.IsNull(i) {
+				continue
+			}
+			if !first {
+				s = append(s, sep...)
+			}
+			first = false
+			str := buf.GetString(i)
+			if uint64(len(s)+len(str)) > b.maxAllowedPacket {
+				isOverflowed = true
+				break
+			}
+			s = append(s, str...)
+		}
+		if isOverflowed {
+			if err := handleAllowedPacketOverflowed(b.ctx, "concat_ws", b.maxAllowedPacket); err != nil {
+				return err
+			}
+			result.AppendNull()
+			continue
+		}
+		result.AppendBytes(s)
+	}
+	return nil
+}
+
+// vecEvalSubstring evaluates SUBSTRING in a vectorized manner, substr is either substringBinary or substringUTF8.
+func vecEvalSubstring(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, substr func(string, int64, int64) string) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(b, input, getArgEvalTps(b.args))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(b, bufs)
+
+	result.ReserveString(n)
+	poses := bufs[1].Int64s()
+	for i := 0; i < n; i++ {
+		if hasNullArg(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		length := int64(-1)
+		if len(bufs) == 3 {
+			// SUBSTRING(str, pos, len) returns an empty string if len is less than 1.
+			if length = bufs[2].GetInt64(i); length < 0 {
+				length = 0
+			}
+		}
+		result.AppendString(substr(bufs[0].GetString(i), poses[i], length))
+	}
+	return nil
+}
+
+func (b *builtinSubstring2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring2ArgsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalSubstring(&b.baseBuiltinFunc, input, result, substringBinary)
+}
+
+func (b *builtinSubstring3ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring3ArgsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalSubstring(&b.baseBuiltinFunc, input, result, substringBinary)
+}
+
+func (b *builtinSubstring2ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring2ArgsUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalSubstring(&b.baseBuiltinFunc, input, result, substringUTF8)
+}
+
+func (b *builtinSubstring3ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring3ArgsUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalSubstring(&b.baseBuiltinFunc, input, result, substringUTF8)
+}
+
+// vecEvalStringIntFunc evaluates the functions like LEFT(str,len) with f in a vectorized manner.
+func vecEvalStringIntFunc(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, f func(string, int64) string) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(b, input, []types.EvalType{types.ETString, types.ETInt})
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(b, bufs)
+
+	result.ReserveString(n)
+	nums := bufs[1].Int64s()
+	for i := 0; i < n; i++ {
+		if hasNullArg(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(f(bufs[0].GetString(i), nums[i]))
+	}
+	return nil
+}
+
+func (b *builtinLeftSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeftSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringIntFunc(&b.baseBuiltinFunc, input, result, leftBinary)
+}
+
+func (b *builtinLeftUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeftUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringIntFunc(&b.baseBuiltinFunc, input, result, leftUTF8)
+}
+
+func (b *builtinRightSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRightSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringIntFunc(&b.baseBuiltinFunc, input, result, rightBinary)
+}
+
+func (b *builtinRightUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRightUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringIntFunc(&b.baseBuiltinFunc, input, result, rightUTF8)
+}
+
+// vecEvalStringFunc evaluates the functions like UPPER(str) with f in a vectorized manner.
+func vecEvalStringFunc(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, f func(string) string) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(f(buf.GetString(i)))
+	}
+	return nil
+}
+
+func (b *builtinUpperSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUpperSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalString(b.ctx, input, result)
+}
+
+func (b *builtinUpperUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUpperUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringFunc(&b.baseBuiltinFunc, input, result, strings.ToUpper)
+}
+
+func (b *builtinLowerSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLowerSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	if types.IsBinaryStr(b.args[0].GetType()) {
+		return b.args[0].VecEvalString(b.ctx, input, result)
+	}
+	return vecEvalStringFunc(&b.baseBuiltinFunc, input, result, strings.ToLower)
+}
+
+func (b *builtinTrim1ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTrim1ArgSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringFunc(&b.baseBuiltinFunc, input, result, func(str string) string {
+		return strings.Trim(str, " ")
+	})
+}
+
+func (b *builtinTrim2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTrim2ArgsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(&b.baseBuiltinFunc, input, getArgEvalTps(b.args))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(&b.baseBuiltinFunc, bufs)
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if hasNullArg(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(trimByDirection(bufs[0].GetString(i), bufs[1].GetString(i), ast.TrimBoth))
+	}
+	return nil
+}
+
+func (b *builtinTrim3ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTrim3ArgsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(&b.baseBuiltinFunc, input, getArgEvalTps(b.args))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(&b.baseBuiltinFunc, bufs)
+
+	result.ReserveString(n)
+	directions := bufs[2].Int64s()
+	for i := 0; i < n; i++ {
+		if bufs[0].IsNull(i) || bufs[2].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		// The remstr is omitted in TRIM(direction FROM str), which trims spaces.
+		remstr := " "
+		if !bufs[1].IsNull(i) {
+			remstr = bufs[1].GetString(i)
+		}
+		result.AppendString(trimByDirection(bufs[0].GetString(i), remstr, ast.TrimDirectionType(directions[i])))
+	}
+	return nil
+}
+
+func (b *builtinLTrimSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLTrimSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringFunc(&b.baseBuiltinFunc, input, result, func(str string) string {
+		return strings.TrimLeft(str, " ")
+	})
+}
+
+func (b *builtinRTrimSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRTrimSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringFunc(&b.baseBuiltinFunc, input, result, func(str string) string {
+		return strings.TrimRight(str, " ")
+	})
+}
+
+func (b *builtinReplaceSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinReplaceSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(&b.baseBuiltinFunc, input, getArgEvalTps(b.args))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(&b.baseBuiltinFunc, bufs)
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if hasNullArg(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		str, oldStr := bufs[0].GetString(i), bufs[1].GetString(i)
+		if oldStr == "" {
+			result.AppendString(str)
+			continue
+		}
+		result.AppendString(strings.Replace(str, oldStr, bufs[2].GetString(i), -1))
+	}
+	return nil
+}
+
+// vecEvalLocate evaluates LOCATE and INSTR in a vectorized manner, subStrIdx and strIdx are the indexes of
+// the arguments, and locate is either locateBinary or locateUTF8.
+func vecEvalLocate(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, subStrIdx, strIdx int, locate func(string, string, int64) int64) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(b, input, getArgEvalTps(b.args))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(b, bufs)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufs...)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		pos := int64(1)
+		if len(bufs) == 3 {
+			pos = bufs[2].GetInt64(i)
+		}
+		i64s[i] = locate(bufs[subStrIdx].GetString(i), bufs[strIdx].GetString(i), pos)
+	}
+	return nil
+}
+
+func (b *builtinLocate2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate2ArgsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 0, 1, locateBinary)
+}
+
+func (b *builtinLocate3ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate3ArgsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 0, 1, locateBinary)
+}
+
+func (b *builtinLocate2ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate2ArgsUTF8Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 0, 1, locateUTF8)
+}
+
+func (b *builtinLocate3ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate3ArgsUTF8Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 0, 1, locateUTF8)
+}
+
+func (b *builtinInstrSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinInstrSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 1, 0, locateBinary)
+}
+
+func (b *builtinInstrUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinInstrUTF8Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLocate(&b.baseBuiltinFunc, input, result, 1, 0, locateUTF8)
+}
+
+// vecEvalPad evaluates LPAD and RPAD in a vectorized manner.
+func vecEvalPad(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, funcName string, maxAllowedPacket uint64, isLeft, isUTF8 bool) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(b, input, getArgEvalTps(b.args))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(b, bufs)
+
+	result.ReserveString(n)
+	lengths := bufs[1].Int64s()
+	for i := 0; i < n; i++ {
+		if hasNullArg(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull, err := pad(b, funcName, maxAllowedPacket, bufs[0].GetString(i), lengths[i], bufs[2].GetString(i), isLeft, isUTF8)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinLpadSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLpadSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, "lpad", b.maxAllowedPacket, true, false)
+}
+
+func (b *builtinLpadUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLpadUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, "lpad", b.maxAllowedPacket, true, true)
+}
+
+func (b *builtinRpadSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRpadSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, "rpad", b.maxAllowedPacket, false, false)
+}
+
+func (b *builtinRpadUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRpadUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, "rpad", b.maxAllowedPacket, false, true)
+}
+
+func (b *builtinRepeatSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRepeatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(&b.baseBuiltinFunc, input, getArgEvalTps(b.args))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(&b.baseBuiltinFunc, bufs)
+
+	result.ReserveString(n)
+	nums := bufs[1].Int64s()
+	for i := 0; i < n; i++ {
+		if hasNullArg(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull, err := b.repeat(bufs[0].GetString(i), nums[i])
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinReverseSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinReverseSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringFunc(&b.baseBuiltinFunc, input, result, reverseBytes)
+}
+
+func (b *builtinReverseUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinReverseUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringFunc(&b.baseBuiltinFunc, input, result, reverseRunes)
+}
+
+// vecEvalStringLength evaluates the length of the string argument by f in a vectorized manner.
+func vecEvalStringLength(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, f func(string) int) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = int64(f(buf.GetString(i)))
+	}
+	return nil
+}
+
+func (b *builtinCharLengthSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCharLengthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringLength(&b.baseBuiltinFunc, input, result, func(str string) int {
+		return len(str)
+	})
+}
+
+func (b *builtinCharLengthUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCharLengthUTF8Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringLength(&b.baseBuiltinFunc, input, result, utf8.RuneCountInString)
+}
+
+func (b *builtinHexStrArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinHexStrArgSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalStringFunc(&b.baseBuiltinFunc, input, result, func(str string) string {
+		return strings.ToUpper(hex.EncodeToString([]byte(str)))
+	})
+}
+
+func (b *builtinHexIntArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinHexIntArgSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	i64s := buf.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(strings.ToUpper(fmt.Sprintf("%x", uint64(i64s[i]))))
+	}
+	return nil
+}
+
+func (b *builtinUnHexSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnHexSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull := unhex(buf.GetString(i))
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+// vecEvalFormat evaluates FORMAT(X,D[,locale]) in a vectorized manner.
+func vecEvalFormat(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(b, input, getArgEvalTps(b.args))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(b, bufs)
+
+	result.ReserveString(n)
+	isDecimal := b.args[0].GetType().EvalType() == types.ETDecimal
+	ds := bufs[1].Int64s()
+	for i := 0; i < n; i++ {
+		if bufs[0].IsNull(i) || bufs[1].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		var xStr string
+		if isDecimal {
+			xStr = bufs[0].GetDecimal(i).String()
+		} else {
+			xStr = strconv.FormatFloat(bufs[0].GetFloat64(i), 'f', -1, 64)
+		}
+		if len(bufs) == 3 {
+			isNull := bufs[2].IsNull(i)
+			locale := ""
+			if !isNull {
+				locale = bufs[2].GetString(i)
+			}
+			checkFormatLocale(b.ctx, locale, isNull)
+		}
+		formatString, err := formatDecimal(xStr, ds[i])
+		if err != nil {
+			return err
+		}
+		result.AppendString(formatString)
+	}
+	return nil
+}
+
+func (b *builtinFormatSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFormatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalFormat(&b.baseBuiltinFunc, input, result)
+}
+
+func (b *builtinFormatWithLocaleSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFormatWithLocaleSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalFormat(&b.baseBuiltinFunc, input, result)
+}
+
+func (b *builtinEltSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinEltSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := vecEvalArgs(&b.baseBuiltinFunc, input, getArgEvalTps(b.args))
+	if err != nil {
+		return err
+	}
+	defer putVecArgs(&b.baseBuiltinFunc, bufs)
+
+	result.ReserveString(n)
+	idxes := bufs[0].Int64s()
+	for i := 0; i < n; i++ {
+		if bufs[0].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		idx := idxes[i]
+		if idx < 1 || idx >= int64(len(bufs)) || bufs[idx].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(bufs[idx].GetString(i))
+	}
+	return nil
+}
+
+func (b *builtinConvertSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinConvertSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalString(b.ctx, input, result)
+}
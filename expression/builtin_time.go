@@ -0,0 +1,816 @@
+// Copyright 2013 The ql Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSES/QL-LICENSE file.
+
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &nowFunctionClass{}
+	_ functionClass = &dateFormatFunctionClass{}
+	_ functionClass = &dateArithFunctionClass{}
+	_ functionClass = &dateDiffFunctionClass{}
+	_ functionClass = &extractFunctionClass{}
+	_ functionClass = &unixTimestampFunctionClass{}
+	_ functionClass = &fromUnixTimeFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinNowWithArgSig{}
+	_ builtinFunc = &builtinNowWithoutArgSig{}
+	_ builtinFunc = &builtinDateFormatSig{}
+	_ builtinFunc = &builtinDateArithDatetimeSig{}
+	_ builtinFunc = &builtinDateArithStringSig{}
+	_ builtinFunc = &builtinDateArithDurationSig{}
+	_ builtinFunc = &builtinDateDiffSig{}
+	_ builtinFunc = &builtinExtractDatetimeSig{}
+	_ builtinFunc = &builtinExtractDurationSig{}
+	_ builtinFunc = &builtinUnixTimestampCurrentSig{}
+	_ builtinFunc = &builtinUnixTimestampIntSig{}
+	_ builtinFunc = &builtinUnixTimestampDecSig{}
+	_ builtinFunc = &builtinFromUnixTime1ArgSig{}
+	_ builtinFunc = &builtinFromUnixTime2ArgSig{}
+)
+
+// handleInvalidTimeError reports error or warning depend on the context.
+func handleInvalidTimeError(ctx sessionctx.Context, err error) error {
+	if err == nil || !(types.ErrWrongValue.Equal(err) ||
+		types.ErrTruncatedWrongVal.Equal(err) || types.ErrInvalidWeekModeFormat.Equal(err) ||
+		types.ErrDatetimeFunctionOverflow.Equal(err) || types.ErrInvalidTimeFormat.Equal(err)) {
+		return err
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	err = sc.HandleTruncate(err)
+	if ctx.GetSessionVars().StrictSQLMode && (sc.InInsertStmt || sc.InUpdateStmt || sc.InDeleteStmt) {
+		return err
+	}
+	return nil
+}
+
+// getFspFromType returns the fractional seconds precision of values of the
+// given type, MaxFsp is returned when it can not be decided statically.
+func getFspFromType(ctx sessionctx.Context, expr Expression) int8 {
+	tp := expr.GetType()
+	switch tp.EvalType() {
+	case types.ETInt:
+		return types.MinFsp
+	case types.ETString:
+		if con, ok := expr.(*Constant); ok {
+			str, isNull, err := con.EvalString(ctx, chunk.Row{})
+			if err == nil && !isNull {
+				return types.GetFsp(str)
+			}
+		}
+		return types.MaxFsp
+	}
+	if tp.Decimal < 0 || tp.Decimal > int(types.MaxFsp) {
+		return types.MaxFsp
+	}
+	return int8(tp.Decimal)
+}
+
+// convertTimeToMysqlTime converts a go time in the session time zone into a
+// DATETIME value with the given fsp.
+func convertTimeToMysqlTime(t time.Time, fsp int8) types.Time {
+	t = t.Round(time.Duration(math.Pow10(9-int(fsp))) * time.Nanosecond)
+	return types.NewTime(types.FromGoTime(t), mysql.TypeDatetime, fsp)
+}
+
+type nowFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *nowFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, 1)
+	if len(args) == 1 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, argTps...)
+
+	var sig builtinFunc
+	if len(args) == 1 {
+		bf.tp.Flen, bf.tp.Decimal = mysql.MaxDatetimeWidthWithFsp, int(types.MaxFsp)
+		if con, ok := args[0].(*Constant); ok {
+			fsp, isNull, err := con.EvalInt(ctx, chunk.Row{})
+			if err == nil && !isNull && fsp >= int64(types.MinFsp) && fsp <= int64(types.MaxFsp) {
+				bf.tp.Flen, bf.tp.Decimal = mysql.MaxDatetimeWidthNoFsp, int(fsp)
+				if fsp > 0 {
+					bf.tp.Flen += 1 + int(fsp)
+				}
+			}
+		}
+		sig = &builtinNowWithArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_NowWithArg)
+	} else {
+		bf.tp.Flen, bf.tp.Decimal = mysql.MaxDatetimeWidthNoFsp, int(types.MinFsp)
+		sig = &builtinNowWithoutArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_NowWithoutArg)
+	}
+	return sig, nil
+}
+
+// evalNowWithFsp returns the start time of the current statement in the
+// session time zone. Calls in one statement always see the same value.
+func evalNowWithFsp(ctx sessionctx.Context, fsp int8) (types.Time, bool, error) {
+	nowTs := ctx.GetSessionVars().StmtCtx.GetNowTsCached().In(ctx.GetSessionVars().Location())
+	// In MySQL's implementation, now() will truncate the result instead of rounding it.
+	nowTs = nowTs.Truncate(time.Duration(math.Pow10(9-int(fsp))) * time.Nanosecond)
+	return types.NewTime(types.FromGoTime(nowTs), mysql.TypeDatetime, fsp), false, nil
+}
+
+type builtinNowWithoutArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNowWithoutArgSig) Clone() builtinFunc {
+	newSig := &builtinNowWithoutArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinNowWithoutArgSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_now
+func (b *builtinNowWithoutArgSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	return evalNowWithFsp(b.ctx, types.MinFsp)
+}
+
+type builtinNowWithArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNowWithArgSig) Clone() builtinFunc {
+	newSig := &builtinNowWithArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinNowWithArgSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_now
+func (b *builtinNowWithArgSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	fsp, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	if isNull {
+		fsp = 0
+	} else if fsp > int64(types.MaxFsp) {
+		return types.ZeroTime, true, types.ErrTooBigPrecision.GenWithStackByArgs(fsp, "now", types.MaxFsp)
+	} else if fsp < int64(types.MinFsp) {
+		return types.ZeroTime, true, errors.Errorf("Invalid negative %d specified, must in [0, 6].", fsp)
+	}
+	return evalNowWithFsp(b.ctx, int8(fsp))
+}
+
+type dateFormatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *dateFormatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETDatetime, types.ETString)
+	// worst case: formatMask=%r%r%r...%r, each %r takes 11 characters
+	bf.tp.Flen = (args[1].GetType().Flen + 1) / 2 * 11
+	sig := &builtinDateFormatSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_DateFormatSig)
+	return sig, nil
+}
+
+type builtinDateFormatSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDateFormatSig) Clone() builtinFunc {
+	newSig := &builtinDateFormatSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinDateFormatSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
+func (b *builtinDateFormatSig) evalString(row chunk.Row) (string, bool, error) {
+	t, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, handleInvalidTimeError(b.ctx, err)
+	}
+	formatMask, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	if t.InvalidZero() {
+		return "", true, handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, t.String()))
+	}
+	res, err := t.DateFormat(formatMask)
+	return res, isNull, err
+}
+
+type dateArithFunctionClass struct {
+	baseFunctionClass
+
+	// isSub indicates the interval is subtracted, it is true for DATE_SUB
+	// and SUBDATE.
+	isSub bool
+}
+
+func (c *dateArithFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	unit := ""
+	if con, ok := args[2].(*Constant); ok {
+		str, isNull, err := con.EvalString(ctx, chunk.Row{})
+		if err != nil {
+			return nil, err
+		}
+		if !isNull {
+			unit = strings.ToUpper(str)
+		}
+	}
+
+	var sig builtinFunc
+	switch args[0].GetType().EvalType() {
+	case types.ETDuration:
+		if types.IsClockUnit(unit) && !types.IsDateUnit(unit) {
+			bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDuration, types.ETDuration, types.ETString, types.ETString)
+			intervalFsp := types.MinFsp
+			if strings.HasSuffix(unit, "MICROSECOND") {
+				intervalFsp = types.MaxFsp
+			} else if unit == "SECOND" {
+				intervalFsp = getFspFromType(ctx, args[1])
+			}
+			fsp := getFspFromType(ctx, args[0])
+			if intervalFsp > fsp {
+				fsp = intervalFsp
+			}
+			bf.tp.Flen, bf.tp.Decimal = mysql.MaxDurationWidthWithFsp, int(fsp)
+			sig = &builtinDateArithDurationSig{bf, c.isSub}
+			break
+		}
+		fallthrough
+	case types.ETDatetime, types.ETTimestamp:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETDatetime, types.ETString, types.ETString)
+		bf.tp.Flen, bf.tp.Decimal = mysql.MaxDatetimeFullWidth, types.UnspecifiedLength
+		sig = &builtinDateArithDatetimeSig{bf, c.isSub}
+	default:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETString, types.ETString, types.ETString)
+		bf.tp.Flen, bf.tp.Decimal = mysql.MaxDatetimeFullWidth, types.UnspecifiedLength
+		sig = &builtinDateArithStringSig{bf, c.isSub}
+	}
+	return sig, nil
+}
+
+// evalInterval evaluates the interval and the unit arguments of a date
+// arithmetic function.
+func evalInterval(ctx sessionctx.Context, args []Expression, row chunk.Row) (interval string, unit string, isNull bool, err error) {
+	interval, isNull, err = args[1].EvalString(ctx, row)
+	if isNull || err != nil {
+		return "", "", true, err
+	}
+	unit, isNull, err = args[2].EvalString(ctx, row)
+	if isNull || err != nil {
+		return "", "", true, err
+	}
+	return interval, unit, false, nil
+}
+
+// addInterval adds the interval to the date, the interval is subtracted when
+// isSub is true. The fsp of the result is 6 if it has a fractional part.
+func addInterval(ctx sessionctx.Context, date types.Time, interval string, unit string, isSub bool) (types.Time, bool, error) {
+	year, month, day, dur, err := types.ParseDurationValue(unit, interval)
+	if err := handleInvalidTimeError(ctx, err); err != nil {
+		return types.ZeroTime, true, err
+	}
+	if isSub {
+		year, month, day, dur = -year, -month, -day, -dur
+	}
+
+	goTime, err := date.GoTime(time.UTC)
+	if err := handleInvalidTimeError(ctx, err); err != nil {
+		return types.ZeroTime, true, err
+	}
+	goTime = goTime.Add(dur)
+	goTime = types.AddDate(year, month, day, goTime)
+	if goTime.Year() < 0 || goTime.Year() > 9999 {
+		return types.ZeroTime, true, handleInvalidTimeError(ctx, types.ErrDatetimeFunctionOverflow.GenWithStackByArgs("datetime"))
+	}
+
+	if goTime.Nanosecond() == 0 {
+		date.SetFsp(types.MinFsp)
+	} else {
+		date.SetFsp(types.MaxFsp)
+	}
+	if date.Type() != mysql.TypeDate || types.IsClockUnit(unit) {
+		date.SetType(mysql.TypeDatetime)
+	}
+	date.SetCoreTime(types.FromGoTime(goTime))
+	return date, false, nil
+}
+
+type builtinDateArithDatetimeSig struct {
+	baseBuiltinFunc
+
+	isSub bool
+}
+
+func (b *builtinDateArithDatetimeSig) Clone() builtinFunc {
+	newSig := &builtinDateArithDatetimeSig{isSub: b.isSub}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals DATE_ADD(date,INTERVAL expr unit) and its variants with a
+// temporal date argument.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-add
+func (b *builtinDateArithDatetimeSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	date, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	if date.InvalidZero() {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, date.String()))
+	}
+	interval, unit, isNull, err := evalInterval(b.ctx, b.args, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	return addInterval(b.ctx, date, interval, unit, b.isSub)
+}
+
+type builtinDateArithStringSig struct {
+	baseBuiltinFunc
+
+	isSub bool
+}
+
+func (b *builtinDateArithStringSig) Clone() builtinFunc {
+	newSig := &builtinDateArithStringSig{isSub: b.isSub}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals DATE_ADD(date,INTERVAL expr unit) and its variants with a
+// string date argument. The result is a DATE when the string only contains
+// a date and the unit has no clock part.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-add
+func (b *builtinDateArithStringSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	dateStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	interval, unit, isNull, err := evalInterval(b.ctx, b.args, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	dateTp := mysql.TypeDate
+	if !types.IsDateFormat(dateStr) || types.IsClockUnit(unit) {
+		dateTp = mysql.TypeDatetime
+	}
+	date, err := types.ParseTime(b.ctx.GetSessionVars().StmtCtx, dateStr, dateTp, types.MaxFsp)
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	if date.InvalidZero() {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, dateStr))
+	}
+	return addInterval(b.ctx, date, interval, unit, b.isSub)
+}
+
+type builtinDateArithDurationSig struct {
+	baseBuiltinFunc
+
+	isSub bool
+}
+
+func (b *builtinDateArithDurationSig) Clone() builtinFunc {
+	newSig := &builtinDateArithDurationSig{isSub: b.isSub}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals DATE_ADD(date,INTERVAL expr unit) and its variants with
+// a TIME argument and a unit which only has clock parts.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-add
+func (b *builtinDateArithDurationSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	dur, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroDuration, true, err
+	}
+	interval, unit, isNull, err := evalInterval(b.ctx, b.args, row)
+	if isNull || err != nil {
+		return types.ZeroDuration, true, err
+	}
+	_, _, day, delta, err := types.ParseDurationValue(unit, interval)
+	if err := handleInvalidTimeError(b.ctx, err); err != nil {
+		return types.ZeroDuration, true, err
+	}
+	delta += time.Duration(day) * 24 * time.Hour
+	if b.isSub {
+		delta = -delta
+	}
+	res, err := dur.Add(types.Duration{Duration: delta, Fsp: types.MaxFsp})
+	if err != nil {
+		return types.ZeroDuration, true, err
+	}
+	res.Fsp = int8(b.tp.Decimal)
+	return res, false, nil
+}
+
+type dateDiffFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *dateDiffFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime, types.ETDatetime)
+	sig := &builtinDateDiffSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_DateDiff)
+	return sig, nil
+}
+
+type builtinDateDiffSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDateDiffSig) Clone() builtinFunc {
+	newSig := &builtinDateDiffSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinDateDiffSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_datediff
+func (b *builtinDateDiffSig) evalInt(row chunk.Row) (int64, bool, error) {
+	lhs, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	rhs, isNull, err := b.args[1].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	if invalidLHS, invalidRHS := lhs.InvalidZero(), rhs.InvalidZero(); invalidLHS || invalidRHS {
+		if invalidLHS {
+			err = handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, lhs.String()))
+		}
+		if invalidRHS {
+			err = handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, rhs.String()))
+		}
+		return 0, true, err
+	}
+	return int64(types.DateDiff(lhs.CoreTime(), rhs.CoreTime())), false, nil
+}
+
+type extractFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *extractFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	// Units with a date part are extracted from a DATETIME, the other ones
+	// only read the clock part and are extracted from a TIME.
+	isDatetimeUnit := true
+	if con, ok := args[0].(*Constant); ok {
+		unit, isNull, err := con.EvalString(ctx, chunk.Row{})
+		if err != nil {
+			return nil, err
+		}
+		isDatetimeUnit = isNull || types.IsDateUnit(unit)
+	}
+	var sig builtinFunc
+	if isDatetimeUnit {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETDatetime)
+		sig = &builtinExtractDatetimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_ExtractDatetime)
+	} else {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETDuration)
+		sig = &builtinExtractDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_ExtractDuration)
+	}
+	return sig, nil
+}
+
+type builtinExtractDatetimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinExtractDatetimeSig) Clone() builtinFunc {
+	newSig := &builtinExtractDatetimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinExtractDatetimeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_extract
+func (b *builtinExtractDatetimeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	unit, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	dt, isNull, err := b.args[1].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, handleInvalidTimeError(b.ctx, err)
+	}
+	res, err := types.ExtractDatetimeNum(&dt, unit)
+	return res, err != nil, err
+}
+
+type builtinExtractDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinExtractDurationSig) Clone() builtinFunc {
+	newSig := &builtinExtractDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinExtractDurationSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_extract
+func (b *builtinExtractDurationSig) evalInt(row chunk.Row) (int64, bool, error) {
+	unit, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	dur, isNull, err := b.args[1].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	res, err := types.ExtractDurationNum(&dur, unit)
+	return res, err != nil, err
+}
+
+type unixTimestampFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *unixTimestampFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt)
+		bf.tp.Flen = 11
+		sig := &builtinUnixTimestampCurrentSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UnixTimestampCurrent)
+		return sig, nil
+	}
+
+	fsp := getFspFromType(ctx, args[0])
+	if fsp == types.MinFsp {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime)
+		bf.tp.Flen = 11
+		sig := &builtinUnixTimestampIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UnixTimestampInt)
+		return sig, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDatetime)
+	bf.tp.Flen, bf.tp.Decimal = 12+int(fsp), int(fsp)
+	sig := &builtinUnixTimestampDecSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_UnixTimestampDec)
+	return sig, nil
+}
+
+// goTimeToMysqlUnixTimestamp converts go time into MySQL's Unix timestamp.
+// MySQL's Unix timestamp ranges in int32. Values out of range should be rewritten to 0.
+func goTimeToMysqlUnixTimestamp(t time.Time, decimal int) (*types.MyDecimal, error) {
+	nanoSeconds := t.UnixNano()
+	if nanoSeconds < 0 || (nanoSeconds/1e3) >= (math.MaxInt32+1)*1e6 {
+		return new(types.MyDecimal), nil
+	}
+	dec := new(types.MyDecimal)
+	// Here we don't use float to prevent precision lose.
+	dec.FromInt(nanoSeconds)
+	err := dec.Shift(-9)
+	if err != nil {
+		return nil, err
+	}
+	// In MySQL's implementation, unix_timestamp() will truncate the result instead of rounding it.
+	err = dec.Round(dec, decimal, types.ModeTruncate)
+	return dec, err
+}
+
+// evalUnixTimestamp evaluates the Unix timestamp of the time argument, which
+// is interpreted in the session time zone.
+func evalUnixTimestamp(ctx sessionctx.Context, arg Expression, row chunk.Row, decimal int) (*types.MyDecimal, bool, error) {
+	val, isNull, err := arg.EvalTime(ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, handleInvalidTimeError(ctx, err)
+	}
+	t, err := val.GoTime(ctx.GetSessionVars().Location())
+	if err != nil {
+		return new(types.MyDecimal), false, nil
+	}
+	dec, err := goTimeToMysqlUnixTimestamp(t, decimal)
+	return dec, err != nil, err
+}
+
+type builtinUnixTimestampCurrentSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampCurrentSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampCurrentSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a UNIX_TIMESTAMP().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func (b *builtinUnixTimestampCurrentSig) evalInt(row chunk.Row) (int64, bool, error) {
+	dec, err := goTimeToMysqlUnixTimestamp(b.ctx.GetSessionVars().StmtCtx.GetNowTsCached(), 1)
+	if err != nil {
+		return 0, true, err
+	}
+	intVal, err := dec.ToInt()
+	if !types.ErrTruncated.Equal(err) {
+		return intVal, err != nil, err
+	}
+	return intVal, false, nil
+}
+
+type builtinUnixTimestampIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampIntSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a UNIX_TIMESTAMP(time).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func (b *builtinUnixTimestampIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	dec, isNull, err := evalUnixTimestamp(b.ctx, b.args[0], row, 1)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	intVal, err := dec.ToInt()
+	if !types.ErrTruncated.Equal(err) {
+		return intVal, err != nil, err
+	}
+	return intVal, false, nil
+}
+
+type builtinUnixTimestampDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampDecSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a UNIX_TIMESTAMP(time).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func (b *builtinUnixTimestampDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	return evalUnixTimestamp(b.ctx, b.args[0], row, b.tp.Decimal)
+}
+
+type fromUnixTimeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *fromUnixTimeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp := getFspFromType(ctx, args[0])
+	if len(args) == 1 {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETDecimal)
+		bf.tp.Flen, bf.tp.Decimal = mysql.MaxDatetimeWidthNoFsp, int(fsp)
+		if fsp > 0 {
+			bf.tp.Flen += 1 + int(fsp)
+		}
+		sig := &builtinFromUnixTime1ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FromUnixTime1Arg)
+		return sig, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETDecimal, types.ETString)
+	bf.tp.Flen = args[1].GetType().Flen
+	if bf.tp.Flen > 0 {
+		// worst case: formatMask=%r%r%r...%r, each %r takes 11 characters
+		bf.tp.Flen = (bf.tp.Flen + 1) / 2 * 11
+	}
+	sig := &builtinFromUnixTime2ArgSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_FromUnixTime2Arg)
+	return sig, nil
+}
+
+// evalFromUnixTime converts a Unix timestamp into a DATETIME in the session
+// time zone. Negative timestamps and the ones out of int32 result in NULL.
+func evalFromUnixTime(ctx sessionctx.Context, fsp int8, unixTimeStamp *types.MyDecimal) (types.Time, bool, error) {
+	if unixTimeStamp.IsNegative() {
+		return types.ZeroTime, true, nil
+	}
+	integralPart, err := unixTimeStamp.ToInt()
+	if err != nil && !types.ErrTruncated.Equal(err) {
+		return types.ZeroTime, true, err
+	}
+	if integralPart > int64(math.MaxInt32) {
+		return types.ZeroTime, true, nil
+	}
+	// Split the integral part and fractional part of a decimal timestamp.
+	// e.g. for timestamp 12345.678,
+	// first get the integral part 12345,
+	// then (12345.678 - 12345) * (10^9) to get the decimal part and convert it to nanosecond precision.
+	fracDecimalTp := new(types.MyDecimal)
+	err = types.DecimalSub(unixTimeStamp, types.NewDecFromInt(integralPart), fracDecimalTp)
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	x := new(types.MyDecimal)
+	err = types.DecimalMul(fracDecimalTp, types.NewDecFromInt(int64(time.Second)), x)
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	fractionalPart, err := x.ToInt()
+	if err != nil && !types.ErrTruncated.Equal(err) {
+		return types.ZeroTime, true, err
+	}
+	tmp := time.Unix(integralPart, fractionalPart).In(ctx.GetSessionVars().Location())
+	return convertTimeToMysqlTime(tmp, fsp), false, nil
+}
+
+type builtinFromUnixTime1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFromUnixTime1ArgSig) Clone() builtinFunc {
+	newSig := &builtinFromUnixTime1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinFromUnixTime1ArgSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_from-unixtime
+func (b *builtinFromUnixTime1ArgSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	unixTimeStamp, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, isNull, err
+	}
+	return evalFromUnixTime(b.ctx, int8(b.tp.Decimal), unixTimeStamp)
+}
+
+type builtinFromUnixTime2ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFromUnixTime2ArgSig) Clone() builtinFunc {
+	newSig := &builtinFromUnixTime2ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinFromUnixTime2ArgSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_from-unixtime
+func (b *builtinFromUnixTime2ArgSig) evalString(row chunk.Row) (string, bool, error) {
+	format, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	unixTimeStamp, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	t, isNull, err := evalFromUnixTime(b.ctx, getFspFromType(b.ctx, b.args[0]), unixTimeStamp)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, err := t.DateFormat(format)
+	return res, err != nil, err
+}
@@ -0,0 +1,78 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testEvaluatorSuite) TestDateArith(c *C) {
+	cases := []struct {
+		fc       string
+		date     interface{}
+		interval interface{}
+		unit     string
+		expected string
+		isNil    bool
+	}{
+		{ast.DateAdd, "2018-01-31", 1, "MONTH", "2018-02-28", false},
+		{ast.DateAdd, "2020-02-29", 1, "YEAR", "2021-02-28", false},
+		{ast.DateAdd, "2018-01-31 23:59:59", 1, "SECOND", "2018-02-01 00:00:00", false},
+		{ast.DateAdd, "2018-01-31", "1:1", "HOUR_MINUTE", "2018-01-31 01:01:00", false},
+		{ast.DateSub, "2018-03-01", 1, "DAY", "2018-02-28", false},
+		{ast.DateSub, "2018-03-01 00:00:00", "1.5", "SECOND", "2018-02-28 23:59:58.500000", false},
+		{ast.DateAdd, nil, 1, "DAY", "", true},
+		{ast.DateAdd, "2018-01-31", nil, "DAY", "", true},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, t.fc, s.primitiveValsToConstants([]interface{}{t.date, t.interval, t.unit})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull)
+		} else {
+			c.Assert(d.GetMysqlTime().String(), Equals, t.expected)
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestDateDiffAndExtract(c *C) {
+	f, err := newFunctionForTest(s.ctx, ast.DateDiff, s.primitiveValsToConstants([]interface{}{"2020-03-01 23:59:59", "2020-02-01"})...)
+	c.Assert(err, IsNil)
+	d, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetInt64(), Equals, int64(29))
+
+	cases := []struct {
+		unit     string
+		arg      string
+		expected int64
+	}{
+		{"YEAR", "2011-11-11 10:10:10", 2011},
+		{"QUARTER", "2011-11-11 10:10:10", 4},
+		{"DAY_HOUR", "2011-11-11 10:10:10", 1110},
+		{"HOUR_SECOND", "2011-11-11 10:10:10", 101010},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Extract, s.primitiveValsToConstants([]interface{}{t.unit, t.arg})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetInt64(), Equals, t.expected)
+	}
+}
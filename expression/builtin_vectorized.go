@@ -53,8 +53,16 @@ func newBuffer(evalType types.EvalType, capacity int) (*chunk.Column, error) {
 		return chunk.NewColumn(types.NewFieldType(mysql.TypeLonglong), capacity), nil
 	case types.ETReal:
 		return chunk.NewColumn(types.NewFieldType(mysql.TypeDouble), capacity), nil
+	case types.ETDecimal:
+		return chunk.NewColumn(types.NewFieldType(mysql.TypeNewDecimal), capacity), nil
 	case types.ETString:
 		return chunk.NewColumn(types.NewFieldType(mysql.TypeString), capacity), nil
+	case types.ETDatetime, types.ETTimestamp:
+		return chunk.NewColumn(types.NewFieldType(mysql.TypeDatetime), capacity), nil
+	case types.ETDuration:
+		return chunk.NewColumn(types.NewFieldType(mysql.TypeDuration), capacity), nil
+	case types.ETJson:
+		return chunk.NewColumn(types.NewFieldType(mysql.TypeJSON), capacity), nil
 	}
 	return nil, errors.Errorf("get column buffer for unsupported EvalType=%v", evalType)
 }
@@ -104,3 +112,51 @@ func (r *localSliceBuffer) put(buf *chunk.Column) {
 	r.size++
 	r.Unlock()
 }
+
+// vecEvalArgs evaluates all the arguments of b by argTps, the buffers must be put back by putVecArgs.
+func vecEvalArgs(b *baseBuiltinFunc, input *chunk.Chunk, argTps []types.EvalType) ([]*chunk.Column, error) {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, 0, len(b.args))
+	for i, arg := range b.args {
+		buf, err := b.bufAllocator.get(argTps[i], n)
+		if err != nil {
+			putVecArgs(b, bufs)
+			return nil, err
+		}
+		bufs = append(bufs, buf)
+		switch argTps[i] {
+		case types.ETInt:
+			err = arg.VecEvalInt(b.ctx, input, buf)
+		case types.ETReal:
+			err = arg.VecEvalReal(b.ctx, input, buf)
+		case types.ETDecimal:
+			err = arg.VecEvalDecimal(b.ctx, input, buf)
+		case types.ETString:
+			err = arg.VecEvalString(b.ctx, input, buf)
+		default:
+			err = errors.Errorf("unsupported argument type %v in vectorized evaluation", argTps[i])
+		}
+		if err != nil {
+			putVecArgs(b, bufs)
+			return nil, err
+		}
+	}
+	return bufs, nil
+}
+
+// putVecArgs puts back the buffers allocated by vecEvalArgs.
+func putVecArgs(b *baseBuiltinFunc, bufs []*chunk.Column) {
+	for _, buf := range bufs {
+		b.bufAllocator.put(buf)
+	}
+}
+
+// hasNullArg checks if any of the arguments in row i is NULL.
+func hasNullArg(bufs []*chunk.Column, i int) bool {
+	for _, buf := range bufs {
+		if buf.IsNull(i) {
+			return true
+		}
+	}
+	return false
+}
@@ -249,6 +249,12 @@ func convertETType(eType types.EvalType) (mysqlType byte) {
 		mysqlType = mysql.TypeDouble
 	case types.ETString:
 		mysqlType = mysql.TypeVarString
+	case types.ETDatetime:
+		mysqlType = mysql.TypeDatetime
+	case types.ETTimestamp:
+		mysqlType = mysql.TypeTimestamp
+	case types.ETDuration:
+		mysqlType = mysql.TypeDuration
 	}
 	return
 }
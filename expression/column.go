@@ -27,6 +27,154 @@ import (
 	"github.com/pingcap/tidb/util/codec"
 )
 
+// CorrelatedColumn stands for a column in a correlated sub query.
+type CorrelatedColumn struct {
+	Column
+
+	Data *types.Datum
+}
+
+// Clone implements Expression interface.
+func (col *CorrelatedColumn) Clone() Expression {
+	return col
+}
+
+// VecEvalInt evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalInt(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETInt, input, result)
+}
+
+// VecEvalReal evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalReal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETReal, input, result)
+}
+
+// VecEvalString evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETString, input, result)
+}
+
+// VecEvalDecimal evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETDecimal, input, result)
+}
+
+// VecEvalTime evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalTime(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETTimestamp, input, result)
+}
+
+// VecEvalDuration evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalDuration(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETDuration, input, result)
+}
+
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETJson, input, result)
+}
+
+// Eval implements Expression interface.
+func (col *CorrelatedColumn) Eval(row chunk.Row) (types.Datum, error) {
+	return *col.Data, nil
+}
+
+// EvalInt returns int representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalInt(ctx sessionctx.Context, row chunk.Row) (int64, bool, error) {
+	if col.Data.IsNull() {
+		return 0, true, nil
+	}
+	if col.GetType().Hybrid() {
+		res, err := col.Data.ToInt64(ctx.GetSessionVars().StmtCtx)
+		return res, err != nil, err
+	}
+	return col.Data.GetInt64(), false, nil
+}
+
+// EvalReal returns real representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalReal(ctx sessionctx.Context, row chunk.Row) (float64, bool, error) {
+	if col.Data.IsNull() {
+		return 0, true, nil
+	}
+	return col.Data.GetFloat64(), false, nil
+}
+
+// EvalString returns string representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bool, error) {
+	if col.Data.IsNull() {
+		return "", true, nil
+	}
+	res, err := col.Data.ToString()
+	return res, err != nil, err
+}
+
+// EvalDecimal returns decimal representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalDecimal(ctx sessionctx.Context, row chunk.Row) (*types.MyDecimal, bool, error) {
+	if col.Data.IsNull() {
+		return nil, true, nil
+	}
+	return col.Data.GetMysqlDecimal(), false, nil
+}
+
+// EvalTime returns DATE/DATETIME/TIMESTAMP representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalTime(ctx sessionctx.Context, row chunk.Row) (types.Time, bool, error) {
+	if col.Data.IsNull() {
+		return types.ZeroTime, true, nil
+	}
+	return col.Data.GetMysqlTime(), false, nil
+}
+
+// EvalDuration returns Duration representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalDuration(ctx sessionctx.Context, row chunk.Row) (types.Duration, bool, error) {
+	if col.Data.IsNull() {
+		return types.Duration{}, true, nil
+	}
+	return col.Data.GetMysqlDuration(), false, nil
+}
+
+// EvalJSON returns JSON representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalJSON(ctx sessionctx.Context, row chunk.Row) (types.BinaryJSON, bool, error) {
+	if col.Data.IsNull() {
+		return types.BinaryJSON{}, true, nil
+	}
+	return col.Data.GetMysqlJSON(), false, nil
+}
+
+// Equal implements Expression interface.
+func (col *CorrelatedColumn) Equal(ctx sessionctx.Context, expr Expression) bool {
+	if cc, ok := expr.(*CorrelatedColumn); ok {
+		return col.Column.Equal(ctx, &cc.Column)
+	}
+	return false
+}
+
+// IsCorrelated implements Expression interface.
+func (col *CorrelatedColumn) IsCorrelated() bool {
+	return true
+}
+
+// ConstItem implements Expression interface.
+func (col *CorrelatedColumn) ConstItem() bool {
+	return false
+}
+
+// Decorrelate implements Expression interface.
+func (col *CorrelatedColumn) Decorrelate(schema *Schema) Expression {
+	if !schema.Contains(&col.Column) {
+		return col
+	}
+	return &col.Column
+}
+
+// ResolveIndices implements Expression interface.
+func (col *CorrelatedColumn) ResolveIndices(_ *Schema) (Expression, error) {
+	return col, nil
+}
+
+func (col *CorrelatedColumn) resolveIndices(_ *Schema) error {
+	return nil
+}
+
 // Column represents a column.
 type Column struct {
 	RetType *types.FieldType
@@ -41,6 +189,10 @@ type Column struct {
 
 	hashcode []byte
 
+	// InOperand indicates whether this column is the inner operand of column equal condition converted
+	// from `[not] in (subq)`.
+	InOperand bool
+
 	OrigName string
 }
 
@@ -129,6 +281,30 @@ func (col *Column) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, res
 	return nil
 }
 
+// VecEvalDecimal evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
+// VecEvalTime evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalTime(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
+// VecEvalDuration evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalDuration(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
 const columnPrefix = "Column#"
 
 // String implements Stringer interface.
@@ -206,6 +382,39 @@ func (col *Column) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bo
 	return val, false, nil
 }
 
+// EvalDecimal returns decimal representation of Column.
+func (col *Column) EvalDecimal(ctx sessionctx.Context, row chunk.Row) (*types.MyDecimal, bool, error) {
+	if row.IsNull(col.Index) {
+		return nil, true, nil
+	}
+	return row.GetMyDecimal(col.Index), false, nil
+}
+
+// EvalTime returns DATE/DATETIME/TIMESTAMP representation of Column.
+func (col *Column) EvalTime(ctx sessionctx.Context, row chunk.Row) (types.Time, bool, error) {
+	if row.IsNull(col.Index) {
+		return types.ZeroTime, true, nil
+	}
+	return row.GetTime(col.Index), false, nil
+}
+
+// EvalDuration returns Duration representation of Column.
+func (col *Column) EvalDuration(ctx sessionctx.Context, row chunk.Row) (types.Duration, bool, error) {
+	if row.IsNull(col.Index) {
+		return types.Duration{}, true, nil
+	}
+	duration := row.GetDuration(col.Index, col.RetType.Decimal)
+	return duration, false, nil
+}
+
+// EvalJSON returns JSON representation of Column.
+func (col *Column) EvalJSON(ctx sessionctx.Context, row chunk.Row) (types.BinaryJSON, bool, error) {
+	if row.IsNull(col.Index) {
+		return types.BinaryJSON{}, true, nil
+	}
+	return row.GetJSON(col.Index), false, nil
+}
+
 // Clone implements Expression interface.
 func (col *Column) Clone() Expression {
 	newCol := *col
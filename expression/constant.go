@@ -87,6 +87,26 @@ func (c *Constant) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, res
 	return genVecFromConstExpr(ctx, c, types.ETString, input, result)
 }
 
+// VecEvalDecimal evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETDecimal, input, result)
+}
+
+// VecEvalTime evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalTime(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETTimestamp, input, result)
+}
+
+// VecEvalDuration evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalDuration(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETDuration, input, result)
+}
+
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETJson, input, result)
+}
+
 // Eval implements Expression interface.
 func (c *Constant) Eval(_ chunk.Row) (types.Datum, error) {
 	return c.Value, nil
@@ -125,6 +145,70 @@ func (c *Constant) EvalString(ctx sessionctx.Context, _ chunk.Row) (string, bool
 	return res, err != nil, err
 }
 
+// EvalDecimal returns decimal representation of Constant.
+func (c *Constant) EvalDecimal(ctx sessionctx.Context, _ chunk.Row) (*types.MyDecimal, bool, error) {
+	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+		return nil, true, nil
+	}
+	res, err := c.Value.ToDecimal(ctx.GetSessionVars().StmtCtx)
+	return res, err != nil, err
+}
+
+// EvalTime returns DATE/DATETIME/TIMESTAMP representation of Constant.
+func (c *Constant) EvalTime(ctx sessionctx.Context, _ chunk.Row) (types.Time, bool, error) {
+	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+		return types.ZeroTime, true, nil
+	}
+	if c.Value.Kind() == types.KindMysqlTime {
+		return c.Value.GetMysqlTime(), false, nil
+	}
+	tp := c.GetType()
+	if !types.IsTypeTime(tp.Tp) {
+		tp = types.NewFieldType(mysql.TypeDatetime)
+		tp.Decimal = int(types.MaxFsp)
+	}
+	d, err := c.Value.ConvertTo(ctx.GetSessionVars().StmtCtx, tp)
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	return d.GetMysqlTime(), false, nil
+}
+
+// EvalDuration returns Duration representation of Constant.
+func (c *Constant) EvalDuration(ctx sessionctx.Context, _ chunk.Row) (types.Duration, bool, error) {
+	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+		return types.Duration{}, true, nil
+	}
+	if c.Value.Kind() == types.KindMysqlDuration {
+		return c.Value.GetMysqlDuration(), false, nil
+	}
+	tp := types.NewFieldType(mysql.TypeDuration)
+	tp.Decimal = c.GetType().Decimal
+	if tp.Decimal == types.UnspecifiedLength {
+		tp.Decimal = int(types.MaxFsp)
+	}
+	d, err := c.Value.ConvertTo(ctx.GetSessionVars().StmtCtx, tp)
+	if err != nil {
+		return types.Duration{}, true, err
+	}
+	return d.GetMysqlDuration(), false, nil
+}
+
+// EvalJSON returns JSON representation of Constant.
+func (c *Constant) EvalJSON(ctx sessionctx.Context, _ chunk.Row) (types.BinaryJSON, bool, error) {
+	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+		return types.BinaryJSON{}, true, nil
+	}
+	if c.Value.Kind() == types.KindMysqlJSON {
+		return c.Value.GetMysqlJSON(), false, nil
+	}
+	d, err := c.Value.ConvertTo(ctx.GetSessionVars().StmtCtx, types.NewFieldType(mysql.TypeJSON))
+	if err != nil {
+		return types.BinaryJSON{}, true, err
+	}
+	return d.GetMysqlJSON(), false, nil
+}
+
 // Equal implements Expression interface.
 func (c *Constant) Equal(ctx sessionctx.Context, b Expression) bool {
 	y, ok := b.(*Constant)
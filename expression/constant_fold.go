@@ -14,11 +14,115 @@
 package expression
 
 import (
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
 )
 
+// specialFoldHandler stores the handlers of the functions that only evaluate some of their arguments,
+// these functions can be folded as long as the arguments that decide the result are constant.
+var specialFoldHandler = map[string]func(*ScalarFunction) (Expression, bool){
+	ast.If:       ifFoldHandler,
+	ast.Ifnull:   ifNullFoldHandler,
+	ast.Case:     caseWhenFoldHandler,
+	ast.Coalesce: coalesceFoldHandler,
+}
+
+// ifFoldHandler folds IF(cond, expr1, expr2) if cond and the chosen branch are constant.
+func ifFoldHandler(expr *ScalarFunction) (Expression, bool) {
+	args := expr.GetArgs()
+	isTrue, ok := evalConstantCond(expr, args[0])
+	if !ok {
+		return expr, false
+	}
+	if isTrue {
+		return evalIfArgConstant(expr, args[1])
+	}
+	return evalIfArgConstant(expr, args[2])
+}
+
+// ifNullFoldHandler folds IFNULL(expr1, expr2) if expr1 is a constant which is
+// not NULL, or both of the arguments are constant.
+func ifNullFoldHandler(expr *ScalarFunction) (Expression, bool) {
+	args := expr.GetArgs()
+	con, isConst := args[0].(*Constant)
+	if !isConst {
+		return expr, false
+	}
+	if !con.Value.IsNull() {
+		return evalToConstant(expr)
+	}
+	return evalIfArgConstant(expr, args[1])
+}
+
+// caseWhenFoldHandler folds CASE if all the conditions before the first true
+// one are constant, and so is the chosen result.
+func caseWhenFoldHandler(expr *ScalarFunction) (Expression, bool) {
+	args := expr.GetArgs()
+	l := len(args)
+	for i := 0; i < l-1; i += 2 {
+		isTrue, ok := evalConstantCond(expr, args[i])
+		if !ok {
+			return expr, false
+		}
+		if isTrue {
+			return evalIfArgConstant(expr, args[i+1])
+		}
+	}
+	if l%2 == 1 {
+		return evalIfArgConstant(expr, args[l-1])
+	}
+	return evalToConstant(expr)
+}
+
+// coalesceFoldHandler folds COALESCE if all the arguments up to the first
+// non-NULL one are constant.
+func coalesceFoldHandler(expr *ScalarFunction) (Expression, bool) {
+	for _, arg := range expr.GetArgs() {
+		con, isConst := arg.(*Constant)
+		if !isConst {
+			return expr, false
+		}
+		if !con.Value.IsNull() {
+			break
+		}
+	}
+	return evalToConstant(expr)
+}
+
+// evalConstantCond evaluates cond as a condition of expr, ok is false if cond
+// is not a constant or fails to be evaluated.
+func evalConstantCond(expr *ScalarFunction, cond Expression) (isTrue bool, ok bool) {
+	con, isConst := cond.(*Constant)
+	if !isConst {
+		return false, false
+	}
+	val, isNull, err := con.EvalInt(expr.GetCtx(), chunk.Row{})
+	if err != nil {
+		return false, false
+	}
+	return !isNull && val != 0, true
+}
+
+// evalIfArgConstant folds expr if arg, which is the only argument left to
+// evaluate, is a constant.
+func evalIfArgConstant(expr *ScalarFunction, arg Expression) (Expression, bool) {
+	if _, isConst := arg.(*Constant); !isConst {
+		return expr, false
+	}
+	return evalToConstant(expr)
+}
+
+func evalToConstant(expr *ScalarFunction) (Expression, bool) {
+	value, err := expr.Eval(chunk.Row{})
+	if err != nil {
+		logutil.BgLogger().Debug("fold expression to constant", zap.String("expression", expr.ExplainInfo()), zap.Error(err))
+		return expr, false
+	}
+	return &Constant{Value: value, RetType: expr.RetType}, true
+}
+
 // FoldConstant does constant folding optimization on an expression excluding deferred ones.
 func FoldConstant(expr Expression) Expression {
 	return foldConstant(expr)
@@ -30,6 +134,11 @@ func foldConstant(expr Expression) Expression {
 		if _, ok := unFoldableFunctions[x.FuncName.L]; ok {
 			return expr
 		}
+		if handler, ok := specialFoldHandler[x.FuncName.L]; ok {
+			if folded, ok := handler(x); ok {
+				return folded
+			}
+		}
 
 		args := x.GetArgs()
 		sc := x.GetCtx().GetSessionVars().StmtCtx
@@ -46,7 +155,7 @@ func foldConstant(expr Expression) Expression {
 			}
 		}
 		if !allConstArg {
-			if !hasNullArg || !sc.InNullRejectCheck {
+			if !hasNullArg || !sc.InNullRejectCheck || x.FuncName.L == ast.NullEQ {
 				return expr
 			}
 			constArgs := make([]Expression, len(args))
@@ -304,6 +304,10 @@ type propOuterJoinConstSolver struct {
 	filterConds []Expression
 	outerSchema *Schema
 	innerSchema *Schema
+	// nullSensitive indicates if this outer join is null sensitive, if true, we cannot generate
+	// additional `col is not null` condition from column equal conditions. Specifically, this value
+	// is true for LeftOuterSemiJoin and AntiLeftOuterSemiJoin.
+	nullSensitive bool
 }
 
 func (s *propOuterJoinConstSolver) setConds2ConstFalse(filterConds bool) {
@@ -419,7 +423,9 @@ func (s *propOuterJoinConstSolver) colsFromOuterAndInner(col1, col2 *Column) (*C
 // `outerCol1 = outerCol2` or `innerCol1 = innerCol2`, they do not help deriving new inner table conditions
 // which can be pushed down to children plan nodes, so we do not pick them.
 func (s *propOuterJoinConstSolver) validColEqualCond(cond Expression) (*Column, *Column) {
-	if fun, ok := cond.(*ScalarFunction); ok && fun.FuncName.L == ast.EQ {
+	// The column equal condition converted from `[not] in (subq)` is null aware,
+	// so we cannot propagate it like a normal column equal condition.
+	if fun, ok := cond.(*ScalarFunction); ok && fun.FuncName.L == ast.EQ && !IsEQCondFromIn(fun) {
 		lCol, lOk := fun.GetArgs()[0].(*Column)
 		rCol, rOk := fun.GetArgs()[1].(*Column)
 		if lOk && rOk {
@@ -477,6 +483,13 @@ func (s *propOuterJoinConstSolver) propagateColumnEQ() {
 			innerID := s.getColID(innerCol)
 			s.unionSet.Union(outerID, innerID)
 			visited[i] = true
+			// Generate `innerCol is not null` from `outerCol = innerCol`. Note that `outerCol is not null`
+			// does not hold since we are in outer join.
+			// For LeftOuterSemiJoin and AntiLeftOuterSemiJoin, rows with null innerCol would impact
+			// whether the join should output 0 or null, so we cannot derive it.
+			if s.nullSensitive {
+				continue
+			}
 			childCol := s.innerSchema.RetrieveColumn(innerCol)
 			if !mysql.HasNotNullFlag(childCol.RetType.Flag) {
 				notNullExpr := BuildNotNullExpr(s.ctx, childCol)
@@ -551,10 +564,11 @@ func propagateConstantDNF(ctx sessionctx.Context, conds []Expression) []Expressi
 // conditions based on this column equal condition and `outerCol` related
 // expressions in join conditions and filter conditions;
 func PropConstOverOuterJoin(ctx sessionctx.Context, joinConds, filterConds []Expression,
-	outerSchema, innerSchema *Schema) ([]Expression, []Expression) {
+	outerSchema, innerSchema *Schema, nullSensitive bool) ([]Expression, []Expression) {
 	solver := &propOuterJoinConstSolver{
-		outerSchema: outerSchema,
-		innerSchema: innerSchema,
+		outerSchema:   outerSchema,
+		innerSchema:   innerSchema,
+		nullSensitive: nullSensitive,
 	}
 	solver.colMapper = make(map[int64]int)
 	solver.ctx = ctx
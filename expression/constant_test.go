@@ -181,6 +181,38 @@ func (*testExpressionSuite) TestConstantFolding(c *C) {
 			condition: newFunction(ast.LT, newColumn(0), newFunction(ast.Plus, newColumn(1), newFunction(ast.Plus, newLonglong(2), newLonglong(1)))),
 			result:    "lt(Column#0, plus(Column#1, 3))",
 		},
+		{
+			condition: newFunction(ast.If, newLonglong(1), newLonglong(2), newColumn(0)),
+			result:    "2",
+		},
+		{
+			condition: newFunction(ast.If, newLonglong(0), newColumn(0), newLonglong(3)),
+			result:    "3",
+		},
+		{
+			condition: newFunction(ast.If, newLonglong(1), newColumn(0), newLonglong(3)),
+			result:    "if(1, Column#0, 3)",
+		},
+		{
+			condition: newFunction(ast.Ifnull, newLonglong(1), newColumn(0)),
+			result:    "1",
+		},
+		{
+			condition: newFunction(ast.Case, newLonglong(0), newColumn(0), newLonglong(1), newLonglong(2), newColumn(1)),
+			result:    "2",
+		},
+		{
+			condition: newFunction(ast.Case, newColumn(0), newLonglong(1), newLonglong(1), newLonglong(2)),
+			result:    "case(Column#0, 1, 1, 2)",
+		},
+		{
+			condition: newFunction(ast.Coalesce, newLonglong(3), newColumn(0)),
+			result:    "3",
+		},
+		{
+			condition: newFunction(ast.Coalesce, newColumn(0), newLonglong(3)),
+			result:    "coalesce(Column#0, 3)",
+		},
 	}
 	for _, tt := range tests {
 		newConds := FoldConstant(tt.condition)
@@ -33,6 +33,14 @@ var (
 	// All the un-exported errors are defined here:
 	errFunctionNotExists = terror.ClassExpression.New(mysql.ErrSpDoesNotExist, mysql.MySQLErrName[mysql.ErrSpDoesNotExist])
 	errNonUniq           = terror.ClassExpression.New(mysql.ErrNonUniq, mysql.MySQLErrName[mysql.ErrNonUniq])
+	errIncorrectArgs     = terror.ClassExpression.New(mysql.ErrWrongArguments, mysql.MySQLErrName[mysql.ErrWrongArguments])
+
+	// Sequence of errors for string functions
+	errWarnAllowedPacketOverflowed = terror.ClassExpression.New(mysql.ErrWarnAllowedPacketOverflowed, mysql.MySQLErrName[mysql.ErrWarnAllowedPacketOverflowed])
+	errUnknownLocale               = terror.ClassExpression.New(mysql.ErrUnknownLocale, mysql.MySQLErrName[mysql.ErrUnknownLocale])
+
+	// Sequence of errors for json functions
+	errJSONDocumentNULLKey = terror.ClassExpression.New(mysql.ErrJSONDocumentNULLKey, mysql.MySQLErrName[mysql.ErrJSONDocumentNULLKey])
 )
 
 func init() {
@@ -57,6 +65,7 @@ func init() {
 		mysql.ErrBadField:                          mysql.ErrBadField,
 		mysql.ErrNonUniq:                           mysql.ErrNonUniq,
 		mysql.ErrIncorrectType:                     mysql.ErrIncorrectType,
+		mysql.ErrJSONDocumentNULLKey:               mysql.ErrJSONDocumentNULLKey,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassExpression] = expressionMySQLErrCodes
 }
@@ -64,7 +73,7 @@ func init() {
 // handleDivisionByZeroError reports error or warning depend on the context.
 func handleDivisionByZeroError(ctx sessionctx.Context) error {
 	sc := ctx.GetSessionVars().StmtCtx
-	if sc.InInsertStmt || sc.InDeleteStmt {
+	if sc.InInsertStmt || sc.InUpdateStmt || sc.InDeleteStmt {
 		if !ctx.GetSessionVars().SQLMode.HasErrorForDivisionByZeroMode() {
 			return nil
 		}
@@ -14,6 +14,8 @@
 package expression
 
 import (
+	"math"
+	"regexp"
 	"testing"
 	"time"
 
@@ -86,6 +88,8 @@ func (s *testEvaluatorSuite) kindToFieldType(kind byte) types.FieldType {
 		ft.Tp = mysql.TypeDouble
 	case types.KindFloat64:
 		ft.Tp = mysql.TypeDouble
+	case types.KindMysqlDecimal:
+		ft.Tp = mysql.TypeNewDecimal
 	case types.KindString:
 		ft.Tp = mysql.TypeVarString
 	case types.KindBytes:
@@ -126,6 +130,12 @@ func (s *testEvaluatorSuite) TestUnaryOp(c *C) {
 		{int64(1), ast.UnaryMinus, int64(-1)},
 		{int64(1), ast.UnaryMinus, int64(-1)},
 		{uint64(1), ast.UnaryMinus, -int64(1)},
+
+		// test BitNeg.
+		{nil, ast.BitNeg, nil},
+		{int64(-1), ast.BitNeg, uint64(0)},
+		{int64(1), ast.BitNeg, uint64(math.MaxUint64 - 1)},
+		{uint64(math.MaxUint64), ast.BitNeg, uint64(0)},
 	}
 	for i, t := range tbl {
 		fc := funcs[t.op]
@@ -136,3 +146,75 @@ func (s *testEvaluatorSuite) TestUnaryOp(c *C) {
 		c.Assert(result, testutil.DatumEquals, types.NewDatum(t.result), Commentf("%d", i))
 	}
 }
+
+func (s *testEvaluatorSuite) TestBinopNumeric(c *C) {
+	tbl := []struct {
+		lhs interface{}
+		op  string
+		rhs interface{}
+		ret interface{}
+	}{
+		// bit operations.
+		{uint64(3), ast.And, int64(6), uint64(2)},
+		{int64(-1), ast.And, int64(7), uint64(7)},
+		{uint64(3), ast.Or, int64(4), uint64(7)},
+		{int64(-1), ast.Or, int64(0), uint64(math.MaxUint64)},
+		{uint64(3), ast.Xor, int64(5), uint64(6)},
+		{int64(1), ast.LeftShift, int64(3), uint64(8)},
+		{int64(1), ast.LeftShift, int64(64), uint64(0)},
+		{int64(-1), ast.LeftShift, int64(63), uint64(1 << 63)},
+		{int64(16), ast.RightShift, int64(2), uint64(4)},
+		{int64(-1), ast.RightShift, int64(63), uint64(1)},
+		{int64(1), ast.RightShift, int64(-1), uint64(0)},
+		{nil, ast.And, int64(1), nil},
+		{int64(1), ast.LeftShift, nil, nil},
+
+		// integer division.
+		{int64(7), ast.IntDiv, int64(2), int64(3)},
+		{int64(-7), ast.IntDiv, int64(2), int64(-3)},
+		{uint64(7), ast.IntDiv, int64(-8), uint64(0)},
+		{int64(-1), ast.IntDiv, uint64(2), uint64(0)},
+		{types.NewDecFromStringForTest("7.5"), ast.IntDiv, int64(2), int64(3)},
+		{float64(-7.5), ast.IntDiv, float64(2), int64(-3)},
+		{types.NewDecFromStringForTest("-0.5"), ast.IntDiv, uint64(1), uint64(0)},
+		{int64(1), ast.IntDiv, int64(0), nil},
+		{nil, ast.IntDiv, int64(1), nil},
+
+		// modulo.
+		{int64(7), ast.Mod, int64(-3), int64(1)},
+		{int64(-7), ast.Mod, int64(3), int64(-1)},
+		{uint64(7), ast.Mod, int64(-3), uint64(1)},
+		{int64(-7), ast.Mod, uint64(3), int64(-1)},
+		{int64(math.MinInt64), ast.Mod, int64(-1), int64(0)},
+		{types.NewDecFromStringForTest("7.5"), ast.Mod, int64(2), types.NewDecFromStringForTest("1.5")},
+		{float64(-7.5), ast.Mod, int64(2), float64(-1.5)},
+		{int64(1), ast.Mod, int64(0), nil},
+		{float64(1), ast.Mod, float64(0), nil},
+		{nil, ast.Mod, int64(0), nil},
+	}
+	for i, t := range tbl {
+		fc := funcs[t.op]
+		f, err := fc.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(t.lhs, t.rhs)))
+		c.Assert(err, IsNil)
+		result, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(result, testutil.DatumEquals, types.NewDatum(t.ret), Commentf("%d", i))
+	}
+
+	errTbl := []struct {
+		lhs interface{}
+		op  string
+		rhs interface{}
+		err string
+	}{
+		{int64(math.MinInt64), ast.IntDiv, int64(-1), "BIGINT value is out of range in '(-9223372036854775808 DIV -1)'"},
+		{uint64(1), ast.IntDiv, int64(-1), "BIGINT UNSIGNED value is out of range in '(1 DIV -1)'"},
+		{types.NewDecFromStringForTest("1e30"), ast.IntDiv, int64(1), "BIGINT value is out of range in '(1000000000000000000000000000000 DIV 1)'"},
+	}
+	for _, t := range errTbl {
+		f, err := funcs[t.op].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(t.lhs, t.rhs)))
+		c.Assert(err, IsNil)
+		_, err = evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, ErrorMatches, ".*"+regexp.QuoteMeta(t.err))
+	}
+}
@@ -142,6 +142,25 @@ func (pc *PbConverter) encodeDatum(ft *types.FieldType, d types.Datum) (tipb.Exp
 	case types.KindFloat64:
 		tp = tipb.ExprType_Float64
 		val = codec.EncodeFloat(nil, d.GetFloat64())
+	case types.KindMysqlDecimal:
+		tp = tipb.ExprType_MysqlDecimal
+		var err error
+		val, err = codec.EncodeDecimal(nil, d.GetMysqlDecimal(), d.Length(), d.Frac())
+		if err != nil {
+			logutil.BgLogger().Error("encode decimal", zap.Error(err))
+			return tp, nil, false
+		}
+	case types.KindMysqlTime:
+		tp = tipb.ExprType_MysqlTime
+		var err error
+		val, err = codec.EncodeMySQLTime(pc.sc, d.GetMysqlTime(), ft.Tp, nil)
+		if err != nil {
+			logutil.BgLogger().Error("encode mysql time", zap.Error(err))
+			return tp, nil, false
+		}
+	case types.KindMysqlDuration:
+		tp = tipb.ExprType_MysqlDuration
+		val = codec.EncodeInt(nil, int64(d.GetMysqlDuration().Duration))
 	default:
 		return tp, nil, false
 	}
@@ -284,6 +303,12 @@ func (pc PbConverter) canFuncBePushed(sf *ScalarFunction) bool {
 		ast.LogicAnd,
 		ast.LogicOr,
 		ast.UnaryNot,
+		ast.And,
+		ast.Or,
+		ast.Xor,
+		ast.BitNeg,
+		ast.LeftShift,
+		ast.RightShift,
 
 		// compare functions.
 		ast.LT,
@@ -294,19 +319,102 @@ func (pc PbConverter) canFuncBePushed(sf *ScalarFunction) bool {
 		ast.GT,
 		ast.In,
 		ast.IsNull,
+		ast.Like,
+		ast.Regexp,
+		ast.Coalesce,
+		ast.Greatest,
+		ast.Least,
 
 		// arithmetical functions.
 		ast.Plus,
 		ast.Minus,
 		ast.Mul,
 		ast.Div,
+		ast.IntDiv,
+		ast.Mod,
+
+		// math functions.
+		ast.Abs,
+		ast.Ceil,
+		ast.Ceiling,
+		ast.Floor,
+		ast.Round,
+		ast.Truncate,
+		ast.Pow,
+		ast.Power,
+		ast.Sqrt,
+		ast.Exp,
+		ast.Ln,
+		ast.Log,
+		ast.Log2,
+		ast.Log10,
+		ast.Sign,
+		ast.CRC32,
+		ast.Conv,
+		ast.PI,
+		ast.Acos,
+		ast.Asin,
+		ast.Atan,
+		ast.Atan2,
+		ast.Cos,
+		ast.Cot,
+		ast.Degrees,
+		ast.Radians,
+		ast.Sin,
+		ast.Tan,
 
 		// control flow functions.
 		ast.If,
 		ast.Ifnull,
+		ast.Case,
 
 		// string functions.
-		ast.Length:
+		ast.Length,
+		ast.CharLength,
+		ast.CharacterLength,
+		ast.Concat,
+		ast.ConcatWS,
+		ast.Substr,
+		ast.Substring,
+		ast.Left,
+		ast.Right,
+		ast.Upper,
+		ast.Ucase,
+		ast.Lower,
+		ast.Lcase,
+		ast.Trim,
+		ast.LTrim,
+		ast.RTrim,
+		ast.Replace,
+		ast.Locate,
+		ast.Position,
+		ast.Instr,
+		ast.Lpad,
+		ast.Rpad,
+		ast.Repeat,
+		ast.Reverse,
+		ast.Hex,
+		ast.Unhex,
+		ast.Format,
+		ast.Field,
+		ast.Elt,
+
+		// json functions.
+		ast.JSONType,
+		ast.JSONExtract,
+		ast.JSONUnquote,
+		ast.JSONArray,
+		ast.JSONObject,
+		ast.JSONSet,
+		ast.JSONInsert,
+		ast.JSONReplace,
+		ast.JSONRemove,
+		ast.JSONContains,
+		ast.JSONKeys,
+		ast.JSONLength,
+
+		// other functions.
+		ast.Cast:
 		return true
 	}
 	return false
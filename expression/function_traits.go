@@ -22,6 +22,7 @@ var unFoldableFunctions = map[string]struct{}{
 	ast.RowFunc: {},
 	ast.SetVar:  {},
 	ast.GetVar:  {},
+	ast.Rand:    {},
 }
 
 // inequalFunctions stores functions which cannot be propagated from column equal condition.
@@ -32,6 +33,10 @@ var inequalFunctions = map[string]struct{}{
 // mutableEffectsFunctions stores functions which are mutable or have side effects, specifically,
 // we cannot remove them from filter even if they have duplicates.
 var mutableEffectsFunctions = map[string]struct{}{
-	ast.SetVar: {},
-	ast.GetVar: {},
+	ast.Now:              {},
+	ast.CurrentTimestamp: {},
+	ast.UnixTimestamp:    {},
+	ast.SetVar:           {},
+	ast.GetVar:           {},
+	ast.Rand:             {},
 }
@@ -86,6 +86,14 @@ func (b *builtin{{ .compare.CompareName }}{{ .type.TypeName }}Sig) vecEvalInt(in
 		}
 {{- if eq .type.ETName "Real" }}
 		val := types.CompareFloat64(arg0[i], arg1[i])
+{{- else if eq .type.ETName "Decimal" }}
+		val := arg0[i].Compare(&arg1[i])
+{{- else if eq .type.ETName "Datetime" }}
+		val := arg0[i].Compare(arg1[i])
+{{- else if eq .type.ETName "Duration" }}
+		val := types.CompareDuration(arg0[i], arg1[i])
+{{- else if eq .type.ETName "Json" }}
+		val := types.CompareBinaryJSON(buf0.GetJSON(i), buf1.GetJSON(i))
 {{- else }}
 		val := types.CompareString(buf0.GetString(i), buf1.GetString(i))
 {{- end }}
@@ -161,7 +169,11 @@ var comparesMap = []CompareContext{
 var typesMap = []TypeContext{
 	TypeInt,
 	TypeReal,
+	TypeDecimal,
 	TypeString,
+	TypeDatetime,
+	TypeDuration,
+	TypeJSON,
 }
 
 func generateDotGo(fileName string, compares []CompareContext, types []TypeContext) (err error) {
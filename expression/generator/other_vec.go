@@ -94,6 +94,15 @@ var builtinInTmpl = template.Must(template.New("builtinInTmpl").Parse(`
 					compareResult = 0
 				}
 		}
+	{{- else if eq .Input.TypeName "Decimal" -}}
+		compareResult = 1
+		if arg0.Compare(&arg1) == 0 {
+			compareResult = 0
+		}
+	{{- else if eq .Input.TypeName "Time" -}}
+		compareResult = arg0.Compare(arg1)
+	{{- else if eq .Input.TypeName "Duration" -}}
+		compareResult = types.CompareDuration(arg0, arg1)
 	{{- else -}}
 		compareResult = types.Compare{{ .Input.TypeNameInColumn }}(arg0, arg1)
 	{{- end -}}
@@ -185,6 +194,7 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
@@ -211,6 +221,16 @@ func (g inGener) gen() interface{} {
 			return -float64(randNum)
 		}
 		return float64(randNum)
+	case types.ETDecimal:
+		if rand.Float64() < 0.5 {
+			return types.NewDecFromInt(-randNum)
+		}
+		return types.NewDecFromInt(randNum)
+	case types.ETDatetime, types.ETTimestamp:
+		gt := types.FromDate(2000+int(randNum), int(randNum)+1, int(randNum)+1, 0, 0, 0, 0)
+		return types.NewTime(gt, convertETType(g.eType), 0)
+	case types.ETDuration:
+		return types.Duration{Duration: time.Duration(randNum) * time.Second}
 	case types.ETString:
 		return fmt.Sprint(randNum)
 	}
@@ -269,6 +289,9 @@ var inSigsTmpl = []sig{
 	{SigName: "builtinInIntSig", Input: TypeInt, Output: TypeInt},
 	{SigName: "builtinInStringSig", Input: TypeString, Output: TypeInt},
 	{SigName: "builtinInRealSig", Input: TypeReal, Output: TypeInt},
+	{SigName: "builtinInDecimalSig", Input: TypeDecimal, Output: TypeInt},
+	{SigName: "builtinInTimeSig", Input: TypeDatetime, Output: TypeInt},
+	{SigName: "builtinInDurationSig", Input: TypeDuration, Output: TypeInt},
 }
 
 type function struct {
@@ -0,0 +1,437 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build ignore
+
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"text/template"
+
+	. "github.com/pingcap/tidb/expression/generator/helper"
+)
+
+const header = `// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by go generate in expression/generator; DO NOT EDIT.
+
+package expression
+`
+
+const newLine = "\n"
+
+const builtinStringImports = `import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+`
+
+var builtinFieldTmpl = template.Must(template.New("builtinFieldTmpl").Parse(`
+{{ range . }}
+{{ $InputFixed := ( .Input.Fixed ) }}
+func (b *{{.SigName}}) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ET{{ .Input.ETName }}, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEval{{ .Input.TypeName }}(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	buf1, err := b.bufAllocator.get(types.ET{{ .Input.ETName }}, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	{{- if $InputFixed }}
+		args0 := buf0.{{.Input.TypeNameInColumn}}s()
+	{{- end }}
+
+	// FIELD() never returns NULL, it returns 0 if str is NULL or not found.
+	result.ResizeInt64(n, false)
+	r64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		r64s[i] = 0
+	}
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEval{{ .Input.TypeName }}(b.ctx, input, buf1); err != nil {
+			return err
+		}
+		{{- if $InputFixed }}
+			args1 := buf1.{{.Input.TypeNameInColumn}}s()
+		{{- end }}
+		for i := 0; i < n; i++ {
+			if r64s[i] > 0 || buf0.IsNull(i) || buf1.IsNull(i) {
+				continue
+			}
+		{{- if $InputFixed }}
+			if args0[i] == args1[i] {
+		{{- else }}
+			if buf0.Get{{ .Input.TypeName }}(i) == buf1.Get{{ .Input.TypeName }}(i) {
+		{{- end }}
+				r64s[i] = int64(j)
+			}
+		} // for i
+	} // for j
+	return nil
+}
+
+func (b *{{.SigName}}) vectorized() bool {
+	return true
+}
+{{ end }}{{/* range */}}
+`))
+
+var testFile = template.Must(template.New("").Parse(`// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by go generate in expression/generator; DO NOT EDIT.
+
+package expression
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+// utf8StrGener is used to generate strings mixed with multibyte characters whose lengths are in [lenBegin, lenEnd).
+type utf8StrGener struct {
+	lenBegin int
+	lenEnd   int
+}
+
+func (g *utf8StrGener) gen() interface{} {
+	if rand.Float64() < 0.1 {
+		return nil
+	}
+	candidates := []rune("aAbBcC01 ,.éÉ你好世界")
+	n := rand.Intn(g.lenEnd-g.lenBegin) + g.lenBegin
+	runes := make([]rune, n)
+	for i := range runes {
+		runes[i] = candidates[rand.Intn(len(candidates))]
+	}
+	return string(runes)
+}
+
+// newBinaryStrFieldType returns the field type of a binary string, to test the signatures for binary strings.
+func newBinaryStrFieldType() *types.FieldType {
+	ft := types.NewFieldType(mysql.TypeVarString)
+	types.SetBinChsClnFlag(ft)
+	return ft
+}
+
+{{/* Add more test cases here if we have more functions in this file */}}
+var vecBuiltin{{ .Category }}GeneratedCases = map[string][]vecExprBenchCase {
+{{- range $.Functions }}
+	ast.{{ .FuncName }}: {
+	{{- range .Cases }}
+		// {{ .SigName }}
+		{
+			retEvalType: types.ET{{ .Output.ETName }},
+			childrenTypes: []types.EvalType{
+			{{- range .Inputs }}
+				types.ET{{ .ETName }},
+			{{- end }}
+			},
+			{{- if .FieldTypes }}
+			childrenFieldTypes: []*types.FieldType{
+			{{- range .FieldTypes }}
+				{{ . }},
+			{{- end }}
+			},
+			{{- end }}
+			geners: []dataGenerator{
+			{{- range .Geners }}
+				{{ . }},
+			{{- end }}
+			},
+		},
+	{{- end }}
+	},
+{{- end }}
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltin{{.Category}}EvalOneVecGenerated(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltin{{.Category}}GeneratedCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltin{{.Category}}FuncGenerated(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltin{{.Category}}GeneratedCases)
+}
+
+func BenchmarkVectorizedBuiltin{{.Category}}EvalOneVecGenerated(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltin{{.Category}}GeneratedCases)
+}
+
+func BenchmarkVectorizedBuiltin{{.Category}}FuncGenerated(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltin{{.Category}}GeneratedCases)
+}
+`))
+
+type sig struct {
+	SigName       string
+	Input, Output TypeContext
+}
+
+var fieldSigsTmpl = []sig{
+	{SigName: "builtinFieldIntSig", Input: TypeInt, Output: TypeInt},
+	{SigName: "builtinFieldRealSig", Input: TypeReal, Output: TypeInt},
+	{SigName: "builtinFieldStringSig", Input: TypeString, Output: TypeInt},
+}
+
+// testCase describes a vecExprBenchCase of a signature, Geners and FieldTypes are go expressions.
+type testCase struct {
+	SigName    string
+	Inputs     []TypeContext
+	Output     TypeContext
+	Geners     []string
+	FieldTypes []string
+}
+
+type function struct {
+	FuncName string
+	Cases    []testCase
+}
+
+const (
+	binaryStr = "newBinaryStrFieldType()"
+	asciiStr  = "&randLenStrGener{0, 10}"
+	utf8Str   = "&utf8StrGener{0, 10}"
+)
+
+var tmplVal = struct {
+	Category  string
+	Functions []function
+}{
+	Category: "String",
+	Functions: []function{
+		{FuncName: "Concat", Cases: []testCase{
+			{SigName: "builtinConcatSig", Inputs: []TypeContext{TypeString, TypeString, TypeString}, Output: TypeString,
+				Geners: []string{utf8Str, asciiStr, utf8Str}},
+		}},
+		{FuncName: "ConcatWS", Cases: []testCase{
+			{SigName: "builtinConcatWSSig", Inputs: []TypeContext{TypeString, TypeString, TypeString, TypeString}, Output: TypeString,
+				Geners: []string{`&selectStringGener{[]string{",", "--", ""}}`, utf8Str, utf8Str, asciiStr}},
+		}},
+		{FuncName: "Substring", Cases: []testCase{
+			{SigName: "builtinSubstring2ArgsSig", Inputs: []TypeContext{TypeString, TypeInt}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-12, 12}"}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinSubstring3ArgsSig", Inputs: []TypeContext{TypeString, TypeInt, TypeInt}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-12, 12}", "&rangeInt64Gener{-2, 12}"}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinSubstring2ArgsUTF8Sig", Inputs: []TypeContext{TypeString, TypeInt}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-12, 12}"}},
+			{SigName: "builtinSubstring3ArgsUTF8Sig", Inputs: []TypeContext{TypeString, TypeInt, TypeInt}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-12, 12}", "&rangeInt64Gener{-2, 12}"}},
+		}},
+		{FuncName: "Left", Cases: []testCase{
+			{SigName: "builtinLeftSig", Inputs: []TypeContext{TypeString, TypeInt}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-2, 12}"}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinLeftUTF8Sig", Inputs: []TypeContext{TypeString, TypeInt}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-2, 12}"}},
+		}},
+		{FuncName: "Right", Cases: []testCase{
+			{SigName: "builtinRightSig", Inputs: []TypeContext{TypeString, TypeInt}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-2, 12}"}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinRightUTF8Sig", Inputs: []TypeContext{TypeString, TypeInt}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-2, 12}"}},
+		}},
+		{FuncName: "Upper", Cases: []testCase{
+			{SigName: "builtinUpperSig", Inputs: []TypeContext{TypeString}, Output: TypeString,
+				Geners: []string{utf8Str}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinUpperUTF8Sig", Inputs: []TypeContext{TypeString}, Output: TypeString,
+				Geners: []string{utf8Str}},
+		}},
+		{FuncName: "Lower", Cases: []testCase{
+			{SigName: "builtinLowerSig", Inputs: []TypeContext{TypeString}, Output: TypeString,
+				Geners: []string{utf8Str}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinLowerSig", Inputs: []TypeContext{TypeString}, Output: TypeString,
+				Geners: []string{utf8Str}},
+		}},
+		{FuncName: "Trim", Cases: []testCase{
+			{SigName: "builtinTrim1ArgSig", Inputs: []TypeContext{TypeString}, Output: TypeString,
+				Geners: []string{utf8Str}},
+			{SigName: "builtinTrim2ArgsSig", Inputs: []TypeContext{TypeString, TypeString}, Output: TypeString,
+				Geners: []string{utf8Str, `&selectStringGener{[]string{"a", "你", "", " "}}`}},
+			{SigName: "builtinTrim3ArgsSig", Inputs: []TypeContext{TypeString, TypeString, TypeInt}, Output: TypeString,
+				Geners: []string{utf8Str, utf8Str, "&rangeInt64Gener{0, 4}"}},
+		}},
+		{FuncName: "LTrim", Cases: []testCase{
+			{SigName: "builtinLTrimSig", Inputs: []TypeContext{TypeString}, Output: TypeString,
+				Geners: []string{utf8Str}},
+		}},
+		{FuncName: "RTrim", Cases: []testCase{
+			{SigName: "builtinRTrimSig", Inputs: []TypeContext{TypeString}, Output: TypeString,
+				Geners: []string{utf8Str}},
+		}},
+		{FuncName: "Replace", Cases: []testCase{
+			{SigName: "builtinReplaceSig", Inputs: []TypeContext{TypeString, TypeString, TypeString}, Output: TypeString,
+				Geners: []string{utf8Str, `&selectStringGener{[]string{"a", "你", "", " "}}`, utf8Str}},
+		}},
+		{FuncName: "Locate", Cases: []testCase{
+			{SigName: "builtinLocate2ArgsSig", Inputs: []TypeContext{TypeString, TypeString}, Output: TypeInt,
+				Geners: []string{`&selectStringGener{[]string{"a", "你", "", "é"}}`, utf8Str}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinLocate3ArgsSig", Inputs: []TypeContext{TypeString, TypeString, TypeInt}, Output: TypeInt,
+				Geners: []string{`&selectStringGener{[]string{"a", "你", "", "é"}}`, utf8Str, "&rangeInt64Gener{-2, 12}"}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinLocate2ArgsUTF8Sig", Inputs: []TypeContext{TypeString, TypeString}, Output: TypeInt,
+				Geners: []string{`&selectStringGener{[]string{"a", "你", "", "é"}}`, utf8Str}},
+			{SigName: "builtinLocate3ArgsUTF8Sig", Inputs: []TypeContext{TypeString, TypeString, TypeInt}, Output: TypeInt,
+				Geners: []string{`&selectStringGener{[]string{"a", "你", "", "é"}}`, utf8Str, "&rangeInt64Gener{-2, 12}"}},
+		}},
+		{FuncName: "Instr", Cases: []testCase{
+			{SigName: "builtinInstrSig", Inputs: []TypeContext{TypeString, TypeString}, Output: TypeInt,
+				Geners: []string{utf8Str, `&selectStringGener{[]string{"a", "你", "", "é"}}`}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinInstrUTF8Sig", Inputs: []TypeContext{TypeString, TypeString}, Output: TypeInt,
+				Geners: []string{utf8Str, `&selectStringGener{[]string{"a", "你", "", "é"}}`}},
+		}},
+		{FuncName: "Lpad", Cases: []testCase{
+			{SigName: "builtinLpadSig", Inputs: []TypeContext{TypeString, TypeInt, TypeString}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-2, 20}", `&selectStringGener{[]string{"-", "你好", ""}}`}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinLpadUTF8Sig", Inputs: []TypeContext{TypeString, TypeInt, TypeString}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-2, 20}", `&selectStringGener{[]string{"-", "你好", ""}}`}},
+		}},
+		{FuncName: "Rpad", Cases: []testCase{
+			{SigName: "builtinRpadSig", Inputs: []TypeContext{TypeString, TypeInt, TypeString}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-2, 20}", `&selectStringGener{[]string{"-", "你好", ""}}`}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinRpadUTF8Sig", Inputs: []TypeContext{TypeString, TypeInt, TypeString}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-2, 20}", `&selectStringGener{[]string{"-", "你好", ""}}`}},
+		}},
+		{FuncName: "Repeat", Cases: []testCase{
+			{SigName: "builtinRepeatSig", Inputs: []TypeContext{TypeString, TypeInt}, Output: TypeString,
+				Geners: []string{utf8Str, "&rangeInt64Gener{-2, 5}"}},
+		}},
+		{FuncName: "Reverse", Cases: []testCase{
+			{SigName: "builtinReverseSig", Inputs: []TypeContext{TypeString}, Output: TypeString,
+				Geners: []string{asciiStr}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinReverseUTF8Sig", Inputs: []TypeContext{TypeString}, Output: TypeString,
+				Geners: []string{utf8Str}},
+		}},
+		{FuncName: "CharLength", Cases: []testCase{
+			{SigName: "builtinCharLengthSig", Inputs: []TypeContext{TypeString}, Output: TypeInt,
+				Geners: []string{utf8Str}, FieldTypes: []string{binaryStr}},
+			{SigName: "builtinCharLengthUTF8Sig", Inputs: []TypeContext{TypeString}, Output: TypeInt,
+				Geners: []string{utf8Str}},
+		}},
+		{FuncName: "Hex", Cases: []testCase{
+			{SigName: "builtinHexStrArgSig", Inputs: []TypeContext{TypeString}, Output: TypeString,
+				Geners: []string{utf8Str}},
+			{SigName: "builtinHexIntArgSig", Inputs: []TypeContext{TypeInt}, Output: TypeString,
+				Geners: []string{"&defaultGener{0.2, types.ETInt}"}},
+		}},
+		{FuncName: "Unhex", Cases: []testCase{
+			{SigName: "builtinUnHexSig", Inputs: []TypeContext{TypeString}, Output: TypeString,
+				Geners: []string{`&selectStringGener{[]string{"4D7953514C", "a", "e4BDa0", "XYZ", ""}}`}},
+		}},
+		{FuncName: "Format", Cases: []testCase{
+			{SigName: "builtinFormatSig", Inputs: []TypeContext{TypeReal, TypeInt}, Output: TypeString,
+				Geners: []string{"&rangeRealGener{-1e7, 1e7, 0.2}", "&rangeInt64Gener{-2, 10}"}},
+			{SigName: "builtinFormatSig", Inputs: []TypeContext{TypeDecimal, TypeInt}, Output: TypeString,
+				Geners: []string{"&defaultGener{0.2, types.ETDecimal}", "&rangeInt64Gener{-2, 10}"}},
+			{SigName: "builtinFormatWithLocaleSig", Inputs: []TypeContext{TypeReal, TypeInt, TypeString}, Output: TypeString,
+				Geners: []string{"&rangeRealGener{-1e7, 1e7, 0.2}", "&rangeInt64Gener{-2, 10}", `&selectStringGener{[]string{"en_US"}}`}},
+		}},
+		{FuncName: "Field", Cases: []testCase{
+			{SigName: "builtinFieldIntSig", Inputs: []TypeContext{TypeInt, TypeInt, TypeInt, TypeInt}, Output: TypeInt,
+				Geners: []string{"&rangeInt64Gener{0, 3}", "&rangeInt64Gener{0, 3}", "&defaultGener{0.2, types.ETInt}", "&rangeInt64Gener{0, 3}"}},
+			{SigName: "builtinFieldRealSig", Inputs: []TypeContext{TypeReal, TypeReal, TypeReal}, Output: TypeInt,
+				Geners: []string{"&rangeRealGener{0, 1, 0.2}", "&rangeRealGener{0, 1, 0.2}", "&rangeRealGener{0, 1, 0.2}"}},
+			{SigName: "builtinFieldStringSig", Inputs: []TypeContext{TypeString, TypeString, TypeString, TypeString}, Output: TypeInt,
+				Geners: []string{`&selectStringGener{[]string{"a", "b", "你"}}`, `&selectStringGener{[]string{"a", "b"}}`, utf8Str, `&selectStringGener{[]string{"b", "你"}}`}},
+		}},
+		{FuncName: "Elt", Cases: []testCase{
+			{SigName: "builtinEltSig", Inputs: []TypeContext{TypeInt, TypeString, TypeString, TypeString}, Output: TypeString,
+				Geners: []string{"&rangeInt64Gener{-1, 5}", utf8Str, utf8Str, utf8Str}},
+		}},
+	},
+}
+
+func generateDotGo(fileName string) error {
+	w := new(bytes.Buffer)
+	w.WriteString(header)
+	w.WriteString(newLine)
+	w.WriteString(builtinStringImports)
+	err := builtinFieldTmpl.Execute(w, fieldSigsTmpl)
+	if err != nil {
+		return err
+	}
+	data, err := format.Source(w.Bytes())
+	if err != nil {
+		log.Println("[Warn]", fileName+": gofmt failed", err)
+		data = w.Bytes() // write original data for debugging
+	}
+	return ioutil.WriteFile(fileName, data, 0644)
+}
+
+func generateTestDotGo(fileName string) error {
+	w := new(bytes.Buffer)
+	err := testFile.Execute(w, tmplVal)
+	if err != nil {
+		return err
+	}
+	data, err := format.Source(w.Bytes())
+	if err != nil {
+		log.Println("[Warn]", fileName+": gofmt failed", err)
+		data = w.Bytes() // write original data for debugging
+	}
+	return ioutil.WriteFile(fileName, data, 0644)
+}
+
+// generateOneFile generate one xxx.go file and the associated xxx_test.go file.
+func generateOneFile(fileNamePrefix string) (err error) {
+	err = generateDotGo(fileNamePrefix + ".go")
+	if err != nil {
+		return
+	}
+	err = generateTestDotGo(fileNamePrefix + "_test.go")
+	return
+}
+
+func main() {
+	var err error
+	outputDir := "."
+	err = generateOneFile(filepath.Join(outputDir, "builtin_string_vec_generated"))
+	if err != nil {
+		log.Fatalln("generateOneFile", err)
+	}
+}
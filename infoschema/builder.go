@@ -56,14 +56,32 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 	case model.ActionCreateTable:
 		newTableID = diff.TableID
 		tblIDs = append(tblIDs, newTableID)
-	case model.ActionDropTable:
+	case model.ActionDropTable, model.ActionDropView:
 		oldTableID = diff.TableID
 		tblIDs = append(tblIDs, oldTableID)
+	case model.ActionCreateView:
+		newTableID = diff.TableID
+		tblIDs = append(tblIDs, newTableID)
+		if diff.OldTableID != 0 {
+			oldTableID = diff.OldTableID
+			tblIDs = append(tblIDs, oldTableID)
+		}
 	default:
 		oldTableID = diff.TableID
 		newTableID = diff.TableID
 		tblIDs = append(tblIDs, oldTableID)
 	}
+	// The transactions record the physical IDs of the partitions they write, so the partition IDs
+	// of the changed table are also returned.
+	if tableIDIsValid(oldTableID) {
+		if tbl, ok := b.is.TableByID(oldTableID); ok {
+			if pi := tbl.Meta().GetPartitionInfo(); pi != nil {
+				for _, def := range pi.Definitions {
+					tblIDs = append(tblIDs, def.ID)
+				}
+			}
+		}
+	}
 	dbInfo := b.copySchemaTables(roDBInfo.Name.L)
 	b.copySortedTables(oldTableID, newTableID)
 
@@ -357,8 +357,12 @@ func HasAutoIncrementColumn(tbInfo *model.TableInfo) (bool, string) {
 	return false, ""
 }
 
-// GetInfoSchema gets TxnCtx InfoSchema.
+// GetInfoSchema gets TxnCtx InfoSchema if snapshot schema is not set,
+// Otherwise, snapshot schema is returned.
 func GetInfoSchema(ctx sessionctx.Context) InfoSchema {
 	sessVar := ctx.GetSessionVars()
+	if snap := sessVar.SnapshotInfoschema; snap != nil {
+		return snap.(InfoSchema)
+	}
 	return sessVar.TxnCtx.InfoSchema.(InfoSchema)
 }
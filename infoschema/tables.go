@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
@@ -51,6 +53,7 @@ const (
 	tablePlugins                            = "PLUGINS"
 	tableConstraints                        = "TABLE_CONSTRAINTS"
 	tableTriggers                           = "TRIGGERS"
+	tableViews                              = "VIEWS"
 	tableUserPrivileges                     = "USER_PRIVILEGES"
 	tableSchemaPrivileges                   = "SCHEMA_PRIVILEGES"
 	tableTablePrivileges                    = "TABLE_PRIVILEGES"
@@ -90,6 +93,7 @@ var tableIDMap = map[string]int64{
 	tableTablePrivileges:                    autoid.InformationSchemaDBID + 20,
 	tableColumnPrivileges:                   autoid.InformationSchemaDBID + 21,
 	tableEngines:                            autoid.InformationSchemaDBID + 22,
+	tableViews:                              autoid.InformationSchemaDBID + 23,
 	tableRoutines:                           autoid.InformationSchemaDBID + 24,
 	tableParameters:                         autoid.InformationSchemaDBID + 25,
 	tableEvents:                             autoid.InformationSchemaDBID + 26,
@@ -184,6 +188,20 @@ var tablesCols = []columnInfo{
 	{"TIDB_ROW_ID_SHARDING_INFO", mysql.TypeVarchar, 255, 0, nil, nil},
 }
 
+// See: https://dev.mysql.com/doc/refman/5.7/en/views-table.html
+var tableViewsCols = []columnInfo{
+	{"TABLE_CATALOG", mysql.TypeVarchar, 512, mysql.NotNullFlag, nil, nil},
+	{"TABLE_SCHEMA", mysql.TypeVarchar, 64, mysql.NotNullFlag, nil, nil},
+	{"TABLE_NAME", mysql.TypeVarchar, 64, mysql.NotNullFlag, nil, nil},
+	{"VIEW_DEFINITION", mysql.TypeBlob, -1, mysql.NotNullFlag, nil, nil},
+	{"CHECK_OPTION", mysql.TypeVarchar, 8, mysql.NotNullFlag, nil, nil},
+	{"IS_UPDATABLE", mysql.TypeVarchar, 3, mysql.NotNullFlag, nil, nil},
+	{"DEFINER", mysql.TypeVarchar, 77, mysql.NotNullFlag, nil, nil},
+	{"SECURITY_TYPE", mysql.TypeVarchar, 7, mysql.NotNullFlag, nil, nil},
+	{"CHARACTER_SET_CLIENT", mysql.TypeVarchar, 32, mysql.NotNullFlag, nil, nil},
+	{"COLLATION_CONNECTION", mysql.TypeVarchar, 32, mysql.NotNullFlag, nil, nil},
+}
+
 // See: http://dev.mysql.com/doc/refman/5.7/en/columns-table.html
 var columnsCols = []columnInfo{
 	{"TABLE_CATALOG", mysql.TypeVarchar, 512, 0, nil, nil},
@@ -828,6 +846,35 @@ func dataForTables(ctx sessionctx.Context, schemas []*model.DBInfo) ([][]types.D
 			if collation == "" {
 				collation = mysql.DefaultCollationName
 			}
+			if table.IsView() {
+				record := types.MakeDatums(
+					catalogVal,    // TABLE_CATALOG
+					schema.Name.O, // TABLE_SCHEMA
+					table.Name.O,  // TABLE_NAME
+					"VIEW",        // TABLE_TYPE
+					nil,           // ENGINE
+					nil,           // VERSION
+					nil,           // ROW_FORMAT
+					nil,           // TABLE_ROWS
+					nil,           // AVG_ROW_LENGTH
+					nil,           // DATA_LENGTH
+					nil,           // MAX_DATA_LENGTH
+					nil,           // INDEX_LENGTH
+					nil,           // DATA_FREE
+					nil,           // AUTO_INCREMENT
+					nil,           // CREATE_TIME
+					nil,           // UPDATE_TIME
+					nil,           // CHECK_TIME
+					nil,           // TABLE_COLLATION
+					nil,           // CHECKSUM
+					nil,           // CREATE_OPTIONS
+					"VIEW",        // TABLE_COMMENT
+					table.ID,      // TIDB_TABLE_ID
+					nil,           // TIDB_ROW_ID_SHARDING_INFO
+				)
+				rows = append(rows, record)
+				continue
+			}
 
 			createOptions := ""
 
@@ -880,11 +927,147 @@ func dataForTables(ctx sessionctx.Context, schemas []*model.DBInfo) ([][]types.D
 	return rows, nil
 }
 
+func dataForPartitions(ctx sessionctx.Context, schemas []*model.DBInfo) ([][]types.Datum, error) {
+	tableRowsMap, colLengthMap, err := tableStatsCache.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			if table.IsView() {
+				continue
+			}
+			pi := table.GetPartitionInfo()
+			if pi == nil {
+				// A table that is not partitioned has one row with the partition columns set to NULL.
+				rowCount := tableRowsMap[table.ID]
+				dataLength, indexLength := getDataAndIndexLength(table, table.ID, rowCount, colLengthMap)
+				avgRowLength := uint64(0)
+				if rowCount != 0 {
+					avgRowLength = dataLength / rowCount
+				}
+				record := types.MakeDatums(
+					catalogVal,    // TABLE_CATALOG
+					schema.Name.O, // TABLE_SCHEMA
+					table.Name.O,  // TABLE_NAME
+					nil,           // PARTITION_NAME
+					nil,           // SUBPARTITION_NAME
+					nil,           // PARTITION_ORDINAL_POSITION
+					nil,           // SUBPARTITION_ORDINAL_POSITION
+					nil,           // PARTITION_METHOD
+					nil,           // SUBPARTITION_METHOD
+					nil,           // PARTITION_EXPRESSION
+					nil,           // SUBPARTITION_EXPRESSION
+					nil,           // PARTITION_DESCRIPTION
+					rowCount,      // TABLE_ROWS
+					avgRowLength,  // AVG_ROW_LENGTH
+					dataLength,    // DATA_LENGTH
+					uint64(0),     // MAX_DATA_LENGTH
+					indexLength,   // INDEX_LENGTH
+					uint64(0),     // DATA_FREE
+					nil,           // CREATE_TIME
+					nil,           // UPDATE_TIME
+					nil,           // CHECK_TIME
+					nil,           // CHECKSUM
+					"",            // PARTITION_COMMENT
+					nil,           // NODEGROUP
+					nil,           // TABLESPACE_NAME
+				)
+				rows = append(rows, record)
+				continue
+			}
+			for i, def := range pi.Definitions {
+				rowCount := tableRowsMap[def.ID]
+				dataLength, indexLength := getDataAndIndexLength(table, def.ID, rowCount, colLengthMap)
+				avgRowLength := uint64(0)
+				if rowCount != 0 {
+					avgRowLength = dataLength / rowCount
+				}
+				var description interface{}
+				if pi.Type == model.PartitionTypeRange {
+					description = strings.Join(def.LessThan, ",")
+				}
+				record := types.MakeDatums(
+					catalogVal,       // TABLE_CATALOG
+					schema.Name.O,    // TABLE_SCHEMA
+					table.Name.O,     // TABLE_NAME
+					def.Name.O,       // PARTITION_NAME
+					nil,              // SUBPARTITION_NAME
+					i+1,              // PARTITION_ORDINAL_POSITION
+					nil,              // SUBPARTITION_ORDINAL_POSITION
+					pi.Type.String(), // PARTITION_METHOD
+					nil,              // SUBPARTITION_METHOD
+					pi.Expr,          // PARTITION_EXPRESSION
+					nil,              // SUBPARTITION_EXPRESSION
+					description,      // PARTITION_DESCRIPTION
+					rowCount,         // TABLE_ROWS
+					avgRowLength,     // AVG_ROW_LENGTH
+					dataLength,       // DATA_LENGTH
+					uint64(0),        // MAX_DATA_LENGTH
+					indexLength,      // INDEX_LENGTH
+					uint64(0),        // DATA_FREE
+					nil,              // CREATE_TIME
+					nil,              // UPDATE_TIME
+					nil,              // CHECK_TIME
+					nil,              // CHECKSUM
+					def.Comment,      // PARTITION_COMMENT
+					nil,              // NODEGROUP
+					nil,              // TABLESPACE_NAME
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	return rows, nil
+}
+
+func dataForViews(schemas []*model.DBInfo) [][]types.Datum {
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			if !table.IsView() {
+				continue
+			}
+			charset, collation := ViewCharsetAndCollation(table)
+			record := types.MakeDatums(
+				catalogVal,            // TABLE_CATALOG
+				schema.Name.O,         // TABLE_SCHEMA
+				table.Name.O,          // TABLE_NAME
+				table.View.SelectStmt, // VIEW_DEFINITION
+				"CASCADED",            // CHECK_OPTION
+				"NO",                  // IS_UPDATABLE
+				"",                    // DEFINER
+				"DEFINER",             // SECURITY_TYPE
+				charset,               // CHARACTER_SET_CLIENT
+				collation,             // COLLATION_CONNECTION
+			)
+			rows = append(rows, record)
+		}
+	}
+	return rows
+}
+
+// ViewCharsetAndCollation returns the charset and collation of the view, they are inherited
+// from the database when the view is created.
+func ViewCharsetAndCollation(tbInfo *model.TableInfo) (string, string) {
+	cs, co := tbInfo.Charset, tbInfo.Collate
+	if cs == "" {
+		cs = mysql.DefaultCharset
+	}
+	if co == "" {
+		co, _ = charset.GetDefaultCollation(cs)
+	}
+	return cs, co
+}
+
 // GetShardingInfo returns a nil or description string for the sharding information of given TableInfo.
 // The returned description string may be:
-//  - "NOT_SHARDED": for tables that SHARD_ROW_ID_BITS is not specified.
-//  - "NOT_SHARDED(PK_IS_HANDLE)": for tables that is primary key is row id.
-//  - "SHARD_BITS={bit_number}": for tables that with SHARD_ROW_ID_BITS.
+//   - "NOT_SHARDED": for tables that SHARD_ROW_ID_BITS is not specified.
+//   - "NOT_SHARDED(PK_IS_HANDLE)": for tables that is primary key is row id.
+//   - "SHARD_BITS={bit_number}": for tables that with SHARD_ROW_ID_BITS.
+//
 // The returned nil indicates that sharding information is not suitable for the table(for example, when the table is a View).
 // This function is exported for unit test.
 func GetShardingInfo(dbInfo *model.DBInfo, tableInfo *model.TableInfo) interface{} {
@@ -1253,6 +1436,7 @@ var tableNameToColumns = map[string][]columnInfo{
 	tableTablePrivileges:                    tableTablePrivilegesCols,
 	tableColumnPrivileges:                   tableColumnPrivilegesCols,
 	tableEngines:                            tableEnginesCols,
+	tableViews:                              tableViewsCols,
 	tableRoutines:                           tableRoutinesCols,
 	tableParameters:                         tableParametersCols,
 	tableEvents:                             tableEventsCols,
@@ -1294,9 +1478,33 @@ func (s schemasSorter) Less(i, j int) bool {
 	return s[i].Name.L < s[j].Name.L
 }
 
+// visibleSchemas returns the databases and the tables which the current user
+// has any privilege on.
+func visibleSchemas(ctx sessionctx.Context, dbs []*model.DBInfo) []*model.DBInfo {
+	checker := privilege.GetPrivilegeManager(ctx)
+	if checker == nil {
+		return dbs
+	}
+	visible := make([]*model.DBInfo, 0, len(dbs))
+	for _, db := range dbs {
+		if !checker.DBIsVisible(db.Name.O) {
+			continue
+		}
+		dbCopy := *db
+		dbCopy.Tables = make([]*model.TableInfo, 0, len(db.Tables))
+		for _, tbl := range db.Tables {
+			if checker.RequestVerification(db.Name.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				dbCopy.Tables = append(dbCopy.Tables, tbl)
+			}
+		}
+		visible = append(visible, &dbCopy)
+	}
+	return visible
+}
+
 func (it *infoschemaTable) getRows(ctx sessionctx.Context, cols []*table.Column) (fullRows [][]types.Datum, err error) {
 	is := GetInfoSchema(ctx)
-	dbs := is.AllSchemas()
+	dbs := visibleSchemas(ctx, is.AllSchemas())
 	sort.Sort(schemasSorter(dbs))
 	switch it.meta.Name.O {
 	case tableSchemata:
@@ -1321,6 +1529,7 @@ func (it *infoschemaTable) getRows(ctx sessionctx.Context, cols []*table.Column)
 			fullRows = dataForPseudoProfiling()
 		}
 	case tablePartitions:
+		fullRows, err = dataForPartitions(ctx, dbs)
 	case tableKeyColumm:
 		fullRows = dataForKeyColumnUsage(dbs)
 	case tableReferConst:
@@ -1329,6 +1538,8 @@ func (it *infoschemaTable) getRows(ctx sessionctx.Context, cols []*table.Column)
 		fullRows = dataForUserPrivileges(ctx)
 	case tableEngines:
 		fullRows = dataForEngines()
+	case tableViews:
+		fullRows = dataForViews(dbs)
 	case tableRoutines:
 	// TODO: Fill the following tables.
 	case tableSchemaPrivileges:
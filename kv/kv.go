@@ -179,10 +179,20 @@ type Transaction interface {
 	SetVars(vars *Variables)
 }
 
+// Used for pessimistic lock wait time
+// these two constants are special for lock protocol with tikv
+// 0 means always wait, -1 means nowait, others meaning lock wait in milliseconds
+var (
+	LockAlwaysWait = int64(0)
+	LockNoWait     = int64(-1)
+)
+
 // LockCtx contains information for LockKeys method.
 type LockCtx struct {
-	Killed      *uint32
-	ForUpdateTS uint64
+	Killed        *uint32
+	ForUpdateTS   uint64
+	LockWaitTime  int64
+	WaitStartTime time.Time
 }
 
 // Client is used to send request to KV layer.
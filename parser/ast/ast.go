@@ -48,6 +48,8 @@ const (
 	FlagHasAggregateFunc
 	FlagHasVariable
 	FlagHasDefault
+	FlagHasSubquery
+	FlagHasWindowFunc
 )
 
 // ExprNode is a node that can be evaluated.
@@ -126,7 +128,7 @@ type ResultField struct {
 }
 
 // ResultSetNode interface has a ResultFields property, represents a Node that returns result set.
-// Implementations include SelectStmt, SubqueryExpr, TableSource, TableName and Join.
+// Implementations include SelectStmt, SetOprStmt, SubqueryExpr, TableSource, TableName and Join.
 type ResultSetNode interface {
 	Node
 }
@@ -351,6 +351,7 @@ type CreateTableStmt struct {
 	ReferTable  *TableName
 	Cols        []*ColumnDef
 	Constraints []*Constraint
+	Partition   *PartitionOptions
 }
 
 // Accept implements Node Accept interface.
@@ -390,6 +391,26 @@ func (n *CreateTableStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// PartitionOptions specifies the partition options.
+// See https://dev.mysql.com/doc/refman/5.7/en/partitioning-types.html
+type PartitionOptions struct {
+	Tp   model.PartitionType
+	Expr ExprNode
+	// Num is the number of partitions given by the PARTITIONS clause, it is 0 if not specified.
+	Num         uint64
+	Definitions []*PartitionDefinition
+}
+
+// PartitionDefinition defines a single partition.
+type PartitionDefinition struct {
+	Name model.CIStr
+	// LessThan is the upper bound of a RANGE partition, it is nil for a HASH partition.
+	LessThan []ExprNode
+	// MaxValue indicates the partition is defined by VALUES LESS THAN MAXVALUE.
+	MaxValue bool
+	Comment  string
+}
+
 // DropTableStmt is a statement to drop one or more tables.
 // See https://dev.mysql.com/doc/refman/5.7/en/drop-table.html
 type DropTableStmt struct {
@@ -418,6 +439,39 @@ func (n *DropTableStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// CreateViewStmt is a statement to create a View.
+// See https://dev.mysql.com/doc/refman/5.7/en/create-view.html
+type CreateViewStmt struct {
+	ddlNode
+
+	OrReplace bool
+	ViewName  *TableName
+	Cols      []model.CIStr
+	Select    StmtNode
+	// SchemaCols is the output column names of the view, it is filled by the planner.
+	SchemaCols []model.CIStr
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateViewStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateViewStmt)
+	node, ok := n.ViewName.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.ViewName = node.(*TableName)
+	selnode, ok := n.Select.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Select = selnode.(StmtNode)
+	return v.Leave(n)
+}
+
 // IndexKeyType is the type for index key.
 type IndexKeyType int
 
@@ -532,6 +586,9 @@ const (
 	AlterTableImportTablespace
 	AlterTableDiscardTablespace
 	AlterTableIndexInvisible
+	AlterTableAddPartitions
+	AlterTableDropPartition
+	AlterTableTruncatePartition
 	// TODO: Add more actions
 	AlterTableOrderByColumns
 )
@@ -564,6 +621,10 @@ type AlterTableSpec struct {
 	WithValidation bool
 	Num            uint64
 	Visibility     IndexVisibility
+	// PartDefinitions are the partitions to add for ADD PARTITION.
+	PartDefinitions []*PartitionDefinition
+	// PartitionNames are the partitions to drop or truncate for DROP PARTITION and TRUNCATE PARTITION.
+	PartitionNames []model.CIStr
 }
 
 // Accept implements Node Accept interface.
@@ -18,6 +18,11 @@ func HasAggFlag(expr ExprNode) bool {
 	return expr.GetFlag()&FlagHasAggregateFunc > 0
 }
 
+// HasWindowFlag checks if the expr contains FlagHasWindowFunc.
+func HasWindowFlag(expr ExprNode) bool {
+	return expr.GetFlag()&FlagHasWindowFunc > 0
+}
+
 // SetFlag sets flag for expression.
 func SetFlag(n Node) {
 	var setter flagSetter
@@ -39,24 +44,40 @@ func (f *flagSetter) Leave(in Node) (Node, bool) {
 		x.SetFlag(x.Expr.GetFlag() | x.Left.GetFlag() | x.Right.GetFlag())
 	case *BinaryOperationExpr:
 		x.SetFlag(x.L.GetFlag() | x.R.GetFlag())
+	case *CaseExpr:
+		f.caseExpr(x)
 	case *ColumnNameExpr:
 		x.SetFlag(FlagHasReference)
+	case *CompareSubqueryExpr:
+		x.SetFlag(x.L.GetFlag() | x.R.GetFlag())
 	case *DefaultExpr:
 		x.SetFlag(FlagHasDefault)
+	case *ExistsSubqueryExpr:
+		x.SetFlag(x.Sel.GetFlag())
 	case *FuncCallExpr:
 		f.funcCall(x)
+	case *FuncCastExpr:
+		x.SetFlag(FlagHasFunc | x.Expr.GetFlag())
 	case *IsNullExpr:
 		x.SetFlag(x.Expr.GetFlag())
 	case *ParenthesesExpr:
 		x.SetFlag(x.Expr.GetFlag())
 	case *PatternInExpr:
 		f.patternIn(x)
+	case *PatternLikeExpr:
+		f.patternLike(x)
+	case *PatternRegexpExpr:
+		f.patternRegexp(x)
 	case *RowExpr:
 		f.row(x)
+	case *SubqueryExpr:
+		x.SetFlag(FlagHasSubquery)
 	case *UnaryOperationExpr:
 		x.SetFlag(x.V.GetFlag())
 	case *ValuesExpr:
 		x.SetFlag(FlagHasReference)
+	case *WindowFuncExpr:
+		f.windowFunc(x)
 	case *VariableExpr:
 		if x.Value == nil {
 			x.SetFlag(FlagHasVariable)
@@ -68,11 +89,45 @@ func (f *flagSetter) Leave(in Node) (Node, bool) {
 	return in, true
 }
 
+func (f *flagSetter) caseExpr(x *CaseExpr) {
+	var flag uint64
+	if x.Value != nil {
+		flag |= x.Value.GetFlag()
+	}
+	for _, val := range x.WhenClauses {
+		flag |= val.Expr.GetFlag()
+		flag |= val.Result.GetFlag()
+	}
+	if x.ElseClause != nil {
+		flag |= x.ElseClause.GetFlag()
+	}
+	x.SetFlag(flag)
+}
+
 func (f *flagSetter) patternIn(x *PatternInExpr) {
 	flag := x.Expr.GetFlag()
 	for _, val := range x.List {
 		flag |= val.GetFlag()
 	}
+	if x.Sel != nil {
+		flag |= x.Sel.GetFlag()
+	}
+	x.SetFlag(flag)
+}
+
+func (f *flagSetter) patternLike(x *PatternLikeExpr) {
+	flag := x.Pattern.GetFlag()
+	if x.Expr != nil {
+		flag |= x.Expr.GetFlag()
+	}
+	x.SetFlag(flag)
+}
+
+func (f *flagSetter) patternRegexp(x *PatternRegexpExpr) {
+	flag := x.Pattern.GetFlag()
+	if x.Expr != nil {
+		flag |= x.Expr.GetFlag()
+	}
 	x.SetFlag(flag)
 }
 
@@ -99,3 +154,21 @@ func (f *flagSetter) aggregateFunc(x *AggregateFuncExpr) {
 	}
 	x.SetFlag(flag)
 }
+
+func (f *flagSetter) windowFunc(x *WindowFuncExpr) {
+	flag := FlagHasWindowFunc
+	for _, val := range x.Args {
+		flag |= val.GetFlag()
+	}
+	if x.Spec.PartitionBy != nil {
+		for _, item := range x.Spec.PartitionBy.Items {
+			flag |= item.Expr.GetFlag()
+		}
+	}
+	if x.Spec.OrderBy != nil {
+		for _, item := range x.Spec.OrderBy.Items {
+			flag |= item.Expr.GetFlag()
+		}
+	}
+	x.SetFlag(flag)
+}
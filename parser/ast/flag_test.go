@@ -51,3 +51,25 @@ func (ts *testFlagSuite) TestHasAggFlag(c *C) {
 		c.Assert(ast.HasAggFlag(expr), Equals, tt.hasAgg)
 	}
 }
+
+func (ts *testFlagSuite) TestHasWindowFlag(c *C) {
+	flagTests := []struct {
+		expr      string
+		hasWindow bool
+		hasAgg    bool
+	}{
+		{"sum(a)", false, true},
+		{"sum(a) over ()", true, false},
+		{"1 + row_number() over (order by a)", true, false},
+		{"sum(sum(a)) over ()", true, true},
+		{"rank() over (order by count(a))", true, true},
+	}
+	for _, tt := range flagTests {
+		stmt, err := ts.ParseOneStmt("select "+tt.expr, "", "")
+		c.Assert(err, IsNil)
+		expr := stmt.(*ast.SelectStmt).Fields.Fields[0].Expr
+		ast.SetFlag(expr)
+		c.Assert(ast.HasWindowFlag(expr), Equals, tt.hasWindow, Commentf("expr %v", tt.expr))
+		c.Assert(ast.HasAggFlag(expr), Equals, tt.hasAgg, Commentf("expr %v", tt.expr))
+	}
+}
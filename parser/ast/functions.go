@@ -18,11 +18,16 @@ import (
 	"io"
 
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/types"
 )
 
 var (
 	_ FuncNode = &AggregateFuncExpr{}
 	_ FuncNode = &FuncCallExpr{}
+	_ FuncNode = &FuncCastExpr{}
+
+	_ ExprNode = &TimeUnitExpr{}
+	_ ExprNode = &TrimDirectionExpr{}
 )
 
 // List scalar function names.
@@ -31,20 +36,36 @@ const (
 	Length      = "length"
 	Strcmp      = "strcmp"
 	OctetLength = "octet_length"
+	Like        = "like"
+	Regexp      = "regexp"
 	If          = "if"
 	Ifnull      = "ifnull"
+	Nullif      = "nullif"
+	Case        = "case"
+	Coalesce    = "coalesce"
+	Greatest    = "greatest"
+	Least       = "least"
 	LogicAnd    = "and"
 	LogicOr     = "or"
 	GE          = "ge"
 	LE          = "le"
 	EQ          = "eq"
 	NE          = "ne"
+	NullEQ      = "nulleq"
 	LT          = "lt"
 	GT          = "gt"
 	Plus        = "plus"
 	Minus       = "minus"
 	Div         = "div"
 	Mul         = "mul"
+	IntDiv      = "intdiv"
+	Mod         = "mod"
+	And         = "bitand"
+	Or          = "bitor"
+	Xor         = "bitxor"
+	LeftShift   = "leftshift"
+	RightShift  = "rightshift"
+	BitNeg      = "bitneg"
 	UnaryNot    = "not"
 	UnaryMinus  = "unaryminus"
 	In          = "in"
@@ -52,6 +73,101 @@ const (
 	SetVar      = "setvar"
 	GetVar      = "getvar"
 	Values      = "values"
+	Cast        = "cast"
+
+	// time functions
+	Now              = "now"
+	CurrentTimestamp = "current_timestamp"
+	DateFormat       = "date_format"
+	DateAdd          = "date_add"
+	DateSub          = "date_sub"
+	AddDate          = "adddate"
+	SubDate          = "subdate"
+	DateDiff         = "datediff"
+	Extract          = "extract"
+	UnixTimestamp    = "unix_timestamp"
+	FromUnixTime     = "from_unixtime"
+
+	// math functions
+	Abs      = "abs"
+	Acos     = "acos"
+	Asin     = "asin"
+	Atan     = "atan"
+	Atan2    = "atan2"
+	Ceil     = "ceil"
+	Ceiling  = "ceiling"
+	Conv     = "conv"
+	Cos      = "cos"
+	Cot      = "cot"
+	CRC32    = "crc32"
+	Degrees  = "degrees"
+	Exp      = "exp"
+	Floor    = "floor"
+	Ln       = "ln"
+	Log      = "log"
+	Log2     = "log2"
+	Log10    = "log10"
+	PI       = "pi"
+	Pow      = "pow"
+	Power    = "power"
+	Radians  = "radians"
+	Rand     = "rand"
+	Round    = "round"
+	Sign     = "sign"
+	Sin      = "sin"
+	Sqrt     = "sqrt"
+	Tan      = "tan"
+	Truncate = "truncate"
+
+	// string functions
+	CharLength      = "char_length"
+	CharacterLength = "character_length"
+	Concat          = "concat"
+	ConcatWS        = "concat_ws"
+	Convert         = "convert"
+	Elt             = "elt"
+	Field           = "field"
+	Format          = "format"
+	Hex             = "hex"
+	Instr           = "instr"
+	Lcase           = "lcase"
+	Left            = "left"
+	Locate          = "locate"
+	Lower           = "lower"
+	Lpad            = "lpad"
+	LTrim           = "ltrim"
+	Position        = "position"
+	Repeat          = "repeat"
+	Replace         = "replace"
+	Reverse         = "reverse"
+	Right           = "right"
+	RTrim           = "rtrim"
+	Rpad            = "rpad"
+	Substr          = "substr"
+	Substring       = "substring"
+	Trim            = "trim"
+	Ucase           = "ucase"
+	Unhex           = "unhex"
+	Upper           = "upper"
+
+	// regexp functions
+	RegexpLike    = "regexp_like"
+	RegexpSubstr  = "regexp_substr"
+	RegexpReplace = "regexp_replace"
+
+	// json functions
+	JSONType     = "json_type"
+	JSONExtract  = "json_extract"
+	JSONUnquote  = "json_unquote"
+	JSONArray    = "json_array"
+	JSONObject   = "json_object"
+	JSONSet      = "json_set"
+	JSONInsert   = "json_insert"
+	JSONReplace  = "json_replace"
+	JSONRemove   = "json_remove"
+	JSONContains = "json_contains"
+	JSONKeys     = "json_keys"
+	JSONLength   = "json_length"
 )
 
 // FuncCallExpr is for function expression.
@@ -92,6 +208,58 @@ func (n *FuncCallExpr) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// CastFunctionType is the type for cast function.
+type CastFunctionType int
+
+// CastFunction types
+const (
+	CastFunction CastFunctionType = iota + 1
+	CastConvertFunction
+)
+
+// FuncCastExpr is the cast function converting value to another type, e.g, cast(expr AS signed).
+// See https://dev.mysql.com/doc/refman/5.7/en/cast-functions.html
+type FuncCastExpr struct {
+	funcNode
+	// Expr is the expression to be converted.
+	Expr ExprNode
+	// Tp is the conversion type.
+	Tp *types.FieldType
+	// FunctionType is either Cast or Convert.
+	FunctionType CastFunctionType
+}
+
+// Format the ExprNode into a Writer.
+func (n *FuncCastExpr) Format(w io.Writer) {
+	switch n.FunctionType {
+	case CastFunction:
+		fmt.Fprint(w, "CAST(")
+		n.Expr.Format(w)
+		fmt.Fprint(w, " AS ")
+	case CastConvertFunction:
+		fmt.Fprint(w, "CONVERT(")
+		n.Expr.Format(w)
+		fmt.Fprint(w, ", ")
+	}
+	n.Tp.FormatAsCastType(w)
+	fmt.Fprint(w, ")")
+}
+
+// Accept implements Node Accept interface.
+func (n *FuncCastExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*FuncCastExpr)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	return v.Leave(n)
+}
+
 const (
 	// AggFuncCount is the name of Count function.
 	AggFuncCount = "count"
@@ -137,3 +305,229 @@ func (n *AggregateFuncExpr) Accept(v Visitor) (Node, bool) {
 	}
 	return v.Leave(n)
 }
+
+const (
+	// WindowFuncRowNumber is the name of row_number function.
+	WindowFuncRowNumber = "row_number"
+	// WindowFuncRank is the name of rank function.
+	WindowFuncRank = "rank"
+	// WindowFuncDenseRank is the name of dense_rank function.
+	WindowFuncDenseRank = "dense_rank"
+	// WindowFuncNtile is the name of ntile function.
+	WindowFuncNtile = "ntile"
+	// WindowFuncLead is the name of lead function.
+	WindowFuncLead = "lead"
+	// WindowFuncLag is the name of lag function.
+	WindowFuncLag = "lag"
+	// WindowFuncFirstValue is the name of first_value function.
+	WindowFuncFirstValue = "first_value"
+	// WindowFuncLastValue is the name of last_value function.
+	WindowFuncLastValue = "last_value"
+)
+
+// WindowFuncExpr represents window function expression.
+type WindowFuncExpr struct {
+	funcNode
+
+	// F is the function name.
+	F string
+	// Args is the function args.
+	Args []ExprNode
+	// Spec is the specification of this window.
+	Spec WindowSpec
+}
+
+// Format the ExprNode into a Writer.
+func (n *WindowFuncExpr) Format(w io.Writer) {
+	panic("Not implemented")
+}
+
+// Accept implements Node Accept interface.
+func (n *WindowFuncExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*WindowFuncExpr)
+	for i, val := range n.Args {
+		node, ok := val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Args[i] = node.(ExprNode)
+	}
+	node, ok := n.Spec.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Spec = *node.(*WindowSpec)
+	return v.Leave(n)
+}
+
+// TimeUnitType is the type for time and timestamp units.
+type TimeUnitType int
+
+const (
+	// TimeUnitInvalid is a placeholder for an invalid time or timestamp unit
+	TimeUnitInvalid TimeUnitType = iota
+	// TimeUnitMicrosecond is the time or timestamp unit MICROSECOND.
+	TimeUnitMicrosecond
+	// TimeUnitSecond is the time or timestamp unit SECOND.
+	TimeUnitSecond
+	// TimeUnitMinute is the time or timestamp unit MINUTE.
+	TimeUnitMinute
+	// TimeUnitHour is the time or timestamp unit HOUR.
+	TimeUnitHour
+	// TimeUnitDay is the time or timestamp unit DAY.
+	TimeUnitDay
+	// TimeUnitWeek is the time or timestamp unit WEEK.
+	TimeUnitWeek
+	// TimeUnitMonth is the time or timestamp unit MONTH.
+	TimeUnitMonth
+	// TimeUnitQuarter is the time or timestamp unit QUARTER.
+	TimeUnitQuarter
+	// TimeUnitYear is the time or timestamp unit YEAR.
+	TimeUnitYear
+	// TimeUnitSecondMicrosecond is the time unit SECOND_MICROSECOND.
+	TimeUnitSecondMicrosecond
+	// TimeUnitMinuteMicrosecond is the time unit MINUTE_MICROSECOND.
+	TimeUnitMinuteMicrosecond
+	// TimeUnitMinuteSecond is the time unit MINUTE_SECOND.
+	TimeUnitMinuteSecond
+	// TimeUnitHourMicrosecond is the time unit HOUR_MICROSECOND.
+	TimeUnitHourMicrosecond
+	// TimeUnitHourSecond is the time unit HOUR_SECOND.
+	TimeUnitHourSecond
+	// TimeUnitHourMinute is the time unit HOUR_MINUTE.
+	TimeUnitHourMinute
+	// TimeUnitDayMicrosecond is the time unit DAY_MICROSECOND.
+	TimeUnitDayMicrosecond
+	// TimeUnitDaySecond is the time unit DAY_SECOND.
+	TimeUnitDaySecond
+	// TimeUnitDayMinute is the time unit DAY_MINUTE.
+	TimeUnitDayMinute
+	// TimeUnitDayHour is the time unit DAY_HOUR.
+	TimeUnitDayHour
+	// TimeUnitYearMonth is the time unit YEAR_MONTH.
+	TimeUnitYearMonth
+)
+
+// String implements fmt.Stringer interface.
+func (unit TimeUnitType) String() string {
+	switch unit {
+	case TimeUnitMicrosecond:
+		return "MICROSECOND"
+	case TimeUnitSecond:
+		return "SECOND"
+	case TimeUnitMinute:
+		return "MINUTE"
+	case TimeUnitHour:
+		return "HOUR"
+	case TimeUnitDay:
+		return "DAY"
+	case TimeUnitWeek:
+		return "WEEK"
+	case TimeUnitMonth:
+		return "MONTH"
+	case TimeUnitQuarter:
+		return "QUARTER"
+	case TimeUnitYear:
+		return "YEAR"
+	case TimeUnitSecondMicrosecond:
+		return "SECOND_MICROSECOND"
+	case TimeUnitMinuteMicrosecond:
+		return "MINUTE_MICROSECOND"
+	case TimeUnitMinuteSecond:
+		return "MINUTE_SECOND"
+	case TimeUnitHourMicrosecond:
+		return "HOUR_MICROSECOND"
+	case TimeUnitHourSecond:
+		return "HOUR_SECOND"
+	case TimeUnitHourMinute:
+		return "HOUR_MINUTE"
+	case TimeUnitDayMicrosecond:
+		return "DAY_MICROSECOND"
+	case TimeUnitDaySecond:
+		return "DAY_SECOND"
+	case TimeUnitDayMinute:
+		return "DAY_MINUTE"
+	case TimeUnitDayHour:
+		return "DAY_HOUR"
+	case TimeUnitYearMonth:
+		return "YEAR_MONTH"
+	default:
+		return ""
+	}
+}
+
+// TimeUnitExpr is an expression representing a time or timestamp unit.
+type TimeUnitExpr struct {
+	exprNode
+	// Unit is the time or timestamp unit.
+	Unit TimeUnitType
+}
+
+// Format the ExprNode into a Writer.
+func (n *TimeUnitExpr) Format(w io.Writer) {
+	fmt.Fprint(w, n.Unit.String())
+}
+
+// Accept implements Node Accept interface.
+func (n *TimeUnitExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*TimeUnitExpr)
+	return v.Leave(n)
+}
+
+// TrimDirectionType is the type for trim direction.
+type TrimDirectionType int
+
+const (
+	// TrimBothDefault trims from both direction by default.
+	TrimBothDefault TrimDirectionType = iota
+	// TrimBoth trims from both direction with explicit notation.
+	TrimBoth
+	// TrimLeading trims from left.
+	TrimLeading
+	// TrimTrailing trims from right.
+	TrimTrailing
+)
+
+// String implements fmt.Stringer interface.
+func (direction TrimDirectionType) String() string {
+	switch direction {
+	case TrimBoth, TrimBothDefault:
+		return "BOTH"
+	case TrimLeading:
+		return "LEADING"
+	case TrimTrailing:
+		return "TRAILING"
+	default:
+		return ""
+	}
+}
+
+// TrimDirectionExpr is an expression representing the trim direction used in the TRIM() function.
+type TrimDirectionExpr struct {
+	exprNode
+	// Direction is the trim direction.
+	Direction TrimDirectionType
+}
+
+// Format the ExprNode into a Writer.
+func (n *TrimDirectionExpr) Format(w io.Writer) {
+	fmt.Fprint(w, n.Direction.String())
+}
+
+// Accept implements Node Accept interface.
+func (n *TrimDirectionExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*TrimDirectionExpr)
+	return v.Leave(n)
+}
@@ -16,7 +16,7 @@ package ast
 // IsReadOnly checks whether the input ast is readOnly.
 func IsReadOnly(node Node) bool {
 	switch st := node.(type) {
-	case *SelectStmt:
+	case *SelectStmt, *SetOprStmt:
 		checker := readOnlyChecker{
 			readOnly: true,
 		}
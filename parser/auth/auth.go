@@ -0,0 +1,36 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+)
+
+// UserIdentity represents username and hostname.
+type UserIdentity struct {
+	Username     string
+	Hostname     string
+	CurrentUser  bool
+	AuthUsername string // Username matched in privileges system
+	AuthHostname string // Match in privs system (i.e. could be a wildcard)
+}
+
+// String converts UserIdentity to the format user@host.
+func (user *UserIdentity) String() string {
+	// TODO: Escape username and hostname.
+	if user == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s@%s", user.Username, user.Hostname)
+}
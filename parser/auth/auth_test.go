@@ -0,0 +1,77 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testAuthSuite{})
+
+type testAuthSuite struct {
+}
+
+func (s *testAuthSuite) TestEncodePassword(c *C) {
+	pwd := "123"
+	c.Assert(EncodePassword(pwd), Equals, "*23AE809DDACAF96AF0FD78ED04B6A265E05AA257")
+	c.Assert(EncodePassword(""), Equals, "")
+}
+
+func (s *testAuthSuite) TestScrambledPassword(c *C) {
+	salt := []byte{0x33, 0x5c, 0x06, 0x33, 0x60, 0x64, 0x20, 0x34, 0x23, 0x16, 0x49, 0x0b, 0x60, 0x3c, 0x58, 0x2e, 0x1b, 0x15, 0x43, 0x6c}
+	hpwd, err := DecodePassword(EncodePassword("123"))
+	c.Assert(err, IsNil)
+	// The client sends xor(sha1(pwd), sha1(salt, sha1(sha1(pwd)))).
+	stage1 := Sha1Hash([]byte("123"))
+	scramble := Sha1Hash(append(append([]byte{}, salt...), hpwd...))
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+	c.Assert(CheckScrambledPassword(salt, hpwd, scramble), IsTrue)
+	c.Assert(CheckScrambledPassword(salt, hpwd, scramble[1:]), IsFalse)
+	scramble[0]++
+	c.Assert(CheckScrambledPassword(salt, hpwd, scramble), IsFalse)
+}
+
+func (s *testAuthSuite) TestSha256Crypt(c *C) {
+	// Test vector from https://www.akkadia.org/drepper/SHA-crypt.txt, which uses 5000 rounds by default.
+	hash := sha256crypt("Hello world!", []byte("saltstring"), 5000)
+	c.Assert(hash, Equals, "$A$005$saltstring5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5")
+}
+
+func (s *testAuthSuite) TestCheckShaPassword(c *C) {
+	pwhash := NewSha2Password("secret")
+	c.Assert(strings.HasPrefix(pwhash, "$A$005$"), IsTrue)
+	c.Assert(pwhash, Not(Equals), NewSha2Password("secret"))
+
+	ok, err := CheckShaPassword([]byte(pwhash), "secret")
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsTrue)
+	ok, err = CheckShaPassword([]byte(pwhash), "wrong")
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsFalse)
+
+	ok, err = CheckShaPassword([]byte(""), "")
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsTrue)
+	_, err = CheckShaPassword([]byte("*23AE809DDACAF96AF0FD78ED04B6A265E05AA257"), "123")
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,222 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+// Resources:
+// - https://dev.mysql.com/doc/refman/8.0/en/caching-sha2-pluggable-authentication.html
+// - https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_connection_phase_authentication_methods_caching_sha2_authentication_exchanges.html
+// - https://www.akkadia.org/drepper/SHA-crypt.txt
+//
+// The stored authentication string looks like '$A$005$<salt><hash>':
+// - "A" is the digest type, SHA-256.
+// - "005" is the number of iterations divided by iterationMultiplier.
+// - the salt is saltLength bytes, followed by the base64-like encoded hash.
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	mixChars            = 32
+	saltLength          = 20
+	iterationMultiplier = 1000
+	defaultIterations   = 5 * iterationMultiplier
+)
+
+var b64t = []byte("./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz")
+
+func b64From24bit(b []byte, n int, buf *bytes.Buffer) {
+	w := (int64(b[0]) << 16) | (int64(b[1]) << 8) | int64(b[2])
+	for n > 0 {
+		n--
+		buf.WriteByte(b64t[w&0x3f])
+		w >>= 6
+	}
+}
+
+// sha256Hash returns the SHA-256 digest of the concatenation of bs.
+func sha256Hash(bs ...[]byte) []byte {
+	h := sha256.New()
+	for _, b := range bs {
+		h.Write(b)
+	}
+	return h.Sum(nil)
+}
+
+// sha256crypt implements the SHA-crypt algorithm with the encoding used by MySQL.
+// The numbers in the comments refer to the steps in https://www.akkadia.org/drepper/SHA-crypt.txt.
+func sha256crypt(plaintext string, salt []byte, iterations int) string {
+	pwd := []byte(plaintext)
+
+	// 1, 2, 3
+	tmpA := sha256.New()
+	tmpA.Write(pwd)
+	tmpA.Write(salt)
+
+	// 4, 5, 6, 7, 8
+	sumB := sha256Hash(pwd, salt, pwd)
+
+	// 9, 10
+	var i int
+	for i = len(pwd); i > mixChars; i -= mixChars {
+		tmpA.Write(sumB[:mixChars])
+	}
+	tmpA.Write(sumB[:i])
+
+	// 11
+	for i = len(pwd); i > 0; i >>= 1 {
+		if i%2 == 0 {
+			tmpA.Write(pwd)
+		} else {
+			tmpA.Write(sumB)
+		}
+	}
+
+	// 12
+	sumA := tmpA.Sum(nil)
+
+	// 13, 14, 15
+	tmpDP := sha256.New()
+	for range pwd {
+		tmpDP.Write(pwd)
+	}
+	sumDP := tmpDP.Sum(nil)
+
+	// 16
+	p := make([]byte, 0, len(pwd))
+	for i = len(pwd); i > 0; i -= mixChars {
+		if i > mixChars {
+			p = append(p, sumDP...)
+		} else {
+			p = append(p, sumDP[:i]...)
+		}
+	}
+
+	// 17, 18, 19
+	tmpDS := sha256.New()
+	for i = 0; i < 16+int(sumA[0]); i++ {
+		tmpDS.Write(salt)
+	}
+	sumDS := tmpDS.Sum(nil)
+
+	// 20
+	s := make([]byte, 0, len(salt))
+	for i = len(salt); i > 0; i -= mixChars {
+		if i > mixChars {
+			s = append(s, sumDS...)
+		} else {
+			s = append(s, sumDS[:i]...)
+		}
+	}
+
+	// 21
+	sumC := sumA
+	tmpC := sha256.New()
+	for i = 0; i < iterations; i++ {
+		tmpC.Reset()
+		if i&1 != 0 {
+			tmpC.Write(p)
+		} else {
+			tmpC.Write(sumC)
+		}
+		if i%3 != 0 {
+			tmpC.Write(s)
+		}
+		if i%7 != 0 {
+			tmpC.Write(p)
+		}
+		if i&1 != 0 {
+			tmpC.Write(sumC)
+		} else {
+			tmpC.Write(p)
+		}
+		sumC = tmpC.Sum(nil)
+	}
+
+	// 22
+	buf := bytes.NewBuffer(make([]byte, 0, 100))
+	buf.WriteString("$A$")
+	fmt.Fprintf(buf, "%03d", iterations/iterationMultiplier)
+	buf.WriteByte('$')
+	buf.Write(salt)
+
+	b64From24bit([]byte{sumC[0], sumC[10], sumC[20]}, 4, buf)
+	b64From24bit([]byte{sumC[21], sumC[1], sumC[11]}, 4, buf)
+	b64From24bit([]byte{sumC[12], sumC[22], sumC[2]}, 4, buf)
+	b64From24bit([]byte{sumC[3], sumC[13], sumC[23]}, 4, buf)
+	b64From24bit([]byte{sumC[24], sumC[4], sumC[14]}, 4, buf)
+	b64From24bit([]byte{sumC[15], sumC[25], sumC[5]}, 4, buf)
+	b64From24bit([]byte{sumC[6], sumC[16], sumC[26]}, 4, buf)
+	b64From24bit([]byte{sumC[27], sumC[7], sumC[17]}, 4, buf)
+	b64From24bit([]byte{sumC[18], sumC[28], sumC[8]}, 4, buf)
+	b64From24bit([]byte{sumC[9], sumC[19], sumC[29]}, 4, buf)
+	b64From24bit([]byte{0, sumC[31], sumC[30]}, 3, buf)
+
+	return buf.String()
+}
+
+// CheckShaPassword checks a plaintext password against a caching_sha2_password authentication string.
+func CheckShaPassword(pwhash []byte, password string) (bool, error) {
+	if len(pwhash) == 0 {
+		return password == "", nil
+	}
+	// The salt may not contain '$', so the hash splits into exactly four parts.
+	pwhashParts := bytes.Split(pwhash, []byte("$"))
+	if len(pwhashParts) != 4 {
+		return false, errors.New("failed to decode hash parts")
+	}
+
+	hashType := string(pwhashParts[1])
+	if hashType != "A" {
+		return false, errors.New("digest type is incompatible")
+	}
+
+	iterations, err := strconv.Atoi(string(pwhashParts[2]))
+	if err != nil {
+		return false, errors.New("failed to decode iterations")
+	}
+	if len(pwhashParts[3]) < saltLength {
+		return false, errors.New("failed to decode salt")
+	}
+	salt := pwhashParts[3][:saltLength]
+
+	newHash := sha256crypt(password, salt, iterations*iterationMultiplier)
+	return bytes.Equal(pwhash, []byte(newHash)), nil
+}
+
+// NewSha2Password creates a caching_sha2_password authentication string with a random salt.
+func NewSha2Password(pwd string) string {
+	if len(pwd) == 0 {
+		return ""
+	}
+	salt := make([]byte, saltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		panic(err)
+	}
+
+	// Restrict the salt to the crypt alphabet, so it never contains '$' or characters
+	// that need quoting in SQL.
+	for i := range salt {
+		salt[i] = b64t[salt[i]&0x3f]
+	}
+
+	return sha256crypt(pwd, salt, defaultIterations)
+}
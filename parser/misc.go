@@ -133,6 +133,7 @@ func init() {
 	initTokenByte('=', eq)
 	initTokenByte('{', int('{'))
 	initTokenByte('}', int('}'))
+	initTokenByte('?', paramMarker)
 
 	initTokenString("||", pipes)
 	initTokenString("&&", andand)
@@ -274,6 +275,7 @@ var tokenMap = map[string]int{
 	"DELAYED":                  delayed,
 	"DELETE":                   deleteKwd,
 	"DEPTH":                    depth,
+	"DENSE_RANK":               denseRank,
 	"DESC":                     desc,
 	"DESCRIBE":                 describe,
 	"DIRECTORY":                directory,
@@ -320,6 +322,7 @@ var tokenMap = map[string]int{
 	"FAULTS":                   faultsSym,
 	"FIELDS":                   fields,
 	"FIRST":                    first,
+	"FIRST_VALUE":              firstValue,
 	"FIXED":                    fixed,
 	"FLOAT":                    floatType,
 	"FLUSH":                    flush,
@@ -379,6 +382,7 @@ var tokenMap = map[string]int{
 	"IO":                       io,
 	"IPC":                      ipc,
 	"INTEGER":                  integerType,
+	"INTERSECT":                intersect,
 	"INTERVAL":                 interval,
 	"INTERNAL":                 internal,
 	"INTO":                     into,
@@ -397,7 +401,10 @@ var tokenMap = map[string]int{
 	"KILL":                     kill,
 	"LABELS":                   labels,
 	"LANGUAGE":                 language,
+	"LAG":                      lag,
 	"LAST":                     last,
+	"LAST_VALUE":               lastValue,
+	"LEAD":                     lead,
 	"LEADING":                  leading,
 	"LEFT":                     left,
 	"LESS":                     less,
@@ -466,12 +473,14 @@ var tokenMap = map[string]int{
 	"NONE":                     none,
 	"NOORDER":                  noorder,
 	"NOT":                      not,
+	"NTILE":                    ntile,
 	"NOW":                      now,
 	"NULL":                     null,
 	"NULLS":                    nulls,
 	"NUMERIC":                  numericType,
 	"NCHAR":                    ncharType,
 	"NVARCHAR":                 nvarcharType,
+	"OF":                       of,
 	"OFFSET":                   offset,
 	"OLAP":                     hintOLAP,
 	"OLTP":                     hintOLTP,
@@ -484,6 +493,7 @@ var tokenMap = map[string]int{
 	"OR":                       or,
 	"ORDER":                    order,
 	"OUTER":                    outer,
+	"OVER":                     over,
 	"PACK_KEYS":                packKeys,
 	"PAGE":                     pageSym,
 	"PARSER":                   parser,
@@ -517,7 +527,9 @@ var tokenMap = map[string]int{
 	"SHARD_ROW_ID_BITS":        shardRowIDBits,
 	"PRE_SPLIT_REGIONS":        preSplitRegions,
 	"RANGE":                    rangeKwd,
+	"RANK":                     rank,
 	"RECOVER":                  recover,
+	"RECURSIVE":                recursive,
 	"REBUILD":                  rebuild,
 	"READ":                     read,
 	"READ_CONSISTENT_REPLICA":  hintReadConsistentReplica,
@@ -551,7 +563,9 @@ var tokenMap = map[string]int{
 	"ROUTINE":                  routine,
 	"ROW":                      row,
 	"ROW_COUNT":                rowCount,
+	"ROW_NUMBER":               rowNumber,
 	"ROW_FORMAT":               rowFormat,
+	"ROWS":                     rows,
 	"RTREE":                    rtree,
 	"SAMPLES":                  samples,
 	"SWAP_JOIN_INPUTS":         hintSJI,
@@ -720,6 +734,7 @@ var tokenMap = map[string]int{
 	"WHEN":                     when,
 	"WHERE":                    where,
 	"WIDTH":                    width,
+	"WINDOW":                   window,
 	"WITH":                     with,
 	"WITHOUT":                  without,
 	"WRITE":                    write,
@@ -407,7 +407,8 @@ type SchemaDiff struct {
 	SchemaID int64      `json:"schema_id"`
 	TableID  int64      `json:"table_id"`
 
-	// OldTableID is the table ID before truncate, only used by truncate table DDL.
+	// OldTableID is the table ID before truncate, only used by truncate table DDL and
+	// the replaced view of create or replace view DDL.
 	OldTableID int64 `json:"old_table_id"`
 	// OldSchemaID is the schema ID before rename table, only used by rename table DDL.
 	OldSchemaID int64 `json:"old_schema_id"`
@@ -224,6 +224,12 @@ type TableInfo struct {
 
 	// TiFlashReplica means the TiFlash replica info.
 	TiFlashReplica *TiFlashReplicaInfo `json:"tiflash_replica"`
+
+	// View is the view info, it is nil if the table is not a view.
+	View *ViewInfo `json:"view"`
+
+	// Partition is the partition info, it is nil if the table is not partitioned.
+	Partition *PartitionInfo `json:"partition"`
 }
 
 // TableLockInfo provides meta data describing a table lock.
@@ -351,6 +357,10 @@ func (t *TableInfo) Clone() *TableInfo {
 		nt.ForeignKeys[i] = t.ForeignKeys[i].Clone()
 	}
 
+	if t.Partition != nil {
+		nt.Partition = t.Partition.Clone()
+	}
+
 	return &nt
 }
 
@@ -436,6 +446,105 @@ func (t *TableInfo) IsLocked() bool {
 	return t.Lock != nil && len(t.Lock.Sessions) > 0
 }
 
+// IsView checks if TableInfo is a view.
+func (t *TableInfo) IsView() bool {
+	return t.View != nil
+}
+
+// GetPartitionInfo returns the partition information, it returns nil if the table is not partitioned.
+func (t *TableInfo) GetPartitionInfo() *PartitionInfo {
+	return t.Partition
+}
+
+// ViewInfo provides meta data describing a DB view.
+type ViewInfo struct {
+	// SelectStmt is the text of the SELECT statement of the view, the unqualified table
+	// names in it belong to the database of the view.
+	SelectStmt string `json:"view_select"`
+	// Cols are the column names of the view, they are the output column names of the
+	// SELECT statement if no column list is specified when creating the view.
+	Cols []CIStr `json:"view_cols"`
+}
+
+// PartitionType is the type for PartitionInfo.
+type PartitionType int
+
+// Partition types.
+const (
+	PartitionTypeRange PartitionType = 1
+	PartitionTypeHash  PartitionType = 2
+)
+
+// String implements fmt.Stringer interface.
+func (p PartitionType) String() string {
+	switch p {
+	case PartitionTypeRange:
+		return "RANGE"
+	case PartitionTypeHash:
+		return "HASH"
+	default:
+		return ""
+	}
+}
+
+// PartitionInfo provides table partition info.
+type PartitionInfo struct {
+	Type PartitionType `json:"type"`
+	// Expr is the partition expression, the rows are routed to the partitions by its value.
+	Expr        string                `json:"expr"`
+	Definitions []PartitionDefinition `json:"definitions"`
+	Num         uint64                `json:"num"`
+}
+
+// Clone clones PartitionInfo.
+func (pi *PartitionInfo) Clone() *PartitionInfo {
+	newPi := *pi
+	newPi.Definitions = make([]PartitionDefinition, len(pi.Definitions))
+	for i := range pi.Definitions {
+		newPi.Definitions[i] = pi.Definitions[i].Clone()
+	}
+	return &newPi
+}
+
+// GetNameByID gets the partition name by ID.
+func (pi *PartitionInfo) GetNameByID(id int64) string {
+	for _, def := range pi.Definitions {
+		if id == def.ID {
+			return def.Name.L
+		}
+	}
+	return ""
+}
+
+// FindPartitionDefinitionByName finds the offset of the partition definition by name, it returns -1 if not found.
+func (pi *PartitionInfo) FindPartitionDefinitionByName(partitionDefinitionName string) int {
+	lowConstrName := strings.ToLower(partitionDefinitionName)
+	for i, def := range pi.Definitions {
+		if def.Name.L == lowConstrName {
+			return i
+		}
+	}
+	return -1
+}
+
+// PartitionDefinition defines a single partition.
+type PartitionDefinition struct {
+	// ID is the physical table ID of the partition, the rows of the partition are encoded with it.
+	ID   int64 `json:"id"`
+	Name CIStr `json:"name"`
+	// LessThan is the upper bound of a RANGE partition, it is "MAXVALUE" for the unbounded one.
+	LessThan []string `json:"less_than"`
+	Comment  string   `json:"comment,omitempty"`
+}
+
+// Clone clones PartitionDefinition.
+func (ci PartitionDefinition) Clone() PartitionDefinition {
+	nci := ci
+	nci.LessThan = make([]string, len(ci.LessThan))
+	copy(nci.LessThan, ci.LessThan)
+	return nci
+}
+
 // NewExtraHandleColInfo mocks a column info for extra handle column.
 func NewExtraHandleColInfo() *ColumnInfo {
 	colInfo := &ColumnInfo{
@@ -39,6 +39,15 @@ const (
 	LocalInFileHeader byte = 0xfb
 )
 
+// Authentication packet headers and caching_sha2_password statuses.
+const (
+	AuthSwitchRequest             byte = 0xfe
+	AuthMoreData                  byte = 0x01
+	AuthRequestPublicKey          byte = 0x02
+	AuthFastAuthSuccess           byte = 0x03
+	AuthPerformFullAuthentication byte = 0x04
+)
+
 // Server information.
 const (
 	ServerStatusInTrans            uint16 = 0x0001
@@ -159,6 +168,10 @@ const (
 // Auth name information.
 const (
 	AuthName = "mysql_native_password"
+	// AuthNativePassword is the plugin name of mysql_native_password.
+	AuthNativePassword = "mysql_native_password"
+	// AuthCachingSha2Password is the plugin name of caching_sha2_password.
+	AuthCachingSha2Password = "caching_sha2_password"
 )
 
 // MySQL database and tables.
@@ -286,6 +299,9 @@ const MaxTypeSetMembers = 64
 // PWDHashLen is the length of password's hash.
 const PWDHashLen = 40
 
+// SHAPWDHashLen is the length of caching_sha2_password's authentication string.
+const SHAPWDHashLen = 70
+
 // Priv2UserCol is the privilege to mysql.user table column name.
 var Priv2UserCol = map[PrivilegeType]string{
 	CreatePriv:         "Create_priv",
@@ -900,12 +900,17 @@ const (
 	ErrInvalidJSONPathWildcard                                      = 3149
 	ErrInvalidJSONContainsPathType                                  = 3150
 	ErrJSONUsedAsKey                                                = 3152
+	ErrJSONDocumentNULLKey                                          = 3158
 	ErrBadUser                                                      = 3162
 	ErrUserAlreadyExists                                            = 3163
 	ErrInvalidJSONPathArrayCell                                     = 3165
 	ErrInvalidEncryptionOption                                      = 3184
 	ErrRoleNotGranted                                               = 3530
 	ErrLockAcquireFailAndNoWaitSet                                  = 3572
+	ErrCTERecursiveRequiresUnion                                    = 3573
+	ErrCTERecursiveRequiresNonRecursiveFirst                        = 3574
+	ErrCTERecursiveForbidsAggregation                               = 3575
+	ErrInvalidRequiresSingleReference                               = 3577
 	ErrWindowNoSuchWindow                                           = 3579
 	ErrWindowCircularityInWindowGraph                               = 3580
 	ErrWindowNoChildPartitioning                                    = 3581
@@ -927,6 +932,7 @@ const (
 	ErrWindowNoGroupOrderUnused                                     = 3597
 	ErrWindowExplainJson                                            = 3598
 	ErrWindowFunctionIgnoresFrame                                   = 3599
+	ErrCTEMaxRecursionDepth                                         = 3636
 	ErrDataTruncatedFunctionalIndex                                 = 3751
 	ErrDataOutOfRangeFunctionalIndex                                = 3752
 	ErrFunctionalIndexOnJsonOrGeometryFunction                      = 3753
@@ -1020,6 +1026,7 @@ const (
 	ErrSnapshotTooOld                      = 8055
 	ErrInvalidTableID                      = 8056
 	ErrInvalidType                         = 8057
+	ErrAsOf                                = 8135
 
 	// Error codes used by TiDB ddl package
 	ErrUnsupportedDDLOperation  = 8200
@@ -896,6 +896,7 @@ var MySQLErrName = map[uint16]string{
 	ErrInvalidJSONPathWildcard:                               "In this situation, path expressions may not contain the * and ** tokens.",
 	ErrInvalidJSONContainsPathType:                           "The second argument can only be either 'one' or 'all'.",
 	ErrJSONUsedAsKey:                                         "JSON column '%-.192s' cannot be used in key specification.",
+	ErrJSONDocumentNULLKey:                                   "JSON documents may not contain NULL member names.",
 	ErrBadUser:                                               "User %s does not exist.",
 	ErrUserAlreadyExists:                                     "User %s already exists.",
 	ErrInvalidJSONPathArrayCell:                              "A path expression is not a path to a cell in an array.",
@@ -921,9 +922,14 @@ var MySQLErrName = map[uint16]string{
 	ErrWindowNoGroupOrderUnused:                              "ASC or DESC with GROUP BY isn't allowed with window functions; put ASC or DESC in ORDER BY",
 	ErrWindowExplainJson:                                     "To get information about window functions use EXPLAIN FORMAT=JSON",
 	ErrWindowFunctionIgnoresFrame:                            "Window function '%s' ignores the frame clause of window '%s' and aggregates over the whole partition",
+	ErrCTEMaxRecursionDepth:                                  "Recursive query aborted after %d iterations. Try increasing @@cte_max_recursion_depth to a larger value.",
 	ErrRoleNotGranted:                                        "%s is is not granted to %s",
 	ErrMaxExecTimeExceeded:                                   "Query execution was interrupted, max_execution_time exceeded.",
 	ErrLockAcquireFailAndNoWaitSet:                           "Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set.",
+	ErrCTERecursiveRequiresUnion:                             "Recursive Common Table Expression '%s' should contain a UNION",
+	ErrCTERecursiveRequiresNonRecursiveFirst:                 "Recursive Common Table Expression '%s' should have one or more non-recursive query blocks followed by one or more recursive ones",
+	ErrCTERecursiveForbidsAggregation:                        "Recursive Common Table Expression '%s' can contain neither aggregation nor window functions in recursive query block",
+	ErrInvalidRequiresSingleReference:                        "In recursive query block of Recursive Common Table Expression '%s', the recursive table must be referenced only once, and not in any subquery",
 	ErrDataTruncatedFunctionalIndex:                          "Data truncated for functional index '%s' at row %d",
 	ErrDataOutOfRangeFunctionalIndex:                         "Value is out of range for functional index '%s' at row %d",
 	ErrFunctionalIndexOnJsonOrGeometryFunction:               "Cannot create a functional index on a function that returns a JSON or GEOMETRY value",
@@ -1013,6 +1019,7 @@ var MySQLErrName = map[uint16]string{
 	ErrUnknownFieldType:           "unknown field type",
 	ErrInvalidSequence:            "invalid sequence",
 	ErrInvalidType:                "invalid type",
+	ErrAsOf:                       "invalid as of timestamp: %s",
 	ErrCantGetValidID:             "cannot get valid auto-increment id in retry",
 	ErrCantSetToNull:              "cannot set variable to null",
 	ErrSnapshotTooOld:             "snapshot is older than GC safe point %s",
@@ -30,6 +30,7 @@ import (
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/opcode"
 	"github.com/pingcap/tidb/parser/charset"
@@ -105,6 +106,7 @@ import (
 	delayed			"DELAYED"
 	deleteKwd		"DELETE"
 	desc			"DESC"
+	denseRank		"DENSE_RANK"
 	describe		"DESCRIBE"
 	distinct		"DISTINCT"
 	distinctRow		"DISTINCTROW"
@@ -120,6 +122,7 @@ import (
 	except			"EXCEPT"
 	falseKwd		"FALSE"
 	floatType		"FLOAT"
+	firstValue		"FIRST_VALUE"
 	forKwd			"FOR"
 	force			"FORCE"
 	foreign			"FOREIGN"
@@ -140,6 +143,7 @@ import (
 	infile			"INFILE"
 	inner 			"INNER"
 	integerType		"INTEGER"
+	intersect		"INTERSECT"
 	interval		"INTERVAL"
 	into			"INTO"
 	is			"IS"
@@ -155,6 +159,9 @@ import (
 	keys			"KEYS"
 	kill			"KILL"
 	language		"LANGUAGE"
+	lag			"LAG"
+	lastValue		"LAST_VALUE"
+	lead			"LEAD"
 	leading			"LEADING"
 	left			"LEFT"
 	like			"LIKE"
@@ -177,10 +184,12 @@ import (
 	minuteSecond 		"MINUTE_SECOND"
 	mod 			"MOD"
 	not			"NOT"
+	ntile			"NTILE"
 	noWriteToBinLog 	"NO_WRITE_TO_BINLOG"
 	null			"NULL"
 	numericType		"NUMERIC"
 	nvarcharType		"NVARCHAR"
+	of			"OF"
 	on			"ON"
 	optimize		"OPTIMIZE"
 	option			"OPTION"
@@ -188,6 +197,7 @@ import (
 	or			"OR"
 	order			"ORDER"
 	outer			"OUTER"
+	over			"OVER"
 	packKeys		"PACK_KEYS"
 	partition		"PARTITION"
 	parser			"PARSER"
@@ -197,8 +207,10 @@ import (
 	shardRowIDBits		"SHARD_ROW_ID_BITS"
 	preSplitRegions		"PRE_SPLIT_REGIONS"
 	rangeKwd		"RANGE"
+	rank			"RANK"
 	read			"READ"
 	realType		"REAL"
+	recursive		"RECURSIVE"
 	references		"REFERENCES"
 	regexpKwd		"REGEXP"
 	rename         		"RENAME"
@@ -210,6 +222,8 @@ import (
 	right			"RIGHT"
 	rlike			"RLIKE"
 	row			"ROW"
+	rowNumber		"ROW_NUMBER"
+	rows			"ROWS"
 	secondMicrosecond	"SECOND_MICROSECOND"
 	selectKwd		"SELECT"
 	set			"SET"
@@ -255,6 +269,7 @@ import (
 	virtual			"VIRTUAL"
 	when			"WHEN"
 	where			"WHERE"
+	window			"WINDOW"
 	write			"WRITE"
 	with			"WITH"
 	xor 			"XOR"
@@ -682,6 +697,7 @@ import (
 	neq		"!="
 	neqSynonym	"<>"
 	nulleq		"<=>"
+	paramMarker	"?"
 	rsh		">>"
 
 %token not2
@@ -695,7 +711,9 @@ import (
 	BitExpr				"bit expression"
 	SimpleExpr			"simple expression"
 	SimpleIdent			"Simple Identifier expression"
+	SubSelect			"Sub Select"
 	SumExpr				"aggregate functions"
+	WindowFuncCall			"WINDOW function call"
 	FunctionCallGeneric		"Function call with Identifier"
 	FunctionCallKeyword		"Function call with keyword as function name"
 	FunctionCallNonKeyword		"Function call with nonkeyword as function name"
@@ -705,6 +723,7 @@ import (
 	UserVariable			"User defined variable name"
 	StringLiteral			"text literal"
 	ExpressionOpt			"Optional expression"
+	ElseOpt				"Optional else clause"
 	SignedLiteral			"Literal or NumLiteral with sign"
 	DefaultValueExpr		"DefaultValueExpr(Now or Signed Literal)"
 	NowSymOptionFraction		"NowSym with optional fraction part"
@@ -717,24 +736,38 @@ import (
 	BeginTransactionStmt		"BEGIN TRANSACTION statement"
 
 	CommitStmt			"COMMIT statement"
+	DeallocateStmt			"Deallocate prepared statement"
+	ExecuteStmt			"EXECUTE statement"
+	PreparedStmt			"PreparedStmt"
 	CreateTableStmt			"CREATE TABLE statement"
 	CreateDatabaseStmt		"Create Database Statement"
 	CreateIndexStmt			"CREATE INDEX statement"
+	CreateUserStmt			"CREATE User statement"
+	CreateViewStmt			"CREATE VIEW statement"
+	ViewSelectStmt			"SELECT statement of a view"
 	DropDatabaseStmt		"DROP DATABASE statement"
 	DropIndexStmt			"DROP INDEX statement"
+	DropViewStmt			"DROP VIEW statement"
 	DropTableStmt			"DROP TABLE statement"
+	DropUserStmt			"DROP USER"
+	GrantStmt			"Grant statement"
 	DeleteFromStmt			"DELETE FROM statement"
 	EmptyStmt			"empty statement"
 	ExplainStmt			"EXPLAIN statement"
 	ExplainableStmt			"explainable statement"
 	InsertIntoStmt			"INSERT INTO statement"
+	KillStmt			"Kill statement"
 	SelectStmt			"SELECT statement"
+	SelectStmtWithClause		"SELECT statement with WITH clause"
+	SetOprStmt			"Set operation statement, e.g. UNION/INTERSECT/EXCEPT"
 	ReplaceIntoStmt			"REPLACE INTO statement"
+	RevokeStmt			"Revoke statement"
 	RollbackStmt			"ROLLBACK statement"
 	SetStmt				"Set variable statement"
 	ShowStmt			"Show engines/databases/tables/user/columns/warnings/status statement"
 	Statement			"statement"
 	TruncateTableStmt		"TRUNCATE TABLE statement"
+	UpdateStmt			"UPDATE statement"
 	UseStmt				"USE statement"
 
 %type   <item>
@@ -743,10 +776,22 @@ import (
 	AlterTableSpecListOpt		"Alter table specification list optional"
 	AnyOrAll			"Any or All for subquery"
 	Assignment			"assignment"
+	AsOfClause			"AS OF TIMESTAMP clause"
 	AssignmentList			"assignment list"
 	AssignmentListOpt		"assignment list opt"
+	AuthOption			"User auth option"
+	AuthString			"Password string value"
 	OptionalBraces			"optional braces"
+	OptLeadLagInfo			"Optional LEAD/LAG info"
+	OptLLDefault			"Optional LEAD/LAG default value"
+	OptPartitionClause		"Optional PARTITION BY clause of window"
+	OptWindowFrameClause		"Optional frame clause of window"
+	OptWindowOrderByClause		"Optional ORDER BY clause of window"
+	OptExistingWindowName		"Optional existing window name"
+	OptWindowingClause		"Optional OVER clause"
 	CastType			"Cast function target type"
+	WhenClause			"When clause"
+	WhenClauseList			"When clause list"
 	CharsetName			"Character set name"
 	CollationName			"Collation name"
 	ColumnDef			"table column definition"
@@ -786,6 +831,7 @@ import (
 	TableRefsClause			"Table references clause"
 	FuncDatetimePrec		"Function datetime precision"
 	GlobalScope			"The scope of variable"
+	HashString			"Hashed string"
 	GroupByClause			"GROUP BY clause"
 	HavingClause			"HAVING clause"
 	IfExists			"If Exists"
@@ -826,7 +872,12 @@ import (
 	ByList				"BY list"
 	QuickOptional			"QUICK or empty"
 	QueryBlockOpt			"Query block identifier optional"
+	PrepareSQL			"Prepare statement sql string"
 	PriorityOpt			"Statement priority option"
+	PrivElem			"Privilege element"
+	PrivElemList			"Privilege element list"
+	PrivLevel			"Privilege scope"
+	PrivType			"Privilege type"
 	OptGConcatSeparator		"optional GROUP_CONCAT SEPARATOR"
 	RowValue			"Row value"
 	SelectStmtCalcFoundRows		"SELECT statement optional SQL_CALC_FOUND_ROWS"
@@ -837,15 +888,33 @@ import (
 	SelectStmtStraightJoin		"SELECT statement optional STRAIGHT_JOIN"
 	SelectStmtFieldList		"SELECT statement field list"
 	SelectStmtLimit			"SELECT statement optional LIMIT clause"
+	SelectLockOpt			"SELECT lock options"
+	CommonTableExpr			"Common table expression"
+	IdentList			"Identifier list"
+	IdentListWithParenOpt		"Optional identifier list with parentheses"
+	WithClause			"WITH clause"
+	WithList			"WITH clause list of common table expressions"
 	SelectStmtOpts			"Select statement options"
 	SelectStmtBasic			"SELECT statement from constant value"
 	SelectStmtFromDualTable			"SELECT statement from dual table"
 	SelectStmtFromTable			"SELECT statement from table"
 	SelectStmtGroup			"SELECT statement optional GROUP BY clause"
+	SetOpr				"Set operator, e.g. UNION/INTERSECT/EXCEPT"
+	SetOprClauseList		"Set operation clause list"
+	SetOprSelect			"Select statement in a set operation"
+	OrReplace			"Optional OR REPLACE"
+	PartitionOpt			"Partition option"
+	PartitionNumOpt			"PARTITIONS option"
+	PartitionDefinitionListOpt	"Partition definition list option"
+	PartitionDefinitionList		"Partition definition list"
+	PartitionDefinition		"Partition definition"
+	PartDefValuesOpt		"VALUES LESS THAN option of partition definition"
+	PartDefCommentOpt		"COMMENT option of partition definition"
 	ShowTargetFilterable    	"Show target that can be filtered by WHERE or LIKE"
 	ShowDatabaseNameOpt		"Show tables/columns statement database name option"
 	ShowTableAliasOpt       	"Show table alias option"
 	ShowLikeOrWhereOpt		"Show like or where clause option"
+	ObjectType			"Grant statement object type"
 	StatementList			"statement list"
 	StringName			"string literal or identifier"
 	StringList 			"string list"
@@ -863,7 +932,15 @@ import (
 	TableNameListOpt		"Table name list opt"
 	TableRef 			"table reference"
 	TableRefs 			"table references"
-
+	TimeUnit			"Time unit for 'DATE_ADD', 'DATE_SUB', 'ADDDATE', 'SUBDATE', 'EXTRACT'"
+	TrimDirection			"Trim string direction"
+
+	Username			"Username"
+	UsernameList			"UsernameList"
+	UserSpec			"Username and auth option"
+	UserSpecList			"Username and auth option list"
+	UserVariableList		"User defined variable name list"
+	WithGrantOptionOpt		"With Grant Option opt"
 	Values			"values"
 	ValuesList		"values list"
 	ValuesOpt		"values optional"
@@ -871,6 +948,17 @@ import (
 	VariableAssignmentList	"set variable value list"
 	WhereClause		"WHERE clause"
 	WhereClauseOptional	"Optional WHERE clause"
+	WindowFrameBound		"Window frame bound"
+	WindowFrameExtent		"Window frame extent"
+	WindowFrameStart		"Window frame start"
+	WindowFrameUnits		"Window frame units"
+	WindowingClause			"Window OVER clause"
+	WindowClauseOptional		"Optional WINDOW clause"
+	WindowDefinition		"Window definition"
+	WindowDefinitionList		"Window definition list"
+	WindowName			"Window name"
+	WindowSpec			"Window specification"
+	WindowSpecDetails		"Window specification details"
 	WithValidation		"with validation"
 	WithValidationOpt	"optional with validation"
 	Type			"Types"
@@ -880,6 +968,8 @@ import (
 	BetweenOrNotOp		"Between predicate"
 	IsOrNotOp		"Is predicate"
 	InOrNotOp		"In predicate"
+	LikeOrNotOp		"Like predicate"
+	RegexpOrNotOp		"Regexp predicate"
 
 	NumericType		"Numeric types"
 	IntegerType		"Integer Types types"
@@ -924,12 +1014,14 @@ import (
 %type	<ident>
 	AsOpt			"AS or EmptyString"
 	KeyOrIndex		"{KEY|INDEX}"
+	RegexpSym		"REGEXP or RLIKE"
 	ColumnKeywordOpt	"Column keyword or empty"
 	PrimaryOpt		"Optional primary keyword"
 	NowSym			"CURRENT_TIMESTAMP/LOCALTIME/LOCALTIMESTAMP"
 	NowSymFunc		"CURRENT_TIMESTAMP/LOCALTIME/LOCALTIMESTAMP/NOW"
 	DefaultKwdOpt		"optional DEFAULT keyword"
 	DatabaseSym		"DATABASE or SCHEMA"
+	DeallocateSym		"Deallocate or drop"
 	ExplainSym		"EXPLAIN or DESCRIBE or DESC"
 	IntoOpt			"INTO or EmptyString"
 	ValueSym		"Value or Values"
@@ -950,6 +1042,7 @@ import (
 	logAnd			"logical and operator"
 	logOr			"logical or operator"
 	StorageMedia		"{DISK|MEMORY|DEFAULT}"
+	AuthPlugin		"Authentication plugin name"
 
 %type	<ident>
 	Identifier			"identifier or unreserved keyword"
@@ -1101,6 +1194,28 @@ AlterTableSpec:
 	{
 		$$ = &ast.AlterTableSpec{Tp: ast.AlterTableDropPrimaryKey}
 	}
+|	"ADD" "PARTITION" '(' PartitionDefinitionList ')'
+	{
+		$$ = &ast.AlterTableSpec{
+			Tp:              ast.AlterTableAddPartitions,
+			PartDefinitions: $4.([]*ast.PartitionDefinition),
+		}
+	}
+|	"DROP" "PARTITION" IfExists IdentList %prec lowerThanComma
+	{
+		$$ = &ast.AlterTableSpec{
+			IfExists:       $3.(bool),
+			Tp:             ast.AlterTableDropPartition,
+			PartitionNames: $4.([]model.CIStr),
+		}
+	}
+|	"TRUNCATE" "PARTITION" IdentList %prec lowerThanComma
+	{
+		$$ = &ast.AlterTableSpec{
+			Tp:             ast.AlterTableTruncatePartition,
+			PartitionNames: $3.([]model.CIStr),
+		}
+	}
 |	"IMPORT" "TABLESPACE"
     {
         ret := &ast.AlterTableSpec{
@@ -1401,6 +1516,18 @@ BeginTransactionStmt:
 	{
 		$$ = &ast.BeginStmt{}
 	}
+|	"BEGIN" "PESSIMISTIC"
+	{
+		$$ = &ast.BeginStmt{
+			Mode: ast.Pessimistic,
+		}
+	}
+|	"BEGIN" "OPTIMISTIC"
+	{
+		$$ = &ast.BeginStmt{
+			Mode: ast.Optimistic,
+		}
+	}
 |	"START" "TRANSACTION"
 	{
 		$$ = &ast.BeginStmt{}
@@ -1994,12 +2121,15 @@ DatabaseOptionList:
  *******************************************************************/
 
 CreateTableStmt:
-	"CREATE" OptTemporary "TABLE" IfNotExists TableName TableElementListOpt AsOpt
+	"CREATE" OptTemporary "TABLE" IfNotExists TableName TableElementListOpt PartitionOpt AsOpt
 	{
 		stmt := $6.(*ast.CreateTableStmt)
 		stmt.Table = $5.(*ast.TableName)
 		stmt.IfNotExists = $4.(bool)
 		stmt.IsTemporary = $2.(bool)
+		if $7 != nil {
+			stmt.Partition = $7.(*ast.PartitionOptions)
+		}
 		$$ = stmt
 	}
 |	"CREATE" OptTemporary "TABLE" IfNotExists TableName LikeTableWithOrWithoutParen
@@ -2012,6 +2142,130 @@ CreateTableStmt:
 		}
 	}
 
+PartitionOpt:
+	{
+		$$ = nil
+	}
+|	"PARTITION" "BY" "RANGE" '(' Expression ')' PartitionNumOpt PartitionDefinitionListOpt
+	{
+		$$ = &ast.PartitionOptions{
+			Tp:          model.PartitionTypeRange,
+			Expr:        $5,
+			Num:         $7.(uint64),
+			Definitions: $8.([]*ast.PartitionDefinition),
+		}
+	}
+|	"PARTITION" "BY" "HASH" '(' Expression ')' PartitionNumOpt PartitionDefinitionListOpt
+	{
+		$$ = &ast.PartitionOptions{
+			Tp:          model.PartitionTypeHash,
+			Expr:        $5,
+			Num:         $7.(uint64),
+			Definitions: $8.([]*ast.PartitionDefinition),
+		}
+	}
+
+PartitionNumOpt:
+	{
+		$$ = uint64(0)
+	}
+|	"PARTITIONS" LengthNum
+	{
+		$$ = $2
+	}
+
+PartitionDefinitionListOpt:
+	{
+		var defs []*ast.PartitionDefinition
+		$$ = defs
+	}
+|	'(' PartitionDefinitionList ')'
+	{
+		$$ = $2
+	}
+
+PartitionDefinitionList:
+	PartitionDefinition
+	{
+		$$ = []*ast.PartitionDefinition{$1.(*ast.PartitionDefinition)}
+	}
+|	PartitionDefinitionList ',' PartitionDefinition
+	{
+		$$ = append($1.([]*ast.PartitionDefinition), $3.(*ast.PartitionDefinition))
+	}
+
+PartitionDefinition:
+	"PARTITION" Identifier PartDefValuesOpt PartDefCommentOpt
+	{
+		partDef := $3.(*ast.PartitionDefinition)
+		partDef.Name = model.NewCIStr($2)
+		partDef.Comment = $4.(string)
+		$$ = partDef
+	}
+
+PartDefValuesOpt:
+	{
+		$$ = &ast.PartitionDefinition{}
+	}
+|	"VALUES" "LESS" "THAN" "MAXVALUE"
+	{
+		$$ = &ast.PartitionDefinition{MaxValue: true}
+	}
+|	"VALUES" "LESS" "THAN" '(' "MAXVALUE" ')'
+	{
+		$$ = &ast.PartitionDefinition{MaxValue: true}
+	}
+|	"VALUES" "LESS" "THAN" '(' ExpressionList ')'
+	{
+		$$ = &ast.PartitionDefinition{LessThan: $5.([]ast.ExprNode)}
+	}
+
+PartDefCommentOpt:
+	{
+		$$ = ""
+	}
+|	"COMMENT" EqOpt stringLit
+	{
+		$$ = $3
+	}
+
+/*******************************************************************
+ *
+ *  Create View Statement
+ *
+ *  Example:
+ *      CREATE OR REPLACE VIEW v (a, b) AS SELECT c, d FROM t
+ *******************************************************************/
+CreateViewStmt:
+	"CREATE" OrReplace "VIEW" TableName IdentListWithParenOpt "AS" ViewSelectStmt
+	{
+		startOffset := parser.startOffset(&yyS[yypt])
+		selStmt := $7
+		// The lookahead token ends the statement, so the text of the SELECT statement
+		// is everything before it.
+		selStmt.SetText(strings.TrimSpace(parser.src[startOffset:parser.lexer.lastScanOffset]))
+		$$ = &ast.CreateViewStmt{
+			OrReplace: $2.(bool),
+			ViewName:  $4.(*ast.TableName),
+			Cols:      $5.([]model.CIStr),
+			Select:    selStmt,
+		}
+	}
+
+OrReplace:
+	{
+		$$ = false
+	}
+|	"OR" "REPLACE"
+	{
+		$$ = true
+	}
+
+ViewSelectStmt:
+	SelectStmt
+|	SelectStmtWithClause
+|	SetOprStmt
+
 DefaultKwdOpt:
 	%prec lowerThanCharsetKwd
 	{}
@@ -2097,6 +2351,12 @@ DropTableStmt:
 		$$ = &ast.DropTableStmt{IfExists: $4.(bool), Tables: $5.([]*ast.TableName), IsView: false, IsTemporary: $2.(bool)}
 	}
 
+DropViewStmt:
+	"DROP" "VIEW" IfExists TableNameList RestrictOrCascadeOpt
+	{
+		$$ = &ast.DropTableStmt{IfExists: $3.(bool), Tables: $4.([]*ast.TableName), IsView: true}
+	}
+
 OptTemporary:
 	  /* empty */ { $$ = false; }
 	| "TEMPORARY" 
@@ -2125,6 +2385,67 @@ EmptyStmt:
 		$$ = nil
 	}
 
+/****************************Prepared Statement*******************************/
+PreparedStmt:
+	"PREPARE" Identifier "FROM" PrepareSQL
+	{
+		var sqlText string
+		var sqlVar *ast.VariableExpr
+		switch x := $4.(type) {
+		case string:
+			sqlText = x
+		case *ast.VariableExpr:
+			sqlVar = x
+		}
+		$$ = &ast.PrepareStmt{
+			Name:    $2,
+			SQLText: sqlText,
+			SQLVar:  sqlVar,
+		}
+	}
+
+PrepareSQL:
+	stringLit
+	{
+		$$ = $1
+	}
+|	UserVariable
+	{
+		$$ = $1.(interface{})
+	}
+
+ExecuteStmt:
+	"EXECUTE" Identifier
+	{
+		$$ = &ast.ExecuteStmt{Name: $2}
+	}
+|	"EXECUTE" Identifier "USING" UserVariableList
+	{
+		$$ = &ast.ExecuteStmt{
+			Name:      $2,
+			UsingVars: $4.([]ast.ExprNode),
+		}
+	}
+
+UserVariableList:
+	UserVariable
+	{
+		$$ = []ast.ExprNode{$1}
+	}
+|	UserVariableList ',' UserVariable
+	{
+		$$ = append($1.([]ast.ExprNode), $3)
+	}
+
+DeallocateStmt:
+	DeallocateSym "PREPARE" Identifier
+	{
+		$$ = &ast.DeallocateStmt{Name: $3}
+	}
+
+DeallocateSym:
+	"DEALLOCATE" | "DROP"
+
 ExplainSym:
 "EXPLAIN" | "DESCRIBE" | "DESC"
 
@@ -2150,6 +2471,14 @@ ExplainStmt:
 			Format: $4.(string),
 		}
 	}
+|	ExplainSym "ANALYZE" ExplainableStmt
+	{
+		$$ = &ast.ExplainStmt{
+			Stmt:	 $3,
+			Format:	 "row",
+			Analyze: true,
+		}
+	}
 
 ExplainFormatType:
 	"TRADITIONAL"
@@ -2196,7 +2525,13 @@ Expression:
 	}
 |	"NOT" Expression %prec not
 	{
-		$$ = &ast.UnaryOperationExpr{Op: opcode.Not, V: $2}
+		expr, ok := $2.(*ast.ExistsSubqueryExpr)
+		if ok {
+			expr.Not = !expr.Not
+			$$ = $2
+		} else {
+			$$ = &ast.UnaryOperationExpr{Op: opcode.Not, V: $2}
+		}
 	}
 |	BoolPri IsOrNotOp "UNKNOWN" %prec is
 	{
@@ -2253,6 +2588,12 @@ BoolPri:
 	{
 		$$ = &ast.BinaryOperationExpr{Op: $2.(opcode.Op), L: $1, R: $3}
 	}
+|	BoolPri CompareOp AnyOrAll SubSelect %prec eq
+	{
+		sq := $4.(*ast.SubqueryExpr)
+		sq.MultiRows = true
+		$$ = &ast.CompareSubqueryExpr{Op: $2.(opcode.Op), L: $1, R: sq, All: $3.(bool)}
+	}
 |	BoolPri CompareOp singleAtIdentifier assignmentEq PredicateExpr %prec assignmentEq
 	{
 		v := $3
@@ -2331,6 +2672,30 @@ InOrNotOp:
 		$$ = false
 	}
 
+LikeOrNotOp:
+	"LIKE"
+	{
+		$$ = true
+	}
+|	"NOT" "LIKE"
+	{
+		$$ = false
+	}
+
+RegexpOrNotOp:
+	RegexpSym
+	{
+		$$ = true
+	}
+|	"NOT" RegexpSym
+	{
+		$$ = false
+	}
+
+RegexpSym:
+	"REGEXP"
+|	"RLIKE"
+
 AnyOrAll:
 	"ANY"
 	{
@@ -2350,6 +2715,12 @@ PredicateExpr:
 	{
 		$$ = &ast.PatternInExpr{Expr: $1, Not: !$2.(bool), List: $4.([]ast.ExprNode)}
 	}
+|	BitExpr InOrNotOp SubSelect
+	{
+		sq := $3.(*ast.SubqueryExpr)
+		sq.MultiRows = true
+		$$ = &ast.PatternInExpr{Expr: $1, Not: !$2.(bool), Sel: sq}
+	}
 |	BitExpr BetweenOrNotOp BitExpr "AND" PredicateExpr
 	{
 		$$ = &ast.BetweenExpr{
@@ -2359,6 +2730,26 @@ PredicateExpr:
 			Not:	!$2.(bool),
 		}
 	}
+|	BitExpr LikeOrNotOp SimpleExpr LikeEscapeOpt
+	{
+		escape := $4.(string)
+		if len(escape) > 1 {
+			yylex.AppendError(ErrWrongArguments.GenWithStackByArgs("ESCAPE"))
+			return 1
+		} else if len(escape) == 0 {
+			escape = "\\"
+		}
+		$$ = &ast.PatternLikeExpr{
+			Expr:		$1,
+			Pattern:	$3,
+			Not:		!$2.(bool),
+			Escape:		escape[0],
+		}
+	}
+|	BitExpr RegexpOrNotOp SimpleExpr
+	{
+		$$ = &ast.PatternRegexpExpr{Expr: $1, Pattern: $3, Not: !$2.(bool)}
+	}
 |	BitExpr
 
 LikeEscapeOpt:
@@ -2709,6 +3100,10 @@ InsertValues:
 	{
 		$$ = &ast.InsertStmt{Lists:  $2.([][]ast.ExprNode)}
 	}
+|	'(' ColumnNameListOpt ')' SetOprStmt
+	{
+		$$ = &ast.InsertStmt{Columns: $2.([]*ast.ColumnName), Select: $4.(*ast.SetOprStmt)}
+	}
 |	'(' SelectStmt ')'
 	{
 		$$ = &ast.InsertStmt{Select: $2.(*ast.SelectStmt)}
@@ -2717,6 +3112,14 @@ InsertValues:
 	{
 		$$ = &ast.InsertStmt{Select: $1.(*ast.SelectStmt)}
 	}
+|	SetOprStmt
+	{
+		$$ = &ast.InsertStmt{Select: $1.(*ast.SetOprStmt)}
+	}
+|	SelectStmtWithClause
+	{
+		$$ = &ast.InsertStmt{Select: $1.(ast.ResultSetNode)}
+	}
 |	"SET" ColumnSetValueList
 	{
 		$$ = &ast.InsertStmt{Setlist: $2.([]*ast.Assignment)}
@@ -2952,6 +3355,27 @@ BitExpr:
 	{
 		$$ = &ast.BinaryOperationExpr{Op: opcode.Minus, L: $1, R: $3}
 	}
+|	BitExpr '+' "INTERVAL" Expression TimeUnit %prec '+'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr(ast.DateAdd),
+			Args: []ast.ExprNode{$1, $4, &ast.TimeUnitExpr{Unit: $5.(ast.TimeUnitType)}},
+		}
+	}
+|	BitExpr '-' "INTERVAL" Expression TimeUnit %prec '-'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr(ast.DateSub),
+			Args: []ast.ExprNode{$1, $4, &ast.TimeUnitExpr{Unit: $5.(ast.TimeUnitType)}},
+		}
+	}
+|	"INTERVAL" Expression TimeUnit '+' BitExpr %prec '+'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr(ast.DateAdd),
+			Args: []ast.ExprNode{$5, $2, &ast.TimeUnitExpr{Unit: $3.(ast.TimeUnitType)}},
+		}
+	}
 |	BitExpr '*' BitExpr %prec '*'
 	{
 		$$ = &ast.BinaryOperationExpr{Op: opcode.Mul, L: $1, R: $3}
@@ -3010,6 +3434,19 @@ SimpleIdent:
 
 SimpleExpr:
 	SimpleIdent
+|	SimpleIdent jss stringLit
+	{
+		// col->'path' is a shorthand for JSON_EXTRACT(col, 'path').
+		expr := ast.NewValueExpr($3)
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONExtract), Args: []ast.ExprNode{$1, expr}}
+	}
+|	SimpleIdent juss stringLit
+	{
+		// col->>'path' is a shorthand for JSON_UNQUOTE(JSON_EXTRACT(col, 'path')).
+		expr := ast.NewValueExpr($3)
+		extract := &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONExtract), Args: []ast.ExprNode{$1, expr}}
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONUnquote), Args: []ast.ExprNode{extract}}
+	}
 |	FunctionCallKeyword
 |	FunctionCallNonKeyword
 |	FunctionCallGeneric
@@ -3019,8 +3456,13 @@ SimpleExpr:
 		$$ = $1
 	}
 |	Literal
+|	paramMarker
+	{
+		$$ = ast.NewParamMarkerExpr(yyS[yypt].offset)
+	}
 |	Variable
 |	SumExpr
+|	WindowFuncCall
 |	'!' SimpleExpr %prec neg
 	{
 		$$ = &ast.UnaryOperationExpr{Op: opcode.Not, V: $2}
@@ -3058,6 +3500,23 @@ SimpleExpr:
 		values := append($3.([]ast.ExprNode), $5)
 		$$ = &ast.RowExpr{Values: values}
 	}
+|	"CASE" ExpressionOpt WhenClauseList ElseOpt "END"
+	{
+		$$ = &ast.CaseExpr{
+			Value:       $2,
+			WhenClauses: $3.([]*ast.WhenClause),
+			ElseClause:  $4,
+		}
+	}
+|	"CONVERT" '(' Expression ',' CastType ')'
+	{
+		// See https://dev.mysql.com/doc/refman/5.7/en/cast-functions.html#function_convert
+		$$ = &ast.FuncCastExpr{
+			Expr:         $3,
+			Tp:           castTargetType($5.(*types.FieldType)),
+			FunctionType: ast.CastConvertFunction,
+		}
+	}
 |	"CONVERT" '(' Expression "USING" CharsetName ')'
 	{
 		// See https://dev.mysql.com/doc/refman/5.7/en/cast-functions.html#function_convert
@@ -3067,6 +3526,13 @@ SimpleExpr:
 			Args: []ast.ExprNode{$3, charset1},
 		}
 	}
+|	SubSelect
+|	"EXISTS" SubSelect
+	{
+		sq := $2.(*ast.SubqueryExpr)
+		sq.Exists = true
+		$$ = &ast.ExistsSubqueryExpr{Sel: sq}
+	}
 |	"DEFAULT" '(' SimpleIdent ')'
 	{
 		$$ = &ast.DefaultExpr{Name: $3.(*ast.ColumnNameExpr).Name}
@@ -3186,6 +3652,15 @@ FunctionCallNonKeyword:
 	{
 		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
 	}
+|	builtinCast '(' Expression "AS" CastType ')'
+	{
+		// See https://dev.mysql.com/doc/refman/5.7/en/cast-functions.html#function_cast
+		$$ = &ast.FuncCastExpr{
+			Expr:         $3,
+			Tp:           castTargetType($5.(*types.FieldType)),
+			FunctionType: ast.CastFunction,
+		}
+	}
 |	builtinSysDate '(' FuncDatetimePrecListOpt ')'
 	{
 		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
@@ -3236,6 +3711,65 @@ FunctionCallNonKeyword:
 			Args: []ast.ExprNode{$5, $3},
 		}
 	}
+|	builtinTrim '(' TrimDirection "FROM" Expression ')'
+	{
+		nilVal := ast.NewValueExpr(nil)
+		direction := &ast.TrimDirectionExpr{Direction: $3.(ast.TrimDirectionType)}
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$5, nilVal, direction},
+		}
+	}
+|	builtinTrim '(' TrimDirection Expression "FROM" Expression ')'
+	{
+		direction := &ast.TrimDirectionExpr{Direction: $3.(ast.TrimDirectionType)}
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$6, $4, direction},
+		}
+	}
+|	FunctionNameDateArith '(' Expression ',' "INTERVAL" Expression TimeUnit ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$3, $6, &ast.TimeUnitExpr{Unit: $7.(ast.TimeUnitType)}},
+		}
+	}
+|	FunctionNameDateArithMultiForms '(' Expression ',' Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$3, $5, &ast.TimeUnitExpr{Unit: ast.TimeUnitDay}},
+		}
+	}
+|	FunctionNameDateArithMultiForms '(' Expression ',' "INTERVAL" Expression TimeUnit ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$3, $6, &ast.TimeUnitExpr{Unit: $7.(ast.TimeUnitType)}},
+		}
+	}
+|	builtinExtract '(' TimeUnit "FROM" Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{&ast.TimeUnitExpr{Unit: $3.(ast.TimeUnitType)}, $5},
+		}
+	}
+
+TrimDirection:
+	"BOTH"
+	{
+		$$ = ast.TrimBoth
+	}
+|	"LEADING"
+	{
+		$$ = ast.TrimLeading
+	}
+|	"TRAILING"
+	{
+		$$ = ast.TrimTrailing
+	}
 
 FunctionNameDateArith:
 	builtinDateAdd
@@ -3247,49 +3781,372 @@ FunctionNameDateArithMultiForms:
 |	builtinSubDate
 
 SumExpr:
-	"AVG" '(' Expression ')'
+	"AVG" '(' Expression ')' OptWindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *$5.(*ast.WindowSpec)}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		}
 	}
-|	builtinCount '(' Expression ')'
+|	builtinCount '(' Expression ')' OptWindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *$5.(*ast.WindowSpec)}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		}
 	}
-|	builtinCount '(' '*' ')'
+|	builtinCount '(' '*' ')' OptWindowingClause
 	{
 		args := []ast.ExprNode{ast.NewValueExpr(1)}
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: args,}
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: args, Spec: *$5.(*ast.WindowSpec)}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: args,}
+		}
 	}
-|	builtinMax '(' Expression ')'
+|	builtinMax '(' Expression ')' OptWindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *$5.(*ast.WindowSpec)}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		}
 	}
-|	builtinMin '(' Expression ')'
+|	builtinMin '(' Expression ')' OptWindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *$5.(*ast.WindowSpec)}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		}
 	}
-|	builtinSum '(' Expression ')'
+|	builtinSum '(' Expression ')' OptWindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *$5.(*ast.WindowSpec)}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		}
 	}
 
-OptGConcatSeparator:
-        {
-            	$$ = ast.NewValueExpr(",")
-        }
-| "SEPARATOR" stringLit
+/************************************************************************************
+ *  Window functions
+ *  See https://dev.mysql.com/doc/refman/8.0/en/window-functions.html
+ ***********************************************************************************/
+WindowFuncCall:
+	"ROW_NUMBER" '(' ')' WindowingClause
 	{
-		$$ = ast.NewValueExpr($2)
+		$$ = &ast.WindowFuncExpr{F: $1, Spec: *$4.(*ast.WindowSpec)}
 	}
-
-
-FunctionCallGeneric:
-	identifier '(' ExpressionListOpt ')'
+|	"RANK" '(' ')' WindowingClause
 	{
-		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
+		$$ = &ast.WindowFuncExpr{F: $1, Spec: *$4.(*ast.WindowSpec)}
 	}
-
-FuncDatetimePrec:
+|	"DENSE_RANK" '(' ')' WindowingClause
+	{
+		$$ = &ast.WindowFuncExpr{F: $1, Spec: *$4.(*ast.WindowSpec)}
+	}
+|	"NTILE" '(' SimpleExpr ')' WindowingClause
+	{
+		$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *$5.(*ast.WindowSpec)}
+	}
+|	"LEAD" '(' Expression OptLeadLagInfo ')' WindowingClause
+	{
+		args := append([]ast.ExprNode{$3}, $4.([]ast.ExprNode)...)
+		$$ = &ast.WindowFuncExpr{F: $1, Args: args, Spec: *$6.(*ast.WindowSpec)}
+	}
+|	"LAG" '(' Expression OptLeadLagInfo ')' WindowingClause
+	{
+		args := append([]ast.ExprNode{$3}, $4.([]ast.ExprNode)...)
+		$$ = &ast.WindowFuncExpr{F: $1, Args: args, Spec: *$6.(*ast.WindowSpec)}
+	}
+|	"FIRST_VALUE" '(' Expression ')' WindowingClause
+	{
+		$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *$5.(*ast.WindowSpec)}
+	}
+|	"LAST_VALUE" '(' Expression ')' WindowingClause
+	{
+		$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *$5.(*ast.WindowSpec)}
+	}
+
+OptLeadLagInfo:
+	{
+		$$ = []ast.ExprNode{}
+	}
+|	',' NumLiteral OptLLDefault
+	{
+		args := []ast.ExprNode{ast.NewValueExpr($2)}
+		if $3 != nil {
+			args = append(args, $3.(ast.ExprNode))
+		}
+		$$ = args
+	}
+
+OptLLDefault:
+	{
+		$$ = nil
+	}
+|	',' Expression
+	{
+		$$ = $2
+	}
+
+OptWindowingClause:
+	{
+		$$ = nil
+	}
+|	WindowingClause
+
+WindowingClause:
+	"OVER" WindowName
+	{
+		$$ = &ast.WindowSpec{Ref: $2.(model.CIStr), OnlyAlias: true}
+	}
+|	"OVER" WindowSpec
+	{
+		$$ = $2
+	}
+
+WindowSpec:
+	'(' WindowSpecDetails ')'
+	{
+		$$ = $2
+	}
+
+WindowSpecDetails:
+	OptExistingWindowName OptPartitionClause OptWindowOrderByClause OptWindowFrameClause
+	{
+		spec := &ast.WindowSpec{Ref: $1.(model.CIStr)}
+		if $2 != nil {
+			spec.PartitionBy = $2.(*ast.PartitionByClause)
+		}
+		if $3 != nil {
+			spec.OrderBy = $3.(*ast.OrderByClause)
+		}
+		if $4 != nil {
+			spec.Frame = $4.(*ast.FrameClause)
+		}
+		$$ = spec
+	}
+
+WindowName:
+	Identifier
+	{
+		$$ = model.NewCIStr($1)
+	}
+
+OptExistingWindowName:
+	{
+		$$ = model.CIStr{}
+	}
+|	WindowName
+
+WindowClauseOptional:
+	{
+		$$ = nil
+	}
+|	"WINDOW" WindowDefinitionList
+	{
+		$$ = $2
+	}
+
+WindowDefinitionList:
+	WindowDefinition
+	{
+		$$ = []ast.WindowSpec{$1.(ast.WindowSpec)}
+	}
+|	WindowDefinitionList ',' WindowDefinition
+	{
+		$$ = append($1.([]ast.WindowSpec), $3.(ast.WindowSpec))
+	}
+
+WindowDefinition:
+	WindowName "AS" WindowSpec
+	{
+		spec := $3.(*ast.WindowSpec)
+		spec.Name = $1.(model.CIStr)
+		$$ = *spec
+	}
+
+OptPartitionClause:
+	{
+		$$ = nil
+	}
+|	"PARTITION" "BY" ByList
+	{
+		$$ = &ast.PartitionByClause{Items: $3.([]*ast.ByItem)}
+	}
+
+OptWindowOrderByClause:
+	{
+		$$ = nil
+	}
+|	"ORDER" "BY" ByList
+	{
+		$$ = &ast.OrderByClause{Items: $3.([]*ast.ByItem)}
+	}
+
+OptWindowFrameClause:
+	{
+		$$ = nil
+	}
+|	WindowFrameUnits WindowFrameExtent
+	{
+		$$ = &ast.FrameClause{
+			Type: $1.(ast.FrameType),
+			Extent: $2.(ast.FrameExtent),
+		}
+	}
+
+WindowFrameUnits:
+	"ROWS"
+	{
+		$$ = ast.Rows
+	}
+|	"RANGE"
+	{
+		$$ = ast.Ranges
+	}
+
+WindowFrameExtent:
+	WindowFrameStart
+	{
+		$$ = ast.FrameExtent{
+			Start: $1.(ast.FrameBound),
+			End: ast.FrameBound{Type: ast.CurrentRow},
+		}
+	}
+|	"BETWEEN" WindowFrameBound "AND" WindowFrameBound
+	{
+		$$ = ast.FrameExtent{Start: $2.(ast.FrameBound), End: $4.(ast.FrameBound)}
+	}
+
+WindowFrameStart:
+	"UNBOUNDED" "PRECEDING"
+	{
+		$$ = ast.FrameBound{Type: ast.Preceding, UnBounded: true}
+	}
+|	NumLiteral "PRECEDING"
+	{
+		$$ = ast.FrameBound{Type: ast.Preceding, Expr: ast.NewValueExpr($1)}
+	}
+|	"CURRENT" "ROW"
+	{
+		$$ = ast.FrameBound{Type: ast.CurrentRow}
+	}
+
+WindowFrameBound:
+	WindowFrameStart
+|	"UNBOUNDED" "FOLLOWING"
+	{
+		$$ = ast.FrameBound{Type: ast.Following, UnBounded: true}
+	}
+|	NumLiteral "FOLLOWING"
+	{
+		$$ = ast.FrameBound{Type: ast.Following, Expr: ast.NewValueExpr($1)}
+	}
+
+OptGConcatSeparator:
+        {
+            	$$ = ast.NewValueExpr(",")
+        }
+| "SEPARATOR" stringLit
+	{
+		$$ = ast.NewValueExpr($2)
+	}
+
+
+FunctionCallGeneric:
+	identifier '(' ExpressionListOpt ')'
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
+	}
+
+TimeUnit:
+	"MICROSECOND"
+	{
+		$$ = ast.TimeUnitMicrosecond
+	}
+|	"SECOND"
+	{
+		$$ = ast.TimeUnitSecond
+	}
+|	"MINUTE"
+	{
+		$$ = ast.TimeUnitMinute
+	}
+|	"HOUR"
+	{
+		$$ = ast.TimeUnitHour
+	}
+|	"DAY"
+	{
+		$$ = ast.TimeUnitDay
+	}
+|	"WEEK"
+	{
+		$$ = ast.TimeUnitWeek
+	}
+|	"MONTH"
+	{
+		$$ = ast.TimeUnitMonth
+	}
+|	"QUARTER"
+	{
+		$$ = ast.TimeUnitQuarter
+	}
+|	"YEAR"
+	{
+		$$ = ast.TimeUnitYear
+	}
+|	"SECOND_MICROSECOND"
+	{
+		$$ = ast.TimeUnitSecondMicrosecond
+	}
+|	"MINUTE_MICROSECOND"
+	{
+		$$ = ast.TimeUnitMinuteMicrosecond
+	}
+|	"MINUTE_SECOND"
+	{
+		$$ = ast.TimeUnitMinuteSecond
+	}
+|	"HOUR_MICROSECOND"
+	{
+		$$ = ast.TimeUnitHourMicrosecond
+	}
+|	"HOUR_SECOND"
+	{
+		$$ = ast.TimeUnitHourSecond
+	}
+|	"HOUR_MINUTE"
+	{
+		$$ = ast.TimeUnitHourMinute
+	}
+|	"DAY_MICROSECOND"
+	{
+		$$ = ast.TimeUnitDayMicrosecond
+	}
+|	"DAY_SECOND"
+	{
+		$$ = ast.TimeUnitDaySecond
+	}
+|	"DAY_MINUTE"
+	{
+		$$ = ast.TimeUnitDayMinute
+	}
+|	"DAY_HOUR"
+	{
+		$$ = ast.TimeUnitDayHour
+	}
+|	"YEAR_MONTH"
+	{
+		$$ = ast.TimeUnitYearMonth
+	}
+
+FuncDatetimePrec:
 	{
 		$$ = nil
 	}
@@ -3312,6 +4169,35 @@ ExpressionOpt:
 		$$ = $1
 	}
 
+WhenClauseList:
+	WhenClause
+	{
+		$$ = []*ast.WhenClause{$1.(*ast.WhenClause)}
+	}
+|	WhenClauseList WhenClause
+	{
+		$$ = append($1.([]*ast.WhenClause), $2.(*ast.WhenClause))
+	}
+
+WhenClause:
+	"WHEN" Expression "THEN" Expression
+	{
+		$$ = &ast.WhenClause{
+			Expr:   $2,
+			Result: $4,
+		}
+	}
+
+ElseOpt:
+	/* empty */
+	{
+		$$ = nil
+	}
+|	"ELSE" Expression
+	{
+		$$ = $2
+	}
+
 CastType:
 	"BINARY" OptFieldLen
 	{
@@ -3560,13 +4446,13 @@ SelectStmtFromDualTable:
 
 SelectStmtFromTable:
 	SelectStmtBasic "FROM"
-	TableRefsClause WhereClauseOptional SelectStmtGroup HavingClause
+	TableRefsClause WhereClauseOptional SelectStmtGroup HavingClause WindowClauseOptional
 	{
 		st := $1.(*ast.SelectStmt)
 		st.From = $3.(*ast.TableRefsClause)
 		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
 		if lastField.Expr != nil && lastField.AsName.O == "" {
-			lastEnd := parser.endOffset(&yyS[yypt-4])
+			lastEnd := parser.endOffset(&yyS[yypt-5])
 			lastField.SetText(parser.src[lastField.Offset:lastEnd])
 		}
 		if $4 != nil {
@@ -3578,21 +4464,27 @@ SelectStmtFromTable:
 		if $6 != nil {
 			st.Having = $6.(*ast.HavingClause)
 		}
+		if $7 != nil {
+			st.WindowSpecs = $7.([]ast.WindowSpec)
+		}
 		$$ = st
 	}
 
 SelectStmt:
-	SelectStmtBasic OrderByOptional SelectStmtLimit
+	SelectStmtBasic OrderByOptional SelectStmtLimit SelectLockOpt
 	{
 		st := $1.(*ast.SelectStmt)
+		st.LockTp = $4.(ast.SelectLockType)
 		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
 		if lastField.Expr != nil && lastField.AsName.O == "" {
 			src := parser.src
 			var lastEnd int
 			if $2 != nil {
-				lastEnd = yyS[yypt-1].offset-1
+				lastEnd = yyS[yypt-2].offset-1
 			} else if $3 != nil {
-				lastEnd = yyS[yypt-0].offset-1
+				lastEnd = yyS[yypt-1].offset-1
+			} else if $4 != ast.SelectLockNone {
+				lastEnd = yyS[yypt].offset-1
 			} else {
 				lastEnd = len(src)
 				if src[lastEnd-1] == ';' {
@@ -3609,9 +4501,10 @@ SelectStmt:
 		}
 		$$ = st
 	}
-|	SelectStmtFromDualTable OrderByOptional SelectStmtLimit
+|	SelectStmtFromDualTable OrderByOptional SelectStmtLimit SelectLockOpt
 	{
 		st := $1.(*ast.SelectStmt)
+		st.LockTp = $4.(ast.SelectLockType)
 		if $2 != nil {
 			st.OrderBy = $2.(*ast.OrderByClause)
 		}
@@ -3620,9 +4513,10 @@ SelectStmt:
 		}
 		$$ = st
 	}
-|	SelectStmtFromTable OrderByOptional SelectStmtLimit
+|	SelectStmtFromTable OrderByOptional SelectStmtLimit SelectLockOpt
 	{
 		st := $1.(*ast.SelectStmt)
+		st.LockTp = $4.(ast.SelectLockType)
 		if $2 != nil {
 			st.OrderBy = $2.(*ast.OrderByClause)
 		}
@@ -3635,6 +4529,249 @@ SelectStmt:
 FromDual:
 	"FROM" "DUAL"
 
+SelectStmtWithClause:
+	WithClause SelectStmt
+	{
+		st := $2.(*ast.SelectStmt)
+		st.With = $1.(*ast.WithClause)
+		$$ = st
+	}
+|	WithClause SetOprStmt
+	{
+		setOpr := $2.(*ast.SetOprStmt)
+		setOpr.With = $1.(*ast.WithClause)
+		$$ = setOpr
+	}
+
+WithClause:
+	"WITH" WithList
+	{
+		$$ = $2
+	}
+|	"WITH" "RECURSIVE" WithList
+	{
+		ws := $3.(*ast.WithClause)
+		ws.IsRecursive = true
+		for _, cte := range ws.CTEs {
+			cte.IsRecursive = true
+		}
+		$$ = ws
+	}
+
+WithList:
+	CommonTableExpr
+	{
+		$$ = &ast.WithClause{CTEs: []*ast.CommonTableExpression{$1.(*ast.CommonTableExpression)}}
+	}
+|	WithList ',' CommonTableExpr
+	{
+		ws := $1.(*ast.WithClause)
+		ws.CTEs = append(ws.CTEs, $3.(*ast.CommonTableExpression))
+		$$ = ws
+	}
+
+CommonTableExpr:
+	Identifier IdentListWithParenOpt "AS" SubSelect
+	{
+		$$ = &ast.CommonTableExpression{
+			Name:        model.NewCIStr($1),
+			ColNameList: $2.([]model.CIStr),
+			Query:       $4.(*ast.SubqueryExpr),
+		}
+	}
+
+IdentListWithParenOpt:
+	{
+		$$ = []model.CIStr(nil)
+	}
+|	'(' IdentList ')'
+	{
+		$$ = $2
+	}
+
+IdentList:
+	Identifier
+	{
+		$$ = []model.CIStr{model.NewCIStr($1)}
+	}
+|	IdentList ',' Identifier
+	{
+		$$ = append($1.([]model.CIStr), model.NewCIStr($3))
+	}
+
+SetOprStmt:
+	SetOprClauseList SetOpr SelectStmtBasic OrderByOptional SelectStmtLimit
+	{
+		st := $3.(*ast.SelectStmt)
+		setOpr := $1.(*ast.SetOprStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-3])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
+		if lastField.Expr != nil && lastField.AsName.O == "" {
+			src := parser.src
+			var lastEnd int
+			if $4 != nil {
+				lastEnd = yyS[yypt-1].offset-1
+			} else if $5 != nil {
+				lastEnd = yyS[yypt-0].offset-1
+			} else {
+				lastEnd = len(src)
+				if src[lastEnd-1] == ';' {
+					lastEnd--
+				}
+			}
+			lastField.SetText(src[lastField.Offset:lastEnd])
+		}
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		if $4 != nil {
+			setOpr.OrderBy = $4.(*ast.OrderByClause)
+		}
+		if $5 != nil {
+			setOpr.Limit = $5.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+|	SetOprClauseList SetOpr SelectStmtFromDualTable OrderByOptional SelectStmtLimit
+	{
+		st := $3.(*ast.SelectStmt)
+		setOpr := $1.(*ast.SetOprStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-3])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		if $4 != nil {
+			setOpr.OrderBy = $4.(*ast.OrderByClause)
+		}
+		if $5 != nil {
+			setOpr.Limit = $5.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+|	SetOprClauseList SetOpr SelectStmtFromTable OrderByOptional SelectStmtLimit
+	{
+		st := $3.(*ast.SelectStmt)
+		setOpr := $1.(*ast.SetOprStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-3])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		if $4 != nil {
+			setOpr.OrderBy = $4.(*ast.OrderByClause)
+		}
+		if $5 != nil {
+			setOpr.Limit = $5.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+|	SetOprClauseList SetOpr '(' SelectStmt ')' OrderByOptional SelectStmtLimit
+	{
+		setOpr := $1.(*ast.SetOprStmt)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-5])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		st := $4.(*ast.SelectStmt)
+		st.IsInBraces = true
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		endOffset = parser.endOffset(&yyS[yypt-2])
+		parser.setLastSelectFieldText(st, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		if $6 != nil {
+			setOpr.OrderBy = $6.(*ast.OrderByClause)
+		}
+		if $7 != nil {
+			setOpr.Limit = $7.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+
+SetOprClauseList:
+	SetOprSelect
+	{
+		selectList := &ast.SetOprSelectList{Selects: []*ast.SelectStmt{$1.(*ast.SelectStmt)}}
+		$$ = &ast.SetOprStmt{SelectList: selectList}
+	}
+|	SetOprClauseList SetOpr SetOprSelect
+	{
+		setOpr := $1.(*ast.SetOprStmt)
+		st := $3.(*ast.SelectStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-1])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		$$ = setOpr
+	}
+
+SetOprSelect:
+	SelectStmt
+	{
+		$$ = $1.(interface{})
+	}
+|	'(' SelectStmt ')'
+	{
+		st := $2.(*ast.SelectStmt)
+		st.IsInBraces = true
+		endOffset := parser.endOffset(&yyS[yypt])
+		parser.setLastSelectFieldText(st, endOffset)
+		$$ = st
+	}
+
+SetOpr:
+	"UNION" DefaultTrueDistinctOpt
+	{
+		tp := ast.Union
+		if !$2.(bool) {
+			tp = ast.UnionAll
+		}
+		$$ = &tp
+	}
+|	"EXCEPT"
+	{
+		tp := ast.Except
+		$$ = &tp
+	}
+|	"INTERSECT"
+	{
+		tp := ast.Intersect
+		$$ = &tp
+	}
+
+SubSelect:
+	'(' SelectStmt ')'
+	{
+		s := $2.(*ast.SelectStmt)
+		endOffset := parser.endOffset(&yyS[yypt])
+		parser.setLastSelectFieldText(s, endOffset)
+		src := parser.src
+		// See the implementation of yyParse function
+		s.SetText(src[yyS[yypt-1].offset:yyS[yypt].offset])
+		$$ = &ast.SubqueryExpr{Query: s}
+	}
+|	'(' SetOprStmt ')'
+	{
+		s := $2.(*ast.SetOprStmt)
+		src := parser.src
+		// See the implementation of yyParse function
+		s.SetText(src[yyS[yypt-1].offset:yyS[yypt].offset])
+		$$ = &ast.SubqueryExpr{Query: s}
+	}
+|	'(' SelectStmtWithClause ')'
+	{
+		rs := $2.(ast.ResultSetNode)
+		if s, ok := rs.(*ast.SelectStmt); ok {
+			endOffset := parser.endOffset(&yyS[yypt])
+			parser.setLastSelectFieldText(s, endOffset)
+		}
+		src := parser.src
+		// See the implementation of yyParse function
+		rs.SetText(src[yyS[yypt-1].offset:yyS[yypt].offset])
+		$$ = &ast.SubqueryExpr{Query: rs}
+	}
+
 TableRefsClause:
 	TableRefs
 	{
@@ -3688,6 +4825,27 @@ TableFactor:
 		tn.IndexHints = $3.([]*ast.IndexHint)
 		$$ = &ast.TableSource{Source: tn, AsName: $2.(model.CIStr)}
 	}
+|	TableName AsOfClause IndexHintListOpt
+	{
+		tn := $1.(*ast.TableName)
+		tn.AsOf = $2.(*ast.AsOfClause)
+		tn.IndexHints = $3.([]*ast.IndexHint)
+		$$ = &ast.TableSource{Source: tn}
+	}
+|	TableName TableAsName AsOfClause IndexHintListOpt
+	{
+		tn := $1.(*ast.TableName)
+		tn.AsOf = $3.(*ast.AsOfClause)
+		tn.IndexHints = $4.([]*ast.IndexHint)
+		$$ = &ast.TableSource{Source: tn, AsName: $2.(model.CIStr)}
+	}
+|	TableName AsOfClause TableAsName IndexHintListOpt
+	{
+		tn := $1.(*ast.TableName)
+		tn.AsOf = $2.(*ast.AsOfClause)
+		tn.IndexHints = $4.([]*ast.IndexHint)
+		$$ = &ast.TableSource{Source: tn, AsName: $3.(model.CIStr)}
+	}
 |	'(' SelectStmt ')' TableAsName
 	{
 		st := $2.(*ast.SelectStmt)
@@ -3695,6 +4853,19 @@ TableFactor:
 		parser.setLastSelectFieldText(st, endOffset)
 		$$ = &ast.TableSource{Source: $2.(*ast.SelectStmt), AsName: $4.(model.CIStr)}
 	}
+|	'(' SetOprStmt ')' TableAsName
+	{
+		$$ = &ast.TableSource{Source: $2.(*ast.SetOprStmt), AsName: $4.(model.CIStr)}
+	}
+|	'(' SelectStmtWithClause ')' TableAsName
+	{
+		rs := $2.(ast.ResultSetNode)
+		if st, ok := rs.(*ast.SelectStmt); ok {
+			endOffset := parser.endOffset(&yyS[yypt-1])
+			parser.setLastSelectFieldText(st, endOffset)
+		}
+		$$ = &ast.TableSource{Source: rs, AsName: $4.(model.CIStr)}
+	}
 |	'(' TableRefs ')'
 	{
 		$$ = $2
@@ -3719,6 +4890,12 @@ TableAsName:
 		$$ = model.NewCIStr($2)
 	}
 
+AsOfClause:
+	"AS" "OF" "TIMESTAMP" Expression
+	{
+		$$ = &ast.AsOfClause{TsExpr: $4}
+	}
+
 IndexHintType:
 	"USE" KeyOrIndex
 	{
@@ -3872,6 +5049,10 @@ LimitOption:
 	{
 		$$ = ast.NewValueExpr($1)
 	}
+|	paramMarker
+	{
+		$$ = ast.NewParamMarkerExpr(yyS[yypt].offset)
+	}
 
 SelectStmtLimit:
 	{
@@ -3890,6 +5071,20 @@ SelectStmtLimit:
 		$$ = &ast.Limit{Offset: $4.(ast.ExprNode), Count: $2.(ast.ExprNode)}
 	}
 
+SelectLockOpt:
+	/* empty */
+	{
+		$$ = ast.SelectLockNone
+	}
+|	"FOR" "UPDATE"
+	{
+		$$ = ast.SelectLockForUpdate
+	}
+|	"FOR" "UPDATE" "NOWAIT"
+	{
+		$$ = ast.SelectLockForUpdateNoWait
+	}
+
 
 SelectStmtOpts:
 	TableOptimizerHints DefaultFalseDistinctOpt PriorityOpt SelectStmtSQLSmallResult SelectStmtSQLBigResult SelectStmtSQLBufferResult SelectStmtSQLCache SelectStmtCalcFoundRows SelectStmtStraightJoin
@@ -4425,7 +5620,11 @@ ShowStmt:
 	{
 		stmt := $2.(*ast.ShowStmt)
 		if $3 != nil {
-			stmt.Where = $3.(ast.ExprNode)
+			if x, ok := $3.(*ast.PatternLikeExpr); ok && x.Expr == nil {
+				stmt.Pattern = x
+			} else {
+				stmt.Where = $3.(ast.ExprNode)
+			}
 		}
 		$$ = stmt
 	}
@@ -4436,6 +5635,13 @@ ShowStmt:
 			Table:	$4.(*ast.TableName),
 		}
 	}
+|	"SHOW" "CREATE" "VIEW" TableName
+	{
+		$$ = &ast.ShowStmt{
+			Tp:	ast.ShowCreateView,
+			Table:	$4.(*ast.TableName),
+		}
+	}
 |	"SHOW" "CREATE" "DATABASE" IfNotExists DBName
 	{
 		$$ = &ast.ShowStmt{
@@ -4451,6 +5657,18 @@ ShowStmt:
 			Full:	$2.(bool),
 		}
 	}
+|	"SHOW" "GRANTS"
+	{
+		// See https://dev.mysql.com/doc/refman/5.7/en/show-grants.html
+		$$ = &ast.ShowStmt{Tp: ast.ShowGrants}
+	}
+|	"SHOW" "GRANTS" "FOR" Username
+	{
+		$$ = &ast.ShowStmt{
+			Tp:	ast.ShowGrants,
+			User:	$4.(*auth.UserIdentity),
+		}
+	}
 
 ShowIndexKwd:
 	"INDEX"
@@ -4493,6 +5711,13 @@ ShowLikeOrWhereOpt:
 	{
 		$$ = nil
 	}
+|	"LIKE" SimpleExpr
+	{
+		$$ = &ast.PatternLikeExpr{
+			Pattern:	$2,
+			Escape:		'\\',
+		}
+	}
 |	"WHERE" Expression
 	{
 		$$ = $2
@@ -4553,26 +5778,42 @@ Statement:
 |	AnalyzeTableStmt
 |	BeginTransactionStmt
 |	CommitStmt
+|	DeallocateStmt
 |	DeleteFromStmt
+|	ExecuteStmt
 |	ExplainStmt
 |	CreateDatabaseStmt
 |	CreateIndexStmt
 |	CreateTableStmt
+|	CreateUserStmt
+|	CreateViewStmt
 |	DropDatabaseStmt
 |	DropIndexStmt
 |	DropTableStmt
+|	DropViewStmt
+|	DropUserStmt
+|	GrantStmt
 |	InsertIntoStmt
+|	KillStmt
+|	PreparedStmt
+|	RevokeStmt
 |	RollbackStmt
 |	ReplaceIntoStmt
 |	SelectStmt
+|	SelectStmtWithClause
+|	SetOprStmt
 |	SetStmt
 |	ShowStmt
 |	TruncateTableStmt
+|	UpdateStmt
 |	UseStmt
 
 ExplainableStmt:
 	SelectStmt
+|	SelectStmtWithClause
+|	SetOprStmt
 |	DeleteFromStmt
+|	UpdateStmt
 |	InsertIntoStmt
 |	ReplaceIntoStmt
 
@@ -5300,6 +6541,397 @@ StringName:
 		$$ = $1
 	}
 
+/*******************************************************************
+ *
+ *  Create User Statement
+ *  See https://dev.mysql.com/doc/refman/5.7/en/create-user.html
+ *
+ *******************************************************************/
+CreateUserStmt:
+	"CREATE" "USER" IfNotExists UserSpecList
+	{
+		$$ = &ast.CreateUserStmt{
+			IfNotExists:	$3.(bool),
+			Specs:		$4.([]*ast.UserSpec),
+		}
+	}
+
+UserSpec:
+	Username AuthOption
+	{
+		userSpec := &ast.UserSpec{
+			User:	$1.(*auth.UserIdentity),
+		}
+		if $2 != nil {
+			userSpec.AuthOpt = $2.(*ast.AuthOption)
+		}
+		$$ = userSpec
+	}
+
+UserSpecList:
+	UserSpec
+	{
+		$$ = []*ast.UserSpec{$1.(*ast.UserSpec)}
+	}
+|	UserSpecList ',' UserSpec
+	{
+		$$ = append($1.([]*ast.UserSpec), $3.(*ast.UserSpec))
+	}
+
+AuthOption:
+	{
+		$$ = nil
+	}
+|	"IDENTIFIED" "BY" AuthString
+	{
+		$$ = &ast.AuthOption{
+			AuthString:	$3.(string),
+			ByAuthString:	true,
+		}
+	}
+|	"IDENTIFIED" "WITH" AuthPlugin
+	{
+		$$ = &ast.AuthOption{
+			AuthPlugin:	$3,
+		}
+	}
+|	"IDENTIFIED" "WITH" AuthPlugin "BY" AuthString
+	{
+		$$ = &ast.AuthOption{
+			AuthPlugin:	$3,
+			AuthString:	$5.(string),
+			ByAuthString:	true,
+		}
+	}
+|	"IDENTIFIED" "WITH" AuthPlugin "AS" HashString
+	{
+		$$ = &ast.AuthOption{
+			AuthPlugin:	$3,
+			HashString:	$5.(string),
+		}
+	}
+|	"IDENTIFIED" "BY" "PASSWORD" HashString
+	{
+		$$ = &ast.AuthOption{
+			HashString:	$4.(string),
+		}
+	}
+
+AuthPlugin:
+	StringName
+	{
+		$$ = strings.ToLower($1.(string))
+	}
+
+HashString:
+	stringLit
+	{
+		$$ = $1
+	}
+
+AuthString:
+	stringLit
+	{
+		$$ = $1
+	}
+
+Username:
+	StringName
+	{
+		$$ = &auth.UserIdentity{Username: $1.(string), Hostname: "%"}
+	}
+|	StringName '@' StringName
+	{
+		$$ = &auth.UserIdentity{Username: $1.(string), Hostname: $3.(string)}
+	}
+|	StringName singleAtIdentifier
+	{
+		$$ = &auth.UserIdentity{Username: $1.(string), Hostname: strings.TrimPrefix($2, "@")}
+	}
+|	"CURRENT_USER" OptionalBraces
+	{
+		$$ = &auth.UserIdentity{CurrentUser: true}
+	}
+
+UsernameList:
+	Username
+	{
+		$$ = []*auth.UserIdentity{$1.(*auth.UserIdentity)}
+	}
+|	UsernameList ',' Username
+	{
+		$$ = append($1.([]*auth.UserIdentity), $3.(*auth.UserIdentity))
+	}
+
+/*******************************************************************
+ *
+ *  Drop User Statement
+ *  See https://dev.mysql.com/doc/refman/5.7/en/drop-user.html
+ *
+ *******************************************************************/
+DropUserStmt:
+	"DROP" "USER" IfExists UsernameList
+	{
+		$$ = &ast.DropUserStmt{IfExists: $3.(bool), UserList: $4.([]*auth.UserIdentity)}
+	}
+
+/*******************************************************************
+ *
+ *  Grant Statement
+ *  See https://dev.mysql.com/doc/refman/5.7/en/grant.html
+ *
+ *******************************************************************/
+GrantStmt:
+	"GRANT" PrivElemList "ON" ObjectType PrivLevel "TO" UserSpecList WithGrantOptionOpt
+	{
+		$$ = &ast.GrantStmt{
+			Privs:		$2.([]*ast.PrivElem),
+			ObjectType:	$4.(ast.ObjectTypeType),
+			Level:		$5.(*ast.GrantLevel),
+			Users:		$7.([]*ast.UserSpec),
+			WithGrant:	$8.(bool),
+		}
+	}
+
+WithGrantOptionOpt:
+	{
+		$$ = false
+	}
+|	"WITH" "GRANT" "OPTION"
+	{
+		$$ = true
+	}
+
+PrivElem:
+	PrivType
+	{
+		$$ = &ast.PrivElem{
+			Priv:	$1.(mysql.PrivilegeType),
+		}
+	}
+
+PrivElemList:
+	PrivElem
+	{
+		$$ = []*ast.PrivElem{$1.(*ast.PrivElem)}
+	}
+|	PrivElemList ',' PrivElem
+	{
+		$$ = append($1.([]*ast.PrivElem), $3.(*ast.PrivElem))
+	}
+
+PrivType:
+	"ALL"
+	{
+		$$ = mysql.AllPriv
+	}
+|	"ALL" "PRIVILEGES"
+	{
+		$$ = mysql.AllPriv
+	}
+|	"ALTER"
+	{
+		$$ = mysql.AlterPriv
+	}
+|	"CREATE"
+	{
+		$$ = mysql.CreatePriv
+	}
+|	"CREATE" "USER"
+	{
+		$$ = mysql.CreateUserPriv
+	}
+|	"CREATE" "VIEW"
+	{
+		$$ = mysql.CreateViewPriv
+	}
+|	"DELETE"
+	{
+		$$ = mysql.DeletePriv
+	}
+|	"DROP"
+	{
+		$$ = mysql.DropPriv
+	}
+|	"EXECUTE"
+	{
+		$$ = mysql.ExecutePriv
+	}
+|	"INDEX"
+	{
+		$$ = mysql.IndexPriv
+	}
+|	"INSERT"
+	{
+		$$ = mysql.InsertPriv
+	}
+|	"SELECT"
+	{
+		$$ = mysql.SelectPriv
+	}
+|	"SUPER"
+	{
+		$$ = mysql.SuperPriv
+	}
+|	"SHOW" "DATABASES"
+	{
+		$$ = mysql.ShowDBPriv
+	}
+|	"SHOW" "VIEW"
+	{
+		$$ = mysql.ShowViewPriv
+	}
+|	"UPDATE"
+	{
+		$$ = mysql.UpdatePriv
+	}
+|	"GRANT" "OPTION"
+	{
+		$$ = mysql.GrantPriv
+	}
+|	"REFERENCES"
+	{
+		$$ = mysql.ReferencesPriv
+	}
+|	"PROCESS"
+	{
+		$$ = mysql.ProcessPriv
+	}
+
+ObjectType:
+	{
+		$$ = ast.ObjectTypeNone
+	}
+|	"TABLE"
+	{
+		$$ = ast.ObjectTypeTable
+	}
+
+PrivLevel:
+	'*'
+	{
+		$$ = &ast.GrantLevel{
+			Level:	ast.GrantLevelDB,
+		}
+	}
+|	'*' '.' '*'
+	{
+		$$ = &ast.GrantLevel{
+			Level:	ast.GrantLevelGlobal,
+		}
+	}
+|	Identifier '.' '*'
+	{
+		$$ = &ast.GrantLevel{
+			Level:	ast.GrantLevelDB,
+			DBName:	$1,
+		}
+	}
+|	Identifier '.' Identifier
+	{
+		$$ = &ast.GrantLevel{
+			Level:		ast.GrantLevelTable,
+			DBName:		$1,
+			TableName:	$3,
+		}
+	}
+|	Identifier
+	{
+		$$ = &ast.GrantLevel{
+			Level:		ast.GrantLevelTable,
+			TableName:	$1,
+		}
+	}
+
+/*******************************************************************
+ *
+ *  Revoke Statement
+ *  See https://dev.mysql.com/doc/refman/5.7/en/revoke.html
+ *
+ *******************************************************************/
+RevokeStmt:
+	"REVOKE" PrivElemList "ON" ObjectType PrivLevel "FROM" UserSpecList
+	{
+		$$ = &ast.RevokeStmt{
+			Privs:		$2.([]*ast.PrivElem),
+			ObjectType:	$4.(ast.ObjectTypeType),
+			Level:		$5.(*ast.GrantLevel),
+			Users:		$7.([]*ast.UserSpec),
+		}
+	}
+
+/*******************************************************************
+ *
+ *  Update Statement
+ *  See https://dev.mysql.com/doc/refman/5.7/en/update.html
+ *
+ *******************************************************************/
+UpdateStmt:
+	"UPDATE" PriorityOpt TableRef "SET" AssignmentList WhereClauseOptional OrderByOptional LimitClause
+	{
+		// Single Table
+		var refs *ast.Join
+		if x, ok := $3.(*ast.Join); ok {
+			refs = x
+		} else {
+			refs = &ast.Join{Left: $3.(ast.ResultSetNode)}
+		}
+		st := &ast.UpdateStmt{
+			Priority:  $2.(mysql.PriorityEnum),
+			TableRefs: &ast.TableRefsClause{TableRefs: refs},
+			List:	   $5.([]*ast.Assignment),
+		}
+		if $6 != nil {
+			st.Where = $6.(ast.ExprNode)
+		}
+		if $7 != nil {
+			st.Order = $7.(*ast.OrderByClause)
+		}
+		if $8 != nil {
+			st.Limit = $8.(*ast.Limit)
+		}
+		$$ = st
+	}
+|	"UPDATE" PriorityOpt TableRefs "SET" AssignmentList WhereClauseOptional
+	{
+		// Multiple Table
+		st := &ast.UpdateStmt{
+			Priority:      $2.(mysql.PriorityEnum),
+			TableRefs:     &ast.TableRefsClause{TableRefs: $3.(*ast.Join)},
+			List:	       $5.([]*ast.Assignment),
+			MultipleTable: true,
+		}
+		if $6 != nil {
+			st.Where = $6.(ast.ExprNode)
+		}
+		$$ = st
+	}
+
+/********************************************************************
+ * Kill Statement
+ * See https://dev.mysql.com/doc/refman/5.7/en/kill.html
+ *******************************************************************/
+KillStmt:
+	"KILL" NUM
+	{
+		$$ = &ast.KillStmt{
+			ConnectionID: getUint64FromNUM($2),
+		}
+	}
+|	"KILL" "CONNECTION" NUM
+	{
+		$$ = &ast.KillStmt{
+			ConnectionID: getUint64FromNUM($3),
+		}
+	}
+|	"KILL" "QUERY" NUM
+	{
+		$$ = &ast.KillStmt{
+			ConnectionID: getUint64FromNUM($3),
+			Query:        true,
+		}
+	}
+
 UseStmt:
 	"USE" DBName
 	{
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/types"
@@ -391,6 +392,17 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 			SELECT * from tmp;
 		ROLLBACK;`, true, "START TRANSACTION; INSERT INTO `tmp` SELECT * FROM `bar`; SELECT * FROM `tmp`; ROLLBACK"},
 
+		{"BEGIN PESSIMISTIC", true, "BEGIN PESSIMISTIC"},
+		{"BEGIN OPTIMISTIC", true, "BEGIN OPTIMISTIC"},
+		{"START TRANSACTION PESSIMISTIC", false, ""},
+
+		// select for update
+		{"SELECT * FROM t FOR UPDATE", true, "SELECT * FROM `t` FOR UPDATE"},
+		{"SELECT * FROM t WHERE a > 1 ORDER BY b LIMIT 2 FOR UPDATE NOWAIT", true, "SELECT * FROM `t` WHERE `a`>1 ORDER BY `b` LIMIT 2 FOR UPDATE NOWAIT"},
+		{"SELECT 1 FOR UPDATE", true, "SELECT 1 FOR UPDATE"},
+		{"SELECT * FROM t FOR UPDATE LIMIT 1", false, ""},
+		{"SELECT * FROM t FOR NOWAIT", false, ""},
+
 		// qualified select
 		{"SELECT a.b.c FROM t", true, "SELECT `a`.`b`.`c` FROM `t`"},
 		{"SELECT a.b.*.c FROM t", false, ""},
@@ -421,6 +433,20 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"DELETE t1, t2 FROM t1 INNER JOIN t2 INNER JOIN t3 WHERE t1.id=t2.id AND t2.id=t3.id limit 10;", false, ""},
 		{"DELETE t1, t2 FROM t1 INNER JOIN t2 INNER JOIN t3 WHERE t1.id=t2.id AND t2.id=t3.id order by t1.id;", false, ""},
 
+		// update statement
+		// single table syntax
+		{"UPDATE t SET id = id + 1", true, "UPDATE `t` SET `id`=`id`+1"},
+		{"UPDATE LOW_PRIORITY t SET id = 1", true, "UPDATE LOW_PRIORITY `t` SET `id`=1"},
+		{"UPDATE t AS u SET u.a = DEFAULT WHERE u.b > 0", true, "UPDATE `t` AS `u` SET `u`.`a`=DEFAULT WHERE `u`.`b`>0"},
+		{"UPDATE t SET a = 1, b = 2 WHERE c = 3 ORDER BY d LIMIT 10", true, "UPDATE `t` SET `a`=1, `b`=2 WHERE `c`=3 ORDER BY `d` LIMIT 10"},
+		{"UPDATE t SET", false, ""},
+		{"UPDATE t WHERE a = 1", false, ""},
+		// multiple table syntax
+		{"UPDATE t1, t2 SET t1.a = t2.b WHERE t1.id = t2.id", true, "UPDATE (`t1`) JOIN `t2` SET `t1`.`a`=`t2`.`b` WHERE `t1`.`id`=`t2`.`id`"},
+		{"UPDATE t1 LEFT JOIN t2 ON t1.id = t2.id SET t1.a = 1", true, "UPDATE `t1` LEFT JOIN `t2` ON `t1`.`id`=`t2`.`id` SET `t1`.`a`=1"},
+		{"UPDATE t1, t2 SET t1.a = 1 ORDER BY t1.a", false, ""},
+		{"UPDATE t1, t2 SET t1.a = 1 LIMIT 1", false, ""},
+
 		// for admin
 		{"admin show ddl;", true, "ADMIN SHOW DDL"},
 		{"admin show ddl jobs;", true, "ADMIN SHOW DDL JOBS"},
@@ -428,6 +454,13 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"admin show ddl jobs 20 where id=0;", true, "ADMIN SHOW DDL JOBS 20 WHERE `id`=0"},
 		{"admin show ddl jobs -1;", false, ""},
 
+		// for kill statement
+		{"kill 3", true, "KILL 3"},
+		{"kill connection 3", true, "KILL 3"},
+		{"kill query 3", true, "KILL QUERY 3"},
+		{"kill query", false, ""},
+		{"kill -3", false, ""},
+
 		// for insert ... set
 		{"INSERT INTO t SET a=1,b=2", true, "INSERT INTO `t` SET `a`=1,`b`=2"},
 		{"INSERT INTO t (a) SET a=1", false, ""},
@@ -539,6 +572,18 @@ func (s *testParserSuite) TestExpression(c *C) {
 		{"select {ts123 '1989-09-10 11:11:11'}", true, "SELECT '1989-09-10 11:11:11'"},
 		{"select {ts123 123}", true, "SELECT 123"},
 		{"select {ts123 1 xor 1}", true, "SELECT 1 XOR 1"},
+
+		// for like and regexp
+		{"select * from t where a like 'abc%'", true, "SELECT * FROM `t` WHERE `a` LIKE 'abc%'"},
+		{"select * from t where a not like 'abc%'", true, "SELECT * FROM `t` WHERE `a` NOT LIKE 'abc%'"},
+		{"select * from t where a like 'abc|%' escape '|'", true, "SELECT * FROM `t` WHERE `a` LIKE 'abc|%' ESCAPE '|'"},
+		{"select * from t where a like 'abc' escape ''", true, "SELECT * FROM `t` WHERE `a` LIKE 'abc'"},
+		{"select * from t where a like 'abc' escape 'ab'", false, ""},
+		{"select 'abc' regexp '^a', 'abc' rlike 'c$', 'abc' not regexp 'b'", true, "SELECT 'abc' REGEXP '^a','abc' REGEXP 'c$','abc' NOT REGEXP 'b'"},
+		{"select 'abc' not rlike 'b'", true, "SELECT 'abc' NOT REGEXP 'b'"},
+		{"show tables like 't%'", true, "SHOW TABLES LIKE 't%'"},
+		{"show databases like 'test'", true, "SHOW DATABASES LIKE 'test'"},
+		{"show variables like 'tidb_%'", true, "SHOW SESSION VARIABLES LIKE 'tidb_%'"},
 	}
 	s.RunTest(c, table)
 }
@@ -619,7 +664,28 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{"SELECT SUBSTRING('Quadratically' FROM 5);", true, "SELECT SUBSTRING('Quadratically', 5)"},
 		{"SELECT SUBSTRING('Quadratically' FROM 5 FOR 3);", true, "SELECT SUBSTRING('Quadratically', 5, 3)"},
 
+		{"SELECT TRIM('  bar   ');", true, "SELECT TRIM('  bar   ')"},
+		{"SELECT TRIM('x' FROM 'xxxbarxxx');", true, "SELECT TRIM('x' FROM 'xxxbarxxx')"},
+		{"SELECT TRIM(BOTH FROM '  bar   ');", true, "SELECT TRIM(BOTH FROM '  bar   ')"},
+		{"SELECT TRIM(LEADING 'x' FROM 'xxxbarxxx');", true, "SELECT TRIM(LEADING 'x' FROM 'xxxbarxxx')"},
+		{"SELECT TRIM(TRAILING 'xyz' FROM 'barxxyz');", true, "SELECT TRIM(TRAILING 'xyz' FROM 'barxxyz')"},
+		{"SELECT TRIM(BOTH 'x' FROM);", false, ""},
+
 		{"SELECT LEAST(), LEAST(1, 2, 3);", true, "SELECT LEAST(),LEAST(1, 2, 3)"},
+		{"SELECT GREATEST(1, 2, 3), COALESCE(NULL, 1), NULLIF(1, 1);", true, "SELECT GREATEST(1, 2, 3),COALESCE(NULL, 1),NULLIF(1, 1)"},
+
+		// control flow and cast functions
+		{"SELECT CASE WHEN a > 0 THEN 1 WHEN a < 0 THEN -1 END FROM t;", true, "SELECT CASE WHEN a>0 THEN 1 WHEN a<0 THEN -1 END FROM t"},
+		{"SELECT CASE a WHEN 1 THEN 'one' WHEN 2 THEN 'two' ELSE 'many' END FROM t;", true, "SELECT CASE a WHEN 1 THEN 'one' WHEN 2 THEN 'two' ELSE 'many' END FROM t"},
+		{"SELECT CASE a ELSE 1 END FROM t;", false, ""},
+		{"SELECT CASE WHEN 1 THEN 2;", false, ""},
+		{"SELECT CAST(1 AS SIGNED), CAST(1 AS UNSIGNED INTEGER), CAST('1' AS DECIMAL(10, 2));", true, "SELECT CAST(1 AS SIGNED),CAST(1 AS UNSIGNED),CAST('1' AS DECIMAL(10, 2))"},
+		{"SELECT CAST(1 AS CHAR), CAST(1 AS CHAR(5)), CAST(1 AS BINARY(3)), CAST(1 AS DOUBLE), CAST(1 AS JSON);", true, "SELECT CAST(1 AS CHAR),CAST(1 AS CHAR(5)),CAST(1 AS BINARY(3)),CAST(1 AS DOUBLE),CAST(1 AS JSON)"},
+		{"SELECT CAST('2020-01-01' AS DATE), CAST('2020-01-01' AS DATETIME(3)), CAST('10:00' AS TIME);", true, "SELECT CAST('2020-01-01' AS DATE),CAST('2020-01-01' AS DATETIME(3)),CAST('10:00' AS TIME)"},
+		{"SELECT CAST(1 AS INT);", false, ""},
+		{"SELECT CAST(1);", false, ""},
+		{"SELECT CONVERT(1, SIGNED), CONVERT('abc', CHAR(2));", true, "SELECT CONVERT(1, SIGNED),CONVERT('abc', CHAR(2))"},
+		{"SELECT CONVERT('abc' USING utf8mb4);", true, "SELECT CONVERT('abc' USING 'utf8mb4')"},
 		// information functions
 		{"SELECT DATABASE();", true, "SELECT DATABASE()"},
 		{"SELECT SCHEMA();", true, "SELECT SCHEMA()"},
@@ -761,6 +827,34 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{"SELECT YEARWEEK('2007-02-03');", true, "SELECT YEARWEEK('2007-02-03')"},
 		{"SELECT YEARWEEK('2007-02-03', 0);", true, "SELECT YEARWEEK('2007-02-03', 0)"},
 
+		// for date arithmetic
+		{"SELECT DATE_ADD('2008-01-02', INTERVAL 31 DAY);", true, "SELECT DATE_ADD('2008-01-02', INTERVAL 31 DAY)"},
+		{"SELECT DATE_ADD('2008-01-02 10:00:00', INTERVAL '1:1' MINUTE_SECOND);", true, "SELECT DATE_ADD('2008-01-02 10:00:00', INTERVAL '1:1' MINUTE_SECOND)"},
+		{"SELECT DATE_SUB('2008-01-02', INTERVAL 1 YEAR_MONTH);", true, "SELECT DATE_SUB('2008-01-02', INTERVAL 1 YEAR_MONTH)"},
+		{"SELECT DATE_ADD('2008-01-02', 31);", false, ""},
+		{"SELECT DATE_ADD('2008-01-02', INTERVAL 31 DAYS);", false, ""},
+		{"SELECT ADDDATE('2008-01-02', 31);", true, "SELECT ADDDATE('2008-01-02', INTERVAL 31 DAY)"},
+		{"SELECT ADDDATE('2008-01-02', INTERVAL 1 QUARTER);", true, "SELECT ADDDATE('2008-01-02', INTERVAL 1 QUARTER)"},
+		{"SELECT SUBDATE('2008-01-02', 31);", true, "SELECT SUBDATE('2008-01-02', INTERVAL 31 DAY)"},
+		{"SELECT SUBDATE('2008-01-02', INTERVAL 2 WEEK);", true, "SELECT SUBDATE('2008-01-02', INTERVAL 2 WEEK)"},
+		{"SELECT NOW() - INTERVAL 1 SECOND;", true, "SELECT DATE_SUB(NOW(), INTERVAL 1 SECOND)"},
+		{"SELECT '2008-01-02' + INTERVAL '1:1' MINUTE_SECOND;", true, "SELECT DATE_ADD('2008-01-02', INTERVAL '1:1' MINUTE_SECOND)"},
+		{"SELECT INTERVAL 1 DAY + '2008-01-02';", true, "SELECT DATE_ADD('2008-01-02', INTERVAL 1 DAY)"},
+		{"SELECT a + INTERVAL 1 DAY - INTERVAL 1 HOUR FROM t;", true, "SELECT DATE_SUB(DATE_ADD(`a`, INTERVAL 1 DAY), INTERVAL 1 HOUR) FROM `t`"},
+		{"SELECT 1 + a - INTERVAL 1 + 1 DAY FROM t;", true, "SELECT DATE_SUB(1+`a`, INTERVAL 1+1 DAY) FROM `t`"},
+		{"SELECT a + INTERVAL 1 DAY > b FROM t;", true, "SELECT DATE_ADD(`a`, INTERVAL 1 DAY)>`b` FROM `t`"},
+		{"SELECT a - INTERVAL 1 DAYS FROM t;", false, ""},
+		{"SELECT INTERVAL 1 DAY - a FROM t;", false, ""},
+
+		// for EXTRACT
+		{"SELECT EXTRACT(YEAR FROM '2009-07-02');", true, "SELECT EXTRACT(YEAR FROM '2009-07-02')"},
+		{"SELECT EXTRACT(DAY_MICROSECOND FROM '2009-07-02 01:02:03.000123');", true, "SELECT EXTRACT(DAY_MICROSECOND FROM '2009-07-02 01:02:03.000123')"},
+		{"SELECT EXTRACT(YEAR '2009-07-02');", false, ""},
+
+		// for UNIX_TIMESTAMP, FROM_UNIXTIME
+		{"SELECT UNIX_TIMESTAMP(), UNIX_TIMESTAMP('2009-07-02 01:02:03');", true, "SELECT UNIX_TIMESTAMP(),UNIX_TIMESTAMP('2009-07-02 01:02:03')"},
+		{"SELECT FROM_UNIXTIME(1447430881), FROM_UNIXTIME(1447430881, '%Y %D %M');", true, "SELECT FROM_UNIXTIME(1447430881),FROM_UNIXTIME(1447430881, '%Y %D %M')"},
+
 		// for ADDTIME, SUBTIME
 		{"SELECT ADDTIME('01:00:00.999999', '02:00:00.999998');", true, "SELECT ADDTIME('01:00:00.999999', '02:00:00.999998')"},
 		{"SELECT ADDTIME('02:00:00.999998');", true, "SELECT ADDTIME('02:00:00.999998')"},
@@ -880,6 +974,12 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{`SELECT RELEASE_ALL_LOCKS(1);`, true, "SELECT RELEASE_ALL_LOCKS(1)"},
 		{`SELECT UUID(1);`, true, "SELECT UUID(1)"},
 		{`SELECT UUID_SHORT(1)`, true, "SELECT UUID_SHORT(1)"},
+		// for json functions
+		{`SELECT JSON_EXTRACT(c, '$.a'), JSON_UNQUOTE(c);`, true, "SELECT JSON_EXTRACT(`c`, '$.a'),JSON_UNQUOTE(`c`)"},
+		{`SELECT JSON_OBJECT('a', 1), JSON_ARRAY(1, 'b');`, true, "SELECT JSON_OBJECT('a', 1),JSON_ARRAY(1, 'b')"},
+		{`SELECT c->'$.a', c->>'$.a' FROM t;`, true, "SELECT JSON_EXTRACT(`c`, '$.a'),JSON_UNQUOTE(JSON_EXTRACT(`c`, '$.a')) FROM `t`"},
+		{`SELECT * FROM t WHERE t.c->'$.a' = 1;`, true, "SELECT * FROM `t` WHERE JSON_EXTRACT(`t`.`c`, '$.a')=1"},
+		{`SELECT c->1 FROM t;`, false, ""},
 		// for aggregate functions
 		{`select avg(), avg(c1,c2) from t;`, false, "SELECT AVG(),AVG(`c1`, `c2`) FROM `t`"},
 		{`select avg(c2) from t;`, true, "SELECT AVG(`c2`) FROM `t`"},
@@ -896,6 +996,69 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 	s.RunTest(c, table)
 }
 
+func (s *testParserSuite) TestSubquery(c *C) {
+	table := []testCase{
+		// for scalar subquery
+		{"SELECT (select 1)", true, ""},
+		{"SELECT * FROM t WHERE a = (select max(b) from t1)", true, ""},
+		// for compare subquery
+		{"SELECT 1 > (select 1)", true, ""},
+		{"SELECT 1 > ANY (select 1)", true, ""},
+		{"SELECT 1 > ALL (select 1)", true, ""},
+		{"SELECT 1 > SOME (select 1)", true, ""},
+		{"SELECT 1 > ANY (1, 2)", false, ""},
+		// for in subquery
+		{"SELECT * FROM t WHERE a IN (select b from t1)", true, ""},
+		{"SELECT * FROM t WHERE a NOT IN (select b from t1 where t1.c = t.c)", true, ""},
+		// for exists subquery
+		{"SELECT EXISTS select 1", false, ""},
+		{"SELECT EXISTS (select 1)", true, ""},
+		{"SELECT + EXISTS (select 1)", true, ""},
+		{"SELECT - EXISTS (select 1)", true, ""},
+		{"SELECT NOT EXISTS (select 1)", true, ""},
+		{"SELECT + NOT EXISTS (select 1)", false, ""},
+		{"SELECT - NOT EXISTS (select 1)", false, ""},
+		{"SELECT * FROM t WHERE NOT EXISTS (select * from t1 where t1.a = t.a)", true, ""},
+	}
+	s.RunTest(c, table)
+}
+
+func (s *testParserSuite) TestSetOperator(c *C) {
+	table := []testCase{
+		{"select c1 from t1 union select c2 from t2", true, ""},
+		{"select c1 from t1 union all select c2 from t2", true, ""},
+		{"select c1 from t1 union distinct select c2 from t2", true, ""},
+		{"select c1 from t1 except select c2 from t2", true, ""},
+		{"select c1 from t1 intersect select c2 from t2", true, ""},
+		{"select c1 from t1 union select c2 from t2 intersect select c3 from t3 except select c4 from t4", true, ""},
+		{"select c1 from t1 union (select c2 from t2) order by c1 limit 1", true, ""},
+		{"(select c1 from t1) union all (select c2 from t2 order by c2 limit 1)", true, ""},
+		{"select 1 union select 2 union select 3", true, ""},
+		{"select * from (select 1 union select 2) as t", true, ""},
+		{"select * from t where a in (select b from t1 intersect select c from t2)", true, ""},
+		{"insert into t select c1 from t1 union select c2 from t2", true, ""},
+		{"explain select c1 from t1 except select c2 from t2", true, ""},
+		{"select c1 from t1 intersect all select c2 from t2", false, ""},
+		{"select c1 from t1 except all select c2 from t2", false, ""},
+		{"select c1 from t1 union", false, ""},
+	}
+	s.RunTest(c, table)
+
+	parser := parser.New()
+	stmt, err := parser.ParseOneStmt("select a from t1 union all (select b from t2) intersect select c from t3 order by a", "", "")
+	c.Assert(err, IsNil)
+	setOpr, ok := stmt.(*ast.SetOprStmt)
+	c.Assert(ok, IsTrue)
+	selects := setOpr.SelectList.Selects
+	c.Assert(selects, HasLen, 3)
+	c.Assert(selects[0].AfterSetOperator, IsNil)
+	c.Assert(*selects[1].AfterSetOperator, Equals, ast.UnionAll)
+	c.Assert(selects[1].IsInBraces, IsTrue)
+	c.Assert(*selects[2].AfterSetOperator, Equals, ast.Intersect)
+	c.Assert(selects[0].Fields.Fields[0].Text(), Equals, "a")
+	c.Assert(setOpr.OrderBy, NotNil)
+}
+
 func (s *testParserSuite) TestIdentifier(c *C) {
 	table := []testCase{
 		// for quote identifier
@@ -1359,6 +1522,251 @@ func (s *testParserSuite) TestSQLNoCache(c *C) {
 	}
 }
 
+func (s *testParserSuite) TestSelectLock(c *C) {
+	table := []struct {
+		src    string
+		lockTp ast.SelectLockType
+		text   string
+	}{
+		{"select a+1 from t", ast.SelectLockNone, "a+1"},
+		{"select a+1 from t for update", ast.SelectLockForUpdate, "a+1"},
+		{"select a+1 from t for update nowait", ast.SelectLockForUpdateNoWait, "a+1"},
+		{"select a+1 for update", ast.SelectLockForUpdate, "a+1"},
+	}
+
+	parser := parser.New()
+	for _, tt := range table {
+		stmt, err := parser.ParseOneStmt(tt.src, "", "")
+		c.Assert(err, IsNil)
+		sel := stmt.(*ast.SelectStmt)
+		c.Assert(sel.LockTp, Equals, tt.lockTp)
+		c.Assert(sel.Fields.Fields[0].Text(), Equals, tt.text)
+	}
+}
+
+func (s *testParserSuite) TestAsOf(c *C) {
+	table := []struct {
+		src   string
+		ok    bool
+		asOf  bool
+		alias string
+	}{
+		{"select * from t", true, false, ""},
+		{"select * from t as of timestamp '2020-10-01 10:00:00'", true, true, ""},
+		{"select * from t as of timestamp now() where a > 1", true, true, ""},
+		{"select * from t t1 as of timestamp '2020-10-01 10:00:00' use index (idx)", true, true, "t1"},
+		{"select * from t as t1 as of timestamp '2020-10-01 10:00:00'", true, true, "t1"},
+		{"select * from t as of timestamp '2020-10-01 10:00:00' t1", true, true, "t1"},
+		{"select * from t as of timestamp now() as t1 use index (idx) where t1.a > 1", true, true, "t1"},
+		{"select * from t as of timestamp 1 t1 as of timestamp 2", false, false, ""},
+		{"select * from t as of '2020-10-01 10:00:00'", false, false, ""},
+		{"select * from t as of", false, false, ""},
+		{"select * from t as of timestamp", false, false, ""},
+		{"select * from t as of as of timestamp 1", false, false, ""},
+	}
+
+	parser := parser.New()
+	for _, tt := range table {
+		stmt, err := parser.ParseOneStmt(tt.src, "", "")
+		if !tt.ok {
+			c.Assert(err, NotNil, Commentf("source %v", tt.src))
+			continue
+		}
+		c.Assert(err, IsNil, Commentf("source %v", tt.src))
+		ts := stmt.(*ast.SelectStmt).From.TableRefs.Left.(*ast.TableSource)
+		c.Assert(ts.Source.(*ast.TableName).AsOf != nil, Equals, tt.asOf, Commentf("source %v", tt.src))
+		c.Assert(ts.AsName.O, Equals, tt.alias)
+	}
+}
+
+func (s *testParserSuite) TestWindowFunctions(c *C) {
+	table := []testCase{
+		{`select row_number() over () from t`, true, ""},
+		{`select rank() over (partition by a order by b desc), dense_rank() over (order by b) from t`, true, ""},
+		{`select ntile(3) over (order by a) from t`, true, ""},
+		{`select lead(a) over (order by a), lag(a, 2, 0) over (order by a) from t`, true, ""},
+		{`select first_value(a) over w from t window w as (order by a)`, true, ""},
+		{`select sum(a) over (w rows 1 preceding), rank() over (w) from t window w as (partition by b), w2 as (w order by a)`, true, ""},
+		{`select a from t group by a having a > 1 window w as () order by a`, true, ""},
+		{`select first_value(a) over w from t`, true, ""},
+		{`select sum(a) over w from t window w`, false, ""},
+		{`select sum(a) over w from t window w as order by a`, false, ""},
+		{`select sum(a) over w from t window w as (order by a) window w2 as ()`, false, ""},
+		{`select sum(a) over (partition by b w) from t`, false, ""},
+		{`create table window (a int)`, false, ""},
+		{`select last_value(a) over (order by b rows between unbounded preceding and unbounded following) from t`, true, ""},
+		{`select sum(a) over (partition by b order by a rows 2 preceding) from t`, true, ""},
+		{`select avg(a) over (order by a range between 1.5 preceding and current row) from t`, true, ""},
+		{`select count(*) over (rows between 1 preceding and 1 following), count(a) over () from t`, true, ""},
+		{`select max(a) over (partition by b), min(a) over (order by a range current row) from t`, true, ""},
+		{`select sum(a) from t group by b order by sum(a) over ()`, true, ""},
+		{`select row_number() from t`, false, ""},
+		{`select row_number() over (rows a preceding) from t`, false, ""},
+		{`select rank() over (order by a rows between current row) from t`, false, ""},
+		{`create table rank (a int)`, false, ""},
+	}
+	s.RunTest(c, table)
+
+	parser := parser.New()
+	stmt, err := parser.ParseOneStmt("select sum(a) over (partition by b order by c range between 1 preceding and unbounded following) from t", "", "")
+	c.Assert(err, IsNil)
+	expr := stmt.(*ast.SelectStmt).Fields.Fields[0].Expr.(*ast.WindowFuncExpr)
+	c.Assert(expr.F, Equals, "sum")
+	c.Assert(expr.Spec.PartitionBy.Items, HasLen, 1)
+	c.Assert(expr.Spec.OrderBy.Items, HasLen, 1)
+	c.Assert(expr.Spec.Frame.Type, Equals, ast.Ranges)
+	c.Assert(expr.Spec.Frame.Extent.Start.Type, Equals, ast.Preceding)
+	c.Assert(expr.Spec.Frame.Extent.Start.Expr, NotNil)
+	c.Assert(expr.Spec.Frame.Extent.End.Type, Equals, ast.Following)
+	c.Assert(expr.Spec.Frame.Extent.End.UnBounded, IsTrue)
+
+	stmt, err = parser.ParseOneStmt("select sum(a) over w, sum(a) over (w2 rows 1 preceding) from t window w as (partition by b), w2 as (w order by c)", "", "")
+	c.Assert(err, IsNil)
+	sel := stmt.(*ast.SelectStmt)
+	expr = sel.Fields.Fields[0].Expr.(*ast.WindowFuncExpr)
+	c.Assert(expr.Spec.Ref.O, Equals, "w")
+	c.Assert(expr.Spec.OnlyAlias, IsTrue)
+	c.Assert(sel.Fields.Fields[0].Text(), Equals, "sum(a) over w")
+	expr = sel.Fields.Fields[1].Expr.(*ast.WindowFuncExpr)
+	c.Assert(expr.Spec.Ref.O, Equals, "w2")
+	c.Assert(expr.Spec.OnlyAlias, IsFalse)
+	c.Assert(expr.Spec.Frame.Type, Equals, ast.Rows)
+	c.Assert(sel.Fields.Fields[1].Text(), Equals, "sum(a) over (w2 rows 1 preceding)")
+	c.Assert(sel.WindowSpecs, HasLen, 2)
+	c.Assert(sel.WindowSpecs[0].Name.O, Equals, "w")
+	c.Assert(sel.WindowSpecs[0].PartitionBy.Items, HasLen, 1)
+	c.Assert(sel.WindowSpecs[1].Name.O, Equals, "w2")
+	c.Assert(sel.WindowSpecs[1].Ref.O, Equals, "w")
+	c.Assert(sel.WindowSpecs[1].OrderBy.Items, HasLen, 1)
+}
+
+func (s *testParserSuite) TestCTE(c *C) {
+	table := []testCase{
+		{`with cte as (select 1) select * from cte`, true, ""},
+		{`with cte(a, b) as (select 1, 2), cte2 as (select a from cte) select * from cte2`, true, ""},
+		{`with recursive cte(n) as (select 1 union all select n + 1 from cte where n < 10) select * from cte`, true, ""},
+		{`with cte as (select 1 union select 2) select * from cte union all select * from cte`, true, ""},
+		{`select * from (with cte as (select 1) select * from cte) t`, true, ""},
+		{`select a from t where a in (with cte as (select 1) select * from cte)`, true, ""},
+		{`insert into t with cte as (select 1) select * from cte`, true, ""},
+		{`explain with cte as (select 1) select * from cte`, true, ""},
+		{`with cte as select 1 select * from cte`, false, ""},
+		{`with cte() as (select 1) select * from cte`, false, ""},
+		{`with recursive as (select 1) select 1`, false, ""},
+		{`with cte as (select 1)`, false, ""},
+	}
+	s.RunTest(c, table)
+
+	parser := parser.New()
+	stmt, err := parser.ParseOneStmt("with recursive cte(n, m) as (select 1, 2 union all select n + 1, m from cte), cte2 as (select * from cte) select * from cte2", "", "")
+	c.Assert(err, IsNil)
+	sel := stmt.(*ast.SelectStmt)
+	c.Assert(sel.With.IsRecursive, IsTrue)
+	c.Assert(sel.With.CTEs, HasLen, 2)
+	cte := sel.With.CTEs[0]
+	c.Assert(cte.Name.L, Equals, "cte")
+	c.Assert(cte.ColNameList, DeepEquals, []model.CIStr{model.NewCIStr("n"), model.NewCIStr("m")})
+	c.Assert(cte.IsRecursive, IsTrue)
+	c.Assert(cte.Query.Query, FitsTypeOf, &ast.SetOprStmt{})
+	c.Assert(sel.With.CTEs[1].ColNameList, HasLen, 0)
+
+	stmt, err = parser.ParseOneStmt("with cte as (select 1) select * from cte union select 2", "", "")
+	c.Assert(err, IsNil)
+	setOpr := stmt.(*ast.SetOprStmt)
+	c.Assert(setOpr.With.CTEs, HasLen, 1)
+	c.Assert(setOpr.With.IsRecursive, IsFalse)
+	c.Assert(setOpr.SelectList.Selects[0].With, IsNil)
+}
+
+func (s *testParserSuite) TestView(c *C) {
+	table := []testCase{
+		{`create view v as select * from t`, true, ""},
+		{`create or replace view test.v (a, b) as select c, d from t where c > 1`, true, ""},
+		{`create view v as select 1 union select 2`, true, ""},
+		{`create view v as with cte as (select 1) select * from cte`, true, ""},
+		{`create view v as select a from t union (select b from t) order by a limit 1`, true, ""},
+		{`create view v as select a from t for update`, true, ""},
+		{`create view v`, false, ""},
+		{`create view v () as select 1`, false, ""},
+		{`create replace view v as select 1`, false, ""},
+		{`drop view v`, true, ""},
+		{`drop view if exists v, test.v2 cascade`, true, ""},
+		{`drop view`, false, ""},
+		{`show create view v`, true, ""},
+		{`show create view test.v`, true, ""},
+	}
+	s.RunTest(c, table)
+
+	parser := parser.New()
+	stmt, err := parser.ParseOneStmt("create or replace view test.v (x, y) as  select a, b from t where a > 1 ; ", "", "")
+	c.Assert(err, IsNil)
+	cv := stmt.(*ast.CreateViewStmt)
+	c.Assert(cv.OrReplace, IsTrue)
+	c.Assert(cv.ViewName.Schema.L, Equals, "test")
+	c.Assert(cv.ViewName.Name.L, Equals, "v")
+	c.Assert(cv.Cols, DeepEquals, []model.CIStr{model.NewCIStr("x"), model.NewCIStr("y")})
+	c.Assert(cv.Select.Text(), Equals, "select a, b from t where a > 1")
+
+	stmts, _, err := parser.Parse("create view v as select 1 union select 2; select 1", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmts, HasLen, 2)
+	cv = stmts[0].(*ast.CreateViewStmt)
+	c.Assert(cv.OrReplace, IsFalse)
+	c.Assert(cv.Cols, HasLen, 0)
+	c.Assert(cv.Select.Text(), Equals, "select 1 union select 2")
+
+	stmt, err = parser.ParseOneStmt("drop view if exists v1, v2", "", "")
+	c.Assert(err, IsNil)
+	drop := stmt.(*ast.DropTableStmt)
+	c.Assert(drop.IsView, IsTrue)
+	c.Assert(drop.IfExists, IsTrue)
+	c.Assert(drop.Tables, HasLen, 2)
+}
+
+func (s *testParserSuite) TestPartition(c *C) {
+	table := []testCase{
+		{`create table t (a int) partition by range (a) (partition p0 values less than (10), partition p1 values less than maxvalue)`, true, ""},
+		{`create table t (a date) partition by range (year(a)) (partition p0 values less than (2000) comment 'old', partition p1 values less than (maxvalue))`, true, ""},
+		{`create table t (a int) partition by hash (a) partitions 4`, true, ""},
+		{`create table t (a int) partition by hash (a + 1) (partition p0, partition p1)`, true, ""},
+		{`create table t (a int) partition by range (a)`, true, ""},
+		{`create table t (a int) partition by list (a)`, false, ""},
+		{`create table t (a int) partition by range (a) (partition values less than (10))`, false, ""},
+		{`alter table t add partition (partition p2 values less than (20))`, true, ""},
+		{`alter table t drop partition p0`, true, ""},
+		{`alter table t drop partition if exists p0, p1`, true, ""},
+		{`alter table t truncate partition p0, p1`, true, ""},
+		{`alter table t drop partition`, false, ""},
+	}
+	s.RunTest(c, table)
+
+	parser := parser.New()
+	stmt, err := parser.ParseOneStmt("create table t (a int) partition by range (a) (partition p0 values less than (10) comment = 'p', partition p1 values less than maxvalue)", "", "")
+	c.Assert(err, IsNil)
+	part := stmt.(*ast.CreateTableStmt).Partition
+	c.Assert(part.Tp, Equals, model.PartitionTypeRange)
+	c.Assert(part.Num, Equals, uint64(0))
+	c.Assert(part.Definitions, HasLen, 2)
+	c.Assert(part.Definitions[0].Name.L, Equals, "p0")
+	c.Assert(part.Definitions[0].LessThan, HasLen, 1)
+	c.Assert(part.Definitions[0].Comment, Equals, "p")
+	c.Assert(part.Definitions[1].MaxValue, IsTrue)
+
+	stmt, err = parser.ParseOneStmt("create table t (a int) partition by hash (a) partitions 3", "", "")
+	c.Assert(err, IsNil)
+	part = stmt.(*ast.CreateTableStmt).Partition
+	c.Assert(part.Tp, Equals, model.PartitionTypeHash)
+	c.Assert(part.Num, Equals, uint64(3))
+	c.Assert(part.Definitions, HasLen, 0)
+
+	stmt, err = parser.ParseOneStmt("alter table t drop partition p0, p1", "", "")
+	c.Assert(err, IsNil)
+	specs := stmt.(*ast.AlterTableStmt).Specs
+	c.Assert(specs, HasLen, 1)
+	c.Assert(specs[0].Tp, Equals, ast.AlterTableDropPartition)
+	c.Assert(specs[0].PartitionNames, DeepEquals, []model.CIStr{model.NewCIStr("p0"), model.NewCIStr("p1")})
+}
+
 func (s *testParserSuite) TestEscape(c *C) {
 	table := []testCase{
 		{`select """;`, false, ""},
@@ -1393,8 +1801,122 @@ func (s *testParserSuite) TestExplain(c *C) {
 		{"EXPLAIN SELECT 1", true, "EXPLAIN FORMAT = 'row' SELECT 1"},
 		{"EXPLAIN FORMAT = JSON SELECT 1", true, "EXPLAIN FORMAT = 'json' SELECT 1"},
 		{"EXPLAIN FORMAT = 'hint' SELECT 1", true, "EXPLAIN FORMAT = 'hint' SELECT 1"},
+		{"EXPLAIN ANALYZE SELECT 1", true, "EXPLAIN ANALYZE SELECT 1"},
+		{"EXPLAIN ANALYZE SELECT c1 FROM t1 ORDER BY c1", true, "EXPLAIN ANALYZE SELECT `c1` FROM `t1` ORDER BY `c1`"},
+	}
+	s.RunTest(c, table)
+}
+
+func (s *testParserSuite) TestPrepare(c *C) {
+	table := []testCase{
+		{"PREPARE pname FROM 'SELECT ?'", true, "PREPARE `pname` FROM 'SELECT ?'"},
+		{"PREPARE pname FROM @test", true, "PREPARE `pname` FROM @`test`"},
+		{"PREPARE `` FROM @test", true, "PREPARE `` FROM @`test`"},
+		{"PREPARE pname FROM 'SELECT ?' 'abc'", false, ""},
+		{"EXECUTE pname", true, "EXECUTE `pname`"},
+		{"EXECUTE pname USING @a, @b", true, "EXECUTE `pname` USING @`a`,@`b`"},
+		{"EXECUTE pname USING 1", false, ""},
+		{"DEALLOCATE PREPARE pname", true, "DEALLOCATE PREPARE `pname`"},
+		{"DROP PREPARE pname", true, "DEALLOCATE PREPARE `pname`"},
+		{"SELECT * FROM t WHERE a = ? AND b > ?", true, "SELECT * FROM `t` WHERE `a`=? AND `b`>?"},
+		{"INSERT INTO t VALUES (?, ?)", true, "INSERT INTO `t` VALUES (?,?)"},
+		{"SELECT * FROM t LIMIT ?, ?", true, "SELECT * FROM `t` LIMIT ?,?"},
+	}
+	s.RunTest(c, table)
+
+	parser := parser.New()
+	stmt, err := parser.ParseOneStmt("PREPARE stmt1 FROM 'SELECT ? + ?'", "", "")
+	c.Assert(err, IsNil)
+	prepare := stmt.(*ast.PrepareStmt)
+	c.Assert(prepare.Name, Equals, "stmt1")
+	c.Assert(prepare.SQLText, Equals, "SELECT ? + ?")
+	c.Assert(prepare.SQLVar, IsNil)
+
+	stmt, err = parser.ParseOneStmt("EXECUTE stmt1 USING @a, @b", "", "")
+	c.Assert(err, IsNil)
+	execute := stmt.(*ast.ExecuteStmt)
+	c.Assert(execute.Name, Equals, "stmt1")
+	c.Assert(execute.UsingVars, HasLen, 2)
+}
+
+func (s *testParserSuite) TestPrivilege(c *C) {
+	table := []testCase{
+		// for create user
+		{`CREATE USER 'test'`, true, "CREATE USER `test`@`%`"},
+		{`CREATE USER test`, true, "CREATE USER `test`@`%`"},
+		{"CREATE USER `test`", true, "CREATE USER `test`@`%`"},
+		{`CREATE USER IF NOT EXISTS 'root'@'localhost' IDENTIFIED BY 'new-password'`, true, "CREATE USER IF NOT EXISTS `root`@`localhost` IDENTIFIED BY 'new-password'"},
+		{`CREATE USER 'root'@'localhost' IDENTIFIED BY 'new-password'`, true, "CREATE USER `root`@`localhost` IDENTIFIED BY 'new-password'"},
+		{`CREATE USER 'root'@'localhost' IDENTIFIED BY PASSWORD 'hashstring'`, true, "CREATE USER `root`@`localhost` IDENTIFIED WITH 'mysql_native_password' AS 'hashstring'"},
+		{`CREATE USER 'root'@'localhost' IDENTIFIED BY 'new-password', 'root'@'127.0.0.1' IDENTIFIED BY PASSWORD 'hashstring'`, true, ""},
+		{`CREATE USER 'u1'@'%' IDENTIFIED WITH 'caching_sha2_password'`, true, ""},
+		{`CREATE USER 'u1'@'%' IDENTIFIED WITH 'caching_sha2_password' BY 'alic123'`, true, ""},
+		{`CREATE USER 'u1'@'%' IDENTIFIED WITH mysql_native_password AS '*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9'`, true, ""},
+		{`CREATE USER 'u1'@'%' IDENTIFIED AS 'abc'`, false, ""},
+		{`DROP USER 'root'@'localhost', 'root1'@'localhost'`, true, ""},
+		{`DROP USER IF EXISTS 'root'@'localhost'`, true, ""},
+		{`DROP USER current_user()`, true, ""},
+
+		// for grant statement
+		{"GRANT ALL ON db1.* TO 'jeffrey'@'localhost';", true, ""},
+		{"GRANT ALL ON db1.* TO 'jeffrey'@'localhost' WITH GRANT OPTION;", true, ""},
+		{"GRANT SELECT ON db2.invoice TO 'jeffrey'@'localhost';", true, ""},
+		{"GRANT ALL ON *.* TO 'someuser'@'somehost';", true, ""},
+		{"GRANT SELECT, INSERT ON *.* TO 'someuser'@'somehost';", true, ""},
+		{"GRANT ALL ON mydb.* TO 'someuser'@'somehost';", true, ""},
+		{"GRANT SELECT, INSERT ON mydb.* TO 'someuser'@'somehost';", true, ""},
+		{"GRANT ALL ON mydb.mytbl TO 'someuser'@'somehost';", true, ""},
+		{"GRANT SELECT, INSERT ON mydb.mytbl TO 'someuser'@'somehost';", true, ""},
+		{"GRANT SELECT ON TABLE mydb.mytbl TO 'someuser'@'somehost';", true, ""},
+		{"GRANT ALL PRIVILEGES ON * TO 'someuser'@'somehost' IDENTIFIED BY 'pass';", true, ""},
+		{"GRANT CREATE USER, SUPER, SHOW DATABASES, PROCESS, GRANT OPTION ON *.* TO 'u'@'h';", true, ""},
+		{"GRANT ALL ON db1.* TO 'jeffrey'@'localhost' WITH", false, ""},
+
+		// for revoke statement
+		{"REVOKE ALL ON db1.* FROM 'jeffrey'@'localhost';", true, ""},
+		{"REVOKE SELECT ON db2.invoice FROM 'jeffrey'@'localhost';", true, ""},
+		{"REVOKE ALL ON *.* FROM 'someuser'@'somehost';", true, ""},
+		{"REVOKE GRANT OPTION ON mydb.mytbl FROM 'someuser'@'somehost';", true, ""},
+		{"REVOKE ALL ON db1.* TO 'jeffrey'@'localhost';", false, ""},
+
+		// for show grants
+		{"SHOW GRANTS", true, "SHOW GRANTS"},
+		{"SHOW GRANTS FOR 'test'@'localhost'", true, "SHOW GRANTS FOR `test`@`localhost`"},
+		{"SHOW GRANTS FOR current_user()", true, ""},
+		{"SHOW GRANTS FOR current_user", true, ""},
 	}
 	s.RunTest(c, table)
+
+	parser := parser.New()
+	stmt, err := parser.ParseOneStmt("CREATE USER 'u1'@'localhost' IDENTIFIED WITH 'Caching_Sha2_Password' BY 'pwd', u2@`%`", "", "")
+	c.Assert(err, IsNil)
+	createUser := stmt.(*ast.CreateUserStmt)
+	c.Assert(createUser.Specs, HasLen, 2)
+	c.Assert(createUser.Specs[0].User.String(), Equals, "u1@localhost")
+	c.Assert(createUser.Specs[0].AuthOpt.AuthPlugin, Equals, mysql.AuthCachingSha2Password)
+	c.Assert(createUser.Specs[0].AuthOpt.ByAuthString, IsTrue)
+	c.Assert(createUser.Specs[0].AuthOpt.AuthString, Equals, "pwd")
+	c.Assert(createUser.Specs[1].User.String(), Equals, "u2@%")
+	c.Assert(createUser.Specs[1].AuthOpt, IsNil)
+
+	stmt, err = parser.ParseOneStmt("GRANT SELECT, INSERT ON TABLE db.t TO 'u1'@'localhost' WITH GRANT OPTION", "", "")
+	c.Assert(err, IsNil)
+	grant := stmt.(*ast.GrantStmt)
+	c.Assert(grant.Privs, HasLen, 2)
+	c.Assert(grant.Privs[0].Priv, Equals, mysql.SelectPriv)
+	c.Assert(grant.Privs[1].Priv, Equals, mysql.InsertPriv)
+	c.Assert(grant.ObjectType, Equals, ast.ObjectTypeTable)
+	c.Assert(grant.Level.Level, Equals, ast.GrantLevelTable)
+	c.Assert(grant.Level.DBName, Equals, "db")
+	c.Assert(grant.Level.TableName, Equals, "t")
+	c.Assert(grant.WithGrant, IsTrue)
+
+	stmt, err = parser.ParseOneStmt("SHOW GRANTS FOR 'u1'@'localhost'", "", "")
+	c.Assert(err, IsNil)
+	show := stmt.(*ast.ShowStmt)
+	c.Assert(show.Tp, Equals, ast.ShowStmtType(ast.ShowGrants))
+	c.Assert(show.User.Username, Equals, "u1")
+	c.Assert(show.User.Hostname, Equals, "localhost")
 }
 
 func (s *testParserSuite) TestSQLModeANSIQuotes(c *C) {
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/parser/types"
 )
 
 var (
@@ -106,6 +107,7 @@ type stmtTexter interface {
 // New returns a Parser object.
 func New() *Parser {
 	if ast.NewValueExpr == nil ||
+		ast.NewParamMarkerExpr == nil ||
 		ast.NewHexLiteral == nil ||
 		ast.NewBitLiteral == nil {
 		panic("no parser driver (forgotten import?) https://github.com/pingcap/tidb/parser/issues/43")
@@ -286,3 +288,16 @@ func getUint64FromNUM(num interface{}) uint64 {
 	}
 	return 0
 }
+
+// castTargetType fills the display length and decimal of a CAST/CONVERT target
+// type that are not given explicitly.
+func castTargetType(tp *types.FieldType) *types.FieldType {
+	defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimalForCast(tp.Tp)
+	if tp.Flen == types.UnspecifiedLength {
+		tp.Flen = defaultFlen
+	}
+	if tp.Decimal == types.UnspecifiedLength {
+		tp.Decimal = defaultDecimal
+	}
+	return tp
+}
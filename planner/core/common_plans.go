@@ -15,17 +15,22 @@ package core
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/chunk"
 )
 
 // ShowDDL is for showing DDL information.
@@ -33,6 +38,89 @@ type ShowDDL struct {
 	baseSchemaProducer
 }
 
+// Prepare represents prepare plan.
+type Prepare struct {
+	baseSchemaProducer
+
+	Name    string
+	SQLText string
+}
+
+// Execute represents prepare plan.
+type Execute struct {
+	baseSchemaProducer
+
+	Name          string
+	UsingVars     []expression.Expression
+	PrepareParams []types.Datum
+	ExecID        uint32
+	Stmt          ast.StmtNode
+	Plan          Plan
+}
+
+// OptimizePreparedPlan optimizes the prepared statement.
+func (e *Execute) OptimizePreparedPlan(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema) error {
+	vars := sctx.GetSessionVars()
+	if e.Name != "" {
+		e.ExecID = vars.PreparedStmtNameToID[e.Name]
+	}
+	prepared, ok := vars.PreparedStmts[e.ExecID].(*ast.Prepared)
+	if !ok {
+		return errors.Trace(ErrStmtNotFound)
+	}
+
+	if e.PrepareParams != nil {
+		// For binary protocol execute, arguments are sent by the client.
+		if len(prepared.Params) != len(e.PrepareParams) {
+			return errors.Trace(ErrWrongParamCount)
+		}
+		for i, val := range e.PrepareParams {
+			param := prepared.Params[i].(*driver.ParamMarkerExpr)
+			param.Datum = val
+			param.InExecute = true
+		}
+	} else {
+		// For `execute stmt using @a, @b, @c`, the values are evaluated from e.UsingVars.
+		if len(prepared.Params) != len(e.UsingVars) {
+			return errors.Trace(ErrWrongParamCount)
+		}
+		for i, usingVar := range e.UsingVars {
+			val, err := usingVar.Eval(chunk.Row{})
+			if err != nil {
+				return err
+			}
+			param := prepared.Params[i].(*driver.ParamMarkerExpr)
+			param.Datum = val
+			param.InExecute = true
+		}
+	}
+
+	if prepared.SchemaVersion != is.SchemaMetaVersion() {
+		// If the schema version has changed we need to preprocess it again,
+		// if this time it failed, the real reason for the error is schema changed.
+		err := Preprocess(sctx, prepared.Stmt, is, InPrepare)
+		if err != nil {
+			return ErrSchemaChanged.GenWithStack("Schema change caused error: %s", err.Error())
+		}
+		prepared.SchemaVersion = is.SchemaMetaVersion()
+	}
+	p, names, err := OptimizeAstNode(ctx, sctx, prepared.Stmt, is)
+	if err != nil {
+		return err
+	}
+	e.names = names
+	e.Stmt = prepared.Stmt
+	e.Plan = p
+	return nil
+}
+
+// Deallocate represents deallocate plan.
+type Deallocate struct {
+	baseSchemaProducer
+
+	Name string
+}
+
 // Set represents a plan for set stmt.
 type Set struct {
 	baseSchemaProducer
@@ -68,6 +156,19 @@ type Insert struct {
 	AllAssignmentsAreConstant bool
 }
 
+// Update represents Update plan.
+type Update struct {
+	baseSchemaProducer
+
+	OrderedList []*expression.Assignment
+
+	AllAssignmentsAreConstant bool
+
+	SelectPlan PhysicalPlan
+
+	TblColPosInfos TblColPosInfoSlice
+}
+
 // Delete represents a delete plan.
 type Delete struct {
 	baseSchemaProducer
@@ -120,6 +221,7 @@ type Explain struct {
 
 	TargetPlan Plan
 	Format     string
+	Analyze    bool
 	ExecStmt   ast.StmtNode
 
 	Rows           [][]string
@@ -132,8 +234,10 @@ func (e *Explain) prepareSchema() error {
 	format := strings.ToLower(e.Format)
 
 	switch {
-	case format == ast.ExplainFormatROW:
+	case format == ast.ExplainFormatROW && !e.Analyze:
 		fieldNames = []string{"id", "count", "task", "operator info"}
+	case format == ast.ExplainFormatROW && e.Analyze:
+		fieldNames = []string{"id", "count", "task", "operator info", "execution info"}
 	case format == ast.ExplainFormatDOT:
 		fieldNames = []string{"dot contents"}
 	default:
@@ -205,10 +309,24 @@ func (e *Explain) explainPlanInRowFormat(p Plan, taskType, indent string, isLast
 		if x.SelectPlan != nil {
 			err = e.explainPlanInRowFormat(x.SelectPlan, "root", childIndent, true)
 		}
+	case *Update:
+		if x.SelectPlan != nil {
+			err = e.explainPlanInRowFormat(x.SelectPlan, "root", childIndent, true)
+		}
 	case *Delete:
 		if x.SelectPlan != nil {
 			err = e.explainPlanInRowFormat(x.SelectPlan, "root", childIndent, true)
 		}
+	case *PhysicalCTE:
+		// The seed and recursive parts are shared by all the references of a CTE,
+		// so they are only explained under the first reference.
+		if e.explainedPlans[x.SeedPlan.ID()] {
+			break
+		}
+		err = e.explainPlanInRowFormat(x.SeedPlan, "root", childIndent, x.RecurPlan == nil)
+		if err == nil && x.RecurPlan != nil {
+			err = e.explainPlanInRowFormat(x.RecurPlan, "root", childIndent, true)
+		}
 	}
 	return
 }
@@ -289,6 +407,13 @@ func (e *Explain) prepareOperatorInfo(p Plan, taskType string, indent string, is
 	}
 	explainID := p.ExplainID().String()
 	row := []string{PrettyIdentifier(explainID, indent, isLastChild), count, taskType, operatorInfo}
+	if e.Analyze {
+		execInfo := "N/A"
+		if coll := e.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl; coll != nil && coll.Exists(explainID) {
+			execInfo = coll.Get(explainID).String()
+		}
+		row = append(row, execInfo)
+	}
 	e.Rows = append(e.Rows, row)
 }
 
@@ -61,6 +61,29 @@ var (
 	ErrCartesianProductUnsupported     = terror.ClassOptimizer.New(mysql.ErrCartesianProductUnsupported, mysql.MySQLErrName[mysql.ErrCartesianProductUnsupported])
 	ErrStmtNotFound                    = terror.ClassOptimizer.New(mysql.ErrPreparedStmtNotFound, mysql.MySQLErrName[mysql.ErrPreparedStmtNotFound])
 	ErrAmbiguous                       = terror.ClassOptimizer.New(mysql.ErrNonUniq, mysql.MySQLErrName[mysql.ErrNonUniq])
+	ErrMultiUpdatePKConflict           = terror.ClassOptimizer.New(mysql.ErrMultiUpdateKeyConflict, mysql.MySQLErrName[mysql.ErrMultiUpdateKeyConflict])
+
+	ErrWindowInvalidWindowFuncUse            = terror.ClassOptimizer.New(mysql.ErrWindowInvalidWindowFuncUse, mysql.MySQLErrName[mysql.ErrWindowInvalidWindowFuncUse])
+	ErrWindowNestedWindowFuncUseInWindowSpec = terror.ClassOptimizer.New(mysql.ErrWindowNestedWindowFuncUseInWindowSpec, mysql.MySQLErrName[mysql.ErrWindowNestedWindowFuncUseInWindowSpec])
+	ErrWindowFrameStartIllegal               = terror.ClassOptimizer.New(mysql.ErrWindowFrameStartIllegal, mysql.MySQLErrName[mysql.ErrWindowFrameStartIllegal])
+	ErrWindowFrameEndIllegal                 = terror.ClassOptimizer.New(mysql.ErrWindowFrameEndIllegal, mysql.MySQLErrName[mysql.ErrWindowFrameEndIllegal])
+	ErrWindowFrameIllegal                    = terror.ClassOptimizer.New(mysql.ErrWindowFrameIllegal, mysql.MySQLErrName[mysql.ErrWindowFrameIllegal])
+	ErrWindowRangeFrameOrderType             = terror.ClassOptimizer.New(mysql.ErrWindowRangeFrameOrderType, mysql.MySQLErrName[mysql.ErrWindowRangeFrameOrderType])
+	ErrWindowFunctionIgnoresFrame            = terror.ClassOptimizer.New(mysql.ErrWindowFunctionIgnoresFrame, mysql.MySQLErrName[mysql.ErrWindowFunctionIgnoresFrame])
+	ErrWindowNoSuchWindow                    = terror.ClassOptimizer.New(mysql.ErrWindowNoSuchWindow, mysql.MySQLErrName[mysql.ErrWindowNoSuchWindow])
+	ErrWindowCircularityInWindowGraph        = terror.ClassOptimizer.New(mysql.ErrWindowCircularityInWindowGraph, mysql.MySQLErrName[mysql.ErrWindowCircularityInWindowGraph])
+	ErrWindowNoChildPartitioning             = terror.ClassOptimizer.New(mysql.ErrWindowNoChildPartitioning, mysql.MySQLErrName[mysql.ErrWindowNoChildPartitioning])
+	ErrWindowNoInherentFrame                 = terror.ClassOptimizer.New(mysql.ErrWindowNoInherentFrame, mysql.MySQLErrName[mysql.ErrWindowNoInherentFrame])
+	ErrWindowNoRedefineOrderBy               = terror.ClassOptimizer.New(mysql.ErrWindowNoRedefineOrderBy, mysql.MySQLErrName[mysql.ErrWindowNoRedefineOrderBy])
+	ErrWindowDuplicateName                   = terror.ClassOptimizer.New(mysql.ErrWindowDuplicateName, mysql.MySQLErrName[mysql.ErrWindowDuplicateName])
+
+	ErrCTERecursiveRequiresUnion             = terror.ClassOptimizer.New(mysql.ErrCTERecursiveRequiresUnion, mysql.MySQLErrName[mysql.ErrCTERecursiveRequiresUnion])
+	ErrCTERecursiveRequiresNonRecursiveFirst = terror.ClassOptimizer.New(mysql.ErrCTERecursiveRequiresNonRecursiveFirst, mysql.MySQLErrName[mysql.ErrCTERecursiveRequiresNonRecursiveFirst])
+	ErrCTERecursiveForbidsAggregation        = terror.ClassOptimizer.New(mysql.ErrCTERecursiveForbidsAggregation, mysql.MySQLErrName[mysql.ErrCTERecursiveForbidsAggregation])
+	ErrInvalidRequiresSingleReference        = terror.ClassOptimizer.New(mysql.ErrInvalidRequiresSingleReference, mysql.MySQLErrName[mysql.ErrInvalidRequiresSingleReference])
+	ErrViewWrongList                         = terror.ClassOptimizer.New(mysql.ErrViewWrongList, mysql.MySQLErrName[mysql.ErrViewWrongList])
+	ErrViewRecursive                         = terror.ClassOptimizer.New(mysql.ErrViewRecursive, mysql.MySQLErrName[mysql.ErrViewRecursive])
+
 	// Since we cannot know if user loggined with a password, use message of ErrAccessDeniedNoPassword instead
 	ErrAccessDenied = terror.ClassOptimizer.New(mysql.ErrAccessDenied, mysql.MySQLErrName[mysql.ErrAccessDeniedNoPassword])
 )
@@ -110,6 +133,28 @@ func init() {
 		mysql.ErrNonuniqTable:                        mysql.ErrNonuniqTable,
 		mysql.ErrTooBigPrecision:                     mysql.ErrTooBigPrecision,
 		mysql.ErrInvalidWildCard:                     mysql.ErrInvalidWildCard,
+		mysql.ErrMultiUpdateKeyConflict:              mysql.ErrMultiUpdateKeyConflict,
+
+		mysql.ErrWindowInvalidWindowFuncUse:            mysql.ErrWindowInvalidWindowFuncUse,
+		mysql.ErrWindowNestedWindowFuncUseInWindowSpec: mysql.ErrWindowNestedWindowFuncUseInWindowSpec,
+		mysql.ErrWindowFrameStartIllegal:               mysql.ErrWindowFrameStartIllegal,
+		mysql.ErrWindowFrameEndIllegal:                 mysql.ErrWindowFrameEndIllegal,
+		mysql.ErrWindowFrameIllegal:                    mysql.ErrWindowFrameIllegal,
+		mysql.ErrWindowRangeFrameOrderType:             mysql.ErrWindowRangeFrameOrderType,
+		mysql.ErrWindowFunctionIgnoresFrame:            mysql.ErrWindowFunctionIgnoresFrame,
+		mysql.ErrWindowNoSuchWindow:                    mysql.ErrWindowNoSuchWindow,
+		mysql.ErrWindowCircularityInWindowGraph:        mysql.ErrWindowCircularityInWindowGraph,
+		mysql.ErrWindowNoChildPartitioning:             mysql.ErrWindowNoChildPartitioning,
+		mysql.ErrWindowNoInherentFrame:                 mysql.ErrWindowNoInherentFrame,
+		mysql.ErrWindowNoRedefineOrderBy:               mysql.ErrWindowNoRedefineOrderBy,
+		mysql.ErrWindowDuplicateName:                   mysql.ErrWindowDuplicateName,
+
+		mysql.ErrCTERecursiveRequiresUnion:             mysql.ErrCTERecursiveRequiresUnion,
+		mysql.ErrCTERecursiveRequiresNonRecursiveFirst: mysql.ErrCTERecursiveRequiresNonRecursiveFirst,
+		mysql.ErrCTERecursiveForbidsAggregation:        mysql.ErrCTERecursiveForbidsAggregation,
+		mysql.ErrInvalidRequiresSingleReference:        mysql.ErrInvalidRequiresSingleReference,
+		mysql.ErrViewWrongList:                         mysql.ErrViewWrongList,
+		mysql.ErrViewRecursive:                         mysql.ErrViewRecursive,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassOptimizer] = mysqlErrCodeMap
 }
@@ -64,6 +64,20 @@ func (s testErrorSuite) TestError(c *C) {
 		ErrCartesianProductUnsupported,
 		ErrStmtNotFound,
 		ErrAmbiguous,
+		ErrMultiUpdatePKConflict,
+		ErrWindowInvalidWindowFuncUse,
+		ErrWindowNestedWindowFuncUseInWindowSpec,
+		ErrWindowFrameStartIllegal,
+		ErrWindowFrameEndIllegal,
+		ErrWindowFrameIllegal,
+		ErrWindowRangeFrameOrderType,
+		ErrWindowFunctionIgnoresFrame,
+		ErrCTERecursiveRequiresUnion,
+		ErrCTERecursiveRequiresNonRecursiveFirst,
+		ErrCTERecursiveForbidsAggregation,
+		ErrInvalidRequiresSingleReference,
+		ErrViewWrongList,
+		ErrViewRecursive,
 	}
 	for _, err := range kvErrs {
 		code := err.ToSQLError().Code
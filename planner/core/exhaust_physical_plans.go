@@ -14,10 +14,16 @@
 package core
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/property"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/set"
 )
 
@@ -107,6 +113,10 @@ func (p *PhysicalMergeJoin) tryToGetChildReqProp(prop *property.PhysicalProperty
 }
 
 func (p *LogicalJoin) getMergeJoin(prop *property.PhysicalProperty) []PhysicalPlan {
+	// TODO: support null equal join keys for merge join.
+	if _, hasNullEQ := p.getNullEQKeys(); hasNullEQ {
+		return nil
+	}
 	joins := make([]PhysicalPlan, 0, len(p.leftProperties)+1)
 	// The leftProperties caches all the possible properties that are provided by its children.
 	for _, lhsChildProperty := range p.leftProperties {
@@ -250,7 +260,7 @@ func (p *LogicalJoin) getHashJoins(prop *property.PhysicalProperty) []PhysicalPl
 	}
 	joins := make([]PhysicalPlan, 0, 2)
 	switch p.JoinType {
-	case LeftOuterJoin:
+	case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin, LeftOuterJoin:
 		joins = append(joins, p.getHashJoin(prop, 1))
 	case RightOuterJoin:
 		joins = append(joins, p.getHashJoin(prop, 0))
@@ -274,6 +284,396 @@ func (p *LogicalJoin) getHashJoin(prop *property.PhysicalProperty, innerIdx int)
 	return hashJoin
 }
 
+// constructIndexJoin builds the PhysicalIndexJoin with the given inner task. keyOff2IdxOff maps the
+// join keys to the index columns, the equal conditions of the join keys which are not used to build
+// the inner ranges(whose offsets are -1) are evaluated as other conditions.
+func (p *LogicalJoin) constructIndexJoin(prop *property.PhysicalProperty, outerIdx int, innerTask task,
+	ranges []*ranger.Range, keyOff2IdxOff []int) []PhysicalPlan {
+	var outerJoinKeys, innerJoinKeys []*expression.Column
+	if outerIdx == 0 {
+		outerJoinKeys, innerJoinKeys = p.LeftJoinKeys, p.RightJoinKeys
+	} else {
+		outerJoinKeys, innerJoinKeys = p.RightJoinKeys, p.LeftJoinKeys
+	}
+	chReqProps := make([]*property.PhysicalProperty, 2)
+	chReqProps[outerIdx] = &property.PhysicalProperty{TaskTp: property.RootTaskType, ExpectedCnt: math.MaxFloat64, Items: prop.Items}
+	if prop.ExpectedCnt < p.stats.RowCount {
+		expCntScale := prop.ExpectedCnt / p.stats.RowCount
+		chReqProps[outerIdx].ExpectedCnt = p.children[outerIdx].statsInfo().RowCount * expCntScale
+	}
+	// The inner task is built by the index join itself, the inner child is only required to be valid.
+	chReqProps[1-outerIdx] = &property.PhysicalProperty{TaskTp: property.RootTaskType, ExpectedCnt: math.MaxFloat64}
+
+	usedEqConds := make([]int, 0, len(keyOff2IdxOff))
+	newOuterKeys := make([]*expression.Column, 0, len(keyOff2IdxOff))
+	newInnerKeys := make([]*expression.Column, 0, len(keyOff2IdxOff))
+	newKeyOff := make([]int, 0, len(keyOff2IdxOff))
+	for keyOff, idxOff := range keyOff2IdxOff {
+		if idxOff < 0 {
+			continue
+		}
+		usedEqConds = append(usedEqConds, keyOff)
+		newOuterKeys = append(newOuterKeys, outerJoinKeys[keyOff])
+		newInnerKeys = append(newInnerKeys, innerJoinKeys[keyOff])
+		newKeyOff = append(newKeyOff, idxOff)
+	}
+	baseJoin := basePhysicalJoin{
+		JoinType:        p.JoinType,
+		LeftConditions:  p.LeftConditions,
+		RightConditions: p.RightConditions,
+		OtherConditions: p.moveEqualToOtherConditions(usedEqConds),
+		InnerChildIdx:   1 - outerIdx,
+		OuterJoinKeys:   newOuterKeys,
+		InnerJoinKeys:   newInnerKeys,
+		DefaultValues:   p.DefaultValues,
+	}
+	join := PhysicalIndexJoin{
+		basePhysicalJoin: baseJoin,
+		innerTask:        innerTask,
+		Ranges:           ranges,
+		KeyOff2IdxOff:    newKeyOff,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), chReqProps...)
+	join.SetSchema(p.schema)
+	return []PhysicalPlan{join}
+}
+
+// getIndexJoinByOuterIdx will generate index join by outerIndex. OuterIdx points out the outer child.
+// First of all, we'll check whether the inner child is DataSource.
+// Then, we will check whether the inner join keys contain the handle column, or match a prefix of some index.
+// If so we will choose the best one and construct an index join.
+func (p *LogicalJoin) getIndexJoinByOuterIdx(prop *property.PhysicalProperty, outerIdx int) []PhysicalPlan {
+	outerChild, innerChild := p.children[outerIdx], p.children[1-outerIdx]
+	// The index join keeps the order of the outer child.
+	if !prop.AllColsFromSchema(outerChild.Schema()) {
+		return nil
+	}
+	ds, isDataSource := innerChild.(*DataSource)
+	if !isDataSource {
+		return nil
+	}
+	innerJoinKeys := p.RightJoinKeys
+	if outerIdx == 1 {
+		innerJoinKeys = p.LeftJoinKeys
+	}
+	// The handle can only be used when the table path isn't excluded by the index hints.
+	if pkCol := ds.getPKIsHandleCol(); pkCol != nil && hasTablePath(ds.possibleAccessPaths) {
+		keyOff2IdxOff := make([]int, len(innerJoinKeys))
+		pkMatched := false
+		for i, key := range innerJoinKeys {
+			if pkMatched || !key.Equal(nil, pkCol) {
+				keyOff2IdxOff[i] = -1
+				continue
+			}
+			pkMatched = true
+			keyOff2IdxOff[i] = 0
+		}
+		if pkMatched {
+			innerTask := p.constructInnerTableScanTask(ds, pkCol)
+			return p.constructIndexJoin(prop, outerIdx, innerTask, nil, keyOff2IdxOff)
+		}
+	}
+	var (
+		bestPath          *util.AccessPath
+		bestRanges        []*ranger.Range
+		bestAccessConds   []expression.Expression
+		bestKeyOff2IdxOff []int
+		bestKeyCnt        int
+	)
+	for _, path := range ds.possibleAccessPaths {
+		if path.IsTablePath {
+			continue
+		}
+		ranges, accessConds, keyOff2IdxOff := p.buildIndexJoinRanges(ds, path, innerJoinKeys)
+		if ranges == nil {
+			continue
+		}
+		keyCnt := 0
+		for _, idxOff := range keyOff2IdxOff {
+			if idxOff >= 0 {
+				keyCnt++
+			}
+		}
+		// Prefer the index matching more join keys, then the one with more columns restricted.
+		if bestPath == nil || keyCnt > bestKeyCnt ||
+			(keyCnt == bestKeyCnt && len(ranges[0].LowVal) > len(bestRanges[0].LowVal)) {
+			bestPath, bestRanges, bestAccessConds, bestKeyOff2IdxOff, bestKeyCnt = path, ranges, accessConds, keyOff2IdxOff, keyCnt
+		}
+	}
+	if bestPath == nil {
+		return nil
+	}
+	filterConds := make([]expression.Expression, 0, len(ds.pushedDownConds))
+	for _, cond := range ds.pushedDownConds {
+		used := false
+		for _, accessCond := range bestAccessConds {
+			if cond == accessCond {
+				used = true
+				break
+			}
+		}
+		if !used {
+			filterConds = append(filterConds, cond)
+		}
+	}
+	innerTask := p.constructInnerIndexScanTask(ds, bestPath, bestRanges, filterConds, bestKeyOff2IdxOff, innerJoinKeys)
+	return p.constructIndexJoin(prop, outerIdx, innerTask, bestRanges, bestKeyOff2IdxOff)
+}
+
+// buildIndexJoinRanges checks whether the index path can be used by the inner side of index join.
+// The index columns are matched from the first one: a column either equals one of the inner join
+// keys, or is restricted to points by the conditions pushed down to the DataSource. The column after
+// the matched ones can be further restricted by a range condition. It returns the template ranges,
+// whose datums at the join key positions are filled for every outer row when executing, the pushed
+// down conditions that are fully used by the ranges, and the offsets of the join keys in the index.
+func (p *LogicalJoin) buildIndexJoinRanges(ds *DataSource, path *util.AccessPath, innerJoinKeys []*expression.Column) (
+	ranges []*ranger.Range, accessConds []expression.Expression, keyOff2IdxOff []int) {
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	keyOff2IdxOff = make([]int, len(innerJoinKeys))
+	for i := range keyOff2IdxOff {
+		keyOff2IdxOff[i] = -1
+	}
+	ranges = []*ranger.Range{{}}
+	matchedKeyCnt := 0
+	for idxOff, idxCol := range path.FullIdxCols {
+		if idxCol == nil {
+			break
+		}
+		colLen := path.FullIdxColLens[idxOff]
+		if keyOff := findUnusedJoinKey(idxCol, colLen, innerJoinKeys, keyOff2IdxOff); keyOff != -1 {
+			keyOff2IdxOff[keyOff] = idxOff
+			matchedKeyCnt++
+			for _, ran := range ranges {
+				ran.LowVal = append(ran.LowVal, types.Datum{})
+				ran.HighVal = append(ran.HighVal, types.Datum{})
+			}
+			continue
+		}
+		conds := ranger.ExtractAccessConditionsForColumn(ds.pushedDownConds, idxCol.UniqueID)
+		if len(conds) == 0 {
+			break
+		}
+		colRanges, err := ranger.BuildColumnRange(conds, sc, idxCol.RetType, colLen)
+		if err != nil || len(colRanges) == 0 {
+			// Leave the empty inner side and the unsupported conditions to other kinds of join.
+			return nil, nil, nil
+		}
+		if colLen == types.UnspecifiedLength {
+			accessConds = append(accessConds, conds...)
+		}
+		isPoint := true
+		newRanges := make([]*ranger.Range, 0, len(ranges)*len(colRanges))
+		for _, colRan := range colRanges {
+			isPoint = isPoint && colRan.IsPoint(sc)
+			for _, ran := range ranges {
+				newRan := ran.Clone()
+				newRan.LowVal = append(newRan.LowVal, colRan.LowVal[0])
+				newRan.HighVal = append(newRan.HighVal, colRan.HighVal[0])
+				newRan.LowExclude, newRan.HighExclude = colRan.LowExclude, colRan.HighExclude
+				newRanges = append(newRanges, newRan)
+			}
+		}
+		ranges = newRanges
+		if !isPoint {
+			break
+		}
+	}
+	if matchedKeyCnt == 0 {
+		return nil, nil, nil
+	}
+	return ranges, accessConds, keyOff2IdxOff
+}
+
+func hasTablePath(paths []*util.AccessPath) bool {
+	for _, path := range paths {
+		if path.IsTablePath {
+			return true
+		}
+	}
+	return false
+}
+
+// findUnusedJoinKey returns the offset of the join key which equals the index column and
+// hasn't been matched by other index columns, -1 is returned if there isn't such a key.
+func findUnusedJoinKey(idxCol *expression.Column, colLen int, joinKeys []*expression.Column, keyOff2IdxOff []int) int {
+	if colLen != types.UnspecifiedLength {
+		return -1
+	}
+	for keyOff, key := range joinKeys {
+		if keyOff2IdxOff[keyOff] == -1 && key.Equal(nil, idxCol) {
+			return keyOff
+		}
+	}
+	return -1
+}
+
+// selectivity returns the selectivity of the pushed down conditions of the DataSource.
+func (ds *DataSource) selectivity() float64 {
+	if ds.tableStats.RowCount <= 0 {
+		return 1
+	}
+	return ds.stats.RowCount / ds.tableStats.RowCount
+}
+
+// constructInnerTableScanTask is specially used to construct the inner plan for PhysicalIndexJoin
+// when the inner rows are read by the handles.
+func (p *LogicalJoin) constructInnerTableScanTask(ds *DataSource, pkCol *expression.Column) task {
+	ts := PhysicalTableScan{
+		Table:           ds.tableInfo,
+		Columns:         ds.Columns,
+		TableAsName:     ds.TableAsName,
+		DBName:          ds.DBName,
+		Ranges:          ranger.FullIntRange(mysql.HasUnsignedFlag(pkCol.RetType.Flag)),
+		filterCondition: ds.pushedDownConds,
+		isPartition:     ds.isPartition,
+		physicalTableID: ds.physicalTableID,
+	}.Init(ds.ctx)
+	ts.SetSchema(ds.schema.Clone())
+	// Every outer row looks up at most one row by the handle.
+	rowCount := 1.0
+	ts.stats = ds.tableStats.ScaleByExpectCnt(rowCount)
+	rowSize := ds.TblColHists.GetTableAvgRowSize(ds.TblCols)
+	sessVars := ds.ctx.GetSessionVars()
+	copTask := &copTask{
+		tablePlan:         ts,
+		indexPlanFinished: true,
+		tblColHists:       ds.TblColHists,
+		cst:               rowCount*rowSize*sessVars.ScanFactor + sessVars.SeekFactor,
+	}
+	ts.addPushedDownSelection(copTask, ds.tableStats.ScaleByExpectCnt(rowCount*ds.selectivity()))
+	return finishCopTask(ds.ctx, copTask)
+}
+
+// constructInnerIndexScanTask is specially used to construct the inner plan for PhysicalIndexJoin
+// when the inner rows are read by the index.
+func (p *LogicalJoin) constructInnerIndexScanTask(ds *DataSource, path *util.AccessPath, ranges []*ranger.Range,
+	filterConds []expression.Expression, keyOff2IdxOff []int, innerJoinKeys []*expression.Column) task {
+	// The real ranges of the index scan are built from the template ranges kept by the join when executing.
+	is := PhysicalIndexScan{
+		Table:            ds.tableInfo,
+		TableAsName:      ds.TableAsName,
+		DBName:           ds.DBName,
+		Columns:          ds.Columns,
+		Index:            path.Index,
+		IdxCols:          path.IdxCols,
+		IdxColLens:       path.IdxColLens,
+		Ranges:           ranger.FullRange(),
+		dataSourceSchema: ds.schema,
+		isPartition:      ds.isPartition,
+		physicalTableID:  ds.physicalTableID,
+	}.Init(ds.ctx)
+	cop := &copTask{
+		indexPlan:   is,
+		tblColHists: ds.TblColHists,
+		tblCols:     ds.TblCols,
+	}
+	indexConds, tblConds := splitIndexFilterConditions(filterConds, path.FullIdxCols, path.FullIdxColLens, ds.tableInfo)
+	if len(tblConds) > 0 || !isCoveringIndex(ds.schema.Columns, path.FullIdxCols, path.FullIdxColLens, ds.tableInfo.PKIsHandle) {
+		// On this way, it's double read case.
+		ts := PhysicalTableScan{
+			Columns:         ds.Columns,
+			Table:           is.Table,
+			TableAsName:     ds.TableAsName,
+			isPartition:     ds.isPartition,
+			physicalTableID: ds.physicalTableID,
+		}.Init(ds.ctx)
+		ts.SetSchema(ds.schema.Clone())
+		cop.tablePlan = ts
+	}
+	is.initSchema(path.Index, path.FullIdxCols, cop.tablePlan != nil)
+	// Estimate the row count looked up by one outer row. A unique index returns at most one row
+	// when all of its columns are restricted to points, otherwise we use the NDV of the join keys.
+	rowCount := 1.0
+	if !path.Index.Unique || len(ranges[0].LowVal) < len(path.Index.Columns) || !ranges[0].IsPoint(ds.ctx.GetSessionVars().StmtCtx) {
+		usedKeys := make([]*expression.Column, 0, len(keyOff2IdxOff))
+		for keyOff, idxOff := range keyOff2IdxOff {
+			if idxOff >= 0 {
+				usedKeys = append(usedKeys, innerJoinKeys[keyOff])
+			}
+		}
+		rowCount = math.Max(ds.tableStats.RowCount/getCardinality(usedKeys, ds.schema, ds.tableStats), 1)
+	}
+	is.stats = ds.tableStats.ScaleByExpectCnt(rowCount)
+	rowSize := is.indexScanRowSize(path.Index, ds, true)
+	sessVars := ds.ctx.GetSessionVars()
+	cop.cst = rowCount*rowSize*sessVars.ScanFactor + float64(len(ranges))*sessVars.SeekFactor
+	tmpPath := &util.AccessPath{
+		IndexFilters:     indexConds,
+		TableFilters:     tblConds,
+		CountAfterAccess: rowCount,
+		CountAfterIndex:  rowCount,
+	}
+	if len(tblConds) == 0 {
+		tmpPath.CountAfterIndex = rowCount * ds.selectivity()
+	}
+	is.addPushedDownSelection(cop, ds, tmpPath, ds.tableStats.ScaleByExpectCnt(rowCount*ds.selectivity()))
+	return finishCopTask(ds.ctx, cop)
+}
+
+// tryToGetIndexJoin will get index join by hints. If we can generate a valid index join by hint, the second return value
+// will be true, which means we force to choose this index join. Otherwise we will select a join algorithm with min-cost.
+func (p *LogicalJoin) tryToGetIndexJoin(prop *property.PhysicalProperty) (indexJoins []PhysicalPlan, forced bool) {
+	// TODO: support null equal join keys for index join.
+	if _, hasNullEQ := p.getNullEQKeys(); hasNullEQ {
+		return nil, false
+	}
+	rightOuter := (p.preferJoinType & preferLeftAsIndexInner) > 0
+	leftOuter := (p.preferJoinType & preferRightAsIndexInner) > 0
+	hasIndexJoinHint := leftOuter || rightOuter
+
+	defer func() {
+		if !forced && hasIndexJoinHint {
+			errMsg := "Optimizer Hint INL_JOIN or TIDB_INLJ is inapplicable"
+			if p.hintInfo != nil {
+				errMsg = fmt.Sprintf("Optimizer Hint %s or %s is inapplicable",
+					restore2JoinHint(HintINLJ, p.hintInfo.indexNestedLoopJoinTables),
+					restore2JoinHint(TiDBIndexNestedLoopJoin, p.hintInfo.indexNestedLoopJoinTables))
+			}
+			if len(p.EqualConditions) == 0 {
+				errMsg += " without column equal ON condition"
+			}
+			// The join is planned for several required properties, only warn once.
+			sc, warning := p.ctx.GetSessionVars().StmtCtx, ErrInternal.GenWithStack(errMsg)
+			for _, warn := range sc.GetWarnings() {
+				if warn.Err.Error() == warning.Error() {
+					return
+				}
+			}
+			sc.AppendWarning(warning)
+		}
+	}()
+
+	switch p.JoinType {
+	case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin, LeftOuterJoin:
+		join := p.getIndexJoinByOuterIdx(prop, 0)
+		return join, join != nil && leftOuter
+	case RightOuterJoin:
+		join := p.getIndexJoinByOuterIdx(prop, 1)
+		return join, join != nil && rightOuter
+	case InnerJoin:
+		lhsCardinality := p.children[0].statsInfo().RowCount
+		rhsCardinality := p.children[1].statsInfo().RowCount
+
+		leftJoins := p.getIndexJoinByOuterIdx(prop, 0)
+		if leftJoins != nil && leftOuter && !rightOuter {
+			return leftJoins, true
+		}
+		rightJoins := p.getIndexJoinByOuterIdx(prop, 1)
+		if rightJoins != nil && rightOuter && !leftOuter {
+			return rightJoins, true
+		}
+		if leftJoins != nil && lhsCardinality < rhsCardinality {
+			return leftJoins, leftOuter
+		}
+		if rightJoins != nil && rhsCardinality < lhsCardinality {
+			return rightJoins, rightOuter
+		}
+		canForceLeft := leftJoins != nil && leftOuter
+		canForceRight := rightJoins != nil && rightOuter
+		return append(leftJoins, rightJoins...), canForceLeft || canForceRight
+	}
+	return nil, false
+}
+
 // LogicalJoin can generates hash join, index join and sort merge join.
 // Firstly we check the hint, if hint is figured by user, we force to choose the corresponding physical plan.
 // If the hint is not matched, it will get other candidates.
@@ -283,9 +683,15 @@ func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) []Ph
 	if (p.preferJoinType & preferMergeJoin) > 0 {
 		return mergeJoins
 	}
-	joins := make([]PhysicalPlan, 0, 3)
+	joins := make([]PhysicalPlan, 0, 5)
 	joins = append(joins, mergeJoins...)
 
+	indexJoins, forced := p.tryToGetIndexJoin(prop)
+	if forced {
+		return indexJoins
+	}
+	joins = append(joins, indexJoins...)
+
 	hashJoins := p.getHashJoins(prop)
 	if (p.preferJoinType & preferHashJoin) > 0 {
 		return hashJoins
@@ -319,7 +725,8 @@ func (p *LogicalProjection) exhaustPhysicalPlans(prop *property.PhysicalProperty
 		return nil
 	}
 	proj := PhysicalProjection{
-		Exprs: p.Exprs,
+		Exprs:                p.Exprs,
+		AvoidColumnEvaluator: p.AvoidColumnEvaluator,
 	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), newProp)
 	proj.SetSchema(p.schema)
 	return []PhysicalPlan{proj}
@@ -396,8 +803,59 @@ func (la *LogicalAggregation) getHashAggs(prop *property.PhysicalProperty) []Phy
 	return hashAggs
 }
 
+// getStreamAggs generates the stream aggregations whose child is required to
+// be ordered by the group-by columns, the order has to satisfy prop as well.
+func (la *LogicalAggregation) getStreamAggs(prop *property.PhysicalProperty) []PhysicalPlan {
+	all, desc := prop.AllSameOrder()
+	if !all {
+		return nil
+	}
+	for _, aggFunc := range la.AggFuncs {
+		if aggFunc.Mode == aggregation.FinalMode {
+			return nil
+		}
+	}
+	// group by a + b is not interested in any order.
+	if len(la.groupByCols) != len(la.GroupByItems) {
+		return nil
+	}
+
+	streamAggs := make([]PhysicalPlan, 0, len(la.possibleProperties)*2)
+	childProp := &property.PhysicalProperty{
+		ExpectedCnt: math.Max(prop.ExpectedCnt*la.inputCount/la.stats.RowCount, prop.ExpectedCnt),
+	}
+	for _, possibleChildProperty := range la.possibleProperties {
+		sortColOffsets := getMaxSortPrefix(possibleChildProperty, la.groupByCols)
+		if len(sortColOffsets) != len(la.groupByCols) {
+			continue
+		}
+		childProp.Items = property.ItemsFromCols(possibleChildProperty[:len(sortColOffsets)], desc)
+		if !prop.IsPrefix(childProp) {
+			continue
+		}
+		// The table read of CopDoubleReadTaskType can't keep the order of the
+		// index, so it never satisfies the order required by stream aggregation.
+		for _, taskTp := range []property.TaskType{property.CopSingleReadTaskType, property.RootTaskType} {
+			copiedChildProperty := new(property.PhysicalProperty)
+			*copiedChildProperty = *childProp
+			copiedChildProperty.TaskTp = taskTp
+
+			agg := basePhysicalAgg{
+				GroupByItems: la.GroupByItems,
+				AggFuncs:     la.AggFuncs,
+			}.initForStream(la.ctx, la.stats.ScaleByExpectCnt(prop.ExpectedCnt), copiedChildProperty)
+			agg.SetSchema(la.schema.Clone())
+			streamAggs = append(streamAggs, agg)
+		}
+	}
+	return streamAggs
+}
+
 func (la *LogicalAggregation) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
-	return la.getHashAggs(prop)
+	aggs := make([]PhysicalPlan, 0, len(la.possibleProperties)+1)
+	aggs = append(aggs, la.getHashAggs(prop)...)
+	aggs = append(aggs, la.getStreamAggs(prop)...)
+	return aggs
 }
 
 func (p *LogicalSelection) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
@@ -408,6 +866,72 @@ func (p *LogicalSelection) exhaustPhysicalPlans(prop *property.PhysicalProperty)
 	return []PhysicalPlan{sel}
 }
 
+func (la *LogicalApply) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.AllColsFromSchema(la.children[0].Schema()) { // for convenient, we don't pass through any prop
+		return nil
+	}
+	join := la.getHashJoin(prop, 1)
+	apply := PhysicalApply{
+		PhysicalHashJoin: *join,
+		OuterSchema:      la.CorCols,
+	}.Init(la.ctx,
+		la.stats.ScaleByExpectCnt(prop.ExpectedCnt),
+		&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64, Items: prop.Items},
+		&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+	apply.SetSchema(la.schema)
+	return []PhysicalPlan{apply}
+}
+
+func (p *LogicalUnionAll) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	// TODO: UnionAll can not pass any order, but we can change it to sort merge to keep order.
+	if !prop.IsEmpty() {
+		return nil
+	}
+	chReqProps := make([]*property.PhysicalProperty, 0, len(p.children))
+	for range p.children {
+		chReqProps = append(chReqProps, &property.PhysicalProperty{ExpectedCnt: prop.ExpectedCnt})
+	}
+	ua := PhysicalUnionAll{}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), chReqProps...)
+	ua.SetSchema(p.Schema())
+	return []PhysicalPlan{ua}
+}
+
+func (p *LogicalMaxOneRow) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.IsEmpty() {
+		return nil
+	}
+	mor := PhysicalMaxOneRow{}.Init(p.ctx, p.stats, &property.PhysicalProperty{ExpectedCnt: 2})
+	return []PhysicalPlan{mor}
+}
+
+func (p *LogicalLock) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	childProp := prop.Clone()
+	lock := PhysicalLock{
+		Lock:         p.Lock,
+		TblID2Handle: p.tblID2Handle,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), childProp)
+	return []PhysicalPlan{lock}
+}
+
+func (p *LogicalWindow) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	// The window needs its child sorted by the partition by items and the order by items.
+	byItems := make([]property.Item, 0, len(p.PartitionBy)+len(p.OrderBy))
+	byItems = append(byItems, p.PartitionBy...)
+	byItems = append(byItems, p.OrderBy...)
+	childProperty := &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64, Items: byItems, Enforced: true}
+	if !prop.IsPrefix(childProperty) {
+		return nil
+	}
+	window := PhysicalWindow{
+		WindowFuncDescs: p.WindowFuncDescs,
+		PartitionBy:     p.PartitionBy,
+		OrderBy:         p.OrderBy,
+		Frame:           p.Frame,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), childProperty)
+	window.SetSchema(p.Schema())
+	return []PhysicalPlan{window}
+}
+
 func (p *LogicalLimit) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
 	if !prop.IsEmpty() {
 		return nil
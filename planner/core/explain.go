@@ -20,6 +20,7 @@ import (
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/statistics"
 )
 
@@ -35,6 +36,11 @@ func (p *PhysicalIndexScan) explainInfo(normalized bool) string {
 		tblName = p.TableAsName.O
 	}
 	fmt.Fprintf(buffer, "table:%s", tblName)
+	if p.isPartition {
+		if pi := p.Table.GetPartitionInfo(); pi != nil {
+			fmt.Fprintf(buffer, ", partition:%s", pi.GetNameByID(p.physicalTableID))
+		}
+	}
 	if len(p.Index.Columns) > 0 {
 		buffer.WriteString(", index:")
 		for i, idxCol := range p.Index.Columns {
@@ -89,6 +95,11 @@ func (p *PhysicalTableScan) explainInfo(normalized bool) string {
 		tblName = p.TableAsName.O
 	}
 	fmt.Fprintf(buffer, "table:%s", tblName)
+	if p.isPartition {
+		if pi := p.Table.GetPartitionInfo(); pi != nil {
+			fmt.Fprintf(buffer, ", partition:%s", pi.GetNameByID(p.physicalTableID))
+		}
+	}
 	if p.pkCol != nil {
 		fmt.Fprintf(buffer, ", pk col:%s", p.pkCol.ExplainInfo())
 	}
@@ -170,12 +181,89 @@ func (p *PhysicalTableDual) ExplainInfo() string {
 	return fmt.Sprintf("rows:%v", p.RowCount)
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalCTE) ExplainInfo() string {
+	return fmt.Sprintf("data:CTE_%d", p.CTE.IDForStorage)
+}
+
+// ExplainInfo implements Plan interface.
+func (p *PhysicalCTETable) ExplainInfo() string {
+	return fmt.Sprintf("Scan on CTE_%d", p.IDForStorage)
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalSort) ExplainInfo() string {
 	buffer := bytes.NewBufferString("")
 	return explainByItems(buffer, p.ByItems).String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalWindow) ExplainInfo() string {
+	buffer := bytes.NewBufferString("")
+	formatWindowFuncDescs(buffer, p.WindowFuncDescs, p.schema)
+	buffer.WriteString(" over(")
+	clauses := make([]string, 0, 3)
+	if len(p.PartitionBy) > 0 {
+		items := make([]string, 0, len(p.PartitionBy))
+		for _, item := range p.PartitionBy {
+			items = append(items, item.Col.ExplainInfo())
+		}
+		clauses = append(clauses, "partition by "+strings.Join(items, ", "))
+	}
+	if len(p.OrderBy) > 0 {
+		items := make([]string, 0, len(p.OrderBy))
+		for _, item := range p.OrderBy {
+			order := "asc"
+			if item.Desc {
+				order = "desc"
+			}
+			items = append(items, item.Col.ExplainInfo()+" "+order)
+		}
+		clauses = append(clauses, "order by "+strings.Join(items, ", "))
+	}
+	if p.Frame != nil {
+		frameType := "range"
+		if p.Frame.Type == ast.Rows {
+			frameType = "rows"
+		}
+		clauses = append(clauses, fmt.Sprintf("%s between %s and %s", frameType, formatFrameBound(p.Frame.Start), formatFrameBound(p.Frame.End)))
+	}
+	buffer.WriteString(strings.Join(clauses, " "))
+	buffer.WriteString(")")
+	return buffer.String()
+}
+
+func formatWindowFuncDescs(buffer *bytes.Buffer, descs []*aggregation.WindowFuncDesc, schema *expression.Schema) *bytes.Buffer {
+	winFuncStartIdx := schema.Len() - len(descs)
+	for i, desc := range descs {
+		if i != 0 {
+			buffer.WriteString(", ")
+		}
+		fmt.Fprintf(buffer, "%v->%v", desc, schema.Columns[winFuncStartIdx+i])
+	}
+	return buffer
+}
+
+func formatFrameBound(bound *FrameBound) string {
+	if bound.Type == ast.CurrentRow {
+		return "current row"
+	}
+	var offset string
+	switch {
+	case bound.UnBounded:
+		offset = "unbounded"
+	case len(bound.CalcFuncs) > 0:
+		// The range frame bound is `col + offset` or `col - offset`.
+		offset = bound.CalcFuncs[0].(*expression.ScalarFunction).GetArgs()[1].ExplainInfo()
+	default:
+		offset = fmt.Sprintf("%d", bound.Num)
+	}
+	if bound.Type == ast.Preceding {
+		return offset + " preceding"
+	}
+	return offset + " following"
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalLimit) ExplainInfo() string {
 	return fmt.Sprintf("offset:%v, count:%v", p.Offset, p.Count)
@@ -260,6 +348,47 @@ func (p *PhysicalHashJoin) explainInfo(normalized bool) string {
 	return buffer.String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalIndexJoin) ExplainInfo() string {
+	return p.explainInfo(false)
+}
+
+// ExplainNormalizedInfo implements Plan interface.
+func (p *PhysicalIndexJoin) ExplainNormalizedInfo() string {
+	return p.explainInfo(true)
+}
+
+func (p *PhysicalIndexJoin) explainInfo(normalized bool) string {
+	sortedExplainExpressionList := expression.SortedExplainExpressionList
+	if normalized {
+		sortedExplainExpressionList = expression.SortedExplainNormalizedExpressionList
+	}
+
+	buffer := bytes.NewBufferString(p.JoinType.String())
+	fmt.Fprintf(buffer, ", inner:%s", p.Children()[p.InnerChildIdx].ExplainID())
+	if len(p.OuterJoinKeys) > 0 {
+		fmt.Fprintf(buffer, ", outer key:%s",
+			expression.ExplainColumnList(p.OuterJoinKeys))
+	}
+	if len(p.InnerJoinKeys) > 0 {
+		fmt.Fprintf(buffer, ", inner key:%s",
+			expression.ExplainColumnList(p.InnerJoinKeys))
+	}
+	if len(p.LeftConditions) > 0 {
+		fmt.Fprintf(buffer, ", left cond:%s",
+			sortedExplainExpressionList(p.LeftConditions))
+	}
+	if len(p.RightConditions) > 0 {
+		fmt.Fprintf(buffer, ", right cond:%s",
+			sortedExplainExpressionList(p.RightConditions))
+	}
+	if len(p.OtherConditions) > 0 {
+		fmt.Fprintf(buffer, ", other cond:%s",
+			sortedExplainExpressionList(p.OtherConditions))
+	}
+	return buffer.String()
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalMergeJoin) ExplainInfo() string {
 	return p.explainInfo(false)
@@ -62,15 +62,44 @@ func GetPropByOrderByItems(items []*ByItems) (*property.PhysicalProperty, bool)
 	return &property.PhysicalProperty{Items: propItems}, true
 }
 
+// canEnforceLeafProperty checks whether a Sort can be put above a leaf plan
+// which provides no order by itself to satisfy the property.
+func canEnforceLeafProperty(prop *property.PhysicalProperty) bool {
+	return prop.IsEmpty() || (prop.Enforced && prop.TaskTp == property.RootTaskType)
+}
+
 func (p *LogicalTableDual) findBestTask(prop *property.PhysicalProperty) (task, error) {
-	if !prop.IsEmpty() {
+	if !canEnforceLeafProperty(prop) {
 		return invalidTask, nil
 	}
 	dual := PhysicalTableDual{
 		RowCount: p.RowCount,
 	}.Init(p.ctx, p.stats)
 	dual.SetSchema(p.schema)
-	return &rootTask{p: dual}, nil
+	return enforceProperty(prop, &rootTask{p: dual}, p.ctx), nil
+}
+
+func (p *LogicalCTE) findBestTask(prop *property.PhysicalProperty) (task, error) {
+	if !canEnforceLeafProperty(prop) {
+		return invalidTask, nil
+	}
+	pcte := PhysicalCTE{
+		SeedPlan:  p.cte.seedPartPhysicalPlan,
+		RecurPlan: p.cte.recursivePartPhysicalPlan,
+		CTE:       p.cte,
+		cteAsName: p.cteAsName,
+	}.Init(p.ctx, p.stats)
+	pcte.SetSchema(p.schema)
+	return enforceProperty(prop, &rootTask{p: pcte}, p.ctx), nil
+}
+
+func (p *LogicalCTETable) findBestTask(prop *property.PhysicalProperty) (task, error) {
+	if !canEnforceLeafProperty(prop) {
+		return invalidTask, nil
+	}
+	pcteTable := PhysicalCTETable{IDForStorage: p.cte.IDForStorage}.Init(p.ctx, p.stats)
+	pcteTable.SetSchema(p.schema)
+	return enforceProperty(prop, &rootTask{p: pcteTable}, p.ctx), nil
 }
 
 func (p *LogicalShow) findBestTask(prop *property.PhysicalProperty) (task, error) {
@@ -164,7 +193,7 @@ func (p *baseLogicalPlan) findBestTask(prop *property.PhysicalProperty) (bestTas
 }
 
 func (p *LogicalMemTable) findBestTask(prop *property.PhysicalProperty) (t task, err error) {
-	if !prop.IsEmpty() {
+	if !canEnforceLeafProperty(prop) {
 		return invalidTask, nil
 	}
 	memTable := PhysicalMemTable{
@@ -173,7 +202,7 @@ func (p *LogicalMemTable) findBestTask(prop *property.PhysicalProperty) (t task,
 		Columns: p.tableInfo.Columns,
 	}.Init(p.ctx, p.stats)
 	memTable.SetSchema(p.schema)
-	return &rootTask{p: memTable}, nil
+	return enforceProperty(prop, &rootTask{p: memTable}, p.ctx), nil
 }
 
 // tryToGetDualTask will check if the push down predicate has false constant. If so, it will return table dual.
@@ -416,9 +445,11 @@ func (ds *DataSource) convertToIndexScan(prop *property.PhysicalProperty, candid
 	if !candidate.isSingleScan {
 		// On this way, it's double read case.
 		ts := PhysicalTableScan{
-			Columns:     ds.Columns,
-			Table:       is.Table,
-			TableAsName: ds.TableAsName,
+			Columns:         ds.Columns,
+			Table:           is.Table,
+			TableAsName:     ds.TableAsName,
+			isPartition:     ds.isPartition,
+			physicalTableID: ds.physicalTableID,
 		}.Init(ds.ctx)
 		ts.SetSchema(ds.schema.Clone())
 		cop.tablePlan = ts
@@ -561,6 +592,8 @@ func (s *LogicalTableScan) GetPhysicalScan(schema *expression.Schema, stats *pro
 		DBName:          ds.DBName,
 		Ranges:          s.Ranges,
 		AccessCondition: s.AccessConds,
+		isPartition:     ds.isPartition,
+		physicalTableID: ds.physicalTableID,
 	}.Init(s.ctx)
 	ts.stats = stats
 	ts.SetSchema(schema.Clone())
@@ -581,6 +614,8 @@ func (s *LogicalIndexScan) GetPhysicalIndexScan(schema *expression.Schema, stats
 		AccessCondition:  s.AccessConds,
 		Ranges:           s.Ranges,
 		dataSourceSchema: ds.schema,
+		isPartition:      ds.isPartition,
+		physicalTableID:  ds.physicalTableID,
 	}.Init(ds.ctx)
 	is.stats = stats
 	is.initSchema(s.Index, s.FullIdxCols, s.IsDoubleRead)
@@ -636,6 +671,8 @@ func (ds *DataSource) getOriginalPhysicalTableScan(prop *property.PhysicalProper
 		Ranges:          path.Ranges,
 		AccessCondition: path.AccessConds,
 		filterCondition: path.TableFilters,
+		isPartition:     ds.isPartition,
+		physicalTableID: ds.physicalTableID,
 	}.Init(ds.ctx)
 	ts.SetSchema(ds.schema.Clone())
 	rowCount := path.CountAfterAccess
@@ -679,6 +716,8 @@ func (ds *DataSource) getOriginalPhysicalIndexScan(prop *property.PhysicalProper
 		AccessCondition:  path.AccessConds,
 		Ranges:           path.Ranges,
 		dataSourceSchema: ds.schema,
+		isPartition:      ds.isPartition,
+		physicalTableID:  ds.physicalTableID,
 	}.Init(ds.ctx)
 	rowCount := path.CountAfterAccess
 	is.initSchema(idx, path.FullIdxCols, !isSingleScan)
@@ -27,6 +27,8 @@ const (
 	TypeAgg = "Aggregation"
 	// TypeHashAgg is the type of HashAgg.
 	TypeHashAgg = "HashAgg"
+	// TypeStreamAgg is the type of StreamAgg.
+	TypeStreamAgg = "StreamAgg"
 	// TypeShow is the type of show.
 	TypeShow = "Show"
 	// TypeJoin is the type of Join.
@@ -51,14 +53,22 @@ const (
 	TypeHashRightJoin = "HashRightJoin"
 	// TypeMergeJoin is the type of merge join.
 	TypeMergeJoin = "MergeJoin"
+	// TypeIndexJoin is the type of index look up join.
+	TypeIndexJoin = "IndexJoin"
 	// TypeApply is the type of Apply.
 	TypeApply = "Apply"
 	// TypeMaxOneRow is the type of MaxOneRow.
 	TypeMaxOneRow = "MaxOneRow"
+	// TypeLock is the type of SelectLock.
+	TypeLock = "SelectLock"
+	// TypeUnion is the type of Union.
+	TypeUnion = "Union"
 	// TypeDual is the type of TableDual.
 	TypeDual = "TableDual"
 	// TypeInsert is the type of Insert
 	TypeInsert = "Insert"
+	// TypeUpdate is the type of Update.
+	TypeUpdate = "Update"
 	// TypeDelete is the type of Delete.
 	TypeDelete = "Delete"
 	// TypeIndexLookUp is the type of IndexLookUp.
@@ -71,6 +81,12 @@ const (
 	TypeTiKVSingleGather = "TiKVSingleGather"
 	// TypeShowDDLJobs is the type of show ddl jobs.
 	TypeShowDDLJobs = "ShowDDLJobs"
+	// TypeWindow is the type of Window.
+	TypeWindow = "Window"
+	// TypeCTE is the type of CTE.
+	TypeCTE = "CTEFullScan"
+	// TypeCTETable is the type of the working table of a recursive CTE.
+	TypeCTETable = "CTETable"
 )
 
 // Init initializes LogicalAggregation.
@@ -85,6 +101,12 @@ func (p LogicalJoin) Init(ctx sessionctx.Context) *LogicalJoin {
 	return &p
 }
 
+// Init initializes LogicalApply.
+func (la LogicalApply) Init(ctx sessionctx.Context) *LogicalApply {
+	la.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeApply, &la)
+	return &la
+}
+
 // Init initializes DataSource.
 func (ds DataSource) Init(ctx sessionctx.Context) *DataSource {
 	ds.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeTableScan, &ds)
@@ -164,6 +186,46 @@ func (p NominalSort) Init(ctx sessionctx.Context, props ...*property.PhysicalPro
 	return &p
 }
 
+// Init initializes LogicalWindow.
+func (p LogicalWindow) Init(ctx sessionctx.Context) *LogicalWindow {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeWindow, &p)
+	return &p
+}
+
+// Init initializes PhysicalWindow.
+func (p PhysicalWindow) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalWindow {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeWindow, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// Init initializes LogicalCTE.
+func (p LogicalCTE) Init(ctx sessionctx.Context) *LogicalCTE {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeCTE, &p)
+	return &p
+}
+
+// Init initializes PhysicalCTE.
+func (p PhysicalCTE) Init(ctx sessionctx.Context, stats *property.StatsInfo) *PhysicalCTE {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeCTE, &p)
+	p.stats = stats
+	return &p
+}
+
+// Init initializes LogicalCTETable.
+func (p LogicalCTETable) Init(ctx sessionctx.Context) *LogicalCTETable {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeCTETable, &p)
+	return &p
+}
+
+// Init initializes PhysicalCTETable.
+func (p PhysicalCTETable) Init(ctx sessionctx.Context, stats *property.StatsInfo) *PhysicalCTETable {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeCTETable, &p)
+	p.stats = stats
+	return &p
+}
+
 // Init initializes LogicalTopN.
 func (lt LogicalTopN) Init(ctx sessionctx.Context) *LogicalTopN {
 	lt.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeTopN, &lt)
@@ -192,6 +254,48 @@ func (p PhysicalLimit) Init(ctx sessionctx.Context, stats *property.StatsInfo, p
 	return &p
 }
 
+// Init initializes LogicalMaxOneRow.
+func (p LogicalMaxOneRow) Init(ctx sessionctx.Context) *LogicalMaxOneRow {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeMaxOneRow, &p)
+	return &p
+}
+
+// Init initializes PhysicalMaxOneRow.
+func (p PhysicalMaxOneRow) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalMaxOneRow {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeMaxOneRow, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// Init initializes LogicalLock.
+func (p LogicalLock) Init(ctx sessionctx.Context) *LogicalLock {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeLock, &p)
+	return &p
+}
+
+// Init initializes PhysicalLock.
+func (p PhysicalLock) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalLock {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeLock, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// Init initializes LogicalUnionAll.
+func (p LogicalUnionAll) Init(ctx sessionctx.Context) *LogicalUnionAll {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeUnion, &p)
+	return &p
+}
+
+// Init initializes PhysicalUnionAll.
+func (p PhysicalUnionAll) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalUnionAll {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeUnion, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
 // Init initializes LogicalTableDual.
 func (p LogicalTableDual) Init(ctx sessionctx.Context) *LogicalTableDual {
 	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeDual, &p)
@@ -205,6 +309,12 @@ func (p PhysicalTableDual) Init(ctx sessionctx.Context, stats *property.StatsInf
 	return &p
 }
 
+// Init initializes Update.
+func (p Update) Init(ctx sessionctx.Context) *Update {
+	p.basePlan = newBasePlan(ctx, TypeUpdate)
+	return &p
+}
+
 // Init initializes Delete.
 func (p Delete) Init(ctx sessionctx.Context) *Delete {
 	p.basePlan = newBasePlan(ctx, TypeDelete)
@@ -282,6 +392,22 @@ func (p PhysicalHashJoin) Init(ctx sessionctx.Context, stats *property.StatsInfo
 	return &p
 }
 
+// Init initializes PhysicalApply.
+func (p PhysicalApply) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalApply {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeApply, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// Init initializes PhysicalIndexJoin.
+func (p PhysicalIndexJoin) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalIndexJoin {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeIndexJoin, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
 // Init initializes PhysicalMergeJoin.
 func (p PhysicalMergeJoin) Init(ctx sessionctx.Context, stats *property.StatsInfo) *PhysicalMergeJoin {
 	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeMergeJoin, &p)
@@ -304,6 +430,14 @@ func (base basePhysicalAgg) initForHash(ctx sessionctx.Context, stats *property.
 	return p
 }
 
+func (base basePhysicalAgg) initForStream(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalStreamAgg {
+	p := &PhysicalStreamAgg{base}
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeStreamAgg, p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return p
+}
+
 // Init initializes PhysicalUnionScan.
 func (p PhysicalUnionScan) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalUnionScan {
 	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeUnionScan, &p)
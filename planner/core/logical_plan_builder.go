@@ -22,6 +22,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
@@ -31,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
@@ -40,6 +42,10 @@ import (
 )
 
 const (
+	// TiDBIndexNestedLoopJoin is hint enforce index nested loop join.
+	TiDBIndexNestedLoopJoin = "tidb_inlj"
+	// HintINLJ is hint enforce index nested loop join.
+	HintINLJ = "inl_join"
 	// TiDBMergeJoin is hint enforce merge join.
 	TiDBMergeJoin = "tidb_smj"
 	// HintSMJ is hint enforce merge join.
@@ -141,8 +147,14 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		switch v := x.Source.(type) {
 		case *ast.SelectStmt:
 			p, err = b.buildSelect(ctx, v)
+		case *ast.SetOprStmt:
+			p, err = b.buildSetOpr(ctx, v)
 		case *ast.TableName:
-			p, err = b.buildDataSource(ctx, v, &x.AsName)
+			if cte := b.findCTE(v); cte != nil {
+				p, err = b.buildCTERef(ctx, cte)
+			} else {
+				p, err = b.buildDataSource(ctx, v, &x.AsName)
+			}
 		default:
 			err = ErrUnsupportedType.GenWithStackByArgs(v)
 		}
@@ -171,6 +183,8 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		return p, nil
 	case *ast.SelectStmt:
 		return b.buildSelect(ctx, x)
+	case *ast.SetOprStmt:
+		return b.buildSetOpr(ctx, x)
 	default:
 		return nil, ErrUnsupportedType.GenWithStack("Unsupported ast.ResultSetNode(%T) for buildResultSetNode()", x)
 	}
@@ -248,8 +262,16 @@ func (p *LogicalJoin) ExtractOnCondition(
 							rightCond = append(rightCond, notNullExpr)
 						}
 					}
-					if binop.FuncName.L == ast.EQ {
-						cond := expression.NewFunctionInternal(ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), arg0, arg1)
+					// For queries like `select a in (select a from s where s.b = t.b) from t`,
+					// if subquery is empty caused by `s.b = t.b`, the result should always be
+					// false even if t.a is null or s.a is null. To make this join "empty aware",
+					// we should differentiate `t.a = s.a` from other column equal conditions, so
+					// we put it into OtherConditions instead of EqualConditions of join.
+					// The arguments of `<=>` built for set operators are not casted to the same type, they
+					// can't be join keys if their types differ since the values are hashed by the types.
+					isNullEQKey := binop.FuncName.L == ast.NullEQ && arg0.RetType.Tp == arg1.RetType.Tp
+					if (binop.FuncName.L == ast.EQ || isNullEQKey) && !arg0.InOperand && !arg1.InOperand {
+						cond := expression.NewFunctionInternal(ctx, binop.FuncName.L, types.NewFieldType(mysql.TypeTiny), arg0, arg1)
 						eqCond = append(eqCond, cond.(*expression.ScalarFunction))
 						continue
 					}
@@ -327,6 +349,12 @@ func (p *LogicalJoin) setPreferredJoinType(hintInfo *tableHintInfo) {
 	if hintInfo.ifPreferHashJoin(lhsAlias, rhsAlias) {
 		p.preferJoinType |= preferHashJoin
 	}
+	if hintInfo.ifPreferINLJ(lhsAlias) {
+		p.preferJoinType |= preferLeftAsIndexInner
+	}
+	if hintInfo.ifPreferINLJ(rhsAlias) {
+		p.preferJoinType |= preferRightAsIndexInner
+	}
 
 	// set hintInfo for further usage if this hint info can be used.
 	if p.preferJoinType != 0 {
@@ -666,11 +694,18 @@ func (b *PlanBuilder) buildSort(ctx context.Context, p LogicalPlan, byItems []*a
 
 // getUintFromNode gets uint64 value from ast.Node.
 // For ordinary statement, node should be uint64 constant value.
+// For prepared statement, node is a ParamMarkerExpr.
 func getUintFromNode(ctx sessionctx.Context, n ast.Node) (uVal uint64, isNull bool, isExpectedType bool) {
 	var val interface{}
 	switch v := n.(type) {
 	case *driver.ValueExpr:
 		val = v.GetValue()
+	case *driver.ParamMarkerExpr:
+		// The value is only known when the prepared statement is executed.
+		if !v.InExecute {
+			return 0, false, true
+		}
+		val = v.GetValue()
 	default:
 		return 0, false, false
 	}
@@ -798,6 +833,7 @@ func resolveFromSelectFields(v *ast.ColumnNameExpr, fields []*ast.SelectField, i
 // It converts ColunmNameExpr to AggregateFuncExpr and collects AggregateFuncExpr.
 type havingAndOrderbyExprResolver struct {
 	inAggFunc    bool
+	inWindowFunc bool
 	inExpr       bool
 	orderBy      bool
 	err          error
@@ -814,7 +850,11 @@ func (a *havingAndOrderbyExprResolver) Enter(n ast.Node) (node ast.Node, skipChi
 	switch n.(type) {
 	case *ast.AggregateFuncExpr:
 		a.inAggFunc = true
+	case *ast.WindowFuncExpr:
+		a.inWindowFunc = true
 	case *ast.ColumnNameExpr, *ast.ColumnName:
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr:
+		return n, true
 	default:
 		a.inExpr = true
 	}
@@ -861,9 +901,26 @@ func (a *havingAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, ok bool
 			Expr:      v,
 			AsName:    model.NewCIStr(fmt.Sprintf("sel_agg_%d", len(a.selectFields))),
 		})
+	case *ast.WindowFuncExpr:
+		a.inWindowFunc = false
+		if a.curClause == havingClause {
+			a.err = ErrWindowInvalidWindowFuncUse.GenWithStackByArgs(strings.ToLower(v.F))
+			return node, false
+		}
+		// The window function in order by clause is evaluated as an auxiliary field,
+		// then the order by item refers to it.
+		asName := model.NewCIStr(fmt.Sprintf("sel_window_%d", len(a.selectFields)))
+		colExpr := &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: asName}}
+		a.colMapper[colExpr] = len(a.selectFields)
+		a.selectFields = append(a.selectFields, &ast.SelectField{
+			Auxiliary: true,
+			Expr:      v,
+			AsName:    asName,
+		})
+		return colExpr, true
 	case *ast.ColumnNameExpr:
 		resolveFieldsFirst := true
-		if a.inAggFunc || (a.orderBy && a.inExpr) || a.curClause == fieldList {
+		if a.inAggFunc || a.inWindowFunc || (a.orderBy && a.inExpr) || a.curClause == fieldList {
 			resolveFieldsFirst = false
 		}
 		if !a.inAggFunc && !a.orderBy {
@@ -905,7 +962,7 @@ func (a *havingAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, ok bool
 			a.err = ErrUnknownColumn.GenWithStackByArgs(v.Name.OrigColName(), clauseMsg[a.curClause])
 			return node, false
 		}
-		if a.inAggFunc {
+		if a.inAggFunc || a.inWindowFunc {
 			return a.selectFields[index].Expr, true
 		}
 		a.colMapper[v] = index
@@ -985,6 +1042,8 @@ type gbyResolver struct {
 func (g *gbyResolver) Enter(inNode ast.Node) (ast.Node, bool) {
 	g.exprDepth++
 	switch inNode.(type) {
+	case *ast.SubqueryExpr, *ast.CompareSubqueryExpr, *ast.ExistsSubqueryExpr:
+		return inNode, true
 	case *driver.ValueExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.ColumnName:
 	default:
 		g.inExpr = true
@@ -1094,11 +1153,13 @@ func (b *PlanBuilder) unfoldWildStar(p LogicalPlan, selectFields []*ast.SelectFi
 
 func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint) {
 	var (
-		sortMergeTables, hashJoinTables []hintTableInfo
-		indexHintList                   []indexHintInfo
+		sortMergeTables, indexNestedLoopJoinTables, hashJoinTables []hintTableInfo
+		indexHintList                                              []indexHintInfo
 	)
 	for _, hint := range hints {
 		switch hint.HintName.L {
+		case TiDBIndexNestedLoopJoin, HintINLJ:
+			indexNestedLoopJoinTables = append(indexNestedLoopJoinTables, tableNames2HintTableInfo(b.ctx, hint.Tables)...)
 		case TiDBMergeJoin, HintSMJ:
 			sortMergeTables = append(sortMergeTables, tableNames2HintTableInfo(b.ctx, hint.Tables)...)
 		case TiDBHashJoin, HintHJ:
@@ -1141,14 +1202,16 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint) {
 		}
 	}
 	b.tableHintInfo = append(b.tableHintInfo, tableHintInfo{
-		sortMergeJoinTables: sortMergeTables,
-		hashJoinTables:      hashJoinTables,
-		indexHintList:       indexHintList,
+		indexNestedLoopJoinTables: indexNestedLoopJoinTables,
+		sortMergeJoinTables:       sortMergeTables,
+		hashJoinTables:            hashJoinTables,
+		indexHintList:             indexHintList,
 	})
 }
 
 func (b *PlanBuilder) popTableHints() {
 	hintInfo := b.tableHintInfo[len(b.tableHintInfo)-1]
+	b.appendUnmatchedJoinHintWarning(HintINLJ, TiDBIndexNestedLoopJoin, hintInfo.indexNestedLoopJoinTables)
 	b.appendUnmatchedJoinHintWarning(HintSMJ, TiDBMergeJoin, hintInfo.sortMergeJoinTables)
 	b.appendUnmatchedJoinHintWarning(HintHJ, TiDBHashJoin, hintInfo.hashJoinTables)
 	b.tableHintInfo = b.tableHintInfo[:len(b.tableHintInfo)-1]
@@ -1177,12 +1240,26 @@ func (b *PlanBuilder) TableHints() *tableHintInfo {
 }
 
 func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p LogicalPlan, err error) {
+	if sel.With != nil {
+		l := len(b.outerCTEs)
+		defer func() {
+			// CTEs are only visible in the statement defining them.
+			b.outerCTEs = b.outerCTEs[:l]
+		}()
+		if err = b.buildWith(ctx, sel, sel.With); err != nil {
+			return nil, err
+		}
+	}
 	b.pushTableHints(sel.TableHints)
 	defer func() {
 		// table hints are only visible in the current SELECT statement.
 		b.popTableHints()
 	}()
 
+	if err = resolveWindowSpecs(sel); err != nil {
+		return nil, err
+	}
+
 	if sel.SelectStmtOpts != nil {
 		origin := b.inStraightJoin
 		b.inStraightJoin = sel.SelectStmtOpts.StraightJoin
@@ -1232,6 +1309,9 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		}
 	}
 
+	if sel.LockTp != ast.SelectLockNone {
+		p = b.buildSelectLock(p, sel.LockTp)
+	}
 	b.handleHelper.popMap()
 	b.handleHelper.pushMap(nil)
 
@@ -1248,6 +1328,13 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		}
 	}
 
+	if b.detectSelectWindow(sel) {
+		p, err = b.buildWindowFunctions(ctx, p, extractWindowFuncs(sel.Fields.Fields), totalMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var oldLen int
 	p, oldLen, err = b.buildProjection(ctx, p, sel.Fields.Fields, totalMap)
 	if err != nil {
@@ -1299,6 +1386,263 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	return p, nil
 }
 
+// buildSetOpr builds the plan of UNION [ALL], INTERSECT and EXCEPT. INTERSECT
+// has a higher precedence than UNION and EXCEPT, which are evaluated from left to right.
+func (b *PlanBuilder) buildSetOpr(ctx context.Context, setOpr *ast.SetOprStmt) (LogicalPlan, error) {
+	if setOpr.With != nil {
+		l := len(b.outerCTEs)
+		defer func() {
+			b.outerCTEs = b.outerCTEs[:l]
+		}()
+		if err := b.buildWith(ctx, setOpr, setOpr.With); err != nil {
+			return nil, err
+		}
+	}
+	selects := setOpr.SelectList.Selects
+	// Divide the selects into groups connected by INTERSECT and build each group first.
+	// oprs[i] is the set operator before groups[i].
+	var (
+		groups []LogicalPlan
+		oprs   []*ast.SetOprType
+	)
+	for i := 0; i < len(selects); {
+		j := i + 1
+		for j < len(selects) && *selects[j].AfterSetOperator == ast.Intersect {
+			j++
+		}
+		p, err := b.buildIntersect(ctx, selects[i:j])
+		if err != nil {
+			return nil, err
+		}
+		if len(groups) > 0 && p.Schema().Len() != groups[0].Schema().Len() {
+			return nil, ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
+		}
+		groups = append(groups, p)
+		oprs = append(oprs, selects[i].AfterSetOperator)
+		i = j
+	}
+
+	var err error
+	p := groups[0]
+	for i := 1; i < len(groups); {
+		if *oprs[i] == ast.Except {
+			p, err = b.buildSemiJoinForSetOperator(p, groups[i], AntiSemiJoin)
+			if err != nil {
+				return nil, err
+			}
+			i++
+			continue
+		}
+		// Unite all the following groups connected by UNION [ALL].
+		j := i + 1
+		for j < len(groups) && *oprs[j] != ast.Except {
+			j++
+		}
+		p, err = b.buildUnion(ctx, append([]LogicalPlan{p}, groups[i:j]...), oprs[i:j])
+		if err != nil {
+			return nil, err
+		}
+		i = j
+	}
+
+	for range selects {
+		b.handleHelper.popMap()
+	}
+	b.handleHelper.pushMap(nil)
+
+	oldLen := p.Schema().Len()
+	if setOpr.OrderBy != nil {
+		p, err = b.buildSort(ctx, p, setOpr.OrderBy.Items, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if setOpr.Limit != nil {
+		p, err = b.buildLimit(p, setOpr.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Fix issue #8189 (https://github.com/pingcap/tidb/issues/8189).
+	// If there are extra expressions generated from `ORDER BY` clause, generate a `Projection` to remove them.
+	if oldLen != p.Schema().Len() {
+		proj := LogicalProjection{Exprs: expression.Column2Exprs(p.Schema().Columns[:oldLen])}.Init(b.ctx)
+		proj.SetChildren(p)
+		schema := expression.NewSchema(p.Schema().Clone().Columns[:oldLen]...)
+		for _, col := range schema.Columns {
+			col.UniqueID = b.ctx.GetSessionVars().AllocPlanColumnID()
+		}
+		proj.names = p.OutputNames()[:oldLen]
+		proj.SetSchema(schema)
+		return proj, nil
+	}
+	return p, nil
+}
+
+// buildIntersect builds the selects connected by INTERSECT from left to right.
+func (b *PlanBuilder) buildIntersect(ctx context.Context, selects []*ast.SelectStmt) (LogicalPlan, error) {
+	leftPlan, err := b.buildSelect(ctx, selects[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, sel := range selects[1:] {
+		rightPlan, err := b.buildSelect(ctx, sel)
+		if err != nil {
+			return nil, err
+		}
+		if rightPlan.Schema().Len() != leftPlan.Schema().Len() {
+			return nil, ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
+		}
+		leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, SemiJoin)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return leftPlan, nil
+}
+
+// buildSemiJoinForSetOperator builds INTERSECT and EXCEPT as a semi join or an anti semi join
+// between the distinct left plan and the right plan, two rows are matched when all of their
+// columns are null-safe equal.
+func (b *PlanBuilder) buildSemiJoinForSetOperator(leftOriginPlan, rightPlan LogicalPlan, joinType JoinType) (LogicalPlan, error) {
+	b.optFlag = b.optFlag | flagPredicatePushDown
+	leftPlan, err := b.buildDistinct(leftOriginPlan, leftOriginPlan.Schema().Len())
+	if err != nil {
+		return nil, err
+	}
+	joinPlan := LogicalJoin{JoinType: joinType}.Init(b.ctx)
+	joinPlan.SetChildren(leftPlan, rightPlan)
+	joinPlan.SetSchema(leftPlan.Schema())
+	joinPlan.names = make([]*types.FieldName, leftPlan.Schema().Len())
+	copy(joinPlan.names, leftPlan.OutputNames())
+	conds := make([]expression.Expression, 0, rightPlan.Schema().Len())
+	for i, rightCol := range rightPlan.Schema().Columns {
+		leftCol := leftPlan.Schema().Columns[i]
+		eqCond, err := expression.NewFunction(b.ctx, ast.NullEQ, types.NewFieldType(mysql.TypeTiny), leftCol, rightCol)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, eqCond)
+	}
+	joinPlan.attachOnConds(conds)
+	return joinPlan, nil
+}
+
+// buildUnion unites the plans, oprs[i] is the set operator between plans[i] and plans[i+1].
+// Mixed UNION types are treated such that a DISTINCT union overrides any ALL union to its left.
+// See https://dev.mysql.com/doc/refman/5.7/en/union.html
+func (b *PlanBuilder) buildUnion(ctx context.Context, plans []LogicalPlan, oprs []*ast.SetOprType) (LogicalPlan, error) {
+	firstUnionAllIdx := 0
+	for i := len(oprs) - 1; i >= 0; i-- {
+		if *oprs[i] == ast.Union {
+			firstUnionAllIdx = i + 2
+			break
+		}
+	}
+	distinctPlans, allPlans := plans[:firstUnionAllIdx], plans[firstUnionAllIdx:]
+
+	var unionPlan LogicalPlan
+	if len(distinctPlans) > 0 {
+		unionDistinctPlan, err := b.buildUnionAll(ctx, distinctPlans)
+		if err != nil {
+			return nil, err
+		}
+		unionPlan, err = b.buildDistinct(unionDistinctPlan, unionDistinctPlan.Schema().Len())
+		if err != nil {
+			return nil, err
+		}
+		// Can't change the statement order in order to get the correct column info.
+		allPlans = append([]LogicalPlan{unionPlan}, allPlans...)
+	}
+	if len(allPlans) > 1 {
+		return b.buildUnionAll(ctx, allPlans)
+	}
+	return unionPlan, nil
+}
+
+func (b *PlanBuilder) buildUnionAll(ctx context.Context, subPlan []LogicalPlan) (*LogicalUnionAll, error) {
+	u := LogicalUnionAll{}.Init(b.ctx)
+	u.children = subPlan
+	err := b.buildProjection4Union(ctx, u)
+	return u, err
+}
+
+// buildProjection4Union adds a projection above each child of the union,
+// so the schema of `UnionAll` can be the same with its children's.
+func (b *PlanBuilder) buildProjection4Union(ctx context.Context, u *LogicalUnionAll) error {
+	unionCols := make([]*expression.Column, 0, u.children[0].Schema().Len())
+	names := make([]*types.FieldName, 0, u.children[0].Schema().Len())
+
+	// Infer union result types by its children's schema.
+	for i, col := range u.children[0].Schema().Columns {
+		resultTp := col.RetType
+		for j := 1; j < len(u.children); j++ {
+			childTp := u.children[j].Schema().Columns[i].RetType
+			resultTp = unionJoinFieldType(resultTp, childTp)
+		}
+		names = append(names, &types.FieldName{ColName: u.children[0].OutputNames()[i].ColName})
+		unionCols = append(unionCols, &expression.Column{
+			RetType:  resultTp,
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		})
+	}
+	u.schema = expression.NewSchema(unionCols...)
+	u.names = names
+	// Process each child and add a projection above original child.
+	for childID, child := range u.children {
+		exprs := make([]expression.Expression, len(child.Schema().Columns))
+		for i, srcCol := range child.Schema().Columns {
+			if unionNeedCast(srcCol.RetType, unionCols[i].RetType) {
+				exprs[i] = expression.BuildCastFunction(b.ctx, srcCol, unionCols[i].RetType)
+			} else {
+				exprs[i] = srcCol
+			}
+		}
+		b.optFlag |= flagEliminateProjection
+		proj := LogicalProjection{Exprs: exprs, AvoidColumnEvaluator: true}.Init(b.ctx)
+		proj.SetSchema(u.schema.Clone())
+		// reset the schema type to make the "not null" flag right.
+		for i, expr := range exprs {
+			proj.schema.Columns[i].RetType = expr.GetType()
+		}
+		proj.SetChildren(child)
+		u.children[childID] = proj
+	}
+	return nil
+}
+
+// unionNeedCast checks whether a value of srcTp has to be converted before it is output as dstTp.
+// Values of the types with the same evaluation type share one representation, except the float ones.
+func unionNeedCast(srcTp, dstTp *types.FieldType) bool {
+	if srcTp.EvalType() != dstTp.EvalType() {
+		return true
+	}
+	return srcTp.EvalType() == types.ETReal && srcTp.Tp != dstTp.Tp
+}
+
+// unionJoinFieldType finds the type which can carry the given types in Union.
+func unionJoinFieldType(a, b *types.FieldType) *types.FieldType {
+	resultTp := types.NewFieldType(types.MergeFieldType(a.Tp, b.Tp))
+	// This logic will be intelligible when it is associated with the buildProjection4Union logic.
+	if resultTp.Tp == mysql.TypeNewDecimal {
+		// The decimal result type will be unsigned only when all the decimals to be united are unsigned.
+		resultTp.Flag &= b.Flag & mysql.UnsignedFlag
+	} else {
+		// Non-decimal results will be unsigned when the first SQL statement result in the union is unsigned.
+		resultTp.Flag |= a.Flag & mysql.UnsignedFlag
+	}
+	resultTp.Decimal = mathutil.Max(a.Decimal, b.Decimal)
+	// `Flen - Decimal` is the fraction before '.'
+	resultTp.Flen = mathutil.Max(a.Flen-a.Decimal, b.Flen-b.Decimal) + resultTp.Decimal
+	if resultTp.EvalType() != types.ETInt && (a.EvalType() == types.ETInt || b.EvalType() == types.ETInt) && resultTp.Flen < mysql.MaxIntWidth {
+		resultTp.Flen = mysql.MaxIntWidth
+	}
+	resultTp.Charset = a.Charset
+	resultTp.Collate = a.Collate
+	expression.SetBinFlagOrBinStr(b, resultTp)
+	return resultTp
+}
+
 func (b *PlanBuilder) buildTableDual() *LogicalTableDual {
 	b.handleHelper.pushMap(nil)
 	return LogicalTableDual{RowCount: 1}.Init(b.ctx)
@@ -1360,6 +1704,15 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	}
 
 	tableInfo := tbl.Meta()
+	var authErr error
+	if user := b.ctx.GetSessionVars().User; user != nil {
+		authErr = ErrTableaccessDenied.GenWithStackByArgs("SELECT", user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName.L, tableInfo.Name.L, "", authErr)
+
+	if tableInfo.IsView() {
+		return b.buildDataSourceFromView(ctx, dbName, tableInfo)
+	}
 
 	if tbl.Type().IsVirtualTable() {
 		return b.buildMemTable(ctx, dbName, tableInfo)
@@ -1374,6 +1727,10 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		return nil, err
 	}
 
+	if tableInfo.GetPartitionInfo() != nil {
+		b.optFlag = b.optFlag | flagPartitionProcessor
+	}
+
 	columns := tbl.Cols()
 	ds := DataSource{
 		DBName:              dbName,
@@ -1448,12 +1805,13 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 
 	// If this SQL is executed in a non-readonly transaction, we need a
 	// "UnionScan" operator to read the modifications of former SQLs, which is
-	// buffered in tidb-server memory.
+	// buffered in tidb-server memory. The history data read at a snapshot
+	// doesn't contain the modifications.
 	txn, err := b.ctx.Txn(false)
 	if err != nil {
 		return nil, err
 	}
-	if txn.Valid() && !txn.IsReadOnly() {
+	if txn.Valid() && !txn.IsReadOnly() && b.ctx.GetSessionVars().StmtCtx.SnapshotTS == 0 {
 		us := LogicalUnionScan{handleCol: handleCol}.Init(b.ctx)
 		us.SetChildren(ds)
 		result = us
@@ -1461,6 +1819,76 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	return result, nil
 }
 
+func viewKey(dbName, viewName model.CIStr) string {
+	return dbName.L + "." + viewName.L
+}
+
+// buildDataSourceFromView expands the view to the plan of its SELECT statement, the output
+// columns of the plan are renamed to the columns of the view.
+func (b *PlanBuilder) buildDataSourceFromView(ctx context.Context, dbName model.CIStr, tableInfo *model.TableInfo) (LogicalPlan, error) {
+	key := viewKey(dbName, tableInfo.Name)
+	if _, ok := b.buildingViews[key]; ok {
+		return nil, ErrViewRecursive.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
+	}
+	charset, collation := b.ctx.GetSessionVars().GetCharsetInfo()
+	selectNode, err := parser.New().ParseOneStmt(tableInfo.View.SelectStmt, charset, collation)
+	if err != nil {
+		return nil, err
+	}
+	if err = Preprocess(b.ctx, selectNode, b.is, WithDefaultDB(dbName.O)); err != nil {
+		return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
+	}
+
+	// The view is built in a new scope, the columns and CTEs of the outer query are invisible
+	// to it. The privileges of the underlying tables are checked when the view is created, so
+	// only the SELECT privilege of the view itself is required.
+	outerSchemas, outerNames, outerCTEs, visitInfo := b.outerSchemas, b.outerNames, b.outerCTEs, b.visitInfo
+	b.outerSchemas, b.outerNames, b.outerCTEs = nil, nil, nil
+	if b.buildingViews == nil {
+		b.buildingViews = make(map[string]struct{})
+	}
+	b.buildingViews[key] = struct{}{}
+	defer func() {
+		b.outerSchemas, b.outerNames, b.outerCTEs, b.visitInfo = outerSchemas, outerNames, outerCTEs, visitInfo
+		delete(b.buildingViews, key)
+	}()
+	p, err := b.buildResultSetNode(ctx, selectNode.(ast.ResultSetNode))
+	if err != nil {
+		if ErrViewRecursive.Equal(err) {
+			return nil, err
+		}
+		return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
+	}
+	viewCols := tableInfo.View.Cols
+	if p.Schema().Len() < len(viewCols) {
+		return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
+	}
+
+	proj := LogicalProjection{Exprs: make([]expression.Expression, 0, len(viewCols))}.Init(b.ctx)
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(viewCols))...)
+	names := make([]*types.FieldName, 0, len(viewCols))
+	for i, colName := range viewCols {
+		col := p.Schema().Columns[i]
+		proj.Exprs = append(proj.Exprs, col)
+		names = append(names, &types.FieldName{
+			DBName:      dbName,
+			TblName:     tableInfo.Name,
+			ColName:     colName,
+			OrigTblName: tableInfo.Name,
+			OrigColName: colName,
+		})
+		schema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  col.GetType(),
+			OrigName: names[i].String(),
+		})
+	}
+	proj.SetChildren(p)
+	proj.SetSchema(schema)
+	proj.names = names
+	return proj, nil
+}
+
 func (b *PlanBuilder) buildMemTable(ctx context.Context, dbName model.CIStr, tableInfo *model.TableInfo) (LogicalPlan, error) {
 	// We can use the `tableInfo.Columns` directly because the memory table has
 	// a stable schema and there is no online DDL on the memory table.
@@ -1594,50 +2022,143 @@ func extractDefaultExpr(node ast.ExprNode) *ast.DefaultExpr {
 	return nil
 }
 
-func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (Plan, error) {
-	p, err := b.buildResultSetNode(ctx, delete.TableRefs.TableRefs)
+// buildApplyWithJoinType builds apply plan with outerPlan and innerPlan with specific join type.
+func (b *PlanBuilder) buildApplyWithJoinType(outerPlan, innerPlan LogicalPlan, tp JoinType) LogicalPlan {
+	b.optFlag = b.optFlag | flagPredicatePushDown | flagBuildKeyInfo | flagDecorrelate
+	ap := LogicalApply{LogicalJoin: LogicalJoin{JoinType: tp}}.Init(b.ctx)
+	ap.SetChildren(outerPlan, innerPlan)
+	ap.names = make([]*types.FieldName, outerPlan.Schema().Len()+innerPlan.Schema().Len())
+	copy(ap.names, outerPlan.OutputNames())
+	ap.SetSchema(expression.MergeSchema(outerPlan.Schema(), innerPlan.Schema()))
+	// Note that, tp can only be LeftOuterJoin or InnerJoin, so we don't consider other outer joins.
+	if tp == LeftOuterJoin {
+		b.optFlag = b.optFlag | flagEliminateOuterJoin
+		resetNotNullFlag(ap.schema, outerPlan.Schema().Len(), ap.schema.Len())
+	}
+	for i := outerPlan.Schema().Len(); i < ap.Schema().Len(); i++ {
+		ap.names[i] = types.EmptyName
+	}
+	return ap
+}
+
+// buildSemiApply builds apply plan with outerPlan and innerPlan, which apply semi-join for every row from outerPlan and the whole innerPlan.
+func (b *PlanBuilder) buildSemiApply(outerPlan, innerPlan LogicalPlan, condition []expression.Expression, asScalar, not bool) LogicalPlan {
+	b.optFlag = b.optFlag | flagPredicatePushDown | flagBuildKeyInfo | flagDecorrelate
+
+	join := b.buildSemiJoin(outerPlan, innerPlan, condition, asScalar, not)
+	ap := &LogicalApply{LogicalJoin: *join}
+	ap.tp = TypeApply
+	ap.self = ap
+	return ap
+}
+
+func (b *PlanBuilder) buildSelectLock(src LogicalPlan, lock ast.SelectLockType) *LogicalLock {
+	selectLock := LogicalLock{
+		Lock:         lock,
+		tblID2Handle: b.handleHelper.tailMap(),
+	}.Init(b.ctx)
+	selectLock.SetChildren(src)
+	return selectLock
+}
+
+func (b *PlanBuilder) buildMaxOneRow(p LogicalPlan) LogicalPlan {
+	maxOneRow := LogicalMaxOneRow{}.Init(b.ctx)
+	maxOneRow.SetChildren(p)
+	return maxOneRow
+}
+
+func (b *PlanBuilder) buildSemiJoin(outerPlan, innerPlan LogicalPlan, onCondition []expression.Expression, asScalar bool, not bool) *LogicalJoin {
+	joinPlan := LogicalJoin{}.Init(b.ctx)
+	for i, expr := range onCondition {
+		onCondition[i] = expr.Decorrelate(outerPlan.Schema())
+	}
+	joinPlan.SetChildren(outerPlan, innerPlan)
+	joinPlan.attachOnConds(onCondition)
+	joinPlan.names = make([]*types.FieldName, outerPlan.Schema().Len(), outerPlan.Schema().Len()+1)
+	copy(joinPlan.names, outerPlan.OutputNames())
+	if asScalar {
+		newSchema := outerPlan.Schema().Clone()
+		newSchema.Append(&expression.Column{
+			RetType:  types.NewFieldType(mysql.TypeTiny),
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		})
+		joinPlan.names = append(joinPlan.names, types.EmptyName)
+		joinPlan.SetSchema(newSchema)
+		if not {
+			joinPlan.JoinType = AntiLeftOuterSemiJoin
+		} else {
+			joinPlan.JoinType = LeftOuterSemiJoin
+		}
+	} else {
+		joinPlan.SetSchema(outerPlan.Schema().Clone())
+		if not {
+			joinPlan.JoinType = AntiSemiJoin
+		} else {
+			joinPlan.JoinType = SemiJoin
+		}
+	}
+	// Apply forces to choose hash join currently, so don't worry the hints will take effect if the semi join is in one apply.
+	joinPlan.setPreferredJoinType(b.TableHints())
+	return joinPlan
+}
+
+func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (Plan, error) {
+	p, err := b.buildResultSetNode(ctx, update.TableRefs.TableRefs)
 	if err != nil {
 		return nil, err
 	}
-	oldSchema := p.Schema()
-	oldLen := oldSchema.Len()
 
-	if delete.Where != nil {
-		p, err = b.buildSelection(ctx, p, delete.Where, nil)
+	oldSchemaLen := p.Schema().Len()
+	if update.Where != nil {
+		p, err = b.buildSelection(ctx, p, update.Where, nil)
 		if err != nil {
 			return nil, err
 		}
 	}
-
-	if delete.Order != nil {
-		p, err = b.buildSort(ctx, p, delete.Order.Items, nil)
+	if update.Order != nil {
+		p, err = b.buildSort(ctx, p, update.Order.Items, nil)
 		if err != nil {
 			return nil, err
 		}
 	}
-
-	if delete.Limit != nil {
-		p, err = b.buildLimit(p, delete.Limit)
+	if update.Limit != nil {
+		p, err = b.buildLimit(p, update.Limit)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	proj := LogicalProjection{Exprs: expression.Column2Exprs(p.Schema().Columns[:oldLen])}.Init(b.ctx)
-	proj.SetChildren(p)
-	proj.SetSchema(oldSchema.Clone())
-	proj.names = p.OutputNames()[:oldLen]
-	p = proj
-
-	del := Delete{}.Init(b.ctx)
+	// The selection may append extra columns to the schema, cut them here so that
+	// the row layout seen by the executor matches the tables being updated.
+	if oldSchemaLen != p.Schema().Len() {
+		proj := LogicalProjection{Exprs: expression.Column2Exprs(p.Schema().Columns[:oldSchemaLen])}.Init(b.ctx)
+		proj.SetSchema(expression.NewSchema(make([]*expression.Column, oldSchemaLen)...))
+		proj.names = make(types.NameSlice, oldSchemaLen)
+		copy(proj.names, p.OutputNames())
+		copy(proj.schema.Columns, p.Schema().Columns[:oldSchemaLen])
+		proj.SetChildren(p)
+		p = proj
+	}
 
-	del.names = p.OutputNames()
-	del.SelectPlan, err = DoOptimize(ctx, b.optFlag, p)
+	orderedList, np, allAssignmentsAreConstant, err := b.buildUpdateLists(ctx, update.List, p)
 	if err != nil {
 		return nil, err
 	}
+	p = np
 
-	tblID2Handle, err := resolveIndicesForTblID2Handle(b.handleHelper.tailMap(), del.SelectPlan.Schema())
+	updt := Update{OrderedList: orderedList, AllAssignmentsAreConstant: allAssignmentsAreConstant}.Init(b.ctx)
+	updt.names = p.OutputNames()
+	// We cannot apply projection elimination when building the subplan, because
+	// columns in orderedList cannot be resolved.
+	updt.SelectPlan, err = DoOptimize(ctx, b.optFlag&^flagEliminateProjection, p)
+	if err != nil {
+		return nil, err
+	}
+	err = updt.ResolveIndices()
+	if err != nil {
+		return nil, err
+	}
+	tblID2Handle, err := resolveIndicesForTblID2Handle(b.handleHelper.tailMap(), updt.SelectPlan.Schema())
 	if err != nil {
 		return nil, err
 	}
@@ -1645,36 +2166,996 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 	for id := range tblID2Handle {
 		tblID2table[id], _ = b.is.TableByID(id)
 	}
-	del.TblColPosInfos, err = buildColumns2Handle(del.names, tblID2Handle, tblID2table, false)
-	return del, err
+	updt.TblColPosInfos, err = buildColumns2Handle(updt.OutputNames(), tblID2Handle, tblID2table, true)
+	if err == nil {
+		err = checkUpdateList(b.ctx, tblID2table, updt)
+	}
+	return updt, err
 }
 
-func resolveIndicesForTblID2Handle(tblID2Handle map[int64][]*expression.Column, schema *expression.Schema) (map[int64][]*expression.Column, error) {
-	newMap := make(map[int64][]*expression.Column, len(tblID2Handle))
-	for i, cols := range tblID2Handle {
-		for _, col := range cols {
-			resolvedCol, err := col.ResolveIndices(schema)
-			if err != nil {
-				return nil, err
-			}
-			newMap[i] = append(newMap[i], resolvedCol.(*expression.Column))
+// GetUpdateColumns gets the columns of updated lists.
+func GetUpdateColumns(ctx sessionctx.Context, orderedList []*expression.Assignment, schemaLen int) ([]bool, error) {
+	assignFlag := make([]bool, schemaLen)
+	for _, v := range orderedList {
+		if !ctx.GetSessionVars().AllowWriteRowID && v.Col.ID == model.ExtraHandleID {
+			columnName := &ast.ColumnName{Name: model.NewCIStr("_tidb_rowid")}
+			return nil, ErrUnknownColumn.GenWithStackByArgs(columnName, "field list")
 		}
+		idx := v.Col.Index
+		assignFlag[idx] = true
 	}
-	return newMap, nil
+	return assignFlag, nil
 }
 
-func getInnerFromParenthesesAndUnaryPlus(expr ast.ExprNode) ast.ExprNode {
-	if pexpr, ok := expr.(*ast.ParenthesesExpr); ok {
-		return getInnerFromParenthesesAndUnaryPlus(pexpr.Expr)
+// checkUpdateList forbids updating the primary key of a table that appears more
+// than once in a multiple-table update, since the rows would be moved twice.
+func checkUpdateList(ctx sessionctx.Context, tblID2table map[int64]table.Table, updt *Update) error {
+	assignFlags, err := GetUpdateColumns(ctx, updt.OrderedList, updt.SelectPlan.Schema().Len())
+	if err != nil {
+		return err
+	}
+	isPKUpdated := make(map[int64]model.CIStr)
+	for _, content := range updt.TblColPosInfos {
+		tbl := tblID2table[content.TblID]
+		flags := assignFlags[content.Start:content.End]
+		var updatePK bool
+		for i, col := range tbl.WritableCols() {
+			if flags[i] && mysql.HasPriKeyFlag(col.Flag) {
+				updatePK = true
+			}
+		}
+		if updatePK {
+			if otherTblName, ok := isPKUpdated[tbl.Meta().ID]; ok {
+				return ErrMultiUpdatePKConflict.GenWithStackByArgs(otherTblName.O, tbl.Meta().Name.O)
+			}
+			isPKUpdated[tbl.Meta().ID] = tbl.Meta().Name
+		}
 	}
-	if uexpr, ok := expr.(*ast.UnaryOperationExpr); ok && uexpr.Op == opcode.Plus {
-		return getInnerFromParenthesesAndUnaryPlus(uexpr.V)
+	return nil
+}
+
+func (b *PlanBuilder) buildUpdateLists(
+	ctx context.Context,
+	list []*ast.Assignment,
+	p LogicalPlan,
+) (newList []*expression.Assignment,
+	po LogicalPlan,
+	allAssignmentsAreConstant bool,
+	e error,
+) {
+	b.curClause = fieldList
+	allAssignmentsAreConstant = true
+	newList = make([]*expression.Assignment, 0, len(list))
+	for _, assign := range list {
+		idx, err := expression.FindFieldName(p.OutputNames(), assign.Column)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if idx < 0 {
+			return nil, nil, false, ErrUnknownColumn.GenWithStackByArgs(assign.Column.Name, "field list")
+		}
+		col := p.Schema().Columns[idx]
+		name := p.OutputNames()[idx]
+		if tbl, err := b.is.TableByName(name.DBName, name.OrigTblName); err == nil && tbl.Meta().IsView() {
+			return nil, nil, false, ErrNonUpdatableTable.GenWithStackByArgs(name.OrigTblName.O, "UPDATE")
+		}
+		var authErr error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("UPDATE", user.AuthUsername, user.AuthHostname, name.OrigTblName.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.UpdatePriv, name.DBName.L, name.OrigTblName.L, "", authErr)
+		// If assign `DEFAULT` to column, fill the `defaultExpr.Name` before rewrite expression
+		if expr := extractDefaultExpr(assign.Expr); expr != nil {
+			expr.Name = assign.Column
+		}
+		newExpr, np, err := b.rewrite(ctx, assign.Expr, p, nil, false)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if _, isConst := newExpr.(*expression.Constant); !isConst {
+			allAssignmentsAreConstant = false
+		}
+		p = np
+		newList = append(newList, &expression.Assignment{Col: col, ColName: name.ColName, Expr: newExpr})
 	}
-	return expr
+	return newList, p, allAssignmentsAreConstant, nil
+}
+
+func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (Plan, error) {
+	if ts, ok := delete.TableRefs.TableRefs.Left.(*ast.TableSource); ok {
+		if tn, ok := ts.Source.(*ast.TableName); ok && tn.TableInfo != nil && tn.TableInfo.IsView() {
+			return nil, ErrNonUpdatableTable.GenWithStackByArgs(tn.Name.O, "DELETE")
+		}
+	}
+	p, err := b.buildResultSetNode(ctx, delete.TableRefs.TableRefs)
+	if err != nil {
+		return nil, err
+	}
+	oldSchema := p.Schema()
+	oldLen := oldSchema.Len()
+
+	if delete.Where != nil {
+		p, err = b.buildSelection(ctx, p, delete.Where, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if delete.Order != nil {
+		p, err = b.buildSort(ctx, p, delete.Order.Items, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if delete.Limit != nil {
+		p, err = b.buildLimit(p, delete.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	proj := LogicalProjection{Exprs: expression.Column2Exprs(p.Schema().Columns[:oldLen])}.Init(b.ctx)
+	proj.SetChildren(p)
+	proj.SetSchema(oldSchema.Clone())
+	proj.names = p.OutputNames()[:oldLen]
+	p = proj
+
+	del := Delete{}.Init(b.ctx)
+
+	del.names = p.OutputNames()
+	del.SelectPlan, err = DoOptimize(ctx, b.optFlag, p)
+	if err != nil {
+		return nil, err
+	}
+
+	tblID2Handle, err := resolveIndicesForTblID2Handle(b.handleHelper.tailMap(), del.SelectPlan.Schema())
+	if err != nil {
+		return nil, err
+	}
+	tblID2table := make(map[int64]table.Table)
+	for id := range tblID2Handle {
+		tblID2table[id], _ = b.is.TableByID(id)
+	}
+	for _, tbl := range tblID2table {
+		dbInfo, _ := b.is.SchemaByTable(tbl.Meta())
+		if dbInfo == nil {
+			continue
+		}
+		var authErr error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("DELETE", user.AuthUsername, user.AuthHostname, tbl.Meta().Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DeletePriv, dbInfo.Name.L, tbl.Meta().Name.L, "", authErr)
+	}
+	del.TblColPosInfos, err = buildColumns2Handle(del.names, tblID2Handle, tblID2table, false)
+	return del, err
+}
+
+func resolveIndicesForTblID2Handle(tblID2Handle map[int64][]*expression.Column, schema *expression.Schema) (map[int64][]*expression.Column, error) {
+	newMap := make(map[int64][]*expression.Column, len(tblID2Handle))
+	for i, cols := range tblID2Handle {
+		for _, col := range cols {
+			resolvedCol, err := col.ResolveIndices(schema)
+			if err != nil {
+				return nil, err
+			}
+			newMap[i] = append(newMap[i], resolvedCol.(*expression.Column))
+		}
+	}
+	return newMap, nil
+}
+
+func getInnerFromParenthesesAndUnaryPlus(expr ast.ExprNode) ast.ExprNode {
+	if pexpr, ok := expr.(*ast.ParenthesesExpr); ok {
+		return getInnerFromParenthesesAndUnaryPlus(pexpr.Expr)
+	}
+	if uexpr, ok := expr.(*ast.UnaryOperationExpr); ok && uexpr.Op == opcode.Plus {
+		return getInnerFromParenthesesAndUnaryPlus(uexpr.V)
+	}
+	return expr
 }
 
 // containDifferentJoinTypes checks whether `preferJoinType` contains different
 // join types.
 func containDifferentJoinTypes(preferJoinType uint) bool {
+	// Both sides of the join can be hinted as the inner side of index join,
+	// it is still one type of join.
+	inlMask := preferLeftAsIndexInner | preferRightAsIndexInner
+	if preferJoinType&inlMask > 0 {
+		preferJoinType = preferJoinType&^inlMask | preferRightAsIndexInner
+	}
 	return bits.OnesCount(preferJoinType) > 1
 }
+
+func extractWindowFuncs(fields []*ast.SelectField) []*ast.WindowFuncExpr {
+	extractor := &WindowFuncExtractor{}
+	for _, f := range fields {
+		n, _ := f.Expr.Accept(extractor)
+		f.Expr = n.(ast.ExprNode)
+	}
+	return extractor.WindowFuncs
+}
+
+// getWindowName returns the name of the window in the error messages.
+func getWindowName(name string) string {
+	if name == "" {
+		return "<unnamed window>"
+	}
+	return name
+}
+
+// resolveWindowSpecs merges the windows defined in the WINDOW clause into the
+// specifications referring to them by name, so that every window function
+// carries its complete window specification.
+func resolveWindowSpecs(sel *ast.SelectStmt) error {
+	specs := make(map[string]*ast.WindowSpec, len(sel.WindowSpecs))
+	for i := range sel.WindowSpecs {
+		spec := &sel.WindowSpecs[i]
+		if _, ok := specs[spec.Name.L]; ok {
+			return ErrWindowDuplicateName.GenWithStackByArgs(spec.Name.O)
+		}
+		specs[spec.Name.L] = spec
+	}
+	// resolving records the windows on the path of the references, a window
+	// met twice means a circular reference.
+	resolving := make(map[string]bool, len(specs))
+	var resolve func(spec *ast.WindowSpec) error
+	resolve = func(spec *ast.WindowSpec) error {
+		if spec.Ref.L == "" {
+			return nil
+		}
+		ref, ok := specs[spec.Ref.L]
+		if !ok {
+			return ErrWindowNoSuchWindow.GenWithStackByArgs(spec.Ref.O)
+		}
+		if resolving[ref.Name.L] {
+			return ErrWindowCircularityInWindowGraph.GenWithStackByArgs()
+		}
+		resolving[ref.Name.L] = true
+		err := resolve(ref)
+		delete(resolving, ref.Name.L)
+		if err != nil {
+			return err
+		}
+		return mergeWindowSpec(spec, ref)
+	}
+	for i := range sel.WindowSpecs {
+		spec := &sel.WindowSpecs[i]
+		resolving[spec.Name.L] = true
+		err := resolve(spec)
+		delete(resolving, spec.Name.L)
+		if err != nil {
+			return err
+		}
+		if err = checkWindowSpec(spec); err != nil {
+			return err
+		}
+	}
+
+	extractor := &WindowFuncExtractor{}
+	for _, f := range sel.Fields.Fields {
+		if f.Expr != nil {
+			f.Expr.Accept(extractor)
+		}
+	}
+	if sel.OrderBy != nil {
+		for _, item := range sel.OrderBy.Items {
+			item.Expr.Accept(extractor)
+		}
+	}
+	for _, windowFunc := range extractor.WindowFuncs {
+		if err := resolve(&windowFunc.Spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeWindowSpec merges the window ref into spec which refers to it. The
+// window referred can't have a frame, and only the ORDER BY clause missing in
+// it can be added by spec.
+func mergeWindowSpec(spec, ref *ast.WindowSpec) error {
+	if spec.OnlyAlias {
+		*spec = *ref
+		return nil
+	}
+	if ref.Frame != nil {
+		return ErrWindowNoInherentFrame.GenWithStackByArgs(ref.Name.O)
+	}
+	if spec.PartitionBy != nil {
+		return ErrWindowNoChildPartitioning.GenWithStackByArgs()
+	}
+	if ref.OrderBy != nil {
+		if spec.OrderBy != nil {
+			return ErrWindowNoRedefineOrderBy.GenWithStackByArgs(getWindowName(spec.Name.O), ref.Name.O)
+		}
+		spec.OrderBy = ref.OrderBy
+	}
+	spec.PartitionBy = ref.PartitionBy
+	spec.Ref = model.CIStr{}
+	return nil
+}
+
+// buildWindowFunctions builds a LogicalWindow for every window function, and
+// records the column of the function result in the windowMapper, so that the
+// projection upon them can refer to the results.
+func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, windowFuncs []*ast.WindowFuncExpr, aggMap map[*ast.AggregateFuncExpr]int) (LogicalPlan, error) {
+	for _, windowFunc := range windowFuncs {
+		name := strings.ToLower(windowFunc.F)
+		spec := &windowFunc.Spec
+		if err := checkWindowSpec(spec); err != nil {
+			return nil, err
+		}
+		np, partitionBy, orderBy, args, err := b.buildProjectionForWindow(ctx, p, spec, windowFunc.Args, aggMap)
+		if err != nil {
+			return nil, err
+		}
+		if spec.Frame != nil {
+			if err = b.checkWindowFrameBound(spec, &spec.Frame.Extent.Start, orderBy); err != nil {
+				return nil, err
+			}
+			if err = b.checkWindowFrameBound(spec, &spec.Frame.Extent.End, orderBy); err != nil {
+				return nil, err
+			}
+		}
+		frame, err := b.buildWindowFunctionFrame(b.handleDefaultFrame(spec, name), orderBy)
+		if err != nil {
+			return nil, err
+		}
+		desc, err := aggregation.NewWindowFuncDesc(b.ctx, name, args)
+		if err != nil {
+			return nil, err
+		}
+		if desc == nil {
+			return nil, ErrWrongArguments.GenWithStackByArgs(strings.ToUpper(name))
+		}
+		window := LogicalWindow{
+			WindowFuncDescs: []*aggregation.WindowFuncDesc{desc},
+			PartitionBy:     partitionBy,
+			OrderBy:         orderBy,
+			Frame:           frame,
+		}.Init(b.ctx)
+		schema := np.Schema().Clone()
+		schema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  desc.RetTp,
+		})
+		names := make([]*types.FieldName, 0, schema.Len())
+		names = append(names, np.OutputNames()...)
+		window.names = append(names, types.EmptyName)
+		window.SetSchema(schema)
+		window.SetChildren(np)
+		b.windowMapper[windowFunc] = schema.Len() - 1
+		p = window
+	}
+	return p, nil
+}
+
+// buildProjectionForWindow builds the projection below the window, it evaluates
+// the partition by items, order by items and arguments which are not columns.
+func (b *PlanBuilder) buildProjectionForWindow(ctx context.Context, p LogicalPlan, spec *ast.WindowSpec, args []ast.ExprNode, aggMap map[*ast.AggregateFuncExpr]int) (LogicalPlan, []property.Item, []property.Item, []expression.Expression, error) {
+	b.optFlag |= flagEliminateProjection
+
+	var partitionItems, orderItems []*ast.ByItem
+	if spec.PartitionBy != nil {
+		partitionItems = spec.PartitionBy.Items
+	}
+	if spec.OrderBy != nil {
+		orderItems = spec.OrderBy.Items
+	}
+
+	projLen := p.Schema().Len() + len(partitionItems) + len(orderItems) + len(args)
+	proj := LogicalProjection{Exprs: make([]expression.Expression, 0, projLen)}.Init(b.ctx)
+	proj.SetSchema(expression.NewSchema(make([]*expression.Column, 0, projLen)...))
+	proj.names = make([]*types.FieldName, 0, projLen)
+	for _, col := range p.Schema().Columns {
+		proj.Exprs = append(proj.Exprs, col)
+		proj.schema.Append(col)
+	}
+	proj.names = append(proj.names, p.OutputNames()...)
+
+	p, partitionBy, err := b.buildByItemsForWindow(ctx, p, proj, partitionItems, aggMap)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	p, orderBy, err := b.buildByItemsForWindow(ctx, p, proj, orderItems, aggMap)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	newArgs := make([]expression.Expression, 0, len(args))
+	for _, arg := range args {
+		newArg, np, err := b.rewrite(ctx, arg, p, aggMap, true)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		p = np
+		switch newArg.(type) {
+		case *expression.Column, *expression.Constant:
+			newArgs = append(newArgs, newArg)
+			continue
+		}
+		newArgs = append(newArgs, proj.appendExprForWindow(b.ctx, newArg))
+	}
+
+	proj.SetChildren(p)
+	return proj, partitionBy, orderBy, newArgs, nil
+}
+
+func (b *PlanBuilder) buildByItemsForWindow(ctx context.Context, p LogicalPlan, proj *LogicalProjection, items []*ast.ByItem, aggMap map[*ast.AggregateFuncExpr]int) (LogicalPlan, []property.Item, error) {
+	retItems := make([]property.Item, 0, len(items))
+	for _, item := range items {
+		it, np, err := b.rewrite(ctx, item.Expr, p, aggMap, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		p = np
+		// The constant items don't affect the partitions and the order.
+		if _, ok := it.(*expression.Constant); ok {
+			continue
+		}
+		col, ok := it.(*expression.Column)
+		if !ok {
+			col = proj.appendExprForWindow(b.ctx, it)
+		}
+		retItems = append(retItems, property.Item{Col: col, Desc: item.Desc})
+	}
+	return p, retItems, nil
+}
+
+// appendExprForWindow appends an expression to the projection below the window
+// and returns the column of it.
+func (p *LogicalProjection) appendExprForWindow(ctx sessionctx.Context, expr expression.Expression) *expression.Column {
+	col := &expression.Column{
+		UniqueID: ctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  expr.GetType(),
+	}
+	p.Exprs = append(p.Exprs, expr)
+	p.schema.Append(col)
+	p.names = append(p.names, types.EmptyName)
+	return col
+}
+
+// checkWindowSpec checks the window specification before it is built.
+func checkWindowSpec(spec *ast.WindowSpec) error {
+	var items []*ast.ByItem
+	if spec.PartitionBy != nil {
+		items = append(items, spec.PartitionBy.Items...)
+	}
+	if spec.OrderBy != nil {
+		items = append(items, spec.OrderBy.Items...)
+	}
+	for _, item := range items {
+		if ast.HasWindowFlag(item.Expr) {
+			return ErrWindowNestedWindowFuncUseInWindowSpec.GenWithStackByArgs(getWindowName(spec.Name.O))
+		}
+	}
+	if spec.Frame == nil {
+		return nil
+	}
+	start, end := spec.Frame.Extent.Start, spec.Frame.Extent.End
+	if start.Type == ast.Following && start.UnBounded {
+		return ErrWindowFrameStartIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
+	}
+	if end.Type == ast.Preceding && end.UnBounded {
+		return ErrWindowFrameEndIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
+	}
+	// The frame must not end before it starts.
+	if start.Type == ast.Following && (end.Type == ast.Preceding || end.Type == ast.CurrentRow) {
+		return ErrWindowFrameIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
+	}
+	if start.Type == ast.CurrentRow && end.Type == ast.Preceding {
+		return ErrWindowFrameIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
+	}
+	return nil
+}
+
+// checkWindowFrameBound checks the offset of a frame bound, the rows frames need
+// an unsigned integer, and the range frames need exactly one numeric order by item.
+func (b *PlanBuilder) checkWindowFrameBound(spec *ast.WindowSpec, bound *ast.FrameBound, orderBy []property.Item) error {
+	if bound.Type == ast.CurrentRow || bound.UnBounded {
+		return nil
+	}
+	if spec.Frame.Type == ast.Rows {
+		_, isNull, isExpectedType := getUintFromNode(b.ctx, bound.Expr)
+		if isNull || !isExpectedType {
+			return ErrWindowFrameIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
+		}
+		return nil
+	}
+	if len(orderBy) != 1 || !types.IsTypeNumeric(orderBy[0].Col.RetType.Tp) {
+		return ErrWindowRangeFrameOrderType.GenWithStackByArgs(getWindowName(spec.Name.O))
+	}
+	return nil
+}
+
+// handleDefaultFrame returns the window specification with the frame that the
+// window function is actually evaluated on.
+func (b *PlanBuilder) handleDefaultFrame(spec *ast.WindowSpec, windowFuncName string) *ast.WindowSpec {
+	needFrame := aggregation.NeedFrame(windowFuncName)
+	// Without a frame clause, the default frame is "RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW"
+	// if there is an order by clause, otherwise it is the whole partition, which is the same as no frame.
+	if needFrame && spec.Frame == nil && spec.OrderBy != nil {
+		newSpec := *spec
+		newSpec.Frame = &ast.FrameClause{
+			Type: ast.Ranges,
+			Extent: ast.FrameExtent{
+				Start: ast.FrameBound{Type: ast.Preceding, UnBounded: true},
+				End:   ast.FrameBound{Type: ast.CurrentRow},
+			},
+		}
+		return &newSpec
+	}
+	// "RANGE/ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING" is the whole partition.
+	if needFrame && spec.Frame != nil && spec.Frame.Extent.Start.UnBounded && spec.Frame.Extent.End.UnBounded {
+		newSpec := *spec
+		newSpec.Frame = nil
+		return &newSpec
+	}
+	// The functions operating on the whole partition ignore the frame clause.
+	if !needFrame && spec.Frame != nil {
+		b.ctx.GetSessionVars().StmtCtx.AppendNote(ErrWindowFunctionIgnoresFrame.GenWithStackByArgs(windowFuncName, getWindowName(spec.Name.O)))
+		newSpec := *spec
+		newSpec.Frame = nil
+		return &newSpec
+	}
+	return spec
+}
+
+func (b *PlanBuilder) buildWindowFunctionFrame(spec *ast.WindowSpec, orderBy []property.Item) (*WindowFrame, error) {
+	if spec.Frame == nil {
+		return nil, nil
+	}
+	frame := &WindowFrame{Type: spec.Frame.Type}
+	var err error
+	frame.Start, err = b.buildWindowFunctionFrameBound(&spec.Frame.Extent.Start, spec.Frame.Type, orderBy)
+	if err != nil {
+		return nil, err
+	}
+	frame.End, err = b.buildWindowFunctionFrameBound(&spec.Frame.Extent.End, spec.Frame.Type, orderBy)
+	return frame, err
+}
+
+func (b *PlanBuilder) buildWindowFunctionFrameBound(boundClause *ast.FrameBound, frameType ast.FrameType, orderBy []property.Item) (*FrameBound, error) {
+	bound := &FrameBound{Type: boundClause.Type, UnBounded: boundClause.UnBounded}
+	if bound.UnBounded {
+		return bound, nil
+	}
+
+	if frameType == ast.Rows {
+		if bound.Type != ast.CurrentRow {
+			bound.Num, _, _ = getUintFromNode(b.ctx, boundClause.Expr)
+		}
+		return bound, nil
+	}
+
+	// The rows of the range frame are decided by comparing the order by items of
+	// them with the bound calculated from the current row.
+	bound.CalcFuncs = make([]expression.Expression, len(orderBy))
+	bound.CmpFuncs = make([]expression.CompareFunc, len(orderBy))
+	if bound.Type == ast.CurrentRow {
+		for i, item := range orderBy {
+			bound.CalcFuncs[i] = item.Col
+			bound.CmpFuncs[i] = expression.GetCmpFunction(item.Col, item.Col)
+		}
+		return bound, nil
+	}
+
+	val, err := evalAstExpr(b.ctx, boundClause.Expr)
+	if err != nil {
+		return nil, err
+	}
+	expr := &expression.Constant{Value: val, RetType: boundClause.Expr.GetType()}
+	// When the order is asc, the bound is `col + offset` for following, and `col - offset`
+	// for preceding. When the order is desc, `+` becomes `-` and vice-versa.
+	col, desc := orderBy[0].Col, orderBy[0].Desc
+	funcName := ast.Plus
+	if (!desc && bound.Type == ast.Preceding) || (desc && bound.Type == ast.Following) {
+		funcName = ast.Minus
+	}
+	bound.CalcFuncs[0], err = expression.NewFunctionBase(b.ctx, funcName, col.RetType, col, expr)
+	if err != nil {
+		return nil, err
+	}
+	bound.CmpFuncs[0] = expression.GetCmpFunction(col, bound.CalcFuncs[0])
+	return bound, nil
+}
+
+// cteInfo records a common table expression visible to the query block being built.
+type cteInfo struct {
+	def *ast.CommonTableExpression
+	// isInline indicates the CTE is built at its reference like a derived table.
+	isInline bool
+	// cte is the shared result of a materialized or recursive CTE.
+	cte *CTEClass
+	// isBuilding indicates the recursive part of the CTE is being built, so its references
+	// read the working table of the recursion.
+	isBuilding bool
+
+	// The scope where the CTE is defined, an inlined CTE is built in it.
+	outerSchemas []*expression.Schema
+	outerNames   [][]*types.FieldName
+	outerCTEs    []*cteInfo
+}
+
+// buildWith registers the common table expressions of a WITH clause to the builder. A
+// non-recursive CTE referenced at most once is inlined at its reference, otherwise it is
+// built and optimized here so that all of its references share one result.
+func (b *PlanBuilder) buildWith(ctx context.Context, stmt ast.Node, with *ast.WithClause) error {
+	defined := make(map[string]struct{}, len(with.CTEs))
+	for i, def := range with.CTEs {
+		if _, ok := defined[def.Name.L]; ok {
+			return ErrNonUniqTable.GenWithStackByArgs(def.Name.O)
+		}
+		defined[def.Name.L] = struct{}{}
+		info := &cteInfo{
+			def:          def,
+			outerSchemas: b.outerSchemas,
+			outerNames:   b.outerNames,
+			outerCTEs:    b.outerCTEs,
+		}
+		if def.IsRecursive {
+			if refs, _ := countCTERefs(def.Query.Query, def.Name, nil); refs > 0 {
+				b.outerCTEs = append(b.outerCTEs, info)
+				if err := b.buildRecursiveCTE(ctx, info); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		refs, _ := countCTERefs(stmt, def.Name, with)
+		for _, later := range with.CTEs[i+1:] {
+			laterRefs, _ := countCTERefs(later.Query.Query, def.Name, nil)
+			refs += laterRefs
+		}
+		if refs <= 1 {
+			info.isInline = true
+		} else if err := b.buildMaterializedCTE(ctx, info); err != nil {
+			return err
+		}
+		b.outerCTEs = append(b.outerCTEs, info)
+	}
+	return nil
+}
+
+// buildMaterializedCTE builds and optimizes a non-recursive CTE referenced more than once.
+func (b *PlanBuilder) buildMaterializedCTE(ctx context.Context, info *cteInfo) error {
+	p, err := b.buildResultSetNode(ctx, info.def.Query.Query)
+	if err != nil {
+		return err
+	}
+	b.handleHelper.popMap()
+	if len(p.extractCorrelatedCols()) > 0 {
+		// The result of a CTE referring to the outer query changes with the outer row,
+		// so it can't be shared and is inlined at each of its references instead.
+		info.isInline = true
+		return nil
+	}
+	names, err := b.buildCTENames(info.def, p)
+	if err != nil {
+		return err
+	}
+	fieldTypes := make([]*types.FieldType, 0, p.Schema().Len())
+	for _, col := range p.Schema().Columns {
+		fieldTypes = append(fieldTypes, col.RetType.Clone())
+	}
+	physicalPlan, err := DoOptimize(ctx, b.optFlag, p)
+	if err != nil {
+		return err
+	}
+	info.cte = &CTEClass{
+		IDForStorage:         b.allocCTEStorageID(),
+		seedPartPhysicalPlan: physicalPlan,
+		fieldTypes:           fieldTypes,
+		names:                names,
+		seedStat:             physicalPlan.statsInfo(),
+	}
+	return nil
+}
+
+// buildRecursiveCTE builds a recursive CTE, which is the seed part united with the recursive
+// part. The seed part is evaluated once, then the recursive part is evaluated repeatedly on
+// the rows produced by the previous iteration until no new row is produced.
+func (b *PlanBuilder) buildRecursiveCTE(ctx context.Context, info *cteInfo) error {
+	def := info.def
+	setOpr, ok := def.Query.Query.(*ast.SetOprStmt)
+	if !ok {
+		return ErrCTERecursiveRequiresUnion.GenWithStackByArgs(def.Name.O)
+	}
+	if setOpr.With != nil {
+		l := len(b.outerCTEs)
+		defer func() {
+			b.outerCTEs = b.outerCTEs[:l]
+		}()
+		if err := b.buildWith(ctx, setOpr, setOpr.With); err != nil {
+			return err
+		}
+	}
+
+	// Split the selects into the seed part and the recursive part, the recursive part
+	// starts from the first select referring to the CTE itself.
+	selects := setOpr.SelectList.Selects
+	seedEnd := len(selects)
+	for i, sel := range selects {
+		if i > 0 && *sel.AfterSetOperator != ast.Union && *sel.AfterSetOperator != ast.UnionAll {
+			return ErrCTERecursiveRequiresUnion.GenWithStackByArgs(def.Name.O)
+		}
+		if refs, _ := countCTERefs(sel, def.Name, nil); refs > 0 {
+			if seedEnd == len(selects) {
+				seedEnd = i
+			}
+		} else if seedEnd < len(selects) {
+			return ErrCTERecursiveRequiresNonRecursiveFirst.GenWithStackByArgs(def.Name.O)
+		}
+	}
+	if seedEnd == 0 {
+		return ErrCTERecursiveRequiresNonRecursiveFirst.GenWithStackByArgs(def.Name.O)
+	}
+	if seedEnd == len(selects) {
+		// The CTE is only referred in the ORDER BY clause of the set operation.
+		return ErrInvalidRequiresSingleReference.GenWithStackByArgs(def.Name.O)
+	}
+	if setOpr.OrderBy != nil || setOpr.Limit != nil {
+		return ErrNotSupportedYet.GenWithStackByArgs("ORDER BY / LIMIT / SELECT DISTINCT in recursive query block of Common Table Expression")
+	}
+	isDistinct := false
+	for _, sel := range selects[seedEnd:] {
+		if err := b.checkRecursiveQueryBlock(sel, def.Name); err != nil {
+			return err
+		}
+		if *sel.AfterSetOperator == ast.Union {
+			isDistinct = true
+		}
+	}
+
+	seedPlan, err := b.buildSelectsOfCTE(ctx, selects[:seedEnd])
+	if err != nil {
+		return err
+	}
+	b.handleHelper.popMap()
+	if len(seedPlan.extractCorrelatedCols()) > 0 {
+		return ErrNotSupportedYet.GenWithStackByArgs("recursive Common Table Expression referring to the outer query")
+	}
+	names, err := b.buildCTENames(def, seedPlan)
+	if err != nil {
+		return err
+	}
+	// The rows produced by the recursive part may be null even if the seed part is not.
+	fieldTypes := make([]*types.FieldType, 0, seedPlan.Schema().Len())
+	for _, col := range seedPlan.Schema().Columns {
+		tp := col.RetType.Clone()
+		tp.Flag &^= mysql.NotNullFlag
+		fieldTypes = append(fieldTypes, tp)
+	}
+	seedPhysicalPlan, err := DoOptimize(ctx, b.optFlag, seedPlan)
+	if err != nil {
+		return err
+	}
+	info.cte = &CTEClass{
+		IDForStorage:         b.allocCTEStorageID(),
+		IsDistinct:           isDistinct,
+		seedPartPhysicalPlan: seedPhysicalPlan,
+		fieldTypes:           fieldTypes,
+		names:                names,
+		seedStat:             seedPhysicalPlan.statsInfo(),
+	}
+
+	info.isBuilding = true
+	recurPlan, err := b.buildSelectsOfCTE(ctx, selects[seedEnd:])
+	info.isBuilding = false
+	if err != nil {
+		return err
+	}
+	b.handleHelper.popMap()
+	if recurPlan.Schema().Len() != len(fieldTypes) {
+		return ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
+	}
+	recurPlan = b.buildProjection4RecursivePart(recurPlan, fieldTypes)
+	if len(recurPlan.extractCorrelatedCols()) > 0 {
+		return ErrNotSupportedYet.GenWithStackByArgs("recursive Common Table Expression referring to the outer query")
+	}
+	info.cte.recursivePartPhysicalPlan, err = DoOptimize(ctx, b.optFlag, recurPlan)
+	return err
+}
+
+// buildProjection4RecursivePart converts the output of the recursive part to the
+// types of the CTE, which are decided by the seed part.
+func (b *PlanBuilder) buildProjection4RecursivePart(p LogicalPlan, fieldTypes []*types.FieldType) LogicalPlan {
+	needCast := false
+	exprs := make([]expression.Expression, 0, len(fieldTypes))
+	for i, col := range p.Schema().Columns {
+		srcTp, dstTp := col.RetType, fieldTypes[i]
+		// The strings longer than the column of the seed part are cast to its length too.
+		longerStr := srcTp.EvalType() == types.ETString && dstTp.EvalType() == types.ETString &&
+			dstTp.Flen != types.UnspecifiedLength && (srcTp.Flen == types.UnspecifiedLength || srcTp.Flen > dstTp.Flen)
+		if unionNeedCast(srcTp, dstTp) || longerStr {
+			needCast = true
+			exprs = append(exprs, expression.BuildCastFunction(b.ctx, col, fieldTypes[i]))
+		} else {
+			exprs = append(exprs, col)
+		}
+	}
+	if !needCast {
+		return p
+	}
+	proj := LogicalProjection{Exprs: exprs, AvoidColumnEvaluator: true}.Init(b.ctx)
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(exprs))...)
+	for _, expr := range exprs {
+		schema.Append(&expression.Column{
+			RetType:  expr.GetType(),
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		})
+	}
+	proj.SetSchema(schema)
+	proj.names = p.OutputNames()
+	proj.SetChildren(p)
+	return proj
+}
+
+// checkRecursiveQueryBlock checks the restrictions of a query block in the recursive part.
+func (b *PlanBuilder) checkRecursiveQueryBlock(sel *ast.SelectStmt, name model.CIStr) error {
+	refs, refsInSubquery := countCTERefs(sel, name, nil)
+	if refs != 1 || refsInSubquery > 0 {
+		return ErrInvalidRequiresSingleReference.GenWithStackByArgs(name.O)
+	}
+	if b.detectSelectAgg(sel) || b.detectSelectWindow(sel) {
+		return ErrCTERecursiveForbidsAggregation.GenWithStackByArgs(name.O)
+	}
+	if sel.Distinct || sel.OrderBy != nil || sel.Limit != nil {
+		return ErrNotSupportedYet.GenWithStackByArgs("ORDER BY / LIMIT / SELECT DISTINCT in recursive query block of Common Table Expression")
+	}
+	return nil
+}
+
+// buildSelectsOfCTE builds the seed part or the recursive part of a recursive CTE.
+func (b *PlanBuilder) buildSelectsOfCTE(ctx context.Context, selects []*ast.SelectStmt) (LogicalPlan, error) {
+	if len(selects) == 1 {
+		return b.buildSelect(ctx, selects[0])
+	}
+	return b.buildSetOpr(ctx, &ast.SetOprStmt{SelectList: &ast.SetOprSelectList{Selects: selects}})
+}
+
+// buildCTENames generates the output names of a CTE, the column list of the CTE overrides
+// the names of the columns selected by its definition.
+func (b *PlanBuilder) buildCTENames(def *ast.CommonTableExpression, p LogicalPlan) ([]*types.FieldName, error) {
+	if len(def.ColNameList) > 0 && len(def.ColNameList) != p.Schema().Len() {
+		return nil, ErrViewWrongList.GenWithStackByArgs()
+	}
+	names := make([]*types.FieldName, 0, p.Schema().Len())
+	for i, name := range p.OutputNames() {
+		colName := name.ColName
+		if len(def.ColNameList) > 0 {
+			colName = def.ColNameList[i]
+		}
+		names = append(names, &types.FieldName{TblName: def.Name, ColName: colName})
+	}
+	return names, nil
+}
+
+func (b *PlanBuilder) allocCTEStorageID() int {
+	id := b.cteStorageID
+	b.cteStorageID++
+	return id
+}
+
+// findCTE finds the visible CTE referred by the table name, the innermost one is preferred.
+func (b *PlanBuilder) findCTE(tn *ast.TableName) *cteInfo {
+	if tn.Schema.L != "" {
+		return nil
+	}
+	for i := len(b.outerCTEs) - 1; i >= 0; i-- {
+		if b.outerCTEs[i].def.Name.L == tn.Name.L {
+			return b.outerCTEs[i]
+		}
+	}
+	return nil
+}
+
+// buildCTERef builds a reference of a CTE.
+func (b *PlanBuilder) buildCTERef(ctx context.Context, info *cteInfo) (LogicalPlan, error) {
+	if info.isInline {
+		return b.buildInlineCTE(ctx, info)
+	}
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(info.cte.fieldTypes))...)
+	names := make([]*types.FieldName, 0, len(info.cte.fieldTypes))
+	for i, tp := range info.cte.fieldTypes {
+		schema.Append(&expression.Column{
+			RetType:  tp,
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		})
+		names = append(names, &types.FieldName{TblName: info.cte.names[i].TblName, ColName: info.cte.names[i].ColName})
+	}
+	b.handleHelper.pushMap(nil)
+	if info.isBuilding {
+		p := LogicalCTETable{cte: info.cte}.Init(b.ctx)
+		p.setSchemaAndNames(schema, names)
+		return p, nil
+	}
+	p := LogicalCTE{cte: info.cte, cteAsName: info.def.Name}.Init(b.ctx)
+	p.setSchemaAndNames(schema, names)
+	return p, nil
+}
+
+// buildInlineCTE builds the definition of a CTE at its reference in the scope defining it.
+func (b *PlanBuilder) buildInlineCTE(ctx context.Context, info *cteInfo) (LogicalPlan, error) {
+	outerSchemas, outerNames, outerCTEs := b.outerSchemas, b.outerNames, b.outerCTEs
+	// Limit the capacities so that appending to the scope won't overwrite the original one.
+	b.outerSchemas = info.outerSchemas[:len(info.outerSchemas):len(info.outerSchemas)]
+	b.outerNames = info.outerNames[:len(info.outerNames):len(info.outerNames)]
+	b.outerCTEs = info.outerCTEs[:len(info.outerCTEs):len(info.outerCTEs)]
+	defer func() {
+		b.outerSchemas, b.outerNames, b.outerCTEs = outerSchemas, outerNames, outerCTEs
+	}()
+	p, err := b.buildResultSetNode(ctx, info.def.Query.Query)
+	if err != nil {
+		return nil, err
+	}
+	names, err := b.buildCTENames(info.def, p)
+	if err != nil {
+		return nil, err
+	}
+	p.SetOutputNames(names)
+	return p, nil
+}
+
+// cteRefCounter counts the references of a CTE in an AST. The references in the statements
+// defining another CTE with the same name are not counted since they refer to the inner one.
+type cteRefCounter struct {
+	name model.CIStr
+	root ast.Node
+	// skip is the WITH clause defining the CTE, the references in it are counted separately.
+	skip ast.Node
+
+	subqueryDepth  int
+	refs           int
+	refsInSubquery int
+}
+
+func countCTERefs(node ast.Node, name model.CIStr, skip ast.Node) (refs, refsInSubquery int) {
+	counter := &cteRefCounter{name: name, root: node, skip: skip}
+	node.Accept(counter)
+	return counter.refs, counter.refsInSubquery
+}
+
+func (c *cteRefCounter) definesCTE(with *ast.WithClause) bool {
+	if with == nil {
+		return false
+	}
+	for _, cte := range with.CTEs {
+		if cte.Name.L == c.name.L {
+			return true
+		}
+	}
+	return false
+}
+
+// Enter implements Visitor interface.
+func (c *cteRefCounter) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.WithClause:
+		return in, in == c.skip
+	case *ast.SelectStmt:
+		return in, in != c.root && c.definesCTE(x.With)
+	case *ast.SetOprStmt:
+		return in, in != c.root && c.definesCTE(x.With)
+	case *ast.SubqueryExpr:
+		c.subqueryDepth++
+	case *ast.TableName:
+		if x.Schema.L == "" && x.Name.L == c.name.L {
+			c.refs++
+			if c.subqueryDepth > 0 {
+				c.refsInSubquery++
+			}
+		}
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (c *cteRefCounter) Leave(in ast.Node) (ast.Node, bool) {
+	if _, ok := in.(*ast.SubqueryExpr); ok {
+		c.subqueryDepth--
+	}
+	return in, true
+}
@@ -265,6 +265,52 @@ func (s *testPlanSuite) TestPlanBuilder(c *C) {
 	}
 }
 
+func (s *testPlanSuite) TestSubquery(c *C) {
+	defer testleak.AfterTest(c)()
+	var input, output []string
+	s.testData.GetTestCases(c, &input, &output)
+	ctx := context.Background()
+	for i, ca := range input {
+		comment := Commentf("for %s", ca)
+		stmt, err := s.ParseOneStmt(ca, "", "")
+		c.Assert(err, IsNil, comment)
+
+		Preprocess(s.ctx, stmt, s.is)
+		p, _, err := BuildLogicalPlan(ctx, s.ctx, stmt, s.is)
+		c.Assert(err, IsNil)
+		if lp, ok := p.(LogicalPlan); ok {
+			p, err = logicalOptimize(context.TODO(), flagBuildKeyInfo|flagDecorrelate|flagPrunColumns, lp)
+			c.Assert(err, IsNil)
+		}
+		s.testData.OnRecord(func() {
+			output[i] = ToString(p)
+		})
+		c.Assert(ToString(p), Equals, output[i], Commentf("for %s", ca))
+	}
+}
+
+func (s *testPlanSuite) TestSetOperator(c *C) {
+	defer testleak.AfterTest(c)()
+	var input, output []string
+	s.testData.GetTestCases(c, &input, &output)
+	ctx := context.Background()
+	for i, ca := range input {
+		comment := Commentf("for %s", ca)
+		stmt, err := s.ParseOneStmt(ca, "", "")
+		c.Assert(err, IsNil, comment)
+
+		Preprocess(s.ctx, stmt, s.is)
+		p, _, err := BuildLogicalPlan(ctx, s.ctx, stmt, s.is)
+		c.Assert(err, IsNil, comment)
+		p, err = logicalOptimize(context.TODO(), flagPredicatePushDown|flagPrunColumns|flagEliminateProjection|flagPushDownTopN, p.(LogicalPlan))
+		c.Assert(err, IsNil, comment)
+		s.testData.OnRecord(func() {
+			output[i] = ToString(p)
+		})
+		c.Assert(ToString(p), Equals, output[i], comment)
+	}
+}
+
 func (s *testPlanSuite) TestJoinReOrder(c *C) {
 	defer testleak.AfterTest(c)()
 	var input, output []string
@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/property"
@@ -43,6 +44,12 @@ var (
 	_ LogicalPlan = &LogicalIndexScan{}
 	_ LogicalPlan = &LogicalSort{}
 	_ LogicalPlan = &LogicalLimit{}
+	_ LogicalPlan = &LogicalApply{}
+	_ LogicalPlan = &LogicalMaxOneRow{}
+	_ LogicalPlan = &LogicalLock{}
+	_ LogicalPlan = &LogicalWindow{}
+	_ LogicalPlan = &LogicalCTE{}
+	_ LogicalPlan = &LogicalCTETable{}
 )
 
 // JoinType contains CrossJoin, InnerJoin, LeftOuterJoin, RightOuterJoin, FullOuterJoin, SemiJoin.
@@ -55,6 +62,14 @@ const (
 	LeftOuterJoin
 	// RightOuterJoin means right join.
 	RightOuterJoin
+	// SemiJoin means if row a in table A matches some rows in B, just output a.
+	SemiJoin
+	// AntiSemiJoin means if row a in table A does not match any row in B, then output a.
+	AntiSemiJoin
+	// LeftOuterSemiJoin means if row a in table A matches some rows in B, output (a, true), otherwise, output (a, false).
+	LeftOuterSemiJoin
+	// AntiLeftOuterSemiJoin means if row a in table A matches some rows in B, output (a, false), otherwise, output (a, true).
+	AntiLeftOuterSemiJoin
 )
 
 // IsOuterJoin returns if this joiner is a outer joiner
@@ -70,12 +85,22 @@ func (tp JoinType) String() string {
 		return "left outer join"
 	case RightOuterJoin:
 		return "right outer join"
+	case SemiJoin:
+		return "semi join"
+	case AntiSemiJoin:
+		return "anti semi join"
+	case LeftOuterSemiJoin:
+		return "left outer semi join"
+	case AntiLeftOuterSemiJoin:
+		return "anti left outer semi join"
 	}
 	return "unsupported join type"
 }
 
 const (
-	preferHashJoin uint = 1 << iota
+	preferLeftAsIndexInner uint = 1 << iota
+	preferRightAsIndexInner
+	preferHashJoin
 	preferMergeJoin
 )
 
@@ -120,11 +145,101 @@ func (p *LogicalJoin) attachOnConds(onConds []expression.Expression) {
 	p.OtherConditions = append(other, p.OtherConditions...)
 }
 
+func (p *LogicalJoin) columnSubstitute(schema *expression.Schema, exprs []expression.Expression) {
+	for i, cond := range p.LeftConditions {
+		p.LeftConditions[i] = expression.ColumnSubstitute(cond, schema, exprs)
+	}
+
+	for i, cond := range p.RightConditions {
+		p.RightConditions[i] = expression.ColumnSubstitute(cond, schema, exprs)
+	}
+
+	for i, cond := range p.OtherConditions {
+		p.OtherConditions[i] = expression.ColumnSubstitute(cond, schema, exprs)
+	}
+
+	for i := len(p.EqualConditions) - 1; i >= 0; i-- {
+		newCond := expression.ColumnSubstitute(p.EqualConditions[i], schema, exprs).(*expression.ScalarFunction)
+
+		// If the columns used in the new filter all come from the left child,
+		// we can push this filter to it.
+		if expression.ExprFromSchema(newCond, p.children[0].Schema()) {
+			p.LeftConditions = append(p.LeftConditions, newCond)
+			p.EqualConditions = append(p.EqualConditions[:i], p.EqualConditions[i+1:]...)
+			continue
+		}
+
+		// If the columns used in the new filter all come from the right
+		// child, we can push this filter to it.
+		if expression.ExprFromSchema(newCond, p.children[1].Schema()) {
+			p.RightConditions = append(p.RightConditions, newCond)
+			p.EqualConditions = append(p.EqualConditions[:i], p.EqualConditions[i+1:]...)
+			continue
+		}
+
+		_, lhsIsCol := newCond.GetArgs()[0].(*expression.Column)
+		_, rhsIsCol := newCond.GetArgs()[1].(*expression.Column)
+
+		// If the columns used in the new filter are not all expression.Column,
+		// we can not use it as join's equal condition.
+		if !(lhsIsCol && rhsIsCol) {
+			p.OtherConditions = append(p.OtherConditions, newCond)
+			p.EqualConditions = append(p.EqualConditions[:i], p.EqualConditions[i+1:]...)
+			continue
+		}
+
+		p.EqualConditions[i] = newCond
+	}
+}
+
+// getNullEQKeys returns whether each equal condition is a null-safe equal condition,
+// and whether there is any of them.
+func (p *LogicalJoin) getNullEQKeys() (isNullEQ []bool, hasNullEQ bool) {
+	for _, expr := range p.EqualConditions {
+		nullEQ := expr.FuncName.L == ast.NullEQ
+		isNullEQ = append(isNullEQ, nullEQ)
+		hasNullEQ = hasNullEQ || nullEQ
+	}
+	return
+}
+
+func (p *LogicalJoin) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, fun := range p.EqualConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.LeftConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.RightConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.OtherConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	return corCols
+}
+
 // LogicalProjection represents a select fields plan.
 type LogicalProjection struct {
 	logicalSchemaProducer
 
 	Exprs []expression.Expression
+
+	// AvoidColumnEvaluator is a temporary variable which is ONLY used to avoid
+	// building columnEvaluator for the expressions of Projection which is
+	// built by buildProjection4Union.
+	// This can be removed after column pool being supported.
+	// Related issue: TiDB#8141(https://github.com/pingcap/tidb/issues/8141)
+	AvoidColumnEvaluator bool
+}
+
+func (p *LogicalProjection) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, expr := range p.Exprs {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	return corCols
 }
 
 // LogicalAggregation represents an aggregate plan.
@@ -156,6 +271,19 @@ func (la *LogicalAggregation) GetGroupByCols() []*expression.Column {
 	return la.groupByCols
 }
 
+func (la *LogicalAggregation) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := la.baseLogicalPlan.extractCorrelatedCols()
+	for _, expr := range la.GroupByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	for _, fun := range la.AggFuncs {
+		for _, arg := range fun.Args {
+			corCols = append(corCols, expression.ExtractCorColumns(arg)...)
+		}
+	}
+	return corCols
+}
+
 // LogicalSelection represents a where or having predicate.
 type LogicalSelection struct {
 	baseLogicalPlan
@@ -166,6 +294,44 @@ type LogicalSelection struct {
 	Conditions []expression.Expression
 }
 
+func (p *LogicalSelection) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, cond := range p.Conditions {
+		corCols = append(corCols, expression.ExtractCorColumns(cond)...)
+	}
+	return corCols
+}
+
+// LogicalApply gets one row from outer executor and gets one row from inner executor according to outer row.
+type LogicalApply struct {
+	LogicalJoin
+
+	CorCols []*expression.CorrelatedColumn
+}
+
+func (la *LogicalApply) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := la.LogicalJoin.extractCorrelatedCols()
+	for i := len(corCols) - 1; i >= 0; i-- {
+		if la.children[0].Schema().Contains(&corCols[i].Column) {
+			corCols = append(corCols[:i], corCols[i+1:]...)
+		}
+	}
+	return corCols
+}
+
+// LogicalMaxOneRow checks if a query returns no more than one row.
+type LogicalMaxOneRow struct {
+	baseLogicalPlan
+}
+
+// LogicalLock represents a select lock plan.
+type LogicalLock struct {
+	baseLogicalPlan
+
+	Lock         ast.SelectLockType
+	tblID2Handle map[int64][]*expression.Column
+}
+
 // LogicalTableDual represents a dual table plan.
 type LogicalTableDual struct {
 	logicalSchemaProducer
@@ -222,6 +388,19 @@ type DataSource struct {
 	// TblColHists contains the Histogram of all original table columns,
 	// it is converted from statisticTable, and used for IO/network cost estimating.
 	TblColHists *statistics.HistColl
+
+	// isPartition indicates the DataSource reads a partition of a partitioned table.
+	isPartition bool
+	// physicalTableID is the ID of the partition when isPartition is true.
+	physicalTableID int64
+}
+
+func (ds *DataSource) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(ds.pushedDownConds))
+	for _, expr := range ds.pushedDownConds {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	return corCols
 }
 
 // TiKVSingleGather is a leaf logical operator of TiDB layer to gather
@@ -544,6 +723,11 @@ func (ds *DataSource) TableInfo() *model.TableInfo {
 	return ds.tableInfo
 }
 
+// LogicalUnionAll represents LogicalUnionAll plan.
+type LogicalUnionAll struct {
+	logicalSchemaProducer
+}
+
 // LogicalSort stands for the order by plan.
 type LogicalSort struct {
 	baseLogicalPlan
@@ -551,6 +735,14 @@ type LogicalSort struct {
 	ByItems []*ByItems
 }
 
+func (ls *LogicalSort) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := ls.baseLogicalPlan.extractCorrelatedCols()
+	for _, item := range ls.ByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(item.Expr)...)
+	}
+	return corCols
+}
+
 // LogicalTopN represents a top-n plan.
 type LogicalTopN struct {
 	baseLogicalPlan
@@ -560,6 +752,14 @@ type LogicalTopN struct {
 	Count   uint64
 }
 
+func (lt *LogicalTopN) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := lt.baseLogicalPlan.extractCorrelatedCols()
+	for _, item := range lt.ByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(item.Expr)...)
+	}
+	return corCols
+}
+
 // isLimit checks if TopN is a limit plan.
 func (lt *LogicalTopN) isLimit() bool {
 	return len(lt.ByItems) == 0
@@ -573,6 +773,104 @@ type LogicalLimit struct {
 	Count  uint64
 }
 
+// FrameBound is the boundary of a frame.
+type FrameBound struct {
+	Type      ast.BoundType
+	UnBounded bool
+	Num       uint64
+	// CalcFuncs is used for range framed windows.
+	// We will build the date_add or date_sub functions for frames like `INTERVAL '2:30' MINUTE_SECOND FOLLOWING`,
+	// and plus or minus for frames like `1 preceding`.
+	CalcFuncs []expression.Expression
+	// CmpFuncs is used to decide whether one row is included in the current frame.
+	CmpFuncs []expression.CompareFunc
+}
+
+// WindowFrame represents a window function frame.
+type WindowFrame struct {
+	Type  ast.FrameType
+	Start *FrameBound
+	End   *FrameBound
+}
+
+// LogicalWindow represents a logical window function plan.
+type LogicalWindow struct {
+	logicalSchemaProducer
+
+	WindowFuncDescs []*aggregation.WindowFuncDesc
+	PartitionBy     []property.Item
+	OrderBy         []property.Item
+	Frame           *WindowFrame
+}
+
+func (p *LogicalWindow) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, windowFunc := range p.WindowFuncDescs {
+		for _, arg := range windowFunc.Args {
+			corCols = append(corCols, expression.ExtractCorColumns(arg)...)
+		}
+	}
+	if p.Frame != nil {
+		if p.Frame.Start != nil {
+			for _, expr := range p.Frame.Start.CalcFuncs {
+				corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+			}
+		}
+		if p.Frame.End != nil {
+			for _, expr := range p.Frame.End.CalcFuncs {
+				corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+			}
+		}
+	}
+	return corCols
+}
+
+// GetWindowResultColumns returns the columns storing the result of the window function.
+func (p *LogicalWindow) GetWindowResultColumns() []*expression.Column {
+	return p.schema.Columns[p.schema.Len()-len(p.WindowFuncDescs):]
+}
+
+// GetPartitionByCols extracts 'partition by' columns from the Window.
+func (p *LogicalWindow) GetPartitionByCols() []*expression.Column {
+	partitionCols := make([]*expression.Column, 0, len(p.PartitionBy))
+	for _, partitionItem := range p.PartitionBy {
+		partitionCols = append(partitionCols, partitionItem.Col)
+	}
+	return partitionCols
+}
+
+// CTEClass holds the information of a materialized common table expression. All the
+// references of the CTE in a statement share one CTEClass, so the CTE is evaluated only once.
+type CTEClass struct {
+	// IDForStorage is the identifier of the storage holding the result of the CTE.
+	IDForStorage int
+	// IsDistinct indicates whether the recursive part is united by UNION DISTINCT.
+	IsDistinct bool
+
+	seedPartPhysicalPlan      PhysicalPlan
+	recursivePartPhysicalPlan PhysicalPlan
+
+	fieldTypes []*types.FieldType
+	names      []*types.FieldName
+	seedStat   *property.StatsInfo
+}
+
+// LogicalCTE is the logical plan of reading a materialized common table expression.
+type LogicalCTE struct {
+	logicalSchemaProducer
+
+	cte       *CTEClass
+	cteAsName model.CIStr
+}
+
+// LogicalCTETable is the logical plan of reading the working table of a recursive common
+// table expression. It only appears in the recursive part of the CTE.
+type LogicalCTETable struct {
+	logicalSchemaProducer
+
+	cte *CTEClass
+}
+
 // ShowContents stores the contents for the `SHOW` statement.
 type ShowContents struct {
 	Tp          ast.ShowStmtType // Databases/Tables/Columns/....
@@ -582,7 +880,8 @@ type ShowContents struct {
 	IndexName   model.CIStr
 	Flag        int // Some flag parsed from sql, such as FULL.
 	Full        bool
-	IfNotExists bool // Used for `show create database if not exists`.
+	User        *auth.UserIdentity // Used for show grants.
+	IfNotExists bool               // Used for `show create database if not exists`.
 
 	GlobalScope bool // Used by show variables.
 }
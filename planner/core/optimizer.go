@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/planner/property"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 )
@@ -31,11 +32,13 @@ var OptimizeAstNode func(ctx context.Context, sctx sessionctx.Context, node ast.
 const (
 	flagPrunColumns uint64 = 1 << iota
 	flagBuildKeyInfo
+	flagDecorrelate
 	flagEliminateAgg
 	flagEliminateProjection
 	flagMaxMinEliminate
 	flagPredicatePushDown
 	flagEliminateOuterJoin
+	flagPartitionProcessor
 	flagPushDownAgg
 	flagPushDownTopN
 	flagJoinReOrder
@@ -44,11 +47,13 @@ const (
 var optRuleList = []logicalOptRule{
 	&columnPruner{},
 	&buildKeySolver{},
+	&decorrelateSolver{},
 	&aggregationEliminator{},
 	&projectionEliminator{},
 	&maxMinEliminator{},
 	&ppdSolver{},
 	&outerJoinEliminator{},
+	&partitionProcessor{},
 	&aggregationPushDownSolver{},
 	&pushDownTopNOptimizer{},
 	&joinReOrderSolver{},
@@ -72,6 +77,19 @@ func BuildLogicalPlan(ctx context.Context, sctx sessionctx.Context, node ast.Nod
 	return p, p.OutputNames(), err
 }
 
+// CheckPrivilege checks the privileges recorded in vs for the user of the privilege manager.
+func CheckPrivilege(pm privilege.Manager, vs []visitInfo) error {
+	for _, v := range vs {
+		if !pm.RequestVerification(v.db, v.table, v.column, v.privilege) {
+			if v.err == nil {
+				return ErrPrivilegeCheckFail
+			}
+			return v.err
+		}
+	}
+	return nil
+}
+
 // DoOptimize optimizes a logical plan to a physical plan.
 func DoOptimize(ctx context.Context, flag uint64, logic LogicalPlan) (PhysicalPlan, error) {
 	logic, err := logicalOptimize(ctx, flag, logic)
@@ -135,4 +153,5 @@ func physicalOptimize(logic LogicalPlan) (PhysicalPlan, error) {
 
 func init() {
 	expression.EvalAstExpr = evalAstExpr
+	expression.RewriteAstExpr = rewriteAstExpr
 }
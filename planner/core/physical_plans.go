@@ -16,6 +16,7 @@ package core
 import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -37,9 +38,17 @@ var (
 	_ PhysicalPlan = &PhysicalIndexReader{}
 	_ PhysicalPlan = &PhysicalIndexLookUpReader{}
 	_ PhysicalPlan = &PhysicalHashAgg{}
+	_ PhysicalPlan = &PhysicalStreamAgg{}
 	_ PhysicalPlan = &PhysicalHashJoin{}
+	_ PhysicalPlan = &PhysicalIndexJoin{}
 	_ PhysicalPlan = &PhysicalMergeJoin{}
 	_ PhysicalPlan = &PhysicalUnionScan{}
+	_ PhysicalPlan = &PhysicalApply{}
+	_ PhysicalPlan = &PhysicalMaxOneRow{}
+	_ PhysicalPlan = &PhysicalLock{}
+	_ PhysicalPlan = &PhysicalWindow{}
+	_ PhysicalPlan = &PhysicalCTE{}
+	_ PhysicalPlan = &PhysicalCTETable{}
 )
 
 // PhysicalTableReader is the table reader in tidb.
@@ -92,7 +101,7 @@ func (p *PhysicalIndexReader) SetSchema(_ *expression.Schema) {
 	if p.indexPlan != nil {
 		p.IndexPlans = flattenPushDownPlan(p.indexPlan)
 		switch p.indexPlan.(type) {
-		case *PhysicalHashAgg:
+		case *PhysicalHashAgg, *PhysicalStreamAgg:
 			p.schema = p.indexPlan.Schema()
 		default:
 			is := p.IndexPlans[0].(*PhysicalIndexScan)
@@ -148,6 +157,14 @@ type PhysicalIndexScan struct {
 	// DoubleRead means if the index executor will read kv two times.
 	// If the query requires the columns that don't belong to index, DoubleRead will be true.
 	DoubleRead bool
+
+	isPartition     bool
+	physicalTableID int64
+}
+
+// IsPartition returns true and partition ID if it works on a partition.
+func (p *PhysicalIndexScan) IsPartition() (bool, int64) {
+	return p.isPartition, p.physicalTableID
 }
 
 // PhysicalMemTable reads memory table.
@@ -180,13 +197,22 @@ type PhysicalTableScan struct {
 	// KeepOrder is true, if sort data by scanning pkcol,
 	KeepOrder bool
 	Desc      bool
+
+	isPartition     bool
+	physicalTableID int64
+}
+
+// IsPartition returns true and partition ID if it's actually a partition.
+func (ts *PhysicalTableScan) IsPartition() (bool, int64) {
+	return ts.isPartition, ts.physicalTableID
 }
 
 // PhysicalProjection is the physical operator of projection.
 type PhysicalProjection struct {
 	physicalSchemaProducer
 
-	Exprs []expression.Expression
+	Exprs                []expression.Expression
+	AvoidColumnEvaluator bool
 }
 
 // PhysicalTopN is the physical operator of topN.
@@ -212,6 +238,9 @@ type basePhysicalJoin struct {
 	InnerJoinKeys []*expression.Column
 	LeftJoinKeys  []*expression.Column
 	RightJoinKeys []*expression.Column
+	// IsNullEQ is used for cases like Except statement where null key should be matched with null key.
+	// <1,null> is exactly matched with <1,null>, the null value should not be filtered.
+	IsNullEQ      []bool
 	DefaultValues []types.Datum
 }
 
@@ -225,12 +254,14 @@ type PhysicalHashJoin struct {
 
 // NewPhysicalHashJoin creates a new PhysicalHashJoin from LogicalJoin.
 func NewPhysicalHashJoin(p *LogicalJoin, innerIdx int, newStats *property.StatsInfo, prop ...*property.PhysicalProperty) *PhysicalHashJoin {
+	isNullEQ, _ := p.getNullEQKeys()
 	baseJoin := basePhysicalJoin{
 		LeftConditions:  p.LeftConditions,
 		RightConditions: p.RightConditions,
 		OtherConditions: p.OtherConditions,
 		LeftJoinKeys:    p.LeftJoinKeys,
 		RightJoinKeys:   p.RightJoinKeys,
+		IsNullEQ:        isNullEQ,
 		JoinType:        p.JoinType,
 		DefaultValues:   p.DefaultValues,
 		InnerChildIdx:   innerIdx,
@@ -243,6 +274,19 @@ func NewPhysicalHashJoin(p *LogicalJoin, innerIdx int, newStats *property.StatsI
 	return hashJoin
 }
 
+// PhysicalIndexJoin represents the plan of index look up join.
+type PhysicalIndexJoin struct {
+	basePhysicalJoin
+
+	innerTask task
+
+	// Ranges stores the IndexRanges when the inner plan is index scan. The
+	// positions of the join keys are filled with the outer values when executing.
+	Ranges []*ranger.Range
+	// KeyOff2IdxOff maps the offsets in join key to the offsets in the index.
+	KeyOff2IdxOff []int
+}
+
 // PhysicalMergeJoin represents merge join implementation of LogicalJoin.
 type PhysicalMergeJoin struct {
 	basePhysicalJoin
@@ -250,6 +294,27 @@ type PhysicalMergeJoin struct {
 	CompareFuncs []expression.CompareFunc
 }
 
+// PhysicalApply represents apply plan, only used for subquery.
+type PhysicalApply struct {
+	PhysicalHashJoin
+
+	OuterSchema []*expression.CorrelatedColumn
+}
+
+// PhysicalMaxOneRow is the physical operator of maxOneRow.
+type PhysicalMaxOneRow struct {
+	basePhysicalPlan
+}
+
+// PhysicalLock is the physical operator of lock, which is used for `select ... for update` clause.
+type PhysicalLock struct {
+	basePhysicalPlan
+
+	Lock ast.SelectLockType
+
+	TblID2Handle map[int64][]*expression.Column
+}
+
 // PhysicalLimit is the physical operator of Limit.
 type PhysicalLimit struct {
 	basePhysicalPlan
@@ -258,6 +323,11 @@ type PhysicalLimit struct {
 	Count  uint64
 }
 
+// PhysicalUnionAll is the physical operator of UnionAll.
+type PhysicalUnionAll struct {
+	physicalSchemaProducer
+}
+
 type basePhysicalAgg struct {
 	physicalSchemaProducer
 
@@ -294,6 +364,13 @@ func NewPhysicalHashAgg(la *LogicalAggregation, newStats *property.StatsInfo, pr
 	return agg
 }
 
+// PhysicalStreamAgg is stream operator of aggregate. It requires its child
+// to be ordered by the group-by items, so every group is output once its
+// last row is read.
+type PhysicalStreamAgg struct {
+	basePhysicalAgg
+}
+
 // PhysicalSort is the physical operator of sort, which implements a memory sort.
 type PhysicalSort struct {
 	basePhysicalPlan
@@ -352,6 +429,34 @@ func (p *PhysicalTableDual) SetOutputNames(names types.NameSlice) {
 	p.names = names
 }
 
+// PhysicalWindow is the physical operator of window function.
+type PhysicalWindow struct {
+	physicalSchemaProducer
+
+	WindowFuncDescs []*aggregation.WindowFuncDesc
+	PartitionBy     []property.Item
+	OrderBy         []property.Item
+	Frame           *WindowFrame
+}
+
+// PhysicalCTE is the physical operator of reading a materialized common table expression.
+type PhysicalCTE struct {
+	physicalSchemaProducer
+
+	SeedPlan  PhysicalPlan
+	RecurPlan PhysicalPlan
+	CTE       *CTEClass
+	cteAsName model.CIStr
+}
+
+// PhysicalCTETable is the physical operator of reading the working table of a recursive
+// common table expression.
+type PhysicalCTETable struct {
+	physicalSchemaProducer
+
+	IDForStorage int
+}
+
 // PhysicalShow represents a show plan.
 type PhysicalShow struct {
 	physicalSchemaProducer
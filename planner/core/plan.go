@@ -116,6 +116,12 @@ type LogicalPlan interface {
 	// exhaustPhysicalPlans generates all possible plans that can match the required property.
 	exhaustPhysicalPlans(*property.PhysicalProperty) []PhysicalPlan
 
+	// extractCorrelatedCols extracts correlated columns inside the LogicalPlan.
+	extractCorrelatedCols() []*expression.CorrelatedColumn
+
+	// MaxOneRow means whether this operator only returns max one row.
+	MaxOneRow() bool
+
 	// Get all the children.
 	Children() []LogicalPlan
 
@@ -165,9 +171,15 @@ type PhysicalPlan interface {
 type baseLogicalPlan struct {
 	basePlan
 
-	taskMap  map[string]task
-	self     LogicalPlan
-	children []LogicalPlan
+	taskMap   map[string]task
+	self      LogicalPlan
+	maxOneRow bool
+	children  []LogicalPlan
+}
+
+// MaxOneRow implements the LogicalPlan interface.
+func (p *baseLogicalPlan) MaxOneRow() bool {
+	return p.maxOneRow
 }
 
 // ExplainInfo implements Plan interface.
@@ -209,11 +221,25 @@ func (p *baseLogicalPlan) storeTask(prop *property.PhysicalProperty, task task)
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *baseLogicalPlan) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	childMaxOneRow := make([]bool, len(p.children))
+	for i := range p.children {
+		childMaxOneRow[i] = p.children[i].MaxOneRow()
+	}
+	p.maxOneRow = HasMaxOneRow(p.self, childMaxOneRow)
 }
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *logicalSchemaProducer) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	selfSchema.Keys = nil
+	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
+}
+
+func (p *baseLogicalPlan) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(p.children))
+	for _, child := range p.children {
+		corCols = append(corCols, child.extractCorrelatedCols()...)
+	}
+	return corCols
 }
 
 func newBasePlan(ctx sessionctx.Context, tp string) basePlan {
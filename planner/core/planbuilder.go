@@ -35,9 +35,10 @@ import (
 )
 
 type tableHintInfo struct {
-	sortMergeJoinTables []hintTableInfo
-	hashJoinTables      []hintTableInfo
-	indexHintList       []indexHintInfo
+	indexNestedLoopJoinTables []hintTableInfo
+	sortMergeJoinTables       []hintTableInfo
+	hashJoinTables            []hintTableInfo
+	indexHintList             []indexHintInfo
 }
 
 type hintTableInfo struct {
@@ -68,6 +69,10 @@ func tableNames2HintTableInfo(ctx sessionctx.Context, hintTables []ast.HintTable
 	return hintTableInfos
 }
 
+func (info *tableHintInfo) ifPreferINLJ(tableNames ...*hintTableInfo) bool {
+	return info.matchTableName(tableNames, info.indexNestedLoopJoinTables)
+}
+
 func (info *tableHintInfo) ifPreferMergeJoin(tableNames ...*hintTableInfo) bool {
 	return info.matchTableName(tableNames, info.sortMergeJoinTables)
 }
@@ -152,13 +157,32 @@ var clauseMsg = map[clauseCode]string{
 	globalOrderByClause: "global ORDER clause",
 }
 
+// visitInfo records a privilege the statement needs, it is checked before the plan is optimized.
+type visitInfo struct {
+	privilege mysql.PrivilegeType
+	db        string
+	table     string
+	column    string
+	err       error
+}
+
 // PlanBuilder builds Plan from an ast.Node.
 // It just builds the ast node straightforwardly.
 type PlanBuilder struct {
-	ctx sessionctx.Context
-	is  infoschema.InfoSchema
+	ctx          sessionctx.Context
+	is           infoschema.InfoSchema
+	outerSchemas []*expression.Schema
+	outerNames   [][]*types.FieldName
+	// outerCTEs stores the common table expressions visible to the current query block.
+	outerCTEs []*cteInfo
+	// cteStorageID allocates the identifiers of the storages holding the results of CTEs.
+	cteStorageID int
+	// buildingViews are the views being expanded, it is used to detect the view recursion.
+	buildingViews map[string]struct{}
 	// colMapper stores the column that must be pre-resolved.
 	colMapper map[*ast.ColumnNameExpr]int
+	// windowMapper stores the index of the window function result column in the schema.
+	windowMapper map[*ast.WindowFuncExpr]int
 
 	tableHintInfo []tableHintInfo
 	// optFlag indicates the flags of the optimizer rules.
@@ -184,6 +208,9 @@ type PlanBuilder struct {
 	//   If it's a join, we pop its children's out then merge them and push the new map to stack.
 	//   If we meet a subquery, it's clearly that it's a independent problem so we just pop one map out when we finish building the subquery.
 	handleHelper *handleColHelper
+
+	// visitInfo is used for privilege check.
+	visitInfo []visitInfo
 }
 
 type handleColHelper struct {
@@ -229,12 +256,18 @@ func (b *PlanBuilder) GetOptFlag() uint64 {
 	return b.optFlag
 }
 
+// GetVisitInfo gets the visitInfo of the PlanBuilder.
+func (b *PlanBuilder) GetVisitInfo() []visitInfo {
+	return b.visitInfo
+}
+
 // NewPlanBuilder creates a new PlanBuilder.
 func NewPlanBuilder(sctx sessionctx.Context, is infoschema.InfoSchema) *PlanBuilder {
 	return &PlanBuilder{
 		ctx:          sctx,
 		is:           is,
 		colMapper:    make(map[*ast.ColumnNameExpr]int),
+		windowMapper: make(map[*ast.WindowFuncExpr]int),
 		handleHelper: &handleColHelper{id2HandleMapStack: make([]map[int64][]*expression.Column, 0)},
 	}
 }
@@ -245,21 +278,32 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 	switch x := node.(type) {
 	case *ast.AdminStmt:
 		return b.buildAdmin(ctx, x)
+	case *ast.DeallocateStmt:
+		return &Deallocate{Name: x.Name}, nil
 	case *ast.DeleteStmt:
 		return b.buildDelete(ctx, x)
+	case *ast.ExecuteStmt:
+		return b.buildExecute(ctx, x)
 	case *ast.ExplainStmt:
 		return b.buildExplain(ctx, x)
 	case *ast.InsertStmt:
 		return b.buildInsert(ctx, x)
+	case *ast.PrepareStmt:
+		return b.buildPrepare(x), nil
 	case *ast.SelectStmt:
 		return b.buildSelect(ctx, x)
+	case *ast.SetOprStmt:
+		return b.buildSetOpr(ctx, x)
 	case *ast.ShowStmt:
 		return b.buildShow(ctx, x)
+	case *ast.UpdateStmt:
+		return b.buildUpdate(ctx, x)
 	case *ast.SetStmt:
 		return b.buildSet(ctx, x)
 	case *ast.AnalyzeTableStmt:
 		return b.buildAnalyze(x)
-	case *ast.UseStmt, *ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt:
+	case *ast.UseStmt, *ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.CreateUserStmt, *ast.DropUserStmt,
+		*ast.GrantStmt, *ast.RevokeStmt, *ast.KillStmt:
 		return b.buildSimple(node.(ast.StmtNode))
 	case ast.DDLNode:
 		return b.buildDDL(ctx, x)
@@ -289,11 +333,51 @@ func (b *PlanBuilder) buildSet(ctx context.Context, v *ast.SetStmt) (Plan, error
 		} else {
 			assign.IsDefault = true
 		}
+		// Changing a global system variable affects all the sessions.
+		if vars.IsGlobal && vars.IsSystem {
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
+		}
 		p.VarAssigns = append(p.VarAssigns, assign)
 	}
 	return p, nil
 }
 
+func (b *PlanBuilder) buildExecute(ctx context.Context, v *ast.ExecuteStmt) (Plan, error) {
+	vars := make([]expression.Expression, 0, len(v.UsingVars))
+	for _, expr := range v.UsingVars {
+		newExpr, _, err := b.rewrite(ctx, expr, nil, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, newExpr)
+	}
+	exe := &Execute{Name: v.Name, UsingVars: vars, ExecID: v.ExecID}
+	if v.BinaryArgs != nil {
+		exe.PrepareParams = v.BinaryArgs.([]types.Datum)
+	}
+	return exe, nil
+}
+
+func (b *PlanBuilder) buildPrepare(x *ast.PrepareStmt) Plan {
+	p := &Prepare{
+		Name: x.Name,
+	}
+	if x.SQLVar != nil {
+		sessionVars := b.ctx.GetSessionVars()
+		sessionVars.UsersLock.RLock()
+		v, ok := sessionVars.Users[strings.ToLower(x.SQLVar.Name)]
+		sessionVars.UsersLock.RUnlock()
+		if ok {
+			p.SQLText = v
+		} else {
+			p.SQLText = "NULL"
+		}
+	} else {
+		p.SQLText = x.SQLText
+	}
+	return p
+}
+
 // detectSelectAgg detects an aggregate function or GROUP BY clause.
 func (b *PlanBuilder) detectSelectAgg(sel *ast.SelectStmt) bool {
 	if sel.GroupBy != nil {
@@ -319,6 +403,15 @@ func (b *PlanBuilder) detectSelectAgg(sel *ast.SelectStmt) bool {
 	return false
 }
 
+func (b *PlanBuilder) detectSelectWindow(sel *ast.SelectStmt) bool {
+	for _, f := range sel.Fields.Fields {
+		if ast.HasWindowFlag(f.Expr) {
+			return true
+		}
+	}
+	return false
+}
+
 func getPathByIndexName(paths []*util.AccessPath, idxName model.CIStr, tblInfo *model.TableInfo) *util.AccessPath {
 	var tablePath *util.AccessPath
 	for _, path := range paths {
@@ -456,6 +549,9 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 	default:
 		return nil, ErrUnsupportedType.GenWithStack("Unsupported ast.AdminStmt(%T) for buildAdmin", as)
 	}
+
+	// Admin command can only be executed by administrator.
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
 	return ret, nil
 }
 
@@ -479,29 +575,54 @@ func getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []
 
 func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (Plan, error) {
 	p := &Analyze{}
+	user := b.ctx.GetSessionVars().User
 	for _, tbl := range as.TableNames {
+		// Analyze reads the table and writes the stats of it.
+		var selectErr, insertErr error
+		if user != nil {
+			selectErr = ErrTableaccessDenied.GenWithStackByArgs("SELECT", user.AuthUsername, user.AuthHostname, tbl.Name.O)
+			insertErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, tbl.Name.O)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, tbl.Schema.L, tbl.Name.L, "", selectErr)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tbl.Schema.L, tbl.Name.L, "", insertErr)
 		idxInfo, colInfo, pkInfo := getColsInfo(tbl)
-		for _, idx := range idxInfo {
-			info := analyzeInfo{DBName: tbl.Schema.O, TableName: tbl.Name.O, PhysicalTableID: tbl.TableInfo.ID}
-			p.IdxTasks = append(p.IdxTasks, AnalyzeIndexTask{
-				IndexInfo:   idx,
-				analyzeInfo: info,
-				TblInfo:     tbl.TableInfo,
-			})
-		}
-		if len(colInfo) > 0 || pkInfo != nil {
-			info := analyzeInfo{DBName: tbl.Schema.O, TableName: tbl.Name.O, PhysicalTableID: tbl.TableInfo.ID}
-			p.ColTasks = append(p.ColTasks, AnalyzeColumnsTask{
-				PKInfo:      pkInfo,
-				ColsInfo:    colInfo,
-				analyzeInfo: info,
-				TblInfo:     tbl.TableInfo,
-			})
+		for _, physicalID := range getPhysicalIDs(tbl.TableInfo) {
+			for _, idx := range idxInfo {
+				info := analyzeInfo{DBName: tbl.Schema.O, TableName: tbl.Name.O, PhysicalTableID: physicalID}
+				p.IdxTasks = append(p.IdxTasks, AnalyzeIndexTask{
+					IndexInfo:   idx,
+					analyzeInfo: info,
+					TblInfo:     tbl.TableInfo,
+				})
+			}
+			if len(colInfo) > 0 || pkInfo != nil {
+				info := analyzeInfo{DBName: tbl.Schema.O, TableName: tbl.Name.O, PhysicalTableID: physicalID}
+				p.ColTasks = append(p.ColTasks, AnalyzeColumnsTask{
+					PKInfo:      pkInfo,
+					ColsInfo:    colInfo,
+					analyzeInfo: info,
+					TblInfo:     tbl.TableInfo,
+				})
+			}
 		}
 	}
 	return p, nil
 }
 
+// getPhysicalIDs returns the IDs of the partitions of a partitioned table, the stats are
+// collected for every partition. It returns the table ID if the table is not partitioned.
+func getPhysicalIDs(tblInfo *model.TableInfo) []int64 {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		return []int64{tblInfo.ID}
+	}
+	ids := make([]int64, 0, len(pi.Definitions))
+	for _, def := range pi.Definitions {
+		ids = append(ids, def.ID)
+	}
+	return ids
+}
+
 func buildShowDDLFields() (*expression.Schema, types.NameSlice) {
 	schema := newColumnsWithNames(6)
 	schema.Append(buildColumnWithName("", "SCHEMA_VER", mysql.TypeLonglong, 4))
@@ -602,6 +723,7 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 			IndexName:   show.IndexName,
 			Flag:        show.Flag,
 			Full:        show.Full,
+			User:        show.User,
 			IfNotExists: show.IfNotExists,
 			GlobalScope: show.GlobalScope,
 		},
@@ -611,6 +733,19 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 		if p.DBName == "" {
 			return nil, ErrNoDB
 		}
+	case ast.ShowCreateTable, ast.ShowCreateView:
+		// Any privilege on the table is enough to show it.
+		var authErr error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("SHOW", user.AuthUsername, user.AuthHostname, show.Table.Name.O)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.AllPrivMask, show.Table.Schema.L, show.Table.Name.L, "", authErr)
+	case ast.ShowGrants:
+		// Showing the grants of other users needs to read the privilege tables.
+		if user := b.ctx.GetSessionVars().User; user != nil && show.User != nil && !show.User.CurrentUser &&
+			(user.AuthUsername != show.User.Username || user.AuthHostname != show.User.Hostname) {
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, mysql.SystemDB, "", "", nil)
+		}
 	}
 	schema, names := buildShowSchema(show)
 	p.SetSchema(schema)
@@ -621,6 +756,16 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 	var err error
 	var np LogicalPlan
 	np = p
+	if show.Pattern != nil {
+		// `SHOW ... LIKE pattern` filters the rows by the first column of the result.
+		show.Pattern.Expr = &ast.ColumnNameExpr{
+			Name: &ast.ColumnName{Name: p.OutputNames()[0].ColName},
+		}
+		np, err = b.buildSelection(ctx, np, show.Pattern, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if show.Where != nil {
 		np, err = b.buildSelection(ctx, np, show.Where, nil)
 		if err != nil {
@@ -654,10 +799,61 @@ func (b *PlanBuilder) buildSimple(node ast.StmtNode) (Plan, error) {
 		if raw.DBName == "" {
 			return nil, ErrNoDB
 		}
+	case *ast.CreateUserStmt, *ast.DropUserStmt:
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreateUserPriv, "", "", "", nil)
+	case *ast.GrantStmt:
+		b.visitInfo = collectVisitInfoFromGrantStmt(b.ctx, b.visitInfo, raw.Level, raw.Privs)
+	case *ast.RevokeStmt:
+		b.visitInfo = collectVisitInfoFromGrantStmt(b.ctx, b.visitInfo, raw.Level, raw.Privs)
+	case *ast.KillStmt:
+		// If you have the SUPER privilege, you can kill all threads and statements.
+		// Otherwise, you can kill only your own threads and statements.
+		sm := b.ctx.GetSessionManager()
+		if sm != nil {
+			if pi, ok := sm.GetProcessInfo(raw.ConnectionID); ok {
+				loginUser := b.ctx.GetSessionVars().User
+				if loginUser == nil || pi.User != loginUser.Username {
+					b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
+				}
+			}
+		}
 	}
 	return p, nil
 }
 
+// collectVisitInfoFromGrantStmt collects the privileges needed by GRANT and REVOKE: the
+// user must hold GRANT OPTION and every privilege it grants or revokes at the same level.
+func collectVisitInfoFromGrantStmt(sctx sessionctx.Context, vi []visitInfo, level *ast.GrantLevel, privs []*ast.PrivElem) []visitInfo {
+	var dbName, tableName string
+	if level.Level != ast.GrantLevelGlobal {
+		dbName, tableName = strings.ToLower(level.DBName), strings.ToLower(level.TableName)
+		if dbName == "" {
+			dbName = strings.ToLower(sctx.GetSessionVars().CurrentDB)
+		}
+	}
+	vi = appendVisitInfo(vi, mysql.GrantPriv, dbName, tableName, "", nil)
+
+	for _, item := range privs {
+		if item.Priv != mysql.AllPriv {
+			vi = appendVisitInfo(vi, item.Priv, dbName, tableName, "", nil)
+			continue
+		}
+		var allPrivs []mysql.PrivilegeType
+		switch level.Level {
+		case ast.GrantLevelGlobal:
+			allPrivs = mysql.AllGlobalPrivs
+		case ast.GrantLevelDB:
+			allPrivs = mysql.AllDBPrivs
+		case ast.GrantLevelTable:
+			allPrivs = mysql.AllTablePrivs
+		}
+		for _, priv := range allPrivs {
+			vi = appendVisitInfo(vi, priv, dbName, tableName, "", nil)
+		}
+	}
+	return vi
+}
+
 func (b *PlanBuilder) getDefaultValue(col *table.Column) (*expression.Constant, error) {
 	value, err := table.GetColDefaultValue(b.ctx, col.ToInfo())
 	if err != nil {
@@ -685,6 +881,21 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		return nil, infoschema.ErrTableNotExists.GenWithStackByArgs()
 	}
 	tableInfo := tn.TableInfo
+	if tableInfo.IsView() {
+		return nil, ErrNonUpdatableTable.GenWithStackByArgs(tableInfo.Name.O, "INSERT")
+	}
+	var authErr error
+	if user := b.ctx.GetSessionVars().User; user != nil {
+		authErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tn.Schema.L, tableInfo.Name.L, "", authErr)
+	if insert.IsReplace {
+		// REPLACE may delete the conflicting rows.
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("DELETE", user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DeletePriv, tn.Schema.L, tableInfo.Name.L, "", authErr)
+	}
 	// Build Schema with DBName otherwise ColumnRef with DBName cannot match any Column in Schema.
 	schema, names := expression.TableInfo2SchemaAndNames(b.ctx, tn.Schema, tableInfo)
 	tableInPlan, ok := b.is.TableByID(tableInfo.ID)
@@ -907,14 +1118,95 @@ func (b *PlanBuilder) buildSelectPlanOfInsert(ctx context.Context, insert *ast.I
 }
 
 func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, error) {
+	var authErr error
+	user := b.ctx.GetSessionVars().User
+	tableAuthErr := func(command, table string) error {
+		if user == nil {
+			return nil
+		}
+		return ErrTableaccessDenied.GenWithStackByArgs(command, user.AuthUsername, user.AuthHostname, table)
+	}
+	switch v := node.(type) {
+	case *ast.AlterTableStmt:
+		authErr = tableAuthErr("ALTER", v.Table.Name.L)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.AlterPriv, v.Table.Schema.L, v.Table.Name.L, "", authErr)
+	case *ast.CreateDatabaseStmt:
+		if user != nil {
+			authErr = ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, v.Name)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreatePriv, strings.ToLower(v.Name), "", "", authErr)
+	case *ast.CreateIndexStmt:
+		authErr = tableAuthErr("INDEX", v.Table.Name.L)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.IndexPriv, v.Table.Schema.L, v.Table.Name.L, "", authErr)
+	case *ast.CreateTableStmt:
+		authErr = tableAuthErr("CREATE", v.Table.Name.L)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreatePriv, v.Table.Schema.L, v.Table.Name.L, "", authErr)
+		if v.ReferTable != nil {
+			authErr = tableAuthErr("SELECT", v.ReferTable.Name.L)
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, v.ReferTable.Schema.L, v.ReferTable.Name.L, "", authErr)
+		}
+	case *ast.CreateViewStmt:
+		if err := b.buildCreateView(ctx, v); err != nil {
+			return nil, err
+		}
+		authErr = tableAuthErr("CREATE VIEW", v.ViewName.Name.L)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreateViewPriv, v.ViewName.Schema.L, v.ViewName.Name.L, "", authErr)
+	case *ast.DropDatabaseStmt:
+		if user != nil {
+			authErr = ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, v.Name)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, strings.ToLower(v.Name), "", "", authErr)
+	case *ast.DropIndexStmt:
+		authErr = tableAuthErr("INDEX", v.Table.Name.L)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.IndexPriv, v.Table.Schema.L, v.Table.Name.L, "", authErr)
+	case *ast.DropTableStmt:
+		for _, tableVal := range v.Tables {
+			authErr = tableAuthErr("DROP", tableVal.Name.L)
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, tableVal.Schema.L, tableVal.Name.L, "", authErr)
+		}
+	case *ast.TruncateTableStmt:
+		authErr = tableAuthErr("DROP", v.Table.Name.L)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, v.Table.Schema.L, v.Table.Name.L, "", authErr)
+	}
 	p := &DDL{Statement: node}
 	return p, nil
 }
 
-func (b *PlanBuilder) buildExplainPlan(targetPlan Plan, format string, execStmt ast.StmtNode) (Plan, error) {
+// buildCreateView checks the SELECT statement of the view and fills the output column names
+// of the view.
+func (b *PlanBuilder) buildCreateView(ctx context.Context, v *ast.CreateViewStmt) error {
+	// A view can't refer to itself.
+	b.buildingViews = map[string]struct{}{viewKey(v.ViewName.Schema, v.ViewName.Name): {}}
+	p, err := b.buildResultSetNode(ctx, v.Select.(ast.ResultSetNode))
+	if err != nil {
+		return err
+	}
+	if len(v.Cols) > 0 && len(v.Cols) != p.Schema().Len() {
+		return ErrViewWrongList.GenWithStackByArgs()
+	}
+	v.SchemaCols = make([]model.CIStr, 0, p.Schema().Len())
+	for _, name := range p.OutputNames() {
+		v.SchemaCols = append(v.SchemaCols, name.ColName)
+	}
+	cols := v.Cols
+	if len(cols) == 0 {
+		cols = v.SchemaCols
+	}
+	dupNames := make(map[string]struct{}, len(cols))
+	for _, col := range cols {
+		if _, ok := dupNames[col.L]; ok {
+			return ErrDupFieldName.GenWithStackByArgs(col.O)
+		}
+		dupNames[col.L] = struct{}{}
+	}
+	return nil
+}
+
+func (b *PlanBuilder) buildExplainPlan(targetPlan Plan, format string, analyze bool, execStmt ast.StmtNode) (Plan, error) {
 	p := &Explain{
 		TargetPlan: targetPlan,
 		Format:     format,
+		Analyze:    analyze,
 		ExecStmt:   execStmt,
 	}
 	p.ctx = b.ctx
@@ -930,7 +1222,7 @@ func (b *PlanBuilder) buildExplain(ctx context.Context, explain *ast.ExplainStmt
 		return nil, err
 	}
 
-	return b.buildExplainPlan(targetPlan, explain.Format, explain.Stmt)
+	return b.buildExplainPlan(targetPlan, explain.Format, explain.Analyze, explain.Stmt)
 }
 
 func buildShowWarningsSchema() (*expression.Schema, types.NameSlice) {
@@ -958,10 +1250,22 @@ func buildShowSchema(s *ast.ShowStmt) (schema *expression.Schema, outputNames []
 		}
 	case ast.ShowVariables:
 		names = []string{"Variable_name", "Value"}
-	case ast.ShowCreateTable:
-		names = []string{"Table", "Create Table"}
+	case ast.ShowCreateTable, ast.ShowCreateView:
+		// SHOW CREATE TABLE shows the definition of a view like SHOW CREATE VIEW.
+		if s.Tp == ast.ShowCreateView || (s.Table != nil && s.Table.TableInfo != nil && s.Table.TableInfo.IsView()) {
+			names = []string{"View", "Create View", "character_set_client", "collation_connection"}
+		} else {
+			names = []string{"Table", "Create Table"}
+		}
 	case ast.ShowCreateDatabase:
 		names = []string{"Database", "Create Database"}
+	case ast.ShowGrants:
+		if s.User != nil && !s.User.CurrentUser {
+			names = []string{fmt.Sprintf("Grants for %s", s.User)}
+		} else {
+			// The current user is only known at execution time.
+			names = []string{"Grants for User"}
+		}
 	}
 
 	schema = expression.NewSchema(make([]*expression.Column, 0, len(names))...)
@@ -982,3 +1286,13 @@ func buildShowSchema(s *ast.ShowStmt) (schema *expression.Schema, outputNames []
 	}
 	return
 }
+
+func appendVisitInfo(vi []visitInfo, priv mysql.PrivilegeType, db, tbl, col string, err error) []visitInfo {
+	return append(vi, visitInfo{
+		privilege: priv,
+		db:        db,
+		table:     tbl,
+		column:    col,
+		err:       err,
+	})
+}
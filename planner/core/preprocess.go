@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -33,6 +34,19 @@ import (
 // PreprocessOpt presents optional parameters to `Preprocess` method.
 type PreprocessOpt func(*preprocessor)
 
+// InPrepare is a PreprocessOpt that indicates preprocess is executing under prepare statement.
+func InPrepare(p *preprocessor) {
+	p.flag |= inPrepare
+}
+
+// WithDefaultDB is a PreprocessOpt that resolves the unqualified table names to the database
+// instead of the current database, it is used to preprocess the SELECT statement of a view.
+func WithDefaultDB(db string) PreprocessOpt {
+	return func(p *preprocessor) {
+		p.defaultDB = db
+	}
+}
+
 // Preprocess resolves table names of the node, and checks some statements validation.
 func Preprocess(ctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema, preprocessOpt ...PreprocessOpt) error {
 	v := preprocessor{is: is, ctx: ctx, tableAliasInJoin: make([]map[string]interface{}, 0)}
@@ -50,6 +64,8 @@ const (
 	inCreateOrDropTable preprocessorFlag = 1 << iota
 	// parentIsJoin is set when visiting node's parent is join.
 	parentIsJoin
+	// inPrepare is set when visiting the statement of a prepare statement.
+	inPrepare
 )
 
 // preprocessor is an ast.Visitor that preprocess
@@ -63,6 +79,14 @@ type preprocessor struct {
 	// tableAliasInJoin is a stack that keeps the table alias names for joins.
 	// len(tableAliasInJoin) may bigger than 1 because the left/right child of join may be subquery that contains `JOIN`
 	tableAliasInJoin []map[string]interface{}
+
+	// cteNames is a stack that keeps the names of the common table expressions visible
+	// to the current query block, the table names referring to them are not resolved.
+	cteNames []string
+
+	// defaultDB is the database of the unqualified table names, the current database is
+	// used if it is empty.
+	defaultDB string
 }
 
 func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
@@ -73,6 +97,10 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 	case *ast.DropTableStmt:
 		p.flag |= inCreateOrDropTable
 		p.checkDropTableGrammar(node)
+	case *ast.CreateViewStmt:
+		p.checkCreateViewGrammar(node)
+		p.resolveCreateViewStmt(node)
+		return in, true
 	case *ast.CreateIndexStmt:
 		p.checkCreateIndexGrammar(node)
 	case *ast.AlterTableStmt:
@@ -86,6 +114,15 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		p.resolveShowStmt(node)
 	case *ast.Join:
 		p.checkNonUniqTableAlias(node)
+	case *ast.CommonTableExpression:
+		// A recursive CTE can refer to itself in its definition.
+		if node.IsRecursive {
+			p.cteNames = append(p.cteNames, node.Name.L)
+		}
+	case *driver.ParamMarkerExpr:
+		if p.flag&inPrepare == 0 {
+			p.err = parser.ErrSyntax.GenWithStack("syntax error, unexpected '?'")
+		}
 	default:
 		p.flag &= ^parentIsJoin
 	}
@@ -116,6 +153,19 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 		}
 	case *ast.TableName:
 		p.handleTableName(x)
+	case *ast.CommonTableExpression:
+		// A non-recursive CTE is only visible to the CTEs defined after it and the query body.
+		if !x.IsRecursive {
+			p.cteNames = append(p.cteNames, x.Name.L)
+		}
+	case *ast.SelectStmt:
+		if x.With != nil {
+			p.cteNames = p.cteNames[:len(p.cteNames)-len(x.With.CTEs)]
+		}
+	case *ast.SetOprStmt:
+		if x.With != nil {
+			p.cteNames = p.cteNames[:len(p.cteNames)-len(x.With.CTEs)]
+		}
 	case *ast.Join:
 		if len(p.tableAliasInJoin) > 0 {
 			p.tableAliasInJoin = p.tableAliasInJoin[:len(p.tableAliasInJoin)-1]
@@ -270,6 +320,20 @@ func (p *preprocessor) checkDropTableGrammar(stmt *ast.DropTableStmt) {
 	}
 }
 
+func (p *preprocessor) checkCreateViewGrammar(stmt *ast.CreateViewStmt) {
+	vName := stmt.ViewName.Name.String()
+	if isIncorrectName(vName) {
+		p.err = ddl.ErrWrongTableName.GenWithStackByArgs(vName)
+		return
+	}
+	for _, col := range stmt.Cols {
+		if isIncorrectName(col.String()) {
+			p.err = ddl.ErrWrongColumnName.GenWithStackByArgs(col)
+			return
+		}
+	}
+}
+
 func (p *preprocessor) checkNonUniqTableAlias(stmt *ast.Join) {
 	if p.flag&parentIsJoin == 0 {
 		p.tableAliasInJoin = append(p.tableAliasInJoin, make(map[string]interface{}))
@@ -503,7 +567,13 @@ func (p *preprocessor) checkContainDotColumn(stmt *ast.CreateTableStmt) {
 
 func (p *preprocessor) handleTableName(tn *ast.TableName) {
 	if tn.Schema.L == "" {
-		currentDB := p.ctx.GetSessionVars().CurrentDB
+		// The table name refers to a common table expression, the planner resolves it.
+		for i := len(p.cteNames) - 1; i >= 0; i-- {
+			if p.cteNames[i] == tn.Name.L {
+				return
+			}
+		}
+		currentDB := p.currentDB()
 		if currentDB == "" {
 			p.err = errors.Trace(ErrNoDB)
 			return
@@ -524,6 +594,34 @@ func (p *preprocessor) handleTableName(tn *ast.TableName) {
 	tn.DBInfo = dbInfo
 }
 
+// currentDB returns the database of the unqualified table names.
+func (p *preprocessor) currentDB() string {
+	if p.defaultDB != "" {
+		return p.defaultDB
+	}
+	return p.ctx.GetSessionVars().CurrentDB
+}
+
+// resolveCreateViewStmt resolves the view name and the SELECT statement of the view, the
+// unqualified table names in the SELECT statement belong to the database of the view.
+func (p *preprocessor) resolveCreateViewStmt(node *ast.CreateViewStmt) {
+	if p.err != nil {
+		return
+	}
+	if node.ViewName.Schema.L == "" {
+		currentDB := p.currentDB()
+		if currentDB == "" {
+			p.err = errors.Trace(ErrNoDB)
+			return
+		}
+		node.ViewName.Schema = model.NewCIStr(currentDB)
+	}
+	defaultDB := p.defaultDB
+	p.defaultDB = node.ViewName.Schema.O
+	node.Select.Accept(p)
+	p.defaultDB = defaultDB
+}
+
 func (p *preprocessor) resolveShowStmt(node *ast.ShowStmt) {
 	if node.DBName == "" {
 		if node.Table != nil && node.Table.Schema.L != "" {
@@ -113,6 +113,86 @@ func (p *PhysicalHashJoin) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalApply) ResolveIndices() (err error) {
+	err = p.PhysicalHashJoin.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	// p.OuterSchema may have duplicated CorrelatedColumns,
+	// we deduplicate it here.
+	dedupCols := make(map[int64]*expression.CorrelatedColumn, len(p.OuterSchema))
+	for _, col := range p.OuterSchema {
+		dedupCols[col.UniqueID] = col
+	}
+	p.OuterSchema = make([]*expression.CorrelatedColumn, 0, len(dedupCols))
+	for _, col := range dedupCols {
+		newCol, err := col.Column.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		col.Column = *newCol.(*expression.Column)
+		p.OuterSchema = append(p.OuterSchema, col)
+	}
+	// Resolve index for equal conditions again, because apply is different from
+	// hash join on the fact that equal conditions are evaluated against the join result,
+	// so columns from equal conditions index are relative to the join result.
+	joinedSchema := expression.MergeSchema(p.children[0].Schema(), p.children[1].Schema())
+	for i, cond := range p.EqualConditions {
+		newSf, err := cond.ResolveIndices(joinedSchema)
+		if err != nil {
+			return err
+		}
+		p.EqualConditions[i] = newSf.(*expression.ScalarFunction)
+	}
+	return
+}
+
+// ResolveIndices implements Plan interface.
+func (p *PhysicalIndexJoin) ResolveIndices() (err error) {
+	err = p.physicalSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	lSchema := p.children[0].Schema()
+	rSchema := p.children[1].Schema()
+	outerSchema, innerSchema := lSchema, rSchema
+	if p.InnerChildIdx == 0 {
+		outerSchema, innerSchema = rSchema, lSchema
+	}
+	for i := range p.OuterJoinKeys {
+		outerKey, err := p.OuterJoinKeys[i].ResolveIndices(outerSchema)
+		if err != nil {
+			return err
+		}
+		p.OuterJoinKeys[i] = outerKey.(*expression.Column)
+		innerKey, err := p.InnerJoinKeys[i].ResolveIndices(innerSchema)
+		if err != nil {
+			return err
+		}
+		p.InnerJoinKeys[i] = innerKey.(*expression.Column)
+	}
+	for i, expr := range p.LeftConditions {
+		p.LeftConditions[i], err = expr.ResolveIndices(lSchema)
+		if err != nil {
+			return err
+		}
+	}
+	for i, expr := range p.RightConditions {
+		p.RightConditions[i], err = expr.ResolveIndices(rSchema)
+		if err != nil {
+			return err
+		}
+	}
+	for i, expr := range p.OtherConditions {
+		p.OtherConditions[i], err = expr.ResolveIndices(expression.MergeSchema(lSchema, rSchema))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalMergeJoin) ResolveIndices() (err error) {
 	err = p.physicalSchemaProducer.ResolveIndices()
@@ -274,6 +354,55 @@ func (p *PhysicalSort) ResolveIndices() (err error) {
 	return err
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalWindow) ResolveIndices() (err error) {
+	err = p.physicalSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	childSchema := p.children[0].Schema()
+	for i := 0; i < p.Schema().Len()-len(p.WindowFuncDescs); i++ {
+		newCol, err := p.Schema().Columns[i].ResolveIndices(childSchema)
+		if err != nil {
+			return err
+		}
+		p.Schema().Columns[i] = newCol.(*expression.Column)
+	}
+	for i, item := range p.PartitionBy {
+		newCol, err := item.Col.ResolveIndices(childSchema)
+		if err != nil {
+			return err
+		}
+		p.PartitionBy[i].Col = newCol.(*expression.Column)
+	}
+	for i, item := range p.OrderBy {
+		newCol, err := item.Col.ResolveIndices(childSchema)
+		if err != nil {
+			return err
+		}
+		p.OrderBy[i].Col = newCol.(*expression.Column)
+	}
+	for _, desc := range p.WindowFuncDescs {
+		for i, arg := range desc.Args {
+			desc.Args[i], err = arg.ResolveIndices(childSchema)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if p.Frame != nil {
+		for _, bound := range []*FrameBound{p.Frame.Start, p.Frame.End} {
+			for i, expr := range bound.CalcFuncs {
+				bound.CalcFuncs[i], err = expr.ResolveIndices(childSchema)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalTopN) ResolveIndices() (err error) {
 	err = p.basePhysicalPlan.ResolveIndices()
@@ -289,6 +418,27 @@ func (p *PhysicalTopN) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *Update) ResolveIndices() (err error) {
+	err = p.baseSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	schema := p.SelectPlan.Schema()
+	for _, assign := range p.OrderedList {
+		newCol, err := assign.Col.ResolveIndices(schema)
+		if err != nil {
+			return err
+		}
+		assign.Col = newCol.(*expression.Column)
+		assign.Expr, err = assign.Expr.ResolveIndices(schema)
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
 // ResolveIndices implements Plan interface.
 func (p *Insert) ResolveIndices() (err error) {
 	err = p.baseSchemaProducer.ResolveIndices()
@@ -318,6 +468,16 @@ func (p *baseSchemaProducer) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalLock) ResolveIndices() (err error) {
+	err = p.basePhysicalPlan.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	p.TblID2Handle, err = resolveIndicesForTblID2Handle(p.TblID2Handle, p.children[0].Schema())
+	return err
+}
+
 // ResolveIndices implements Plan interface.
 func (p *basePhysicalPlan) ResolveIndices() (err error) {
 	for _, child := range p.children {
@@ -15,7 +15,9 @@ package core
 
 import (
 	"context"
+
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 )
@@ -39,6 +41,31 @@ func buildKeyInfo(lp LogicalPlan) {
 	lp.BuildKeyInfo(lp.Schema(), childSchema)
 }
 
+// HasMaxOneRow returns if the LogicalPlan will output at most one row.
+func HasMaxOneRow(p LogicalPlan, childMaxOneRow []bool) bool {
+	if len(childMaxOneRow) == 0 {
+		// The reason why we use this check is that, this function
+		// is used both in planner/core and planner/cascades.
+		// In cascades planner, LogicalPlan may have no `children`.
+		return false
+	}
+	switch x := p.(type) {
+	case *LogicalLimit, *LogicalTopN, *LogicalSort, *LogicalSelection,
+		*LogicalApply, *LogicalProjection, *LogicalAggregation, *LogicalWindow:
+		return childMaxOneRow[0]
+	case *LogicalMaxOneRow:
+		return true
+	case *LogicalJoin:
+		switch x.JoinType {
+		case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
+			return childMaxOneRow[0]
+		default:
+			return childMaxOneRow[0] && childMaxOneRow[1]
+		}
+	}
+	return false
+}
+
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (la *LogicalAggregation) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	if la.IsPartialModeAgg() {
@@ -66,16 +93,76 @@ func (la *LogicalAggregation) BuildKeyInfo(selfSchema *expression.Schema, childS
 			selfSchema.Keys = append(selfSchema.Keys, newKey)
 		}
 	}
+	if len(la.GroupByItems) == 0 {
+		la.maxOneRow = true
+	}
+}
+
+// If a condition is the form of (uniqueKey = constant) or (uniqueKey = Correlated column), it returns at most one row.
+// This function will check it.
+func (p *LogicalSelection) checkMaxOneRowCond(unique expression.Expression, constOrCorCol expression.Expression, childSchema *expression.Schema) bool {
+	col, ok := unique.(*expression.Column)
+	if !ok {
+		return false
+	}
+	if !childSchema.IsUniqueKey(col) {
+		return false
+	}
+	_, okCon := constOrCorCol.(*expression.Constant)
+	if okCon {
+		return true
+	}
+	_, okCorCol := constOrCorCol.(*expression.CorrelatedColumn)
+	return okCorCol
 }
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *LogicalSelection) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
+	for _, cond := range p.Conditions {
+		if sf, ok := cond.(*expression.ScalarFunction); ok && sf.FuncName.L == ast.EQ {
+			if p.checkMaxOneRowCond(sf.GetArgs()[0], sf.GetArgs()[1], childSchema[0]) || p.checkMaxOneRowCond(sf.GetArgs()[1], sf.GetArgs()[0], childSchema[0]) {
+				p.maxOneRow = true
+				break
+			}
+		}
+	}
 }
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *LogicalLimit) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
+	if p.Count == 1 {
+		p.maxOneRow = true
+	}
+}
+
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (lt *LogicalTopN) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	lt.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
+	if lt.Count == 1 {
+		lt.maxOneRow = true
+	}
+}
+
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (p *LogicalTableDual) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	p.logicalSchemaProducer.BuildKeyInfo(selfSchema, childSchema)
+	if p.RowCount == 1 {
+		p.maxOneRow = true
+	}
+}
+
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (p *LogicalWindow) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	p.logicalSchemaProducer.BuildKeyInfo(selfSchema, childSchema)
+	// The window outputs the rows of its child with the function results appended,
+	// so the keys of the child are still keys.
+	for _, key := range childSchema[0].Keys {
+		if selfSchema.ColumnsIndices(key) != nil {
+			selfSchema.Keys = append(selfSchema.Keys, key)
+		}
+	}
 }
 
 // A bijection exists between columns of a projection's schema and this projection's Exprs.
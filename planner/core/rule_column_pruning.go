@@ -92,6 +92,15 @@ func (p *LogicalSelection) PruneColumns(parentUsedCols []*expression.Column) err
 	return child.PruneColumns(parentUsedCols)
 }
 
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalLock) PruneColumns(parentUsedCols []*expression.Column) error {
+	// The handle columns are needed to lock the rows.
+	for _, cols := range p.tblID2Handle {
+		parentUsedCols = append(parentUsedCols, cols...)
+	}
+	return p.children[0].PruneColumns(parentUsedCols)
+}
+
 // PruneColumns implements LogicalPlan interface.
 func (la *LogicalAggregation) PruneColumns(parentUsedCols []*expression.Column) error {
 	child := la.children[0]
@@ -160,6 +169,39 @@ func (ls *LogicalSort) PruneColumns(parentUsedCols []*expression.Column) error {
 	return child.PruneColumns(parentUsedCols)
 }
 
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalUnionAll) PruneColumns(parentUsedCols []*expression.Column) error {
+	used := getUsedList(parentUsedCols, p.schema)
+	hasBeenUsed := false
+	for i := range used {
+		hasBeenUsed = hasBeenUsed || used[i]
+		if hasBeenUsed {
+			break
+		}
+	}
+	if !hasBeenUsed {
+		parentUsedCols = make([]*expression.Column, len(p.schema.Columns))
+		copy(parentUsedCols, p.schema.Columns)
+	}
+	for _, child := range p.Children() {
+		err := child.PruneColumns(parentUsedCols)
+		if err != nil {
+			return err
+		}
+	}
+
+	if hasBeenUsed {
+		// keep the schema of LogicalUnionAll same as its children's
+		used := getUsedList(p.children[0].Schema().Columns, p.schema)
+		for i := len(used) - 1; i >= 0; i-- {
+			if !used[i] {
+				p.schema.Columns = append(p.schema.Columns[:i], p.schema.Columns[i+1:]...)
+			}
+		}
+	}
+	return nil
+}
+
 // PruneColumns implements LogicalPlan interface.
 func (p *LogicalUnionScan) PruneColumns(parentUsedCols []*expression.Column) error {
 	parentUsedCols = append(parentUsedCols, p.handleCol)
@@ -240,7 +282,16 @@ func (p *LogicalJoin) extractUsedCols(parentUsedCols []*expression.Column) (left
 func (p *LogicalJoin) mergeSchema() {
 	lChild := p.children[0]
 	rChild := p.children[1]
-	p.schema = expression.MergeSchema(lChild.Schema(), rChild.Schema())
+	composedSchema := expression.MergeSchema(lChild.Schema(), rChild.Schema())
+	if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin {
+		p.schema = lChild.Schema().Clone()
+	} else if p.JoinType == LeftOuterSemiJoin || p.JoinType == AntiLeftOuterSemiJoin {
+		joinCol := p.schema.Columns[len(p.schema.Columns)-1]
+		p.schema = lChild.Schema().Clone()
+		p.schema.Append(joinCol)
+	} else {
+		p.schema = composedSchema
+	}
 }
 
 // PruneColumns implements LogicalPlan interface.
@@ -251,16 +302,111 @@ func (p *LogicalJoin) PruneColumns(parentUsedCols []*expression.Column) error {
 	if err != nil {
 		return err
 	}
+	addConstOneForEmptyProjection(p.children[0])
 
 	err = p.children[1].PruneColumns(rightCols)
 	if err != nil {
 		return err
 	}
+	addConstOneForEmptyProjection(p.children[1])
 
 	p.mergeSchema()
 	return nil
 }
 
+// PruneColumns implements LogicalPlan interface.
+func (la *LogicalApply) PruneColumns(parentUsedCols []*expression.Column) error {
+	leftCols, rightCols := la.extractUsedCols(parentUsedCols)
+
+	err := la.children[1].PruneColumns(rightCols)
+	if err != nil {
+		return err
+	}
+	addConstOneForEmptyProjection(la.children[1])
+
+	la.CorCols = extractCorColumnsBySchema(la.children[1], la.children[0].Schema())
+	for _, col := range la.CorCols {
+		leftCols = append(leftCols, &col.Column)
+	}
+
+	err = la.children[0].PruneColumns(leftCols)
+	if err != nil {
+		return err
+	}
+	addConstOneForEmptyProjection(la.children[0])
+
+	la.mergeSchema()
+	return nil
+}
+
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalWindow) PruneColumns(parentUsedCols []*expression.Column) error {
+	windowColumns := p.GetWindowResultColumns()
+	windowSchema := expression.NewSchema(windowColumns...)
+	used := getUsedList(parentUsedCols, windowSchema)
+	descs := make([]*aggregation.WindowFuncDesc, 0, len(p.WindowFuncDescs))
+	usedWindowColumns := make([]*expression.Column, 0, len(windowColumns))
+	for i, desc := range p.WindowFuncDescs {
+		if used[i] {
+			descs = append(descs, desc)
+			usedWindowColumns = append(usedWindowColumns, windowColumns[i])
+		}
+	}
+	// If all the window functions are pruned, we keep the first one to keep the window valid.
+	if len(descs) == 0 {
+		descs = append(descs, p.WindowFuncDescs[0])
+		usedWindowColumns = append(usedWindowColumns, windowColumns[0])
+	}
+	p.WindowFuncDescs = descs
+
+	selfUsedCols := make([]*expression.Column, 0, len(parentUsedCols))
+	for _, col := range parentUsedCols {
+		if !windowSchema.Contains(col) {
+			selfUsedCols = append(selfUsedCols, col)
+		}
+	}
+	for _, desc := range p.WindowFuncDescs {
+		selfUsedCols = expression.ExtractColumnsFromExpressions(selfUsedCols, desc.Args, nil)
+	}
+	for _, item := range p.PartitionBy {
+		selfUsedCols = append(selfUsedCols, item.Col)
+	}
+	for _, item := range p.OrderBy {
+		selfUsedCols = append(selfUsedCols, item.Col)
+	}
+	err := p.children[0].PruneColumns(selfUsedCols)
+	if err != nil {
+		return err
+	}
+	addConstOneForEmptyProjection(p.children[0])
+
+	p.SetSchema(p.children[0].Schema().Clone())
+	p.schema.Append(usedWindowColumns...)
+	return nil
+}
+
+// addConstOneForEmptyProjection makes sure that Projection has at least one
+// expression.
+func addConstOneForEmptyProjection(p LogicalPlan) {
+	proj, ok := p.(*LogicalProjection)
+	if !ok {
+		return
+	}
+	if proj.Schema().Len() != 0 {
+		return
+	}
+
+	constOne := expression.One.Clone()
+	proj.schema.Append(&expression.Column{
+		UniqueID: proj.ctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  constOne.GetType(),
+	})
+	proj.Exprs = append(proj.Exprs, &expression.Constant{
+		Value:   constOne.(*expression.Constant).Value,
+		RetType: constOne.GetType(),
+	})
+}
+
 func (*columnPruner) name() string {
 	return "column_prune"
 }
@@ -53,9 +53,9 @@ func canProjectionBeEliminatedStrict(p *PhysicalProjection) bool {
 func resolveColumnAndReplace(origin *expression.Column, replace map[string]*expression.Column) {
 	dst := replace[string(origin.HashCode(nil))]
 	if dst != nil {
-		retType := origin.RetType
+		retType, inOperand := origin.RetType, origin.InOperand
 		*origin = *dst
-		origin.RetType = retType
+		origin.RetType, origin.InOperand = retType, inOperand
 	}
 }
 
@@ -64,6 +64,8 @@ func ResolveExprAndReplace(origin expression.Expression, replace map[string]*exp
 	switch expr := origin.(type) {
 	case *expression.Column:
 		resolveColumnAndReplace(expr, replace)
+	case *expression.CorrelatedColumn:
+		resolveColumnAndReplace(&expr.Column, replace)
 	case *expression.ScalarFunction:
 		for _, arg := range expr.GetArgs() {
 			ResolveExprAndReplace(arg, replace)
@@ -112,7 +114,9 @@ func (pe *projectionEliminator) optimize(ctx context.Context, lp LogicalPlan) (L
 func (pe *projectionEliminator) eliminate(p LogicalPlan, replace map[string]*expression.Column, canEliminate bool) LogicalPlan {
 	proj, isProj := p.(*LogicalProjection)
 	childFlag := canEliminate
-	if _, isAgg := p.(*LogicalAggregation); isAgg || isProj {
+	if _, isUnion := p.(*LogicalUnionAll); isUnion {
+		childFlag = false
+	} else if _, isAgg := p.(*LogicalAggregation); isAgg || isProj {
 		childFlag = true
 	}
 	for i, child := range p.Children() {
@@ -122,6 +126,14 @@ func (pe *projectionEliminator) eliminate(p LogicalPlan, replace map[string]*exp
 	switch x := p.(type) {
 	case *LogicalJoin:
 		x.schema = buildLogicalJoinSchema(x.JoinType, x)
+	case *LogicalApply:
+		x.schema = buildLogicalJoinSchema(x.JoinType, x)
+	case *LogicalWindow:
+		// The schema of the window starts with the schema of its child, which
+		// may be changed by the elimination.
+		schema := x.children[0].Schema().Clone()
+		schema.Append(x.GetWindowResultColumns()...)
+		x.schema = schema
 	default:
 		for _, dst := range p.Schema().Columns {
 			resolveColumnAndReplace(dst, replace)
@@ -178,6 +190,16 @@ func (p *LogicalJoin) replaceExprColumns(replace map[string]*expression.Column)
 	}
 }
 
+func (la *LogicalApply) replaceExprColumns(replace map[string]*expression.Column) {
+	la.LogicalJoin.replaceExprColumns(replace)
+	for _, coCol := range la.CorCols {
+		dst := replace[string(coCol.Column.HashCode(nil))]
+		if dst != nil {
+			coCol.Column = *dst
+		}
+	}
+}
+
 func (p *LogicalProjection) replaceExprColumns(replace map[string]*expression.Column) {
 	for _, expr := range p.Exprs {
 		ResolveExprAndReplace(expr, replace)
@@ -214,6 +236,27 @@ func (lt *LogicalTopN) replaceExprColumns(replace map[string]*expression.Column)
 	}
 }
 
+func (p *LogicalWindow) replaceExprColumns(replace map[string]*expression.Column) {
+	for _, desc := range p.WindowFuncDescs {
+		for _, arg := range desc.Args {
+			ResolveExprAndReplace(arg, replace)
+		}
+	}
+	for _, item := range p.PartitionBy {
+		resolveColumnAndReplace(item.Col, replace)
+	}
+	for _, item := range p.OrderBy {
+		resolveColumnAndReplace(item.Col, replace)
+	}
+	if p.Frame != nil {
+		for _, bound := range []*FrameBound{p.Frame.Start, p.Frame.End} {
+			for _, expr := range bound.CalcFuncs {
+				ResolveExprAndReplace(expr, replace)
+			}
+		}
+	}
+}
+
 func (*projectionEliminator) name() string {
 	return "projection_eliminate"
 }
@@ -0,0 +1,278 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/ranger"
+)
+
+// partitionProcessor rewrites the ast for table partition.
+//
+// create table t (id int) partition by range (id)
+//   (partition p1 values less than (10),
+//    partition p2 values less than (20),
+//    partition p3 values less than (30))
+//
+// select * from t is equal to
+// select * from (union all
+//      select * from p1 where id < 10
+//      select * from p2 where id < 20
+//      select * from p3 where id < 30)
+//
+// The partitions that can't contain the rows satisfying the filters are pruned.
+// partitionProcessor is here because it's easier to prune partition after predicate push down.
+type partitionProcessor struct{}
+
+func (s *partitionProcessor) optimize(ctx context.Context, lp LogicalPlan) (LogicalPlan, error) {
+	return s.rewriteDataSource(lp)
+}
+
+func (s *partitionProcessor) rewriteDataSource(lp LogicalPlan) (LogicalPlan, error) {
+	switch p := lp.(type) {
+	case *DataSource:
+		return s.prune(p)
+	case *LogicalUnionScan:
+		ds := p.Children()[0]
+		ds, err := s.prune(ds.(*DataSource))
+		if err != nil {
+			return nil, err
+		}
+		if ua, ok := ds.(*LogicalUnionAll); ok {
+			// Adjust the UnionScan->Union->DataSource1, DataSource2 ... to
+			// Union->(UnionScan->DataSource1), (UnionScan->DataSource2)
+			children := make([]LogicalPlan, 0, len(ua.Children()))
+			for _, child := range ua.Children() {
+				us := LogicalUnionScan{conditions: p.conditions, handleCol: p.handleCol}.Init(ua.ctx)
+				us.SetChildren(child)
+				children = append(children, us)
+			}
+			ua.SetChildren(children...)
+			return ua, nil
+		}
+		// Only one partition, no union all.
+		p.SetChildren(ds)
+		return p, nil
+	default:
+		children := lp.Children()
+		for i, child := range children {
+			newChild, err := s.rewriteDataSource(child)
+			if err != nil {
+				return nil, err
+			}
+			children[i] = newChild
+		}
+	}
+	return lp, nil
+}
+
+// prune replaces the DataSource of a partitioned table with the union of its partitions
+// that are not pruned by the filters.
+func (s *partitionProcessor) prune(ds *DataSource) (LogicalPlan, error) {
+	pi := ds.tableInfo.GetPartitionInfo()
+	if pi == nil || ds.isPartition {
+		return ds, nil
+	}
+
+	// The partition expression is built on the columns of the DataSource, so it can be
+	// evaluated together with the filters.
+	tblCols := ds.table.Cols()
+	schema := expression.NewSchema(ds.TblCols[:len(tblCols)]...)
+	names := make(types.NameSlice, 0, len(tblCols))
+	for _, col := range tblCols {
+		names = append(names, &types.FieldName{TblName: ds.tableInfo.Name, ColName: col.Name})
+	}
+	pe, err := tables.NewPartitionExpr(ds.ctx, ds.tableInfo, schema, names)
+	if err != nil {
+		return nil, err
+	}
+
+	var used []int
+	switch pi.Type {
+	case model.PartitionTypeRange:
+		used, err = s.pruneRangePartition(ds.ctx, pe, ds.allConds)
+	case model.PartitionTypeHash:
+		used, err = s.pruneHashPartition(ds.ctx, pi, pe, schema, ds.allConds)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.makeUnionAllChildren(ds, pi, used), nil
+}
+
+// pruneRangePartition returns the offsets of the RANGE partitions that may contain the rows
+// satisfying the filters.
+func (s *partitionProcessor) pruneRangePartition(sctx sessionctx.Context, pe *tables.PartitionExpr, conds []expression.Expression) ([]int, error) {
+	partCol, _ := pe.Expr.(*expression.Column)
+	used := make([]int, 0, len(pe.Ranges))
+	for i, rng := range pe.Ranges {
+		if rng != nil {
+			pruned, err := s.canBePruned(sctx, partCol, rng, conds)
+			if err != nil {
+				return nil, err
+			}
+			if pruned {
+				continue
+			}
+		}
+		used = append(used, i)
+	}
+	return used, nil
+}
+
+// canBePruned checks if partition expression will never meets the selection condition.
+// For example, partition by column a > 3, and select condition is a < 3, then canBePrune returns true.
+func (s *partitionProcessor) canBePruned(sctx sessionctx.Context, partCol *expression.Column, partExpr expression.Expression, filterExprs []expression.Expression) (bool, error) {
+	conds := make([]expression.Expression, 0, 1+len(filterExprs))
+	conds = append(conds, partExpr)
+	conds = append(conds, filterExprs...)
+	conds = expression.PropagateConstant(sctx, conds)
+	if len(conds) == 1 {
+		// Constant false.
+		if con, ok := conds[0].(*expression.Constant); ok {
+			ret, _, err := expression.EvalBool(sctx, expression.CNFExprs{con}, chunk.Row{})
+			if err == nil && !ret {
+				return true, nil
+			}
+		}
+		// Not a constant false, but this is the only condition, it can't be pruned.
+		return false, nil
+	}
+
+	// Calculates the column range to prune.
+	if partCol == nil {
+		// If partition column is nil, we can't calculate range, so we can't prune
+		// partition by range.
+		return false, nil
+	}
+
+	// Constant propagation doesn't handle the null condition, while calculating range can
+	// prune something like "select * from t where a is null".
+	accessConds := ranger.ExtractAccessConditionsForColumn(conds, partCol.UniqueID)
+	r, err := ranger.BuildColumnRange(accessConds, sctx.GetSessionVars().StmtCtx, partCol.RetType, types.UnspecifiedLength)
+	if err != nil {
+		return false, err
+	}
+	return len(r) == 0, nil
+}
+
+// pruneHashPartition returns the offsets of the HASH partitions that may contain the rows
+// satisfying the filters. The partitions can only be pruned when the filters restrict the
+// only column in the partition expression to some points.
+func (s *partitionProcessor) pruneHashPartition(sctx sessionctx.Context, pi *model.PartitionInfo, pe *tables.PartitionExpr,
+	schema *expression.Schema, conds []expression.Expression) ([]int, error) {
+	all := make([]int, 0, len(pi.Definitions))
+	for i := range pi.Definitions {
+		all = append(all, i)
+	}
+	cols := expression.ExtractColumns(pe.Expr)
+	if len(cols) == 0 || len(conds) == 0 {
+		return all, nil
+	}
+	partCol := cols[0]
+	for _, col := range cols[1:] {
+		if !col.Equal(nil, partCol) {
+			return all, nil
+		}
+	}
+
+	sc := sctx.GetSessionVars().StmtCtx
+	accessConds := ranger.ExtractAccessConditionsForColumn(conds, partCol.UniqueID)
+	if len(accessConds) == 0 {
+		return all, nil
+	}
+	ranges, err := ranger.BuildColumnRange(accessConds, sc, partCol.RetType, types.UnspecifiedLength)
+	if err != nil {
+		return nil, err
+	}
+	for _, ran := range ranges {
+		if !ran.IsPoint(sc) {
+			return all, nil
+		}
+	}
+
+	expr, err := pe.Expr.ResolveIndices(schema)
+	if err != nil {
+		return nil, err
+	}
+	locator := &tables.PartitionExpr{Expr: expr}
+	colIdx := schema.ColumnIndex(partCol)
+	row := make([]types.Datum, schema.Len())
+	used := make([]int, 0, len(ranges))
+	usedSet := make(map[int]struct{}, len(ranges))
+	for _, ran := range ranges {
+		row[colIdx] = ran.LowVal[0]
+		idx, err := locator.LocatePartition(sctx, pi, chunk.MutRowFromDatums(row).ToRow())
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := usedSet[idx]; ok {
+			continue
+		}
+		usedSet[idx] = struct{}{}
+		used = append(used, idx)
+	}
+	return used, nil
+}
+
+// makeUnionAllChildren builds a DataSource for every partition in used, and puts them under a UnionAll.
+func (s *partitionProcessor) makeUnionAllChildren(ds *DataSource, pi *model.PartitionInfo, used []int) LogicalPlan {
+	children := make([]LogicalPlan, 0, len(used))
+	for _, i := range used {
+		// Not a deep copy.
+		newDataSource := *ds
+		newDataSource.baseLogicalPlan = newBaseLogicalPlan(ds.SCtx(), TypeTableScan, &newDataSource)
+		newDataSource.isPartition = true
+		newDataSource.physicalTableID = pi.Definitions[i].ID
+		newDataSource.statisticTable = getStatsTable(ds.SCtx(), ds.tableInfo, newDataSource.physicalTableID)
+		// There are many expression nodes in the plan tree use the original datasource
+		// id as FromID. So we set the id of the newDataSource with the original one to
+		// avoid traversing the whole plan tree to update the references.
+		newDataSource.id = ds.id
+		// The access paths are filled when deriving the stats of every partition.
+		newDataSource.possibleAccessPaths = make([]*util.AccessPath, 0, len(ds.possibleAccessPaths))
+		for _, path := range ds.possibleAccessPaths {
+			newPath := *path
+			newDataSource.possibleAccessPaths = append(newDataSource.possibleAccessPaths, &newPath)
+		}
+		children = append(children, &newDataSource)
+	}
+
+	if len(children) == 0 {
+		// No result after table pruning.
+		tableDual := LogicalTableDual{RowCount: 0}.Init(ds.SCtx())
+		tableDual.SetSchema(ds.Schema())
+		return tableDual
+	}
+	if len(children) == 1 {
+		// No need for the union all.
+		return children[0]
+	}
+	unionAll := LogicalUnionAll{}.Init(ds.SCtx())
+	unionAll.SetChildren(children...)
+	unionAll.SetSchema(ds.schema)
+	return unionAll
+}
+
+func (*partitionProcessor) name() string {
+	return "partition_processor"
+}
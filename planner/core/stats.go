@@ -44,6 +44,31 @@ func (p *LogicalTableDual) DeriveStats(childStats []*property.StatsInfo, selfSch
 	return p.stats, nil
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalCTE) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	p.stats = deriveCTEStats(p.cte, selfSchema)
+	return p.stats, nil
+}
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalCTETable) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	p.stats = deriveCTEStats(p.cte, selfSchema)
+	return p.stats, nil
+}
+
+// deriveCTEStats estimates the result of a CTE by its seed part, since the number of
+// iterations of the recursive part is unknown before execution.
+func deriveCTEStats(cte *CTEClass, selfSchema *expression.Schema) *property.StatsInfo {
+	profile := &property.StatsInfo{
+		RowCount:    cte.seedStat.RowCount,
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	for i := range profile.Cardinality {
+		profile.Cardinality[i] = cte.seedStat.RowCount
+	}
+	return profile
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (p *LogicalMemTable) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
 	statsTable := statistics.PseudoTable(p.tableInfo)
@@ -303,6 +328,20 @@ func getCardinality(cols []*expression.Column, schema *expression.Schema, profil
 	return cardinality
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalUnionAll) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	p.stats = &property.StatsInfo{
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	for _, childProfile := range childStats {
+		p.stats.RowCount += childProfile.RowCount
+		for i := range p.stats.Cardinality {
+			p.stats.Cardinality[i] += childProfile.Cardinality[i]
+		}
+	}
+	return p.stats, nil
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (p *LogicalProjection) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
 	childProfile := childStats[0]
@@ -357,6 +396,25 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		rightSchema:   childSchema[1],
 	}
 	p.equalCondOutCnt = helper.estimate()
+	if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin {
+		p.stats = &property.StatsInfo{
+			RowCount:    leftProfile.RowCount * selectionFactor,
+			Cardinality: make([]float64, len(leftProfile.Cardinality)),
+		}
+		for i := range p.stats.Cardinality {
+			p.stats.Cardinality[i] = leftProfile.Cardinality[i] * selectionFactor
+		}
+		return p.stats, nil
+	}
+	if p.JoinType == LeftOuterSemiJoin || p.JoinType == AntiLeftOuterSemiJoin {
+		p.stats = &property.StatsInfo{
+			RowCount:    leftProfile.RowCount,
+			Cardinality: make([]float64, selfSchema.Len()),
+		}
+		copy(p.stats.Cardinality, leftProfile.Cardinality)
+		p.stats.Cardinality[len(p.stats.Cardinality)-1] = 2.0
+		return p.stats, nil
+	}
 	count := p.equalCondOutCnt
 	if p.JoinType == LeftOuterJoin {
 		count = math.Max(count, leftProfile.RowCount)
@@ -376,6 +434,42 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 	return p.stats, nil
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (la *LogicalApply) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	leftProfile := childStats[0]
+	la.stats = &property.StatsInfo{
+		RowCount:    leftProfile.RowCount,
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	copy(la.stats.Cardinality, leftProfile.Cardinality)
+	if la.JoinType == LeftOuterSemiJoin || la.JoinType == AntiLeftOuterSemiJoin {
+		la.stats.Cardinality[len(la.stats.Cardinality)-1] = 2.0
+	} else {
+		for i := childSchema[0].Len(); i < selfSchema.Len(); i++ {
+			la.stats.Cardinality[i] = leftProfile.RowCount
+		}
+	}
+	return la.stats, nil
+}
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalWindow) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	childProfile := childStats[0]
+	p.stats = &property.StatsInfo{
+		RowCount:    childProfile.RowCount,
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	childLen := selfSchema.Len() - len(p.WindowFuncDescs)
+	for i := 0; i < childLen; i++ {
+		colIdx := childSchema[0].ColumnIndex(selfSchema.Columns[i])
+		p.stats.Cardinality[i] = childProfile.Cardinality[colIdx]
+	}
+	for i := childLen; i < selfSchema.Len(); i++ {
+		p.stats.Cardinality[i] = childProfile.RowCount
+	}
+	return p.stats, nil
+}
+
 type fullJoinRowCountHelper struct {
 	cartesian     bool
 	leftProfile   *property.StatsInfo
@@ -14,6 +14,7 @@
 package core
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 )
@@ -66,6 +67,18 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 			r := eq.GetArgs()[1].String()
 			str += fmt.Sprintf("(%s,%s)", l, r)
 		}
+	case *PhysicalIndexJoin:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		idxs = idxs[:last]
+		str = "IndexJoin{" + strings.Join(children, "->") + "}"
+		for i := range x.OuterJoinKeys {
+			l := x.OuterJoinKeys[i]
+			r := x.InnerJoinKeys[i]
+			str += fmt.Sprintf("(%s,%s)", l, r)
+		}
 	case *PhysicalMergeJoin:
 		last := len(idxs) - 1
 		idx := idxs[last]
@@ -97,6 +110,36 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 		str = "ShowDDLJobs"
 	case *LogicalSort, *PhysicalSort:
 		str = "Sort"
+	case *LogicalWindow:
+		buffer := bytes.NewBufferString("")
+		formatWindowFuncDescs(buffer, x.WindowFuncDescs, x.schema)
+		str = fmt.Sprintf("Window(%s)", buffer.String())
+	case *PhysicalWindow:
+		str = fmt.Sprintf("Window(%s)", x.ExplainInfo())
+	case *LogicalCTE:
+		str = fmt.Sprintf("CTE(%s)", x.cteAsName)
+	case *PhysicalCTE:
+		str = fmt.Sprintf("CTE(%s)", x.cteAsName)
+	case *LogicalCTETable, *PhysicalCTETable:
+		str = "CTETable"
+	case *LogicalApply:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		str = "Apply{" + strings.Join(children, "->") + "}"
+		idxs = idxs[:last]
+	case *LogicalMaxOneRow, *PhysicalMaxOneRow:
+		str = "MaxOneRow"
+	case *LogicalLock, *PhysicalLock:
+		str = "Lock"
+	case *PhysicalApply:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		str = "Apply{" + strings.Join(children, "->") + "}"
+		idxs = idxs[:last]
 	case *LogicalJoin:
 		last := len(idxs) - 1
 		idx := idxs[last]
@@ -127,8 +170,17 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 		str = fmt.Sprintf("TopN(%v,%d,%d)", x.ByItems, x.Offset, x.Count)
 	case *LogicalTableDual, *PhysicalTableDual:
 		str = "Dual"
+	case *LogicalUnionAll, *PhysicalUnionAll:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		str = "UnionAll{" + strings.Join(children, "->") + "}"
+		idxs = idxs[:last]
 	case *PhysicalHashAgg:
 		str = "HashAgg"
+	case *PhysicalStreamAgg:
+		str = "StreamAgg"
 	case *LogicalAggregation:
 		str = "Aggr("
 		for i, aggFunc := range x.AggFuncs {
@@ -163,6 +215,8 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 			children = append(children, fmt.Sprintf("Table(%s)", strings.Join(colNames, ", ")))
 		}
 		str = str + strings.Join(children, ",") + "}"
+	case *Update:
+		str = fmt.Sprintf("%s->Update", ToString(x.SelectPlan))
 	case *Delete:
 		str = fmt.Sprintf("%s->Delete", ToString(x.SelectPlan))
 	case *Insert:
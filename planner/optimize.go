@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/planner/cascades"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 )
@@ -38,6 +39,21 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		return nil, nil, err
 	}
 
+	// Check privilege. Maybe it's better to move this to the Preprocess, but
+	// we need the table information to check privilege, which is collected
+	// into the visitInfo in the logical plan builder.
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil {
+		if err := plannercore.CheckPrivilege(pm, builder.GetVisitInfo()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Execute plan is built from the prepared statement, so optimize the statement it refers to.
+	if execPlan, ok := p.(*plannercore.Execute); ok {
+		err := execPlan.OptimizePreparedPlan(ctx, sctx, is)
+		return p, p.OutputNames(), err
+	}
+
 	names := p.OutputNames()
 
 	// Handle the non-logical plan statement.
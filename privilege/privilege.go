@@ -0,0 +1,64 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privilege
+
+import (
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+type keyType int
+
+func (k keyType) String() string {
+	return "privilege-key"
+}
+
+// Manager is the interface for providing privilege related operations.
+type Manager interface {
+	// ShowGrants shows granted privileges for user.
+	ShowGrants(ctx sessionctx.Context, user *auth.UserIdentity) ([]string, error)
+
+	// GetAuthPlugin gets the authentication plugin for the account identified by the user and host.
+	GetAuthPlugin(user, host string) (string, error)
+
+	// RequestVerification verifies user privilege for the request.
+	// If table is "", only check global/db scope privileges.
+	// If table is not "", check global/db/table scope privileges.
+	RequestVerification(db, table, column string, priv mysql.PrivilegeType) bool
+
+	// ConnectionVerification verifies user privilege for connection.
+	// For mysql_native_password accounts, auth is the scrambled password sent by the client.
+	// For caching_sha2_password accounts, auth is the cleartext password.
+	// It returns the user and host of the matched account on success.
+	ConnectionVerification(user, host string, auth, salt []byte) (string, string, bool)
+
+	// DBIsVisible returns true is the database is visible to current user.
+	DBIsVisible(db string) bool
+}
+
+const key keyType = 0
+
+// BindPrivilegeManager binds Checker to context.
+func BindPrivilegeManager(ctx sessionctx.Context, pc Manager) {
+	ctx.SetValue(key, pc)
+}
+
+// GetPrivilegeManager gets Checker from context.
+func GetPrivilegeManager(ctx sessionctx.Context) Manager {
+	if v, ok := ctx.Value(key).(Manager); ok {
+		return v
+	}
+	return nil
+}
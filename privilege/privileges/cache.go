@@ -0,0 +1,444 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/stringutil"
+)
+
+// baseRecord is the common part of the records in the privilege tables.
+type baseRecord struct {
+	Host string // max length 60, primary key
+	User string // max length 32, primary key
+
+	// patChars is compiled from Host, cached for pattern match performance.
+	patChars []byte
+	patTypes []byte
+}
+
+// UserRecord is used to represent a user record in privilege cache.
+type UserRecord struct {
+	baseRecord
+
+	AuthenticationString string
+	AuthPlugin           string
+	Privileges           mysql.PrivilegeType
+}
+
+type dbRecord struct {
+	baseRecord
+
+	DB         string
+	Privileges mysql.PrivilegeType
+}
+
+type tablesPrivRecord struct {
+	baseRecord
+
+	DB        string
+	TableName string
+	Grantor   string
+	TablePriv mysql.PrivilegeType
+}
+
+// MySQLPrivilege is the in-memory cache of mysql privilege tables.
+type MySQLPrivilege struct {
+	User       []UserRecord
+	DB         []dbRecord
+	TablesPriv []tablesPrivRecord
+}
+
+// LoadAll loads the tables from database to memory.
+func (p *MySQLPrivilege) LoadAll(ctx sessionctx.Context) error {
+	err := p.LoadUserTable(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = p.LoadDBTable(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = p.LoadTablesPrivTable(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// LoadUserTable loads the mysql.user table from database.
+func (p *MySQLPrivilege) LoadUserTable(ctx sessionctx.Context) error {
+	err := p.loadTable(ctx, "select HIGH_PRIORITY * from mysql.user;", p.decodeUserTableRow)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// See https://dev.mysql.com/doc/refman/8.0/en/connection-access.html
+	// When a connection is attempted, the server looks through the rows in sorted order.
+	// The server uses the first row that matches the client host name and user name.
+	sort.Slice(p.User, func(i, j int) bool {
+		return compareBaseRecord(&p.User[i].baseRecord, &p.User[j].baseRecord)
+	})
+	return nil
+}
+
+// LoadDBTable loads the mysql.db table from database.
+func (p *MySQLPrivilege) LoadDBTable(ctx sessionctx.Context) error {
+	err := p.loadTable(ctx, "select HIGH_PRIORITY * from mysql.db;", p.decodeDBTableRow)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sort.Slice(p.DB, func(i, j int) bool {
+		return compareBaseRecord(&p.DB[i].baseRecord, &p.DB[j].baseRecord)
+	})
+	return nil
+}
+
+// LoadTablesPrivTable loads the mysql.tables_priv table from database.
+func (p *MySQLPrivilege) LoadTablesPrivTable(ctx sessionctx.Context) error {
+	return p.loadTable(ctx, "select HIGH_PRIORITY Host,DB,User,Table_name,Grantor,Table_priv from mysql.tables_priv", p.decodeTablesPrivTableRow)
+}
+
+func (p *MySQLPrivilege) loadTable(sctx sessionctx.Context, sql string,
+	decodeTableRow func(chunk.Row, []*ast.ResultField) error) error {
+	rows, fields, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(sql)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, row := range rows {
+		err = decodeTableRow(row, fields)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField) error {
+	var value UserRecord
+	for i, f := range fs {
+		switch {
+		case f.ColumnAsName.L == "authentication_string":
+			value.AuthenticationString = row.GetString(i)
+		case f.ColumnAsName.L == "plugin":
+			if row.GetString(i) != "" {
+				value.AuthPlugin = row.GetString(i)
+			} else {
+				value.AuthPlugin = mysql.AuthNativePassword
+			}
+		case f.ColumnAsName.L == "user" || f.ColumnAsName.L == "host":
+			value.assignBaseRecord(f.ColumnAsName.L, row.GetString(i))
+		default:
+			priv, ok := mysql.Col2PrivType[f.ColumnAsName.O]
+			if ok && row.GetString(i) == "Y" {
+				value.Privileges |= priv
+			}
+		}
+	}
+	p.User = append(p.User, value)
+	return nil
+}
+
+func (p *MySQLPrivilege) decodeDBTableRow(row chunk.Row, fs []*ast.ResultField) error {
+	var value dbRecord
+	for i, f := range fs {
+		switch {
+		case f.ColumnAsName.L == "db":
+			value.DB = row.GetString(i)
+		case f.ColumnAsName.L == "user" || f.ColumnAsName.L == "host":
+			value.assignBaseRecord(f.ColumnAsName.L, row.GetString(i))
+		default:
+			priv, ok := mysql.Col2PrivType[f.ColumnAsName.O]
+			if ok && row.GetString(i) == "Y" {
+				value.Privileges |= priv
+			}
+		}
+	}
+	p.DB = append(p.DB, value)
+	return nil
+}
+
+func (p *MySQLPrivilege) decodeTablesPrivTableRow(row chunk.Row, fs []*ast.ResultField) error {
+	var value tablesPrivRecord
+	for i, f := range fs {
+		switch {
+		case f.ColumnAsName.L == "db":
+			value.DB = row.GetString(i)
+		case f.ColumnAsName.L == "table_name":
+			value.TableName = row.GetString(i)
+		case f.ColumnAsName.L == "grantor":
+			value.Grantor = row.GetString(i)
+		case f.ColumnAsName.L == "table_priv":
+			value.TablePriv = decodeSetToPrivilege(row.GetString(i))
+		case f.ColumnAsName.L == "user" || f.ColumnAsName.L == "host":
+			value.assignBaseRecord(f.ColumnAsName.L, row.GetString(i))
+		}
+	}
+	p.TablesPriv = append(p.TablesPriv, value)
+	return nil
+}
+
+func (record *baseRecord) assignBaseRecord(name, value string) {
+	switch name {
+	case "user":
+		record.User = value
+	case "host":
+		record.Host = value
+		record.patChars, record.patTypes = stringutil.CompilePattern(value, '\\')
+	}
+}
+
+func (record *baseRecord) match(user, host string) bool {
+	return record.User == user && stringutil.DoMatch(host, record.patChars, record.patTypes)
+}
+
+// decodeSetToPrivilege decodes a comma separated privilege list, such as 'Select,Insert'.
+func decodeSetToPrivilege(s string) mysql.PrivilegeType {
+	var ret mysql.PrivilegeType
+	if s == "" {
+		return ret
+	}
+	for _, str := range strings.Split(s, ",") {
+		priv, ok := mysql.SetStr2Priv[str]
+		if ok {
+			ret |= priv
+		}
+	}
+	return ret
+}
+
+// compareBaseRecord orders the more specific records first.
+func compareBaseRecord(x, y *baseRecord) bool {
+	c := compareHost(x.Host, y.Host)
+	if c != 0 {
+		return c < 0
+	}
+	// The anonymous user '' sorts after the named users.
+	return x.User > y.User
+}
+
+// compareHost compares two host patterns, the more specific one is smaller.
+// The pattern '%' means any host and is the least specific one.
+func compareHost(x, y string) int {
+	xWild := strings.ContainsAny(x, "%_")
+	yWild := strings.ContainsAny(y, "%_")
+	switch {
+	case x == y:
+		return 0
+	case x == "%":
+		return 1
+	case y == "%":
+		return -1
+	case !xWild && yWild:
+		return -1
+	case xWild && !yWild:
+		return 1
+	case xWild && yWild:
+		// 192.168.1.% is more specific than 192.168.%.
+		if len(x) != len(y) {
+			if len(x) > len(y) {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(x, y)
+}
+
+// connectionVerification returns the first user record which matches the user and host.
+func (p *MySQLPrivilege) connectionVerification(user, host string) *UserRecord {
+	for i := 0; i < len(p.User); i++ {
+		record := &p.User[i]
+		if record.match(user, host) {
+			return record
+		}
+	}
+	return nil
+}
+
+func (p *MySQLPrivilege) matchUser(user, host string) *UserRecord {
+	return p.connectionVerification(user, host)
+}
+
+func (p *MySQLPrivilege) matchDB(user, host, db string) *dbRecord {
+	for i := 0; i < len(p.DB); i++ {
+		record := &p.DB[i]
+		if record.match(user, host) && strings.EqualFold(record.DB, db) {
+			return record
+		}
+	}
+	return nil
+}
+
+func (p *MySQLPrivilege) matchTables(user, host, db, table string) *tablesPrivRecord {
+	for i := 0; i < len(p.TablesPriv); i++ {
+		record := &p.TablesPriv[i]
+		if record.match(user, host) &&
+			strings.EqualFold(record.DB, db) &&
+			strings.EqualFold(record.TableName, table) {
+			return record
+		}
+	}
+	return nil
+}
+
+// RequestVerification checks whether the user have sufficient privileges to do the operation.
+func (p *MySQLPrivilege) RequestVerification(user, host, db, table, column string, priv mysql.PrivilegeType) bool {
+	record1 := p.matchUser(user, host)
+	if record1 != nil && record1.Privileges&priv > 0 {
+		return true
+	}
+
+	record2 := p.matchDB(user, host, db)
+	if record2 != nil && record2.Privileges&priv > 0 {
+		return true
+	}
+
+	record3 := p.matchTables(user, host, db, table)
+	if record3 != nil && record3.TablePriv&priv > 0 {
+		return true
+	}
+
+	return false
+}
+
+// DBIsVisible checks whether the user can see the db.
+func (p *MySQLPrivilege) DBIsVisible(user, host, db string) bool {
+	if record := p.matchUser(user, host); record != nil {
+		if record.Privileges != 0 {
+			return true
+		}
+	}
+
+	// INFORMATION_SCHEMA is visible to all users.
+	if strings.EqualFold(db, "INFORMATION_SCHEMA") {
+		return true
+	}
+
+	if record := p.matchDB(user, host, db); record != nil {
+		if record.Privileges > 0 {
+			return true
+		}
+	}
+
+	for _, record := range p.TablesPriv {
+		if record.match(user, host) && strings.EqualFold(record.DB, db) && record.TablePriv != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *MySQLPrivilege) showGrants(user, host string) []string {
+	var gs []string
+	// Show global grants.
+	var userExists bool
+	for _, record := range p.User {
+		if record.User == user && record.Host == host {
+			userExists = true
+			gs = append(gs, fmt.Sprintf(`GRANT %s ON *.* TO '%s'@'%s'%s`,
+				privilegeToString(record.Privileges, mysql.AllGlobalPrivs), user, host, withGrantOption(record.Privileges)))
+			break
+		}
+	}
+	if !userExists {
+		return gs
+	}
+
+	// Show db scope grants.
+	for _, record := range p.DB {
+		if record.User == user && record.Host == host && record.Privileges != 0 {
+			gs = append(gs, fmt.Sprintf("GRANT %s ON `%s`.* TO '%s'@'%s'%s",
+				privilegeToString(record.Privileges, mysql.AllDBPrivs), record.DB, user, host, withGrantOption(record.Privileges)))
+		}
+	}
+
+	// Show table scope grants.
+	for _, record := range p.TablesPriv {
+		if record.User == user && record.Host == host && record.TablePriv != 0 {
+			gs = append(gs, fmt.Sprintf("GRANT %s ON `%s`.`%s` TO '%s'@'%s'%s",
+				privilegeToString(record.TablePriv, mysql.AllTablePrivs), record.DB, record.TableName, user, host, withGrantOption(record.TablePriv)))
+		}
+	}
+	return gs
+}
+
+// privilegeToString converts the privileges to the string used by SHOW GRANTS.
+// allPrivs is all the privileges of the scope, if all of them are granted, "ALL PRIVILEGES" is returned.
+func privilegeToString(priv mysql.PrivilegeType, allPrivs []mysql.PrivilegeType) string {
+	var privs []string
+	hasAll := true
+	for _, p := range allPrivs {
+		if priv&p == 0 {
+			hasAll = false
+			continue
+		}
+		privs = append(privs, mysql.Priv2Str[p])
+	}
+	if hasAll {
+		return mysql.AllPrivilegeLiteral
+	}
+	if len(privs) == 0 {
+		return "USAGE"
+	}
+	return strings.Join(privs, ",")
+}
+
+func withGrantOption(priv mysql.PrivilegeType) string {
+	if priv&mysql.GrantPriv > 0 {
+		return " WITH GRANT OPTION"
+	}
+	return ""
+}
+
+// Handle wraps MySQLPrivilege providing thread safe access.
+type Handle struct {
+	priv atomic.Value
+}
+
+// NewHandle returns a Handle.
+func NewHandle() *Handle {
+	return &Handle{}
+}
+
+// Get the MySQLPrivilege for read.
+func (h *Handle) Get() *MySQLPrivilege {
+	if v, ok := h.priv.Load().(*MySQLPrivilege); ok {
+		return v
+	}
+	return &MySQLPrivilege{}
+}
+
+// Update loads all the privilege info from kv storage.
+func (h *Handle) Update(ctx sessionctx.Context) error {
+	var priv MySQLPrivilege
+	err := priv.LoadAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	h.priv.Store(&priv)
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+var _ privilege.Manager = (*UserPrivileges)(nil)
+
+var errNonexistingGrant = terror.ClassPrivilege.New(mysql.ErrNonexistingGrant, mysql.MySQLErrName[mysql.ErrNonexistingGrant])
+
+// UserPrivileges implements privilege.Manager interface.
+// This is used to check privilege for the current user.
+type UserPrivileges struct {
+	user string
+	host string
+	*Handle
+}
+
+// RequestVerification implements the Manager interface.
+func (p *UserPrivileges) RequestVerification(db, table, column string, priv mysql.PrivilegeType) bool {
+	// If the user and host are empty, the session is an internal one or it is not authenticated
+	// through the handshake, such as the ones used in bootstrap and tests, skip the check.
+	if p.user == "" && p.host == "" {
+		return true
+	}
+
+	// Skip check for INFORMATION_SCHEMA database.
+	if strings.EqualFold(db, "INFORMATION_SCHEMA") {
+		return true
+	}
+
+	mysqlPriv := p.Handle.Get()
+	return mysqlPriv.RequestVerification(p.user, p.host, db, table, column, priv)
+}
+
+// GetAuthPlugin implements the Manager interface.
+func (p *UserPrivileges) GetAuthPlugin(user, host string) (string, error) {
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.connectionVerification(user, host)
+	if record == nil {
+		return "", errors.New("Failed to get user record")
+	}
+	return record.AuthPlugin, nil
+}
+
+// ConnectionVerification implements the Manager interface.
+func (p *UserPrivileges) ConnectionVerification(user, host string, authentication, salt []byte) (u string, h string, success bool) {
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.connectionVerification(user, host)
+	if record == nil {
+		logutil.BgLogger().Error("get user privilege record fail",
+			zap.String("user", user), zap.String("host", host))
+		return
+	}
+
+	u = record.User
+	h = record.Host
+
+	pwd := record.AuthenticationString
+	switch record.AuthPlugin {
+	case mysql.AuthCachingSha2Password:
+		ok, err := auth.CheckShaPassword([]byte(pwd), string(authentication))
+		if err != nil {
+			logutil.BgLogger().Error("check caching_sha2_password failed", zap.Error(err))
+			return
+		}
+		if !ok {
+			return
+		}
+	default:
+		if len(pwd) > 0 && len(pwd) != mysql.PWDHashLen+1 {
+			logutil.BgLogger().Error("user password from system DB not like sha1sum", zap.String("user", user))
+			return
+		}
+
+		// empty password
+		if len(pwd) == 0 && len(authentication) == 0 {
+			p.user = user
+			p.host = h
+			success = true
+			return
+		}
+
+		if len(pwd) == 0 || len(authentication) == 0 {
+			return
+		}
+
+		hpwd, err := auth.DecodePassword(pwd)
+		if err != nil {
+			logutil.BgLogger().Error("decode password string failed", zap.Error(err))
+			return
+		}
+
+		if !auth.CheckScrambledPassword(salt, hpwd, authentication) {
+			return
+		}
+	}
+
+	p.user = user
+	p.host = h
+	success = true
+	return
+}
+
+// DBIsVisible implements the Manager interface.
+func (p *UserPrivileges) DBIsVisible(db string) bool {
+	if p.user == "" && p.host == "" {
+		return true
+	}
+	mysqlPriv := p.Handle.Get()
+	return mysqlPriv.DBIsVisible(p.user, p.host, db)
+}
+
+// ShowGrants implements privilege.Manager ShowGrants interface.
+func (p *UserPrivileges) ShowGrants(ctx sessionctx.Context, user *auth.UserIdentity) (grants []string, err error) {
+	u := user.Username
+	h := user.Hostname
+	if len(user.AuthUsername) > 0 && len(user.AuthHostname) > 0 {
+		u = user.AuthUsername
+		h = user.AuthHostname
+	}
+	mysqlPrivilege := p.Handle.Get()
+	grants = mysqlPrivilege.showGrants(u, h)
+	if len(grants) == 0 {
+		err = errNonexistingGrant.GenWithStackByArgs(u, h)
+	}
+	return
+}
@@ -0,0 +1,292 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges_test
+
+import (
+	"crypto/sha1"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func TestT(t *testing.T) {
+	CustomVerboseFlag = true
+	TestingT(t)
+}
+
+var _ = Suite(&testPrivilegeSuite{})
+
+type testPrivilegeSuite struct {
+	store kv.Storage
+	dom   *domain.Domain
+}
+
+func (s *testPrivilegeSuite) SetUpSuite(c *C) {
+	testleak.BeforeTest()
+	session.SetSchemaLease(0)
+	session.DisableStats4Test()
+	store, err := mockstore.NewMockTikvStore()
+	c.Assert(err, IsNil)
+	s.store = store
+	s.dom, err = session.BootstrapSession(store)
+	c.Assert(err, IsNil)
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create database if not exists test")
+	tk.MustExec("create table test.priv_t (a int primary key, b int)")
+	tk.MustExec("insert into test.priv_t values (1, 1)")
+}
+
+func (s *testPrivilegeSuite) TearDownSuite(c *C) {
+	s.dom.Close()
+	s.store.Close()
+	testleak.AfterTest(c)()
+}
+
+// newUserTestKit returns a TestKit whose session is authenticated as user@localhost without password.
+func (s *testPrivilegeSuite) newUserTestKit(c *C, user string) *testkit.TestKit {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: user, Hostname: "localhost"}, nil, nil), IsTrue)
+	return tk
+}
+
+// scramble computes the mysql_native_password auth response of the client.
+func scramble(password string, salt []byte) []byte {
+	stage1 := sha1.Sum([]byte(password))
+	stage2 := sha1.Sum(stage1[:])
+	h := sha1.New()
+	h.Write(salt)
+	h.Write(stage2[:])
+	reply := h.Sum(nil)
+	for i := range reply {
+		reply[i] ^= stage1[i]
+	}
+	return reply
+}
+
+func (s *testPrivilegeSuite) TestConnectionVerification(c *C) {
+	rootTk := testkit.NewTestKit(c, s.store)
+	rootTk.MustExec(`CREATE USER 'pwd_native'@'localhost' IDENTIFIED BY '123'`)
+	rootTk.MustExec(`CREATE USER 'pwd_sha2'@'%' IDENTIFIED WITH 'caching_sha2_password' BY '123'`)
+	rootTk.MustExec(`CREATE USER 'no_pwd'@'%'`)
+
+	salt := []byte("0123456789abcdefghij")
+	se := testkit.NewTestKitWithInit(c, s.store).Se
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "pwd_native", Hostname: "localhost"}, scramble("123", salt), salt), IsTrue)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "pwd_native", Hostname: "localhost"}, scramble("456", salt), salt), IsFalse)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "pwd_native", Hostname: "localhost"}, nil, salt), IsFalse)
+	// The host doesn't match.
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "pwd_native", Hostname: "10.0.0.1"}, scramble("123", salt), salt), IsFalse)
+
+	// caching_sha2_password receives the password in clear text.
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "pwd_sha2", Hostname: "10.0.0.1"}, []byte("123"), salt), IsTrue)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "pwd_sha2", Hostname: "10.0.0.1"}, []byte("456"), salt), IsFalse)
+	plugin, err := se.AuthPluginForUser(&auth.UserIdentity{Username: "pwd_sha2", Hostname: "10.0.0.1"})
+	c.Assert(err, IsNil)
+	c.Assert(plugin, Equals, mysql.AuthCachingSha2Password)
+
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "no_pwd", Hostname: "localhost"}, nil, salt), IsTrue)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "no_pwd", Hostname: "localhost"}, scramble("123", salt), salt), IsFalse)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "not_exist", Hostname: "localhost"}, nil, salt), IsFalse)
+	// The matched account is recorded in the session.
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "no_pwd", Hostname: "localhost"}, nil, salt), IsTrue)
+	user := se.GetSessionVars().User
+	c.Assert(user.AuthUsername, Equals, "no_pwd")
+	c.Assert(user.AuthHostname, Equals, "%")
+}
+
+func (s *testPrivilegeSuite) TestCheckTablePrivilege(c *C) {
+	rootTk := testkit.NewTestKit(c, s.store)
+	rootTk.MustExec(`CREATE USER 'tbl_user'@'localhost'`)
+
+	tk := s.newUserTestKit(c, "tbl_user")
+	tk.MustExec("use information_schema")
+	err := tk.ExecToErr("select * from test.priv_t")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+
+	rootTk.MustExec(`GRANT SELECT ON test.priv_t TO 'tbl_user'@'localhost'`)
+	tk.MustQuery("select * from test.priv_t").Check(testkit.Rows("1 1"))
+	err = tk.ExecToErr("update test.priv_t set b = 2")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("insert into test.priv_t values (2, 2)")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+
+	rootTk.MustExec(`GRANT UPDATE, INSERT ON test.priv_t TO 'tbl_user'@'localhost'`)
+	tk.MustExec("update test.priv_t set b = 2 where a = 1")
+	tk.MustExec("insert into test.priv_t values (2, 2)")
+	// REPLACE needs DELETE as well.
+	err = tk.ExecToErr("replace into test.priv_t values (2, 3)")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("delete from test.priv_t where a = 2")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+
+	rootTk.MustExec(`GRANT DELETE ON test.priv_t TO 'tbl_user'@'localhost'`)
+	tk.MustExec("delete from test.priv_t where a = 2")
+	tk.MustExec("update test.priv_t set b = 1 where a = 1")
+
+	rootTk.MustExec(`REVOKE SELECT ON test.priv_t FROM 'tbl_user'@'localhost'`)
+	err = tk.ExecToErr("select * from test.priv_t")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+	// Information schema can always be read.
+	tk.MustQuery("select count(*) > 0 from information_schema.tables").Check(testkit.Rows("1"))
+}
+
+func (s *testPrivilegeSuite) TestCheckDBPrivilege(c *C) {
+	rootTk := testkit.NewTestKit(c, s.store)
+	rootTk.MustExec(`CREATE USER 'db_user'@'localhost'`)
+	rootTk.MustExec("create database if not exists priv_db")
+
+	tk := s.newUserTestKit(c, "db_user")
+	err := tk.ExecToErr("use priv_db")
+	c.Assert(terror.ErrorEqual(executor.ErrDBaccessDenied, err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("create table priv_db.t (a int)")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("show databases").Check(testkit.Rows("INFORMATION_SCHEMA"))
+
+	rootTk.MustExec(`GRANT CREATE, SELECT ON priv_db.* TO 'db_user'@'localhost'`)
+	tk.MustExec("use priv_db")
+	tk.MustQuery("show databases").Check(testkit.Rows("INFORMATION_SCHEMA", "priv_db"))
+	tk.MustExec("create table t (a int)")
+	tk.MustQuery("select * from t").Check(testkit.Rows())
+	err = tk.ExecToErr("drop table t")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("create index idx on t (a)")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("drop database priv_db")
+	c.Assert(terror.ErrorEqual(core.ErrDBaccessDenied, err), IsTrue, Commentf("err %v", err))
+
+	rootTk.MustExec(`GRANT DROP ON priv_db.* TO 'db_user'@'localhost'`)
+	tk.MustExec("drop table t")
+	tk.MustExec("drop database priv_db")
+}
+
+func (s *testPrivilegeSuite) TestCheckGlobalPrivilege(c *C) {
+	rootTk := testkit.NewTestKit(c, s.store)
+	rootTk.MustExec(`CREATE USER 'global_user'@'localhost'`)
+
+	tk := s.newUserTestKit(c, "global_user")
+	err := tk.ExecToErr(`CREATE USER 'another'@'localhost'`)
+	c.Assert(terror.ErrorEqual(core.ErrPrivilegeCheckFail, err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("admin show ddl")
+	c.Assert(terror.ErrorEqual(core.ErrPrivilegeCheckFail, err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("set global tidb_gc_life_time = '20m'")
+	c.Assert(terror.ErrorEqual(core.ErrPrivilegeCheckFail, err), IsTrue, Commentf("err %v", err))
+	// The session variables and the user variables can always be set.
+	tk.MustExec("set @@session.tidb_txn_mode = 'pessimistic', @a = 1")
+
+	rootTk.MustExec(`GRANT CREATE USER, SUPER ON *.* TO 'global_user'@'localhost'`)
+	tk.MustExec(`CREATE USER 'another'@'localhost'`)
+	tk.MustExec(`DROP USER 'another'@'localhost'`)
+	tk.MustExec("admin show ddl")
+	tk.MustExec("set global tidb_gc_life_time = '20m'")
+	tk.MustQuery("select @@global.tidb_gc_life_time").Check(testkit.Rows("20m"))
+	tk.MustExec("set global tidb_gc_life_time = '10m0s'")
+
+	// GRANT needs the GRANT OPTION and the privileges to grant.
+	rootTk.MustExec(`CREATE USER 'another'@'localhost'`)
+	err = tk.ExecToErr(`GRANT SUPER ON *.* TO 'another'@'localhost'`)
+	c.Assert(terror.ErrorEqual(core.ErrPrivilegeCheckFail, err), IsTrue, Commentf("err %v", err))
+	rootTk.MustExec(`GRANT GRANT OPTION ON *.* TO 'global_user'@'localhost'`)
+	tk.MustExec(`GRANT SUPER ON *.* TO 'another'@'localhost'`)
+	err = tk.ExecToErr(`GRANT SELECT ON *.* TO 'another'@'localhost'`)
+	c.Assert(terror.ErrorEqual(core.ErrPrivilegeCheckFail, err), IsTrue, Commentf("err %v", err))
+	rootTk.MustExec(`DROP USER 'another'@'localhost'`)
+}
+
+func (s *testPrivilegeSuite) TestShowAndAnalyzePrivilege(c *C) {
+	rootTk := testkit.NewTestKit(c, s.store)
+	rootTk.MustExec(`CREATE USER 'meta_user'@'localhost'`)
+	rootTk.MustExec(`GRANT SELECT ON test.* TO 'meta_user'@'localhost'`)
+	rootTk.MustExec("create database if not exists priv_other")
+	rootTk.MustExec("create table priv_other.o (a int)")
+	rootTk.MustExec("create table priv_other.p (a int)")
+	rootTk.MustExec("create view priv_other.v as select a from priv_other.o")
+
+	tk := s.newUserTestKit(c, "meta_user")
+	err := tk.ExecToErr("analyze table priv_other.o")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("show create table priv_other.o")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("show create view priv_other.v")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("select count(*) from information_schema.tables where table_schema = 'priv_other'").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) from information_schema.columns where table_schema = 'priv_other'").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) from information_schema.schemata where schema_name = 'priv_other'").Check(testkit.Rows("0"))
+	// The granted tables are still listed.
+	tk.MustQuery("select table_name from information_schema.tables where table_schema = 'test' and table_name = 'priv_t'").Check(testkit.Rows("priv_t"))
+
+	rootTk.MustExec(`GRANT SELECT ON priv_other.o TO 'meta_user'@'localhost'`)
+	tk.MustQuery("show create table priv_other.o").Check(testkit.Rows("o CREATE TABLE `o` (\n  `a` int(11) DEFAULT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustQuery("select table_name from information_schema.tables where table_schema = 'priv_other'").Check(testkit.Rows("o"))
+	tk.MustQuery("show tables in priv_other").Check(testkit.Rows("o"))
+	err = tk.ExecToErr("show create table priv_other.p")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+	// ANALYZE needs INSERT as well.
+	err = tk.ExecToErr("analyze table priv_other.o")
+	c.Assert(terror.ErrorEqual(core.ErrTableaccessDenied, err), IsTrue, Commentf("err %v", err))
+	rootTk.MustExec(`GRANT INSERT ON priv_other.o TO 'meta_user'@'localhost'`)
+	tk.MustExec("analyze table priv_other.o")
+}
+
+func (s *testPrivilegeSuite) TestShowGrants(c *C) {
+	rootTk := testkit.NewTestKit(c, s.store)
+	rootTk.MustExec(`CREATE USER 'show_user'@'localhost'`)
+	rootTk.MustQuery(`SHOW GRANTS FOR 'show_user'@'localhost'`).Check(testkit.Rows("GRANT USAGE ON *.* TO 'show_user'@'localhost'"))
+
+	rootTk.MustExec(`GRANT SELECT, INSERT ON *.* TO 'show_user'@'localhost'`)
+	rootTk.MustExec(`GRANT ALL ON test.* TO 'show_user'@'localhost' WITH GRANT OPTION`)
+	rootTk.MustExec(`GRANT UPDATE ON test.priv_t TO 'show_user'@'localhost'`)
+	rootTk.MustQuery(`SHOW GRANTS FOR 'show_user'@'localhost'`).Check(testkit.Rows(
+		"GRANT Select,Insert ON *.* TO 'show_user'@'localhost'",
+		"GRANT ALL PRIVILEGES ON `test`.* TO 'show_user'@'localhost' WITH GRANT OPTION",
+		"GRANT Update ON `test`.`priv_t` TO 'show_user'@'localhost'",
+	))
+
+	tk := s.newUserTestKit(c, "show_user")
+	tk.MustQuery("SHOW GRANTS").Check(testkit.Rows(
+		"GRANT Select,Insert ON *.* TO 'show_user'@'localhost'",
+		"GRANT ALL PRIVILEGES ON `test`.* TO 'show_user'@'localhost' WITH GRANT OPTION",
+		"GRANT Update ON `test`.`priv_t` TO 'show_user'@'localhost'",
+	))
+	c.Assert(tk.MustQuery("SHOW GRANTS FOR CURRENT_USER()").Rows(), HasLen, 3)
+
+	// The grants of the other users are in the mysql database, which can be read with the global SELECT.
+	tk.MustQuery("SHOW GRANTS FOR 'root'@'%'").Check(testkit.Rows("GRANT ALL PRIVILEGES ON *.* TO 'root'@'%' WITH GRANT OPTION"))
+	rootTk.MustExec(`REVOKE SELECT ON *.* FROM 'show_user'@'localhost'`)
+	err := tk.ExecToErr("SHOW GRANTS FOR 'root'@'%'")
+	c.Assert(terror.ErrorEqual(core.ErrPrivilegeCheckFail, err), IsTrue, Commentf("err %v", err))
+
+	err = rootTk.QueryToErr("SHOW GRANTS FOR 'not_exist'@'%'")
+	c.Assert(err, NotNil)
+}
+
+func (s *testPrivilegeSuite) TestInternalSessionSkipsCheck(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	pm := privilege.GetPrivilegeManager(tk.Se)
+	c.Assert(pm, NotNil)
+	c.Assert(pm.RequestVerification("mysql", "user", "", mysql.SuperPriv), IsTrue)
+	c.Assert(pm.DBIsVisible("mysql"), IsTrue)
+}
@@ -37,8 +37,13 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/binary"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"net"
@@ -52,6 +57,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -244,6 +250,7 @@ type handshakeResponse41 struct {
 	User       string
 	DBName     string
 	Auth       []byte
+	AuthPlugin string
 	Attrs      map[string]string
 }
 
@@ -370,9 +377,11 @@ func parseHandshakeResponseBody(ctx context.Context, packet *handshakeResponse41
 	}
 
 	if packet.Capability&mysql.ClientPluginAuth > 0 {
-		// TODO: Support mysql.ClientPluginAuth, skip it now
 		idx := bytes.IndexByte(data[offset:], 0)
-		offset = offset + idx + 1
+		if idx != -1 {
+			packet.AuthPlugin = string(data[offset : offset+idx])
+			offset = offset + idx + 1
+		}
 	}
 
 	if packet.Capability&mysql.ClientConnectAtts > 0 {
@@ -480,7 +489,7 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 	cc.collation = resp.Collation
 	cc.attrs = resp.Attrs
 
-	err = cc.openSessionAndDoAuth()
+	err = cc.openSessionAndDoAuth(resp.Auth, resp.AuthPlugin)
 	return err
 }
 
@@ -498,7 +507,7 @@ func (cc *clientConn) SessionStatusToString() string {
 	)
 }
 
-func (cc *clientConn) openSessionAndDoAuth() error {
+func (cc *clientConn) openSessionAndDoAuth(authData []byte, authPlugin string) error {
 	var tlsStatePtr *tls.ConnectionState
 	if cc.tlsConn != nil {
 		tlsState := cc.tlsConn.ConnectionState()
@@ -509,6 +518,39 @@ func (cc *clientConn) openSessionAndDoAuth() error {
 	if err != nil {
 		return err
 	}
+	cc.ctx.SetSessionManager(cc.server)
+
+	hasPassword := "YES"
+	if len(authData) == 0 {
+		hasPassword = "NO"
+	}
+	host, err := cc.PeerHost(hasPassword)
+	if err != nil {
+		return err
+	}
+	userIdentity := &auth.UserIdentity{Username: cc.user, Hostname: host}
+	// If the user doesn't exist, go on with mysql_native_password and let the authentication fail.
+	userPlugin, err := cc.ctx.AuthPluginForUser(userIdentity)
+	if err != nil || userPlugin == "" {
+		userPlugin = mysql.AuthNativePassword
+	}
+	if authPlugin != "" && authPlugin != userPlugin {
+		// The client used another plugin in the handshake, ask it to switch to the plugin of the user.
+		authData, err = cc.authSwitchRequest(userPlugin)
+		if err != nil {
+			return err
+		}
+	}
+	if userPlugin == mysql.AuthCachingSha2Password && len(authData) > 0 {
+		authData, err = cc.cachingSha2FullAuth()
+		if err != nil {
+			return err
+		}
+	}
+	if !cc.ctx.Auth(userIdentity, authData, cc.salt) {
+		return errAccessDenied.GenWithStackByArgs(cc.user, host, hasPassword)
+	}
+
 	if cc.dbname != "" {
 		err = cc.useDB(context.Background(), cc.dbname)
 		if err != nil {
@@ -518,6 +560,76 @@ func (cc *clientConn) openSessionAndDoAuth() error {
 	return nil
 }
 
+// authSwitchRequest asks the client to authenticate with the given plugin, and returns its new auth data.
+// See https://dev.mysql.com/doc/internals/en/connection-phase-packets.html#packet-Protocol::AuthSwitchRequest
+func (cc *clientConn) authSwitchRequest(plugin string) ([]byte, error) {
+	data := cc.alloc.AllocWithLen(4, 32)
+	data = append(data, mysql.AuthSwitchRequest)
+	data = append(data, []byte(plugin)...)
+	data = append(data, 0)
+	data = append(data, cc.salt...)
+	data = append(data, 0)
+	if err := cc.writePacket(data); err != nil {
+		return nil, err
+	}
+	if err := cc.flush(); err != nil {
+		return nil, err
+	}
+	return cc.readPacket()
+}
+
+// cachingSha2FullAuth performs the full authentication of caching_sha2_password, the server doesn't
+// cache the password hashes, so the fast authentication is never used. It returns the clear text password.
+// See https://dev.mysql.com/doc/dev/mysql-server/latest/page_caching_sha2_authentication_exchanges.html
+func (cc *clientConn) cachingSha2FullAuth() ([]byte, error) {
+	data := cc.alloc.AllocWithLen(4, 4+2)
+	data = append(data, mysql.AuthMoreData, mysql.AuthPerformFullAuthentication)
+	if err := cc.writePacket(data); err != nil {
+		return nil, err
+	}
+	if err := cc.flush(); err != nil {
+		return nil, err
+	}
+	resp, err := cc.readPacket()
+	if err != nil {
+		return nil, err
+	}
+
+	// Without TLS, the client requests the public key of the server to encrypt the password.
+	if cc.tlsConn == nil && len(resp) == 1 && resp[0] == mysql.AuthRequestPublicKey {
+		key, err := cc.server.rsaKey()
+		if err != nil {
+			return nil, err
+		}
+		pubKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		data = cc.alloc.AllocWithLen(4, 512)
+		data = append(data, mysql.AuthMoreData)
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubKey})...)
+		if err = cc.writePacket(data); err != nil {
+			return nil, err
+		}
+		if err = cc.flush(); err != nil {
+			return nil, err
+		}
+		resp, err = cc.readPacket()
+		if err != nil {
+			return nil, err
+		}
+		resp, err = rsa.DecryptOAEP(sha1.New(), rand.Reader, key, resp, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// The password is XORed with the salt before it is encrypted.
+		for i := range resp {
+			resp[i] ^= cc.salt[i%len(cc.salt)]
+		}
+	}
+	return bytes.TrimSuffix(resp, []byte{0}), nil
+}
+
 func (cc *clientConn) PeerHost(hasPassword string) (host string, err error) {
 	if len(cc.peerHost) > 0 {
 		return cc.peerHost, nil
@@ -703,6 +815,18 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 		return cc.writeOK()
 	case mysql.ComFieldList:
 		return cc.handleFieldList(dataStr)
+	case mysql.ComStmtPrepare:
+		return cc.handleStmtPrepare(dataStr)
+	case mysql.ComStmtExecute:
+		return cc.handleStmtExecute(ctx, data)
+	case mysql.ComStmtFetch:
+		return cc.handleStmtFetch(ctx, data)
+	case mysql.ComStmtClose:
+		return cc.handleStmtClose(data)
+	case mysql.ComStmtSendLongData:
+		return cc.handleStmtSendLongData(data)
+	case mysql.ComStmtReset:
+		return cc.handleStmtReset(data)
 	default:
 		return mysql.NewErrf(mysql.ErrUnknown, "command %d not supported now", cmd)
 	}
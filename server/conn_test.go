@@ -17,16 +17,26 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
 	"encoding/binary"
+	"encoding/pem"
+	"net"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/util/arena"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -113,6 +123,7 @@ func (ts *ConnTestSuite) TestParseHandshakeResponse(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(p.User, Equals, "pam")
 	c.Assert(p.DBName, Equals, "test")
+	c.Assert(p.AuthPlugin, Equals, mysql.AuthNativePassword)
 
 	// Test for compatibility of Protocol::HandshakeResponse320
 	data = []byte{
@@ -190,6 +201,100 @@ func (ts *ConnTestSuite) TestInitialHandshake(c *C) {
 	c.Assert(outBuffer.Bytes()[4:], DeepEquals, expected.Bytes())
 }
 
+// mockSha2Client plays the client side of caching_sha2_password over an unencrypted connection:
+// it follows the auth switch, requests the public key of the server and sends the encrypted password.
+func mockSha2Client(conn net.Conn, salt []byte, password string) error {
+	pkt := newPacketIO(newBufferedReadConn(conn))
+	writePacket := func(payload []byte) error {
+		data := make([]byte, 4, 4+len(payload))
+		if err := pkt.writePacket(append(data, payload...)); err != nil {
+			return err
+		}
+		return pkt.flush()
+	}
+
+	// AuthSwitchRequest, reply with the fast authentication scramble, which is not checked.
+	data, err := pkt.readPacket()
+	if err != nil {
+		return err
+	}
+	if data[0] != mysql.AuthSwitchRequest || !bytes.HasPrefix(data[1:], []byte(mysql.AuthCachingSha2Password)) {
+		return errors.Errorf("unexpected auth switch request %v", data)
+	}
+	if err = writePacket(make([]byte, 32)); err != nil {
+		return err
+	}
+	// Perform full authentication, request the public key.
+	if data, err = pkt.readPacket(); err != nil {
+		return err
+	}
+	if !bytes.Equal(data, []byte{mysql.AuthMoreData, mysql.AuthPerformFullAuthentication}) {
+		return errors.Errorf("unexpected full authentication request %v", data)
+	}
+	if err = writePacket([]byte{mysql.AuthRequestPublicKey}); err != nil {
+		return err
+	}
+	if data, err = pkt.readPacket(); err != nil {
+		return err
+	}
+	block, _ := pem.Decode(data[1:])
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+	plain := append([]byte(password), 0)
+	for i := range plain {
+		plain[i] ^= salt[i%len(salt)]
+	}
+	enc, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, pub.(*rsa.PublicKey), plain, nil)
+	if err != nil {
+		return err
+	}
+	return writePacket(enc)
+}
+
+func (ts *ConnTestSuite) TestCachingSha2Auth(c *C) {
+	se, err := session.CreateSession4Test(ts.store)
+	c.Assert(err, IsNil)
+	_, err = se.Execute(context.Background(), "CREATE USER 'sha2user'@'%' IDENTIFIED WITH 'caching_sha2_password' BY '123'")
+	c.Assert(err, IsNil)
+	se.Close()
+
+	srv := &Server{capability: defaultCapability, driver: NewTiDBDriver(ts.store)}
+	for _, ca := range []struct {
+		password string
+		ok       bool
+	}{{"123", true}, {"456", false}} {
+		serverSide, clientSide := net.Pipe()
+		cc := &clientConn{
+			connectionID: 1,
+			user:         "sha2user",
+			salt:         []byte("0123456789abcdefghij"),
+			collation:    mysql.DefaultCollationID,
+			server:       srv,
+			alloc:        arena.NewAllocator(1024),
+			peerHost:     "localhost",
+		}
+		cc.setConn(serverSide)
+
+		clientErr := make(chan error, 1)
+		go func() {
+			clientErr <- mockSha2Client(clientSide, cc.salt, ca.password)
+		}()
+		err = cc.openSessionAndDoAuth([]byte{0x01}, mysql.AuthNativePassword)
+		serverSide.Close()
+		c.Assert(<-clientErr, IsNil, Commentf("server err %v", err))
+		if ca.ok {
+			c.Assert(err, IsNil)
+			c.Assert(cc.ctx.GetSessionVars().User.AuthUsername, Equals, "sha2user")
+		} else {
+			c.Assert(terror.ErrorEqual(err, errAccessDenied), IsTrue, Commentf("err %v", err))
+		}
+		c.Assert(cc.ctx.Close(), IsNil)
+		clientSide.Close()
+	}
+}
+
 func mapIdentical(m1, m2 map[string]string) bool {
 	return mapBelong(m1, m2) && mapBelong(m2, m1)
 }
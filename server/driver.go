@@ -17,8 +17,10 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
@@ -63,6 +65,12 @@ type QueryCtx interface {
 	// SetClientCapability sets client capability flags
 	SetClientCapability(uint32)
 
+	// Prepare prepares a statement.
+	Prepare(sql string) (statement PreparedStatement, columns, params []*ColumnInfo, err error)
+
+	// GetStatement gets PreparedStatement by statement ID.
+	GetStatement(stmtID int) PreparedStatement
+
 	// FieldList returns columns of a table.
 	FieldList(tableName string) (columns []*ColumnInfo, err error)
 
@@ -73,6 +81,15 @@ type QueryCtx interface {
 	GetSessionVars() *variable.SessionVars
 
 	SetCommandValue(command byte)
+
+	// SetSessionManager sets the session manager which the kill statement relies on.
+	SetSessionManager(util.SessionManager)
+
+	// Auth verifies user's authentication.
+	Auth(user *auth.UserIdentity, auth []byte, salt []byte) bool
+
+	// AuthPluginForUser returns the authentication plugin of the user.
+	AuthPluginForUser(user *auth.UserIdentity) (string, error)
 }
 
 // PreparedStatement is the interface to use a prepared statement.
@@ -30,6 +30,8 @@ package server
 
 import (
 	"context"
+	crand "crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
 	"fmt"
 	"math/rand"
@@ -58,9 +60,10 @@ var (
 )
 
 var (
-	errInvalidSequence = terror.ClassServer.New(mysql.ErrInvalidSequence, mysql.MySQLErrName[mysql.ErrInvalidSequence])
-	errInvalidType     = terror.ClassServer.New(mysql.ErrInvalidType, mysql.MySQLErrName[mysql.ErrInvalidType])
-	errAccessDenied    = terror.ClassServer.New(mysql.ErrAccessDenied, mysql.MySQLErrName[mysql.ErrAccessDenied])
+	errInvalidSequence  = terror.ClassServer.New(mysql.ErrInvalidSequence, mysql.MySQLErrName[mysql.ErrInvalidSequence])
+	errInvalidType      = terror.ClassServer.New(mysql.ErrInvalidType, mysql.MySQLErrName[mysql.ErrInvalidType])
+	errUnknownFieldType = terror.ClassServer.New(mysql.ErrUnknownFieldType, mysql.MySQLErrName[mysql.ErrUnknownFieldType])
+	errAccessDenied     = terror.ClassServer.New(mysql.ErrAccessDenied, mysql.MySQLErrName[mysql.ErrAccessDenied])
 )
 
 // DefaultCapability is the capability of the server when it is created using the default configuration.
@@ -88,6 +91,20 @@ type Server struct {
 	// So we just stop the listener and store to force clients to chose other TiDB servers.
 	stopListenerCh chan struct{}
 	statusServer   *http.Server
+
+	// rsaPrivateKey is used to exchange the password with clients of caching_sha2_password
+	// over unencrypted connections, it is generated on first use.
+	rsaKeyOnce    sync.Once
+	rsaPrivateKey *rsa.PrivateKey
+	rsaKeyErr     error
+}
+
+// rsaKey returns the RSA key of the server, generating it if needed.
+func (s *Server) rsaKey() (*rsa.PrivateKey, error) {
+	s.rsaKeyOnce.Do(func() {
+		s.rsaPrivateKey, s.rsaKeyErr = rsa.GenerateKey(crand.Reader, 2048)
+	})
+	return s.rsaPrivateKey, errors.Trace(s.rsaKeyErr)
 }
 
 // ConnectionCount gets current connection count.
@@ -241,6 +258,40 @@ func (s *Server) onConn(conn *clientConn) {
 	conn.Run(ctx)
 }
 
+// GetProcessInfo implements the SessionManager interface.
+func (s *Server) GetProcessInfo(id uint64) (*util.ProcessInfo, bool) {
+	s.rwlock.RLock()
+	conn, ok := s.clients[uint32(id)]
+	s.rwlock.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return &util.ProcessInfo{
+		ID:   id,
+		User: conn.user,
+		Host: conn.peerHost,
+	}, true
+}
+
+// Kill implements the SessionManager interface.
+func (s *Server) Kill(connectionID uint64, query bool) {
+	logutil.BgLogger().Info("kill", zap.Uint64("connID", connectionID), zap.Bool("query", query))
+
+	s.rwlock.RLock()
+	defer s.rwlock.RUnlock()
+	conn, ok := s.clients[uint32(connectionID)]
+	if !ok {
+		return
+	}
+
+	if !query {
+		// Mark the client connection status as WaitShutdown, when the connection detects
+		// this, it will end the dispatch loop and exit.
+		atomic.StoreInt32(&conn.status, connStatusWaitShutdown)
+	}
+	killConn(conn)
+}
+
 func killConn(conn *clientConn) {
 	sessVars := conn.ctx.GetSessionVars()
 	atomic.CompareAndSwapUint32(&sessVars.Killed, 0, 1)
@@ -14,9 +14,11 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"os"
 	"testing"
@@ -148,6 +150,83 @@ func runTestResultFieldTableIsNull(c *C) {
 	})
 }
 
+func runTestPreparedStmt(c *C) {
+	runTestsOnNewDB(c, nil, "PreparedStmt", func(dbt *DBTest) {
+		dbt.mustExec("create table test (a int primary key, b varchar(20), c double)")
+		// The go mysql driver uses the binary protocol when a query has arguments.
+		stmt, err := dbt.db.Prepare("insert into test values (?, ?, ?)")
+		dbt.Assert(err, IsNil)
+		_, err = stmt.Exec(1, "a", 1.5)
+		dbt.Assert(err, IsNil)
+		_, err = stmt.Exec(2, nil, 2.5)
+		dbt.Assert(err, IsNil)
+		dbt.Assert(stmt.Close(), IsNil)
+
+		stmt, err = dbt.db.Prepare("select a, b, c from test where a >= ? order by a")
+		dbt.Assert(err, IsNil)
+		rows, err := stmt.Query(1)
+		dbt.Assert(err, IsNil)
+		var (
+			a int
+			b sql.NullString
+			f float64
+		)
+		dbt.Assert(rows.Next(), IsTrue)
+		dbt.Assert(rows.Scan(&a, &b, &f), IsNil)
+		dbt.Assert(a, Equals, 1)
+		dbt.Assert(b.String, Equals, "a")
+		dbt.Assert(f, Equals, 1.5)
+		dbt.Assert(rows.Next(), IsTrue)
+		dbt.Assert(rows.Scan(&a, &b, &f), IsNil)
+		dbt.Assert(a, Equals, 2)
+		dbt.Assert(b.Valid, IsFalse)
+		dbt.Assert(rows.Next(), IsFalse)
+		dbt.Assert(rows.Close(), IsNil)
+
+		// Executing with the wrong number of arguments fails in the driver.
+		_, err = stmt.Query(1, 2)
+		dbt.Assert(err, NotNil)
+		dbt.Assert(stmt.Close(), IsNil)
+	})
+}
+
+func runTestKill(c *C, server *Server) {
+	runTestsOnNewDB(c, nil, "Kill", func(dbt *DBTest) {
+		db, err := sql.Open("mysql", getDSN(func(config *mysql.Config) {
+			config.DBName = "Kill"
+		}))
+		dbt.Assert(err, IsNil)
+		defer db.Close()
+		ctx := context.Background()
+		conn, err := db.Conn(ctx)
+		dbt.Assert(err, IsNil)
+		defer conn.Close()
+
+		var connID uint32
+		server.rwlock.RLock()
+		for id, cc := range server.clients {
+			if cc.dbname == "Kill" {
+				connID = id
+			}
+		}
+		server.rwlock.RUnlock()
+		dbt.Assert(connID, Not(Equals), uint32(0))
+
+		// KILL QUERY only interrupts the running statement, the connection is still usable.
+		dbt.mustExec(fmt.Sprintf("kill query %d", connID))
+		_, err = conn.ExecContext(ctx, "select 1")
+		dbt.Assert(err, IsNil)
+
+		_, err = dbt.db.Exec(fmt.Sprintf("kill %d", uint32(math.MaxUint32)))
+		dbt.Assert(err, NotNil)
+		dbt.Assert(err.Error(), Equals, fmt.Sprintf("Error 1094: Unknown thread id: %d", uint32(math.MaxUint32)))
+
+		dbt.mustExec(fmt.Sprintf("kill connection %d", connID))
+		_, err = conn.ExecContext(ctx, "select 1")
+		dbt.Assert(err, NotNil)
+	})
+}
+
 const retryTime = 100
 
 func waitUntilServerOnline(statusPort uint) {
@@ -82,6 +82,16 @@ func (ts *TidbTestSuite) TestResultFieldTableIsNull(c *C) {
 	runTestResultFieldTableIsNull(c)
 }
 
+func (ts *TidbTestSuite) TestPreparedStmt(c *C) {
+	c.Parallel()
+	runTestPreparedStmt(c)
+}
+
+func (ts *TidbTestSuite) TestKill(c *C) {
+	c.Parallel()
+	runTestKill(c, ts.server)
+}
+
 func (ts *TidbTestSuite) TestShowTablesFlen(c *C) {
 	qctx, err := ts.tidbdrv.OpenCtx(uint64(0), 0, uint8(tmysql.DefaultCollationID), "test", nil)
 	c.Assert(err, IsNil)
@@ -14,12 +14,15 @@
 package server
 
 import (
+	"time"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -109,6 +112,28 @@ func (s *testUtilSuite) TestDumpTextValue(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "2.20")
 
+	d := types.NewDecimalDatum(types.NewDecFromStringForTest("1.23"))
+	columns[0].Type = mysql.TypeNewDecimal
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{d}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, "1.23")
+
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	t, err := types.ParseTime(sc, "2017-01-05 23:59:59.575601", mysql.TypeDatetime, 0)
+	c.Assert(err, IsNil)
+	columns[0].Type = mysql.TypeDatetime
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewTimeDatum(t)}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, "2017-01-06 00:00:00")
+
+	duration, err := types.ParseDuration(sc, "11:30:45", 0)
+	c.Assert(err, IsNil)
+	columns[0].Type = mysql.TypeDuration
+	columns[0].Decimal = 0
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewDurationDatum(duration)}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, "11:30:45")
+
 	columns[0].Type = mysql.TypeBlob
 	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewBytesDatum([]byte("foo"))}).ToRow())
 	c.Assert(err, IsNil)
@@ -35,6 +35,67 @@ import (
 )
 
 const (
+	// CreateUserTable is the SQL statement creates User table in system db.
+	CreateUserTable = `CREATE TABLE if not exists mysql.user (
+		Host				CHAR(64),
+		User				CHAR(32),
+		authentication_string	TEXT,
+		plugin				CHAR(64),
+		Select_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Insert_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Update_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Delete_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Create_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Drop_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Process_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Grant_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		References_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Alter_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Show_db_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Super_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Create_tmp_table_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Lock_tables_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Execute_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Create_view_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Show_view_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Create_routine_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Alter_routine_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Index_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Create_user_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Event_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Trigger_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Create_role_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Drop_role_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		Shutdown_priv			CHAR(1) NOT NULL DEFAULT 'N',
+		PRIMARY KEY (Host, User));`
+	// CreateDBPrivTable is the SQL statement creates DB scope privilege table in system db.
+	CreateDBPrivTable = `CREATE TABLE if not exists mysql.db (
+		Host			CHAR(60),
+		DB			CHAR(64),
+		User			CHAR(32),
+		Select_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Insert_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Update_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Delete_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Create_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Drop_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Grant_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Alter_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Execute_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Index_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		Create_view_priv	CHAR(1) NOT NULL DEFAULT 'N',
+		Show_view_priv		CHAR(1) NOT NULL DEFAULT 'N',
+		PRIMARY KEY (Host, DB, User));`
+	// CreateTablePrivTable is the SQL statement creates table scope privilege table in system db.
+	// Table_priv holds the granted privileges as a comma separated list, such as 'Select,Insert'.
+	CreateTablePrivTable = `CREATE TABLE if not exists mysql.tables_priv (
+		Host		CHAR(60),
+		DB		CHAR(64),
+		User		CHAR(32),
+		Table_name	CHAR(64),
+		Grantor		CHAR(77),
+		Table_priv	VARCHAR(255) NOT NULL DEFAULT '',
+		PRIMARY KEY (Host, DB, User, Table_name));`
 	// CreateGloablVariablesTable is the SQL statement creates global variable table in system db.
 	// TODO: MySQL puts GLOBAL_VARIABLES table in INFORMATION_SCHEMA db.
 	// INFORMATION_SCHEMA is a virtual db in TiDB. So we put this table in system db.
@@ -215,6 +276,11 @@ func doDDLWorks(s Session) {
 	mustExecute(s, "CREATE DATABASE IF NOT EXISTS test")
 	// Create system db.
 	mustExecute(s, fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s;", mysql.SystemDB))
+	// Create user table.
+	mustExecute(s, CreateUserTable)
+	// Create privilege tables.
+	mustExecute(s, CreateDBPrivTable)
+	mustExecute(s, CreateTablePrivTable)
 	// Create global system variable table.
 	mustExecute(s, CreateGloablVariablesTable)
 	// Create TiDB table.
@@ -238,6 +304,17 @@ func doDDLWorks(s Session) {
 func doDMLWorks(s Session) {
 	mustExecute(s, "BEGIN")
 
+	// Insert a default user with empty password.
+	privCols := make([]string, 0, len(mysql.AllGlobalPrivs)+1)
+	privValues := make([]string, 0, len(mysql.AllGlobalPrivs)+1)
+	for _, priv := range append(mysql.AllGlobalPrivs, mysql.GrantPriv) {
+		privCols = append(privCols, mysql.Priv2UserCol[priv])
+		privValues = append(privValues, `"Y"`)
+	}
+	sql := fmt.Sprintf(`INSERT HIGH_PRIORITY INTO %s.%s (Host, User, authentication_string, plugin, %s) VALUES ("%%", "root", "", "%s", %s)`,
+		mysql.SystemDB, mysql.UserTable, strings.Join(privCols, ", "), mysql.AuthNativePassword, strings.Join(privValues, ", "))
+	mustExecute(s, sql)
+
 	// Init global system variables table.
 	values := make([]string, 0, len(variable.SysVars))
 	for k, v := range variable.SysVars {
@@ -247,7 +324,7 @@ func doDMLWorks(s Session) {
 			values = append(values, value)
 		}
 	}
-	sql := fmt.Sprintf("INSERT HIGH_PRIORITY INTO %s.%s VALUES %s;", mysql.SystemDB, mysql.GlobalVariablesTable,
+	sql = fmt.Sprintf("INSERT HIGH_PRIORITY INTO %s.%s VALUES %s;", mysql.SystemDB, mysql.GlobalVariablesTable,
 		strings.Join(values, ", "))
 	mustExecute(s, sql)
 
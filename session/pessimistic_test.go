@@ -0,0 +1,249 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session_test
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/store/mockstore/mocktikv"
+	"github.com/pingcap/tidb/store/tikv"
+	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+var _ = SerialSuites(&testPessimisticSuite{})
+
+type testPessimisticSuite struct {
+	cluster   *mocktikv.Cluster
+	mvccStore mocktikv.MVCCStore
+	store     kv.Storage
+	dom       *domain.Domain
+}
+
+func (s *testPessimisticSuite) SetUpSuite(c *C) {
+	testleak.BeforeTest()
+	s.cluster = mocktikv.NewCluster()
+	mocktikv.BootstrapWithSingleStore(s.cluster)
+	s.mvccStore = mocktikv.MustNewMVCCStore()
+	store, err := mockstore.NewMockTikvStore(
+		mockstore.WithCluster(s.cluster),
+		mockstore.WithMVCCStore(s.mvccStore),
+	)
+	c.Assert(err, IsNil)
+	s.store = store
+	session.SetSchemaLease(0)
+	session.DisableStats4Test()
+	s.dom, err = session.BootstrapSession(s.store)
+	c.Assert(err, IsNil)
+}
+
+func (s *testPessimisticSuite) TearDownSuite(c *C) {
+	s.dom.Close()
+	s.store.Close()
+	testleak.AfterTest(c)()
+}
+
+func (s *testPessimisticSuite) TestPessimisticTxn(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists pessimistic")
+	tk.MustExec("create table pessimistic (k int primary key, v int)")
+	tk.MustExec("insert pessimistic values (1, 1)")
+
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("update pessimistic set v = v + 1 where k = 1")
+
+	done := make(chan struct{})
+	go func() {
+		tk1.MustExec("begin pessimistic")
+		// Blocked until tk commits, then it reads the committed row.
+		tk1.MustExec("update pessimistic set v = v + 1 where k = 1")
+		tk1.MustExec("commit")
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-done:
+		c.Fatal("the update should wait for the lock")
+	default:
+	}
+	tk.MustExec("commit")
+	<-done
+	tk.MustQuery("select v from pessimistic where k = 1").Check(testkit.Rows("3"))
+
+	// The optimistic transaction doesn't wait.
+	tk.MustExec("begin")
+	tk.MustExec("update pessimistic set v = v + 1 where k = 1")
+	tk1.MustExec("begin pessimistic")
+	tk1.MustExec("update pessimistic set v = v + 1 where k = 1")
+	tk1.MustExec("rollback")
+	tk.MustExec("rollback")
+	tk.MustQuery("select v from pessimistic where k = 1").Check(testkit.Rows("3"))
+}
+
+func (s *testPessimisticSuite) TestSelectForUpdate(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, c int)")
+	tk.MustExec("insert t values (1, 1), (2, 2)")
+
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select c from t where id = 1 for update").Check(testkit.Rows("1"))
+
+	tk1.MustExec("begin pessimistic")
+	_, err := tk1.Exec("select * from t where id = 1 for update nowait")
+	c.Assert(terror.ErrorEqual(err, tikv.ErrLockAcquireFailAndNoWaitSet), IsTrue, Commentf("err %v", err))
+	// The other row is not locked.
+	tk1.MustQuery("select c from t where id = 2 for update nowait").Check(testkit.Rows("2"))
+
+	tk1.MustExec("set innodb_lock_wait_timeout = 1")
+	start := time.Now()
+	_, err = tk1.Exec("update t set c = 10 where id = 1")
+	c.Assert(terror.ErrorEqual(err, tikv.ErrLockWaitTimeout), IsTrue, Commentf("err %v", err))
+	c.Assert(time.Since(start), GreaterEqual, time.Second)
+	tk1.MustExec("rollback")
+	tk.MustExec("rollback")
+
+	// Rollback releases the locks.
+	tk1.MustExec("begin pessimistic")
+	tk1.MustQuery("select c from t for update nowait").Check(testkit.Rows("1", "2"))
+	tk1.MustExec("commit")
+
+	// Without a transaction, SELECT FOR UPDATE doesn't lock the rows.
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select c from t where id = 1 for update").Check(testkit.Rows("1"))
+	tk1.MustQuery("select c from t where id = 1 for update nowait").Check(testkit.Rows("1"))
+	tk.MustExec("commit")
+}
+
+func (s *testPessimisticSuite) TestDeadlock(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists deadlock")
+	tk.MustExec("create table deadlock (k int primary key, v int)")
+	tk.MustExec("insert deadlock values (1, 1), (2, 2)")
+
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("update deadlock set v = v + 1 where k = 1")
+	tk1.MustExec("begin pessimistic")
+	tk1.MustExec("update deadlock set v = v + 1 where k = 2")
+
+	done := make(chan error)
+	go func() {
+		// tk1 waits for tk.
+		_, err := tk1.Exec("update deadlock set v = v + 1 where k = 1")
+		done <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	// tk waits for tk1, it's a deadlock.
+	_, err := tk.Exec("update deadlock set v = v + 1 where k = 2")
+	c.Assert(terror.ErrorEqual(err, tikv.ErrDeadlock), IsTrue, Commentf("err %v", err))
+	// The transaction of tk is rolled back, tk1 gets the lock.
+	c.Assert(<-done, IsNil)
+	tk1.MustExec("commit")
+	tk.MustQuery("select v from deadlock").Check(testkit.Rows("2", "3"))
+}
+
+func (s *testPessimisticSuite) TestTxnMode(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, c int)")
+	tk.MustExec("insert t values (1, 1)")
+
+	checkLocked := func(locked bool) {
+		tk1.MustExec("begin pessimistic")
+		_, err := tk1.Exec("select * from t for update nowait")
+		c.Assert(err != nil, Equals, locked)
+		tk1.MustExec("rollback")
+	}
+
+	tk.MustExec("set tidb_txn_mode = 'pessimistic'")
+	tk.MustExec("begin")
+	tk.MustExec("update t set c = 2")
+	checkLocked(true)
+	tk.MustExec("commit")
+
+	// The mode of BEGIN overrides tidb_txn_mode.
+	tk.MustExec("begin optimistic")
+	tk.MustExec("update t set c = 3")
+	checkLocked(false)
+	tk.MustExec("commit")
+
+	// A transaction started with autocommit disabled uses tidb_txn_mode too.
+	tk.MustExec("set autocommit = 0")
+	tk.MustExec("update t set c = 4")
+	checkLocked(true)
+	tk.MustExec("commit")
+	tk.MustExec("set autocommit = 1")
+
+	tk.MustExec("set tidb_txn_mode = ''")
+	tk.MustExec("begin")
+	tk.MustExec("update t set c = 5")
+	checkLocked(false)
+	tk.MustExec("commit")
+	tk.MustQuery("select c from t").Check(testkit.Rows("5"))
+}
+
+func (s *testPessimisticSuite) TestWriteLockedRow(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("insert t values (1, 10)")
+
+	// An autocommit write waits for the pessimistic lock instead of being lost.
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from t where a = 1 for update").Check(testkit.Rows("1 10"))
+	done := make(chan struct{})
+	go func() {
+		tk1.MustExec("update t set b = b + 1 where a = 1")
+		c.Assert(tk1.Se.AffectedRows(), Equals, uint64(1))
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-done:
+		c.Fatal("the update should wait for the lock")
+	default:
+	}
+	tk.MustExec("update t set b = b + 100 where a = 1")
+	tk.MustExec("commit")
+	<-done
+	tk.MustQuery("select b from t where a = 1").Check(testkit.Rows("111"))
+
+	// The optimistic transaction fails to commit if the row it locked is changed.
+	tk.MustExec("begin")
+	tk.MustQuery("select * from t where a = 1 for update").Check(testkit.Rows("1 111"))
+	tk1.MustExec("update t set b = b + 1 where a = 1")
+	tk.MustExec("update t set b = b + 100 where a = 1")
+	_, err := tk.Exec("commit")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("select b from t where a = 1").Check(testkit.Rows("112"))
+
+	tk.MustExec("set tidb_disable_txn_auto_retry = 0")
+	tk.MustExec("begin")
+	tk.MustQuery("select * from t where a = 1 for update").Check(testkit.Rows("1 112"))
+	tk1.MustExec("update t set b = b + 1 where a = 1")
+	_, err = tk.Exec("commit")
+	c.Assert(session.ErrForUpdateCantRetry.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("select b from t where a = 1").Check(testkit.Rows("113"))
+}
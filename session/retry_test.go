@@ -0,0 +1,151 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session_test
+
+import (
+	"context"
+	"sync/atomic"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/store/tikv"
+	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+var _ = SerialSuites(&testRetrySuite{})
+
+type testRetrySuite struct {
+	store *conflictStore
+	dom   *domain.Domain
+}
+
+// conflictStore makes the next `conflicts` commits fail with a write conflict.
+type conflictStore struct {
+	tikv.Storage
+	conflicts int32
+}
+
+func (s *conflictStore) Begin() (kv.Transaction, error) {
+	txn, err := s.Storage.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &conflictTxn{Transaction: txn, store: s}, nil
+}
+
+func (s *conflictStore) BeginWithStartTS(startTS uint64) (kv.Transaction, error) {
+	txn, err := s.Storage.BeginWithStartTS(startTS)
+	if err != nil {
+		return nil, err
+	}
+	return &conflictTxn{Transaction: txn, store: s}, nil
+}
+
+type conflictTxn struct {
+	kv.Transaction
+	store *conflictStore
+}
+
+func (txn *conflictTxn) Commit(ctx context.Context) error {
+	if atomic.AddInt32(&txn.store.conflicts, -1) >= 0 {
+		terror.Log(txn.Transaction.Rollback())
+		return kv.ErrWriteConflict.FastGenByArgs(txn.StartTS(), 0, 0, "mock")
+	}
+	atomic.StoreInt32(&txn.store.conflicts, 0)
+	return txn.Transaction.Commit(ctx)
+}
+
+func (s *testRetrySuite) SetUpSuite(c *C) {
+	testleak.BeforeTest()
+	store, err := mockstore.NewMockTikvStore()
+	c.Assert(err, IsNil)
+	s.store = &conflictStore{Storage: store.(tikv.Storage)}
+	session.SetSchemaLease(0)
+	session.DisableStats4Test()
+	s.dom, err = session.BootstrapSession(s.store)
+	c.Assert(err, IsNil)
+}
+
+func (s *testRetrySuite) TearDownSuite(c *C) {
+	s.dom.Close()
+	s.store.Close()
+	testleak.AfterTest(c)()
+}
+
+func (s *testRetrySuite) mockConflicts(n int32) {
+	atomic.StoreInt32(&s.store.conflicts, n)
+}
+
+func (s *testRetrySuite) TestAutocommitRetry(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, v int)")
+
+	s.mockConflicts(1)
+	tk.MustExec("insert t values (1, 1)")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1"))
+
+	// The retry stops at tidb_retry_limit.
+	tk.MustExec("set tidb_retry_limit = 3")
+	s.mockConflicts(100)
+	_, err := tk.Exec("insert t values (2, 2)")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("err %v", err))
+	c.Assert(atomic.LoadInt32(&s.store.conflicts), Equals, int32(96))
+
+	tk.MustExec("set tidb_retry_limit = 0")
+	s.mockConflicts(1)
+	_, err = tk.Exec("insert t values (2, 2)")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1"))
+}
+
+func (s *testRetrySuite) TestExplicitTxnRetry(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, v int)")
+	tk.MustExec("insert t values (1, 1)")
+
+	// The explicit transaction isn't retried by default.
+	tk.MustExec("begin")
+	tk.MustExec("update t set v = v + 1 where id = 1")
+	s.mockConflicts(1)
+	_, err := tk.Exec("commit")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("select v from t").Check(testkit.Rows("1"))
+
+	// The retry replays the statements with a new start TS, so it sees the
+	// update committed by tk1.
+	tk.MustExec("set tidb_disable_txn_auto_retry = 0")
+	tk.MustExec("begin")
+	tk.MustExec("update t set v = v + 1 where id = 1")
+	tk1.MustExec("update t set v = 10 where id = 1")
+	s.mockConflicts(1)
+	tk.MustExec("commit")
+	tk.MustQuery("select v from t").Check(testkit.Rows("11"))
+
+	// The transaction that has SELECT FOR UPDATE can't be retried.
+	tk.MustExec("begin")
+	tk.MustQuery("select v from t where id = 1 for update").Check(testkit.Rows("11"))
+	tk.MustExec("update t set v = v + 1 where id = 1")
+	s.mockConflicts(1)
+	_, err = tk.Exec("commit")
+	c.Assert(session.ErrForUpdateCantRetry.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("select v from t").Check(testkit.Rows("11"))
+}
@@ -38,13 +38,18 @@ import (
 	"github.com/pingcap/tidb/owner"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/tikv"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
@@ -61,11 +66,22 @@ type Session interface {
 	AffectedRows() uint64                                         // Affected rows by latest executed stmt.
 	Execute(context.Context, string) ([]sqlexec.RecordSet, error) // Execute a sql statement.
 	String() string                                               // String is used to debug.
+	// Auth verifies the user and password sent by the client during the handshake.
+	Auth(user *auth.UserIdentity, auth []byte, salt []byte) bool
+	// AuthPluginForUser gets the authentication plugin of the account the user will log in as.
+	AuthPluginForUser(user *auth.UserIdentity) (string, error)
+	// PrepareStmt executes prepare statement in binary protocol.
+	PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error)
+	// ExecutePreparedStmt executes a prepared statement.
+	ExecutePreparedStmt(ctx context.Context, stmtID uint32, param []types.Datum) (sqlexec.RecordSet, error)
+	// DropPreparedStmt drops a prepared statement.
+	DropPreparedStmt(stmtID uint32) error
 	CommitTxn(context.Context) error
 	RollbackTxn(context.Context)
 	SetClientCapability(uint32) // Set client capability flags.
 	SetConnectionID(uint64)
 	SetCommandValue(byte)
+	SetSessionManager(util.SessionManager)
 	SetTLSState(*tls.ConnectionState)
 	SetCollation(coID int) error
 	Close()
@@ -122,6 +138,8 @@ type session struct {
 
 	// shared coprocessor client per session
 	client kv.Client
+
+	sessionManager util.SessionManager
 }
 
 // DDLOwnerChecker returns s.ddlOwnerChecker.
@@ -129,6 +147,14 @@ func (s *session) DDLOwnerChecker() owner.DDLOwnerChecker {
 	return s.ddlOwnerChecker
 }
 
+func (s *session) SetSessionManager(sm util.SessionManager) {
+	s.sessionManager = sm
+}
+
+func (s *session) GetSessionManager() util.SessionManager {
+	return s.sessionManager
+}
+
 func (s *session) getMembufCap() int {
 	return kv.DefaultTxnMembufCap
 }
@@ -236,6 +262,157 @@ func (s *session) doCommit(ctx context.Context) error {
 	return s.txn.Commit(sessionctx.SetCommitCtx(ctx, s))
 }
 
+func (s *session) doCommitWithRetry(ctx context.Context) error {
+	var txnSize int
+	if s.txn.Valid() {
+		txnSize = s.txn.Size()
+	}
+	err := s.doCommit(ctx)
+	if err != nil {
+		commitRetryLimit := s.sessionVars.RetryLimit
+		if !s.sessionVars.TxnCtx.CouldRetry {
+			commitRetryLimit = 0
+		}
+		// The pessimistic transaction has locked the rows it writes, a write conflict
+		// means the statements should be retried, not the whole transaction.
+		isPessimistic := s.sessionVars.TxnCtx.IsPessimistic
+		if s.isTxnRetryableError(err) && commitRetryLimit > 0 && !isPessimistic && GetHistory(s).Count() > 0 {
+			logutil.Logger(ctx).Warn("sql",
+				zap.Uint64("conn", s.sessionVars.ConnectionID),
+				zap.Error(err),
+				zap.String("txn", s.txn.GoString()))
+			// Transactions will retry 2 ~ commitRetryLimit times.
+			// We make larger transactions retry less times to prevent cluster resource outage.
+			txnSizeRate := float64(txnSize) / float64(kv.TxnTotalSizeLimit)
+			maxRetryCount := commitRetryLimit - int64(float64(commitRetryLimit-1)*txnSizeRate)
+			err = s.retry(ctx, uint(maxRetryCount))
+		}
+	}
+	return err
+}
+
+// isTxnRetryableError checks whether the commit error could be fixed by replaying the transaction.
+func (s *session) isTxnRetryableError(err error) bool {
+	if atomic.LoadUint32(&SchemaChangedWithoutRetry) == 1 {
+		return kv.IsTxnRetryableError(err)
+	}
+	return kv.IsTxnRetryableError(err) || domain.ErrInfoSchemaChanged.Equal(err)
+}
+
+// isTxnRetryable checks whether the current transaction could be replayed when its commit fails.
+func (s *session) isTxnRetryable() bool {
+	sessVars := s.sessionVars
+	// The pessimistic transaction doesn't need to retry.
+	if sessVars.TxnCtx.IsPessimistic {
+		return false
+	}
+	// If the retry limit is 0, the transaction could not retry.
+	if sessVars.RetryLimit == 0 {
+		return false
+	}
+	// If the session is not InTxn, it is an auto-committed transaction.
+	// The auto-committed transaction could always retry.
+	if !sessVars.InTxn() {
+		return true
+	}
+	// The internal transaction could always retry.
+	if sessVars.InRestrictedSQL {
+		return true
+	}
+	// If the retry is enabled, the transaction could retry.
+	return !sessVars.DisableTxnAutoRetry
+}
+
+// retry replays the statements in the history of the current transaction with a new
+// start TS, until the commit succeeds, meets a non-retryable error or reaches maxCnt.
+func (s *session) retry(ctx context.Context, maxCnt uint) (err error) {
+	var retryCnt uint
+	defer func() {
+		s.sessionVars.SetStatusFlag(mysql.ServerStatusInTrans, false)
+		if err != nil {
+			s.RollbackTxn(ctx)
+		}
+		s.txn.changeToInvalid()
+	}()
+
+	connID := s.sessionVars.ConnectionID
+	if s.sessionVars.TxnCtx.ForUpdate {
+		return ErrForUpdateCantRetry.GenWithStackByArgs(connID)
+	}
+
+	nh := GetHistory(s)
+	var schemaVersion int64
+	orgStartTS := s.sessionVars.TxnCtx.StartTS
+	for {
+		s.PrepareTxnCtx(ctx)
+		for i, sr := range nh.history {
+			st := sr.st
+			s.sessionVars.StmtCtx = sr.stmtCtx
+			s.sessionVars.StmtCtx.ResetForRetry()
+			schemaVersion, err = st.RebuildPlan(ctx)
+			if err != nil {
+				return err
+			}
+
+			if retryCnt == 0 {
+				// We do not have to log the query every time.
+				// We print the queries at the first try only.
+				logutil.Logger(ctx).Warn("retrying",
+					zap.Uint64("conn", connID),
+					zap.Int64("schemaVersion", schemaVersion),
+					zap.Uint("retryCnt", retryCnt),
+					zap.Int("queryNum", i),
+					zap.String("sql", st.OriginText()))
+			} else {
+				logutil.Logger(ctx).Warn("retrying",
+					zap.Uint64("conn", connID),
+					zap.Int64("schemaVersion", schemaVersion),
+					zap.Uint("retryCnt", retryCnt),
+					zap.Int("queryNum", i))
+			}
+			_, err = st.Exec(ctx)
+			if err != nil {
+				s.StmtRollback()
+				break
+			}
+			err = s.StmtCommit()
+			if err != nil {
+				return err
+			}
+		}
+		logutil.Logger(ctx).Warn("transaction association",
+			zap.Uint64("retrying txnStartTS", s.sessionVars.TxnCtx.StartTS),
+			zap.Uint64("original txnStartTS", orgStartTS))
+		if err == nil {
+			err = s.doCommit(ctx)
+			if err == nil {
+				break
+			}
+		}
+		if !s.isTxnRetryableError(err) {
+			logutil.Logger(ctx).Warn("sql",
+				zap.Uint64("conn", connID),
+				zap.Error(err))
+			return err
+		}
+		retryCnt++
+		if retryCnt >= maxCnt {
+			logutil.Logger(ctx).Warn("sql",
+				zap.Uint64("conn", connID),
+				zap.Uint("retry reached max count", retryCnt))
+			return err
+		}
+		logutil.Logger(ctx).Warn("sql",
+			zap.Uint64("conn", connID),
+			zap.Error(err),
+			zap.String("txn", s.txn.GoString()))
+		kv.BackOff(retryCnt)
+		s.txn.changeToInvalid()
+		s.sessionVars.SetStatusFlag(mysql.ServerStatusInTrans, false)
+	}
+	return err
+}
+
 func (s *session) commitTxn(ctx context.Context) error {
 	defer func() {
 		s.txn.changeToInvalid()
@@ -244,7 +421,7 @@ func (s *session) commitTxn(ctx context.Context) error {
 		// If the transaction is invalid, maybe it has already been rolled back by the client.
 		return nil
 	}
-	err := s.doCommit(ctx)
+	err := s.doCommitWithRetry(ctx)
 
 	if isoLevelOneShot := &s.sessionVars.TxnIsolationLevelOneShot; isoLevelOneShot.State != 0 {
 		switch isoLevelOneShot.State {
@@ -301,6 +478,9 @@ func (s *session) String() string {
 		"status":     sessVars.Status,
 		"strictMode": sessVars.StrictSQLMode,
 	}
+	if sessVars.User != nil {
+		data["user"] = sessVars.User.Username
+	}
 	if s.txn.Valid() {
 		// if txn is committed or rolled back, txn is nil.
 		data["txn"] = s.txn.String()
@@ -628,6 +808,55 @@ func (s *session) execute(ctx context.Context, sql string) (recordSets []sqlexec
 	return recordSets, nil
 }
 
+// PrepareStmt is used for executing prepare statement in binary protocol
+func (s *session) PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error) {
+	if s.sessionVars.TxnCtx.InfoSchema == nil {
+		// We don't need to create a transaction for prepare statement, just get information schema will do.
+		s.sessionVars.TxnCtx.InfoSchema = domain.GetDomain(s).InfoSchema()
+	}
+	err = s.loadCommonGlobalVariablesIfNeeded()
+	if err != nil {
+		return
+	}
+
+	ctx := context.Background()
+	inTxn := s.GetSessionVars().InTxn()
+	// NewPrepareExec may need startTS to build the executor, for example prepare statement has subquery in it.
+	// So we have to call PrepareTxnCtx here.
+	s.PrepareTxnCtx(ctx)
+	prepareExec := executor.NewPrepareExec(s, infoschema.GetInfoSchema(s), sql)
+	err = prepareExec.Next(ctx, nil)
+	if err != nil {
+		return
+	}
+	if !inTxn {
+		// We could start a transaction to build the prepare executor before, we should rollback it here.
+		s.RollbackTxn(ctx)
+	}
+	return prepareExec.ID, prepareExec.ParamCount, prepareExec.Fields, nil
+}
+
+// ExecutePreparedStmt executes a prepared statement.
+func (s *session) ExecutePreparedStmt(ctx context.Context, stmtID uint32, args []types.Datum) (sqlexec.RecordSet, error) {
+	s.PrepareTxnCtx(ctx)
+	s.sessionVars.StartTime = time.Now()
+	st, err := executor.CompileExecutePreparedStmt(ctx, s, stmtID, args)
+	if err != nil {
+		return nil, err
+	}
+	return runStmt(ctx, s, st)
+}
+
+// DropPreparedStmt drops a prepared statement.
+func (s *session) DropPreparedStmt(stmtID uint32) error {
+	vars := s.sessionVars
+	if _, ok := vars.PreparedStmts[stmtID]; !ok {
+		return plannercore.ErrStmtNotFound
+	}
+	vars.RemovePreparedStmt(stmtID)
+	return nil
+}
+
 // rollbackOnError makes sure the next statement starts a new transaction with the latest InfoSchema.
 func (s *session) rollbackOnError(ctx context.Context) {
 	if !s.sessionVars.InTxn() {
@@ -690,6 +919,7 @@ func (s *session) NewTxn(ctx context.Context) error {
 		InfoSchema:    is,
 		SchemaVersion: is.SchemaMetaVersion(),
 		CreateTime:    time.Now(),
+		CouldRetry:    true,
 		StartTS:       txn.StartTS(),
 	}
 	return nil
@@ -718,6 +948,7 @@ func (s *session) ClearValue(key fmt.Stringer) {
 func (s *session) Close() {
 	ctx := context.TODO()
 	s.RollbackTxn(ctx)
+	s.sessionVars.WithdrawAllPreparedStmt()
 }
 
 // GetSessionVars implements the context.Context interface.
@@ -725,6 +956,25 @@ func (s *session) GetSessionVars() *variable.SessionVars {
 	return s.sessionVars
 }
 
+func (s *session) Auth(user *auth.UserIdentity, authentication []byte, salt []byte) bool {
+	pm := privilege.GetPrivilegeManager(s)
+	authUser, authHost, success := pm.ConnectionVerification(user.Username, user.Hostname, authentication, salt)
+	if !success {
+		logutil.BgLogger().Error("user connection verification failed",
+			zap.Stringer("user", user))
+		return false
+	}
+	user.AuthUsername = authUser
+	user.AuthHostname = authHost
+	s.sessionVars.User = user
+	return true
+}
+
+func (s *session) AuthPluginForUser(user *auth.UserIdentity) (string, error) {
+	pm := privilege.GetPrivilegeManager(s)
+	return pm.GetAuthPlugin(user.Username, user.Hostname)
+}
+
 // CreateSession4Test creates a new session environment for test.
 func CreateSession4Test(store kv.Storage) (Session, error) {
 	s, err := CreateSession(store)
@@ -759,6 +1009,10 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 	}
 
 	dom := domain.GetDomain(se)
+	err = dom.LoadPrivilegeLoop(se)
+	if err != nil {
+		return nil, err
+	}
 
 	se1, err := createSession(store)
 	if err != nil {
@@ -769,6 +1023,13 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 		return nil, err
 	}
 
+	if raw, ok := store.(tikv.Storage); ok {
+		err = raw.StartGCWorker()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return dom, err
 }
 
@@ -815,6 +1076,9 @@ func createSession(store kv.Storage) (*session, error) {
 	// session implements variable.GlobalVarAccessor. Bind it to ctx.
 	s.sessionVars.GlobalVarsAccessor = s
 	s.txn.init()
+	privilege.BindPrivilegeManager(s, &privileges.UserPrivileges{
+		Handle: dom.PrivilegeHandle(),
+	})
 	return s, nil
 }
 
@@ -834,6 +1098,9 @@ func CreateSessionWithDomain(store kv.Storage, dom *domain.Domain) (*session, er
 	// session implements variable.GlobalVarAccessor. Bind it to ctx.
 	s.sessionVars.GlobalVarsAccessor = s
 	s.txn.init()
+	privilege.BindPrivilegeManager(s, &privileges.UserPrivileges{
+		Handle: dom.PrivilegeHandle(),
+	})
 	return s, nil
 }
 
@@ -916,6 +1183,7 @@ var builtinGlobalVariable = []string{
 	/* TiDB specific global variables: */
 	variable.TiDBSkipUTF8Check,
 	variable.TiDBIndexLookupSize,
+	variable.TiDBIndexJoinBatchSize,
 	variable.TiDBIndexLookupConcurrency,
 	variable.TiDBIndexLookupJoinConcurrency,
 	variable.TiDBIndexSerialScanConcurrency,
@@ -947,6 +1215,9 @@ var builtinGlobalVariable = []string{
 	variable.TiDBEnableVectorizedExpression,
 	variable.TiDBEnableNoopFuncs,
 	variable.TiDBMaxDeltaSchemaCount,
+	variable.TiDBTxnMode,
+	variable.TiDBRetryLimit,
+	variable.TiDBDisableTxnAutoRetry,
 }
 
 var (
@@ -1026,6 +1297,12 @@ func (s *session) PrepareTxnCtx(ctx context.Context) {
 		InfoSchema:    is,
 		SchemaVersion: is.SchemaMetaVersion(),
 		CreateTime:    time.Now(),
+		CouldRetry:    true,
+	}
+	// A transaction started implicitly with autocommit disabled takes its mode
+	// from tidb_txn_mode, an autocommit statement is always optimistic.
+	if !s.sessionVars.IsAutocommit() && s.sessionVars.TxnMode == ast.Pessimistic {
+		s.sessionVars.TxnCtx.IsPessimistic = true
 	}
 }
 
@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/tikv"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
@@ -170,6 +171,11 @@ func finishStmt(ctx context.Context, sctx sessionctx.Context, se *session, sessV
 		if !sessVars.InTxn() {
 			logutil.BgLogger().Info("rollbackTxn for ddl/autocommit failed")
 			se.RollbackTxn(ctx)
+		} else if terror.ErrorEqual(meetsErr, tikv.ErrDeadlock) {
+			// The transaction is chosen as the deadlock victim, roll it back to
+			// release its locks, as MySQL does.
+			logutil.BgLogger().Info("rollbackTxn for deadlock", zap.Uint64("txn", sessVars.TxnCtx.StartTS))
+			se.RollbackTxn(ctx)
 		}
 		return meetsErr
 	}
@@ -219,6 +225,15 @@ func runStmt(ctx context.Context, sctx sessionctx.Context, s sqlexec.Statement)
 	rs, err = s.Exec(ctx)
 	sessVars.TxnCtx.StatementCount++
 	if !s.IsReadOnly() {
+		// All the history should be added here, it's replayed when the commit
+		// meets a retryable error.
+		if err == nil && sessVars.TxnCtx.CouldRetry {
+			if se.isTxnRetryable() {
+				GetHistory(sctx).Add(s, sessVars.StmtCtx)
+			} else {
+				sessVars.TxnCtx.CouldRetry = false
+			}
+		}
 		// Handle the stmt commit/rollback.
 		if txn, err1 := sctx.Txn(false); err1 == nil {
 			if txn.Valid() {
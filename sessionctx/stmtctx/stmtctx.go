@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/memory"
 	"go.uber.org/zap"
 )
 
@@ -48,6 +50,7 @@ type StatementContext struct {
 	// If IsDDLJobInQueue is true, it means the DDL job is in the queue of storage, and it can be handled by the DDL worker.
 	IsDDLJobInQueue        bool
 	InInsertStmt           bool
+	InUpdateStmt           bool
 	InDeleteStmt           bool
 	InSelectStmt           bool
 	InExplainStmt          bool
@@ -67,6 +70,10 @@ type StatementContext struct {
 	// then cast the float string to int string. Otherwise, we cast string to integer
 	// prefix in a strict way, only extract 0-9 and (+ or - in first bit).
 	CastStrToIntStrict bool
+	// SnapshotTS is the timestamp of the snapshot that the statement reads,
+	// it's set when the statement reads history data by AS OF TIMESTAMP or
+	// tidb_snapshot.
+	SnapshotTS uint64
 
 	// mu struct holds variables that change during execution.
 	mu struct {
@@ -116,6 +123,13 @@ type StatementContext struct {
 	nowTs          time.Time // use this variable for now/current_timestamp calculation/cache for one stmt
 	stmtTimeCached bool
 	StmtType       string
+
+	// MemTracker tracks the memory usage of the statement, its limit is the
+	// memory quota of the statement.
+	MemTracker *memory.Tracker
+	// RuntimeStatsColl collects the execution info of executors, it is only
+	// set for EXPLAIN ANALYZE.
+	RuntimeStatsColl *execdetails.RuntimeStatsColl
 }
 
 // StmtHints are SessionVars related sql hints.
@@ -376,7 +390,7 @@ func (sc *StatementContext) PushDownFlags() uint64 {
 	var flags uint64
 	if sc.InInsertStmt {
 		flags |= model.FlagInInsertStmt
-	} else if sc.InDeleteStmt {
+	} else if sc.InUpdateStmt || sc.InDeleteStmt {
 		flags |= model.FlagInUpdateOrDeleteStmt
 	} else if sc.InSelectStmt {
 		flags |= model.FlagInSelectStmt
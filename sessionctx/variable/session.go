@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -33,6 +34,8 @@ import (
 	"github.com/pingcap/tidb/util/rowcodec"
 )
 
+var preparedStmtCount int64
+
 // Error instances.
 var (
 	ErrCantSetToNull  = terror.ClassVariable.New(mysql.ErrCantSetToNull, mysql.MySQLErrName[mysql.ErrCantSetToNull])
@@ -49,6 +52,12 @@ type TransactionContext struct {
 	StartTS       uint64
 	Shard         *int64
 	TableDeltaMap map[int64]TableDelta
+	IsPessimistic bool
+	// CouldRetry indicates whether the transaction could be replayed from its
+	// statement history when the commit meets a retryable error.
+	CouldRetry bool
+	// ForUpdate indicates whether the transaction has locked rows by SELECT FOR UPDATE.
+	ForUpdate bool
 
 	CreateTime     time.Time
 	StatementCount int
@@ -134,6 +143,11 @@ type SessionVars struct {
 	Users map[string]string
 	// systems variables, don't modify it directly, use GetSystemVar/SetSystemVar method.
 	systems map[string]string
+	// PreparedStmts stores prepared statement.
+	PreparedStmts        map[uint32]interface{}
+	PreparedStmtNameToID map[string]uint32
+	// preparedStmtID is id of prepared statement.
+	preparedStmtID uint32
 	// SysWarningCount is the system variable "warning_count", because it is on the hot path, so we extract it from the systems
 	SysWarningCount int
 	// SysErrorCount is the system variable "error_count", because it is on the hot path, so we extract it from the systems
@@ -166,6 +180,9 @@ type SessionVars struct {
 	// ConnectionID is the connection id of the current session.
 	ConnectionID uint64
 
+	// User is the user identity with which the session login.
+	User *auth.UserIdentity
+
 	// PlanID is the unique id of logical and physical plan.
 	PlanID int
 
@@ -193,6 +210,9 @@ type SessionVars struct {
 	// StmtCtx holds variables for current executing statement.
 	StmtCtx *stmtctx.StatementContext
 
+	// MemQuotaQuery defines the memory quota of a query in bytes.
+	MemQuotaQuery int64
+
 	// AllowAggPushDown can be set to false to forbid aggregation push down.
 	AllowAggPushDown bool
 
@@ -286,6 +306,28 @@ type SessionVars struct {
 	// Killed is a flag to indicate that this query is killed.
 	Killed uint32
 
+	// TxnMode indicates should be pessimistic or optimistic.
+	TxnMode string
+
+	// LockWaitTimeout is the duration waiting for pessimistic lock in milliseconds.
+	LockWaitTimeout int64
+
+	// RetryLimit is the maximum number of retries when committing a transaction.
+	RetryLimit int64
+
+	// DisableTxnAutoRetry disables transaction auto retry.
+	DisableTxnAutoRetry bool
+
+	// CTEMaxRecursionDepth is the maximum number of iterations of a recursive common table expression.
+	CTEMaxRecursionDepth int
+
+	// SnapshotTS is used for reading history data. For simplicity, SnapshotTS only supports distsql request.
+	SnapshotTS uint64
+
+	// SnapshotInfoschema is used with SnapshotTS, when the schema version at snapshotTS less than current schema
+	// version, we load an old version schema for query.
+	SnapshotInfoschema interface{}
+
 	// ConnectionInfo indicates current connection info used by current session, only be lazy assigned by plugin.
 	ConnectionInfo *ConnectionInfo
 
@@ -344,8 +386,9 @@ func NewSessionVars() *SessionVars {
 	vars := &SessionVars{
 		Users:                       make(map[string]string),
 		systems:                     make(map[string]string),
+		PreparedStmts:               make(map[uint32]interface{}),
+		PreparedStmtNameToID:        make(map[string]uint32),
 		TxnCtx:                      &TransactionContext{},
-		KVVars:                      kv.NewVariables(),
 		StrictSQLMode:               true,
 		Status:                      mysql.ServerStatusAutocommit,
 		StmtCtx:                     new(stmtctx.StatementContext),
@@ -372,7 +415,14 @@ func NewSessionVars() *SessionVars {
 		EnableNoopFuncs:             DefTiDBEnableNoopFuncs,
 		replicaRead:                 kv.ReplicaReadLeader,
 		AllowRemoveAutoInc:          DefTiDBAllowRemoveAutoInc,
+		MemQuotaQuery:               DefTiDBMemQuotaQuery,
+		TxnMode:                     DefTiDBTxnMode,
+		LockWaitTimeout:             DefInnodbLockWaitTimeout * 1000,
+		RetryLimit:                  DefTiDBRetryLimit,
+		DisableTxnAutoRetry:         DefTiDBDisableTxnAutoRetry,
+		CTEMaxRecursionDepth:        DefCTEMaxRecursionDepth,
 	}
+	vars.KVVars = kv.NewVariables(&vars.Killed)
 	vars.Concurrency = Concurrency{
 		IndexLookupConcurrency:     DefIndexLookupConcurrency,
 		IndexSerialScanConcurrency: DefIndexSerialScanConcurrency,
@@ -384,9 +434,10 @@ func NewSessionVars() *SessionVars {
 		HashAggFinalConcurrency:    DefTiDBHashAggFinalConcurrency,
 	}
 	vars.BatchSize = BatchSize{
-		IndexLookupSize: DefIndexLookupSize,
-		InitChunkSize:   DefInitChunkSize,
-		MaxChunkSize:    DefMaxChunkSize,
+		IndexLookupSize:    DefIndexLookupSize,
+		IndexJoinBatchSize: DefIndexJoinBatchSize,
+		InitChunkSize:      DefInitChunkSize,
+		MaxChunkSize:       DefMaxChunkSize,
 	}
 	return vars
 }
@@ -432,6 +483,48 @@ func (s *SessionVars) CleanBuffers() {
 	s.GetWriteStmtBufs().clean()
 }
 
+// GetNextPreparedStmtID generates and returns the next session scope prepared statement id.
+func (s *SessionVars) GetNextPreparedStmtID() uint32 {
+	s.preparedStmtID++
+	return s.preparedStmtID
+}
+
+// AddPreparedStmt adds prepareStmt to current session and count in global.
+func (s *SessionVars) AddPreparedStmt(stmtID uint32, stmt interface{}) error {
+	if _, exists := s.PreparedStmts[stmtID]; !exists {
+		valStr, _ := s.GetSystemVar(MaxPreparedStmtCount)
+		maxPreparedStmtCount, err := strconv.ParseInt(valStr, 10, 64)
+		if err != nil {
+			maxPreparedStmtCount = DefMaxPreparedStmtCount
+		}
+		newPreparedStmtCount := atomic.AddInt64(&preparedStmtCount, 1)
+		if maxPreparedStmtCount >= 0 && newPreparedStmtCount > maxPreparedStmtCount {
+			atomic.AddInt64(&preparedStmtCount, -1)
+			return ErrMaxPreparedStmtCountReached.GenWithStackByArgs(maxPreparedStmtCount)
+		}
+	}
+	s.PreparedStmts[stmtID] = stmt
+	return nil
+}
+
+// RemovePreparedStmt removes preparedStmt from current session and decrease count in global.
+func (s *SessionVars) RemovePreparedStmt(stmtID uint32) {
+	if _, exists := s.PreparedStmts[stmtID]; !exists {
+		return
+	}
+	delete(s.PreparedStmts, stmtID)
+	atomic.AddInt64(&preparedStmtCount, -1)
+}
+
+// WithdrawAllPreparedStmt remove all preparedStmt in current session and decrease count in global.
+func (s *SessionVars) WithdrawAllPreparedStmt() {
+	psCount := len(s.PreparedStmts)
+	if psCount == 0 {
+		return
+	}
+	atomic.AddInt64(&preparedStmtCount, -int64(psCount))
+}
+
 // AllocPlanColumnID allocates column id for plan.
 func (s *SessionVars) AllocPlanColumnID() int64 {
 	s.PlanColumnID++
@@ -553,6 +646,12 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		s.StrictSQLMode = sqlMode.HasStrictMode()
 		s.SQLMode = sqlMode
 		s.SetStatusFlag(mysql.ServerStatusNoBackslashEscaped, sqlMode.HasNoBackslashEscapesMode())
+	case TimeZone:
+		tz, err := parseTimeZone(val)
+		if err != nil {
+			return err
+		}
+		s.TimeZone = tz
 	case AutoCommit:
 		isAutocommit := TiDBOptOn(val)
 		s.SetStatusFlag(mysql.ServerStatusAutocommit, isAutocommit)
@@ -598,6 +697,8 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		s.IndexLookupJoinConcurrency = tidbOptPositiveInt32(val, DefIndexLookupJoinConcurrency)
 	case TiDBIndexLookupSize:
 		s.IndexLookupSize = tidbOptPositiveInt32(val, DefIndexLookupSize)
+	case TiDBIndexJoinBatchSize:
+		s.IndexJoinBatchSize = tidbOptPositiveInt32(val, DefIndexJoinBatchSize)
 	case TiDBHashJoinConcurrency:
 		s.HashJoinConcurrency = tidbOptPositiveInt32(val, DefTiDBHashJoinConcurrency)
 	case TiDBProjectionConcurrency:
@@ -622,6 +723,8 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		s.MaxChunkSize = tidbOptPositiveInt32(val, DefMaxChunkSize)
 	case TiDBInitChunkSize:
 		s.InitChunkSize = tidbOptPositiveInt32(val, DefInitChunkSize)
+	case TiDBMemQuotaQuery:
+		s.MemQuotaQuery = tidbOptInt64(val, DefTiDBMemQuotaQuery)
 	case TiDBGeneralLog:
 		atomic.StoreUint32(&ProcessGeneralLog, uint32(tidbOptPositiveInt32(val, DefTiDBGeneralLog)))
 	case TiDBEnableCascadesPlanner:
@@ -650,6 +753,22 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		}
 	case TiDBAllowRemoveAutoInc:
 		s.AllowRemoveAutoInc = TiDBOptOn(val)
+	case TiDBTxnMode:
+		s.TxnMode = strings.ToUpper(val)
+	case InnodbLockWaitTimeout:
+		lockWaitSec := tidbOptInt64(val, DefInnodbLockWaitTimeout)
+		s.LockWaitTimeout = lockWaitSec * 1000
+	case TiDBRetryLimit:
+		s.RetryLimit = tidbOptInt64(val, DefTiDBRetryLimit)
+	case TiDBDisableTxnAutoRetry:
+		s.DisableTxnAutoRetry = TiDBOptOn(val)
+	case CTEMaxRecursionDepth:
+		s.CTEMaxRecursionDepth = int(tidbOptInt64(val, DefCTEMaxRecursionDepth))
+	case TiDBSnapshot:
+		err := setSnapshotTS(s, val)
+		if err != nil {
+			return err
+		}
 	// It's a global variable, but it also wants to be cached in server.
 	case TiDBMaxDeltaSchemaCount:
 		SetMaxDeltaSchemaCount(tidbOptInt64(val, DefTiDBMaxDeltaSchemaCount))
@@ -739,6 +858,9 @@ type BatchSize struct {
 	// IndexLookupSize is the number of handles for an index lookup task in index double read executor.
 	IndexLookupSize int
 
+	// IndexJoinBatchSize is the batch size of a index lookup join.
+	IndexJoinBatchSize int
+
 	// InitChunkSize defines init row count of a Chunk during query execution.
 	InitChunkSize int
 
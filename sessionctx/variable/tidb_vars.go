@@ -123,6 +123,12 @@ const (
 	// to fetch inner rows and join the matched (outer, inner) row pairs.
 	TiDBIndexLookupJoinConcurrency = "tidb_index_lookup_join_concurrency"
 
+	// tidb_index_join_batch_size is used to set the batch size of a index lookup join.
+	// The index lookup join fetches batches of data from outer executor and constructs ranges for inner executor.
+	// This value controls how much of data in a batch to do the index join.
+	// Large value may reduce the latency but consumes more system resource.
+	TiDBIndexJoinBatchSize = "tidb_index_join_batch_size"
+
 	// tidb_index_serial_scan_concurrency is used for controlling the concurrency of index scan operation
 	// when we need to keep the data output order the same as the order of index data.
 	TiDBIndexSerialScanConcurrency = "tidb_index_serial_scan_concurrency"
@@ -133,6 +139,11 @@ const (
 	// TiDBInitChunkSize is used to control the init chunk size during query execution.
 	TiDBInitChunkSize = "tidb_init_chunk_size"
 
+	// tidb_mem_quota_query controls the memory quota of a query in bytes.
+	// Memory hungry executors like Sort spill their data to disk when the quota is exceeded.
+	// A value less than or equal to 0 means no limit.
+	TiDBMemQuotaQuery = "tidb_mem_quota_query"
+
 	// tidb_enable_cascades_planner is used to control whether to enable the cascades planner.
 	TiDBEnableCascadesPlanner = "tidb_enable_cascades_planner"
 
@@ -211,6 +222,23 @@ const (
 
 	// TiDBEnableNoopFuncs set true will enable using fake funcs(like get_lock release_lock)
 	TiDBEnableNoopFuncs = "tidb_enable_noop_functions"
+
+	// tidb_txn_mode is used to control the transaction behavior, it can be "pessimistic" or "optimistic".
+	// An empty value means optimistic.
+	// A pessimistic transaction locks the modified rows when the DML statement executes, so that
+	// concurrent writers wait for the lock instead of meeting write conflicts at commit time.
+	TiDBTxnMode = "tidb_txn_mode"
+
+	// tidb_retry_limit is the maximum number of retries when committing a transaction.
+	TiDBRetryLimit = "tidb_retry_limit"
+
+	// tidb_disable_txn_auto_retry disables transaction auto retry for explicit transactions.
+	// Auto-commit statements and internal transactions are still retried.
+	TiDBDisableTxnAutoRetry = "tidb_disable_txn_auto_retry"
+
+	// tidb_gc_life_time is how long the old MVCC versions are retained, the GC worker removes
+	// the versions that are invisible to the snapshots taken within this duration.
+	TiDBGCLifeTime = "tidb_gc_life_time"
 )
 
 // Default TiDB system variable values.
@@ -220,6 +248,7 @@ const (
 	DefIndexLookupJoinConcurrency    = 4
 	DefIndexSerialScanConcurrency    = 1
 	DefIndexLookupSize               = 20000
+	DefIndexJoinBatchSize            = 25000
 	DefDistSQLScanConcurrency        = 15
 	DefBuildStatsConcurrency         = 4
 	DefSkipUTF8Check                 = false
@@ -240,6 +269,7 @@ const (
 	DefCurretTS                      = 0
 	DefInitChunkSize                 = 32
 	DefMaxChunkSize                  = 1024
+	DefTiDBMemQuotaQuery             = 1 << 30 // 1GB.
 	DefMaxPreparedStmtCount          = -1
 	DefWaitTimeout                   = 0
 	DefTiDBGeneralLog                = 0
@@ -264,6 +294,9 @@ const (
 	DefTiDBEnableNoopFuncs           = false
 	DefTiDBAllowRemoveAutoInc        = false
 	DefInnodbLockWaitTimeout         = 50 // 50s
+	DefCTEMaxRecursionDepth          = 1000
+	DefTiDBTxnMode                   = ""
+	DefTiDBGCLifeTime                = "10m0s"
 )
 
 // Process global variables.
@@ -24,6 +24,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 )
 
@@ -297,6 +298,8 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 		return checkUInt64SystemVar(name, value, 0, 2, vars)
 	case InnodbLockWaitTimeout:
 		return checkUInt64SystemVar(name, value, 1, 1073741824, vars)
+	case CTEMaxRecursionDepth:
+		return checkUInt64SystemVar(name, value, 0, 4294967295, vars)
 	// See "https://dev.mysql.com/doc/refman/5.7/en/server-system-variables.html#sysvar_max_allowed_packet"
 	case MaxAllowedPacket:
 		return checkUInt64SystemVar(name, value, 1024, MaxOfMaxAllowedPacket, vars)
@@ -364,7 +367,8 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 		return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
 	case TiDBSkipUTF8Check, TiDBOptAggPushDown, TiDBOptInSubqToJoinAndAgg,
 		TiDBEnableCascadesPlanner, TiDBEnableNoopFuncs,
-		TiDBScatterRegion, TiDBGeneralLog, TiDBConstraintCheckInPlace, TiDBEnableVectorizedExpression:
+		TiDBScatterRegion, TiDBGeneralLog, TiDBConstraintCheckInPlace, TiDBEnableVectorizedExpression,
+		TiDBDisableTxnAutoRetry:
 		fallthrough
 	case GeneralLog, AvoidTemporalUpgrade, BigTables, CheckProxyUsers, LogBin,
 		CoreFile, EndMakersInJSON, SQLLogBin, OfflineMode, PseudoSlaveMode, LowPriorityUpdates,
@@ -417,6 +421,7 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 		return checkUInt64SystemVar(name, value, uint64(0), math.MaxInt64, vars)
 	case TiDBIndexLookupConcurrency, TiDBIndexLookupJoinConcurrency,
 		TiDBIndexLookupSize,
+		TiDBIndexJoinBatchSize,
 		TiDBHashJoinConcurrency,
 		TiDBHashAggPartialConcurrency,
 		TiDBHashAggFinalConcurrency,
@@ -431,7 +436,7 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 			return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
 		}
 		return value, nil
-	case TiDBOptCorrelationExpFactor:
+	case TiDBOptCorrelationExpFactor, TiDBRetryLimit:
 		v, err := strconv.Atoi(value)
 		if err != nil {
 			return value, ErrWrongTypeForVar.GenWithStackByArgs(name)
@@ -518,6 +523,10 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 			return value, errors.Errorf("tidb_max_chunk_size(%d) cannot be smaller than %d", v, maxChunkSizeLowerBound)
 		}
 		return value, nil
+	case TiDBMemQuotaQuery:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return value, ErrWrongTypeForVar.GenWithStackByArgs(name)
+		}
 	case TiDBOptJoinReorderThreshold:
 		v, err := strconv.Atoi(value)
 		if err != nil {
@@ -541,6 +550,12 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 			return "leader", nil
 		}
 		return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
+	case TimeZone:
+		if strings.EqualFold(value, "SYSTEM") {
+			return "SYSTEM", nil
+		}
+		_, err := parseTimeZone(value)
+		return value, err
 	case TiDBAllowRemoveAutoInc:
 		switch {
 		case strings.EqualFold(value, "ON") || value == "1":
@@ -549,6 +564,17 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 			return "off", nil
 		}
 		return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
+	case TiDBGCLifeTime:
+		if _, err := time.ParseDuration(value); err != nil {
+			return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
+		}
+		return value, nil
+	case TiDBTxnMode:
+		switch strings.ToLower(value) {
+		case "pessimistic", "optimistic", "":
+			return strings.ToLower(value), nil
+		}
+		return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
 	}
 	return value, nil
 }
@@ -558,6 +584,67 @@ func TiDBOptOn(opt string) bool {
 	return strings.EqualFold(opt, "ON") || opt == "1"
 }
 
+// parseTimeZone parses the value of the time_zone variable, which can be
+// 'SYSTEM', a named time zone such as 'Asia/Shanghai', or an offset from UTC
+// such as '+10:00' or '-6:00'.
+func parseTimeZone(s string) (*time.Location, error) {
+	if strings.EqualFold(s, "SYSTEM") {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(s)
+	if err == nil {
+		return loc, nil
+	}
+
+	// The time zone's value should in [-12:59,+14:00].
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		d, err := types.ParseDuration(nil, s[1:], 0)
+		if err == nil && strings.Contains(s, ":") {
+			if s[0] == '-' {
+				if d.Duration > 12*time.Hour+59*time.Minute {
+					return nil, ErrUnknownTimeZone.GenWithStackByArgs(s)
+				}
+			} else {
+				if d.Duration > 14*time.Hour {
+					return nil, ErrUnknownTimeZone.GenWithStackByArgs(s)
+				}
+			}
+
+			ofst := int(d.Duration / time.Second)
+			if s[0] == '-' {
+				ofst = -ofst
+			}
+			return time.FixedZone("", ofst), nil
+		}
+	}
+
+	return nil, ErrUnknownTimeZone.GenWithStackByArgs(s)
+}
+
+// setSnapshotTS sets the snapshot TS of tidb_snapshot, the value can be a TSO
+// or a datetime in the session time zone. An empty value disables the snapshot.
+func setSnapshotTS(s *SessionVars, sVal string) error {
+	if sVal == "" {
+		s.SnapshotTS = 0
+		return nil
+	}
+
+	if tso, err := strconv.ParseUint(sVal, 10, 64); err == nil {
+		s.SnapshotTS = tso
+		return nil
+	}
+
+	t, err := types.ParseTime(s.StmtCtx, sVal, mysql.TypeTimestamp, types.MaxFsp)
+	if err != nil {
+		return err
+	}
+
+	t1, err := t.GoTime(s.Location())
+	s.SnapshotTS = GoTimeToTS(t1)
+	return err
+}
+
 func tidbOptPositiveInt32(opt string, defaultVal int) int {
 	val, err := strconv.Atoi(opt)
 	if err != nil || val <= 0 {
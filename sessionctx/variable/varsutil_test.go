@@ -18,6 +18,7 @@ import (
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/testleak"
 )
 
@@ -53,6 +54,7 @@ func (s *testVarsutilSuite) TestNewSessionVars(c *C) {
 	vars := NewSessionVars()
 
 	c.Assert(vars.IndexLookupSize, Equals, DefIndexLookupSize)
+	c.Assert(vars.IndexJoinBatchSize, Equals, DefIndexJoinBatchSize)
 	c.Assert(vars.IndexLookupConcurrency, Equals, DefIndexLookupConcurrency)
 	c.Assert(vars.IndexSerialScanConcurrency, Equals, DefIndexSerialScanConcurrency)
 	c.Assert(vars.IndexLookupJoinConcurrency, Equals, DefIndexLookupJoinConcurrency)
@@ -65,6 +67,11 @@ func (s *testVarsutilSuite) TestNewSessionVars(c *C) {
 	c.Assert(vars.EnableRadixJoin, Equals, DefTiDBUseRadixJoin)
 	c.Assert(vars.AllowWriteRowID, Equals, DefOptWriteRowID)
 	c.Assert(vars.TiDBOptJoinReorderThreshold, Equals, DefTiDBOptJoinReorderThreshold)
+	c.Assert(vars.TxnMode, Equals, DefTiDBTxnMode)
+	c.Assert(vars.LockWaitTimeout, Equals, int64(DefInnodbLockWaitTimeout*1000))
+	c.Assert(vars.RetryLimit, Equals, int64(DefTiDBRetryLimit))
+	c.Assert(vars.DisableTxnAutoRetry, Equals, DefTiDBDisableTxnAutoRetry)
+	c.Assert(vars.CTEMaxRecursionDepth, Equals, DefCTEMaxRecursionDepth)
 
 	assertFieldsGreaterThanZero(c, reflect.ValueOf(vars.Concurrency))
 	assertFieldsGreaterThanZero(c, reflect.ValueOf(vars.BatchSize))
@@ -156,6 +163,19 @@ func (s *testVarsutilSuite) TestValidate(c *C) {
 		{TiDBOptJoinReorderThreshold, "a", true},
 		{TiDBOptJoinReorderThreshold, "-1", true},
 		{TiDBReplicaRead, "invalid", true},
+		{TiDBTxnMode, "pessimistic", false},
+		{TiDBTxnMode, "OPTIMISTIC", false},
+		{TiDBTxnMode, "", false},
+		{TiDBTxnMode, "invalid", true},
+		{TiDBRetryLimit, "0", false},
+		{TiDBRetryLimit, "a", true},
+		{TiDBRetryLimit, "-1", true},
+		{TiDBDisableTxnAutoRetry, "OFF", false},
+		{TiDBDisableTxnAutoRetry, "2", true},
+		{CTEMaxRecursionDepth, "0", false},
+		{CTEMaxRecursionDepth, "a", true},
+		{TiDBGCLifeTime, "24h", false},
+		{TiDBGCLifeTime, "10", true},
 	}
 
 	for _, t := range tests {
@@ -168,3 +188,23 @@ func (s *testVarsutilSuite) TestValidate(c *C) {
 	}
 
 }
+
+func (s *testVarsutilSuite) TestSetSnapshotTS(c *C) {
+	v := NewSessionVars()
+	v.TimeZone = time.UTC
+
+	err := SetSessionSystemVar(v, TiDBSnapshot, types.NewStringDatum("416215545124913152"))
+	c.Assert(err, IsNil)
+	c.Assert(v.SnapshotTS, Equals, uint64(416215545124913152))
+
+	err = SetSessionSystemVar(v, TiDBSnapshot, types.NewStringDatum("2020-04-01 10:00:00"))
+	c.Assert(err, IsNil)
+	c.Assert(v.SnapshotTS, Equals, GoTimeToTS(time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)))
+
+	err = SetSessionSystemVar(v, TiDBSnapshot, types.NewStringDatum("invalid"))
+	c.Assert(err, NotNil)
+
+	err = SetSessionSystemVar(v, TiDBSnapshot, types.NewStringDatum(""))
+	c.Assert(err, IsNil)
+	c.Assert(v.SnapshotTS, Equals, uint64(0))
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
@@ -133,7 +134,7 @@ func (h *Handle) Update(is infoschema.InfoSchema) error {
 		modifyCount := row.GetInt64(2)
 		count := row.GetInt64(3)
 		lastVersion = version
-		table, ok := is.TableByID(physicalID)
+		table, ok := h.getTableByPhysicalID(is, physicalID)
 		if !ok {
 			logutil.BgLogger().Debug("unknown physical ID in stats meta table, maybe it has been dropped", zap.Int64("ID", physicalID))
 			deletedTableIDs = append(deletedTableIDs, physicalID)
@@ -160,6 +161,35 @@ func (h *Handle) Update(is infoschema.InfoSchema) error {
 	return nil
 }
 
+func (h *Handle) getTableByPhysicalID(is infoschema.InfoSchema, physicalID int64) (table.Table, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if is.SchemaMetaVersion() != h.mu.schemaVersion {
+		h.mu.schemaVersion = is.SchemaMetaVersion()
+		h.mu.pid2tid = buildPartitionID2TableID(is)
+	}
+	if id, ok := h.mu.pid2tid[physicalID]; ok {
+		return is.TableByID(id)
+	}
+	return is.TableByID(physicalID)
+}
+
+func buildPartitionID2TableID(is infoschema.InfoSchema) map[int64]int64 {
+	mapper := make(map[int64]int64)
+	for _, db := range is.AllSchemas() {
+		for _, tbl := range db.Tables {
+			pi := tbl.GetPartitionInfo()
+			if pi == nil {
+				continue
+			}
+			for _, def := range pi.Definitions {
+				mapper[def.ID] = tbl.ID
+			}
+		}
+	}
+	return mapper
+}
+
 func getFullTableName(is infoschema.InfoSchema, tblInfo *model.TableInfo) string {
 	for _, schema := range is.AllSchemas() {
 		if t, err := is.TableByName(schema.Name, tblInfo.Name); err == nil {
@@ -87,6 +87,15 @@ func (hg *Histogram) calcFraction(index int, value *types.Datum) float64 {
 		return calcFraction(float64(lower.GetInt64(0)), float64(upper.GetInt64(0)), float64(value.GetInt64()))
 	case types.KindUint64:
 		return calcFraction(float64(lower.GetUint64(0)), float64(upper.GetUint64(0)), float64(value.GetUint64()))
+	case types.KindMysqlDecimal:
+		l, _ := lower.GetMyDecimal(0).ToFloat64()
+		u, _ := upper.GetMyDecimal(0).ToFloat64()
+		v, _ := value.GetMysqlDecimal().ToFloat64()
+		return calcFraction(l, u, v)
+	case types.KindMysqlDuration:
+		return calcFraction(float64(lower.GetDuration(0, 0).Duration), float64(upper.GetDuration(0, 0).Duration), float64(value.GetMysqlDuration().Duration))
+	case types.KindMysqlTime:
+		return calcFraction(convertTimeToScalar(lower.GetTime(0)), convertTimeToScalar(upper.GetTime(0)), convertTimeToScalar(value.GetMysqlTime()))
 	case types.KindBytes, types.KindString:
 		return calcFraction(hg.scalars[index].lower, hg.scalars[index].upper, convertDatumToScalar(value, hg.scalars[index].commonPfxLen))
 	}
@@ -106,6 +115,14 @@ func commonPrefixLength(lower, upper []byte) int {
 	return minLen
 }
 
+// convertTimeToScalar converts a time into the number of days since
+// 0000-00-00, the clock part is kept as a fraction of a day.
+func convertTimeToScalar(t types.Time) float64 {
+	days := float64(types.DateDiff(t.CoreTime(), types.ZeroCoreTime))
+	secs := float64(t.Hour()*3600+t.Minute()*60+t.Second()) + float64(t.Microsecond())/1e6
+	return days + secs/(24*3600)
+}
+
 func convertBytesToScalar(value []byte) float64 {
 	// Bytes type is viewed as a base-256 value, so we only consider at most 8 bytes.
 	var buf [8]byte
@@ -25,7 +25,10 @@ import (
 
 type aggCtxsMapper map[string][]*aggregation.AggEvaluateContext
 
-var _ executor = &hashAggExec{}
+var (
+	_ executor = &hashAggExec{}
+	_ executor = &streamAggExec{}
+)
 
 type hashAggExec struct {
 	evalCtx           *evalContext
@@ -180,3 +183,149 @@ func (e *hashAggExec) getContexts(groupKey []byte) []*aggregation.AggEvaluateCon
 	}
 	return aggCtxs
 }
+
+// streamAggExec aggregates the rows which are ordered by the group-by items,
+// a group is output once a row of the next group is read.
+type streamAggExec struct {
+	evalCtx           *evalContext
+	aggExprs          []aggregation.Aggregation
+	aggCtxs           []*aggregation.AggEvaluateContext
+	groupByExprs      []expression.Expression
+	relatedColOffsets []int
+	row               []types.Datum
+	tmpGroupByRow     []types.Datum
+	currGroupByRow    []types.Datum
+	nextGroupByRow    []types.Datum
+	currGroupByValues [][]byte
+	executed          bool
+	hasData           bool
+	count             int64
+
+	src executor
+}
+
+func (e *streamAggExec) SetSrcExec(exec executor) {
+	e.src = exec
+}
+
+func (e *streamAggExec) GetSrcExec() executor {
+	return e.src
+}
+
+func (e *streamAggExec) ResetCounts() {
+	e.src.ResetCounts()
+}
+
+func (e *streamAggExec) Counts() []int64 {
+	return e.src.Counts()
+}
+
+// meetNewGroup checks whether the current row belongs to a new group, it
+// evaluates the group-by items of the row decoded in e.row.
+func (e *streamAggExec) meetNewGroup() (bool, error) {
+	if len(e.groupByExprs) == 0 {
+		return false, nil
+	}
+
+	e.tmpGroupByRow = e.tmpGroupByRow[:0]
+	matched, firstGroup := true, false
+	if e.nextGroupByRow == nil {
+		matched, firstGroup = false, true
+	}
+	for i, item := range e.groupByExprs {
+		d, err := item.Eval(chunk.MutRowFromDatums(e.row).ToRow())
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if matched {
+			c, err := d.CompareDatum(e.evalCtx.sc, &e.nextGroupByRow[i])
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			matched = c == 0
+		}
+		e.tmpGroupByRow = append(e.tmpGroupByRow, d)
+	}
+	if firstGroup {
+		e.currGroupByRow = types.CloneRow(e.tmpGroupByRow)
+	}
+	if matched {
+		return false, nil
+	}
+	e.nextGroupByRow = types.CloneRow(e.tmpGroupByRow)
+	return !firstGroup, nil
+}
+
+func (e *streamAggExec) Next(ctx context.Context) (retRow [][]byte, err error) {
+	e.count++
+	if e.executed {
+		return nil, nil
+	}
+
+	for {
+		values, err := e.src.Next(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if values == nil {
+			e.executed = true
+			if !e.hasData && len(e.groupByExprs) > 0 {
+				return nil, nil
+			}
+			return e.getPartialResult()
+		}
+
+		e.hasData = true
+		err = e.evalCtx.decodeRelatedColumnVals(e.relatedColOffsets, values, e.row)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		newGroup, err := e.meetNewGroup()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if newGroup {
+			retRow, err = e.getPartialResult()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		for i, agg := range e.aggExprs {
+			err = agg.Update(e.aggCtxs[i], e.evalCtx.sc, chunk.MutRowFromDatums(e.row).ToRow())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if newGroup {
+			return retRow, nil
+		}
+	}
+}
+
+// getPartialResult returns the partial results of the current group and
+// starts a new group.
+func (e *streamAggExec) getPartialResult() ([][]byte, error) {
+	value := make([][]byte, 0, len(e.groupByExprs)+2*len(e.aggExprs))
+	for i, agg := range e.aggExprs {
+		partialResults := agg.GetPartialResult(e.aggCtxs[i])
+		for _, result := range partialResults {
+			data, err := codec.EncodeValue(e.evalCtx.sc, nil, result)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			value = append(value, data)
+		}
+		// Clear the aggregate context.
+		e.aggCtxs[i] = agg.CreateContext(e.evalCtx.sc)
+	}
+	e.currGroupByValues = e.currGroupByValues[:0]
+	for _, d := range e.currGroupByRow {
+		buf, err := codec.EncodeValue(e.evalCtx.sc, nil, d)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		e.currGroupByValues = append(e.currGroupByValues, buf)
+	}
+	e.currGroupByRow = types.CloneRow(e.nextGroupByRow)
+	return append(value, e.currGroupByValues...), nil
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/kv"
 )
 
 func TestT(t *testing.T) {
@@ -679,3 +680,55 @@ func (s *testMVCCLevelDB) TestTxnHeartBeat(c *C) {
 	_, err = s.store.TxnHeartBeat([]byte("pk"), 5, 1000)
 	c.Assert(err, NotNil)
 }
+
+func lockMutations(keys ...string) []*kvrpcpb.Mutation {
+	var mutations []*kvrpcpb.Mutation
+	for _, key := range keys {
+		mutations = append(mutations, &kvrpcpb.Mutation{
+			Op:  kvrpcpb.Op_Lock,
+			Key: []byte(key),
+		})
+	}
+	return mutations
+}
+
+func (s *testMVCCLevelDB) TestPessimisticLock(c *C) {
+	s.mustPutOK(c, "k1", "v1", 1, 2)
+
+	// Written after forUpdateTS.
+	errs := s.store.PessimisticLock(lockMutations("k1"), []byte("k1"), 1, 1, 100, kv.LockAlwaysWait)
+	s.mustWriteWriteConflict(c, errs, 0)
+
+	errs = s.store.PessimisticLock(lockMutations("k1", "k2"), []byte("k1"), 3, 3, 100, kv.LockAlwaysWait)
+	c.Assert(errs, DeepEquals, []error{nil, nil})
+	// Locking again by the same transaction is ok.
+	errs = s.store.PessimisticLock(lockMutations("k1"), []byte("k1"), 3, 4, 100, kv.LockAlwaysWait)
+	c.Assert(errs, DeepEquals, []error{nil})
+	// Pessimistic locks don't block reads.
+	s.mustGetOK(c, "k1", 5, "v1")
+
+	errs = s.store.PessimisticLock(lockMutations("k3"), []byte("k3"), 5, 5, 100, kv.LockAlwaysWait)
+	c.Assert(errs, DeepEquals, []error{nil})
+	// Txn 5 waits for txn 3.
+	errs = s.store.PessimisticLock(lockMutations("k1"), []byte("k3"), 5, 5, 100, kv.LockAlwaysWait)
+	_, ok := errs[0].(*ErrLocked)
+	c.Assert(ok, IsTrue)
+	// Txn 3 waits for txn 5, it's a deadlock.
+	errs = s.store.PessimisticLock(lockMutations("k3"), []byte("k1"), 3, 6, 100, kv.LockAlwaysWait)
+	_, ok = errs[0].(*ErrDeadlock)
+	c.Assert(ok, IsTrue)
+
+	// The prewritten lock is not removed by pessimistic rollback.
+	s.mustPrewriteOK(c, putMutations("k2", "v2"), "k1", 3)
+	errs = s.store.PessimisticRollback([][]byte{[]byte("k1"), []byte("k2")}, 3, 6)
+	c.Assert(errs, DeepEquals, []error{nil, nil})
+	s.mustGetErr(c, "k2", 7)
+	s.mustRollbackOK(c, [][]byte{[]byte("k2")}, 3)
+
+	// Txn 5 is not blocked now.
+	errs = s.store.PessimisticLock(lockMutations("k1"), []byte("k3"), 5, 7, 100, kv.LockNoWait)
+	c.Assert(errs, DeepEquals, []error{nil})
+	s.mustPrewriteOK(c, putMutations("k1", "v5"), "k3", 5)
+	s.mustCommitOK(c, [][]byte{[]byte("k1"), []byte("k3")}, 5, 8)
+	s.mustGetOK(c, "k1", 9, "v5")
+}
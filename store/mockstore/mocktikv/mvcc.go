@@ -234,6 +234,8 @@ type MVCCStore interface {
 	Prewrite(req *kvrpcpb.PrewriteRequest) []error
 	Commit(keys [][]byte, startTS, commitTS uint64) error
 	Rollback(keys [][]byte, startTS uint64) error
+	PessimisticLock(mutations []*kvrpcpb.Mutation, primary []byte, startTS, forUpdateTS, ttl uint64, waitTimeout int64) []error
+	PessimisticRollback(keys [][]byte, startTS, forUpdateTS uint64) []error
 	Cleanup(key []byte, startTS, currentTS uint64) error
 	ScanLock(startKey, endKey []byte, maxTS uint64) ([]*kvrpcpb.LockInfo, error)
 	TxnHeartBeat(primaryKey []byte, startTS uint64, adviseTTL uint64) (uint64, error)
@@ -25,10 +25,13 @@ import (
 	"github.com/pingcap/goleveldb/leveldb/opt"
 	"github.com/pingcap/goleveldb/leveldb/storage"
 	"github.com/pingcap/goleveldb/leveldb/util"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/store/tikv/oracle"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/deadlock"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/spaolacci/murmur3"
 	"go.uber.org/zap"
 )
 
@@ -61,6 +64,8 @@ type MVCCLevelDB struct {
 	// leveldb can not guarantee multiple operations to be atomic, for example, read
 	// then write, another write may happen during it, so this lock is necessory.
 	mu sync.RWMutex
+
+	deadlockDetector *deadlock.Detector
 }
 
 const lockVer uint64 = math.MaxUint64
@@ -121,7 +126,7 @@ func NewMVCCLevelDB(path string) (*MVCCLevelDB, error) {
 		d, err = leveldb.OpenFile(path, &opt.Options{BlockCacheCapacity: 600 * 1024 * 1024})
 	}
 
-	return &MVCCLevelDB{db: d}, errors.Trace(err)
+	return &MVCCLevelDB{db: d, deadlockDetector: deadlock.NewDetector()}, errors.Trace(err)
 }
 
 // Iterator wraps iterator.Iterator to provide Valid() method.
@@ -536,6 +541,139 @@ func prewriteMutation(db *leveldb.DB, batch *leveldb.Batch,
 	return nil
 }
 
+// PessimisticLock implements the MVCCStore interface.
+func (mvcc *MVCCLevelDB) PessimisticLock(mutations []*kvrpcpb.Mutation, primary []byte, startTS, forUpdateTS, ttl uint64, waitTimeout int64) []error {
+	mvcc.mu.Lock()
+	defer mvcc.mu.Unlock()
+
+	anyError := false
+	batch := &leveldb.Batch{}
+	errs := make([]error, 0, len(mutations))
+	for _, m := range mutations {
+		err := mvcc.pessimisticLockMutation(batch, m, startTS, forUpdateTS, primary, ttl)
+		errs = append(errs, err)
+		if err != nil {
+			anyError = true
+		}
+		if lockErr, ok := err.(*ErrLocked); ok && waitTimeout != kv.LockNoWait {
+			keyHash := murmur3.Sum64(m.Key)
+			if dl := mvcc.deadlockDetector.Detect(startTS, lockErr.StartTS, keyHash); dl != nil {
+				errs[len(errs)-1] = &ErrDeadlock{
+					LockKey:        m.Key,
+					LockTS:         lockErr.StartTS,
+					DealockKeyHash: dl.KeyHash,
+				}
+			}
+		}
+	}
+	if anyError {
+		return errs
+	}
+	if err := mvcc.db.Write(batch, nil); err != nil {
+		return []error{err}
+	}
+	// All the keys are locked, the transaction doesn't wait for others any more.
+	mvcc.deadlockDetector.CleanUp(startTS)
+	return errs
+}
+
+func (mvcc *MVCCLevelDB) pessimisticLockMutation(batch *leveldb.Batch, mutation *kvrpcpb.Mutation, startTS, forUpdateTS uint64, primary []byte, ttl uint64) error {
+	startKey := mvccEncode(mutation.Key, lockVer)
+	iter := newIterator(mvcc.db, &util.Range{
+		Start: startKey,
+	})
+	defer iter.Release()
+
+	dec := lockDecoder{
+		expectKey: mutation.Key,
+	}
+	ok, err := dec.Decode(iter)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ok {
+		if dec.lock.startTS != startTS {
+			return dec.lock.lockErr(mutation.Key)
+		}
+		// The key is already locked by this transaction.
+		return nil
+	}
+
+	// A key written after forUpdateTS can not be locked, the statement should
+	// be retried with a newer forUpdateTS.
+	if err = checkConflictValue(iter, mutation, forUpdateTS+1); err != nil {
+		if conflict, ok := err.(*ErrConflict); ok {
+			conflict.StartTS = forUpdateTS
+		}
+		return err
+	}
+
+	lock := mvccLock{
+		startTS:     startTS,
+		primary:     primary,
+		op:          kvrpcpb.Op_Lock,
+		ttl:         ttl,
+		forUpdateTS: forUpdateTS,
+	}
+	writeKey := mvccEncode(mutation.Key, lockVer)
+	writeValue, err := lock.MarshalBinary()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	batch.Put(writeKey, writeValue)
+	return nil
+}
+
+// PessimisticRollback implements the MVCCStore interface.
+func (mvcc *MVCCLevelDB) PessimisticRollback(keys [][]byte, startTS, forUpdateTS uint64) []error {
+	mvcc.mu.Lock()
+	defer mvcc.mu.Unlock()
+
+	anyError := false
+	batch := &leveldb.Batch{}
+	errs := make([]error, 0, len(keys))
+	for _, key := range keys {
+		err := pessimisticRollbackKey(mvcc.db, batch, key, startTS, forUpdateTS)
+		errs = append(errs, err)
+		if err != nil {
+			anyError = true
+		}
+	}
+	if anyError {
+		return errs
+	}
+	if err := mvcc.db.Write(batch, nil); err != nil {
+		return []error{err}
+	}
+	mvcc.deadlockDetector.CleanUp(startTS)
+	return errs
+}
+
+func pessimisticRollbackKey(db *leveldb.DB, batch *leveldb.Batch, key []byte, startTS, forUpdateTS uint64) error {
+	startKey := mvccEncode(key, lockVer)
+	iter := newIterator(db, &util.Range{
+		Start: startKey,
+	})
+	defer iter.Release()
+
+	dec := lockDecoder{
+		expectKey: key,
+	}
+	ok, err := dec.Decode(iter)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Only the pessimistic lock of the transaction is removed, a prewritten lock
+	// must be rolled back by Rollback.
+	if ok {
+		lock := dec.lock
+		if lock.op == kvrpcpb.Op_Lock && lock.startTS == startTS && lock.forUpdateTS != 0 && lock.forUpdateTS <= forUpdateTS {
+			batch.Delete(startKey)
+		}
+	}
+	return nil
+}
+
 // Commit implements the MVCCStore interface.
 func (mvcc *MVCCLevelDB) Commit(keys [][]byte, startTS, commitTS uint64) error {
 	mvcc.mu.Lock()
@@ -550,6 +688,7 @@ func (mvcc *MVCCLevelDB) Commit(keys [][]byte, startTS, commitTS uint64) error {
 			return errors.Trace(err)
 		}
 	}
+	mvcc.deadlockDetector.CleanUp(startTS)
 	return mvcc.db.Write(batch, nil)
 }
 
@@ -626,6 +765,7 @@ func (mvcc *MVCCLevelDB) Rollback(keys [][]byte, startTS uint64) error {
 			return errors.Trace(err)
 		}
 	}
+	mvcc.deadlockDetector.CleanUp(startTS)
 	return mvcc.db.Write(batch, nil)
 }
 
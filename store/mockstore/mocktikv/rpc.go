@@ -14,6 +14,7 @@
 package mocktikv
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -253,6 +254,40 @@ func (h *rpcHandler) handleKvCommit(req *kvrpcpb.CommitRequest) *kvrpcpb.CommitR
 	return &resp
 }
 
+func (h *rpcHandler) handleKvPessimisticLock(req *tikvrpc.PessimisticLockRequest) *tikvrpc.PessimisticLockResponse {
+	for _, m := range req.Mutations {
+		if !h.checkKeyInRegion(m.Key) {
+			panic("KvPessimisticLock: key not in region")
+		}
+	}
+	errs := h.mvccStore.PessimisticLock(req.Mutations, req.PrimaryLock, req.StartVersion, req.ForUpdateTs, req.LockTtl, req.WaitTimeout)
+	resp := &tikvrpc.PessimisticLockResponse{}
+	for _, err := range errs {
+		if dl, ok := errors.Cause(err).(*ErrDeadlock); ok {
+			resp.Deadlock = &tikvrpc.Deadlock{
+				LockTs:          dl.LockTS,
+				LockKey:         dl.LockKey,
+				DeadlockKeyHash: dl.DealockKeyHash,
+			}
+			return resp
+		}
+	}
+	resp.Errors = convertToKeyErrors(errs)
+	return resp
+}
+
+func (h *rpcHandler) handleKvPessimisticRollback(req *tikvrpc.PessimisticRollbackRequest) *tikvrpc.PessimisticRollbackResponse {
+	for _, k := range req.Keys {
+		if !h.checkKeyInRegion(k) {
+			panic("KvPessimisticRollback: key not in region")
+		}
+	}
+	errs := h.mvccStore.PessimisticRollback(req.Keys, req.StartVersion, req.ForUpdateTs)
+	return &tikvrpc.PessimisticRollbackResponse{
+		Errors: convertToKeyErrors(errs),
+	}
+}
+
 func (h *rpcHandler) handleKvCheckTxnStatus(req *kvrpcpb.CheckTxnStatusRequest) (*kvrpcpb.CheckTxnStatusResponse, error) {
 	if !h.checkKeyInRegion(req.PrimaryKey) {
 		panic("KvCheckTxnStatus: key not in region")
@@ -288,6 +323,38 @@ func (h *rpcHandler) handleKvResolveLock(req *kvrpcpb.ResolveLockRequest) *kvrpc
 	return &kvrpcpb.ResolveLockResponse{}
 }
 
+func (h *rpcHandler) handleKvScanLock(req *tikvrpc.ScanLockRequest) *tikvrpc.ScanLockResponse {
+	startKey := MvccKey(h.startKey).Raw()
+	if bytes.Compare(req.StartKey, startKey) > 0 {
+		startKey = req.StartKey
+	}
+	endKey := MvccKey(h.endKey).Raw()
+	locks, err := h.mvccStore.ScanLock(startKey, endKey, req.MaxVersion)
+	if err != nil {
+		return &tikvrpc.ScanLockResponse{
+			Error: convertToKeyError(err),
+		}
+	}
+	if req.Limit > 0 && len(locks) > int(req.Limit) {
+		locks = locks[:req.Limit]
+	}
+	return &tikvrpc.ScanLockResponse{
+		Locks: locks,
+	}
+}
+
+func (h *rpcHandler) handleKvGC(req *tikvrpc.GCRequest) *tikvrpc.GCResponse {
+	startKey := MvccKey(h.startKey).Raw()
+	endKey := MvccKey(h.endKey).Raw()
+	err := h.mvccStore.GC(startKey, endKey, req.SafePoint)
+	if err != nil {
+		return &tikvrpc.GCResponse{
+			Error: convertToKeyError(err),
+		}
+	}
+	return &tikvrpc.GCResponse{}
+}
+
 func (h *rpcHandler) handleKvRawGet(req *kvrpcpb.RawGetRequest) *kvrpcpb.RawGetResponse {
 	rawKV, ok := h.mvccStore.(RawKV)
 	if !ok {
@@ -466,6 +533,20 @@ func (c *RPCClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.R
 				failpoint.Return(nil, undeterminedErr)
 			}
 		})
+	case tikvrpc.CmdPessimisticLock:
+		r := req.PessimisticLock()
+		if err := handler.checkRequest(reqCtx, r.Size()); err != nil {
+			resp.Resp = &tikvrpc.PessimisticLockResponse{RegionError: err}
+			return resp, nil
+		}
+		resp.Resp = handler.handleKvPessimisticLock(r)
+	case tikvrpc.CmdPessimisticRollback:
+		r := req.PessimisticRollback()
+		if err := handler.checkRequest(reqCtx, r.Size()); err != nil {
+			resp.Resp = &tikvrpc.PessimisticRollbackResponse{RegionError: err}
+			return resp, nil
+		}
+		resp.Resp = handler.handleKvPessimisticRollback(r)
 	case tikvrpc.CmdCheckTxnStatus:
 		r := req.CheckTxnStatus()
 		if err := handler.checkRequest(reqCtx, r.Size()); err != nil {
@@ -488,6 +569,20 @@ func (c *RPCClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.R
 			return resp, nil
 		}
 		resp.Resp = handler.handleKvResolveLock(r)
+	case tikvrpc.CmdScanLock:
+		r := req.ScanLock()
+		if err := handler.checkRequest(reqCtx, r.Size()); err != nil {
+			resp.Resp = &tikvrpc.ScanLockResponse{RegionError: err}
+			return resp, nil
+		}
+		resp.Resp = handler.handleKvScanLock(r)
+	case tikvrpc.CmdGC:
+		r := req.GC()
+		if err := handler.checkRequest(reqCtx, r.Size()); err != nil {
+			resp.Resp = &tikvrpc.GCResponse{RegionError: err}
+			return resp, nil
+		}
+		resp.Resp = handler.handleKvGC(r)
 	case tikvrpc.CmdRawGet:
 		r := req.RawGet()
 		if err := handler.checkRequest(reqCtx, r.Size()); err != nil {
@@ -603,6 +603,10 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 		}
 	})
 
+	if worker.vars != nil && worker.vars.Killed != nil && atomic.LoadUint32(worker.vars.Killed) == 1 {
+		return nil, ErrQueryInterrupted
+	}
+
 	req := tikvrpc.NewRequest(task.cmdType, &coprocessor.Request{
 		Tp:      worker.req.Tp,
 		StartTs: worker.req.StartTs,
@@ -0,0 +1,430 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcworker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap-incubator/tinykv/proto/pkg/errorpb"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/tikv"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/store/tikv/tikvrpc"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// GCWorker periodically triggers GC process on tikv server.
+// Only the GC worker of the DDL owner runs the GC job.
+type GCWorker struct {
+	uuid        string
+	desc        string
+	store       tikv.Storage
+	gcIsRunning bool
+	lastFinish  time.Time
+	cancel      context.CancelFunc
+	done        chan error
+	session     session.Session
+}
+
+// NewGCWorker creates a GCWorker instance.
+func NewGCWorker(store tikv.Storage) (tikv.GCHandler, error) {
+	ver, err := store.CurrentVersion()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hostName, err := os.Hostname()
+	if err != nil {
+		hostName = "unknown"
+	}
+	worker := &GCWorker{
+		uuid:  strconv.FormatUint(ver.Ver, 16),
+		desc:  fmt.Sprintf("host:%s, pid:%d, start at %s", hostName, os.Getpid(), time.Now()),
+		store: store,
+		done:  make(chan error),
+	}
+	return worker, nil
+}
+
+// Start starts the worker.
+func (w *GCWorker) Start() {
+	var ctx context.Context
+	ctx, w.cancel = context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go w.start(ctx, &wg)
+	wg.Wait() // Wait create session finish in worker, some test code depend on this to avoid race.
+}
+
+// Close stops background goroutines.
+func (w *GCWorker) Close() {
+	w.cancel()
+}
+
+// gcWorkerTickInterval is the interval to check whether to run a GC job.
+var gcWorkerTickInterval = time.Minute
+
+const (
+	gcRunInterval   = 10 * time.Minute
+	gcConcurrency   = 2
+	gcScanLockLimit = 1024
+)
+
+// gcMinLifeTime is the lower bound of tidb_gc_life_time, a shorter life time
+// makes the snapshots of the running transactions invalid too quickly.
+var gcMinLifeTime = 10 * time.Minute
+
+func (w *GCWorker) start(ctx context.Context, wg *sync.WaitGroup) {
+	logutil.Logger(ctx).Info("[gc worker] start",
+		zap.String("uuid", w.uuid),
+		zap.String("desc", w.desc))
+
+	var err error
+	w.session, err = session.CreateSession(w.store)
+	if err != nil {
+		logutil.Logger(ctx).Warn("[gc worker] create session failed", zap.Error(err))
+	} else {
+		w.session.GetSessionVars().InRestrictedSQL = true
+	}
+	wg.Done()
+	if err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(gcWorkerTickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.tick(ctx)
+		case err := <-w.done:
+			w.gcIsRunning = false
+			w.lastFinish = time.Now()
+			if err != nil {
+				logutil.Logger(ctx).Error("[gc worker] runGCJob", zap.Error(err))
+			}
+		case <-ctx.Done():
+			logutil.Logger(ctx).Info("[gc worker] quit", zap.String("uuid", w.uuid))
+			w.session.Close()
+			return
+		}
+	}
+}
+
+func (w *GCWorker) tick(ctx context.Context) {
+	if !w.isOwner() || w.gcIsRunning || time.Since(w.lastFinish) < gcRunInterval {
+		return
+	}
+	safePoint, err := w.calculateNewSafePoint(ctx)
+	if err != nil {
+		logutil.Logger(ctx).Error("[gc worker] calculate new safe point failed",
+			zap.String("uuid", w.uuid),
+			zap.Error(err))
+		return
+	}
+	if safePoint == 0 {
+		return
+	}
+	w.gcIsRunning = true
+	logutil.Logger(ctx).Info("[gc worker] starts the whole job",
+		zap.String("uuid", w.uuid),
+		zap.Uint64("safePoint", safePoint))
+	go func() {
+		var err error
+		util.WithRecovery(func() {
+			err = w.runGCJob(ctx, safePoint)
+		}, func(r interface{}) {
+			if r != nil {
+				err = errors.Errorf("%v", r)
+			}
+		})
+		w.done <- err
+	}()
+}
+
+// isOwner checks whether this TiDB server is the DDL owner, the GC job runs on
+// the owner only.
+func (w *GCWorker) isOwner() bool {
+	return domain.GetDomain(w.session).DDL().OwnerManager().IsOwner()
+}
+
+// calculateNewSafePoint returns the safe point of the next GC job, which is
+// tidb_gc_life_time before now. It returns 0 if the safe point doesn't advance.
+func (w *GCWorker) calculateNewSafePoint(ctx context.Context) (uint64, error) {
+	lifeTime, err := w.loadLifeTime()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	ver, err := w.store.CurrentVersion()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	now := oracle.GetTimeFromTS(ver.Ver)
+	safePoint := oracle.ComposeTS(oracle.GetPhysical(now.Add(-lifeTime)), 0)
+
+	lastSafePoint, err := w.loadSafePoint()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	// We should never decrease safePoint.
+	if safePoint <= lastSafePoint {
+		logutil.Logger(ctx).Info("[gc worker] last safe point is later than current one, no need to gc",
+			zap.Uint64("lastSafePoint", lastSafePoint),
+			zap.Uint64("safePoint", safePoint))
+		return 0, nil
+	}
+	return safePoint, nil
+}
+
+func (w *GCWorker) loadLifeTime() (time.Duration, error) {
+	str, err := w.session.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBGCLifeTime)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	lifeTime, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if lifeTime < gcMinLifeTime {
+		lifeTime = gcMinLifeTime
+	}
+	return lifeTime, nil
+}
+
+func (w *GCWorker) loadSafePoint() (uint64, error) {
+	safePoint, err := tikv.LoadSafePoint(w.store.GetSafePointKV())
+	return safePoint, errors.Trace(err)
+}
+
+// saveSafePoint saves the safe point, the store rejects the snapshots older
+// than it after that.
+func (w *GCWorker) saveSafePoint(safePoint uint64) error {
+	err := w.store.GetSafePointKV().Put(tikv.GcSavedSafePoint, strconv.FormatUint(safePoint, 10))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.store.UpdateSPCache(safePoint, time.Now())
+	return nil
+}
+
+// runGCJob resolves the locks older than the safe point, then advances the
+// safe point and removes the stale versions.
+func (w *GCWorker) runGCJob(ctx context.Context, safePoint uint64) error {
+	err := w.resolveLocks(ctx, safePoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = w.saveSafePoint(safePoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return w.doGC(ctx, safePoint)
+}
+
+func (w *GCWorker) resolveLocks(ctx context.Context, safePoint uint64) error {
+	handler := func(ctx context.Context, r kv.KeyRange) (tikv.RangeTaskStat, error) {
+		return w.resolveLocksForRange(ctx, safePoint, r.StartKey, r.EndKey)
+	}
+	runner := tikv.NewRangeTaskRunner("resolve-locks-runner", w.store, gcConcurrency, handler)
+	err := runner.RunOnRange(ctx, []byte(""), []byte(""))
+	if err != nil {
+		logutil.Logger(ctx).Error("[gc worker] resolve locks failed",
+			zap.String("uuid", w.uuid),
+			zap.Uint64("safePoint", safePoint),
+			zap.Error(err))
+		return errors.Trace(err)
+	}
+	logutil.Logger(ctx).Info("[gc worker] finish resolve locks",
+		zap.String("uuid", w.uuid),
+		zap.Uint64("safePoint", safePoint),
+		zap.Int("regions", runner.CompletedRegions()))
+	return nil
+}
+
+func (w *GCWorker) resolveLocksForRange(ctx context.Context, safePoint uint64, startKey []byte, endKey []byte) (tikv.RangeTaskStat, error) {
+	req := tikvrpc.NewRequest(tikvrpc.CmdScanLock, &tikvrpc.ScanLockRequest{
+		MaxVersion: safePoint,
+		Limit:      gcScanLockLimit,
+	})
+
+	var stat tikv.RangeTaskStat
+	key := startKey
+	bo := tikv.NewBackoffer(ctx, tikv.GcResolveLockMaxBackoff)
+	for {
+		select {
+		case <-ctx.Done():
+			return stat, errors.New("[gc worker] gc job canceled")
+		default:
+		}
+
+		req.ScanLock().StartKey = key
+		loc, err := w.store.GetRegionCache().LocateKey(bo, key)
+		if err != nil {
+			return stat, errors.Trace(err)
+		}
+		resp, err := w.store.SendReq(bo, req, loc.Region, tikv.ReadTimeoutMedium)
+		if err != nil {
+			return stat, errors.Trace(err)
+		}
+		regionErr, err := resp.GetRegionError()
+		if err != nil {
+			return stat, errors.Trace(err)
+		}
+		if regionErr != nil {
+			err = bo.Backoff(tikv.BoRegionMiss, errors.New(regionErr.String()))
+			if err != nil {
+				return stat, errors.Trace(err)
+			}
+			continue
+		}
+		if resp.Resp == nil {
+			return stat, errors.Trace(tikv.ErrBodyMissing)
+		}
+		locksResp := resp.Resp.(*tikvrpc.ScanLockResponse)
+		if locksResp.Error != nil {
+			return stat, errors.Errorf("unexpected scanlock error: %s", locksResp.Error)
+		}
+		locksInfo := locksResp.Locks
+		locks := make([]*tikv.Lock, len(locksInfo))
+		for i := range locksInfo {
+			locks[i] = tikv.NewLock(locksInfo[i])
+		}
+
+		msBeforeExpired, _, err := w.store.GetLockResolver().ResolveLocks(bo, 0, locks)
+		if err != nil {
+			return stat, errors.Trace(err)
+		}
+		if msBeforeExpired > 0 {
+			// Some locks are still alive, wait for them to expire and scan the region again.
+			err = bo.BackoffWithMaxSleep(tikv.BoTxnLock, int(msBeforeExpired), errors.Errorf("remaining locks: %d", len(locks)))
+			if err != nil {
+				return stat, errors.Trace(err)
+			}
+			continue
+		}
+
+		if len(locks) < gcScanLockLimit {
+			stat.CompletedRegions++
+			key = loc.EndKey
+		} else {
+			logutil.Logger(ctx).Info("[gc worker] region has more than limit locks",
+				zap.String("uuid", w.uuid),
+				zap.Uint64("region", loc.Region.GetID()),
+				zap.Int("scan lock limit", gcScanLockLimit))
+			key = locks[len(locks)-1].Key
+		}
+
+		if len(key) == 0 || (len(endKey) != 0 && bytes.Compare(key, endKey) >= 0) {
+			break
+		}
+		bo = tikv.NewBackoffer(ctx, tikv.GcResolveLockMaxBackoff)
+	}
+	return stat, nil
+}
+
+func (w *GCWorker) doGC(ctx context.Context, safePoint uint64) error {
+	handler := func(ctx context.Context, r kv.KeyRange) (tikv.RangeTaskStat, error) {
+		return w.doGCForRange(ctx, safePoint, r.StartKey, r.EndKey)
+	}
+	runner := tikv.NewRangeTaskRunner("gc-runner", w.store, gcConcurrency, handler)
+	err := runner.RunOnRange(ctx, []byte(""), []byte(""))
+	if err != nil {
+		logutil.Logger(ctx).Error("[gc worker] gc failed",
+			zap.String("uuid", w.uuid),
+			zap.Uint64("safePoint", safePoint),
+			zap.Error(err))
+		return errors.Trace(err)
+	}
+	logutil.Logger(ctx).Info("[gc worker] finish gc",
+		zap.String("uuid", w.uuid),
+		zap.Uint64("safePoint", safePoint),
+		zap.Int("regions", runner.CompletedRegions()))
+	return nil
+}
+
+func (w *GCWorker) doGCForRange(ctx context.Context, safePoint uint64, startKey []byte, endKey []byte) (tikv.RangeTaskStat, error) {
+	var stat tikv.RangeTaskStat
+	key := startKey
+	bo := tikv.NewBackoffer(ctx, tikv.GcOneRegionMaxBackoff)
+	for {
+		loc, err := w.store.GetRegionCache().LocateKey(bo, key)
+		if err != nil {
+			return stat, errors.Trace(err)
+		}
+
+		var regionErr *errorpb.Error
+		regionErr, err = w.doGCForRegion(bo, safePoint, loc.Region)
+		if regionErr != nil {
+			// The region may be split or moved, locate it again and retry.
+			err = bo.Backoff(tikv.BoRegionMiss, errors.New(regionErr.String()))
+			if err == nil {
+				continue
+			}
+		}
+		if err != nil {
+			logutil.Logger(ctx).Warn("[gc worker] gc for region failed",
+				zap.String("uuid", w.uuid),
+				zap.Uint64("region", loc.Region.GetID()),
+				zap.Error(err))
+			stat.FailedRegions++
+		} else {
+			stat.CompletedRegions++
+		}
+
+		key = loc.EndKey
+		if len(key) == 0 || (len(endKey) != 0 && bytes.Compare(key, endKey) >= 0) {
+			break
+		}
+		bo = tikv.NewBackoffer(ctx, tikv.GcOneRegionMaxBackoff)
+	}
+	return stat, nil
+}
+
+// doGCForRegion sends the GC request to a region. The region error is
+// returned to the caller, which locates the region again and retries.
+func (w *GCWorker) doGCForRegion(bo *tikv.Backoffer, safePoint uint64, region tikv.RegionVerID) (*errorpb.Error, error) {
+	req := tikvrpc.NewRequest(tikvrpc.CmdGC, &tikvrpc.GCRequest{
+		SafePoint: safePoint,
+	})
+	resp, err := w.store.SendReq(bo, req, region, tikv.ReadTimeoutLong)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	regionErr, err := resp.GetRegionError()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if regionErr != nil {
+		return regionErr, nil
+	}
+	if resp.Resp == nil {
+		return nil, errors.Trace(tikv.ErrBodyMissing)
+	}
+	gcResp := resp.Resp.(*tikvrpc.GCResponse)
+	if gcResp.Error != nil {
+		return nil, errors.Errorf("unexpected gc error: %s", gcResp.Error)
+	}
+	return nil, nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcworker
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/store/mockstore/mocktikv"
+	"github.com/pingcap/tidb/store/tikv"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testGCWorkerSuite{})
+
+type testGCWorkerSuite struct {
+	cluster   *mocktikv.Cluster
+	mvccStore mocktikv.MVCCStore
+	store     tikv.Storage
+	dom       *domain.Domain
+	gcWorker  *GCWorker
+}
+
+func (s *testGCWorkerSuite) SetUpTest(c *C) {
+	s.cluster = mocktikv.NewCluster()
+	mocktikv.BootstrapWithMultiRegions(s.cluster, []byte("b"), []byte("d"))
+	s.mvccStore = mocktikv.MustNewMVCCStore()
+	store, err := mockstore.NewMockTikvStore(
+		mockstore.WithCluster(s.cluster),
+		mockstore.WithMVCCStore(s.mvccStore),
+	)
+	c.Assert(err, IsNil)
+	s.store = store.(tikv.Storage)
+
+	session.SetSchemaLease(0)
+	session.DisableStats4Test()
+	s.dom, err = session.BootstrapSession(s.store)
+	c.Assert(err, IsNil)
+
+	gcWorker, err := NewGCWorker(s.store)
+	c.Assert(err, IsNil)
+	s.gcWorker = gcWorker.(*GCWorker)
+	s.gcWorker.session, err = session.CreateSession(s.store)
+	c.Assert(err, IsNil)
+}
+
+func (s *testGCWorkerSuite) TearDownTest(c *C) {
+	s.gcWorker.session.Close()
+	s.dom.Close()
+	s.store.Close()
+}
+
+func (s *testGCWorkerSuite) mustAllocTS(c *C) uint64 {
+	ts, err := s.store.GetOracle().GetTimestamp(context.Background())
+	c.Assert(err, IsNil)
+	return ts
+}
+
+// mustPut writes a version of the key directly to the MVCC store and returns
+// its commit ts.
+func (s *testGCWorkerSuite) mustPut(c *C, key, value string) uint64 {
+	startTS := s.mustAllocTS(c)
+	errs := s.mvccStore.Prewrite(&kvrpcpb.PrewriteRequest{
+		Mutations:    []*kvrpcpb.Mutation{{Op: kvrpcpb.Op_Put, Key: []byte(key), Value: []byte(value)}},
+		PrimaryLock:  []byte(key),
+		StartVersion: startTS,
+	})
+	for _, err := range errs {
+		c.Assert(err, IsNil)
+	}
+	commitTS := s.mustAllocTS(c)
+	c.Assert(s.mvccStore.Commit([][]byte{[]byte(key)}, startTS, commitTS), IsNil)
+	return commitTS
+}
+
+func (s *testGCWorkerSuite) mustGet(c *C, key string, ts uint64) string {
+	value, err := s.mvccStore.Get([]byte(key), ts)
+	c.Assert(err, IsNil)
+	return string(value)
+}
+
+func (s *testGCWorkerSuite) TestCalculateNewSafePoint(c *C) {
+	ctx := context.Background()
+	now := time.Now()
+	safePoint, err := s.gcWorker.calculateNewSafePoint(ctx)
+	c.Assert(err, IsNil)
+	lifeTime := now.Sub(oracle.GetTimeFromTS(safePoint))
+	c.Assert(lifeTime, GreaterEqual, gcMinLifeTime-time.Minute)
+	c.Assert(lifeTime, Less, gcMinLifeTime+time.Minute)
+
+	// The life time is read from tidb_gc_life_time.
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("set @@global.tidb_gc_life_time = '24h'")
+	safePoint, err = s.gcWorker.calculateNewSafePoint(ctx)
+	c.Assert(err, IsNil)
+	lifeTime = now.Sub(oracle.GetTimeFromTS(safePoint))
+	c.Assert(lifeTime, GreaterEqual, 24*time.Hour-time.Minute)
+	c.Assert(lifeTime, Less, 24*time.Hour+time.Minute)
+
+	// A life time shorter than the minimum is ignored.
+	tk.MustExec("set @@global.tidb_gc_life_time = '1m'")
+	safePoint, err = s.gcWorker.calculateNewSafePoint(ctx)
+	c.Assert(err, IsNil)
+	lifeTime = now.Sub(oracle.GetTimeFromTS(safePoint))
+	c.Assert(lifeTime, GreaterEqual, gcMinLifeTime-time.Minute)
+	c.Assert(lifeTime, Less, gcMinLifeTime+time.Minute)
+
+	// The safe point never goes back.
+	c.Assert(s.gcWorker.saveSafePoint(oracle.ComposeTS(oracle.GetPhysical(now), 0)), IsNil)
+	safePoint, err = s.gcWorker.calculateNewSafePoint(ctx)
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(0))
+}
+
+func (s *testGCWorkerSuite) TestRunGCJob(c *C) {
+	ts1 := s.mustPut(c, "a", "a1")
+	ts2 := s.mustPut(c, "a", "a2")
+	tsC := s.mustPut(c, "c", "c1")
+	safePoint := s.mustAllocTS(c)
+	ts3 := s.mustPut(c, "a", "a3")
+
+	err := s.gcWorker.runGCJob(context.Background(), safePoint)
+	c.Assert(err, IsNil)
+
+	// The versions overwritten before the safe point are removed.
+	c.Assert(s.mustGet(c, "a", ts1), Equals, "")
+	// The latest version before the safe point and the later ones are kept.
+	c.Assert(s.mustGet(c, "a", ts2), Equals, "a2")
+	c.Assert(s.mustGet(c, "a", ts3), Equals, "a3")
+	c.Assert(s.mustGet(c, "c", tsC), Equals, "c1")
+
+	str, err := s.store.GetSafePointKV().Get(tikv.GcSavedSafePoint)
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, strconv.FormatUint(safePoint, 10))
+	// Reading before the safe point is rejected.
+	txn, err := s.store.BeginWithStartTS(tsC)
+	c.Assert(err, IsNil)
+	_, err = txn.Get(context.Background(), []byte("c"))
+	c.Assert(terror.ErrorEqual(err, tikv.ErrGCTooEarly), IsTrue, Commentf("err %v", err))
+	c.Assert(txn.Rollback(), IsNil)
+}
+
+func (s *testGCWorkerSuite) TestStartGCWorker(c *C) {
+	defer func(interval, minLifeTime time.Duration) {
+		gcWorkerTickInterval = interval
+		gcMinLifeTime = minLifeTime
+		tikv.NewGCHandlerFunc = nil
+	}(gcWorkerTickInterval, gcMinLifeTime)
+	gcWorkerTickInterval = 50 * time.Millisecond
+	gcMinLifeTime = 0
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("set @@global.tidb_gc_life_time = '1s'")
+
+	ts1 := s.mustPut(c, "a", "a1")
+	ts2 := s.mustPut(c, "a", "a2")
+	time.Sleep(time.Second)
+
+	// The worker started by the store is the owner, it runs the GC job on its own.
+	tikv.NewGCHandlerFunc = NewGCWorker
+	c.Assert(s.store.StartGCWorker(), IsNil)
+	for i := 0; i < 100 && s.mustGet(c, "a", ts1) != ""; i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+	c.Assert(s.mustGet(c, "a", ts1), Equals, "")
+	c.Assert(s.mustGet(c, "a", ts2), Equals, "a2")
+	safePoint, err := tikv.LoadSafePoint(s.store.GetSafePointKV())
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Greater, ts2)
+}
+
+func (s *testGCWorkerSuite) TestDoGCForStaleRegion(c *C) {
+	ts1 := s.mustPut(c, "c", "c1")
+	ts2 := s.mustPut(c, "c", "c2")
+	safePoint := s.mustAllocTS(c)
+
+	// Split the region after it's cached, the GC request gets a region error.
+	bo := tikv.NewBackoffer(context.Background(), tikv.GcOneRegionMaxBackoff)
+	loc, err := s.store.GetRegionCache().LocateKey(bo, []byte("c"))
+	c.Assert(err, IsNil)
+	ids := s.cluster.AllocIDs(2)
+	s.cluster.Split(loc.Region.GetID(), ids[0], []byte("c"), []uint64{ids[1]}, ids[1])
+
+	stat, err := s.gcWorker.doGCForRange(context.Background(), safePoint, []byte(""), []byte(""))
+	c.Assert(err, IsNil)
+	c.Assert(stat.FailedRegions, Equals, 0)
+	c.Assert(stat.CompletedRegions, Equals, 4)
+	c.Assert(s.mustGet(c, "c", ts1), Equals, "")
+	c.Assert(s.mustGet(c, "c", ts2), Equals, "c2")
+}
@@ -51,4 +51,7 @@ type Storage interface {
 
 	// Closed returns the closed channel.
 	Closed() <-chan struct{}
+
+	// StartGCWorker starts the GC worker of the store if GC is enabled.
+	StartGCWorker() error
 }
@@ -102,6 +102,19 @@ type EtcdBackend interface {
 	TLSConfig() *tls.Config
 }
 
+// GCHandler runs garbage collection job.
+type GCHandler interface {
+	// Start starts the GCHandler.
+	Start()
+
+	// Close closes the GCHandler.
+	Close()
+}
+
+// NewGCHandlerFunc creates a new GCHandler.
+// To enable real GC, we should assign the function to `gcworker.NewGCWorker`.
+var NewGCHandlerFunc func(storage Storage) (GCHandler, error)
+
 // update oracle's lastTS every 2000ms.
 var oracleUpdateInterval = 2000
 
@@ -116,6 +129,7 @@ type tikvStore struct {
 	etcdAddrs    []string
 	mock         bool
 	enableGC     bool
+	gcWorker     GCHandler
 
 	kv        SafePointKV
 	safePoint uint64
@@ -183,12 +197,32 @@ func (s *tikvStore) EtcdAddrs() []string {
 	return s.etcdAddrs
 }
 
+// StartGCWorker starts GC worker, it's called in BootstrapSession, don't call this function more than once.
+func (s *tikvStore) StartGCWorker() error {
+	return s.startGCWorker(s)
+}
+
+// startGCWorker starts the GC worker on the store, which may be a wrapper of s.
+func (s *tikvStore) startGCWorker(store Storage) error {
+	if !s.enableGC || NewGCHandlerFunc == nil {
+		return nil
+	}
+
+	gcWorker, err := NewGCHandlerFunc(store)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	gcWorker.Start()
+	s.gcWorker = gcWorker
+	return nil
+}
+
 func (s *tikvStore) runSafePointChecker() {
 	d := gcSafePointUpdateInterval
 	for {
 		select {
 		case spCachedTime := <-time.After(d):
-			cachedSafePoint, err := loadSafePoint(s.GetSafePointKV())
+			cachedSafePoint, err := LoadSafePoint(s.GetSafePointKV())
 			if err == nil {
 				s.UpdateSPCache(cachedSafePoint, spCachedTime)
 				d = gcSafePointUpdateInterval
@@ -235,6 +269,9 @@ func (s *tikvStore) Close() error {
 	delete(mc.cache, s.uuid)
 	s.oracle.Close()
 	s.pdClient.Close()
+	if s.gcWorker != nil {
+		s.gcWorker.Close()
+	}
 
 	close(s.closed)
 	if err := s.client.Close(); err != nil {
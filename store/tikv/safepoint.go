@@ -149,7 +149,9 @@ func saveSafePoint(kv SafePointKV, t uint64) error {
 	return nil
 }
 
-func loadSafePoint(kv SafePointKV) (uint64, error) {
+// LoadSafePoint loads the GC safe point saved in the SafePointKV, it returns 0
+// if the safe point is never saved.
+func LoadSafePoint(kv SafePointKV) (uint64, error) {
 	str, err := kv.Get(GcSavedSafePoint)
 
 	if err != nil {
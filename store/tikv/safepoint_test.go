@@ -53,7 +53,7 @@ func (s *testSafePointSuite) waitUntilErrorPlugIn(t uint64) {
 	for {
 		saveSafePoint(s.store.GetSafePointKV(), t+10)
 		cachedTime := time.Now()
-		newSafePoint, err := loadSafePoint(s.store.GetSafePointKV())
+		newSafePoint, err := LoadSafePoint(s.store.GetSafePointKV())
 		if err == nil {
 			s.store.UpdateSPCache(newSafePoint, cachedTime)
 			break
@@ -14,8 +14,11 @@
 package tikv
 
 import (
+	"bytes"
 	"context"
 	"flag"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	pb "github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
@@ -25,6 +28,8 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/store/tikv/tikvrpc"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
 )
 
 var mockStore = flag.Bool("mockStore", true, "use mock store when commit protocol is not implemented")
@@ -43,7 +48,7 @@ func NewTestTiKVStore(client Client, pdClient pd.Client, clientHijack func(Clien
 	// Make sure the uuid is unique.
 	uid := uuid.New().String()
 	spkv := NewMockSafePointKV()
-	tikvStore, err := newTikvStore(uid, pdCli, spkv, client, false)
+	tikvStore, err := newTikvStore(uid, pdCli, spkv, client, true)
 
 	tikvStore.mock = true
 	if *mockStore {
@@ -72,6 +77,12 @@ func (m *mockTikvStore) Begin()  (kv.Transaction, error){
 	return &mockTransaction{txn.(*tikvTxn)}, nil
 }
 
+// StartGCWorker starts the GC worker on the mock store, so the transactions of
+// the worker are committed by the mock committer too.
+func (m *mockTikvStore) StartGCWorker() error {
+	return m.startGCWorker(m)
+}
+
 func (m *mockTikvStore) BeginWithStartTS(startTS uint64) (kv.Transaction, error){
 	txn, err := m.tikvStore.BeginWithStartTS(startTS)
 	if err != nil {
@@ -120,45 +131,190 @@ func (txn *mockTransaction) Commit(ctx context.Context) error {
 }
 
 // execute executes the two-phase commit protocol.
-func (c *mockTwoPhaseCommitter) execute(_ context.Context) (err error) {
+func (c *mockTwoPhaseCommitter) execute(ctx context.Context) (err error) {
 	if len(c.mutations) == 0 {
 		return nil
 	}
-	for k := range c.mutations {
-		c.primaryKey = []byte(k)
-		break
-	}
+	keys := c.sortedKeys()
+	c.primaryKey = keys[0]
 	// prewrite
-	for k, m := range c.mutations {
-		req := tikvrpc.NewRequest(tikvrpc.CmdPrewrite, &pb.PrewriteRequest{
-			Mutations:    []*pb.Mutation{&m.Mutation},
-			PrimaryLock:  c.primary(),
-			StartVersion: c.startTS,
-			LockTtl:      c.lockTTL,
-		}, pb.Context{})
-		bo :=  NewBackoffer(context.Background(), PrewriteMaxBackoff)
-		sender := NewRegionRequestSender(c.store.regionCache, c.store.client)
-		loc, err := c.store.GetRegionCache().LocateKey(bo, []byte(k))
+	for i, k := range keys {
+		lock, err := c.prewriteKey(ctx, k)
+		if err == nil && lock == nil {
+			continue
+		}
+		// Release the locks of the transaction, including the pessimistic ones
+		// of the keys that are not prewritten yet.
+		c.rollbackKeys(keys)
 		if err != nil {
-			return err
+			return errors.Trace(err)
 		}
-		sender.SendReq(bo, req, loc.Region,readTimeoutShort  )
+		// The key is locked by another transaction, the value the statement read
+		// may be changed by it. Wait until the lock is released and report a
+		// write conflict, so that the statement can be retried.
+		logutil.Logger(ctx).Debug("mock 2PC prewrite meets lock",
+			zap.Uint64("txnStartTS", c.startTS),
+			zap.Int("prewritten", i),
+			zap.Stringer("lock", lock))
+		if err = c.waitForLock(ctx, lock); err != nil {
+			return errors.Trace(err)
+		}
+		return newWriteConflictError(&pb.WriteConflict{
+			StartTs:    c.startTS,
+			ConflictTs: lock.TxnID,
+			Key:        lock.Key,
+			Primary:    c.primary(),
+		})
 	}
 
-	// commit
-	for k := range c.mutations {
+	// Commit at a new timestamp, so that the snapshots taken before can still
+	// read the old versions.
+	commitTS, err := c.store.getTimestampWithRetry(NewBackoffer(ctx, tsoMaxBackoff).WithVars(c.txn.vars))
+	if err != nil {
+		c.rollbackKeys(keys)
+		return errors.Trace(err)
+	}
+	c.commitTS = commitTS
+	c.txn.commitTS = commitTS
+
+	// commit, the transaction is committed once its primary key is committed.
+	for i, k := range keys {
 		req := tikvrpc.NewRequest(tikvrpc.CmdCommit, &pb.CommitRequest{
 			StartVersion:  c.startTS,
-			Keys:          [][]byte{[]byte(k)},
+			Keys:          [][]byte{k},
 			CommitVersion: c.commitTS,
 		}, pb.Context{})
-		bo :=  NewBackoffer(context.Background(), CommitMaxBackoff)
-		sender := NewRegionRequestSender(c.store.regionCache, c.store.client)
-		loc, err := c.store.GetRegionCache().LocateKey(bo, []byte(k))
+		resp, err := c.sendReq(ctx, CommitMaxBackoff, k, req)
+		if err == nil {
+			if keyErr := resp.Resp.(*pb.CommitResponse).GetError(); keyErr != nil {
+				err = extractKeyErr(keyErr)
+			}
+		}
 		if err != nil {
-			return err
+			if i == 0 {
+				c.rollbackKeys(keys)
+				return errors.Trace(err)
+			}
+			logutil.Logger(ctx).Warn("mock 2PC commit secondary key failed",
+				zap.Uint64("txnStartTS", c.startTS),
+				zap.Error(err))
 		}
-		sender.SendReq(bo, req, loc.Region,readTimeoutShort  )
 	}
 	return nil
 }
+
+// sortedKeys returns the keys of the mutations in order, the primary lock of a
+// pessimistic transaction goes first.
+func (c *mockTwoPhaseCommitter) sortedKeys() [][]byte {
+	keys := make([][]byte, 0, len(c.mutations))
+	for k := range c.mutations {
+		keys = append(keys, []byte(k))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+	if primary := c.txn.primaryLock; len(primary) > 0 {
+		for i, k := range keys {
+			if bytes.Equal(k, primary) {
+				copy(keys[1:i+1], keys[:i])
+				keys[0] = k
+				break
+			}
+		}
+	}
+	return keys
+}
+
+// prewriteKey prewrites a key, it returns the lock if the key is locked by
+// another transaction.
+func (c *mockTwoPhaseCommitter) prewriteKey(ctx context.Context, key []byte) (*Lock, error) {
+	req := tikvrpc.NewRequest(tikvrpc.CmdPrewrite, &pb.PrewriteRequest{
+		Mutations:    []*pb.Mutation{&c.mutations[string(key)].Mutation},
+		PrimaryLock:  c.primary(),
+		StartVersion: c.startTS,
+		LockTtl:      c.lockTTL,
+	}, pb.Context{})
+	resp, err := c.sendReq(ctx, PrewriteMaxBackoff, key, req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keyErrs := resp.Resp.(*pb.PrewriteResponse).GetErrors()
+	if len(keyErrs) == 0 {
+		return nil, nil
+	}
+	lock, err := extractLockFromKeyErr(keyErrs[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return lock, nil
+}
+
+// waitForLock waits until the lock is released by its transaction.
+func (c *mockTwoPhaseCommitter) waitForLock(ctx context.Context, lock *Lock) error {
+	bo := NewBackoffer(ctx, PrewriteMaxBackoff).WithVars(c.txn.vars)
+	for {
+		req := tikvrpc.NewRequest(tikvrpc.CmdScanLock, &tikvrpc.ScanLockRequest{
+			MaxVersion: lock.TxnID,
+			StartKey:   lock.Key,
+			Limit:      1,
+		})
+		resp, err := c.sendReq(ctx, PrewriteMaxBackoff, lock.Key, req)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		locks := resp.Resp.(*tikvrpc.ScanLockResponse).Locks
+		if len(locks) == 0 || !bytes.Equal(locks[0].Key, lock.Key) || locks[0].LockVersion != lock.TxnID {
+			return nil
+		}
+		err = bo.BackoffWithMaxSleep(BoTxnLock, int(pessimisticLockWaitInterval/time.Millisecond), errors.Errorf("key is locked: %v", lock))
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// rollbackKeys rolls back the keys of the transaction, the errors are ignored
+// since the locks left are still owned by the aborted transaction.
+func (c *mockTwoPhaseCommitter) rollbackKeys(keys [][]byte) {
+	for _, k := range keys {
+		req := tikvrpc.NewRequest(tikvrpc.CmdBatchRollback, &pb.BatchRollbackRequest{
+			StartVersion: c.startTS,
+			Keys:         [][]byte{k},
+		}, pb.Context{})
+		if _, err := c.sendReq(context.Background(), cleanupMaxBackoff, k, req); err != nil {
+			logutil.BgLogger().Warn("mock 2PC rollback failed",
+				zap.Uint64("txnStartTS", c.startTS),
+				zap.Error(err))
+		}
+	}
+}
+
+// sendReq sends the request to the region of the key, it retries on region errors.
+func (c *mockTwoPhaseCommitter) sendReq(ctx context.Context, maxBackoff int, key []byte, req *tikvrpc.Request) (*tikvrpc.Response, error) {
+	bo := NewBackoffer(ctx, maxBackoff).WithVars(c.txn.vars)
+	sender := NewRegionRequestSender(c.store.regionCache, c.store.client)
+	for {
+		loc, err := c.store.GetRegionCache().LocateKey(bo, key)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		resp, err := sender.SendReq(bo, req, loc.Region, readTimeoutShort)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		regionErr, err := resp.GetRegionError()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if regionErr != nil {
+			if err = bo.Backoff(BoRegionMiss, errors.New(regionErr.String())); err != nil {
+				return nil, errors.Trace(err)
+			}
+			continue
+		}
+		if resp.Resp == nil {
+			return nil, errors.Trace(ErrBodyMissing)
+		}
+		return resp, nil
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tikvrpc
+
+import (
+	"github.com/pingcap-incubator/tinykv/proto/pkg/errorpb"
+	"github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
+)
+
+// The kvrpcpb protocol of TinyKV has no GC messages either, the requests used
+// by the GC worker are defined here. Like the pessimistic lock requests, they
+// are only served by the mock store.
+
+// ScanLockRequest scans the locks whose start ts is not greater than MaxVersion
+// in a region.
+type ScanLockRequest struct {
+	Context    *kvrpcpb.Context
+	MaxVersion uint64
+	// StartKey is the key to start the scan from, an empty key means the start
+	// of the region.
+	StartKey []byte
+	// Limit is the max number of locks to return, 0 means no limit.
+	Limit uint32
+}
+
+// Size returns the size of the keys in the request.
+func (m *ScanLockRequest) Size() int {
+	return len(m.StartKey)
+}
+
+// ScanLockResponse is the response of ScanLockRequest.
+type ScanLockResponse struct {
+	RegionError *errorpb.Error
+	Error       *kvrpcpb.KeyError
+	Locks       []*kvrpcpb.LockInfo
+}
+
+// GetRegionError returns the region error of the response.
+func (m *ScanLockResponse) GetRegionError() *errorpb.Error {
+	return m.RegionError
+}
+
+// GCRequest removes the versions of a region that are invisible to the
+// transactions whose start ts is not less than SafePoint.
+type GCRequest struct {
+	Context   *kvrpcpb.Context
+	SafePoint uint64
+}
+
+// Size returns the size of the keys in the request.
+func (m *GCRequest) Size() int {
+	return 0
+}
+
+// GCResponse is the response of GCRequest.
+type GCResponse struct {
+	RegionError *errorpb.Error
+	Error       *kvrpcpb.KeyError
+}
+
+// GetRegionError returns the region error of the response.
+func (m *GCResponse) GetRegionError() *errorpb.Error {
+	return m.RegionError
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tikvrpc
+
+import (
+	"github.com/pingcap-incubator/tinykv/proto/pkg/errorpb"
+	"github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
+)
+
+// The kvrpcpb protocol of TinyKV has no pessimistic lock messages, so the
+// pessimistic lock requests and responses are defined here. They are only
+// served by the mock store, CallRPC rejects them.
+
+// PessimisticLockRequest acquires pessimistic locks on the keys of the mutations.
+type PessimisticLockRequest struct {
+	Context *kvrpcpb.Context
+	// Mutations are the keys to lock, their Op should be Op_Lock.
+	Mutations    []*kvrpcpb.Mutation
+	PrimaryLock  []byte
+	StartVersion uint64
+	// ForUpdateTs is used to detect write conflicts, a key written after
+	// ForUpdateTs can not be locked.
+	ForUpdateTs uint64
+	LockTtl     uint64
+	// WaitTimeout is the lock wait time in milliseconds, kv.LockNoWait means
+	// the request fails immediately when a key is locked by others.
+	WaitTimeout int64
+}
+
+// Size returns the size of the keys in the request.
+func (m *PessimisticLockRequest) Size() int {
+	size := len(m.PrimaryLock)
+	for _, mut := range m.Mutations {
+		size += len(mut.Key)
+	}
+	return size
+}
+
+// Deadlock describes the lock which makes a pessimistic lock request wait in a
+// cycle.
+type Deadlock struct {
+	LockTs          uint64
+	LockKey         []byte
+	DeadlockKeyHash uint64
+}
+
+// PessimisticLockResponse is the response of PessimisticLockRequest.
+type PessimisticLockResponse struct {
+	RegionError *errorpb.Error
+	Errors      []*kvrpcpb.KeyError
+	// Deadlock is set when waiting for the lock in Errors would form a deadlock.
+	Deadlock *Deadlock
+}
+
+// GetRegionError returns the region error of the response.
+func (m *PessimisticLockResponse) GetRegionError() *errorpb.Error {
+	return m.RegionError
+}
+
+// PessimisticRollbackRequest releases the pessimistic locks of a transaction.
+type PessimisticRollbackRequest struct {
+	Context      *kvrpcpb.Context
+	StartVersion uint64
+	ForUpdateTs  uint64
+	Keys         [][]byte
+}
+
+// Size returns the size of the keys in the request.
+func (m *PessimisticRollbackRequest) Size() int {
+	size := 0
+	for _, key := range m.Keys {
+		size += len(key)
+	}
+	return size
+}
+
+// PessimisticRollbackResponse is the response of PessimisticRollbackRequest.
+type PessimisticRollbackResponse struct {
+	RegionError *errorpb.Error
+	Errors      []*kvrpcpb.KeyError
+}
+
+// GetRegionError returns the region error of the response.
+func (m *PessimisticRollbackResponse) GetRegionError() *errorpb.Error {
+	return m.RegionError
+}
@@ -37,6 +37,10 @@ const (
 	CmdBatchRollback
 	CmdResolveLock
 	CmdCheckTxnStatus
+	CmdPessimisticLock
+	CmdPessimisticRollback
+	CmdScanLock
+	CmdGC
 
 	CmdRawGet CmdType = 256 + iota
 	CmdRawPut
@@ -72,6 +76,14 @@ func (t CmdType) String() string {
 		return "Cop"
 	case CmdCheckTxnStatus:
 		return "CheckTxnStatus"
+	case CmdPessimisticLock:
+		return "PessimisticLock"
+	case CmdPessimisticRollback:
+		return "PessimisticRollback"
+	case CmdScanLock:
+		return "ScanLock"
+	case CmdGC:
+		return "GC"
 	}
 	return "Unknown"
 }
@@ -159,6 +171,26 @@ func (req *Request) CheckTxnStatus() *kvrpcpb.CheckTxnStatusRequest {
 	return req.req.(*kvrpcpb.CheckTxnStatusRequest)
 }
 
+// PessimisticLock returns PessimisticLockRequest in request.
+func (req *Request) PessimisticLock() *PessimisticLockRequest {
+	return req.req.(*PessimisticLockRequest)
+}
+
+// PessimisticRollback returns PessimisticRollbackRequest in request.
+func (req *Request) PessimisticRollback() *PessimisticRollbackRequest {
+	return req.req.(*PessimisticRollbackRequest)
+}
+
+// ScanLock returns ScanLockRequest in request.
+func (req *Request) ScanLock() *ScanLockRequest {
+	return req.req.(*ScanLockRequest)
+}
+
+// GC returns GCRequest in request.
+func (req *Request) GC() *GCRequest {
+	return req.req.(*GCRequest)
+}
+
 // Response wraps all kv/coprocessor responses.
 type Response struct {
 	Resp interface{}
@@ -198,6 +230,14 @@ func SetContext(req *Request, region *metapb.Region, peer *metapb.Peer) error {
 		req.Cop().Context = ctx
 	case CmdCheckTxnStatus:
 		req.CheckTxnStatus().Context = ctx
+	case CmdPessimisticLock:
+		req.PessimisticLock().Context = ctx
+	case CmdPessimisticRollback:
+		req.PessimisticRollback().Context = ctx
+	case CmdScanLock:
+		req.ScanLock().Context = ctx
+	case CmdGC:
+		req.GC().Context = ctx
 	default:
 		return fmt.Errorf("invalid request type %v", req.Type)
 	}
@@ -258,6 +298,22 @@ func GenRegionErrorResp(req *Request, e *errorpb.Error) (*Response, error) {
 		p = &kvrpcpb.CheckTxnStatusResponse{
 			RegionError: e,
 		}
+	case CmdPessimisticLock:
+		p = &PessimisticLockResponse{
+			RegionError: e,
+		}
+	case CmdPessimisticRollback:
+		p = &PessimisticRollbackResponse{
+			RegionError: e,
+		}
+	case CmdScanLock:
+		p = &ScanLockResponse{
+			RegionError: e,
+		}
+	case CmdGC:
+		p = &GCResponse{
+			RegionError: e,
+		}
 	default:
 		return nil, fmt.Errorf("invalid request type %v", req.Type)
 	}
@@ -312,6 +368,8 @@ func CallRPC(ctx context.Context, client tinykvpb.TinyKvClient, req *Request) (*
 		resp.Resp, err = client.Coprocessor(ctx, req.Cop())
 	case CmdCheckTxnStatus:
 		resp.Resp, err = client.KvCheckTxnStatus(ctx, req.CheckTxnStatus())
+	case CmdPessimisticLock, CmdPessimisticRollback, CmdScanLock, CmdGC:
+		return nil, errors.Errorf("request type %v is not supported by TinyKV", req.Type)
 	default:
 		return nil, errors.Errorf("invalid request type: %v", req.Type)
 	}
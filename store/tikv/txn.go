@@ -16,14 +16,18 @@ package tikv
 import (
 	"context"
 	"fmt"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	pb "github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/kv"
 
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/store/tikv/tikvrpc"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
 )
@@ -32,6 +36,10 @@ var (
 	_ kv.Transaction = (*tikvTxn)(nil)
 )
 
+// pessimisticLockWaitInterval is the interval to retry a pessimistic lock
+// request when the key is locked by another transaction.
+const pessimisticLockWaitInterval = 5 * time.Millisecond
+
 // tikvTxn implements kv.Transaction.
 type tikvTxn struct {
 	snapshot  *tikvSnapshot
@@ -49,6 +57,11 @@ type tikvTxn struct {
 
 	valid bool
 	dirty bool
+
+	// isPessimistic is set once a key is locked by a pessimistic lock, the
+	// locks are released by Rollback.
+	isPessimistic bool
+	primaryLock   []byte
 }
 
 func newTiKVTxn(store *tikvStore) (*tikvTxn, error) {
@@ -197,6 +210,12 @@ func (txn *tikvTxn) Rollback() error {
 	if !txn.valid {
 		return kv.ErrInvalidTxn
 	}
+	if txn.isPessimistic && len(txn.lockKeys) > 0 {
+		if err := txn.pessimisticRollbackKeys(txn.lockKeys); err != nil {
+			logutil.BgLogger().Warn("[kv] rollback pessimistic locks failed",
+				zap.Uint64("txnStartTS", txn.StartTS()), zap.Error(err))
+		}
+	}
 	txn.close()
 	logutil.BgLogger().Debug("[kv] rollback txn", zap.Uint64("txnStartTS", txn.StartTS()))
 	return nil
@@ -216,6 +235,9 @@ func (txn *tikvTxn) LockKeys(ctx context.Context, lockCtx *kv.LockCtx, keysInput
 	if len(keys) == 0 {
 		return nil
 	}
+	if lockCtx.ForUpdateTS > 0 {
+		return txn.pessimisticLockKeys(ctx, lockCtx, keys)
+	}
 	txn.mu.Lock()
 	txn.lockKeys = append(txn.lockKeys, keys...)
 	for _, key := range keys {
@@ -226,6 +248,125 @@ func (txn *tikvTxn) LockKeys(ctx context.Context, lockCtx *kv.LockCtx, keysInput
 	return nil
 }
 
+// pessimisticLockKeys acquires the pessimistic locks of the keys one by one.
+// A key is recorded as soon as it is locked, so the locks acquired before an
+// error are still released by Rollback.
+func (txn *tikvTxn) pessimisticLockKeys(ctx context.Context, lockCtx *kv.LockCtx, keys [][]byte) error {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	txn.isPessimistic = true
+	if len(txn.primaryLock) == 0 {
+		txn.primaryLock = keys[0]
+	}
+	for _, key := range keys {
+		waitForTS, err := txn.pessimisticLockKey(ctx, lockCtx, key)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		txn.lockKeys = append(txn.lockKeys, key)
+		txn.lockedMap[string(key)] = struct{}{}
+		txn.dirty = true
+		if waitForTS != 0 {
+			// The key may have been changed by the transaction we waited for,
+			// the statement has to read it again with a newer forUpdateTS.
+			return newWriteConflictError(&pb.WriteConflict{
+				StartTs:    txn.startTS,
+				ConflictTs: waitForTS,
+				Key:        key,
+				Primary:    txn.primaryLock,
+			})
+		}
+	}
+	return nil
+}
+
+// pessimisticLockKey locks a key, it waits for the lock held by another
+// transaction until it is released or the lock wait time of lockCtx is used up.
+// It returns the start ts of the transaction it has waited for, or 0 if the key
+// is locked without waiting.
+func (txn *tikvTxn) pessimisticLockKey(ctx context.Context, lockCtx *kv.LockCtx, key []byte) (uint64, error) {
+	sender := NewRegionRequestSender(txn.store.regionCache, txn.store.client)
+	var waitForTS uint64
+	for {
+		req := tikvrpc.NewRequest(tikvrpc.CmdPessimisticLock, &tikvrpc.PessimisticLockRequest{
+			Mutations:    []*pb.Mutation{{Op: pb.Op_Lock, Key: key}},
+			PrimaryLock:  txn.primaryLock,
+			StartVersion: txn.startTS,
+			ForUpdateTs:  lockCtx.ForUpdateTS,
+			LockTtl:      ManagedLockTTL,
+			WaitTimeout:  lockCtx.LockWaitTime,
+		}, pb.Context{})
+		bo := NewBackoffer(ctx, pessimisticLockMaxBackoff)
+		loc, err := txn.store.GetRegionCache().LocateKey(bo, key)
+		if err != nil {
+			return waitForTS, errors.Trace(err)
+		}
+		resp, err := sender.SendReq(bo, req, loc.Region, readTimeoutShort)
+		if err != nil {
+			return waitForTS, errors.Trace(err)
+		}
+		regionErr, err := resp.GetRegionError()
+		if err != nil {
+			return waitForTS, errors.Trace(err)
+		}
+		if regionErr != nil {
+			if err = bo.Backoff(BoRegionMiss, errors.New(regionErr.String())); err != nil {
+				return waitForTS, errors.Trace(err)
+			}
+			continue
+		}
+		if resp.Resp == nil {
+			return waitForTS, errors.Trace(ErrBodyMissing)
+		}
+		lockResp := resp.Resp.(*tikvrpc.PessimisticLockResponse)
+		if lockResp.Deadlock != nil {
+			return waitForTS, errors.Trace(ErrDeadlock)
+		}
+		if len(lockResp.Errors) == 0 {
+			return waitForTS, nil
+		}
+		keyErr := lockResp.Errors[0]
+		if keyErr.Locked == nil {
+			return waitForTS, extractKeyErr(keyErr)
+		}
+
+		// The key is locked by another transaction, wait for it.
+		if lockCtx.LockWaitTime == kv.LockNoWait {
+			return waitForTS, errors.Trace(ErrLockAcquireFailAndNoWaitSet)
+		}
+		if lockCtx.LockWaitTime != kv.LockAlwaysWait &&
+			time.Since(lockCtx.WaitStartTime) >= time.Duration(lockCtx.LockWaitTime)*time.Millisecond {
+			return waitForTS, errors.Trace(ErrLockWaitTimeout)
+		}
+		if lockCtx.Killed != nil && atomic.LoadUint32(lockCtx.Killed) == 1 {
+			return waitForTS, errors.Trace(ErrQueryInterrupted)
+		}
+		waitForTS = keyErr.Locked.LockVersion
+		time.Sleep(pessimisticLockWaitInterval)
+	}
+}
+
+// pessimisticRollbackKeys releases the pessimistic locks of the keys.
+func (txn *tikvTxn) pessimisticRollbackKeys(keys [][]byte) error {
+	sender := NewRegionRequestSender(txn.store.regionCache, txn.store.client)
+	for _, key := range keys {
+		req := tikvrpc.NewRequest(tikvrpc.CmdPessimisticRollback, &tikvrpc.PessimisticRollbackRequest{
+			StartVersion: txn.startTS,
+			ForUpdateTs:  math.MaxUint64,
+			Keys:         [][]byte{key},
+		}, pb.Context{})
+		bo := NewBackoffer(context.Background(), pessimisticRollbackMaxBackoff)
+		loc, err := txn.store.GetRegionCache().LocateKey(bo, key)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, err = sender.SendReq(bo, req, loc.Region, readTimeoutShort); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 func (txn *tikvTxn) IsReadOnly() bool {
 	return !txn.dirty
 }
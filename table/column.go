@@ -18,8 +18,10 @@
 package table
 
 import (
+	"math"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/pingcap/tidb/parser/ast"
@@ -328,6 +330,11 @@ func getColDefaultValue(ctx sessionctx.Context, col *model.ColumnInfo, defaultVa
 		return getColDefaultValueFromNil(ctx, col)
 	}
 
+	if col.Tp == mysql.TypeTimestamp || col.Tp == mysql.TypeDatetime {
+		if str, ok := defaultVal.(string); ok && strings.EqualFold(str, ast.CurrentTimestamp) {
+			return getCurrentTimestamp(ctx, col), nil
+		}
+	}
 	value, err := CastValue(ctx, types.NewDatum(defaultVal), col)
 	if err != nil {
 		return types.Datum{}, err
@@ -335,6 +342,19 @@ func getColDefaultValue(ctx sessionctx.Context, col *model.ColumnInfo, defaultVa
 	return value, nil
 }
 
+// getCurrentTimestamp returns the start time of the current statement in the
+// session time zone, it is the value of a CURRENT_TIMESTAMP default.
+func getCurrentTimestamp(ctx sessionctx.Context, col *model.ColumnInfo) types.Datum {
+	vars := ctx.GetSessionVars()
+	fsp := int8(col.Decimal)
+	if fsp < types.MinFsp || fsp > types.MaxFsp {
+		fsp = types.MinFsp
+	}
+	now := vars.StmtCtx.GetNowTsCached().In(vars.Location())
+	now = now.Truncate(time.Duration(math.Pow10(9-int(fsp))) * time.Nanosecond)
+	return types.NewTimeDatum(types.NewTime(types.FromGoTime(now), col.Tp, fsp))
+}
+
 func getColDefaultValueFromNil(ctx sessionctx.Context, col *model.ColumnInfo) (types.Datum, error) {
 	if !mysql.HasNotNullFlag(col.Flag) {
 		return types.Datum{}, nil
@@ -370,6 +390,16 @@ func GetZeroValue(col *model.ColumnInfo) types.Datum {
 		d.SetFloat32(0)
 	case mysql.TypeDouble:
 		d.SetFloat64(0)
+	case mysql.TypeNewDecimal:
+		d.SetMysqlDecimal(new(types.MyDecimal))
+	case mysql.TypeDuration:
+		d.SetMysqlDuration(types.ZeroDuration)
+	case mysql.TypeDate:
+		d.SetMysqlTime(types.ZeroDate)
+	case mysql.TypeTimestamp:
+		d.SetMysqlTime(types.ZeroTimestamp)
+	case mysql.TypeDatetime:
+		d.SetMysqlTime(types.ZeroDatetime)
 	case mysql.TypeString:
 		if col.Flen > 0 && col.Charset == charset.CharsetBin {
 			d.SetBytes(make([]byte, col.Flen))
@@ -380,6 +410,8 @@ func GetZeroValue(col *model.ColumnInfo) types.Datum {
 		d.SetString("")
 	case mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		d.SetBytes([]byte{})
+	case mysql.TypeJSON:
+		d.SetMysqlJSON(types.CreateBinaryJSON(nil))
 	}
 	return d
 }
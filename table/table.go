@@ -216,6 +216,15 @@ type PhysicalTable interface {
 	GetPhysicalID() int64
 }
 
+// PartitionedTable is a Table, and it has a GetPartition() method.
+// GetPartition() gets the partition from a partition table by a physical table ID,
+// GetPartitionByRow() gets the partition the row belongs to.
+type PartitionedTable interface {
+	Table
+	GetPartition(physicalID int64) PhysicalTable
+	GetPartitionByRow(sessionctx.Context, []types.Datum) (PhysicalTable, error)
+}
+
 // TableFromMeta builds a table.Table from *model.TableInfo.
 // Currently, it is assigned to tables.TableFromMeta in tidb package's init function.
 var TableFromMeta func(alloc autoid.Allocator, tblInfo *model.TableInfo) (Table, error)
@@ -0,0 +1,278 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/mock"
+)
+
+// Both partition and partitionedTable implement the table.Table interface.
+var _ table.PhysicalTable = &partition{}
+var _ table.Table = &partitionedTable{}
+
+// partitionedTable implements the table.PartitionedTable interface.
+var _ table.PartitionedTable = &partitionedTable{}
+
+// PartitionMaxValue is the upper bound of the last RANGE partition defined by VALUES LESS THAN MAXVALUE.
+const PartitionMaxValue = "MAXVALUE"
+
+// partition is a feature from MySQL:
+// See https://dev.mysql.com/doc/refman/8.0/en/partitioning.html
+// A partition table may contain many partitions, each partition has a unique partition
+// id. The underlying representation of a partition and a normal table (a table with no
+// partitions) is basically the same.
+// partition also implements the table.Table interface.
+type partition struct {
+	TableCommon
+}
+
+// GetPhysicalID implements table.Table GetPhysicalID interface.
+func (p *partition) GetPhysicalID() int64 {
+	return p.physicalTableID
+}
+
+// partitionedTable implements the table.PartitionedTable interface.
+// partitionedTable is a table, it contains many Partitions.
+type partitionedTable struct {
+	TableCommon
+	partitionExpr *PartitionExpr
+	partitions    map[int64]*partition
+}
+
+func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Table, error) {
+	ctx := mock.NewContext()
+	columns, names := expression.ColumnInfos2ColumnsAndNames(ctx, model.CIStr{}, tblInfo.Name, tblInfo.Columns)
+	partitionExpr, err := NewPartitionExpr(ctx, tblInfo, expression.NewSchema(columns...), names)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ret := &partitionedTable{
+		TableCommon:   *tbl,
+		partitionExpr: partitionExpr,
+	}
+
+	pi := tblInfo.GetPartitionInfo()
+	partitions := make(map[int64]*partition, len(pi.Definitions))
+	for _, def := range pi.Definitions {
+		var t partition
+		initTableCommon(&t.TableCommon, tblInfo, def.ID, tbl.Columns, tbl.alloc)
+		if err := initTableIndices(&t.TableCommon); err != nil {
+			return nil, errors.Trace(err)
+		}
+		partitions[def.ID] = &t
+	}
+	ret.partitions = partitions
+	return ret, nil
+}
+
+// PartitionExpr is the partition definition expressions.
+type PartitionExpr struct {
+	// Expr is the partition expression.
+	Expr expression.Expression
+	// UpperBounds are the conditions "Expr < LessThan" of the RANGE partitions, they are used to
+	// locate the partition of a row. The one of the partition defined by MAXVALUE is nil.
+	UpperBounds []expression.Expression
+	// Ranges are the conditions satisfied by the rows of the RANGE partitions, they are used for
+	// partition pruning. The one of a partition that contains all the rows is nil.
+	Ranges []expression.Expression
+}
+
+// NewPartitionExpr builds the partition expressions of the table, the columns in the expressions
+// are resolved against the schema.
+func NewPartitionExpr(ctx sessionctx.Context, tblInfo *model.TableInfo, schema *expression.Schema, names types.NameSlice) (*PartitionExpr, error) {
+	pi := tblInfo.GetPartitionInfo()
+	expr, err := expression.ParseSimpleExprWithSchema(ctx, pi.Expr, schema, names)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ret := &PartitionExpr{Expr: expr}
+	if pi.Type != model.PartitionTypeRange {
+		return ret, nil
+	}
+
+	ret.UpperBounds = make([]expression.Expression, 0, len(pi.Definitions))
+	ret.Ranges = make([]expression.Expression, 0, len(pi.Definitions))
+	for i, def := range pi.Definitions {
+		var upperBound, rng expression.Expression
+		var conds []string
+		if !strings.EqualFold(def.LessThan[0], PartitionMaxValue) {
+			upperBoundStr := fmt.Sprintf("(%s) < (%s)", pi.Expr, def.LessThan[0])
+			upperBound, err = expression.ParseSimpleExprWithSchema(ctx, upperBoundStr, schema, names)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			conds = append(conds, upperBoundStr)
+		}
+		if i > 0 {
+			conds = append(conds, fmt.Sprintf("(%s) >= (%s)", pi.Expr, pi.Definitions[i-1].LessThan[0]))
+		} else if len(conds) > 0 {
+			// NULL is less than any value, so the rows whose partition expression is NULL are in the first partition.
+			conds[0] = fmt.Sprintf("%s or (%s) is null", conds[0], pi.Expr)
+		}
+		if len(conds) > 0 {
+			rng, err = expression.ParseSimpleExprWithSchema(ctx, "("+strings.Join(conds, ") and (")+")", schema, names)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		ret.UpperBounds = append(ret.UpperBounds, upperBound)
+		ret.Ranges = append(ret.Ranges, rng)
+	}
+	return ret, nil
+}
+
+// LocatePartition returns the offset of the partition the row belongs to.
+func (pe *PartitionExpr) LocatePartition(ctx sessionctx.Context, pi *model.PartitionInfo, row chunk.Row) (int, error) {
+	if pi.Type == model.PartitionTypeRange {
+		return pe.locateRangePartition(ctx, row)
+	}
+	return pe.locateHashPartition(ctx, pi, row)
+}
+
+func (pe *PartitionExpr) locateRangePartition(ctx sessionctx.Context, row chunk.Row) (int, error) {
+	var err error
+	idx := sort.Search(len(pe.UpperBounds), func(i int) bool {
+		if pe.UpperBounds[i] == nil {
+			return true
+		}
+		ret, isNull, err1 := pe.UpperBounds[i].EvalInt(ctx, row)
+		if err1 != nil {
+			err = err1
+			return true
+		}
+		// NULL is less than any value, so the rows whose partition expression is NULL are in the first partition.
+		return isNull || ret > 0
+	})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if idx >= len(pe.UpperBounds) {
+		val, err := pe.Expr.Eval(row)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		valStr, err := val.ToString()
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		return 0, table.ErrNoPartitionForGivenValue.GenWithStackByArgs(valStr)
+	}
+	return idx, nil
+}
+
+func (pe *PartitionExpr) locateHashPartition(ctx sessionctx.Context, pi *model.PartitionInfo, row chunk.Row) (int, error) {
+	ret, isNull, err := pe.Expr.EvalInt(ctx, row)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if isNull {
+		return 0, nil
+	}
+	idx := ret % int64(len(pi.Definitions))
+	if idx < 0 {
+		idx = -idx
+	}
+	return int(idx), nil
+}
+
+// PartitionExpr returns the partition expressions of the table.
+func (t *partitionedTable) PartitionExpr() *PartitionExpr {
+	return t.partitionExpr
+}
+
+// GetPartition returns a Table, which is actually a partition.
+func (t *partitionedTable) GetPartition(pid int64) table.PhysicalTable {
+	// Attention, can't simply use `return t.partitions[pid]` here.
+	// Because A nil of type *partition is a kind of `table.PhysicalTable`
+	p, ok := t.partitions[pid]
+	if !ok {
+		return nil
+	}
+	return p
+}
+
+// GetPartitionByRow returns a Table, which is actually a Partition.
+func (t *partitionedTable) GetPartitionByRow(ctx sessionctx.Context, r []types.Datum) (table.PhysicalTable, error) {
+	pid, err := t.locatePartition(ctx, r)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return t.partitions[pid], nil
+}
+
+// locatePartition returns the physical ID of the partition the row belongs to.
+func (t *partitionedTable) locatePartition(ctx sessionctx.Context, r []types.Datum) (int64, error) {
+	pi := t.meta.GetPartitionInfo()
+	idx, err := t.partitionExpr.LocatePartition(ctx, pi, chunk.MutRowFromDatums(r).ToRow())
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return pi.Definitions[idx].ID, nil
+}
+
+// AddRecord implements the AddRecord method for the table.Table interface.
+func (t *partitionedTable) AddRecord(ctx sessionctx.Context, r []types.Datum, opts ...table.AddRecordOption) (recordID int64, err error) {
+	pid, err := t.locatePartition(ctx, r)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return t.partitions[pid].AddRecord(ctx, r, opts...)
+}
+
+// RemoveRecord implements table.Table RemoveRecord interface.
+func (t *partitionedTable) RemoveRecord(ctx sessionctx.Context, h int64, r []types.Datum) error {
+	pid, err := t.locatePartition(ctx, r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return t.partitions[pid].RemoveRecord(ctx, h, r)
+}
+
+// UpdateRecord implements table.Table UpdateRecord interface.
+// `touched` means which columns are really modified, used for secondary indices.
+// Length of `oldData` and `newData` equals to length of `t.WritableCols()`.
+func (t *partitionedTable) UpdateRecord(ctx sessionctx.Context, h int64, currData, newData []types.Datum, touched []bool) error {
+	from, err := t.locatePartition(ctx, currData)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	to, err := t.locatePartition(ctx, newData)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// The old and new data locate in different partitions.
+	// Remove record from old partition and add record to new partition.
+	if from != to {
+		// Add the record first, so the errors such as 'Key Already Exists' are returned
+		// before the old record is removed.
+		_, err = t.partitions[to].AddRecord(ctx, newData, table.IsUpdate)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return t.partitions[from].RemoveRecord(ctx, h, currData)
+	}
+	return t.partitions[to].UpdateRecord(ctx, h, currData, newData, touched)
+}
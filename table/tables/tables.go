@@ -103,7 +103,11 @@ func TableFromMeta(alloc autoid.Allocator, tblInfo *model.TableInfo) (table.Tabl
 	if err := initTableIndices(&t); err != nil {
 		return nil, err
 	}
-	return &t, nil
+	if tblInfo.GetPartitionInfo() == nil {
+		return &t, nil
+	}
+
+	return newPartitionedTable(&t, tblInfo)
 }
 
 // initTableCommon initializes a TableCommon struct.
@@ -493,7 +497,10 @@ func (t *TableCommon) AddRecord(ctx sessionctx.Context, r []types.Datum, opts ..
 	}
 	ctx.StmtAddDirtyTableOP(table.DirtyTableAddRow, t.physicalTableID, recordID)
 
-	sc.AddAffectedRows(1)
+	// The affected rows of an update are counted by the caller.
+	if !opt.IsUpdate {
+		sc.AddAffectedRows(1)
+	}
 	colSize := make(map[int64]int64, len(r))
 	for id, col := range t.Cols() {
 		size, err := codec.EstimateValueSize(sc, r[id])
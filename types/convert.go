@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/util/hack"
 )
 
 func truncateStr(str string, flen int) string {
@@ -173,6 +174,27 @@ func ConvertFloatToUint(sc *stmtctx.StatementContext, fval float64, upperBound u
 	return uint64(val), nil
 }
 
+// ConvertDecimalToUint converts a decimal to a uint value, the decimal is rounded to an integer first.
+func ConvertDecimalToUint(sc *stmtctx.StatementContext, d *MyDecimal, upperBound uint64, tp byte) (uint64, error) {
+	var to MyDecimal
+	err := d.Round(&to, 0, ModeHalfEven)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if to.IsNegative() {
+		if sc.ShouldClipToZero() {
+			return 0, overflow(d, tp)
+		}
+		val, _ := to.ToInt()
+		return uint64(val), overflow(d, tp)
+	}
+	val, err := to.ToUint()
+	if err != nil || val > upperBound {
+		return upperBound, overflow(d, tp)
+	}
+	return val, nil
+}
+
 // convertScientificNotation converts a decimal string with scientific notation to a normal decimal string.
 // 1E6 => 1000000, .12345E+5 => 12345
 func convertScientificNotation(str string) (string, error) {
@@ -428,7 +450,7 @@ func StrToFloat(sc *stmtctx.StatementContext, str string) (float64, error) {
 
 // getValidFloatPrefix gets prefix of string which can be successfully parsed as float.
 func getValidFloatPrefix(sc *stmtctx.StatementContext, s string) (valid string, err error) {
-	if (sc.InDeleteStmt || sc.InSelectStmt) && s == "" {
+	if (sc.InDeleteStmt || sc.InSelectStmt || sc.InUpdateStmt) && s == "" {
 		return "0", nil
 	}
 
@@ -499,7 +521,111 @@ func ToString(value interface{}) (string, error) {
 		return v, nil
 	case []byte:
 		return string(v), nil
+	case Time:
+		return v.String(), nil
+	case Duration:
+		return v.String(), nil
+	case BinaryJSON:
+		return v.String(), nil
 	default:
 		return "", errors.Errorf("cannot convert %v(type %T) to string", value, value)
 	}
 }
+
+// ConvertJSONToInt casts JSON into int64.
+func ConvertJSONToInt(sc *stmtctx.StatementContext, j BinaryJSON, unsigned bool) (int64, error) {
+	switch j.TypeCode {
+	case JSONTypeCodeObject, JSONTypeCodeArray:
+		return 0, nil
+	case JSONTypeCodeLiteral:
+		switch j.Value[0] {
+		case JSONLiteralNil, JSONLiteralFalse:
+			return 0, nil
+		default:
+			return 1, nil
+		}
+	case JSONTypeCodeInt64, JSONTypeCodeUint64:
+		return j.GetInt64(), nil
+	case JSONTypeCodeFloat64:
+		f := j.GetFloat64()
+		if !unsigned {
+			lBound := IntergerSignedLowerBound(mysql.TypeLonglong)
+			uBound := IntergerSignedUpperBound(mysql.TypeLonglong)
+			return ConvertFloatToInt(f, lBound, uBound, mysql.TypeLonglong)
+		}
+		bound := IntergerUnsignedUpperBound(mysql.TypeLonglong)
+		u, err := ConvertFloatToUint(sc, f, bound, mysql.TypeLonglong)
+		return int64(u), errors.Trace(err)
+	case JSONTypeCodeOpaque:
+		d := j.GetDecimal()
+		if d == nil {
+			break
+		}
+		var to MyDecimal
+		if err := d.Round(&to, 0, ModeHalfEven); err != nil {
+			return 0, errors.Trace(err)
+		}
+		if !unsigned {
+			i, err := to.ToInt()
+			return i, errors.Trace(err)
+		}
+		u, err := to.ToUint()
+		return int64(u), errors.Trace(err)
+	case JSONTypeCodeString:
+		str := string(hack.String(j.GetString()))
+		if !unsigned {
+			return StrToInt(sc, str)
+		}
+		u, err := StrToUint(sc, str)
+		return int64(u), errors.Trace(err)
+	}
+	return 0, errors.New("Unknown type code in JSON")
+}
+
+// ConvertJSONToFloat casts JSON into float64.
+func ConvertJSONToFloat(sc *stmtctx.StatementContext, j BinaryJSON) (float64, error) {
+	switch j.TypeCode {
+	case JSONTypeCodeObject, JSONTypeCodeArray:
+		return 0, nil
+	case JSONTypeCodeLiteral:
+		switch j.Value[0] {
+		case JSONLiteralNil, JSONLiteralFalse:
+			return 0, nil
+		default:
+			return 1, nil
+		}
+	case JSONTypeCodeInt64:
+		return float64(j.GetInt64()), nil
+	case JSONTypeCodeUint64:
+		return float64(j.GetUint64()), nil
+	case JSONTypeCodeFloat64:
+		return j.GetFloat64(), nil
+	case JSONTypeCodeOpaque:
+		if d := j.GetDecimal(); d != nil {
+			f, err := d.ToFloat64()
+			return f, errors.Trace(err)
+		}
+	case JSONTypeCodeString:
+		str := string(hack.String(j.GetString()))
+		return StrToFloat(sc, str)
+	}
+	return 0, errors.New("Unknown type code in JSON")
+}
+
+// ConvertJSONToDecimal casts JSON into decimal.
+func ConvertJSONToDecimal(sc *stmtctx.StatementContext, j BinaryJSON) (*MyDecimal, error) {
+	if d := j.GetDecimal(); d != nil {
+		return d, nil
+	}
+	res := new(MyDecimal)
+	if j.TypeCode != JSONTypeCodeString {
+		f64, err := ConvertJSONToFloat(sc, j)
+		if err != nil {
+			return res, errors.Trace(err)
+		}
+		err = res.FromFloat64(f64)
+		return res, errors.Trace(err)
+	}
+	err := sc.HandleTruncate(res.FromString(j.GetString()))
+	return res, errors.Trace(err)
+}
@@ -19,6 +19,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/pingcap/errors"
@@ -38,7 +39,9 @@ const (
 	KindFloat64       byte = 4
 	KindString        byte = 5
 	KindBytes         byte = 6
-	KindBinaryLiteral byte = 7  // Used for BIT / HEX literals.
+	KindBinaryLiteral byte = 7 // Used for BIT / HEX literals.
+	KindMysqlDecimal  byte = 8
+	KindMysqlDuration byte = 9
 	KindMysqlBit      byte = 11 // Used for BIT table column values.
 	KindMysqlSet      byte = 12
 	KindMysqlTime     byte = 13
@@ -68,6 +71,10 @@ func (d *Datum) Copy() *Datum {
 		ret.b = make([]byte, len(d.b))
 		copy(ret.b, d.b)
 	}
+	if d.k == KindMysqlDecimal {
+		dec := *d.GetMysqlDecimal()
+		ret.x = &dec
+	}
 	return &ret
 }
 
@@ -244,6 +251,54 @@ func (d *Datum) GetRaw() []byte {
 	return d.b
 }
 
+// GetMysqlDecimal gets Decimal value
+func (d *Datum) GetMysqlDecimal() *MyDecimal {
+	return d.x.(*MyDecimal)
+}
+
+// SetMysqlDecimal sets Decimal value
+func (d *Datum) SetMysqlDecimal(b *MyDecimal) {
+	d.k = KindMysqlDecimal
+	d.x = b
+}
+
+// GetMysqlDuration gets Duration value
+func (d *Datum) GetMysqlDuration() Duration {
+	return Duration{Duration: time.Duration(d.i), Fsp: int8(d.decimal)}
+}
+
+// SetMysqlDuration sets Duration value
+func (d *Datum) SetMysqlDuration(b Duration) {
+	d.k = KindMysqlDuration
+	d.i = int64(b.Duration)
+	d.decimal = uint16(b.Fsp)
+}
+
+// GetMysqlTime gets types.Time value
+func (d *Datum) GetMysqlTime() Time {
+	return NewTime(CoreTime(d.i), uint8(d.length), int8(d.decimal))
+}
+
+// SetMysqlTime sets types.Time value
+func (d *Datum) SetMysqlTime(b Time) {
+	d.k = KindMysqlTime
+	d.i = int64(b.CoreTime())
+	d.length = uint32(b.Type())
+	d.decimal = uint16(b.Fsp())
+}
+
+// GetMysqlJSON gets BinaryJSON value
+func (d *Datum) GetMysqlJSON() BinaryJSON {
+	return BinaryJSON{TypeCode: d.x.(JSONTypeCode), Value: d.b}
+}
+
+// SetMysqlJSON sets BinaryJSON value
+func (d *Datum) SetMysqlJSON(b BinaryJSON) {
+	d.k = KindMysqlJSON
+	d.x = b.TypeCode
+	d.b = b.Value
+}
+
 // SetAutoID set the auto increment ID according to its int flag.
 func (d *Datum) SetAutoID(id int64, flag uint) {
 	if mysql.HasUnsignedFlag(flag) {
@@ -273,6 +328,10 @@ func (d Datum) String() string {
 		t = "KindBytes"
 	case KindBinaryLiteral:
 		t = "KindBinaryLiteral"
+	case KindMysqlDecimal:
+		t = "KindMysqlDecimal"
+	case KindMysqlDuration:
+		t = "KindMysqlDuration"
 	case KindMysqlBit:
 		t = "KindMysqlBit"
 	case KindMysqlSet:
@@ -308,6 +367,14 @@ func (d *Datum) GetValue() interface{} {
 		return d.GetBytes()
 	case KindBinaryLiteral, KindMysqlBit:
 		return d.GetBinaryLiteral()
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal()
+	case KindMysqlDuration:
+		return d.GetMysqlDuration()
+	case KindMysqlTime:
+		return d.GetMysqlTime()
+	case KindMysqlJSON:
+		return d.GetMysqlJSON()
 	default:
 		return d.GetInterface()
 	}
@@ -344,6 +411,14 @@ func (d *Datum) SetValue(val interface{}) {
 		d.SetBinaryLiteral(BinaryLiteral(x))
 	case HexLiteral:
 		d.SetBinaryLiteral(BinaryLiteral(x))
+	case *MyDecimal:
+		d.SetMysqlDecimal(x)
+	case Duration:
+		d.SetMysqlDuration(x)
+	case Time:
+		d.SetMysqlTime(x)
+	case BinaryJSON:
+		d.SetMysqlJSON(x)
 	default:
 		d.SetInterface(x)
 	}
@@ -386,6 +461,14 @@ func (d *Datum) CompareDatum(sc *stmtctx.StatementContext, ad *Datum) (int, erro
 		return d.compareBytes(sc, ad.GetBytes())
 	case KindBinaryLiteral, KindMysqlBit:
 		return d.compareBinaryLiteral(sc, ad.GetBinaryLiteral())
+	case KindMysqlDecimal:
+		return d.compareMysqlDecimal(sc, ad.GetMysqlDecimal())
+	case KindMysqlDuration:
+		return d.compareMysqlDuration(sc, ad.GetMysqlDuration())
+	case KindMysqlTime:
+		return d.compareMysqlTime(sc, ad.GetMysqlTime())
+	case KindMysqlJSON:
+		return d.compareMysqlJSON(sc, ad.GetMysqlJSON())
 	default:
 		return 0, nil
 	}
@@ -402,6 +485,8 @@ func (d *Datum) compareInt64(sc *stmtctx.StatementContext, i int64) (int, error)
 			return 1, nil
 		}
 		return CompareInt64(d.i, i), nil
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal().Compare(NewDecFromInt(i)), nil
 	default:
 		return d.compareFloat64(sc, float64(i))
 	}
@@ -418,6 +503,8 @@ func (d *Datum) compareUint64(sc *stmtctx.StatementContext, u uint64) (int, erro
 		return CompareInt64(d.i, int64(u)), nil
 	case KindUint64:
 		return CompareUint64(d.GetUint64(), u), nil
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal().Compare(NewDecFromUint(u)), nil
 	default:
 		return d.compareFloat64(sc, float64(u))
 	}
@@ -442,6 +529,9 @@ func (d *Datum) compareFloat64(sc *stmtctx.StatementContext, f float64) (int, er
 		val, err := d.GetBinaryLiteral().ToInt(sc)
 		fVal := float64(val)
 		return CompareFloat64(fVal, f), errors.Trace(err)
+	case KindMysqlDecimal:
+		fVal, err := d.GetMysqlDecimal().ToFloat64()
+		return CompareFloat64(fVal, f), errors.Trace(err)
 	default:
 		return -1, nil
 	}
@@ -457,6 +547,16 @@ func (d *Datum) compareString(sc *stmtctx.StatementContext, s string) (int, erro
 		return CompareString(d.GetString(), s), nil
 	case KindBinaryLiteral, KindMysqlBit:
 		return CompareString(d.GetBinaryLiteral().ToString(), s), nil
+	case KindMysqlDecimal:
+		dec := new(MyDecimal)
+		err := sc.HandleTruncate(dec.FromString([]byte(s)))
+		return d.GetMysqlDecimal().Compare(dec), errors.Trace(err)
+	case KindMysqlTime:
+		dt, err := ParseDatetime(sc, s)
+		return d.GetMysqlTime().Compare(dt), errors.Trace(err)
+	case KindMysqlDuration:
+		dur, err := ParseDuration(sc, s, MaxFsp)
+		return d.GetMysqlDuration().Compare(dur), errors.Trace(err)
 	default:
 		fVal, err := StrToFloat(sc, s)
 		if err != nil {
@@ -471,6 +571,71 @@ func (d *Datum) compareBytes(sc *stmtctx.StatementContext, b []byte) (int, error
 	return d.compareString(sc, str)
 }
 
+func (d *Datum) compareMysqlDecimal(sc *stmtctx.StatementContext, dec *MyDecimal) (int, error) {
+	switch d.k {
+	case KindNull, KindMinNotNull:
+		return -1, nil
+	case KindMaxValue:
+		return 1, nil
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal().Compare(dec), nil
+	case KindString, KindBytes:
+		dDec := new(MyDecimal)
+		err := sc.HandleTruncate(dDec.FromString(d.GetBytes()))
+		return dDec.Compare(dec), errors.Trace(err)
+	case KindInt64:
+		return NewDecFromInt(d.GetInt64()).Compare(dec), nil
+	case KindUint64:
+		return NewDecFromUint(d.GetUint64()).Compare(dec), nil
+	default:
+		dVal, err := d.convertToMysqlDecimal(sc, NewFieldType(mysql.TypeNewDecimal))
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		return dVal.GetMysqlDecimal().Compare(dec), nil
+	}
+}
+
+func (d *Datum) compareMysqlDuration(sc *stmtctx.StatementContext, dur Duration) (int, error) {
+	switch d.k {
+	case KindNull, KindMinNotNull:
+		return -1, nil
+	case KindMaxValue:
+		return 1, nil
+	case KindMysqlDuration:
+		return d.GetMysqlDuration().Compare(dur), nil
+	case KindString, KindBytes:
+		dDur, err := ParseDuration(sc, d.GetString(), MaxFsp)
+		return dDur.Compare(dur), errors.Trace(err)
+	default:
+		fVal, err := dur.ToNumber().ToFloat64()
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		return d.compareFloat64(sc, fVal)
+	}
+}
+
+func (d *Datum) compareMysqlTime(sc *stmtctx.StatementContext, time Time) (int, error) {
+	switch d.k {
+	case KindNull, KindMinNotNull:
+		return -1, nil
+	case KindMaxValue:
+		return 1, nil
+	case KindString, KindBytes:
+		dt, err := ParseDatetime(sc, d.GetString())
+		return dt.Compare(time), errors.Trace(err)
+	case KindMysqlTime:
+		return d.GetMysqlTime().Compare(time), nil
+	default:
+		fVal, err := time.ToNumber().ToFloat64()
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		return d.compareFloat64(sc, fVal)
+	}
+}
+
 func (d *Datum) compareBinaryLiteral(sc *stmtctx.StatementContext, b BinaryLiteral) (int, error) {
 	switch d.k {
 	case KindString, KindBytes:
@@ -487,6 +652,20 @@ func (d *Datum) compareBinaryLiteral(sc *stmtctx.StatementContext, b BinaryLiter
 	}
 }
 
+func (d *Datum) compareMysqlJSON(sc *stmtctx.StatementContext, target BinaryJSON) (int, error) {
+	switch d.k {
+	case KindNull, KindMinNotNull:
+		return -1, nil
+	case KindMaxValue:
+		return 1, nil
+	}
+	origin, err := d.ToMysqlJSON()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return CompareBinaryJSON(origin, target), nil
+}
+
 // ConvertTo converts a datum to the target field type.
 func (d *Datum) ConvertTo(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
 	if d.k == KindNull {
@@ -504,8 +683,16 @@ func (d *Datum) ConvertTo(sc *stmtctx.StatementContext, target *FieldType) (Datu
 	case mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob,
 		mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString:
 		return d.convertToString(sc, target)
+	case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDate:
+		return d.convertToMysqlTime(sc, target)
+	case mysql.TypeDuration:
+		return d.convertToMysqlDuration(sc, target)
+	case mysql.TypeNewDecimal:
+		return d.convertToMysqlDecimal(sc, target)
 	case mysql.TypeBit:
 		return d.convertToMysqlBit(sc, target)
+	case mysql.TypeJSON:
+		return d.convertToMysqlJSON(sc, target)
 	case mysql.TypeNull:
 		return Datum{}, nil
 	default:
@@ -533,6 +720,14 @@ func (d *Datum) convertToFloat(sc *stmtctx.StatementContext, target *FieldType)
 	case KindBinaryLiteral, KindMysqlBit:
 		val, err1 := d.GetBinaryLiteral().ToInt(sc)
 		f, err = float64(val), err1
+	case KindMysqlDecimal:
+		f, err = d.GetMysqlDecimal().ToFloat64()
+	case KindMysqlTime:
+		f, err = d.GetMysqlTime().ToNumber().ToFloat64()
+	case KindMysqlDuration:
+		f, err = d.GetMysqlDuration().ToNumber().ToFloat64()
+	case KindMysqlJSON:
+		f, err = ConvertJSONToFloat(sc, d.GetMysqlJSON())
 	default:
 		return invalidConv(d, target.Tp)
 	}
@@ -581,6 +776,14 @@ func (d *Datum) convertToString(sc *stmtctx.StatementContext, target *FieldType)
 		s = d.GetString()
 	case KindBinaryLiteral, KindMysqlBit:
 		s = d.GetBinaryLiteral().ToString()
+	case KindMysqlDecimal:
+		s = d.GetMysqlDecimal().String()
+	case KindMysqlTime:
+		s = d.GetMysqlTime().String()
+	case KindMysqlDuration:
+		s = d.GetMysqlDuration().String()
+	case KindMysqlJSON:
+		s = d.GetMysqlJSON().String()
 	default:
 		return invalidConv(d, target.Tp)
 	}
@@ -663,6 +866,16 @@ func (d *Datum) convertToUint(sc *stmtctx.StatementContext, target *FieldType) (
 		err = err1
 	case KindBinaryLiteral, KindMysqlBit:
 		val, err = d.GetBinaryLiteral().ToInt(sc)
+	case KindMysqlDecimal:
+		val, err = ConvertDecimalToUint(sc, d.GetMysqlDecimal(), upperBound, tp)
+	case KindMysqlTime:
+		val, err = ConvertDecimalToUint(sc, d.GetMysqlTime().ToNumber(), upperBound, tp)
+	case KindMysqlDuration:
+		val, err = ConvertDecimalToUint(sc, d.GetMysqlDuration().ToNumber(), upperBound, tp)
+	case KindMysqlJSON:
+		var i64 int64
+		i64, err = ConvertJSONToInt(sc, d.GetMysqlJSON(), true)
+		val = uint64(i64)
 	default:
 		return invalidConv(d, target.Tp)
 	}
@@ -673,6 +886,120 @@ func (d *Datum) convertToUint(sc *stmtctx.StatementContext, target *FieldType) (
 	return ret, nil
 }
 
+func (d *Datum) convertToMysqlTime(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	// Time has a fsp, so we should use target's fsp.
+	tp := target.Tp
+	fsp := DefaultFsp
+	if target.Decimal != UnspecifiedLength {
+		fsp = int8(target.Decimal)
+	}
+	var (
+		ret Datum
+		t   Time
+		err error
+	)
+	switch d.k {
+	case KindMysqlTime:
+		t, err = d.GetMysqlTime().Convert(sc, tp)
+		if err != nil {
+			ret.SetMysqlTime(t)
+			return ret, errors.Trace(err)
+		}
+		t, err = t.RoundFrac(fsp)
+	case KindMysqlDuration:
+		t, err = d.GetMysqlDuration().ConvertToTime(sc, tp)
+		if err != nil {
+			ret.SetMysqlTime(t)
+			return ret, errors.Trace(err)
+		}
+		t, err = t.RoundFrac(fsp)
+	case KindMysqlDecimal:
+		t, err = ParseTimeFromFloatString(sc, d.GetMysqlDecimal().String(), tp, fsp)
+	case KindString, KindBytes:
+		t, err = ParseTime(sc, d.GetString(), tp, fsp)
+	case KindInt64:
+		t, err = ParseTimeFromNum(sc, d.GetInt64(), tp, fsp)
+	case KindUint64:
+		t, err = ParseTimeFromNum(sc, int64(d.GetUint64()), tp, fsp)
+	case KindFloat32, KindFloat64:
+		t, err = ParseTimeFromFloatString(sc, strconv.FormatFloat(d.GetFloat64(), 'f', -1, 64), tp, fsp)
+	case KindMysqlJSON:
+		s := NewStringDatum(d.GetMysqlJSON().Unquote())
+		return s.convertToMysqlTime(sc, target)
+	default:
+		return invalidConv(d, tp)
+	}
+	if tp == mysql.TypeDate {
+		// Truncate hh:mm:ss part if the type is Date.
+		t.SetCoreTime(FromDate(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0))
+	}
+	ret.SetMysqlTime(t)
+	return ret, errors.Trace(err)
+}
+
+func (d *Datum) convertToMysqlDuration(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	tp := target.Tp
+	fsp := DefaultFsp
+	if target.Decimal != UnspecifiedLength {
+		fsp = int8(target.Decimal)
+	}
+	var ret Datum
+	switch d.k {
+	case KindMysqlTime:
+		dur, err := d.GetMysqlTime().ConvertToDuration()
+		if err != nil {
+			ret.SetMysqlDuration(ZeroDuration)
+			return ret, errors.Trace(err)
+		}
+		dur, err = dur.RoundFrac(fsp)
+		ret.SetMysqlDuration(dur)
+		if err != nil {
+			return ret, errors.Trace(err)
+		}
+	case KindMysqlDuration:
+		dur, err := d.GetMysqlDuration().RoundFrac(fsp)
+		ret.SetMysqlDuration(dur)
+		if err != nil {
+			return ret, errors.Trace(err)
+		}
+	case KindInt64, KindUint64, KindFloat32, KindFloat64, KindMysqlDecimal:
+		timeStr, err := d.ToString()
+		if err != nil {
+			return ret, errors.Trace(err)
+		}
+		timeNum, err := d.ToInt64(sc)
+		if err != nil {
+			return ret, errors.Trace(err)
+		}
+		// For huge numbers(>'0001-00-00 00-00-00') try full DATETIME in ParseDuration.
+		if timeNum > MaxDuration && timeNum < 10000000000 {
+			// mysql return max in no strict sql mode.
+			ret.SetMysqlDuration(Duration{Duration: MaxTime, Fsp: 0})
+			return ret, ErrWrongValue.GenWithStackByArgs(TimeStr, timeStr)
+		}
+		if timeNum < -MaxDuration {
+			return ret, ErrWrongValue.GenWithStackByArgs(TimeStr, timeStr)
+		}
+		t, err := ParseDuration(sc, timeStr, fsp)
+		ret.SetMysqlDuration(t)
+		if err != nil {
+			return ret, errors.Trace(err)
+		}
+	case KindString, KindBytes:
+		t, err := ParseDuration(sc, d.GetString(), fsp)
+		ret.SetMysqlDuration(t)
+		if err != nil {
+			return ret, errors.Trace(err)
+		}
+	case KindMysqlJSON:
+		s := NewStringDatum(d.GetMysqlJSON().Unquote())
+		return s.convertToMysqlDuration(sc, target)
+	default:
+		return invalidConv(d, tp)
+	}
+	return ret, nil
+}
+
 func (d *Datum) convertToMysqlBit(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
 	var ret Datum
 	var uintValue uint64
@@ -696,6 +1023,119 @@ func (d *Datum) convertToMysqlBit(sc *stmtctx.StatementContext, target *FieldTyp
 	return ret, errors.Trace(err)
 }
 
+func (d *Datum) convertToMysqlJSON(sc *stmtctx.StatementContext, target *FieldType) (ret Datum, err error) {
+	switch d.k {
+	case KindString, KindBytes:
+		var j BinaryJSON
+		if j, err = ParseBinaryJSONFromString(d.GetString()); err == nil {
+			ret.SetMysqlJSON(j)
+		}
+	case KindInt64:
+		ret.SetMysqlJSON(CreateBinaryJSON(d.GetInt64()))
+	case KindUint64:
+		ret.SetMysqlJSON(CreateBinaryJSON(d.GetUint64()))
+	case KindFloat32, KindFloat64:
+		ret.SetMysqlJSON(CreateBinaryJSON(d.GetFloat64()))
+	case KindMysqlDecimal:
+		ret.SetMysqlJSON(CreateBinaryJSON(d.GetMysqlDecimal()))
+	case KindMysqlJSON:
+		ret = *d
+	default:
+		var s string
+		if s, err = d.ToString(); err == nil {
+			ret.SetMysqlJSON(CreateBinaryJSON(s))
+		}
+	}
+	return ret, errors.Trace(err)
+}
+
+func (d *Datum) convertToMysqlDecimal(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	var ret Datum
+	ret.SetLength(target.Flen)
+	ret.SetFrac(target.Decimal)
+	var dec = &MyDecimal{}
+	var err error
+	switch d.k {
+	case KindInt64:
+		dec.FromInt(d.GetInt64())
+	case KindUint64:
+		dec.FromUint(d.GetUint64())
+	case KindFloat32, KindFloat64:
+		err = dec.FromFloat64(d.GetFloat64())
+	case KindString, KindBytes:
+		err = dec.FromString(d.GetBytes())
+	case KindMysqlDecimal:
+		*dec = *d.GetMysqlDecimal()
+	case KindMysqlTime:
+		dec = d.GetMysqlTime().ToNumber()
+	case KindMysqlDuration:
+		dec = d.GetMysqlDuration().ToNumber()
+	case KindBinaryLiteral, KindMysqlBit:
+		val, err1 := d.GetBinaryLiteral().ToInt(sc)
+		err = err1
+		dec.FromUint(val)
+	case KindMysqlJSON:
+		dec, err = ConvertJSONToDecimal(sc, d.GetMysqlJSON())
+	default:
+		return invalidConv(d, target.Tp)
+	}
+	if err == ErrTruncated || err == ErrBadNumber {
+		err = sc.HandleTruncate(ErrTruncatedWrongVal.GenWithStackByArgs("DECIMAL", d.GetValue()))
+	}
+	var err1 error
+	dec, err1 = ProduceDecWithSpecifiedTp(dec, target, sc)
+	if err == nil && err1 != nil {
+		err = err1
+	}
+	if dec.negative && mysql.HasUnsignedFlag(target.Flag) {
+		*dec = zeroMyDecimal
+		if err == nil {
+			err = ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%d, %d)", target.Flen, target.Decimal))
+		}
+	}
+	ret.SetMysqlDecimal(dec)
+	return ret, err
+}
+
+// ProduceDecWithSpecifiedTp produces a new decimal according to `flen` and `decimal`.
+func ProduceDecWithSpecifiedTp(dec *MyDecimal, tp *FieldType, sc *stmtctx.StatementContext) (_ *MyDecimal, err error) {
+	flen, decimal := tp.Flen, tp.Decimal
+	if flen != UnspecifiedLength && decimal != UnspecifiedLength {
+		if flen < decimal {
+			return nil, ErrMBiggerThanD.GenWithStackByArgs("")
+		}
+		prec, frac := dec.PrecisionAndFrac()
+		if !dec.IsZero() && prec-frac > flen-decimal {
+			dec = NewMaxOrMinDec(dec.IsNegative(), flen, decimal)
+			// select (cast 111 as decimal(1)) causes a warning in MySQL.
+			err = ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%d, %d)", flen, decimal))
+		} else if frac != decimal {
+			old := *dec
+			err = dec.Round(dec, decimal, ModeHalfEven)
+			if err != nil {
+				return nil, err
+			}
+			prec, frac = dec.PrecisionAndFrac()
+			if !dec.IsZero() && prec-frac > flen-decimal {
+				// Rounding may carry into a new integer digit, e.g. 9.99 as decimal(2, 1).
+				dec = NewMaxOrMinDec(dec.IsNegative(), flen, decimal)
+				err = ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%d, %d)", flen, decimal))
+			} else if !dec.IsZero() && frac > decimal && dec.Compare(&old) != 0 {
+				err = sc.HandleTruncate(ErrTruncatedWrongVal.GenWithStackByArgs("DECIMAL", &old))
+			}
+		}
+	}
+
+	if ErrOverflow.Equal(err) {
+		err = sc.HandleOverflow(err, err)
+	}
+	unsigned := mysql.HasUnsignedFlag(tp.Flag)
+	if unsigned && dec.IsNegative() {
+		dec = dec.FromUint(0)
+	}
+	return dec, err
+}
+
 // ToBool converts to a bool.
 // We will use 1 for true, and 0 for false.
 func (d *Datum) ToBool(sc *stmtctx.StatementContext) (int64, error) {
@@ -716,6 +1156,15 @@ func (d *Datum) ToBool(sc *stmtctx.StatementContext) (int64, error) {
 	case KindBinaryLiteral, KindMysqlBit:
 		val, err1 := d.GetBinaryLiteral().ToInt(sc)
 		isZero, err = val == 0, err1
+	case KindMysqlDecimal:
+		isZero = d.GetMysqlDecimal().IsZero()
+	case KindMysqlTime:
+		isZero = d.GetMysqlTime().IsZero()
+	case KindMysqlDuration:
+		isZero = d.GetMysqlDuration().Duration == 0
+	case KindMysqlJSON:
+		val, err1 := ConvertJSONToFloat(sc, d.GetMysqlJSON())
+		isZero, err = val == 0, err1
 	default:
 		return 0, errors.Errorf("cannot convert %v(type %T) to bool", d.GetValue(), d.GetValue())
 	}
@@ -758,6 +1207,45 @@ func (d *Datum) toSignedInteger(sc *stmtctx.StatementContext, tp byte) (int64, e
 	case KindBinaryLiteral, KindMysqlBit:
 		val, err := d.GetBinaryLiteral().ToInt(sc)
 		return int64(val), errors.Trace(err)
+	case KindMysqlDecimal:
+		var to MyDecimal
+		err := d.GetMysqlDecimal().Round(&to, 0, ModeHalfEven)
+		ival, err1 := to.ToInt()
+		if err == nil {
+			err = err1
+		}
+		if err == ErrOverflow {
+			return ival, ErrOverflow.GenWithStackByArgs("BIGINT", d.GetMysqlDecimal().String())
+		}
+		ival, err2 := ConvertIntToInt(ival, lowerBound, upperBound, tp)
+		if err == nil || err == ErrTruncated {
+			err = err2
+		}
+		return ival, errors.Trace(err)
+	case KindMysqlTime, KindMysqlDuration:
+		var dec *MyDecimal
+		if d.Kind() == KindMysqlTime {
+			dec = d.GetMysqlTime().ToNumber()
+		} else {
+			dec = d.GetMysqlDuration().ToNumber()
+		}
+		err := dec.Round(dec, 0, ModeHalfEven)
+		ival, err1 := dec.ToInt()
+		if err == nil {
+			err = err1
+		}
+		ival, err2 := ConvertIntToInt(ival, lowerBound, upperBound, tp)
+		if err == nil {
+			err = err2
+		}
+		return ival, errors.Trace(err)
+	case KindMysqlJSON:
+		ival, err := ConvertJSONToInt(sc, d.GetMysqlJSON(), false)
+		ival, err2 := ConvertIntToInt(ival, lowerBound, upperBound, tp)
+		if err == nil {
+			err = err2
+		}
+		return ival, errors.Trace(err)
 	default:
 		return 0, errors.Errorf("cannot convert %v(type %T) to int64", d.GetValue(), d.GetValue())
 	}
@@ -781,11 +1269,31 @@ func (d *Datum) ToFloat64(sc *stmtctx.StatementContext) (float64, error) {
 	case KindBinaryLiteral, KindMysqlBit:
 		val, err := d.GetBinaryLiteral().ToInt(sc)
 		return float64(val), errors.Trace(err)
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal().ToFloat64()
+	case KindMysqlTime:
+		return d.GetMysqlTime().ToNumber().ToFloat64()
+	case KindMysqlDuration:
+		return d.GetMysqlDuration().ToNumber().ToFloat64()
+	case KindMysqlJSON:
+		return ConvertJSONToFloat(sc, d.GetMysqlJSON())
 	default:
 		return 0, errors.Errorf("cannot convert %v(type %T) to float64", d.GetValue(), d.GetValue())
 	}
 }
 
+// ToDecimal converts to a decimal.
+func (d *Datum) ToDecimal(sc *stmtctx.StatementContext) (*MyDecimal, error) {
+	if d.Kind() == KindMysqlDecimal {
+		return d.GetMysqlDecimal(), nil
+	}
+	converted, err := d.convertToMysqlDecimal(sc, NewFieldType(mysql.TypeNewDecimal))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return converted.GetMysqlDecimal(), nil
+}
+
 // ToString gets the string representation of the datum.
 func (d *Datum) ToString() (string, error) {
 	switch d.Kind() {
@@ -803,11 +1311,52 @@ func (d *Datum) ToString() (string, error) {
 		return d.GetString(), nil
 	case KindBinaryLiteral, KindMysqlBit:
 		return d.GetBinaryLiteral().ToString(), nil
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal().String(), nil
+	case KindMysqlTime:
+		return d.GetMysqlTime().String(), nil
+	case KindMysqlDuration:
+		return d.GetMysqlDuration().String(), nil
+	case KindMysqlJSON:
+		return d.GetMysqlJSON().String(), nil
 	default:
 		return "", errors.Errorf("cannot convert %v(type %T) to string", d.GetValue(), d.GetValue())
 	}
 }
 
+// ToMysqlJSON is similar to convertToMysqlJSON, except the
+// latter parses from string, but the former uses it as primitive.
+func (d *Datum) ToMysqlJSON() (j BinaryJSON, err error) {
+	var in interface{}
+	switch d.Kind() {
+	case KindMysqlJSON:
+		j = d.GetMysqlJSON()
+		return
+	case KindInt64:
+		in = d.GetInt64()
+	case KindUint64:
+		in = d.GetUint64()
+	case KindFloat32, KindFloat64:
+		in = d.GetFloat64()
+	case KindMysqlDecimal:
+		in = d.GetMysqlDecimal()
+	case KindString, KindBytes:
+		in = d.GetString()
+	case KindBinaryLiteral, KindMysqlBit:
+		in = d.GetBinaryLiteral().ToString()
+	case KindNull:
+		in = nil
+	default:
+		in, err = d.ToString()
+	}
+	if err != nil {
+		err = errors.Trace(err)
+		return
+	}
+	j = CreateBinaryJSON(in)
+	return
+}
+
 // ToBytes gets the bytes representation of the datum.
 func (d *Datum) ToBytes() ([]byte, error) {
 	switch d.k {
@@ -867,6 +1416,30 @@ func NewFloat64Datum(f float64) (d Datum) {
 	return d
 }
 
+// NewDecimalDatum creates a new Datum from a MyDecimal value.
+func NewDecimalDatum(dec *MyDecimal) (d Datum) {
+	d.SetMysqlDecimal(dec)
+	return d
+}
+
+// NewDurationDatum creates a new Datum from a Duration value.
+func NewDurationDatum(dur Duration) (d Datum) {
+	d.SetMysqlDuration(dur)
+	return d
+}
+
+// NewTimeDatum creates a new Time from a Time value.
+func NewTimeDatum(t Time) (d Datum) {
+	d.SetMysqlTime(t)
+	return d
+}
+
+// NewJSONDatum creates a new Datum from a BinaryJSON value
+func NewJSONDatum(j BinaryJSON) (d Datum) {
+	d.SetMysqlJSON(j)
+	return d
+}
+
 // NewFloat32Datum creates a new Datum from a float32 value.
 func NewFloat32Datum(f float32) (d Datum) {
 	d.SetFloat32(f)
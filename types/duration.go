@@ -0,0 +1,383 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	gotime "time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+)
+
+// Duration is the type for MySQL TIME type.
+type Duration struct {
+	gotime.Duration
+	// Fsp is short for Fractional Seconds Precision.
+	// See http://dev.mysql.com/doc/refman/5.7/en/fractional-seconds.html
+	Fsp int8
+}
+
+// NewDuration constructs duration with time.
+func NewDuration(hour, minute, second, microsecond int, fsp int8) Duration {
+	return Duration{
+		Duration: gotime.Duration(hour)*gotime.Hour + gotime.Duration(minute)*gotime.Minute +
+			gotime.Duration(second)*gotime.Second + gotime.Duration(microsecond)*gotime.Microsecond,
+		Fsp: fsp,
+	}
+}
+
+// Add adds d to d, returns a duration value.
+func (d Duration) Add(v Duration) (Duration, error) {
+	dsum, err := AddInt64(int64(d.Duration), int64(v.Duration))
+	if err != nil {
+		return Duration{}, errors.Trace(err)
+	}
+	if d.Fsp >= v.Fsp {
+		return Duration{Duration: gotime.Duration(dsum), Fsp: d.Fsp}, nil
+	}
+	return Duration{Duration: gotime.Duration(dsum), Fsp: v.Fsp}, nil
+}
+
+// Sub subtracts d to d, returns a duration value.
+func (d Duration) Sub(v Duration) (Duration, error) {
+	dsum, err := SubInt64(int64(d.Duration), int64(v.Duration))
+	if err != nil {
+		return Duration{}, errors.Trace(err)
+	}
+	if d.Fsp >= v.Fsp {
+		return Duration{Duration: gotime.Duration(dsum), Fsp: d.Fsp}, nil
+	}
+	return Duration{Duration: gotime.Duration(dsum), Fsp: v.Fsp}, nil
+}
+
+// String returns the time formatted using default TimeFormat and fsp.
+func (d Duration) String() string {
+	var buf bytes.Buffer
+
+	sign, hours, minutes, seconds, fraction := splitDuration(d.Duration)
+	if sign < 0 {
+		buf.WriteByte('-')
+	}
+
+	fmt.Fprintf(&buf, "%02d:%02d:%02d", hours, minutes, seconds)
+	if d.Fsp > 0 {
+		buf.WriteString(".")
+		buf.WriteString(d.formatFrac(fraction))
+	}
+	return buf.String()
+}
+
+func (d Duration) formatFrac(frac int) string {
+	s := fmt.Sprintf("%06d", frac)
+	return s[0:d.Fsp]
+}
+
+// ToNumber changes duration to number format.
+// e.g,
+// 10:10:10 -> 101010
+func (d Duration) ToNumber() *MyDecimal {
+	sign, hours, minutes, seconds, fraction := splitDuration(d.Duration)
+	var signStr string
+	if sign < 0 {
+		signStr = "-"
+	}
+
+	// Convert to HHMMSS.000000
+	s := fmt.Sprintf("%s%02d%02d%02d", signStr, hours, minutes, seconds)
+	if d.Fsp > 0 {
+		s = fmt.Sprintf("%s.%s", s, d.formatFrac(fraction))
+	}
+
+	// We skip checking error here because time formatted string can be parsed certainly.
+	dec := new(MyDecimal)
+	err := dec.FromString([]byte(s))
+	terror.Log(errors.Trace(err))
+	return dec
+}
+
+// ConvertToTime converts duration to Time.
+// Tp is TypeDatetime, TypeTimestamp and TypeDate.
+func (d Duration) ConvertToTime(sc *stmtctx.StatementContext, tp uint8) (Time, error) {
+	year, month, day := gotime.Now().In(getTimeZone(sc)).Date()
+	datePart := gotime.Date(year, month, day, 0, 0, 0, 0, gotime.UTC)
+	t := NewTime(FromGoTime(datePart.Add(d.Duration)), mysql.TypeDatetime, d.Fsp)
+	return t.Convert(sc, tp)
+}
+
+// RoundFrac rounds fractional seconds precision with new fsp and returns a new one.
+// We will use the “round half up” rule, e.g, >= 0.5 -> 1, < 0.5 -> 0,
+// so 10:10:10.999999 round 0 -> 10:10:11
+// and 10:10:10.000000 round 0 -> 10:10:10
+func (d Duration) RoundFrac(fsp int8) (Duration, error) {
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+
+	if fsp == d.Fsp {
+		return d, nil
+	}
+
+	n := gotime.Date(0, 0, 0, 0, 0, 0, 0, gotime.UTC)
+	nd := n.Add(d.Duration).Round(gotime.Duration(math.Pow10(9-int(fsp))) * gotime.Nanosecond).Sub(n)
+	return Duration{Duration: nd, Fsp: fsp}, nil
+}
+
+// Compare returns an integer comparing the Duration instant t to o.
+// If d is after o, returns 1, equal o, returns 0, before o, returns -1.
+func (d Duration) Compare(o Duration) int {
+	if d.Duration > o.Duration {
+		return 1
+	} else if d.Duration == o.Duration {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+// CompareString is like Compare,
+// but parses str to Duration then compares.
+func (d Duration) CompareString(sc *stmtctx.StatementContext, str string) (int, error) {
+	// use MaxFsp to parse the string
+	o, err := ParseDuration(sc, str, MaxFsp)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.Compare(o), nil
+}
+
+// Hour returns current hour.
+// e.g, hour("11:11:11") -> 11
+func (d Duration) Hour() int {
+	_, hour, _, _, _ := splitDuration(d.Duration)
+	return hour
+}
+
+// Minute returns current minute.
+// e.g, hour("11:11:11") -> 11
+func (d Duration) Minute() int {
+	_, _, minute, _, _ := splitDuration(d.Duration)
+	return minute
+}
+
+// Second returns current second.
+// e.g, hour("11:11:11") -> 11
+func (d Duration) Second() int {
+	_, _, _, second, _ := splitDuration(d.Duration)
+	return second
+}
+
+// MicroSecond returns current microsecond.
+// e.g, hour("11:11:11.11") -> 110000
+func (d Duration) MicroSecond() int {
+	_, _, _, _, frac := splitDuration(d.Duration)
+	return frac
+}
+
+func splitDuration(t gotime.Duration) (int, int, int, int, int) {
+	sign := 1
+	if t < 0 {
+		t = -t
+		sign = -1
+	}
+
+	hours := t / gotime.Hour
+	t -= hours * gotime.Hour
+	minutes := t / gotime.Minute
+	t -= minutes * gotime.Minute
+	seconds := t / gotime.Second
+	t -= seconds * gotime.Second
+	fraction := t / gotime.Microsecond
+
+	return sign, int(hours), int(minutes), int(seconds), int(fraction)
+}
+
+func isDigitsOnly(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseDuration parses the time form a formatted string with a fractional seconds part,
+// returns the duration type Time value.
+// See http://dev.mysql.com/doc/refman/5.7/en/fractional-seconds.html
+func ParseDuration(sc *stmtctx.StatementContext, str string, fsp int8) (Duration, error) {
+	var (
+		day, hour, minute, second int
+		err                       error
+		sign                      = 0
+		dayExists                 = false
+		origStr                   = str
+	)
+
+	fsp, err = CheckFsp(int(fsp))
+	if err != nil {
+		return ZeroDuration, errors.Trace(err)
+	}
+
+	str = strings.TrimSpace(str)
+	if len(str) == 0 {
+		return ZeroDuration, nil
+	} else if str[0] == '-' {
+		str = str[1:]
+		sign = -1
+	}
+
+	// Time format may has day.
+	if n := strings.IndexByte(str, ' '); n >= 0 {
+		if day, err = strconv.Atoi(str[:n]); err == nil {
+			dayExists = true
+		}
+		str = str[n+1:]
+	}
+
+	var (
+		integeralPart = str
+		fracPart      int
+		overflow      bool
+	)
+	if n := strings.IndexByte(str, '.'); n >= 0 {
+		// It has fractional precision parts.
+		fracStr := str[n+1:]
+		fracPart, overflow, err = ParseFrac(fracStr, fsp)
+		if err != nil {
+			return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs("time", origStr)
+		}
+		integeralPart = str[0:n]
+	}
+
+	// It tries to split integeralPart with delimiter, time delimiter must be :
+	seps := strings.Split(integeralPart, ":")
+
+	switch len(seps) {
+	case 1:
+		if dayExists {
+			hour, err = strconv.Atoi(seps[0])
+		} else {
+			// No delimiter.
+			switch len(integeralPart) {
+			case 7: // HHHMMSS
+				err = scanDigits(integeralPart, []int{3, 2, 2}, &hour, &minute, &second)
+			case 6: // HHMMSS
+				err = scanDigits(integeralPart, []int{2, 2, 2}, &hour, &minute, &second)
+			case 5: // HMMSS
+				err = scanDigits(integeralPart, []int{1, 2, 2}, &hour, &minute, &second)
+			case 4: // MMSS
+				err = scanDigits(integeralPart, []int{2, 2}, &minute, &second)
+			case 3: // MSS
+				err = scanDigits(integeralPart, []int{1, 2}, &minute, &second)
+			case 2: // SS
+				err = scanDigits(integeralPart, []int{2}, &second)
+			case 1: // 0S
+				err = scanDigits(integeralPart, []int{1}, &second)
+			default: // Maybe contains date.
+				if !isDigitsOnly(integeralPart) {
+					return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs("time", origStr)
+				}
+				t, err1 := ParseDatetime(sc, origStr)
+				if err1 != nil {
+					return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs("time", origStr)
+				}
+				dur, err1 := t.ConvertToDuration()
+				if err1 != nil {
+					return ZeroDuration, errors.Trace(err1)
+				}
+				return dur.RoundFrac(fsp)
+			}
+		}
+	case 2:
+		// HH:MM
+		err = scanTimeArgs(seps, &hour, &minute)
+	case 3:
+		// Time format maybe HH:MM:SS or HHH:MM:SS.
+		// See https://dev.mysql.com/doc/refman/5.7/en/time.html
+		err = scanTimeArgs(seps, &hour, &minute, &second)
+	default:
+		return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs("time", origStr)
+	}
+
+	if err != nil {
+		return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs("time", origStr)
+	}
+
+	if overflow {
+		second++
+		fracPart = 0
+	}
+	// Invalid TIME values are converted to '00:00:00'.
+	// See https://dev.mysql.com/doc/refman/5.7/en/time.html
+	if minute >= 60 || second > 60 || (!overflow && second == 60) {
+		return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs("time", origStr)
+	}
+	d := gotime.Duration(day*24*3600+hour*3600+minute*60+second)*gotime.Second + gotime.Duration(fracPart)*gotime.Microsecond
+	if sign == -1 {
+		d = -d
+	}
+
+	d, err = TruncateOverflowMySQLTime(d)
+	return Duration{Duration: d, Fsp: fsp}, errors.Trace(err)
+}
+
+// TruncateOverflowMySQLTime truncates d when it overflows, and return ErrTruncatedWrongVal.
+func TruncateOverflowMySQLTime(d gotime.Duration) (gotime.Duration, error) {
+	if d > MaxTime {
+		return MaxTime, ErrTruncatedWrongVal.GenWithStackByArgs("time", d.String())
+	} else if d < MinTime {
+		return MinTime, ErrTruncatedWrongVal.GenWithStackByArgs("time", d.String())
+	}
+
+	return d, nil
+}
+
+// NumberToDuration converts number to Duration.
+func NumberToDuration(number int64, fsp int8) (Duration, error) {
+	if number > TimeMaxValue {
+		// Try to parse DATETIME.
+		if number >= 10000000000 { // '2001-00-00 00-00-00'
+			if t, err := ParseDatetimeFromNum(nil, number); err == nil {
+				dur, err1 := t.ConvertToDuration()
+				return dur, errors.Trace(err1)
+			}
+		}
+		dur := Duration{Duration: MaxTime, Fsp: fsp}
+		return dur, ErrOverflow.GenWithStackByArgs("Duration", strconv.FormatInt(number, 10))
+	} else if number < -TimeMaxValue {
+		dur := Duration{Duration: MinTime, Fsp: fsp}
+		return dur, ErrOverflow.GenWithStackByArgs("Duration", strconv.FormatInt(number, 10))
+	}
+	var neg bool
+	if neg = number < 0; neg {
+		number = -number
+	}
+
+	if number/10000 > TimeMaxHour || number%100 >= 60 || (number/100)%100 >= 60 {
+		return ZeroDuration, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.FormatInt(number, 10)))
+	}
+	dur := NewDuration(int(number/10000), int((number/100)%100), int(number%100), 0, fsp)
+	if neg {
+		dur.Duration = -dur.Duration
+	}
+	return dur, nil
+}
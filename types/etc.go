@@ -41,6 +41,12 @@ func IsTypeVarchar(tp byte) bool {
 	return tp == mysql.TypeVarString || tp == mysql.TypeVarchar
 }
 
+// IsTypeTime returns a boolean indicating
+// whether the tp is time type like datetime, date or timestamp.
+func IsTypeTime(tp byte) bool {
+	return tp == mysql.TypeDatetime || tp == mysql.TypeDate || tp == mysql.TypeTimestamp
+}
+
 // IsTypeUnspecified returns a boolean indicating whether the tp is the Unspecified type.
 func IsTypeUnspecified(tp byte) bool {
 	return tp == mysql.TypeUnspecified
@@ -84,17 +90,21 @@ func IsString(tp byte) bool {
 }
 
 var kind2Str = map[byte]string{
-	KindNull:       "null",
-	KindInt64:      "bigint",
-	KindUint64:     "unsigned bigint",
-	KindFloat32:    "float",
-	KindFloat64:    "double",
-	KindString:     "char",
-	KindBytes:      "bytes",
-	KindInterface:  "interface",
-	KindMinNotNull: "min_not_null",
-	KindMaxValue:   "max_value",
-	KindRaw:        "raw",
+	KindNull:          "null",
+	KindInt64:         "bigint",
+	KindUint64:        "unsigned bigint",
+	KindFloat32:       "float",
+	KindFloat64:       "double",
+	KindString:        "char",
+	KindBytes:         "bytes",
+	KindMysqlDecimal:  "decimal",
+	KindMysqlDuration: "time",
+	KindMysqlTime:     "datetime",
+	KindMysqlJSON:     "json",
+	KindInterface:     "interface",
+	KindMinNotNull:    "min_not_null",
+	KindMaxValue:      "max_value",
+	KindRaw:           "raw",
 }
 
 // TypeStr converts tp to a string.
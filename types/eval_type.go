@@ -23,6 +23,16 @@ const (
 	ETInt = ast.ETInt
 	// ETReal represents type REAL in evaluation.
 	ETReal = ast.ETReal
+	// ETDecimal represents type DECIMAL in evaluation.
+	ETDecimal = ast.ETDecimal
+	// ETDatetime represents type DATETIME in evaluation.
+	ETDatetime = ast.ETDatetime
+	// ETTimestamp represents type TIMESTAMP in evaluation.
+	ETTimestamp = ast.ETTimestamp
+	// ETDuration represents type DURATION in evaluation.
+	ETDuration = ast.ETDuration
 	// ETString represents type STRING in evaluation.
 	ETString = ast.ETString
+	// ETJson represents type JSON in evaluation.
+	ETJson = ast.ETJson
 )
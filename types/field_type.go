@@ -105,6 +105,8 @@ func mergeEvalType(lhs, rhs EvalType, lft, rft *FieldType, isLHSUnsigned, isRHSU
 		return ETString
 	} else if lhs == ETReal || rhs == ETReal {
 		return ETReal
+	} else if lhs == ETDecimal || rhs == ETDecimal {
+		return ETDecimal
 	}
 	return ETInt
 }
@@ -192,11 +194,41 @@ func DefaultTypeForValue(value interface{}, tp *FieldType) {
 		tp.Flen = len(s)
 		tp.Decimal = UnspecifiedLength
 		SetBinChsClnFlag(tp)
+	case *MyDecimal:
+		tp.Tp = mysql.TypeNewDecimal
+		tp.Flen = len(x.ToString())
+		tp.Decimal = int(x.digitsFrac)
+		SetBinChsClnFlag(tp)
+	case Time:
+		tp.Tp = x.Type()
+		switch x.Type() {
+		case mysql.TypeDate:
+			tp.Flen = mysql.MaxDateWidth
+			tp.Decimal = int(MinFsp)
+		default:
+			tp.Flen = mysql.MaxDatetimeWidthNoFsp
+			if x.Fsp() > DefaultFsp { // consider point('.') and the fractional part.
+				tp.Flen += int(x.Fsp()) + 1
+			}
+			tp.Decimal = int(x.Fsp())
+		}
+		SetBinChsClnFlag(tp)
+	case Duration:
+		tp.Tp = mysql.TypeDuration
+		tp.Flen = len(x.String())
+		tp.Decimal = int(x.Fsp)
+		SetBinChsClnFlag(tp)
 	case []byte:
 		tp.Tp = mysql.TypeBlob
 		tp.Flen = len(x)
 		tp.Decimal = UnspecifiedLength
 		SetBinChsClnFlag(tp)
+	case BinaryJSON:
+		tp.Tp = mysql.TypeJSON
+		tp.Flen = UnspecifiedLength
+		tp.Decimal = 0
+		tp.Charset = charset.CharsetUTF8MB4
+		tp.Collate = charset.CollationUTF8MB4
 	default:
 		tp.Tp = mysql.TypeUnspecified
 		tp.Flen = UnspecifiedLength
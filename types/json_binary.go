@@ -0,0 +1,754 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/hack"
+)
+
+/*
+   The binary JSON format from MySQL 5.7 is as follows:
+
+   JSON doc ::= type value
+   type ::=
+       0x01 |       // large JSON object
+       0x03 |       // large JSON array
+       0x04 |       // literal (true/false/null)
+       0x09 |       // int64
+       0x0a |       // uint64
+       0x0b |       // double
+       0x0c |       // utf8mb4 string
+       0x0f |       // opaque value of another MySQL type
+
+   value ::=
+       object  |
+       array   |
+       literal |
+       number  |
+       string  |
+       opaque  |
+
+   object ::= element-count size key-entry* value-entry* key* value*
+
+   array ::= element-count size value-entry* value*
+
+   // number of members in object or number of element in array
+   element-count ::= uint32
+
+   // number of bytes in the binary representation of the object or array
+   size ::= uint32
+
+   key-entry ::= key-offset key-length
+
+   key-offset ::= uint32
+
+   key-length ::= uint16    // key length must be less than 64KB
+
+   value-entry ::= type offset-or-inlined-value
+
+   // This field holds either the offset to where the value is stored,
+   // or the value itself if it is small enough to be inlined (that is,
+   // if it is a JSON literal)
+   offset-or-inlined-value ::= uint32
+
+   key ::= utf8mb4-data
+
+   literal ::=
+       0x00 |   // JSON null literal
+       0x01 |   // JSON true literal
+       0x02 |   // JSON false literal
+
+   number ::=  ....    // little-endian format for [u]int64 and double
+
+   string ::= data-length utf8mb4-data
+
+   data-length ::= uint8*    // If the high bit of a byte is 1, the length
+                             // field is continued in the next byte,
+                             // otherwise it is the last byte of the length
+                             // field. So we need 1 byte to represent
+                             // lengths up to 127, 2 bytes to represent
+                             // lengths up to 16383, and so on...
+
+   opaque ::= field-type data-length byte*
+
+   // The MySQL field type of the value, only DECIMAL is supported, whose
+   // data is the precision, the frac and the binary form of the decimal.
+   field-type ::= uint8
+
+   Object keys are sorted by compareJSONKeys so that a key can be found by
+   binary search.
+*/
+
+var jsonEndian = binary.LittleEndian
+
+const (
+	jsonHeaderSize   = 8 // element size + data size.
+	jsonDataSizeOff  = 4
+	jsonKeyEntrySize = 6 // keyOff + keyLen
+	jsonKeyLenOff    = 4
+	jsonValTypeSize  = 1
+	jsonValEntrySize = 5
+)
+
+// BinaryJSON represents a binary encoded JSON object.
+// It can be randomly accessed without deserialization.
+type BinaryJSON struct {
+	TypeCode JSONTypeCode
+	Value    []byte
+}
+
+// String implements fmt.Stringer interface.
+func (bj BinaryJSON) String() string {
+	out, err := bj.MarshalJSON()
+	terror.Log(err)
+	return string(out)
+}
+
+// Copy makes a copy of the BinaryJSON.
+func (bj BinaryJSON) Copy() BinaryJSON {
+	buf := make([]byte, len(bj.Value))
+	copy(buf, bj.Value)
+	return BinaryJSON{TypeCode: bj.TypeCode, Value: buf}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (bj BinaryJSON) MarshalJSON() ([]byte, error) {
+	buf := make([]byte, 0, len(bj.Value)*3/2)
+	return bj.marshalTo(buf)
+}
+
+// GetInt64 gets the int64 value.
+func (bj BinaryJSON) GetInt64() int64 {
+	return int64(jsonEndian.Uint64(bj.Value))
+}
+
+// GetUint64 gets the uint64 value.
+func (bj BinaryJSON) GetUint64() uint64 {
+	return jsonEndian.Uint64(bj.Value)
+}
+
+// GetFloat64 gets the float64 value.
+func (bj BinaryJSON) GetFloat64() float64 {
+	return math.Float64frombits(bj.GetUint64())
+}
+
+// GetString gets the string value.
+func (bj BinaryJSON) GetString() []byte {
+	strLen, lenLen := binary.Uvarint(bj.Value)
+	return bj.Value[lenLen : lenLen+int(strLen)]
+}
+
+// GetOpaque gets the field type and the data of the opaque value.
+func (bj BinaryJSON) GetOpaque() (byte, []byte) {
+	dataLen, lenLen := binary.Uvarint(bj.Value[1:])
+	return bj.Value[0], bj.Value[1+lenLen : 1+lenLen+int(dataLen)]
+}
+
+// GetDecimal gets the decimal value, it returns nil if the value isn't a decimal.
+func (bj BinaryJSON) GetDecimal() *MyDecimal {
+	if bj.TypeCode != JSONTypeCodeOpaque {
+		return nil
+	}
+	tp, data := bj.GetOpaque()
+	if tp != mysql.TypeNewDecimal || len(data) < 2 {
+		return nil
+	}
+	d := new(MyDecimal)
+	if _, err := d.FromBin(data[2:], int(data[0]), int(data[1])); err != nil {
+		terror.Log(errors.Trace(err))
+		return nil
+	}
+	return d
+}
+
+// GetKeys gets the keys of the object as a JSON array.
+func (bj BinaryJSON) GetKeys() BinaryJSON {
+	count := bj.GetElemCount()
+	ret := make([]BinaryJSON, 0, count)
+	for i := 0; i < count; i++ {
+		ret = append(ret, CreateBinaryJSON(string(bj.objectGetKey(i))))
+	}
+	return buildBinaryJSONArray(ret)
+}
+
+// GetElemCount gets the count of Object or Array.
+func (bj BinaryJSON) GetElemCount() int {
+	return int(jsonEndian.Uint32(bj.Value))
+}
+
+func (bj BinaryJSON) arrayGetElem(idx int) BinaryJSON {
+	return bj.valEntryGet(jsonHeaderSize + idx*jsonValEntrySize)
+}
+
+func (bj BinaryJSON) objectGetKey(i int) []byte {
+	keyOff := int(jsonEndian.Uint32(bj.Value[jsonHeaderSize+i*jsonKeyEntrySize:]))
+	keyLen := int(jsonEndian.Uint16(bj.Value[jsonHeaderSize+i*jsonKeyEntrySize+jsonKeyLenOff:]))
+	return bj.Value[keyOff : keyOff+keyLen]
+}
+
+func (bj BinaryJSON) objectGetVal(i int) BinaryJSON {
+	elemCount := bj.GetElemCount()
+	return bj.valEntryGet(jsonHeaderSize + elemCount*jsonKeyEntrySize + i*jsonValEntrySize)
+}
+
+func (bj BinaryJSON) valEntryGet(valEntryOff int) BinaryJSON {
+	tpCode := bj.Value[valEntryOff]
+	valOff := int(jsonEndian.Uint32(bj.Value[valEntryOff+jsonValTypeSize:]))
+	switch tpCode {
+	case JSONTypeCodeLiteral:
+		return BinaryJSON{TypeCode: JSONTypeCodeLiteral, Value: bj.Value[valEntryOff+jsonValTypeSize : valEntryOff+jsonValTypeSize+1]}
+	case JSONTypeCodeUint64, JSONTypeCodeInt64, JSONTypeCodeFloat64:
+		return BinaryJSON{TypeCode: tpCode, Value: bj.Value[valOff : valOff+8]}
+	case JSONTypeCodeString:
+		strLen, lenLen := binary.Uvarint(bj.Value[valOff:])
+		totalLen := lenLen + int(strLen)
+		return BinaryJSON{TypeCode: tpCode, Value: bj.Value[valOff : valOff+totalLen]}
+	case JSONTypeCodeOpaque:
+		dataLen, lenLen := binary.Uvarint(bj.Value[valOff+1:])
+		totalLen := 1 + lenLen + int(dataLen)
+		return BinaryJSON{TypeCode: tpCode, Value: bj.Value[valOff : valOff+totalLen]}
+	}
+	dataSize := int(jsonEndian.Uint32(bj.Value[valOff+jsonDataSizeOff:]))
+	return BinaryJSON{TypeCode: tpCode, Value: bj.Value[valOff : valOff+dataSize]}
+}
+
+// objectSearchKey searches the key in the object by binary search, keys are
+// stored in the order defined by compareJSONKeys.
+func (bj BinaryJSON) objectSearchKey(key []byte) (BinaryJSON, bool) {
+	elemCount := bj.GetElemCount()
+	idx := sort.Search(elemCount, func(i int) bool {
+		return compareJSONKeys(bj.objectGetKey(i), key) >= 0
+	})
+	if idx < elemCount && bytes.Equal(bj.objectGetKey(idx), key) {
+		return bj.objectGetVal(idx), true
+	}
+	return BinaryJSON{}, false
+}
+
+// compareJSONKeys orders object keys the way MySQL does: shorter keys come
+// first, keys of the same length are compared byte by byte.
+func compareJSONKeys(a, b []byte) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return bytes.Compare(a, b)
+}
+
+func (bj BinaryJSON) marshalTo(buf []byte) ([]byte, error) {
+	switch bj.TypeCode {
+	case JSONTypeCodeString:
+		return marshalJSONStringTo(buf, bj.GetString()), nil
+	case JSONTypeCodeLiteral:
+		return marshalJSONLiteralTo(buf, bj.Value[0]), nil
+	case JSONTypeCodeInt64:
+		return strconv.AppendInt(buf, bj.GetInt64(), 10), nil
+	case JSONTypeCodeUint64:
+		return strconv.AppendUint(buf, bj.GetUint64(), 10), nil
+	case JSONTypeCodeFloat64:
+		return marshalJSONFloat64To(buf, bj.GetFloat64())
+	case JSONTypeCodeOpaque:
+		return bj.marshalOpaqueTo(buf), nil
+	case JSONTypeCodeArray:
+		return bj.marshalArrayTo(buf)
+	case JSONTypeCodeObject:
+		return bj.marshalObjTo(buf)
+	}
+	return buf, nil
+}
+
+func (bj BinaryJSON) marshalArrayTo(buf []byte) ([]byte, error) {
+	elemCount := bj.GetElemCount()
+	buf = append(buf, '[')
+	for i := 0; i < elemCount; i++ {
+		if i != 0 {
+			buf = append(buf, ", "...)
+		}
+		var err error
+		buf, err = bj.arrayGetElem(i).marshalTo(buf)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return append(buf, ']'), nil
+}
+
+func (bj BinaryJSON) marshalObjTo(buf []byte) ([]byte, error) {
+	elemCount := bj.GetElemCount()
+	buf = append(buf, '{')
+	for i := 0; i < elemCount; i++ {
+		if i != 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = marshalJSONStringTo(buf, bj.objectGetKey(i))
+		buf = append(buf, ": "...)
+		var err error
+		buf, err = bj.objectGetVal(i).marshalTo(buf)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// marshalOpaqueTo prints a decimal as a number, the other values are printed
+// as base64 strings the way MySQL does.
+func (bj BinaryJSON) marshalOpaqueTo(buf []byte) []byte {
+	if d := bj.GetDecimal(); d != nil {
+		return append(buf, d.ToString()...)
+	}
+	tp, data := bj.GetOpaque()
+	str := fmt.Sprintf("base64:type%d:%s", tp, base64.StdEncoding.EncodeToString(data))
+	return marshalJSONStringTo(buf, hack.Slice(str))
+}
+
+func marshalJSONFloat64To(buf []byte, f float64) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return buf, errors.Errorf("json: unsupported value: %s", strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	// Convert as if by ES6 number to string conversion, as encoding/json does.
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	start := len(buf)
+	buf = strconv.AppendFloat(buf, f, format, -1, 64)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(buf)
+		if n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	} else if bytes.IndexByte(buf[start:], '.') < 0 {
+		// MySQL always prints a fractional part for doubles.
+		buf = append(buf, ".0"...)
+	}
+	return buf, nil
+}
+
+func marshalJSONLiteralTo(buf []byte, litType byte) []byte {
+	switch litType {
+	case JSONLiteralFalse:
+		return append(buf, "false"...)
+	case JSONLiteralTrue:
+		return append(buf, "true"...)
+	}
+	return append(buf, "null"...)
+}
+
+const jsonHexChars = "0123456789abcdef"
+
+// marshalJSONStringTo quotes the string the way MySQL does, which differs
+// from encoding/json in that HTML characters are not escaped.
+func marshalJSONStringTo(buf, s []byte) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 0x20 && c != '"' && c != '\\' {
+			continue
+		}
+		buf = append(buf, s[start:i]...)
+		switch c {
+		case '"', '\\':
+			buf = append(buf, '\\', c)
+		case '\b':
+			buf = append(buf, '\\', 'b')
+		case '\f':
+			buf = append(buf, '\\', 'f')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		case '\t':
+			buf = append(buf, '\\', 't')
+		default:
+			buf = append(buf, '\\', 'u', '0', '0', jsonHexChars[c>>4], jsonHexChars[c&0xF])
+		}
+		start = i + 1
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
+
+// ParseBinaryJSONFromString parses a json from string.
+func ParseBinaryJSONFromString(s string) (bj BinaryJSON, err error) {
+	if len(s) == 0 {
+		err = ErrInvalidJSONText.GenWithStackByArgs("The document is empty")
+		return
+	}
+	data := hack.Slice(s)
+	if !json.Valid(data) {
+		err = ErrInvalidJSONText.GenWithStackByArgs("The document is not a valid JSON text")
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var in interface{}
+	if err = decoder.Decode(&in); err != nil {
+		err = ErrInvalidJSONText.GenWithStackByArgs(err)
+		return
+	}
+	bj.TypeCode, bj.Value, err = appendBinaryJSON(nil, in)
+	return
+}
+
+// CreateBinaryJSON creates a BinaryJSON from interface.
+func CreateBinaryJSON(in interface{}) BinaryJSON {
+	typeCode, buf, err := appendBinaryJSON(nil, in)
+	if err != nil {
+		panic(err)
+	}
+	return BinaryJSON{TypeCode: typeCode, Value: buf}
+}
+
+func appendBinaryJSON(buf []byte, in interface{}) (JSONTypeCode, []byte, error) {
+	var typeCode byte
+	var err error
+	switch x := in.(type) {
+	case nil:
+		typeCode = JSONTypeCodeLiteral
+		buf = append(buf, JSONLiteralNil)
+	case bool:
+		typeCode = JSONTypeCodeLiteral
+		if x {
+			buf = append(buf, JSONLiteralTrue)
+		} else {
+			buf = append(buf, JSONLiteralFalse)
+		}
+	case int64:
+		typeCode = JSONTypeCodeInt64
+		buf = appendBinaryJSONUint64(buf, uint64(x))
+	case uint64:
+		typeCode = JSONTypeCodeUint64
+		buf = appendBinaryJSONUint64(buf, x)
+	case float64:
+		typeCode = JSONTypeCodeFloat64
+		buf = appendBinaryJSONUint64(buf, math.Float64bits(x))
+	case json.Number:
+		typeCode, buf, err = appendBinaryJSONNumber(buf, x)
+		if err != nil {
+			return typeCode, nil, errors.Trace(err)
+		}
+	case string:
+		typeCode = JSONTypeCodeString
+		buf = appendBinaryJSONString(buf, x)
+	case *MyDecimal:
+		typeCode = JSONTypeCodeOpaque
+		buf, err = appendBinaryJSONDecimal(buf, x)
+		if err != nil {
+			return typeCode, nil, errors.Trace(err)
+		}
+	case BinaryJSON:
+		typeCode = x.TypeCode
+		buf = append(buf, x.Value...)
+	case []interface{}:
+		typeCode = JSONTypeCodeArray
+		buf, err = appendBinaryJSONArray(buf, x)
+		if err != nil {
+			return typeCode, nil, errors.Trace(err)
+		}
+	case map[string]interface{}:
+		typeCode = JSONTypeCodeObject
+		buf, err = appendBinaryJSONObject(buf, x)
+		if err != nil {
+			return typeCode, nil, errors.Trace(err)
+		}
+	default:
+		msg := fmt.Sprintf("unsupported type %T for JSON", in)
+		err = errors.New(msg)
+	}
+	return typeCode, buf, err
+}
+
+func appendBinaryJSONNumber(buf []byte, x json.Number) (JSONTypeCode, []byte, error) {
+	if i64, err := x.Int64(); err == nil {
+		return JSONTypeCodeInt64, appendBinaryJSONUint64(buf, uint64(i64)), nil
+	}
+	if u64, err := strconv.ParseUint(string(x), 10, 64); err == nil {
+		return JSONTypeCodeUint64, appendBinaryJSONUint64(buf, u64), nil
+	}
+	f64, err := x.Float64()
+	if err != nil {
+		return JSONTypeCodeFloat64, nil, ErrInvalidJSONText.GenWithStackByArgs(err)
+	}
+	return JSONTypeCodeFloat64, appendBinaryJSONUint64(buf, math.Float64bits(f64)), nil
+}
+
+func appendBinaryJSONUint64(buf []byte, v uint64) []byte {
+	off := len(buf)
+	buf = appendZero(buf, 8)
+	jsonEndian.PutUint64(buf[off:], v)
+	return buf
+}
+
+func appendBinaryJSONString(buf []byte, v string) []byte {
+	begin := len(buf)
+	buf = appendZero(buf, binary.MaxVarintLen64)
+	lenLen := binary.PutUvarint(buf[begin:], uint64(len(v)))
+	buf = buf[:len(buf)-binary.MaxVarintLen64+lenLen]
+	return append(buf, v...)
+}
+
+func appendBinaryJSONDecimal(buf []byte, d *MyDecimal) ([]byte, error) {
+	precision, frac := d.PrecisionAndFrac()
+	bin, err := d.ToBin(precision, frac)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data := make([]byte, 0, 2+len(bin))
+	data = append(data, byte(precision), byte(frac))
+	data = append(data, bin...)
+	buf = append(buf, mysql.TypeNewDecimal)
+	return appendBinaryJSONString(buf, string(hack.String(data))), nil
+}
+
+func appendZero(buf []byte, length int) []byte {
+	var tmp [8]byte
+	rem := length % 8
+	loop := length / 8
+	for i := 0; i < loop; i++ {
+		buf = append(buf, tmp[:]...)
+	}
+	for i := 0; i < rem; i++ {
+		buf = append(buf, 0)
+	}
+	return buf
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	var tmp [4]byte
+	jsonEndian.PutUint32(tmp[:], v)
+	return append(buf, tmp[:]...)
+}
+
+func appendBinaryJSONArray(buf []byte, array []interface{}) ([]byte, error) {
+	elems := make([]BinaryJSON, 0, len(array))
+	for _, val := range array {
+		typeCode, value, err := appendBinaryJSON(nil, val)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		elems = append(elems, BinaryJSON{TypeCode: typeCode, Value: value})
+	}
+	return append(buf, buildBinaryJSONArray(elems).Value...), nil
+}
+
+func appendBinaryJSONObject(buf []byte, x map[string]interface{}) ([]byte, error) {
+	keys := make([][]byte, 0, len(x))
+	elems := make([]BinaryJSON, 0, len(x))
+	for key, val := range x {
+		typeCode, value, err := appendBinaryJSON(nil, val)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		keys = append(keys, []byte(key))
+		elems = append(elems, BinaryJSON{TypeCode: typeCode, Value: value})
+	}
+	obj, err := buildBinaryJSONObject(keys, elems)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(buf, obj.Value...), nil
+}
+
+// appendBinaryJSONValElem appends the value entry of elem at entryOff, the
+// value itself is appended to the end of buf unless it can be inlined.
+func appendBinaryJSONValElem(buf []byte, entryOff int, elem BinaryJSON) []byte {
+	buf[entryOff] = elem.TypeCode
+	if elem.TypeCode == JSONTypeCodeLiteral {
+		buf[entryOff+jsonValTypeSize] = elem.Value[0]
+		return buf
+	}
+	jsonEndian.PutUint32(buf[entryOff+jsonValTypeSize:], uint32(len(buf)))
+	return append(buf, elem.Value...)
+}
+
+func buildBinaryJSONArray(elems []BinaryJSON) BinaryJSON {
+	totalSize := jsonHeaderSize + len(elems)*jsonValEntrySize
+	for _, elem := range elems {
+		if elem.TypeCode != JSONTypeCodeLiteral {
+			totalSize += len(elem.Value)
+		}
+	}
+	buf := make([]byte, jsonHeaderSize+len(elems)*jsonValEntrySize, totalSize)
+	jsonEndian.PutUint32(buf, uint32(len(elems)))
+	jsonEndian.PutUint32(buf[jsonDataSizeOff:], uint32(totalSize))
+	for i, elem := range elems {
+		buf = appendBinaryJSONValElem(buf, jsonHeaderSize+i*jsonValEntrySize, elem)
+	}
+	return BinaryJSON{TypeCode: JSONTypeCodeArray, Value: buf}
+}
+
+type jsonObjectField struct {
+	key []byte
+	val BinaryJSON
+}
+
+// buildBinaryJSONObject builds an object from keys and values, when a key
+// is duplicated the last value wins.
+func buildBinaryJSONObject(keys [][]byte, elems []BinaryJSON) (BinaryJSON, error) {
+	fields := make([]jsonObjectField, 0, len(keys))
+	for i, key := range keys {
+		if len(key) > math.MaxUint16 {
+			return BinaryJSON{}, ErrInvalidJSONData.GenWithStackByArgs("JSON_OBJECT", "key is too long")
+		}
+		fields = append(fields, jsonObjectField{key: key, val: elems[i]})
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return compareJSONKeys(fields[i].key, fields[j].key) < 0
+	})
+	// Remove duplicated keys, keep the last one.
+	uniq := fields[:0]
+	for _, field := range fields {
+		if len(uniq) > 0 && bytes.Equal(uniq[len(uniq)-1].key, field.key) {
+			uniq[len(uniq)-1] = field
+			continue
+		}
+		uniq = append(uniq, field)
+	}
+	fields = uniq
+
+	totalSize := jsonHeaderSize + len(fields)*(jsonKeyEntrySize+jsonValEntrySize)
+	for _, field := range fields {
+		totalSize += len(field.key)
+		if field.val.TypeCode != JSONTypeCodeLiteral {
+			totalSize += len(field.val.Value)
+		}
+	}
+	buf := make([]byte, jsonHeaderSize+len(fields)*(jsonKeyEntrySize+jsonValEntrySize), totalSize)
+	jsonEndian.PutUint32(buf, uint32(len(fields)))
+	jsonEndian.PutUint32(buf[jsonDataSizeOff:], uint32(totalSize))
+	for i, field := range fields {
+		keyEntryOff := jsonHeaderSize + i*jsonKeyEntrySize
+		jsonEndian.PutUint32(buf[keyEntryOff:], uint32(len(buf)))
+		jsonEndian.PutUint16(buf[keyEntryOff+jsonKeyLenOff:], uint16(len(field.key)))
+		buf = append(buf, field.key...)
+	}
+	valEntryStart := jsonHeaderSize + len(fields)*jsonKeyEntrySize
+	for i, field := range fields {
+		buf = appendBinaryJSONValElem(buf, valEntryStart+i*jsonValEntrySize, field.val)
+	}
+	return BinaryJSON{TypeCode: JSONTypeCodeObject, Value: buf}, nil
+}
+
+// PeekBytesAsJSON trys to peek some bytes from b, until
+// we can deserialize a JSON from those bytes.
+func PeekBytesAsJSON(b []byte) (n int, err error) {
+	if len(b) <= 0 {
+		err = errors.New("Cant peek from empty bytes")
+		return
+	}
+	switch c := b[0]; c {
+	case JSONTypeCodeObject, JSONTypeCodeArray:
+		if len(b) >= 1+jsonHeaderSize {
+			size := jsonEndian.Uint32(b[1+jsonDataSizeOff:])
+			n = 1 + int(size)
+			return
+		}
+	case JSONTypeCodeString:
+		strLen, lenLen := binary.Uvarint(b[1:])
+		if lenLen > 0 {
+			n = 1 + int(strLen) + lenLen
+			return
+		}
+	case JSONTypeCodeInt64, JSONTypeCodeUint64, JSONTypeCodeFloat64:
+		n = 1 + 8
+		return
+	case JSONTypeCodeOpaque:
+		if len(b) >= 2 {
+			dataLen, lenLen := binary.Uvarint(b[2:])
+			if lenLen > 0 {
+				n = 2 + lenLen + int(dataLen)
+				return
+			}
+		}
+	case JSONTypeCodeLiteral:
+		n = 1 + 1
+		return
+	}
+	err = errors.New("Invalid JSON bytes")
+	return
+}
+
+// UnquoteJSONString unescapes a quoted JSON string, the surrounding quotes
+// must already be stripped.
+func UnquoteJSONString(s string) (string, error) {
+	ret := new(bytes.Buffer)
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			ret.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i == len(s) {
+			return "", errors.New("Missing a closing quotation mark in string")
+		}
+		switch s[i] {
+		case '"':
+			ret.WriteByte('"')
+		case 'b':
+			ret.WriteByte('\b')
+		case 'f':
+			ret.WriteByte('\f')
+		case 'n':
+			ret.WriteByte('\n')
+		case 'r':
+			ret.WriteByte('\r')
+		case 't':
+			ret.WriteByte('\t')
+		case '\\':
+			ret.WriteByte('\\')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", errors.New("Invalid unicode")
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", errors.Trace(err)
+			}
+			var tmp [utf8.UTFMax]byte
+			n := utf8.EncodeRune(tmp[:], rune(r))
+			ret.Write(tmp[:n])
+			i += 4
+		default:
+			// For all other escape sequences, backslash is ignored.
+			ret.WriteByte(s[i])
+		}
+	}
+	return ret.String(), nil
+}
+
+// Unquote is for JSON_UNQUOTE, it returns the string content of a JSON
+// string, or the JSON text for other types.
+func (bj BinaryJSON) Unquote() string {
+	if bj.TypeCode == JSONTypeCodeString {
+		return string(hack.String(bj.GetString()))
+	}
+	return bj.String()
+}
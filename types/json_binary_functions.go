@@ -0,0 +1,502 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/hack"
+)
+
+// Type returns type of BinaryJSON as string.
+func (bj BinaryJSON) Type() string {
+	switch bj.TypeCode {
+	case JSONTypeCodeObject:
+		return "OBJECT"
+	case JSONTypeCodeArray:
+		return "ARRAY"
+	case JSONTypeCodeLiteral:
+		switch bj.Value[0] {
+		case JSONLiteralNil:
+			return "NULL"
+		default:
+			return "BOOLEAN"
+		}
+	case JSONTypeCodeInt64:
+		return "INTEGER"
+	case JSONTypeCodeUint64:
+		return "UNSIGNED INTEGER"
+	case JSONTypeCodeFloat64:
+		return "DOUBLE"
+	case JSONTypeCodeString:
+		return "STRING"
+	case JSONTypeCodeOpaque:
+		if bj.GetDecimal() != nil {
+			return "DECIMAL"
+		}
+		return "OPAQUE"
+	default:
+		msg := "unknown type code: " + string(bj.TypeCode)
+		panic(msg)
+	}
+}
+
+// Extract receives several path expressions as arguments, matches them in bj,
+// and returns:
+//
+//	ret: target JSON matched any path expressions. maybe autowrapped as an array.
+//	found: true if any path expressions matched.
+func (bj BinaryJSON) Extract(pathExprList []JSONPathExpression) (ret BinaryJSON, found bool) {
+	buf := make([]BinaryJSON, 0, 1)
+	for _, pathExpr := range pathExprList {
+		buf = bj.extractTo(buf, pathExpr)
+	}
+	if len(buf) == 0 {
+		found = false
+	} else if len(pathExprList) == 1 && len(buf) == 1 && !pathExprList[0].ContainsAnyAsterisk() {
+		// A single path without asterisks returns the matched value itself.
+		found = true
+		ret = buf[0]
+	} else {
+		found = true
+		ret = buildBinaryJSONArray(buf)
+	}
+	return
+}
+
+func (bj BinaryJSON) extractTo(buf []BinaryJSON, pathExpr JSONPathExpression) []BinaryJSON {
+	if len(pathExpr.legs) == 0 {
+		return append(buf, bj)
+	}
+	currentLeg, subPathExpr := pathExpr.popOneLeg()
+	if currentLeg.typ == jsonPathLegIndex {
+		if bj.TypeCode != JSONTypeCodeArray {
+			// A scalar or an object is treated as an array of one element.
+			if currentLeg.arrayIndex == 0 || currentLeg.arrayIndex == jsonArrayIndexAsterisk {
+				buf = bj.extractTo(buf, subPathExpr)
+			}
+			return buf
+		}
+		elemCount := bj.GetElemCount()
+		if currentLeg.arrayIndex == jsonArrayIndexAsterisk {
+			for i := 0; i < elemCount; i++ {
+				buf = bj.arrayGetElem(i).extractTo(buf, subPathExpr)
+			}
+		} else if currentLeg.arrayIndex < elemCount {
+			buf = bj.arrayGetElem(currentLeg.arrayIndex).extractTo(buf, subPathExpr)
+		}
+	} else if currentLeg.typ == jsonPathLegKey && bj.TypeCode == JSONTypeCodeObject {
+		elemCount := bj.GetElemCount()
+		if currentLeg.dotKey == "*" {
+			for i := 0; i < elemCount; i++ {
+				buf = bj.objectGetVal(i).extractTo(buf, subPathExpr)
+			}
+		} else {
+			child, ok := bj.objectSearchKey(hack.Slice(currentLeg.dotKey))
+			if ok {
+				buf = child.extractTo(buf, subPathExpr)
+			}
+		}
+	} else if currentLeg.typ == jsonPathLegDoubleAsterisk {
+		buf = bj.extractTo(buf, subPathExpr)
+		if bj.TypeCode == JSONTypeCodeArray {
+			elemCount := bj.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				buf = bj.arrayGetElem(i).extractTo(buf, pathExpr)
+			}
+		} else if bj.TypeCode == JSONTypeCodeObject {
+			elemCount := bj.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				buf = bj.objectGetVal(i).extractTo(buf, pathExpr)
+			}
+		}
+	}
+	return buf
+}
+
+func (bj BinaryJSON) arrayElems() []BinaryJSON {
+	elemCount := bj.GetElemCount()
+	elems := make([]BinaryJSON, 0, elemCount+1)
+	for i := 0; i < elemCount; i++ {
+		elems = append(elems, bj.arrayGetElem(i))
+	}
+	return elems
+}
+
+func (bj BinaryJSON) objectFields() ([][]byte, []BinaryJSON) {
+	elemCount := bj.GetElemCount()
+	keys := make([][]byte, 0, elemCount+1)
+	elems := make([]BinaryJSON, 0, elemCount+1)
+	for i := 0; i < elemCount; i++ {
+		keys = append(keys, bj.objectGetKey(i))
+		elems = append(elems, bj.objectGetVal(i))
+	}
+	return keys, elems
+}
+
+// Modify modifies a JSON object by insert, replace or set.
+// All path expressions cannot contain * or ** wildcard.
+// If any error occurs, the input won't be changed.
+func (bj BinaryJSON) Modify(pathExprList []JSONPathExpression, values []BinaryJSON, mt JSONModifyType) (retj BinaryJSON, err error) {
+	if len(pathExprList) != len(values) {
+		return retj, errors.New("Incorrect parameter count")
+	}
+	for _, pathExpr := range pathExprList {
+		if pathExpr.ContainsAnyAsterisk() {
+			return retj, ErrInvalidJSONPathWildcard.GenWithStackByArgs()
+		}
+	}
+	for i := 0; i < len(pathExprList); i++ {
+		bj, err = bj.modify(pathExprList[i], values[i], mt)
+		if err != nil {
+			return retj, errors.Trace(err)
+		}
+	}
+	return bj, nil
+}
+
+func (bj BinaryJSON) modify(pathExpr JSONPathExpression, value BinaryJSON, mt JSONModifyType) (BinaryJSON, error) {
+	if len(pathExpr.legs) == 0 {
+		if mt&JSONModifyReplace != 0 {
+			return value, nil
+		}
+		return bj, nil
+	}
+	currentLeg, subPathExpr := pathExpr.popOneLeg()
+	switch currentLeg.typ {
+	case jsonPathLegIndex:
+		if bj.TypeCode != JSONTypeCodeArray {
+			if currentLeg.arrayIndex == 0 {
+				// A scalar or an object is treated as an array of one element.
+				return bj.modify(subPathExpr, value, mt)
+			}
+			if len(subPathExpr.legs) == 0 && mt&JSONModifyInsert != 0 {
+				return buildBinaryJSONArray([]BinaryJSON{bj, value}), nil
+			}
+			return bj, nil
+		}
+		elems := bj.arrayElems()
+		if currentLeg.arrayIndex < len(elems) {
+			child, err := elems[currentLeg.arrayIndex].modify(subPathExpr, value, mt)
+			if err != nil {
+				return bj, errors.Trace(err)
+			}
+			elems[currentLeg.arrayIndex] = child
+			return buildBinaryJSONArray(elems), nil
+		}
+		if len(subPathExpr.legs) == 0 && mt&JSONModifyInsert != 0 {
+			elems = append(elems, value)
+			return buildBinaryJSONArray(elems), nil
+		}
+	case jsonPathLegKey:
+		if bj.TypeCode != JSONTypeCodeObject {
+			return bj, nil
+		}
+		key := hack.Slice(currentLeg.dotKey)
+		keys, elems := bj.objectFields()
+		for i := range keys {
+			if bytes.Equal(keys[i], key) {
+				child, err := elems[i].modify(subPathExpr, value, mt)
+				if err != nil {
+					return bj, errors.Trace(err)
+				}
+				elems[i] = child
+				return buildBinaryJSONObject(keys, elems)
+			}
+		}
+		if len(subPathExpr.legs) == 0 && mt&JSONModifyInsert != 0 {
+			keys = append(keys, key)
+			elems = append(elems, value)
+			return buildBinaryJSONObject(keys, elems)
+		}
+	}
+	return bj, nil
+}
+
+// Remove removes the elements indicated by pathExprList from JSON.
+func (bj BinaryJSON) Remove(pathExprList []JSONPathExpression) (BinaryJSON, error) {
+	for _, pathExpr := range pathExprList {
+		if pathExpr.IsRoot() {
+			return bj, ErrInvalidJSONPath.GenWithStackByArgs("'$'")
+		}
+		if pathExpr.ContainsAnyAsterisk() {
+			return bj, ErrInvalidJSONPathWildcard.GenWithStackByArgs()
+		}
+		var err error
+		bj, err = bj.remove(pathExpr)
+		if err != nil {
+			return bj, errors.Trace(err)
+		}
+	}
+	return bj, nil
+}
+
+func (bj BinaryJSON) remove(pathExpr JSONPathExpression) (BinaryJSON, error) {
+	currentLeg, subPathExpr := pathExpr.popOneLeg()
+	switch currentLeg.typ {
+	case jsonPathLegIndex:
+		if bj.TypeCode != JSONTypeCodeArray {
+			return bj, nil
+		}
+		elems := bj.arrayElems()
+		if currentLeg.arrayIndex >= len(elems) {
+			return bj, nil
+		}
+		if len(subPathExpr.legs) == 0 {
+			elems = append(elems[:currentLeg.arrayIndex], elems[currentLeg.arrayIndex+1:]...)
+			return buildBinaryJSONArray(elems), nil
+		}
+		child, err := elems[currentLeg.arrayIndex].remove(subPathExpr)
+		if err != nil {
+			return bj, errors.Trace(err)
+		}
+		elems[currentLeg.arrayIndex] = child
+		return buildBinaryJSONArray(elems), nil
+	case jsonPathLegKey:
+		if bj.TypeCode != JSONTypeCodeObject {
+			return bj, nil
+		}
+		key := hack.Slice(currentLeg.dotKey)
+		keys, elems := bj.objectFields()
+		for i := range keys {
+			if !bytes.Equal(keys[i], key) {
+				continue
+			}
+			if len(subPathExpr.legs) == 0 {
+				keys = append(keys[:i], keys[i+1:]...)
+				elems = append(elems[:i], elems[i+1:]...)
+				return buildBinaryJSONObject(keys, elems)
+			}
+			child, err := elems[i].remove(subPathExpr)
+			if err != nil {
+				return bj, errors.Trace(err)
+			}
+			elems[i] = child
+			return buildBinaryJSONObject(keys, elems)
+		}
+	}
+	return bj, nil
+}
+
+// ContainsBinaryJSON checks whether JSON document target is contained by obj,
+// see https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-contains.
+func ContainsBinaryJSON(obj, target BinaryJSON) bool {
+	switch obj.TypeCode {
+	case JSONTypeCodeObject:
+		if target.TypeCode == JSONTypeCodeObject {
+			elemCount := target.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				key := target.objectGetKey(i)
+				val := target.objectGetVal(i)
+				if exp, exists := obj.objectSearchKey(key); !exists || !ContainsBinaryJSON(exp, val) {
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	case JSONTypeCodeArray:
+		if target.TypeCode == JSONTypeCodeArray {
+			elemCount := target.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				if !ContainsBinaryJSON(obj, target.arrayGetElem(i)) {
+					return false
+				}
+			}
+			return true
+		}
+		elemCount := obj.GetElemCount()
+		for i := 0; i < elemCount; i++ {
+			if ContainsBinaryJSON(obj.arrayGetElem(i), target) {
+				return true
+			}
+		}
+		return false
+	default:
+		return CompareBinaryJSON(obj, target) == 0
+	}
+}
+
+// CompareBinaryJSON compares two binary JSON values following the MySQL rules:
+// values of different types are ordered by their type precedences, numbers
+// are compared by their numeric values, arrays are compared element by
+// element and objects only support equality. The result is -1, 0 or 1.
+func CompareBinaryJSON(left, right BinaryJSON) int {
+	precedence1 := jsonTypePrecedences[left.Type()]
+	precedence2 := jsonTypePrecedences[right.Type()]
+	if precedence1 != precedence2 {
+		return CompareInt64(int64(precedence1), int64(precedence2))
+	}
+	var cmp int
+	switch left.TypeCode {
+	case JSONTypeCodeLiteral:
+		// false is less than true, null is only equal to null.
+		cmp = CompareInt64(int64(right.Value[0]), int64(left.Value[0]))
+	case JSONTypeCodeInt64, JSONTypeCodeUint64, JSONTypeCodeFloat64:
+		cmp = compareJSONNumber(left, right)
+	case JSONTypeCodeString:
+		cmp = bytes.Compare(left.GetString(), right.GetString())
+	case JSONTypeCodeOpaque:
+		if precedence1 == jsonTypePrecedences["DECIMAL"] {
+			cmp = compareJSONNumber(left, right)
+			break
+		}
+		cmp = bytes.Compare(left.Value, right.Value)
+	case JSONTypeCodeArray:
+		leftCount := left.GetElemCount()
+		rightCount := right.GetElemCount()
+		for i := 0; i < leftCount && i < rightCount; i++ {
+			cmp = CompareBinaryJSON(left.arrayGetElem(i), right.arrayGetElem(i))
+			if cmp != 0 {
+				return cmp
+			}
+		}
+		cmp = CompareInt64(int64(leftCount), int64(rightCount))
+	case JSONTypeCodeObject:
+		// Only equality is meaningful on two JSON objects, but a stable order
+		// is still needed for sorting, so compare the sorted members one by one.
+		leftCount := left.GetElemCount()
+		rightCount := right.GetElemCount()
+		if cmp = CompareInt64(int64(leftCount), int64(rightCount)); cmp != 0 {
+			return cmp
+		}
+		for i := 0; i < leftCount; i++ {
+			if cmp = compareJSONKeys(left.objectGetKey(i), right.objectGetKey(i)); cmp != 0 {
+				return CompareInt64(int64(cmp), 0)
+			}
+			if cmp = CompareBinaryJSON(left.objectGetVal(i), right.objectGetVal(i)); cmp != 0 {
+				return cmp
+			}
+		}
+	}
+	return cmp
+}
+
+func compareJSONNumber(left, right BinaryJSON) int {
+	switch {
+	case left.TypeCode == JSONTypeCodeInt64 && right.TypeCode == JSONTypeCodeInt64:
+		return CompareInt64(left.GetInt64(), right.GetInt64())
+	case left.TypeCode == JSONTypeCodeUint64 && right.TypeCode == JSONTypeCodeUint64:
+		return CompareUint64(left.GetUint64(), right.GetUint64())
+	case left.TypeCode == JSONTypeCodeInt64 && right.TypeCode == JSONTypeCodeUint64:
+		if left.GetInt64() < 0 {
+			return -1
+		}
+		return CompareUint64(left.GetUint64(), right.GetUint64())
+	case left.TypeCode == JSONTypeCodeUint64 && right.TypeCode == JSONTypeCodeInt64:
+		return -compareJSONNumber(right, left)
+	case left.TypeCode == JSONTypeCodeOpaque || right.TypeCode == JSONTypeCodeOpaque:
+		// Compare decimals exactly, as converting them to floats may lose precision.
+		return jsonNumberAsDecimal(left).Compare(jsonNumberAsDecimal(right))
+	}
+	return CompareFloat64(jsonNumberAsFloat64(left), jsonNumberAsFloat64(right))
+}
+
+func jsonNumberAsFloat64(bj BinaryJSON) float64 {
+	switch bj.TypeCode {
+	case JSONTypeCodeInt64:
+		return float64(bj.GetInt64())
+	case JSONTypeCodeUint64:
+		return float64(bj.GetUint64())
+	case JSONTypeCodeOpaque:
+		f, err := bj.GetDecimal().ToFloat64()
+		terror.Log(errors.Trace(err))
+		return f
+	}
+	return bj.GetFloat64()
+}
+
+func jsonNumberAsDecimal(bj BinaryJSON) *MyDecimal {
+	switch bj.TypeCode {
+	case JSONTypeCodeInt64:
+		return new(MyDecimal).FromInt(bj.GetInt64())
+	case JSONTypeCodeUint64:
+		return new(MyDecimal).FromUint(bj.GetUint64())
+	case JSONTypeCodeOpaque:
+		return bj.GetDecimal()
+	}
+	d := new(MyDecimal)
+	terror.Log(errors.Trace(d.FromFloat64(bj.GetFloat64())))
+	return d
+}
+
+// HashValue converts certain JSON values for aggregate comparisons.
+// For example int64(3) == float64(3.0), so both are hashed as float64.
+func (bj BinaryJSON) HashValue(buf []byte) []byte {
+	switch bj.TypeCode {
+	case JSONTypeCodeInt64, JSONTypeCodeUint64, JSONTypeCodeFloat64:
+		// Convert to a float if no precision is lost.
+		f := jsonNumberAsFloat64(bj)
+		exact := true
+		if bj.TypeCode == JSONTypeCodeInt64 {
+			exact = f >= math.MinInt64 && f < math.MaxInt64 && int64(f) == bj.GetInt64()
+		} else if bj.TypeCode == JSONTypeCodeUint64 {
+			exact = f < math.MaxUint64 && uint64(f) == bj.GetUint64()
+		}
+		if !exact {
+			buf = append(buf, bj.TypeCode)
+			buf = append(buf, bj.Value...)
+			break
+		}
+		if f == 0 {
+			// Normalize -0.0.
+			f = 0
+		}
+		buf = append(buf, JSONTypeCodeFloat64)
+		buf = appendBinaryJSONUint64(buf, math.Float64bits(f))
+	case JSONTypeCodeOpaque:
+		d := bj.GetDecimal()
+		if d == nil {
+			buf = append(buf, bj.TypeCode)
+			buf = append(buf, bj.Value...)
+			break
+		}
+		// Hash the decimal as a float if no precision is lost, so 1.50 and
+		// 1.5e0 are equal, otherwise use its hash key which ignores the frac.
+		f := jsonNumberAsFloat64(bj)
+		exact := new(MyDecimal)
+		if err := exact.FromFloat64(f); err == nil && exact.Compare(d) == 0 {
+			if f == 0 {
+				f = 0
+			}
+			buf = append(buf, JSONTypeCodeFloat64)
+			buf = appendBinaryJSONUint64(buf, math.Float64bits(f))
+			break
+		}
+		key, err := d.ToHashKey()
+		terror.Log(errors.Trace(err))
+		buf = append(buf, bj.TypeCode)
+		buf = append(buf, key...)
+	case JSONTypeCodeArray:
+		buf = append(buf, bj.TypeCode)
+		elemCount := bj.GetElemCount()
+		for i := 0; i < elemCount; i++ {
+			buf = bj.arrayGetElem(i).HashValue(buf)
+		}
+	case JSONTypeCodeObject:
+		buf = append(buf, bj.TypeCode)
+		elemCount := bj.GetElemCount()
+		for i := 0; i < elemCount; i++ {
+			buf = appendBinaryJSONString(buf, string(hack.String(bj.objectGetKey(i))))
+			buf = bj.objectGetVal(i).HashValue(buf)
+		}
+	default:
+		buf = append(buf, bj.TypeCode)
+		buf = append(buf, bj.Value...)
+	}
+	return buf
+}
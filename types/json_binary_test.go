@@ -0,0 +1,261 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testJSONSuite{})
+
+type testJSONSuite struct{}
+
+func mustParseBinaryJSON(c *C, s string) BinaryJSON {
+	bj, err := ParseBinaryJSONFromString(s)
+	c.Assert(err, IsNil, Commentf("%s", s))
+	return bj
+}
+
+func mustParseJSONPathExprs(c *C, paths ...string) []JSONPathExpression {
+	pathExprs := make([]JSONPathExpression, 0, len(paths))
+	for _, path := range paths {
+		pathExpr, err := ParseJSONPathExpr(path)
+		c.Assert(err, IsNil, Commentf("%s", path))
+		pathExprs = append(pathExprs, pathExpr)
+	}
+	return pathExprs
+}
+
+func (s *testJSONSuite) TestParseAndMarshal(c *C) {
+	tests := []struct {
+		input    string
+		expected string
+		tp       string
+	}{
+		{`null`, `null`, "NULL"},
+		{` true `, `true`, "BOOLEAN"},
+		{`false`, `false`, "BOOLEAN"},
+		{`-3`, `-3`, "INTEGER"},
+		{`18446744073709551615`, `18446744073709551615`, "UNSIGNED INTEGER"},
+		{`3.5`, `3.5`, "DOUBLE"},
+		{`1e2`, `100.0`, "DOUBLE"},
+		{`"a\"bé"`, `"a\"bé"`, "STRING"},
+		{`[1,"a",[],{}]`, `[1, "a", [], {}]`, "ARRAY"},
+		{`{"b":1,"a":2,"aa":3,"b":4}`, `{"a": 2, "b": 4, "aa": 3}`, "OBJECT"},
+	}
+	for _, t := range tests {
+		bj := mustParseBinaryJSON(c, t.input)
+		c.Assert(bj.String(), Equals, t.expected)
+		c.Assert(bj.Type(), Equals, t.tp)
+
+		copied := bj.Copy()
+		c.Assert(CompareBinaryJSON(bj, copied), Equals, 0)
+		parsed := mustParseBinaryJSON(c, t.expected)
+		c.Assert(CompareBinaryJSON(bj, parsed), Equals, 0)
+	}
+
+	for _, s := range []string{``, `{`, `[1,]`, `{"a"}`, `abc`, `1 2`} {
+		_, err := ParseBinaryJSONFromString(s)
+		c.Assert(ErrInvalidJSONText.Equal(err), IsTrue, Commentf("%s", s))
+	}
+}
+
+func (s *testJSONSuite) TestParseJSONPathExpr(c *C) {
+	tests := []struct {
+		path        string
+		expected    string
+		hasAsterisk bool
+	}{
+		{`$`, `$`, false},
+		{` $ . a [ 1 ] `, `$."a"[1]`, false},
+		{`$."a b"`, `$."a b"`, false},
+		{`$[*].a`, `$[*]."a"`, true},
+		{`$.*`, `$.*`, true},
+		{`$**.a`, `$**."a"`, true},
+	}
+	for _, t := range tests {
+		pathExpr, err := ParseJSONPathExpr(t.path)
+		c.Assert(err, IsNil, Commentf("%s", t.path))
+		c.Assert(pathExpr.String(), Equals, t.expected)
+		c.Assert(pathExpr.ContainsAnyAsterisk(), Equals, t.hasAsterisk)
+	}
+
+	for _, path := range []string{``, `a`, `$.`, `$[`, `$[a]`, `$[-1]`, `$**`, `$.a**`, `$."a`} {
+		_, err := ParseJSONPathExpr(path)
+		c.Assert(ErrInvalidJSONPath.Equal(err), IsTrue, Commentf("%s", path))
+	}
+}
+
+func (s *testJSONSuite) TestExtract(c *C) {
+	doc := mustParseBinaryJSON(c, `{"a": [1, {"b": 2}, {"b": 3}], "c": {"b": 4}, "d": "e"}`)
+	tests := []struct {
+		paths    []string
+		expected string
+		found    bool
+	}{
+		{[]string{`$`}, doc.String(), true},
+		{[]string{`$.a[1].b`}, `2`, true},
+		{[]string{`$.d[0]`}, `"e"`, true},
+		{[]string{`$.a[3]`}, ``, false},
+		{[]string{`$.x`}, ``, false},
+		{[]string{`$.a[*].b`}, `[2, 3]`, true},
+		{[]string{`$**.b`}, `[2, 3, 4]`, true},
+		{[]string{`$.x[*]`}, ``, false},
+		{[]string{`$.d`, `$.a[0]`}, `["e", 1]`, true},
+		{[]string{`$.x`, `$.y`}, ``, false},
+	}
+	for _, t := range tests {
+		ret, found := doc.Extract(mustParseJSONPathExprs(c, t.paths...))
+		c.Assert(found, Equals, t.found, Commentf("%v", t.paths))
+		if found {
+			c.Assert(ret.String(), Equals, t.expected, Commentf("%v", t.paths))
+		}
+	}
+}
+
+func (s *testJSONSuite) TestModifyAndRemove(c *C) {
+	doc := mustParseBinaryJSON(c, `{"a": 1, "b": [1, 2]}`)
+	tests := []struct {
+		path     string
+		value    string
+		mt       JSONModifyType
+		expected string
+	}{
+		{`$`, `3`, JSONModifySet, `3`},
+		{`$`, `3`, JSONModifyInsert, `{"a": 1, "b": [1, 2]}`},
+		{`$.a`, `3`, JSONModifySet, `{"a": 3, "b": [1, 2]}`},
+		{`$.a`, `3`, JSONModifyInsert, `{"a": 1, "b": [1, 2]}`},
+		{`$.c`, `3`, JSONModifyInsert, `{"a": 1, "b": [1, 2], "c": 3}`},
+		{`$.c`, `3`, JSONModifyReplace, `{"a": 1, "b": [1, 2]}`},
+		{`$.b[1]`, `[3]`, JSONModifyReplace, `{"a": 1, "b": [1, [3]]}`},
+		{`$.b[9]`, `3`, JSONModifyInsert, `{"a": 1, "b": [1, 2, 3]}`},
+		{`$.a[1]`, `3`, JSONModifySet, `{"a": [1, 3], "b": [1, 2]}`},
+		{`$.a[0]`, `3`, JSONModifySet, `{"a": 3, "b": [1, 2]}`},
+		{`$.x.y`, `3`, JSONModifySet, `{"a": 1, "b": [1, 2]}`},
+	}
+	for _, t := range tests {
+		ret, err := doc.Modify(mustParseJSONPathExprs(c, t.path), []BinaryJSON{mustParseBinaryJSON(c, t.value)}, t.mt)
+		c.Assert(err, IsNil)
+		c.Assert(ret.String(), Equals, t.expected, Commentf("%s %d", t.path, t.mt))
+	}
+	_, err := doc.Modify(mustParseJSONPathExprs(c, `$.*`), []BinaryJSON{CreateBinaryJSON(nil)}, JSONModifySet)
+	c.Assert(ErrInvalidJSONPathWildcard.Equal(err), IsTrue)
+	c.Assert(doc.String(), Equals, `{"a": 1, "b": [1, 2]}`)
+
+	ret, err := doc.Remove(mustParseJSONPathExprs(c, `$.b[0]`, `$.a`, `$.x`))
+	c.Assert(err, IsNil)
+	c.Assert(ret.String(), Equals, `{"b": [2]}`)
+	_, err = doc.Remove(mustParseJSONPathExprs(c, `$`))
+	c.Assert(ErrInvalidJSONPath.Equal(err), IsTrue)
+	_, err = doc.Remove(mustParseJSONPathExprs(c, `$**.a`))
+	c.Assert(ErrInvalidJSONPathWildcard.Equal(err), IsTrue)
+}
+
+func (s *testJSONSuite) TestContains(c *C) {
+	tests := []struct {
+		obj      string
+		target   string
+		expected bool
+	}{
+		{`1`, `1`, true},
+		{`1`, `1.0`, true},
+		{`1`, `"1"`, false},
+		{`[1, [2, 3]]`, `3`, true},
+		{`[1, [2, 3]]`, `[1, 3]`, true},
+		{`[1, 2]`, `[1, 4]`, false},
+		{`{"a": 1, "b": {"c": [1, 2]}}`, `{"b": {"c": 2}}`, true},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{`{"a": 1}`, `1`, false},
+		{`[{"a": 1}]`, `{"a": 1}`, true},
+	}
+	for _, t := range tests {
+		obj, target := mustParseBinaryJSON(c, t.obj), mustParseBinaryJSON(c, t.target)
+		c.Assert(ContainsBinaryJSON(obj, target), Equals, t.expected, Commentf("%s %s", t.obj, t.target))
+	}
+}
+
+func (s *testJSONSuite) TestCompare(c *C) {
+	tests := []struct {
+		left     string
+		right    string
+		expected int
+	}{
+		{`null`, `1`, -1},
+		{`1`, `"1"`, -1},
+		{`"a"`, `{}`, -1},
+		{`{}`, `[]`, -1},
+		{`[]`, `false`, -1},
+		{`false`, `true`, -1},
+		{`1`, `1.0`, 0},
+		{`-1`, `18446744073709551615`, -1},
+		{`1.5`, `2`, -1},
+		{`"ab"`, `"b"`, -1},
+		{`[1, 2]`, `[1, 3]`, -1},
+		{`[1, 2]`, `[1, 2, 0]`, -1},
+		{`{"a": 1}`, `{"a": 1.0}`, 0},
+		{`{"a": 1}`, `{"a": 2}`, -1},
+		{`{"a": 1}`, `{"bb": 1}`, -1},
+		{`{"a": 1}`, `{"a": 1, "b": 1}`, -1},
+	}
+	for _, t := range tests {
+		left, right := mustParseBinaryJSON(c, t.left), mustParseBinaryJSON(c, t.right)
+		c.Assert(CompareBinaryJSON(left, right), Equals, t.expected, Commentf("%s %s", t.left, t.right))
+		c.Assert(CompareBinaryJSON(right, left), Equals, -t.expected, Commentf("%s %s", t.right, t.left))
+	}
+}
+
+func (s *testJSONSuite) TestDecimal(c *C) {
+	d := NewDecFromStringForTest("1.50")
+	bj := CreateBinaryJSON(d)
+	c.Assert(bj.Type(), Equals, "DECIMAL")
+	c.Assert(bj.String(), Equals, "1.50")
+	c.Assert(bj.GetDecimal().Compare(d), Equals, 0)
+	c.Assert(CompareBinaryJSON(bj, mustParseBinaryJSON(c, `1.5`)), Equals, 0)
+	c.Assert(CompareBinaryJSON(bj, mustParseBinaryJSON(c, `2`)), Equals, -1)
+	c.Assert(CompareBinaryJSON(mustParseBinaryJSON(c, `"1.5"`), bj), Equals, 1)
+	c.Assert(bj.HashValue(nil), DeepEquals, mustParseBinaryJSON(c, `1.5`).HashValue(nil))
+
+	arr := CreateBinaryJSON([]interface{}{d, "a"})
+	c.Assert(arr.String(), Equals, `[1.50, "a"]`)
+	c.Assert(arr.arrayGetElem(0).Type(), Equals, "DECIMAL")
+	peeked, err := PeekBytesAsJSON(append([]byte{arr.TypeCode}, arr.Value...))
+	c.Assert(err, IsNil)
+	c.Assert(peeked, Equals, 1+len(arr.Value))
+	peeked, err = PeekBytesAsJSON(append([]byte{bj.TypeCode}, bj.Value...))
+	c.Assert(err, IsNil)
+	c.Assert(peeked, Equals, 1+len(bj.Value))
+}
+
+func (s *testJSONSuite) TestUnquote(c *C) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a"`, `a`},
+		{`"\"\\\/\b\f\n\r\t"`, "\"\\/\b\f\n\r\t"},
+		{`"你好"`, `你好`},
+		{`[1, "a"]`, `[1, "a"]`},
+		{`null`, `null`},
+	}
+	for _, t := range tests {
+		bj := mustParseBinaryJSON(c, t.input)
+		c.Assert(bj.Unquote(), Equals, t.expected)
+	}
+
+	str, err := UnquoteJSONString(`a\tbé\x`)
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, "a\tbéx")
+	_, err = UnquoteJSONString(`\u00`)
+	c.Assert(err, NotNil)
+}
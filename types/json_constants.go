@@ -0,0 +1,118 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+)
+
+// JSONTypeCode indicates JSON type.
+type JSONTypeCode = byte
+
+const (
+	// JSONTypeCodeObject indicates the JSON is an object.
+	JSONTypeCodeObject JSONTypeCode = 0x01
+	// JSONTypeCodeArray indicates the JSON is an array.
+	JSONTypeCodeArray JSONTypeCode = 0x03
+	// JSONTypeCodeLiteral indicates the JSON is a literal.
+	JSONTypeCodeLiteral JSONTypeCode = 0x04
+	// JSONTypeCodeInt64 indicates the JSON is a signed integer.
+	JSONTypeCodeInt64 JSONTypeCode = 0x09
+	// JSONTypeCodeUint64 indicates the JSON is a unsigned integer.
+	JSONTypeCodeUint64 JSONTypeCode = 0x0a
+	// JSONTypeCodeFloat64 indicates the JSON is a double float number.
+	JSONTypeCodeFloat64 JSONTypeCode = 0x0b
+	// JSONTypeCodeString indicates the JSON is a string.
+	JSONTypeCodeString JSONTypeCode = 0x0c
+	// JSONTypeCodeOpaque indicates the JSON is a value of another MySQL type,
+	// only decimals are stored in it now.
+	JSONTypeCodeOpaque JSONTypeCode = 0x0f
+)
+
+const (
+	// JSONLiteralNil represents JSON null.
+	JSONLiteralNil byte = 0x00
+	// JSONLiteralTrue represents JSON true.
+	JSONLiteralTrue byte = 0x01
+	// JSONLiteralFalse represents JSON false.
+	JSONLiteralFalse byte = 0x02
+)
+
+// JSON precedences used in comparison, see
+// https://dev.mysql.com/doc/refman/5.7/en/json.html#json-comparison.
+// Values with a higher precedence compare greater than values with a lower
+// precedence, values with the same precedence are compared by their contents.
+const (
+	jsonPrecedenceBlob    = -1
+	jsonPrecedenceBit     = -2
+	jsonPrecedenceOpaque  = -3
+	jsonPrecedenceBoolean = -7
+	jsonPrecedenceArray   = -8
+	jsonPrecedenceObject  = -9
+	jsonPrecedenceString  = -10
+	jsonPrecedenceNumber  = -11
+	jsonPrecedenceNull    = -12
+)
+
+var jsonTypePrecedences = map[string]int{
+	"BLOB":             jsonPrecedenceBlob,
+	"BIT":              jsonPrecedenceBit,
+	"OPAQUE":           jsonPrecedenceOpaque,
+	"BOOLEAN":          jsonPrecedenceBoolean,
+	"ARRAY":            jsonPrecedenceArray,
+	"OBJECT":           jsonPrecedenceObject,
+	"STRING":           jsonPrecedenceString,
+	"INTEGER":          jsonPrecedenceNumber,
+	"UNSIGNED INTEGER": jsonPrecedenceNumber,
+	"DOUBLE":           jsonPrecedenceNumber,
+	"DECIMAL":          jsonPrecedenceNumber,
+	"NULL":             jsonPrecedenceNull,
+}
+
+// JSONModifyType is for modify a JSON. There are three valid values:
+// JSONModifyInsert, JSONModifyReplace and JSONModifySet.
+type JSONModifyType byte
+
+const (
+	// JSONModifyInsert is for insert a new element into a JSON.
+	JSONModifyInsert JSONModifyType = 0x01
+	// JSONModifyReplace is for replace an old elemList from a JSON.
+	JSONModifyReplace JSONModifyType = 0x02
+	// JSONModifySet = JSONModifyInsert | JSONModifyReplace
+	JSONModifySet JSONModifyType = 0x03
+)
+
+var (
+	// ErrInvalidJSONText means invalid JSON text.
+	ErrInvalidJSONText = terror.ClassJSON.New(mysql.ErrInvalidJSONText, mysql.MySQLErrName[mysql.ErrInvalidJSONText])
+	// ErrInvalidJSONPath means invalid JSON path.
+	ErrInvalidJSONPath = terror.ClassJSON.New(mysql.ErrInvalidJSONPath, mysql.MySQLErrName[mysql.ErrInvalidJSONPath])
+	// ErrInvalidJSONData means invalid JSON data.
+	ErrInvalidJSONData = terror.ClassJSON.New(mysql.ErrInvalidJSONData, mysql.MySQLErrName[mysql.ErrInvalidJSONData])
+	// ErrInvalidJSONPathWildcard means invalid JSON path that contain wildcard characters.
+	ErrInvalidJSONPathWildcard = terror.ClassJSON.New(mysql.ErrInvalidJSONPathWildcard, mysql.MySQLErrName[mysql.ErrInvalidJSONPathWildcard])
+	// ErrInvalidJSONContainsPathType means invalid JSON contains path type.
+	ErrInvalidJSONContainsPathType = terror.ClassJSON.New(mysql.ErrInvalidJSONContainsPathType, mysql.MySQLErrName[mysql.ErrInvalidJSONContainsPathType])
+)
+
+func init() {
+	terror.ErrClassToMySQLCodes[terror.ClassJSON] = map[terror.ErrCode]uint16{
+		mysql.ErrInvalidJSONText:             mysql.ErrInvalidJSONText,
+		mysql.ErrInvalidJSONPath:             mysql.ErrInvalidJSONPath,
+		mysql.ErrInvalidJSONData:             mysql.ErrInvalidJSONData,
+		mysql.ErrInvalidJSONPathWildcard:     mysql.ErrInvalidJSONPathWildcard,
+		mysql.ErrInvalidJSONContainsPathType: mysql.ErrInvalidJSONContainsPathType,
+	}
+}
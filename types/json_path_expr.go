@@ -0,0 +1,270 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+	From MySQL 5.7, JSON path expression grammar:
+		pathExpression ::= scope (pathLeg)*
+		scope ::= [ columnReference ] '$'
+		columnReference ::= // omit...
+		pathLeg ::= member | arrayLocation | '**'
+		member ::= '.' (keyName | '*')
+		arrayLocation ::= '[' (non-negative-integer | '*') ']'
+		keyName ::= ECMAScript-identifier | ECMAScript-string-literal
+
+	And some implementation limits in MySQL 5.7:
+		1) columnReference in scope must be empty now;
+		2) double asterisk(**) could not be last leg;
+
+	Examples:
+		select json_extract('{"a": "b", "c": [1, "2"]}', '$.a') -> "b"
+		select json_extract('{"a": "b", "c": [1, "2"]}', '$.c') -> [1, "2"]
+		select json_extract('{"a": "b", "c": [1, "2"]}', '$.a', '$.c') -> ["b", [1, "2"]]
+		select json_extract('{"a": "b", "c": [1, "2"]}', '$.c[0]') -> 1
+		select json_extract('{"a": "b", "c": [1, "2"]}', '$.c[2]') -> NULL
+		select json_extract('{"a": "b", "c": [1, "2"]}', '$.c[*]') -> [1, "2"]
+		select json_extract('{"a": "b", "c": [1, "2"]}', '$.*') -> ["b", [1, "2"]]
+*/
+
+// jsonPathLegType is the type of path legs.
+type jsonPathLegType byte
+
+const (
+	// jsonPathLegKey indicates the path leg with '.key'.
+	jsonPathLegKey jsonPathLegType = 0x01
+	// jsonPathLegIndex indicates the path leg with form '[number]'.
+	jsonPathLegIndex jsonPathLegType = 0x02
+	// jsonPathLegDoubleAsterisk indicates the path leg with form '**'.
+	jsonPathLegDoubleAsterisk jsonPathLegType = 0x03
+)
+
+// jsonPathLeg is only used by JSONPathExpression.
+type jsonPathLeg struct {
+	typ        jsonPathLegType
+	arrayIndex int    // if typ is jsonPathLegIndex, the value should be parsed into here.
+	dotKey     string // if typ is jsonPathLegKey, the key should be parsed into here.
+}
+
+// jsonArrayIndexAsterisk is for parsing `*` into a number.
+// we need this number represent "all".
+const jsonArrayIndexAsterisk = -1
+
+// jsonPathExpressionFlag holds attributes of JSONPathExpression
+type jsonPathExpressionFlag byte
+
+const (
+	jsonPathExpressionContainsAsterisk       jsonPathExpressionFlag = 0x01
+	jsonPathExpressionContainsDoubleAsterisk jsonPathExpressionFlag = 0x02
+)
+
+// JSONPathExpression is for JSON path expression.
+type JSONPathExpression struct {
+	legs  []jsonPathLeg
+	flags jsonPathExpressionFlag
+}
+
+// popOneLeg returns a jsonPathLeg, and a child JSONPathExpression without that leg.
+func (pe JSONPathExpression) popOneLeg() (jsonPathLeg, JSONPathExpression) {
+	newPe := JSONPathExpression{
+		legs:  pe.legs[1:],
+		flags: 0,
+	}
+	for _, leg := range newPe.legs {
+		if leg.typ == jsonPathLegIndex && leg.arrayIndex == jsonArrayIndexAsterisk {
+			newPe.flags |= jsonPathExpressionContainsAsterisk
+		} else if leg.typ == jsonPathLegKey && leg.dotKey == "*" {
+			newPe.flags |= jsonPathExpressionContainsAsterisk
+		} else if leg.typ == jsonPathLegDoubleAsterisk {
+			newPe.flags |= jsonPathExpressionContainsDoubleAsterisk
+		}
+	}
+	return pe.legs[0], newPe
+}
+
+// ContainsAnyAsterisk returns true if pe contains any asterisk.
+func (pe JSONPathExpression) ContainsAnyAsterisk() bool {
+	return pe.flags&(jsonPathExpressionContainsAsterisk|jsonPathExpressionContainsDoubleAsterisk) != 0
+}
+
+// IsRoot returns true if pe is the root path '$'.
+func (pe JSONPathExpression) IsRoot() bool {
+	return len(pe.legs) == 0
+}
+
+// ParseJSONPathExpr parses a JSON path expression. Returns a JSONPathExpression
+// object which can be used in JSON_EXTRACT, JSON_SET and so on.
+func ParseJSONPathExpr(pathExpr string) (JSONPathExpression, error) {
+	p := jsonPathParser{s: pathExpr}
+	pe, ok := p.parse()
+	if !ok {
+		return JSONPathExpression{}, ErrInvalidJSONPath.GenWithStackByArgs(strconv.Quote(pathExpr))
+	}
+	return pe, nil
+}
+
+type jsonPathParser struct {
+	s   string
+	pos int
+}
+
+func (p *jsonPathParser) skipWhitespace() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *jsonPathParser) parse() (pe JSONPathExpression, ok bool) {
+	p.skipWhitespace()
+	if p.pos >= len(p.s) || p.s[p.pos] != '$' {
+		return pe, false
+	}
+	p.pos++
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.s) {
+			break
+		}
+		var leg jsonPathLeg
+		switch p.s[p.pos] {
+		case '.':
+			p.pos++
+			leg, ok = p.parseMember()
+		case '[':
+			p.pos++
+			leg, ok = p.parseArrayLocation()
+		case '*':
+			if p.pos+1 < len(p.s) && p.s[p.pos+1] == '*' {
+				p.pos += 2
+				leg, ok = jsonPathLeg{typ: jsonPathLegDoubleAsterisk}, true
+			} else {
+				ok = false
+			}
+		default:
+			ok = false
+		}
+		if !ok {
+			return pe, false
+		}
+		pe.legs = append(pe.legs, leg)
+		switch {
+		case leg.typ == jsonPathLegDoubleAsterisk:
+			pe.flags |= jsonPathExpressionContainsDoubleAsterisk
+		case leg.typ == jsonPathLegKey && leg.dotKey == "*",
+			leg.typ == jsonPathLegIndex && leg.arrayIndex == jsonArrayIndexAsterisk:
+			pe.flags |= jsonPathExpressionContainsAsterisk
+		}
+	}
+	if len(pe.legs) > 0 && pe.legs[len(pe.legs)-1].typ == jsonPathLegDoubleAsterisk {
+		// The last leg of a path expression cannot be '**'.
+		return pe, false
+	}
+	return pe, true
+}
+
+func (p *jsonPathParser) parseMember() (jsonPathLeg, bool) {
+	p.skipWhitespace()
+	if p.pos >= len(p.s) {
+		return jsonPathLeg{}, false
+	}
+	switch c := p.s[p.pos]; {
+	case c == '*':
+		p.pos++
+		return jsonPathLeg{typ: jsonPathLegKey, dotKey: "*"}, true
+	case c == '"':
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.s) && p.s[p.pos] != '"' {
+			if p.s[p.pos] == '\\' {
+				p.pos++
+			}
+			p.pos++
+		}
+		if p.pos >= len(p.s) {
+			return jsonPathLeg{}, false
+		}
+		p.pos++
+		key, err := UnquoteJSONString(p.s[start+1 : p.pos-1])
+		if err != nil {
+			return jsonPathLeg{}, false
+		}
+		return jsonPathLeg{typ: jsonPathLegKey, dotKey: key}, true
+	default:
+		start := p.pos
+		for p.pos < len(p.s) && isJSONPathIdentChar(rune(p.s[p.pos])) {
+			p.pos++
+		}
+		if start == p.pos || (p.s[start] >= '0' && p.s[start] <= '9') {
+			return jsonPathLeg{}, false
+		}
+		return jsonPathLeg{typ: jsonPathLegKey, dotKey: p.s[start:p.pos]}, true
+	}
+}
+
+// isJSONPathIdentChar checks whether the byte can be a part of an unquoted
+// key, non-ASCII bytes are always accepted so that UTF-8 keys are supported.
+func isJSONPathIdentChar(c rune) bool {
+	return c == '_' || c == '$' || c >= utf8.RuneSelf || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+func (p *jsonPathParser) parseArrayLocation() (jsonPathLeg, bool) {
+	end := strings.IndexByte(p.s[p.pos:], ']')
+	if end < 0 {
+		return jsonPathLeg{}, false
+	}
+	idxStr := strings.TrimSpace(p.s[p.pos : p.pos+end])
+	p.pos += end + 1
+	if idxStr == "*" {
+		return jsonPathLeg{typ: jsonPathLegIndex, arrayIndex: jsonArrayIndexAsterisk}, true
+	}
+	idx, err := strconv.ParseUint(idxStr, 10, 31)
+	if err != nil {
+		return jsonPathLeg{}, false
+	}
+	return jsonPathLeg{typ: jsonPathLegIndex, arrayIndex: int(idx)}, true
+}
+
+func (pe JSONPathExpression) String() string {
+	var s strings.Builder
+
+	s.WriteString("$")
+	for _, leg := range pe.legs {
+		switch leg.typ {
+		case jsonPathLegIndex:
+			if leg.arrayIndex == jsonArrayIndexAsterisk {
+				s.WriteString("[*]")
+			} else {
+				s.WriteString("[")
+				s.WriteString(strconv.Itoa(leg.arrayIndex))
+				s.WriteString("]")
+			}
+		case jsonPathLegKey:
+			s.WriteString(".")
+			if leg.dotKey == "*" {
+				s.WriteString(leg.dotKey)
+			} else {
+				s.WriteString(strconv.Quote(leg.dotKey))
+			}
+		case jsonPathLegDoubleAsterisk:
+			s.WriteString("**")
+		}
+	}
+	return s.String()
+}
@@ -38,9 +38,11 @@ import (
 
 func init() {
 	ast.NewValueExpr = newValueExpr
+	ast.NewParamMarkerExpr = newParamMarkerExpr
 	ast.NewDecimal = func(str string) (interface{}, error) {
-		n, err := strconv.ParseFloat(str, 64)
-		return n, err
+		dec := new(types.MyDecimal)
+		err := dec.FromString([]byte(str))
+		return dec, err
 	}
 	ast.NewHexLiteral = func(str string) (interface{}, error) {
 		h, err := types.NewHexLiteral(str)
@@ -53,7 +55,8 @@ func init() {
 }
 
 var (
-	_ ast.ValueExpr = &ValueExpr{}
+	_ ast.ParamMarkerExpr = &ParamMarkerExpr{}
+	_ ast.ValueExpr       = &ValueExpr{}
 )
 
 // ValueExpr is the simple value expression.
@@ -92,6 +95,8 @@ func (n *ValueExpr) Format(w io.Writer) {
 		s = strconv.FormatFloat(n.GetFloat64(), 'e', -1, 64)
 	case types.KindString, types.KindBytes:
 		s = strconv.Quote(n.GetString())
+	case types.KindMysqlDecimal:
+		s = n.GetMysqlDecimal().String()
 	default:
 		panic("Can't format to string")
 	}
@@ -129,3 +134,38 @@ func (n *ValueExpr) Accept(v ast.Visitor) (ast.Node, bool) {
 	n = newNode.(*ValueExpr)
 	return v.Leave(n)
 }
+
+// ParamMarkerExpr expression holds a place for another expression.
+// Used in parsing prepare statement.
+type ParamMarkerExpr struct {
+	ValueExpr
+	Offset    int
+	Order     int
+	InExecute bool
+}
+
+func newParamMarkerExpr(offset int) ast.ParamMarkerExpr {
+	return &ParamMarkerExpr{
+		Offset: offset,
+	}
+}
+
+// Format the ExprNode into a Writer.
+func (n *ParamMarkerExpr) Format(w io.Writer) {
+	fmt.Fprint(w, "?")
+}
+
+// Accept implements Node Accept interface.
+func (n *ParamMarkerExpr) Accept(v ast.Visitor) (ast.Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ParamMarkerExpr)
+	return v.Leave(n)
+}
+
+// SetOrder implements the ast.ParamMarkerExpr interface.
+func (n *ParamMarkerExpr) SetOrder(order int) {
+	n.Order = order
+}
@@ -0,0 +1,1327 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	gotime "time"
+	"unicode"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+)
+
+const (
+	// DateFormat is the format of DATE values.
+	DateFormat = "2006-01-02"
+	// TimeFormat is the format of DATETIME and TIMESTAMP values.
+	TimeFormat = "2006-01-02 15:04:05"
+	// TimeFSPFormat is time format with fractional seconds precision.
+	TimeFSPFormat = "2006-01-02 15:04:05.000000"
+)
+
+const (
+	// MaxDuration is the maximum for duration.
+	MaxDuration int64 = 838*10000 + 59*100 + 59
+	// MinTime is the minimum for mysql time type.
+	MinTime = -gotime.Duration(838*3600+59*60+59) * gotime.Second
+	// MaxTime is the maximum for mysql time type.
+	MaxTime = gotime.Duration(838*3600+59*60+59) * gotime.Second
+	// ZeroDatetimeStr is the string representation of a zero datetime.
+	ZeroDatetimeStr = "0000-00-00 00:00:00"
+	// ZeroDateStr is the string representation of a zero date.
+	ZeroDateStr = "0000-00-00"
+
+	// TimeMaxHour is the max hour for mysql time type.
+	TimeMaxHour = 838
+	// TimeMaxMinute is the max minute for mysql time type.
+	TimeMaxMinute = 59
+	// TimeMaxSecond is the max second for mysql time type.
+	TimeMaxSecond = 59
+	// TimeMaxValue is the maximum value for mysql time type.
+	TimeMaxValue = TimeMaxHour*10000 + TimeMaxMinute*100 + TimeMaxSecond
+)
+
+var (
+	// ZeroDuration is the zero value for Duration type.
+	ZeroDuration = Duration{Duration: gotime.Duration(0), Fsp: DefaultFsp}
+
+	// ZeroTime is the zero value for TimeInternal type.
+	ZeroTime = NewTime(ZeroCoreTime, mysql.TypeDatetime, DefaultFsp)
+
+	// ZeroDatetime is the zero value for datetime Time.
+	ZeroDatetime = NewTime(ZeroCoreTime, mysql.TypeDatetime, DefaultFsp)
+
+	// ZeroTimestamp is the zero value for timestamp Time.
+	ZeroTimestamp = NewTime(ZeroCoreTime, mysql.TypeTimestamp, DefaultFsp)
+
+	// ZeroDate is the zero value for date Time.
+	ZeroDate = NewTime(ZeroCoreTime, mysql.TypeDate, DefaultFsp)
+)
+
+var (
+	// MinDatetime is the minimum for mysql datetime type.
+	MinDatetime = FromDate(1000, 1, 1, 0, 0, 0, 0)
+	// MaxDatetime is the maximum for mysql datetime type.
+	MaxDatetime = FromDate(9999, 12, 31, 23, 59, 59, 999999)
+
+	// BoundTimezone is the timezone for min and max timestamp.
+	BoundTimezone = gotime.UTC
+	// MinTimestamp is the minimum for mysql timestamp type.
+	MinTimestamp = NewTime(FromDate(1970, 1, 1, 0, 0, 1, 0), mysql.TypeTimestamp, DefaultFsp)
+	// MaxTimestamp is the maximum for mysql timestamp type.
+	MaxTimestamp = NewTime(FromDate(2038, 1, 19, 3, 14, 7, 999999), mysql.TypeTimestamp, MaxFsp)
+)
+
+// Time is the struct for handling datetime, timestamp and date.
+type Time struct {
+	coreTime CoreTime
+	tp       uint8
+	fsp      int8
+}
+
+// NewTime constructs time from core time, type and fsp.
+func NewTime(coreTime CoreTime, tp uint8, fsp int8) Time {
+	return Time{coreTime: coreTime, tp: tp, fsp: fsp}
+}
+
+// CoreTime returns the internal representation of t.
+func (t Time) CoreTime() CoreTime {
+	return t.coreTime
+}
+
+// SetCoreTime updates the internal representation of t.
+func (t *Time) SetCoreTime(ct CoreTime) {
+	t.coreTime = ct
+}
+
+// Type returns type value.
+func (t Time) Type() uint8 {
+	return t.tp
+}
+
+// SetType updates the type in Time.
+// Only DateTime/Date/Timestamp is valid.
+func (t *Time) SetType(tp uint8) {
+	t.tp = tp
+	if tp == mysql.TypeDate {
+		// Truncate hh:mm:ss part if the type is Date.
+		t.coreTime = FromDate(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0)
+		t.fsp = 0
+	}
+}
+
+// Fsp returns fsp value.
+func (t Time) Fsp() int8 {
+	return t.fsp
+}
+
+// SetFsp updates the fsp in Time.
+func (t *Time) SetFsp(fsp int8) {
+	if fsp == UnspecifiedFsp {
+		fsp = DefaultFsp
+	}
+	t.fsp = fsp
+}
+
+// Year returns the year value.
+func (t Time) Year() int {
+	return t.coreTime.Year()
+}
+
+// Month returns the month value.
+func (t Time) Month() int {
+	return t.coreTime.Month()
+}
+
+// Day returns the day value.
+func (t Time) Day() int {
+	return t.coreTime.Day()
+}
+
+// Hour returns the hour value.
+func (t Time) Hour() int {
+	return t.coreTime.Hour()
+}
+
+// Minute returns the minute value.
+func (t Time) Minute() int {
+	return t.coreTime.Minute()
+}
+
+// Second returns the second value.
+func (t Time) Second() int {
+	return t.coreTime.Second()
+}
+
+// Microsecond returns the microsecond value.
+func (t Time) Microsecond() int {
+	return t.coreTime.Microsecond()
+}
+
+// Clock returns the hour, minute, and second within the day specified by t.
+func (t Time) Clock() (hour int, minute int, second int) {
+	return t.Hour(), t.Minute(), t.Second()
+}
+
+// Weekday returns weekday value.
+func (t Time) Weekday() gotime.Weekday {
+	return t.coreTime.Weekday()
+}
+
+// YearDay returns day in year.
+func (t Time) YearDay() int {
+	return t.coreTime.YearDay()
+}
+
+// YearWeek returns year and week.
+func (t Time) YearWeek(mode int) (int, int) {
+	return t.coreTime.YearWeek(mode)
+}
+
+// Week returns week value.
+func (t Time) Week(mode int) int {
+	return t.coreTime.Week(mode)
+}
+
+// GoTime converts Time to GoTime.
+func (t Time) GoTime(loc *gotime.Location) (gotime.Time, error) {
+	return t.coreTime.GoTime(loc)
+}
+
+// String implements fmt.Stringer interface.
+func (t Time) String() string {
+	if t.tp == mysql.TypeDate {
+		return fmt.Sprintf("%04d-%02d-%02d", t.Year(), t.Month(), t.Day())
+	}
+	str := fmt.Sprintf("%04d-%02d-%02d %02d:%02d:%02d", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+	if t.fsp > 0 {
+		frac := fmt.Sprintf(".%06d", t.Microsecond())
+		str += frac[:1+t.fsp]
+	}
+	return str
+}
+
+// IsZero returns a boolean indicating whether the time is equal to ZeroTime.
+func (t Time) IsZero() bool {
+	return t.coreTime == ZeroCoreTime
+}
+
+// InvalidZero returns a boolean indicating whether the month or day is zero.
+func (t Time) InvalidZero() bool {
+	return t.Month() == 0 || t.Day() == 0
+}
+
+// ToNumber returns a formatted number.
+// e.g,
+// 2012-12-12 -> 20121212
+// 2012-12-12T10:10:10 -> 20121212101010
+// 2012-12-12T10:10:10.123456 -> 20121212101010.123456
+func (t Time) ToNumber() *MyDecimal {
+	dec := new(MyDecimal)
+	if t.IsZero() {
+		return dec.FromInt(0)
+	}
+	var s string
+	if t.tp == mysql.TypeDate {
+		s = fmt.Sprintf("%04d%02d%02d", t.Year(), t.Month(), t.Day())
+	} else {
+		s = fmt.Sprintf("%04d%02d%02d%02d%02d%02d", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+		if t.fsp > 0 {
+			frac := fmt.Sprintf(".%06d", t.Microsecond())
+			s += frac[:1+t.fsp]
+		}
+	}
+	// We skip checking error here because time formatted string can be parsed certainly.
+	err := dec.FromString([]byte(s))
+	terror.Log(errors.Trace(err))
+	return dec
+}
+
+// Compare returns an integer comparing the time instant t to o.
+// If t is after o, returns 1, equal o, returns 0, before o, returns -1.
+func (t Time) Compare(o Time) int {
+	switch {
+	case t.coreTime < o.coreTime:
+		return -1
+	case t.coreTime > o.coreTime:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// CompareString is like Compare,
+// but parses string to Time then compares.
+func (t Time) CompareString(sc *stmtctx.StatementContext, str string) (int, error) {
+	// use MaxFsp to parse the string
+	o, err := ParseTime(sc, str, t.tp, MaxFsp)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return t.Compare(o), nil
+}
+
+// roundTime rounds the time value according to digits count specified by fsp.
+func roundTime(t gotime.Time, fsp int8) gotime.Time {
+	d := gotime.Duration(math.Pow10(9 - int(fsp)))
+	return t.Round(d)
+}
+
+// RoundFrac rounds the fraction part of a time-type value according to `fsp`.
+func (t Time) RoundFrac(fsp int8) (Time, error) {
+	if t.tp == mysql.TypeDate || t.IsZero() {
+		// date type has no fsp
+		return t, nil
+	}
+
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return t, errors.Trace(err)
+	}
+
+	if fsp == t.fsp {
+		// have same fsp
+		return t, nil
+	}
+
+	var nt CoreTime
+	if t1, err := t.GoTime(gotime.UTC); err == nil {
+		nt = FromGoTime(roundTime(t1, fsp))
+	} else {
+		// Take the hh:mm:ss part out to avoid handle month or day = 0.
+		t1 := gotime.Date(1, 1, 1, t.Hour(), t.Minute(), t.Second(), t.Microsecond()*1000, gotime.UTC)
+		t2 := roundTime(t1, fsp)
+		// When hh:mm:ss overflows one day after rounding, it should be added to the yy:mm:dd part,
+		// but mm:dd may contain 0, so we report an error here.
+		if t2.Day() != 1 {
+			return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, t.String()))
+		}
+		hour, minute, second := t2.Clock()
+		nt = FromDate(t.Year(), t.Month(), t.Day(), hour, minute, second, t2.Nanosecond()/1000)
+	}
+	return NewTime(nt, t.tp, fsp), nil
+}
+
+// Convert converts t with type tp.
+func (t Time) Convert(sc *stmtctx.StatementContext, tp uint8) (Time, error) {
+	t1 := t
+	if t.tp == tp || t.IsZero() {
+		t1.SetType(tp)
+		return t1, nil
+	}
+
+	t1.SetType(tp)
+	err := t1.Check(sc)
+	return t1, errors.Trace(err)
+}
+
+// ConvertToDuration converts mysql datetime, timestamp and date to mysql time type.
+// e.g,
+// 2012-12-12T10:10:10 -> 10:10:10
+// 2012-12-12 -> 0
+func (t Time) ConvertToDuration() (Duration, error) {
+	if t.IsZero() {
+		return ZeroDuration, nil
+	}
+
+	hour, minute, second := t.Clock()
+	frac := t.Microsecond() * 1000
+
+	d := gotime.Duration(hour*3600+minute*60+second)*gotime.Second + gotime.Duration(frac)
+	return Duration{Duration: d, Fsp: t.fsp}, nil
+}
+
+// ConvertTimeZone converts the time value from one timezone to another.
+// The input time should be a valid timestamp.
+func (t *Time) ConvertTimeZone(from, to *gotime.Location) error {
+	if !t.IsZero() {
+		raw, err := t.GoTime(from)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		converted := raw.In(to)
+		t.coreTime = FromGoTime(converted)
+	}
+	return nil
+}
+
+// ToPackedUint encodes Time to a packed uint64 value.
+//
+//	 1 bit  0
+//	17 bits year*13+month   (year 0-9999, month 0-12)
+//	 5 bits day             (0-31)
+//	 5 bits hour            (0-23)
+//	 6 bits minute          (0-59)
+//	 6 bits second          (0-59)
+//	24 bits microseconds    (0-999999)
+//
+//	Total: 64 bits = 8 bytes
+//
+//	0YYYYYYY.YYYYYYYY.YYdddddh.hhhhmmmm.mmssssss.ffffffff.ffffffff.ffffffff
+func (t Time) ToPackedUint() (uint64, error) {
+	if t.IsZero() {
+		return 0, nil
+	}
+	year, month, day := t.Year(), t.Month(), t.Day()
+	hour, minute, sec := t.Clock()
+	ymd := uint64(((year*13 + month) << 5) | day)
+	hms := uint64(hour<<12 | minute<<6 | sec)
+	micro := uint64(t.Microsecond())
+	return ((ymd<<17 | hms) << 24) | micro, nil
+}
+
+// FromPackedUint decodes Time from a packed uint64 value.
+func (t *Time) FromPackedUint(packed uint64) error {
+	if packed == 0 {
+		t.coreTime = ZeroCoreTime
+		return nil
+	}
+	ymdhms := packed >> 24
+	ymd := ymdhms >> 17
+	day := int(ymd & (1<<5 - 1))
+	ym := ymd >> 5
+	month := int(ym % 13)
+	year := int(ym / 13)
+
+	hms := ymdhms & (1<<17 - 1)
+	second := int(hms & (1<<6 - 1))
+	minute := int((hms >> 6) & (1<<6 - 1))
+	hour := int(hms >> 12)
+	microsec := int(packed % (1 << 24))
+
+	t.coreTime = FromDate(year, month, day, hour, minute, second, microsec)
+	return nil
+}
+
+// Check if 't' is valid
+func (t *Time) Check(sc *stmtctx.StatementContext) error {
+	var err error
+	switch t.tp {
+	case mysql.TypeTimestamp:
+		err = checkTimestampType(sc, t.coreTime)
+	case mysql.TypeDatetime, mysql.TypeDate:
+		err = checkDatetimeType(t.coreTime)
+	}
+	return errors.Trace(err)
+}
+
+func checkDatetimeType(t CoreTime) error {
+	if t == ZeroCoreTime {
+		return nil
+	}
+	year, month, day := t.Year(), t.Month(), t.Day()
+	if year > 9999 || month > 12 || (month != 0 && day > GetLastDay(year, month)) ||
+		t.Hour() > 23 || t.Minute() > 59 || t.Second() > 59 || t.Microsecond() > 999999 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, NewTime(t, mysql.TypeDatetime, MaxFsp)))
+	}
+	return nil
+}
+
+func checkTimestampType(sc *stmtctx.StatementContext, t CoreTime) error {
+	if t == ZeroCoreTime {
+		return nil
+	}
+	checkTime := NewTime(t, mysql.TypeTimestamp, MaxFsp)
+	if loc := getTimeZone(sc); loc != BoundTimezone {
+		if err := checkTime.ConvertTimeZone(loc, BoundTimezone); err != nil {
+			return err
+		}
+	}
+	if checkTime.Compare(MaxTimestamp) > 0 || checkTime.Compare(MinTimestamp) < 0 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, NewTime(t, mysql.TypeDatetime, MaxFsp)))
+	}
+	return nil
+}
+
+func getTimeZone(sc *stmtctx.StatementContext) *gotime.Location {
+	if sc == nil || sc.TimeZone == nil {
+		return gotime.UTC
+	}
+	return sc.TimeZone
+}
+
+// CurrentTime returns current time with type tp.
+func CurrentTime(tp uint8) Time {
+	return NewTime(FromGoTime(gotime.Now()), tp, 0)
+}
+
+// DateFormat returns a textual representation of the time value formatted
+// according to layout.
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
+func (t Time) DateFormat(layout string) (string, error) {
+	var buf bytes.Buffer
+	inPatternMatch := false
+	for _, b := range layout {
+		if inPatternMatch {
+			if err := t.convertDateFormat(b, &buf); err != nil {
+				return "", errors.Trace(err)
+			}
+			inPatternMatch = false
+			continue
+		}
+
+		// It's not in pattern match now.
+		if b == '%' {
+			inPatternMatch = true
+		} else {
+			buf.WriteRune(b)
+		}
+	}
+	return buf.String(), nil
+}
+
+var abbrevWeekdayName = []string{
+	"Sun", "Mon", "Tue",
+	"Wed", "Thu", "Fri", "Sat",
+}
+
+// MonthNames lists names of months.
+var MonthNames = []string{
+	"January", "February",
+	"March", "April",
+	"May", "June",
+	"July", "August",
+	"September", "October",
+	"November", "December",
+}
+
+func (t Time) convertDateFormat(b rune, buf *bytes.Buffer) error {
+	switch b {
+	case 'b':
+		m := t.Month()
+		if m == 0 || m > 12 {
+			return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.Itoa(m)))
+		}
+		buf.WriteString(MonthNames[m-1][:3])
+	case 'M':
+		m := t.Month()
+		if m == 0 || m > 12 {
+			return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.Itoa(m)))
+		}
+		buf.WriteString(MonthNames[m-1])
+	case 'm':
+		buf.WriteString(FormatIntWidthN(t.Month(), 2))
+	case 'c':
+		buf.WriteString(strconv.FormatInt(int64(t.Month()), 10))
+	case 'D':
+		buf.WriteString(strconv.FormatInt(int64(t.Day()), 10))
+		buf.WriteString(abbrDayOfMonth(t.Day()))
+	case 'd':
+		buf.WriteString(FormatIntWidthN(t.Day(), 2))
+	case 'e':
+		buf.WriteString(strconv.FormatInt(int64(t.Day()), 10))
+	case 'j':
+		fmt.Fprintf(buf, "%03d", t.YearDay())
+	case 'H':
+		buf.WriteString(FormatIntWidthN(t.Hour(), 2))
+	case 'k':
+		buf.WriteString(strconv.FormatInt(int64(t.Hour()), 10))
+	case 'h', 'I':
+		tt := t.Hour()
+		if tt%12 == 0 {
+			buf.WriteString("12")
+		} else {
+			buf.WriteString(FormatIntWidthN(tt%12, 2))
+		}
+	case 'l':
+		tt := t.Hour()
+		if tt%12 == 0 {
+			buf.WriteString("12")
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(tt%12), 10))
+		}
+	case 'i':
+		buf.WriteString(FormatIntWidthN(t.Minute(), 2))
+	case 'p':
+		hour := t.Hour()
+		if hour/12%2 == 0 {
+			buf.WriteString("AM")
+		} else {
+			buf.WriteString("PM")
+		}
+	case 'r':
+		h := t.Hour()
+		switch {
+		case h == 0:
+			fmt.Fprintf(buf, "%02d:%02d:%02d AM", 12, t.Minute(), t.Second())
+		case h == 12:
+			fmt.Fprintf(buf, "%02d:%02d:%02d PM", 12, t.Minute(), t.Second())
+		case h < 12:
+			fmt.Fprintf(buf, "%02d:%02d:%02d AM", h, t.Minute(), t.Second())
+		default:
+			fmt.Fprintf(buf, "%02d:%02d:%02d PM", h-12, t.Minute(), t.Second())
+		}
+	case 'T':
+		fmt.Fprintf(buf, "%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
+	case 'S', 's':
+		buf.WriteString(FormatIntWidthN(t.Second(), 2))
+	case 'f':
+		fmt.Fprintf(buf, "%06d", t.Microsecond())
+	case 'U':
+		buf.WriteString(FormatIntWidthN(t.Week(0), 2))
+	case 'u':
+		buf.WriteString(FormatIntWidthN(t.Week(1), 2))
+	case 'V':
+		buf.WriteString(FormatIntWidthN(t.Week(2), 2))
+	case 'v':
+		_, w := t.YearWeek(3)
+		buf.WriteString(FormatIntWidthN(w, 2))
+	case 'a':
+		buf.WriteString(abbrevWeekdayName[t.Weekday()])
+	case 'W':
+		buf.WriteString(t.Weekday().String())
+	case 'w':
+		buf.WriteString(strconv.FormatInt(int64(t.Weekday()), 10))
+	case 'X':
+		year, _ := t.YearWeek(2)
+		buf.WriteString(FormatIntWidthN(year, 4))
+	case 'x':
+		year, _ := t.YearWeek(3)
+		buf.WriteString(FormatIntWidthN(year, 4))
+	case 'Y':
+		buf.WriteString(FormatIntWidthN(t.Year(), 4))
+	case 'y':
+		str := FormatIntWidthN(t.Year(), 4)
+		buf.WriteString(str[2:])
+	default:
+		buf.WriteRune(b)
+	}
+
+	return nil
+}
+
+// FormatIntWidthN uses to format int with width. Insufficient digits are filled by 0.
+func FormatIntWidthN(num, n int) string {
+	numString := strconv.FormatInt(int64(num), 10)
+	if len(numString) >= n {
+		return numString
+	}
+	return strings.Repeat("0", n-len(numString)) + numString
+}
+
+func abbrDayOfMonth(day int) string {
+	switch day {
+	case 1, 21, 31:
+		return "st"
+	case 2, 22:
+		return "nd"
+	case 3, 23:
+		return "rd"
+	default:
+		return "th"
+	}
+}
+
+// ParseDateFormat parses a formatted date string and returns separated components.
+func ParseDateFormat(format string) []string {
+	format = strings.TrimSpace(format)
+
+	if len(format) == 0 {
+		return nil
+	}
+
+	// Date format must start and end with number.
+	if !isDigit(format[0]) || !isDigit(format[len(format)-1]) {
+		return nil
+	}
+
+	start := 0
+	// Initialize `seps` with capacity of 6. The input `format` is typically
+	// a date time of the form "2006-01-02 15:04:05", which has 6 numeric parts
+	// (the fractional second part is usually removed by `splitDateTime`).
+	seps := make([]string, 0, 6)
+	for i := 1; i < len(format)-1; i++ {
+		if isValidSeparator(format[i], len(seps)) {
+			prevParts := len(seps)
+			seps = append(seps, format[start:i])
+			start = i + 1
+
+			// Consume further consecutive separators.
+			for j := i + 1; j < len(format); j++ {
+				if !isValidSeparator(format[j], prevParts) {
+					break
+				}
+				start++
+				i++
+			}
+			continue
+		}
+
+		if !isDigit(format[i]) {
+			return nil
+		}
+	}
+
+	seps = append(seps, format[start:])
+	return seps
+}
+
+// IsDateFormat returns true when the specified time format could contain only date.
+func IsDateFormat(format string) bool {
+	format = strings.TrimSpace(format)
+	seps := ParseDateFormat(format)
+	length := len(format)
+	switch len(seps) {
+	case 1:
+		if (length == 8) || (length == 6) {
+			return true
+		}
+	case 3:
+		return true
+	}
+	return false
+}
+
+// isValidSeparator reports whether c separates two date or time parts.
+// Punctuation characters are valid everywhere, while spaces and 'T' are
+// only valid between the date part and the time part.
+func isValidSeparator(c byte, prevParts int) bool {
+	if unicode.IsPunct(rune(c)) || unicode.IsSymbol(rune(c)) {
+		return true
+	}
+	return prevParts == 2 && (c == ' ' || c == 'T')
+}
+
+// splitDateTime splits a datetime string into the date/time parts and the
+// fractional seconds part.
+func splitDateTime(format string) (seps []string, fracStr string) {
+	if index := getFracIndex(format); index > 0 {
+		fracStr = format[index+1:]
+		format = format[:index]
+	}
+	return ParseDateFormat(format), fracStr
+}
+
+// getFracIndex finds the last '.' for get fracStr, index = -1 means fracStr not found.
+// but for format like '2019.01.01 00:00:00', the index should be -1.
+func getFracIndex(s string) (index int) {
+	index = -1
+	for i := len(s) - 1; i >= 0; i-- {
+		if unicode.IsPunct(rune(s[i])) {
+			if s[i] == '.' {
+				index = i
+			}
+			break
+		}
+	}
+	return index
+}
+
+// adjustYear adjusts year according to y.
+// See https://dev.mysql.com/doc/refman/5.7/en/two-digit-years.html
+func adjustYear(y int) int {
+	if y >= 0 && y <= 69 {
+		y = 2000 + y
+	} else if y >= 70 && y <= 99 {
+		y = 1900 + y
+	}
+	return y
+}
+
+// scanDigits parses s as fixed-width unsigned integers, e.g. "20190102" with
+// widths [4, 2, 2] fills 2019, 1 and 2.
+func scanDigits(s string, widths []int, args ...*int) error {
+	for i, w := range widths {
+		if len(s) == 0 {
+			// Missing trailing fields are left as zero.
+			break
+		}
+		if len(s) < w {
+			w = len(s)
+		}
+		v, err := strconv.Atoi(s[:w])
+		if err != nil {
+			return errors.Trace(err)
+		}
+		*args[i] = v
+		s = s[w:]
+	}
+	return nil
+}
+
+func scanTimeArgs(seps []string, args ...*int) error {
+	if len(seps) != len(args) {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strings.Join(seps, "-")))
+	}
+
+	var err error
+	for i, s := range seps {
+		*args[i], err = strconv.Atoi(s)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func parseDatetime(sc *stmtctx.StatementContext, str string, fsp int8, isFloat bool) (Time, error) {
+	var (
+		year, month, day, hour, minute, second int
+		err                                    error
+		hhmmss                                 bool
+	)
+
+	seps, fracStr := splitDateTime(str)
+	switch len(seps) {
+	case 0:
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+	case 1:
+		l := len(seps[0])
+		// Values specified as numbers.
+		if isFloat {
+			numOfTime, err := StrToInt(sc, seps[0])
+			if err != nil {
+				return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+			}
+			dateTime, err := parseDateTimeFromNum(sc, numOfTime)
+			if err != nil {
+				return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+			}
+			year, month, day = dateTime.Year(), dateTime.Month(), dateTime.Day()
+			hour, minute, second = dateTime.Clock()
+			hhmmss = l >= 9 && l <= 14
+			break
+		}
+		// Values specified as strings.
+		switch l {
+		case 14: // YYYYMMDDHHMMSS
+			err = scanDigits(seps[0], []int{4, 2, 2, 2, 2, 2}, &year, &month, &day, &hour, &minute, &second)
+			hhmmss = true
+		case 12: // YYMMDDHHMMSS
+			err = scanDigits(seps[0], []int{2, 2, 2, 2, 2, 2}, &year, &month, &day, &hour, &minute, &second)
+			year = adjustYear(year)
+			hhmmss = true
+		case 11: // YYMMDDHHMMS
+			err = scanDigits(seps[0], []int{2, 2, 2, 2, 2, 1}, &year, &month, &day, &hour, &minute, &second)
+			year = adjustYear(year)
+			hhmmss = true
+		case 10: // YYMMDDHHMM
+			err = scanDigits(seps[0], []int{2, 2, 2, 2, 2}, &year, &month, &day, &hour, &minute)
+			year = adjustYear(year)
+		case 9: // YYMMDDHHM
+			err = scanDigits(seps[0], []int{2, 2, 2, 2, 1}, &year, &month, &day, &hour, &minute)
+			year = adjustYear(year)
+		case 8: // YYYYMMDD
+			err = scanDigits(seps[0], []int{4, 2, 2}, &year, &month, &day)
+		case 7: // YYMMDDH
+			err = scanDigits(seps[0], []int{2, 2, 2, 1}, &year, &month, &day, &hour)
+			year = adjustYear(year)
+		case 6, 5: // YYMMDD && YYMMD
+			err = scanDigits(seps[0], []int{2, 2, 2}, &year, &month, &day)
+			year = adjustYear(year)
+		default:
+			return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+		}
+		if err == nil && (l == 5 || l == 6 || l == 8) && len(fracStr) > 0 {
+			// '20170118.123423' is parsed to 2017-01-18 12:34:23.
+			hhmmssStr := fracStr
+			if len(hhmmssStr) > 6 {
+				hhmmssStr = hhmmssStr[:6]
+			}
+			err = scanDigits(hhmmssStr, []int{2, 2, 2}, &hour, &minute, &second)
+			fracStr = ""
+		}
+		if err == nil && (l == 9 || l == 10) && len(fracStr) > 0 {
+			secondStr := fracStr
+			if len(secondStr) > 2 {
+				secondStr = secondStr[:2]
+			}
+			second, err = strconv.Atoi(secondStr)
+			fracStr = ""
+		}
+		if err != nil {
+			return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+		}
+	case 2:
+		// YYYY-MM is not valid
+		if len(fracStr) == 0 {
+			return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+		}
+
+		// YYYY-MM.DD, DD is treat as fracStr
+		err = scanTimeArgs(append(seps, fracStr), &year, &month, &day)
+		fracStr = ""
+	case 3:
+		// YYYY-MM-DD
+		err = scanTimeArgs(seps, &year, &month, &day)
+	case 4:
+		// YYYY-MM-DD HH
+		err = scanTimeArgs(seps, &year, &month, &day, &hour)
+	case 5:
+		// YYYY-MM-DD HH-MM
+		err = scanTimeArgs(seps, &year, &month, &day, &hour, &minute)
+	case 6:
+		// We don't have fractional seconds part.
+		// YYYY-MM-DD HH-MM-SS
+		err = scanTimeArgs(seps, &year, &month, &day, &hour, &minute, &second)
+		hhmmss = true
+	default:
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+	}
+	if err != nil {
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+	}
+
+	// If str is sepereated by delimiters, the first one is year, and if the year is 2 digit,
+	// we should adjust it.
+	if len(seps) > 1 && len(seps[0]) == 2 {
+		if year == 0 && month == 0 && day == 0 && hour == 0 && minute == 0 && second == 0 && fracStr == "" {
+			// Skip a special case "00-00-00".
+		} else {
+			year = adjustYear(year)
+		}
+	}
+
+	var (
+		microsecond int
+		overflow    bool
+	)
+	if hhmmss {
+		// If input string is "20170118.999", without hhmmss, fsp is meaningless.
+		microsecond, overflow, err = ParseFrac(fracStr, fsp)
+		if err != nil {
+			return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+		}
+	}
+
+	tmp, ok := FromDateChecked(year, month, day, hour, minute, second, microsecond)
+	if !ok {
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+	}
+	if overflow {
+		// Convert to Go time and add 1 second, to handle input like 2017-01-05 08:40:59.575601
+		t1, err := tmp.GoTime(gotime.UTC)
+		if err != nil {
+			return ZeroDatetime, errors.Trace(err)
+		}
+		tmp = FromGoTime(t1.Add(gotime.Second))
+	}
+
+	return NewTime(tmp, mysql.TypeDatetime, fsp), nil
+}
+
+// ParseTime parses a formatted string with type tp and specific fsp.
+// Type is TypeDatetime, TypeTimestamp and TypeDate.
+// Fsp is in range [0, 6].
+// MySQL supports many valid datetime format, but still has some limitation.
+// If delimiter exists, the date part and time part is separated by a space or T,
+// other punctuation character can be used as the delimiter between date parts or time parts.
+// If no delimiter, the format must be YYYYMMDDhhmmss or YYMMDDhhmmss
+// If we have fractional seconds part, we must use decimal points as the delimiter.
+// The valid datetime range is from '1000-01-01 00:00:00.000000' to '9999-12-31 23:59:59.999999'.
+// The valid timestamp range is from '1970-01-01 00:00:01.000000' to '2038-01-19 03:14:07.999999'.
+// The valid date range is from '1000-01-01' to '9999-12-31'
+func ParseTime(sc *stmtctx.StatementContext, str string, tp byte, fsp int8) (Time, error) {
+	return parseTime(sc, str, tp, fsp, false)
+}
+
+// ParseTimeFromFloatString is similar to ParseTime, except that it's used to parse a float converted string.
+func ParseTimeFromFloatString(sc *stmtctx.StatementContext, str string, tp byte, fsp int8) (Time, error) {
+	// MySQL compatibility: 0.0 should not be converted to null, see #11203
+	if len(str) >= 3 && str[:3] == "0.0" {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), nil
+	}
+	return parseTime(sc, str, tp, fsp, true)
+}
+
+func parseTime(sc *stmtctx.StatementContext, str string, tp byte, fsp int8, isFloat bool) (Time, error) {
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+
+	t, err := parseDatetime(sc, str, fsp, isFloat)
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+
+	t.SetType(tp)
+	if err = t.Check(sc); err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+	return t, nil
+}
+
+// ParseDatetime is a helper function wrapping ParseTime with datetime type and default fsp.
+func ParseDatetime(sc *stmtctx.StatementContext, str string) (Time, error) {
+	return ParseTime(sc, str, mysql.TypeDatetime, GetFsp(str))
+}
+
+// ParseTimestamp is a helper function wrapping ParseTime with timestamp type and default fsp.
+func ParseTimestamp(sc *stmtctx.StatementContext, str string) (Time, error) {
+	return ParseTime(sc, str, mysql.TypeTimestamp, GetFsp(str))
+}
+
+// ParseDate is a helper function wrapping ParseTime with date type.
+func ParseDate(sc *stmtctx.StatementContext, str string) (Time, error) {
+	// date has no fractional seconds precision
+	return ParseTime(sc, str, mysql.TypeDate, MinFsp)
+}
+
+// GetFsp gets the fsp of a string.
+func GetFsp(s string) int8 {
+	index := getFracIndex(s)
+	var fsp int
+	if index < 0 {
+		fsp = 0
+	} else {
+		fsp = len(s) - index - 1
+	}
+
+	if fsp > int(MaxFsp) {
+		fsp = int(MaxFsp)
+	}
+	return int8(fsp)
+}
+
+// ParseTimeFromNum parses a formatted int64,
+// returns the value which type is tp.
+func ParseTimeFromNum(sc *stmtctx.StatementContext, num int64, tp byte, fsp int8) (Time, error) {
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, fsp), errors.Trace(err)
+	}
+
+	t, err := parseDateTimeFromNum(sc, num)
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, fsp), errors.Trace(err)
+	}
+
+	t.SetType(tp)
+	t.SetFsp(fsp)
+	if err := t.Check(sc); err != nil {
+		return NewTime(ZeroCoreTime, tp, fsp), errors.Trace(err)
+	}
+	return t, nil
+}
+
+// ParseDatetimeFromNum is a helper function wrapping ParseTimeFromNum with datetime type and default fsp.
+func ParseDatetimeFromNum(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	return ParseTimeFromNum(sc, num, mysql.TypeDatetime, DefaultFsp)
+}
+
+// ParseDateFromNum is a helper function wrapping ParseTimeFromNum with date type.
+func ParseDateFromNum(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	// date has no fractional seconds precision
+	return ParseTimeFromNum(sc, num, mysql.TypeDate, MinFsp)
+}
+
+func getTime(sc *stmtctx.StatementContext, num int64, tp byte) (Time, error) {
+	s1 := num / 1000000
+	s2 := num - s1*1000000
+
+	year := int(s1 / 10000)
+	s1 %= 10000
+	month := int(s1 / 100)
+	day := int(s1 % 100)
+
+	hour := int(s2 / 10000)
+	s2 %= 10000
+	minute := int(s2 / 100)
+	second := int(s2 % 100)
+
+	ct, ok := FromDateChecked(year, month, day, hour, minute, second, 0)
+	if !ok {
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.FormatInt(num, 10)))
+	}
+	t := NewTime(ct, tp, DefaultFsp)
+	err := t.Check(sc)
+	return t, errors.Trace(err)
+}
+
+// parseDateTimeFromNum parses date time from num.
+// See number_to_datetime function.
+// https://github.com/mysql/mysql-server/blob/5.7/sql-common/my_time.c
+func parseDateTimeFromNum(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	t := ZeroDate
+	// Check zero.
+	if num == 0 {
+		return t, nil
+	}
+
+	// Check datetime type.
+	if num >= 10000101000000 {
+		return getTime(sc, num, mysql.TypeDatetime)
+	}
+
+	// Check MMDD.
+	if num < 101 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Adjust year
+	// YYMMDD, year: 2000-2069
+	if num <= (70-1)*10000+1231 {
+		num = (num + 20000000) * 1000000
+		return getTime(sc, num, t.tp)
+	}
+
+	// Check YYMMDD.
+	if num < 70*10000+101 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Adjust year
+	// YYMMDD, year: 1970-1999
+	if num <= 991231 {
+		num = (num + 19000000) * 1000000
+		return getTime(sc, num, t.tp)
+	}
+
+	// Adjust hour/min/second.
+	if num <= 99991231 {
+		num = num * 1000000
+		return getTime(sc, num, t.tp)
+	}
+
+	// Check MMDDHHMMSS.
+	if num < 101000000 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Adjust year
+	// YYMMDDHHMMSS, 2000-2069
+	if num <= 69*10000000000+1231235959 {
+		num = num + 20000000000000
+		return getTime(sc, num, mysql.TypeDatetime)
+	}
+
+	// Check YYYYMMDDHHMMSS.
+	if num < 70*10000000000+101000000 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Adjust year
+	// YYMMDDHHMMSS, 1970-1999
+	if num <= 991231235959 {
+		num = num + 19000000000000
+	}
+	return getTime(sc, num, mysql.TypeDatetime)
+}
+
+// ExtractDatetimeNum extracts the part of a datetime specified by unit.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_extract
+func ExtractDatetimeNum(t *Time, unit string) (int64, error) {
+	switch strings.ToUpper(unit) {
+	case "DAY":
+		return int64(t.Day()), nil
+	case "WEEK":
+		return int64(t.Week(0)), nil
+	case "MONTH":
+		return int64(t.Month()), nil
+	case "QUARTER":
+		m := int64(t.Month())
+		// 1 - 3 -> 1
+		// 4 - 6 -> 2
+		// 7 - 9 -> 3
+		// 10 - 12 -> 4
+		return (m + 2) / 3, nil
+	case "YEAR":
+		return int64(t.Year()), nil
+	case "DAY_MICROSECOND":
+		h, m, s := t.Clock()
+		d := t.Day()
+		return int64(d*1000000+h*10000+m*100+s)*1000000 + int64(t.Microsecond()), nil
+	case "DAY_SECOND":
+		h, m, s := t.Clock()
+		d := t.Day()
+		return int64(d)*1000000 + int64(h)*10000 + int64(m)*100 + int64(s), nil
+	case "DAY_MINUTE":
+		h, m, _ := t.Clock()
+		d := t.Day()
+		return int64(d)*10000 + int64(h)*100 + int64(m), nil
+	case "DAY_HOUR":
+		h, _, _ := t.Clock()
+		d := t.Day()
+		return int64(d)*100 + int64(h), nil
+	case "YEAR_MONTH":
+		y, m := t.Year(), t.Month()
+		return int64(y)*100 + int64(m), nil
+	default:
+		return 0, errors.Errorf("invalid unit %s", unit)
+	}
+}
+
+// ExtractDurationNum extracts the part of a duration specified by unit.
+func ExtractDurationNum(d *Duration, unit string) (int64, error) {
+	var res int64
+	switch strings.ToUpper(unit) {
+	case "MICROSECOND":
+		res = int64(d.MicroSecond())
+	case "SECOND":
+		res = int64(d.Second())
+	case "MINUTE":
+		res = int64(d.Minute())
+	case "HOUR":
+		res = int64(d.Hour())
+	case "SECOND_MICROSECOND":
+		res = int64(d.Second())*1000000 + int64(d.MicroSecond())
+	case "MINUTE_MICROSECOND":
+		res = int64(d.Minute())*100000000 + int64(d.Second())*1000000 + int64(d.MicroSecond())
+	case "MINUTE_SECOND":
+		res = int64(d.Minute()*100 + d.Second())
+	case "HOUR_MICROSECOND":
+		res = int64(d.Hour())*10000000000 + int64(d.Minute())*100000000 + int64(d.Second())*1000000 + int64(d.MicroSecond())
+	case "HOUR_SECOND":
+		res = int64(d.Hour())*10000 + int64(d.Minute())*100 + int64(d.Second())
+	case "HOUR_MINUTE":
+		res = int64(d.Hour())*100 + int64(d.Minute())
+	default:
+		return 0, errors.Errorf("invalid unit %s", unit)
+	}
+	if d.Duration < 0 {
+		return -res, nil
+	}
+	return res, nil
+}
+
+// IsClockUnit returns true when unit is interval unit with hour, minute or second.
+func IsClockUnit(unit string) bool {
+	switch strings.ToUpper(unit) {
+	case "MICROSECOND", "SECOND", "MINUTE", "HOUR",
+		"SECOND_MICROSECOND", "MINUTE_MICROSECOND", "MINUTE_SECOND",
+		"HOUR_MICROSECOND", "HOUR_SECOND", "HOUR_MINUTE",
+		"DAY_MICROSECOND", "DAY_SECOND", "DAY_MINUTE", "DAY_HOUR":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsDateUnit returns true when unit is interval unit with year, quarter, month, week or day.
+func IsDateUnit(unit string) bool {
+	switch strings.ToUpper(unit) {
+	case "DAY", "WEEK", "MONTH", "QUARTER", "YEAR",
+		"DAY_MICROSECOND", "DAY_SECOND", "DAY_MINUTE", "DAY_HOUR",
+		"YEAR_MONTH":
+		return true
+	default:
+		return false
+	}
+}
+
+// compoundTimeUnits lists the parts of every compound interval unit, from
+// the most significant to the least significant one.
+var compoundTimeUnits = map[string][]string{
+	"SECOND_MICROSECOND": {"SECOND", "MICROSECOND"},
+	"MINUTE_MICROSECOND": {"MINUTE", "SECOND", "MICROSECOND"},
+	"MINUTE_SECOND":      {"MINUTE", "SECOND"},
+	"HOUR_MICROSECOND":   {"HOUR", "MINUTE", "SECOND", "MICROSECOND"},
+	"HOUR_SECOND":        {"HOUR", "MINUTE", "SECOND"},
+	"HOUR_MINUTE":        {"HOUR", "MINUTE"},
+	"DAY_MICROSECOND":    {"DAY", "HOUR", "MINUTE", "SECOND", "MICROSECOND"},
+	"DAY_SECOND":         {"DAY", "HOUR", "MINUTE", "SECOND"},
+	"DAY_MINUTE":         {"DAY", "HOUR", "MINUTE"},
+	"DAY_HOUR":           {"DAY", "HOUR"},
+	"YEAR_MONTH":         {"YEAR", "MONTH"},
+}
+
+const microsecondsPerDay = 24 * 3600 * 1000000
+
+// intervalValue accumulates the parts of an interval. Clock parts are
+// normalized into days and microseconds, so that large intervals do not
+// overflow gotime.Duration.
+type intervalValue struct {
+	years, months, days, micros int64
+}
+
+func (iv *intervalValue) add(unit string, v int64) {
+	switch unit {
+	case "YEAR":
+		iv.years += v
+	case "QUARTER":
+		iv.months += 3 * v
+	case "MONTH":
+		iv.months += v
+	case "WEEK":
+		iv.days += 7 * v
+	case "DAY":
+		iv.days += v
+	case "HOUR":
+		iv.days += v / 24
+		iv.micros += (v % 24) * 3600 * 1000000
+	case "MINUTE":
+		iv.days += v / (24 * 60)
+		iv.micros += (v % (24 * 60)) * 60 * 1000000
+	case "SECOND":
+		iv.days += v / (24 * 3600)
+		iv.micros += (v % (24 * 3600)) * 1000000
+	case "MICROSECOND":
+		iv.days += v / microsecondsPerDay
+		iv.micros += v % microsecondsPerDay
+	}
+}
+
+// ParseDurationValue parses the interval expression of DATE_ADD and DATE_SUB
+// with the given unit, and returns the years, months, days and the clock part
+// to add.
+// See https://dev.mysql.com/doc/refman/5.7/en/expressions.html#temporal-intervals
+func ParseDurationValue(unit string, format string) (y int64, m int64, d int64, dur gotime.Duration, err error) {
+	unit = strings.ToUpper(unit)
+	format = strings.TrimSpace(format)
+	var iv intervalValue
+	if parts, ok := compoundTimeUnits[unit]; ok {
+		neg := strings.HasPrefix(format, "-")
+		if neg {
+			format = format[1:]
+		}
+		fields := strings.FieldsFunc(format, func(c rune) bool { return !unicode.IsDigit(c) })
+		if len(fields) > len(parts) {
+			return 0, 0, 0, 0, ErrWrongValue.GenWithStackByArgs(DateTimeStr, format)
+		}
+		// If the value is too short, the leftmost parts are omitted.
+		offset := len(parts) - len(fields)
+		for i, field := range fields {
+			v, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return 0, 0, 0, 0, ErrWrongValue.GenWithStackByArgs(DateTimeStr, format)
+			}
+			part := parts[offset+i]
+			if part == "MICROSECOND" && len(field) < 6 {
+				// The microsecond part is a fraction, '1.5' SECOND_MICROSECOND means 1.5 seconds.
+				v *= int64(math.Pow10(6 - len(field)))
+			}
+			if neg {
+				v = -v
+			}
+			iv.add(part, v)
+		}
+	} else {
+		switch unit {
+		case "MICROSECOND", "SECOND", "MINUTE", "HOUR", "DAY", "WEEK", "MONTH", "QUARTER", "YEAR":
+		default:
+			return 0, 0, 0, 0, errors.Errorf("invalid time unit %s", unit)
+		}
+		fv, err := strconv.ParseFloat(format, 64)
+		if err != nil {
+			return 0, 0, 0, 0, ErrWrongValue.GenWithStackByArgs(DateTimeStr, format)
+		}
+		if unit == "SECOND" {
+			// The fractional part of seconds is kept as microseconds.
+			sec := math.Trunc(fv)
+			iv.add("SECOND", int64(sec))
+			iv.add("MICROSECOND", int64(math.Round((fv-sec)*1000000)))
+		} else {
+			iv.add(unit, int64(math.Round(fv)))
+		}
+	}
+	return iv.years, iv.months, iv.days, gotime.Duration(iv.micros) * gotime.Microsecond, nil
+}
@@ -0,0 +1,458 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+)
+
+var _ = Suite(&testTimeSuite{})
+
+type testTimeSuite struct{}
+
+func (s *testTimeSuite) TestDateTime(c *C) {
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input  string
+		Expect string
+	}{
+		{"2012-12-31 11:30:45", "2012-12-31 11:30:45"},
+		{"0000-00-00 00:00:00", "0000-00-00 00:00:00"},
+		{"0001-01-01 00:00:00", "0001-01-01 00:00:00"},
+		{"00-12-31 11:30:45", "2000-12-31 11:30:45"},
+		{"12-12-31 11:30:45", "2012-12-31 11:30:45"},
+		{"2012-12-31", "2012-12-31 00:00:00"},
+		{"20121231", "2012-12-31 00:00:00"},
+		{"121231", "2012-12-31 00:00:00"},
+		{"2012^12^31 11+30+45", "2012-12-31 11:30:45"},
+		{"2012^12^31T11+30+45", "2012-12-31 11:30:45"},
+		{"2012-2-1 11:30:45", "2012-02-01 11:30:45"},
+		{"12-2-1 11:30:45", "2012-02-01 11:30:45"},
+		{"20121231113045", "2012-12-31 11:30:45"},
+		{"121231113045", "2012-12-31 11:30:45"},
+		{"2012-02-29", "2012-02-29 00:00:00"},
+	}
+
+	for _, test := range table {
+		t, err := ParseDatetime(sc, test.Input)
+		c.Assert(err, IsNil, Commentf("%s", test.Input))
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	fspTbl := []struct {
+		Input  string
+		Fsp    int8
+		Expect string
+	}{
+		{"20170118.123", 6, "2017-01-18 12:03:00.000000"},
+		{"121231113045.123345", 6, "2012-12-31 11:30:45.123345"},
+		{"20121231113045.123345", 6, "2012-12-31 11:30:45.123345"},
+		{"121231113045.9999999", 6, "2012-12-31 11:30:46.000000"},
+		{"2012-12-31 11:30:45.1234567", 6, "2012-12-31 11:30:45.123457"},
+		{"2017-01-05 08:40:59.575601", 0, "2017-01-05 08:41:00"},
+		{"2017-01-05 23:59:59.575601", 0, "2017-01-06 00:00:00"},
+	}
+
+	for _, test := range fspTbl {
+		t, err := ParseTime(sc, test.Input, mysql.TypeDatetime, test.Fsp)
+		c.Assert(err, IsNil)
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	errTable := []string{
+		"1000-01-01 00:00:70",
+		"1000-13-00 00:00:00",
+		"2012-02-30 00:00:00",
+		"2012-12",
+		"2012-04-31 11:30:45",
+		"abcd",
+	}
+
+	for _, test := range errTable {
+		_, err := ParseDatetime(sc, test)
+		c.Assert(err, NotNil, Commentf("%s", test))
+	}
+}
+
+func (s *testTimeSuite) TestTimestamp(c *C) {
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	t, err := ParseTimestamp(sc, "2012-12-31 11:30:45")
+	c.Assert(err, IsNil)
+	c.Assert(t.String(), Equals, "2012-12-31 11:30:45")
+	c.Assert(t.Type(), Equals, mysql.TypeTimestamp)
+
+	for _, str := range []string{"2038-01-19 03:14:08", "1970-01-01 00:00:00", "1969-12-31 23:59:59"} {
+		_, err = ParseTimestamp(sc, str)
+		c.Assert(err, NotNil, Commentf("%s", str))
+	}
+
+	// The timestamp range depends on the session time zone.
+	sc.TimeZone = time.FixedZone("UTC+8", 8*3600)
+	_, err = ParseTimestamp(sc, "1970-01-01 08:00:01")
+	c.Assert(err, IsNil)
+	_, err = ParseTimestamp(sc, "1970-01-01 01:00:00")
+	c.Assert(err, NotNil)
+}
+
+func (s *testTimeSuite) TestDate(c *C) {
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input  string
+		Expect string
+	}{
+		{"2012-12-31", "2012-12-31"},
+		{"00-12-31", "2000-12-31"},
+		{"20121231", "2012-12-31"},
+		{"121231", "2012-12-31"},
+		{"2015-06-01 12:12:12", "2015-06-01"},
+		{"0001-01-01 00:00:00", "0001-01-01"},
+		{"2012.12.31", "2012-12-31"},
+	}
+
+	for _, test := range table {
+		t, err := ParseDate(sc, test.Input)
+		c.Assert(err, IsNil)
+		c.Assert(t.String(), Equals, test.Expect)
+		c.Assert(t.Hour(), Equals, 0)
+	}
+}
+
+func (s *testTimeSuite) TestParseTimeFromNum(c *C) {
+	table := []struct {
+		Input                int64
+		ExpectDateTimeError  bool
+		ExpectDateTimeValue  string
+		ExpectTimeStampError bool
+		ExpectTimeStampValue string
+		ExpectDateError      bool
+		ExpectDateValue      string
+	}{
+		{20101010111111, false, "2010-10-10 11:11:11", false, "2010-10-10 11:11:11", false, "2010-10-10"},
+		{2010101011111, false, "0201-01-01 01:11:11", true, ZeroDatetimeStr, false, "0201-01-01"},
+		{201010101111, false, "2020-10-10 10:11:11", false, "2020-10-10 10:11:11", false, "2020-10-10"},
+		{20101010111, false, "2002-01-01 01:01:11", false, "2002-01-01 01:01:11", false, "2002-01-01"},
+		{1111, false, "2000-11-11 00:00:00", false, "2000-11-11 00:00:00", false, "2000-11-11"},
+		{0, false, ZeroDatetimeStr, false, ZeroDatetimeStr, false, ZeroDateStr},
+		{100, true, ZeroDatetimeStr, true, ZeroDatetimeStr, true, ZeroDateStr},
+		{20121231, false, "2012-12-31 00:00:00", false, "2012-12-31 00:00:00", false, "2012-12-31"},
+	}
+
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	for ith, test := range table {
+		t, err := ParseDatetimeFromNum(sc, test.Input)
+		if test.ExpectDateTimeError {
+			c.Assert(err, NotNil, Commentf("%d", ith))
+		} else {
+			c.Assert(err, IsNil)
+			c.Assert(t.Type(), Equals, mysql.TypeDatetime)
+		}
+		c.Assert(t.String(), Equals, test.ExpectDateTimeValue)
+
+		t, err = ParseTimeFromNum(sc, test.Input, mysql.TypeTimestamp, DefaultFsp)
+		if test.ExpectTimeStampError {
+			c.Assert(err, NotNil)
+		} else {
+			c.Assert(err, IsNil, Commentf("%d", ith))
+			c.Assert(t.Type(), Equals, mysql.TypeTimestamp)
+		}
+		c.Assert(t.String(), Equals, test.ExpectTimeStampValue)
+
+		t, err = ParseDateFromNum(sc, test.Input)
+		if test.ExpectDateError {
+			c.Assert(err, NotNil)
+		} else {
+			c.Assert(err, IsNil)
+			c.Assert(t.Type(), Equals, mysql.TypeDate)
+		}
+		c.Assert(t.String(), Equals, test.ExpectDateValue)
+	}
+}
+
+func (s *testTimeSuite) TestDuration(c *C) {
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input  string
+		Fsp    int8
+		Expect string
+	}{
+		{"10:11:12", 0, "10:11:12"},
+		{"101112", 0, "10:11:12"},
+		{"10:11", 0, "10:11:00"},
+		{"101112.123456", 6, "10:11:12.123456"},
+		{"1112", 0, "00:11:12"},
+		{"12", 0, "00:00:12"},
+		{"1 12", 0, "36:00:00"},
+		{"1 10:11:12", 0, "34:11:12"},
+		{"-1 10:11:12.123456", 6, "-34:11:12.123456"},
+		{"10:11:12.9", 0, "10:11:13"},
+		{"838:59:59", 0, "838:59:59"},
+		{"-10:11:12", 0, "-10:11:12"},
+		{"2011-11-11 00:00:01", 0, "00:00:01"},
+		{"20111111000001.12", 2, "00:00:01.12"},
+	}
+
+	for _, test := range table {
+		t, err := ParseDuration(sc, test.Input, test.Fsp)
+		c.Assert(err, IsNil, Commentf("%s", test.Input))
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	errTable := []string{
+		"2011-11-11",
+		"232 10",
+		"-232 10",
+		"10:60:12",
+		"1:2:3:4",
+	}
+
+	for _, test := range errTable {
+		_, err := ParseDuration(sc, test, DefaultFsp)
+		c.Assert(err, NotNil, Commentf("%s", test))
+	}
+
+	dur, err := ParseDuration(sc, "10:11:12.345", 3)
+	c.Assert(err, IsNil)
+	c.Assert(dur.ToNumber().String(), Equals, "101112.345")
+	c.Assert(dur.Hour(), Equals, 10)
+	c.Assert(dur.Minute(), Equals, 11)
+	c.Assert(dur.Second(), Equals, 12)
+	c.Assert(dur.MicroSecond(), Equals, 345000)
+	dur, err = dur.RoundFrac(1)
+	c.Assert(err, IsNil)
+	c.Assert(dur.String(), Equals, "10:11:12.3")
+
+	dur, err = NumberToDuration(101112, 0)
+	c.Assert(err, IsNil)
+	c.Assert(dur.String(), Equals, "10:11:12")
+	_, err = NumberToDuration(106012, 0)
+	c.Assert(err, NotNil)
+}
+
+func (s *testTimeSuite) TestCodec(c *C) {
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	t, err := ParseTimestamp(sc, "2010-10-10 10:11:11")
+	c.Assert(err, IsNil)
+	packed, err := t.ToPackedUint()
+	c.Assert(err, IsNil)
+
+	var t2 Time
+	t2.SetType(mysql.TypeTimestamp)
+	err = t2.FromPackedUint(packed)
+	c.Assert(err, IsNil)
+	c.Assert(t.String(), Equals, t2.String())
+
+	packed, _ = ZeroDatetime.ToPackedUint()
+	c.Assert(packed, Equals, uint64(0))
+	var t3 Time
+	err = t3.FromPackedUint(packed)
+	c.Assert(err, IsNil)
+	c.Assert(t3.IsZero(), IsTrue)
+
+	// The packed value keeps the order of time values.
+	prev := uint64(0)
+	for _, str := range []string{"0001-01-01 00:00:00", "2000-12-31 23:59:59.999999", "2001-01-01 00:00:00", "9999-12-31 23:59:59"} {
+		t, err = ParseTime(sc, str, mysql.TypeDatetime, MaxFsp)
+		c.Assert(err, IsNil)
+		packed, err = t.ToPackedUint()
+		c.Assert(err, IsNil)
+		c.Assert(packed, Greater, prev)
+		prev = packed
+
+		var t4 Time
+		t4.SetType(mysql.TypeDatetime)
+		t4.SetFsp(MaxFsp)
+		c.Assert(t4.FromPackedUint(packed), IsNil)
+		c.Assert(t4.Compare(t), Equals, 0)
+	}
+}
+
+func (s *testTimeSuite) TestCompare(c *C) {
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Arg1 string
+		Arg2 string
+		Ret  int
+	}{
+		{"2011-10-10 11:11:11", "2011-10-10 11:11:11", 0},
+		{"2011-10-10 11:11:11.123456", "2011-10-10 11:11:11.1", 1},
+		{"2011-10-10 11:11:11", "2011-10-10 11:11:11.123", -1},
+		{"0000-00-00 00:00:00", "2011-10-10 11:11:11", -1},
+		{"0000-00-00 00:00:00", "0000-00-00 00:00:00", 0},
+		{"2011-10-10 11:11:11", "2010-10-10 11:11:11", 1},
+	}
+
+	for _, test := range table {
+		v1, err := ParseTime(sc, test.Arg1, mysql.TypeDatetime, MaxFsp)
+		c.Assert(err, IsNil)
+
+		ret, err := v1.CompareString(sc, test.Arg2)
+		c.Assert(err, IsNil)
+		c.Assert(ret, Equals, test.Ret)
+	}
+
+	v1, err := ParseTime(sc, "2011-10-10 11:11:11", mysql.TypeDatetime, MaxFsp)
+	c.Assert(err, IsNil)
+	res, err := v1.CompareString(sc, "Test should error")
+	c.Assert(err, NotNil)
+	c.Assert(res, Equals, 0)
+
+	d1, err := ParseDuration(sc, "11:11:11", 0)
+	c.Assert(err, IsNil)
+	ret, err := d1.CompareString(sc, "11:11:11.5")
+	c.Assert(err, IsNil)
+	c.Assert(ret, Equals, -1)
+}
+
+func (s *testTimeSuite) TestConvert(c *C) {
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	t, err := ParseTime(sc, "2010-10-10 10:11:11.12345", mysql.TypeDatetime, 5)
+	c.Assert(err, IsNil)
+	c.Assert(t.ToNumber().String(), Equals, "20101010101111.12345")
+	dur, err := t.ConvertToDuration()
+	c.Assert(err, IsNil)
+	c.Assert(dur.String(), Equals, "10:11:11.12345")
+
+	t, err = t.Convert(sc, mysql.TypeDate)
+	c.Assert(err, IsNil)
+	c.Assert(t.String(), Equals, "2010-10-10")
+	c.Assert(t.ToNumber().String(), Equals, "20101010")
+
+	t, err = ParseTime(sc, "2012-12-31 11:30:45.999999", mysql.TypeDatetime, 6)
+	c.Assert(err, IsNil)
+	t, err = t.RoundFrac(2)
+	c.Assert(err, IsNil)
+	c.Assert(t.String(), Equals, "2012-12-31 11:30:46.00")
+
+	t, err = ParseTimestamp(sc, "2012-12-31 11:30:45")
+	c.Assert(err, IsNil)
+	err = t.ConvertTimeZone(time.UTC, time.FixedZone("UTC+8", 8*3600))
+	c.Assert(err, IsNil)
+	c.Assert(t.String(), Equals, "2012-12-31 19:30:45")
+}
+
+func (s *testTimeSuite) TestDateFormat(c *C) {
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	t, err := ParseTime(sc, "2010-01-07 23:12:34.12345", mysql.TypeDatetime, 6)
+	c.Assert(err, IsNil)
+	tests := []struct {
+		format string
+		expect string
+	}{
+		{"%b %M %m %c %D %d %e %j %k %h %i %p %r %T %s %f", "Jan January 01 1 7th 07 7 007 23 11 12 PM 11:12:34 PM 23:12:34 34 123450"},
+		{"%a %W %w %y %Y", "Thu Thursday 4 10 2010"},
+		{"%U %u %V %v %X %x", "01 01 01 01 2010 2010"},
+		{"%Y-%m-%d %H:%i:%s", "2010-01-07 23:12:34"},
+		{"%%%z", "%z"},
+	}
+	for _, tt := range tests {
+		str, err := t.DateFormat(tt.format)
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, tt.expect)
+	}
+
+	t, err = ParseDate(sc, "2020-01-01")
+	c.Assert(err, IsNil)
+	c.Assert(t.Weekday(), Equals, time.Wednesday)
+	c.Assert(t.YearDay(), Equals, 1)
+	t, err = ParseDate(sc, "2020-12-31")
+	c.Assert(err, IsNil)
+	c.Assert(t.YearDay(), Equals, 366)
+
+	c.Assert(IsDateFormat("2020-01-01"), IsTrue)
+	c.Assert(IsDateFormat("20200101"), IsTrue)
+	c.Assert(IsDateFormat("2020-01-01 10:00:00"), IsFalse)
+}
+
+func (s *testTimeSuite) TestParseDurationValue(c *C) {
+	tests := []struct {
+		unit   string
+		format string
+		y      int64
+		m      int64
+		d      int64
+		dur    time.Duration
+	}{
+		{"DAY", "10", 0, 0, 10, 0},
+		{"DAY", "1.6", 0, 0, 2, 0},
+		{"WEEK", "2", 0, 0, 14, 0},
+		{"QUARTER", "1", 0, 3, 0, 0},
+		{"YEAR_MONTH", "1-2", 1, 2, 0, 0},
+		{"YEAR_MONTH", "-1-2", -1, -2, 0, 0},
+		{"HOUR", "25", 0, 0, 1, time.Hour},
+		{"SECOND", "1.5", 0, 0, 0, 1500 * time.Millisecond},
+		{"DAY_SECOND", "1 01:02:03", 0, 0, 1, time.Hour + 2*time.Minute + 3*time.Second},
+		{"DAY_SECOND", "02:03", 0, 0, 0, 2*time.Minute + 3*time.Second},
+		{"SECOND_MICROSECOND", "1.5", 0, 0, 0, 1500 * time.Millisecond},
+		{"MINUTE_SECOND", "-1:30", 0, 0, 0, -90 * time.Second},
+	}
+	for _, tt := range tests {
+		y, m, d, dur, err := ParseDurationValue(tt.unit, tt.format)
+		c.Assert(err, IsNil)
+		c.Assert([]interface{}{y, m, d, dur}, DeepEquals, []interface{}{tt.y, tt.m, tt.d, tt.dur}, Commentf("%s %s", tt.unit, tt.format))
+	}
+	_, _, _, _, err := ParseDurationValue("DAY_SECOND", "1 2:3:4:5")
+	c.Assert(err, NotNil)
+	_, _, _, _, err = ParseDurationValue("DAY", "abc")
+	c.Assert(err, NotNil)
+}
+
+func (s *testTimeSuite) TestAddDate(c *C) {
+	ot := time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC)
+	c.Assert(AddDate(0, 1, 0, ot).Format(DateFormat), Equals, "2018-02-28")
+	c.Assert(AddDate(1, 1, 0, ot).Format(DateFormat), Equals, "2019-02-28")
+	c.Assert(AddDate(0, 0, 1, ot).Format(DateFormat), Equals, "2018-02-01")
+	ot = time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
+	c.Assert(AddDate(0, -1, 0, ot).Format(DateFormat), Equals, "2020-02-29")
+}
+
+func (s *testTimeSuite) TestExtract(c *C) {
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	t, err := ParseTime(sc, "2011-11-11 10:10:10.123456", mysql.TypeDatetime, 6)
+	c.Assert(err, IsNil)
+	tbl := []struct {
+		Unit   string
+		Expect int64
+	}{
+		{"DAY", 11},
+		{"WEEK", 45},
+		{"MONTH", 11},
+		{"QUARTER", 4},
+		{"YEAR", 2011},
+		{"DAY_MICROSECOND", 11101010123456},
+		{"DAY_SECOND", 11101010},
+		{"DAY_MINUTE", 111010},
+		{"DAY_HOUR", 1110},
+		{"YEAR_MONTH", 201111},
+	}
+	for _, tt := range tbl {
+		res, err := ExtractDatetimeNum(&t, tt.Unit)
+		c.Assert(err, IsNil)
+		c.Assert(res, Equals, tt.Expect)
+	}
+	_, err = ExtractDatetimeNum(&t, "TEST_ERROR")
+	c.Assert(err, NotNil)
+
+	dur, err := ParseDuration(sc, "-10:11:12.123456", 6)
+	c.Assert(err, IsNil)
+	res, err := ExtractDurationNum(&dur, "HOUR_SECOND")
+	c.Assert(err, IsNil)
+	c.Assert(res, Equals, int64(-101112))
+	res, err = ExtractDurationNum(&dur, "MICROSECOND")
+	c.Assert(err, IsNil)
+	c.Assert(res, Equals, int64(-123456))
+}
@@ -518,6 +518,30 @@ func (c *Chunk) AppendFloat64(colIdx int, f float64) {
 	c.columns[colIdx].AppendFloat64(f)
 }
 
+// AppendMyDecimal appends a MyDecimal value to the chunk.
+func (c *Chunk) AppendMyDecimal(colIdx int, dec *types.MyDecimal) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendMyDecimal(dec)
+}
+
+// AppendTime appends a Time value to the chunk.
+func (c *Chunk) AppendTime(colIdx int, t types.Time) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendTime(t)
+}
+
+// AppendDuration appends a Duration value to the chunk.
+func (c *Chunk) AppendDuration(colIdx int, dur types.Duration) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendDuration(dur)
+}
+
+// AppendJSON appends a JSON value to the chunk.
+func (c *Chunk) AppendJSON(colIdx int, j types.BinaryJSON) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendJSON(j)
+}
+
 // AppendString appends a string value to the chunk.
 func (c *Chunk) AppendString(colIdx int, str string) {
 	c.appendSel(colIdx)
@@ -551,6 +575,14 @@ func (c *Chunk) AppendDatum(colIdx int, d *types.Datum) {
 		c.AppendFloat64(colIdx, d.GetFloat64())
 	case types.KindString, types.KindBytes:
 		c.AppendBytes(colIdx, d.GetBytes())
+	case types.KindMysqlDecimal:
+		c.AppendMyDecimal(colIdx, d.GetMysqlDecimal())
+	case types.KindMysqlDuration:
+		c.AppendDuration(colIdx, d.GetMysqlDuration())
+	case types.KindMysqlTime:
+		c.AppendTime(colIdx, d.GetMysqlTime())
+	case types.KindMysqlJSON:
+		c.AppendJSON(colIdx, d.GetMysqlJSON())
 	}
 }
 
@@ -175,6 +175,10 @@ func getFixedLen(colType *types.FieldType) int {
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong,
 		mysql.TypeLonglong, mysql.TypeDouble, mysql.TypeYear, mysql.TypeDuration:
 		return 8
+	case mysql.TypeNewDecimal:
+		return types.MyDecimalStructSize
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		return sizeTime
 	default:
 		return varElemLen
 	}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"math/bits"
 	"reflect"
+	"time"
 	"unsafe"
 
 	"github.com/pingcap/tidb/types"
@@ -67,8 +68,16 @@ func (c *Column) Reset(eType types.EvalType) {
 		c.ResizeInt64(0, false)
 	case types.ETReal:
 		c.ResizeFloat64(0, false)
+	case types.ETDecimal:
+		c.ResizeDecimal(0, false)
+	case types.ETDatetime, types.ETTimestamp:
+		c.ResizeTime(0, false)
+	case types.ETDuration:
+		c.ResizeGoDuration(0, false)
 	case types.ETString:
 		c.ReserveString(0)
+	case types.ETJson:
+		c.ReserveJSON(0)
 	default:
 		panic(fmt.Sprintf("invalid EvalType %v", eType))
 	}
@@ -187,6 +196,23 @@ func (c *Column) AppendFloat64(f float64) {
 	c.finishAppendFixed()
 }
 
+// AppendMyDecimal appends a MyDecimal value into this Column.
+func (c *Column) AppendMyDecimal(dec *types.MyDecimal) {
+	*(*types.MyDecimal)(unsafe.Pointer(&c.elemBuf[0])) = *dec
+	c.finishAppendFixed()
+}
+
+// AppendTime appends a time value into this Column.
+func (c *Column) AppendTime(t types.Time) {
+	*(*types.Time)(unsafe.Pointer(&c.elemBuf[0])) = t
+	c.finishAppendFixed()
+}
+
+// AppendDuration appends a duration value into this Column.
+func (c *Column) AppendDuration(dur types.Duration) {
+	c.AppendInt64(int64(dur.Duration))
+}
+
 func (c *Column) finishAppendVar() {
 	c.appendNullBitmap(true)
 	c.offsets = append(c.offsets, int64(len(c.data)))
@@ -205,11 +231,21 @@ func (c *Column) AppendBytes(b []byte) {
 	c.finishAppendVar()
 }
 
+// AppendJSON appends a BinaryJSON value into this Column.
+func (c *Column) AppendJSON(j types.BinaryJSON) {
+	c.data = append(c.data, j.TypeCode)
+	c.data = append(c.data, j.Value...)
+	c.finishAppendVar()
+}
+
 const (
-	sizeInt64   = int(unsafe.Sizeof(int64(0)))
-	sizeUint64  = int(unsafe.Sizeof(uint64(0)))
-	sizeFloat32 = int(unsafe.Sizeof(float32(0)))
-	sizeFloat64 = int(unsafe.Sizeof(float64(0)))
+	sizeInt64      = int(unsafe.Sizeof(int64(0)))
+	sizeUint64     = int(unsafe.Sizeof(uint64(0)))
+	sizeFloat32    = int(unsafe.Sizeof(float32(0)))
+	sizeFloat64    = int(unsafe.Sizeof(float64(0)))
+	sizeMyDecimal  = types.MyDecimalStructSize
+	sizeGoDuration = int(unsafe.Sizeof(time.Duration(0)))
+	sizeTime       = int(unsafe.Sizeof(types.ZeroTime))
 )
 
 var (
@@ -347,6 +383,21 @@ func (c *Column) ResizeFloat64(n int, isNull bool) {
 	c.resize(n, sizeFloat64, isNull)
 }
 
+// ResizeDecimal resizes the column so that it contains n decimal elements.
+func (c *Column) ResizeDecimal(n int, isNull bool) {
+	c.resize(n, sizeMyDecimal, isNull)
+}
+
+// ResizeGoDuration resizes the column so that it contains n duration elements.
+func (c *Column) ResizeGoDuration(n int, isNull bool) {
+	c.resize(n, sizeGoDuration, isNull)
+}
+
+// ResizeTime resizes the column so that it contains n Time elements.
+func (c *Column) ResizeTime(n int, isNull bool) {
+	c.resize(n, sizeTime, isNull)
+}
+
 // ReserveString changes the column capacity to store n string elements and set the length to zero.
 func (c *Column) ReserveString(n int) {
 	c.reserve(n, 8)
@@ -357,6 +408,11 @@ func (c *Column) ReserveBytes(n int) {
 	c.reserve(n, 8)
 }
 
+// ReserveJSON changes the column capacity to store n JSON elements and set the length to zero.
+func (c *Column) ReserveJSON(n int) {
+	c.reserve(n, 8)
+}
+
 // ReserveSet changes the column capacity to store n set elements and set the length to zero.
 func (c *Column) ReserveSet(n int) {
 	c.reserve(n, 8)
@@ -401,6 +457,28 @@ func (c *Column) Float64s() []float64 {
 	return res
 }
 
+// Decimals returns a MyDecimal slice stored in this Column.
+func (c *Column) Decimals() []types.MyDecimal {
+	var res []types.MyDecimal
+	c.castSliceHeader((*reflect.SliceHeader)(unsafe.Pointer(&res)), sizeMyDecimal)
+	return res
+}
+
+// GoDurations returns a Golang time.Duration slice stored in this Column.
+// Different from the row.GetDuration, the argument Fsp is ignored, so the user should handle it outside.
+func (c *Column) GoDurations() []time.Duration {
+	var res []time.Duration
+	c.castSliceHeader((*reflect.SliceHeader)(unsafe.Pointer(&res)), sizeGoDuration)
+	return res
+}
+
+// Times returns a Time slice stored in this Column.
+func (c *Column) Times() []types.Time {
+	var res []types.Time
+	c.castSliceHeader((*reflect.SliceHeader)(unsafe.Pointer(&res)), sizeTime)
+	return res
+}
+
 // GetInt64 returns the int64 in the specific row.
 func (c *Column) GetInt64(rowID int) int64 {
 	return *(*int64)(unsafe.Pointer(&c.data[rowID*8]))
@@ -421,6 +499,22 @@ func (c *Column) GetFloat64(rowID int) float64 {
 	return *(*float64)(unsafe.Pointer(&c.data[rowID*8]))
 }
 
+// GetDecimal returns the decimal in the specific row.
+func (c *Column) GetDecimal(rowID int) *types.MyDecimal {
+	return (*types.MyDecimal)(unsafe.Pointer(&c.data[rowID*types.MyDecimalStructSize]))
+}
+
+// GetTime returns the Time in the specific row.
+func (c *Column) GetTime(rowID int) types.Time {
+	return *(*types.Time)(unsafe.Pointer(&c.data[rowID*sizeTime]))
+}
+
+// GetDuration returns the Duration in the specific row.
+func (c *Column) GetDuration(rowID int, fillFsp int) types.Duration {
+	dur := *(*int64)(unsafe.Pointer(&c.data[rowID*8]))
+	return types.Duration{Duration: time.Duration(dur), Fsp: int8(fillFsp)}
+}
+
 // GetString returns the string in the specific row.
 func (c *Column) GetString(rowID int) string {
 	return string(hack.String(c.data[c.offsets[rowID]:c.offsets[rowID+1]]))
@@ -431,6 +525,12 @@ func (c *Column) GetBytes(rowID int) []byte {
 	return c.data[c.offsets[rowID]:c.offsets[rowID+1]]
 }
 
+// GetJSON returns the JSON in the specific row.
+func (c *Column) GetJSON(rowID int) types.BinaryJSON {
+	start := c.offsets[rowID]
+	return types.BinaryJSON{TypeCode: c.data[start], Value: c.data[start+1 : c.offsets[rowID+1]]}
+}
+
 // GetRaw returns the underlying raw bytes in the specific row.
 func (c *Column) GetRaw(rowID int) []byte {
 	var data []byte
@@ -35,6 +35,14 @@ func GetCompareFunc(tp *types.FieldType) CompareFunc {
 		return cmpFloat32
 	case mysql.TypeDouble:
 		return cmpFloat64
+	case mysql.TypeNewDecimal:
+		return cmpMyDecimal
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		return cmpTime
+	case mysql.TypeDuration:
+		return cmpDuration
+	case mysql.TypeJSON:
+		return cmpJSON
 	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
 		mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		return cmpString
@@ -92,6 +100,42 @@ func cmpFloat64(l Row, lCol int, r Row, rCol int) int {
 	return types.CompareFloat64(l.GetFloat64(lCol), r.GetFloat64(rCol))
 }
 
+func cmpMyDecimal(l Row, lCol int, r Row, rCol int) int {
+	lNull, rNull := l.IsNull(lCol), r.IsNull(rCol)
+	if lNull || rNull {
+		return cmpNull(lNull, rNull)
+	}
+	lDec, rDec := l.GetMyDecimal(lCol), r.GetMyDecimal(rCol)
+	return lDec.Compare(rDec)
+}
+
+func cmpTime(l Row, lCol int, r Row, rCol int) int {
+	lNull, rNull := l.IsNull(lCol), r.IsNull(rCol)
+	if lNull || rNull {
+		return cmpNull(lNull, rNull)
+	}
+	lTime, rTime := l.GetTime(lCol), r.GetTime(rCol)
+	return lTime.Compare(rTime)
+}
+
+func cmpDuration(l Row, lCol int, r Row, rCol int) int {
+	lNull, rNull := l.IsNull(lCol), r.IsNull(rCol)
+	if lNull || rNull {
+		return cmpNull(lNull, rNull)
+	}
+	lDur, rDur := l.GetDuration(lCol, 0).Duration, r.GetDuration(rCol, 0).Duration
+	return types.CompareInt64(int64(lDur), int64(rDur))
+}
+
+func cmpJSON(l Row, lCol int, r Row, rCol int) int {
+	lNull, rNull := l.IsNull(lCol), r.IsNull(rCol)
+	if lNull || rNull {
+		return cmpNull(lNull, rNull)
+	}
+	lJ, rJ := l.GetJSON(lCol), r.GetJSON(rCol)
+	return types.CompareBinaryJSON(lJ, rJ)
+}
+
 // Compare compares the value with ad.
 func Compare(row Row, colIdx int, ad *types.Datum) int {
 	switch ad.Kind() {
@@ -115,8 +159,20 @@ func Compare(row Row, colIdx int, ad *types.Datum) int {
 		return types.CompareFloat64(float64(row.GetFloat32(colIdx)), float64(ad.GetFloat32()))
 	case types.KindFloat64:
 		return types.CompareFloat64(row.GetFloat64(colIdx), ad.GetFloat64())
+	case types.KindMysqlDecimal:
+		l, r := row.GetMyDecimal(colIdx), ad.GetMysqlDecimal()
+		return l.Compare(r)
+	case types.KindMysqlDuration:
+		l, r := row.GetDuration(colIdx, 0).Duration, ad.GetMysqlDuration().Duration
+		return types.CompareInt64(int64(l), int64(r))
+	case types.KindMysqlTime:
+		l, r := row.GetTime(colIdx), ad.GetMysqlTime()
+		return l.Compare(r)
 	case types.KindString, types.KindBytes:
 		return types.CompareString(row.GetString(colIdx), ad.GetString())
+	case types.KindMysqlJSON:
+		l, r := row.GetJSON(colIdx), ad.GetMysqlJSON()
+		return types.CompareBinaryJSON(l, r)
 	default:
 		return 0
 	}
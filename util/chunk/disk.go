@@ -0,0 +1,129 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chunk
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/types"
+)
+
+const (
+	writeBufSize = 128 * 1024
+	// defaultChunkListInDiskPrefix is the name prefix of the temporary files.
+	defaultChunkListInDiskPrefix = "chunk.ListInDisk"
+)
+
+// ListInDisk represents a slice of chunks storing in temporary disk.
+// Chunks are encoded by Codec and appended to a temporary file one after
+// another, so a chunk can be read back by its index.
+type ListInDisk struct {
+	fieldTypes []*types.FieldType
+	codec      *Codec
+	// offsets stores the offsets in disk of all chunks,
+	// offsets[i] is the beginning of the i-th chunk.
+	offsets   []int64
+	numRows   []int
+	length    int
+	offWrite  int64
+	disk      *os.File
+	bufWriter *bufio.Writer
+}
+
+// NewListInDisk creates a new ListInDisk with field types.
+func NewListInDisk(fieldTypes []*types.FieldType) *ListInDisk {
+	return &ListInDisk{
+		fieldTypes: fieldTypes,
+		codec:      NewCodec(fieldTypes),
+	}
+}
+
+func (l *ListInDisk) initDiskFile() (err error) {
+	l.disk, err = ioutil.TempFile("", defaultChunkListInDiskPrefix)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	l.bufWriter = bufio.NewWriterSize(l.disk, writeBufSize)
+	return nil
+}
+
+// Len returns the number of rows in ListInDisk.
+func (l *ListInDisk) Len() int {
+	return l.length
+}
+
+// NumChunks returns the number of chunks in ListInDisk.
+func (l *ListInDisk) NumChunks() int {
+	return len(l.offsets)
+}
+
+// NumRowsOfChunk returns the number of rows of the chunk at chkIdx.
+func (l *ListInDisk) NumRowsOfChunk(chkIdx int) int {
+	return l.numRows[chkIdx]
+}
+
+// Add adds a chunk to the ListInDisk. Caller must make sure the input chk
+// is not empty and has the same field types.
+func (l *ListInDisk) Add(chk *Chunk) (err error) {
+	if chk.NumRows() == 0 {
+		return errors.New("chunk appended to ListInDisk should have at least 1 row")
+	}
+	if l.disk == nil {
+		if err = l.initDiskFile(); err != nil {
+			return err
+		}
+	}
+	data := l.codec.Encode(chk)
+	if _, err = l.bufWriter.Write(data); err != nil {
+		return errors.Trace(err)
+	}
+	l.offsets = append(l.offsets, l.offWrite)
+	l.numRows = append(l.numRows, chk.NumRows())
+	l.offWrite += int64(len(data))
+	l.length += chk.NumRows()
+	return nil
+}
+
+// GetChunk gets a Chunk from the ListInDisk by chkIdx.
+func (l *ListInDisk) GetChunk(chkIdx int) (*Chunk, error) {
+	if err := l.bufWriter.Flush(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	end := l.offWrite
+	if chkIdx+1 < len(l.offsets) {
+		end = l.offsets[chkIdx+1]
+	}
+	buf := make([]byte, end-l.offsets[chkIdx])
+	if _, err := l.disk.ReadAt(buf, l.offsets[chkIdx]); err != nil {
+		return nil, errors.Trace(err)
+	}
+	chk, _ := l.codec.Decode(buf)
+	return chk, nil
+}
+
+// Close releases the disk resource.
+func (l *ListInDisk) Close() error {
+	if l.disk == nil {
+		return nil
+	}
+	name := l.disk.Name()
+	if err := l.disk.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	l.disk = nil
+	return errors.Trace(os.Remove(name))
+}
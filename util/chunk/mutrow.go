@@ -82,6 +82,18 @@ func zeroValForType(tp *types.FieldType) interface{} {
 		return ""
 	case mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		return []byte{}
+	case mysql.TypeNewDecimal:
+		return types.NewDecFromInt(0)
+	case mysql.TypeDate:
+		return types.ZeroDate
+	case mysql.TypeDatetime:
+		return types.ZeroDatetime
+	case mysql.TypeTimestamp:
+		return types.ZeroTimestamp
+	case mysql.TypeDuration:
+		return types.ZeroDuration
+	case mysql.TypeJSON:
+		return types.CreateBinaryJSON(nil)
 	default:
 		return nil
 	}
@@ -109,6 +121,21 @@ func makeMutRowColumn(in interface{}) *Column {
 		return makeMutRowBytesColumn(hack.Slice(x))
 	case []byte:
 		return makeMutRowBytesColumn(x)
+	case *types.MyDecimal:
+		col := newMutRowFixedLenColumn(types.MyDecimalStructSize)
+		*(*types.MyDecimal)(unsafe.Pointer(&col.data[0])) = *x
+		return col
+	case types.Time:
+		col := newMutRowFixedLenColumn(sizeTime)
+		*(*types.Time)(unsafe.Pointer(&col.data[0])) = x
+		return col
+	case types.Duration:
+		return makeMutRowUint64Column(uint64(x.Duration))
+	case types.BinaryJSON:
+		col := newMutRowVarLenColumn(len(x.Value) + 1)
+		col.data[0] = x.TypeCode
+		copy(col.data[1:], x.Value)
+		return col
 	default:
 		return nil
 	}
@@ -198,6 +225,14 @@ func (mr MutRow) SetValue(colIdx int, val interface{}) {
 		setMutRowBytes(col, hack.Slice(x))
 	case []byte:
 		setMutRowBytes(col, x)
+	case *types.MyDecimal:
+		*(*types.MyDecimal)(unsafe.Pointer(&col.data[0])) = *x
+	case types.Time:
+		*(*types.Time)(unsafe.Pointer(&col.data[0])) = x
+	case types.Duration:
+		binary.LittleEndian.PutUint64(col.data, uint64(x.Duration))
+	case types.BinaryJSON:
+		setMutRowJSON(col, x)
 	}
 	col.nullBitmap[0] = 1
 }
@@ -223,6 +258,14 @@ func (mr MutRow) SetDatum(colIdx int, d types.Datum) {
 		binary.LittleEndian.PutUint32(mr.c.columns[colIdx].data, math.Float32bits(d.GetFloat32()))
 	case types.KindString, types.KindBytes:
 		setMutRowBytes(col, d.GetBytes())
+	case types.KindMysqlDecimal:
+		*(*types.MyDecimal)(unsafe.Pointer(&col.data[0])) = *d.GetMysqlDecimal()
+	case types.KindMysqlTime:
+		*(*types.Time)(unsafe.Pointer(&col.data[0])) = d.GetMysqlTime()
+	case types.KindMysqlDuration:
+		binary.LittleEndian.PutUint64(col.data, uint64(d.GetInt64()))
+	case types.KindMysqlJSON:
+		setMutRowJSON(col, d.GetMysqlJSON())
 	default:
 		mr.c.columns[colIdx] = makeMutRowColumn(d.GetValue())
 	}
@@ -241,6 +284,22 @@ func setMutRowBytes(col *Column, bin []byte) {
 	col.offsets[1] = int64(len(bin))
 }
 
+func setMutRowJSON(col *Column, j types.BinaryJSON) {
+	dataLen := len(j.Value) + 1
+	if len(col.data) >= dataLen {
+		col.data = col.data[:dataLen]
+	} else {
+		// In MutRow, there always exists 1 data in every Column,
+		// we should allocate one more byte for null bitmap.
+		buf := make([]byte, dataLen+1)
+		col.data = buf[:dataLen]
+		col.nullBitmap = buf[dataLen:]
+	}
+	col.data[0] = j.TypeCode
+	copy(col.data[1:], j.Value)
+	col.offsets[1] = int64(dataLen)
+}
+
 // ShallowCopyPartialRow shallow copies the data of `row` to MutRow.
 func (mr MutRow) ShallowCopyPartialRow(colIdx int, row Row) {
 	for i, srcCol := range row.c.columns {
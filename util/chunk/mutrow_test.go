@@ -63,6 +63,7 @@ var allTypes = []*types.FieldType{
 		Elems:   []string{"a", "b"},
 	},
 	types.NewFieldType(mysql.TypeBit),
+	types.NewFieldType(mysql.TypeJSON),
 }
 
 func (s *testChunkSuite) TestMutRow(c *check.C) {
@@ -64,6 +64,26 @@ func (r Row) GetFloat64(colIdx int) float64 {
 	return r.c.columns[colIdx].GetFloat64(r.idx)
 }
 
+// GetMyDecimal returns the MyDecimal value with the colIdx.
+func (r Row) GetMyDecimal(colIdx int) *types.MyDecimal {
+	return r.c.columns[colIdx].GetDecimal(r.idx)
+}
+
+// GetTime returns the Time value with the colIdx.
+func (r Row) GetTime(colIdx int) types.Time {
+	return r.c.columns[colIdx].GetTime(r.idx)
+}
+
+// GetDuration returns the Duration value with the colIdx.
+func (r Row) GetDuration(colIdx int, fillFsp int) types.Duration {
+	return r.c.columns[colIdx].GetDuration(r.idx, fillFsp)
+}
+
+// GetJSON returns the JSON value with the colIdx.
+func (r Row) GetJSON(colIdx int) types.BinaryJSON {
+	return r.c.columns[colIdx].GetJSON(r.idx)
+}
+
 // GetString returns the string value with the colIdx.
 func (r Row) GetString(colIdx int) string {
 	return r.c.columns[colIdx].GetString(r.idx)
@@ -116,6 +136,34 @@ func (r Row) GetDatum(colIdx int, tp *types.FieldType) types.Datum {
 		if !r.IsNull(colIdx) {
 			d.SetBytes(r.GetBytes(colIdx))
 		}
+	case mysql.TypeNewDecimal:
+		if !r.IsNull(colIdx) {
+			dec := r.GetMyDecimal(colIdx)
+			d.SetMysqlDecimal(dec)
+			if tp.Flen != types.UnspecifiedLength {
+				d.SetLength(tp.Flen)
+			}
+			// If tp.Decimal is unspecified, use the real fraction length of the decimal value,
+			// otherwise the frac would be MAX_UINT16 and the encoding of it fails.
+			if tp.Decimal == types.UnspecifiedLength {
+				d.SetFrac(int(dec.GetDigitsFrac()))
+			} else {
+				d.SetFrac(tp.Decimal)
+			}
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		if !r.IsNull(colIdx) {
+			d.SetMysqlTime(r.GetTime(colIdx))
+		}
+	case mysql.TypeDuration:
+		if !r.IsNull(colIdx) {
+			duration := r.GetDuration(colIdx, tp.Decimal)
+			d.SetMysqlDuration(duration)
+		}
+	case mysql.TypeJSON:
+		if !r.IsNull(colIdx) {
+			d.SetMysqlJSON(r.GetJSON(colIdx))
+		}
 	}
 	return d
 }
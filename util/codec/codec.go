@@ -24,6 +24,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -84,6 +85,34 @@ func encode(sc *stmtctx.StatementContext, b []byte, vals []types.Datum, comparab
 			b = EncodeFloat(b, vals[i].GetFloat64())
 		case types.KindString, types.KindBytes:
 			b = encodeBytes(b, vals[i].GetBytes(), comparable)
+		case types.KindMysqlDecimal:
+			b = append(b, decimalFlag)
+			b, err = EncodeDecimal(b, vals[i].GetMysqlDecimal(), vals[i].Length(), vals[i].Frac())
+			if sc != nil {
+				if terror.ErrorEqual(err, types.ErrTruncated) {
+					err = sc.HandleTruncate(err)
+				} else if terror.ErrorEqual(err, types.ErrOverflow) {
+					err = sc.HandleOverflow(err, err)
+				}
+			}
+			if err != nil {
+				return b, errors.Trace(err)
+			}
+		case types.KindMysqlTime:
+			b = append(b, uintFlag)
+			b, err = EncodeMySQLTime(sc, vals[i].GetMysqlTime(), mysql.TypeUnspecified, b)
+			if err != nil {
+				return b, errors.Trace(err)
+			}
+		case types.KindMysqlDuration:
+			// duration may have negative value, so we cannot use String to encode directly.
+			b = append(b, durationFlag)
+			b = EncodeInt(b, int64(vals[i].GetMysqlDuration().Duration))
+		case types.KindMysqlJSON:
+			b = append(b, jsonFlag)
+			j := vals[i].GetMysqlJSON()
+			b = append(b, j.TypeCode)
+			b = append(b, j.Value...)
 		case types.KindNull:
 			b = append(b, NilFlag)
 		case types.KindMinNotNull:
@@ -98,6 +127,28 @@ func encode(sc *stmtctx.StatementContext, b []byte, vals []types.Datum, comparab
 	return b, errors.Trace(err)
 }
 
+// EncodeMySQLTime encodes datum of `KindMysqlTime` to []byte.
+// TIMESTAMP values are converted from the session time zone to UTC first,
+// so that they are stored independently of the session that wrote them.
+func EncodeMySQLTime(sc *stmtctx.StatementContext, t types.Time, tp byte, b []byte) (_ []byte, err error) {
+	if tp == mysql.TypeUnspecified {
+		tp = t.Type()
+	}
+	if tp == mysql.TypeTimestamp && sc != nil && sc.TimeZone != nil && sc.TimeZone != time.UTC {
+		err = t.ConvertTimeZone(sc.TimeZone, time.UTC)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	var v uint64
+	v, err = t.ToPackedUint()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	b = EncodeUint(b, v)
+	return b, nil
+}
+
 // EstimateValueSize uses to estimate the value  size of the encoded values.
 func EstimateValueSize(sc *stmtctx.StatementContext, val types.Datum) (int, error) {
 	l := 0
@@ -110,6 +161,12 @@ func EstimateValueSize(sc *stmtctx.StatementContext, val types.Datum) (int, erro
 		l = 9
 	case types.KindString, types.KindBytes:
 		l = valueSizeOfBytes(val.GetBytes())
+	case types.KindMysqlDecimal:
+		l = valueSizeOfDecimal(val.GetMysqlDecimal(), val.Length(), val.Frac()) + 1
+	case types.KindMysqlTime, types.KindMysqlDuration:
+		l = 9
+	case types.KindMysqlJSON:
+		l = 2 + len(val.GetMysqlJSON().Value)
 	case types.KindNull, types.KindMinNotNull, types.KindMaxValue:
 		l = 1
 	default:
@@ -233,6 +290,28 @@ func encodeHashChunkRowIdx(sc *stmtctx.StatementContext, row chunk.Row, tp *type
 	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		flag = compactBytesFlag
 		b = row.GetBytes(idx)
+	case mysql.TypeNewDecimal:
+		flag = decimalFlag
+		// If hash is true, we only consider the original value of this decimal and ignore it's precision.
+		b, err = row.GetMyDecimal(idx).ToHashKey()
+		if err != nil {
+			return
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		flag = uintFlag
+		var v uint64
+		v, err = row.GetTime(idx).ToPackedUint()
+		if err != nil {
+			return
+		}
+		b = (*[unsafe.Sizeof(v)]byte)(unsafe.Pointer(&v))[:]
+	case mysql.TypeDuration:
+		flag = durationFlag
+		// duration may have negative value, so we cannot use String to encode directly.
+		b = row.GetRaw(idx)
+	case mysql.TypeJSON:
+		flag = jsonFlag
+		b = row.GetJSON(idx).HashValue(b)
 	default:
 		return 0, nil, errors.Errorf("unsupport column type for encode %d", tp.Tp)
 	}
@@ -241,13 +320,15 @@ func encodeHashChunkRowIdx(sc *stmtctx.StatementContext, row chunk.Row, tp *type
 
 // HashChunkColumns writes the encoded value of each row's column, which of index `colIdx`, to h.
 func HashChunkColumns(sc *stmtctx.StatementContext, h []hash.Hash64, chk *chunk.Chunk, tp *types.FieldType, colIdx int, buf []byte, isNull []bool) (err error) {
-	return HashChunkSelected(sc, h, chk, tp, colIdx, buf, isNull, nil)
+	return HashChunkSelected(sc, h, chk, tp, colIdx, buf, isNull, nil, false)
 }
 
 // HashChunkSelected writes the encoded value of selected row's column, which of index `colIdx`, to h.
 // sel indicates which rows are selected. If it is nil, all rows are selected.
+// If ignoreNull is true, a NULL value is hashed like any other value and isNull is not set,
+// which is needed by null-safe equal (`<=>`) join keys.
 func HashChunkSelected(sc *stmtctx.StatementContext, h []hash.Hash64, chk *chunk.Chunk, tp *types.FieldType, colIdx int, buf []byte,
-	isNull, sel []bool) (err error) {
+	isNull, sel []bool, ignoreNull bool) (err error) {
 	var b []byte
 	column := chk.Column(colIdx)
 	rows := chk.NumRows()
@@ -260,7 +341,7 @@ func HashChunkSelected(sc *stmtctx.StatementContext, h []hash.Hash64, chk *chunk
 			}
 			if column.IsNull(i) {
 				buf[0], b = NilFlag, nil
-				isNull[i] = true
+				isNull[i] = !ignoreNull
 			} else {
 				buf[0] = varintFlag
 				if mysql.HasUnsignedFlag(tp.Flag) && v < 0 {
@@ -282,7 +363,7 @@ func HashChunkSelected(sc *stmtctx.StatementContext, h []hash.Hash64, chk *chunk
 			}
 			if column.IsNull(i) {
 				buf[0], b = NilFlag, nil
-				isNull[i] = true
+				isNull[i] = !ignoreNull
 			} else {
 				buf[0] = floatFlag
 				d := float64(f)
@@ -302,7 +383,7 @@ func HashChunkSelected(sc *stmtctx.StatementContext, h []hash.Hash64, chk *chunk
 			}
 			if column.IsNull(i) {
 				buf[0], b = NilFlag, nil
-				isNull[i] = true
+				isNull[i] = !ignoreNull
 			} else {
 				buf[0] = floatFlag
 				b = (*[sizeFloat64]byte)(unsafe.Pointer(&f))[:]
@@ -320,12 +401,97 @@ func HashChunkSelected(sc *stmtctx.StatementContext, h []hash.Hash64, chk *chunk
 			}
 			if column.IsNull(i) {
 				buf[0], b = NilFlag, nil
-				isNull[i] = true
+				isNull[i] = !ignoreNull
 			} else {
 				buf[0] = compactBytesFlag
 				b = column.GetBytes(i)
 			}
 
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeNewDecimal:
+		for i := 0; i < rows; i++ {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = !ignoreNull
+			} else {
+				buf[0] = decimalFlag
+				// We only consider the original value of the decimal and ignore its precision,
+				// so that decimals which are equal but have different fractions hash to the same value.
+				b, err = column.GetDecimal(i).ToHashKey()
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		ts := column.Times()
+		for i, t := range ts {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = !ignoreNull
+			} else {
+				buf[0] = uintFlag
+				var v uint64
+				v, err = t.ToPackedUint()
+				if err != nil {
+					return errors.Trace(err)
+				}
+				b = (*[unsafe.Sizeof(v)]byte)(unsafe.Pointer(&v))[:]
+			}
+
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeDuration:
+		for i := 0; i < rows; i++ {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = !ignoreNull
+			} else {
+				buf[0] = durationFlag
+				// duration may have negative value, so we cannot use String to encode directly.
+				b = column.GetRaw(i)
+			}
+
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeJSON:
+		for i := 0; i < rows; i++ {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = !ignoreNull
+			} else {
+				buf[0] = jsonFlag
+				// Equal numbers of different types are hashed to the same value.
+				b = column.GetJSON(i).HashValue(b[:0])
+			}
+
 			// As the golang doc described, `Hash.Write` never returns an error.
 			// See https://golang.org/pkg/hash/#Hash
 			_, _ = h[i].Write(buf)
@@ -480,6 +646,34 @@ func DecodeOne(b []byte) (remain []byte, d types.Datum, err error) {
 		var v []byte
 		b, v, err = DecodeCompactBytes(b)
 		d.SetBytes(v)
+	case decimalFlag:
+		var (
+			dec             *types.MyDecimal
+			precision, frac int
+		)
+		b, dec, precision, frac, err = DecodeDecimal(b)
+		if err == nil {
+			d.SetMysqlDecimal(dec)
+			d.SetLength(precision)
+			d.SetFrac(frac)
+		}
+	case durationFlag:
+		var r int64
+		b, r, err = DecodeInt(b)
+		if err == nil {
+			// use max fsp, let outer to do round manually.
+			v := types.Duration{Duration: time.Duration(r), Fsp: types.MaxFsp}
+			d.SetMysqlDuration(v)
+		}
+	case jsonFlag:
+		var size int
+		size, err = types.PeekBytesAsJSON(b)
+		if err != nil {
+			return b, d, err
+		}
+		j := types.BinaryJSON{TypeCode: b[0], Value: b[1:size]}
+		d.SetMysqlJSON(j)
+		b = b[size:]
 	case NilFlag:
 	default:
 		return b, d, errors.Errorf("invalid encoded key flag %v", flag)
@@ -542,10 +736,14 @@ func peek(b []byte) (length int, err error) {
 		l, err = peekBytes(b)
 	case compactBytesFlag:
 		l, err = peekCompactBytes(b)
+	case decimalFlag:
+		l, err = peekDecimal(b)
 	case varintFlag:
 		l, err = peekVarint(b)
 	case uvarintFlag:
 		l, err = peekUvarint(b)
+	case jsonFlag:
+		l, err = types.PeekBytesAsJSON(b)
 	default:
 		return 0, errors.Errorf("invalid encoded key flag %v", flag)
 	}
@@ -680,6 +878,29 @@ func (decoder *Decoder) DecodeOne(b []byte, colIdx int, ft *types.FieldType) (re
 			return nil, errors.Trace(err)
 		}
 		chk.AppendBytes(colIdx, v)
+	case decimalFlag:
+		var dec *types.MyDecimal
+		b, dec, _, _, err = DecodeDecimal(b)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		chk.AppendMyDecimal(colIdx, dec)
+	case durationFlag:
+		var r int64
+		b, r, err = DecodeInt(b)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		v := types.Duration{Duration: time.Duration(r), Fsp: int8(ft.Decimal)}
+		chk.AppendDuration(colIdx, v)
+	case jsonFlag:
+		var size int
+		size, err = types.PeekBytesAsJSON(b)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		chk.AppendJSON(colIdx, types.BinaryJSON{TypeCode: b[0], Value: b[1:size]})
+		b = b[size:]
 	case NilFlag:
 		chk.AppendNull(colIdx)
 	default:
@@ -692,11 +913,33 @@ func (decoder *Decoder) DecodeOne(b []byte, colIdx int, ft *types.FieldType) (re
 }
 
 func appendIntToChunk(val int64, chk *chunk.Chunk, colIdx int, ft *types.FieldType) {
-	chk.AppendInt64(colIdx, val)
+	switch ft.Tp {
+	case mysql.TypeDuration:
+		v := types.Duration{Duration: time.Duration(val), Fsp: int8(ft.Decimal)}
+		chk.AppendDuration(colIdx, v)
+	default:
+		chk.AppendInt64(colIdx, val)
+	}
 }
 
 func appendUintToChunk(val uint64, chk *chunk.Chunk, colIdx int, ft *types.FieldType, loc *time.Location) error {
-	chk.AppendUint64(colIdx, val)
+	switch ft.Tp {
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		t := types.NewTime(types.ZeroCoreTime, ft.Tp, int8(ft.Decimal))
+		err := t.FromPackedUint(val)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if ft.Tp == mysql.TypeTimestamp && loc != nil && loc != time.UTC {
+			err = t.ConvertTimeZone(time.UTC, loc)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		chk.AppendTime(colIdx, t)
+	default:
+		chk.AppendUint64(colIdx, val)
+	}
 	return nil
 }
 
@@ -739,6 +982,53 @@ func HashGroupKey(sc *stmtctx.StatementContext, n int, col *chunk.Column, buf []
 				buf[i] = encodeBytes(buf[i], col.GetBytes(i), false)
 			}
 		}
+	case types.ETDecimal:
+		ds := col.Decimals()
+		for i := 0; i < n; i++ {
+			if col.IsNull(i) {
+				buf[i] = append(buf[i], NilFlag)
+				continue
+			}
+			buf[i] = append(buf[i], decimalFlag)
+			b, err := ds[i].ToHashKey()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			buf[i] = append(buf[i], b...)
+		}
+	case types.ETDatetime, types.ETTimestamp:
+		ts := col.Times()
+		for i := 0; i < n; i++ {
+			if col.IsNull(i) {
+				buf[i] = append(buf[i], NilFlag)
+				continue
+			}
+			v, err := ts[i].ToPackedUint()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			buf[i] = append(buf[i], uintFlag)
+			buf[i] = EncodeUint(buf[i], v)
+		}
+	case types.ETDuration:
+		ds := col.GoDurations()
+		for i := 0; i < n; i++ {
+			if col.IsNull(i) {
+				buf[i] = append(buf[i], NilFlag)
+			} else {
+				buf[i] = append(buf[i], durationFlag)
+				buf[i] = EncodeInt(buf[i], int64(ds[i]))
+			}
+		}
+	case types.ETJson:
+		for i := 0; i < n; i++ {
+			if col.IsNull(i) {
+				buf[i] = append(buf[i], NilFlag)
+			} else {
+				buf[i] = append(buf[i], jsonFlag)
+				buf[i] = col.GetJSON(i).HashValue(buf[i])
+			}
+		}
 	default:
 		return nil, errors.New(fmt.Sprintf("invalid eval type %v", ft.EvalType()))
 	}
@@ -0,0 +1,90 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package execdetails
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RuntimeStatsColl collects executors's execution info.
+type RuntimeStatsColl struct {
+	mu        sync.Mutex
+	rootStats map[string]*RuntimeStats
+}
+
+// RuntimeStats collects one executor's execution info.
+type RuntimeStats struct {
+	// executor's Next() called times.
+	loop int32
+	// executor consume time.
+	consume int64
+	// executor return row count.
+	rows int64
+	// spill is the number of times the executor spilled its data to disk.
+	spill int32
+}
+
+// NewRuntimeStatsColl creates new executor collector.
+func NewRuntimeStatsColl() *RuntimeStatsColl {
+	return &RuntimeStatsColl{rootStats: make(map[string]*RuntimeStats)}
+}
+
+// Get gets execStat for a executor.
+func (e *RuntimeStatsColl) Get(planID string) *RuntimeStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	runtimeStats, exists := e.rootStats[planID]
+	if !exists {
+		runtimeStats = &RuntimeStats{}
+		e.rootStats[planID] = runtimeStats
+	}
+	return runtimeStats
+}
+
+// Exists checks if the planID exists in the stats collection.
+func (e *RuntimeStatsColl) Exists(planID string) bool {
+	e.mu.Lock()
+	_, exists := e.rootStats[planID]
+	e.mu.Unlock()
+	return exists
+}
+
+// Record records executor's execution.
+func (e *RuntimeStats) Record(d time.Duration, rowNum int) {
+	atomic.AddInt32(&e.loop, 1)
+	atomic.AddInt64(&e.consume, int64(d))
+	atomic.AddInt64(&e.rows, int64(rowNum))
+}
+
+// RecordSpill records that the executor spilled its data to disk once.
+func (e *RuntimeStats) RecordSpill() {
+	atomic.AddInt32(&e.spill, 1)
+}
+
+// SpillCount returns how many times the executor spilled its data to disk.
+func (e *RuntimeStats) SpillCount() int {
+	return int(atomic.LoadInt32(&e.spill))
+}
+
+func (e *RuntimeStats) String() string {
+	result := fmt.Sprintf("time:%v, loops:%d, rows:%d", time.Duration(atomic.LoadInt64(&e.consume)),
+		atomic.LoadInt32(&e.loop), atomic.LoadInt64(&e.rows))
+	if spill := e.SpillCount(); spill > 0 {
+		result += fmt.Sprintf(", spill:%d", spill)
+	}
+	return result
+}
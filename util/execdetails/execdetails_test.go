@@ -0,0 +1,40 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package execdetails
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRuntimeStatsColl(t *testing.T) {
+	coll := NewRuntimeStatsColl()
+	if coll.Exists("Sort_1") {
+		t.Fatal("stats should not exist before Get")
+	}
+	stats := coll.Get("Sort_1")
+	if !coll.Exists("Sort_1") || coll.Get("Sort_1") != stats {
+		t.Fatal("Get should register and reuse the stats")
+	}
+	stats.Record(time.Second, 10)
+	stats.Record(time.Second, 5)
+	if str := stats.String(); str != "time:2s, loops:2, rows:15" {
+		t.Fatalf("unexpected stats %s", str)
+	}
+	stats.RecordSpill()
+	stats.RecordSpill()
+	if str := stats.String(); str != "time:2s, loops:2, rows:15, spill:2" {
+		t.Fatalf("unexpected stats %s", str)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package math
+
+import (
+	. "github.com/pingcap/check"
+)
+
+func (s *testMath) TestRandWithSeed(c *C) {
+	tests := [4]struct {
+		seed  int64
+		once  float64
+		twice float64
+	}{{0, 0.15522042769493574, 0.620881741513388},
+		{1, 0.40540353712197724, 0.8716141803857071},
+		{-1, 0.9050373219931845, 0.37014932126752037},
+		{9223372036854775807, 0.9050373219931845, 0.37014932126752037}}
+	for _, test := range tests {
+		rng := NewWithSeed(test.seed)
+		got1 := rng.Gen()
+		c.Assert(got1 == test.once, IsTrue, Commentf("seed %d: %v", test.seed, got1))
+		got2 := rng.Gen()
+		c.Assert(got2 == test.twice, IsTrue, Commentf("seed %d: %v", test.seed, got2))
+	}
+}
+
+func (s *testMath) TestRandWithTime(c *C) {
+	rng1 := NewWithTime()
+	for i := 0; i < 1000; i++ {
+		v := rng1.Gen()
+		c.Assert(v < 1.0, IsTrue)
+		c.Assert(v >= 0.0, IsTrue)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"sync"
+
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// ActionOnExceed is the action taken when memory usage exceeds memory quota.
+// NOTE: All the implementors should be thread-safe.
+type ActionOnExceed interface {
+	// Action will be called when memory usage exceeds memory quota by the
+	// corresponding Tracker.
+	Action(t *Tracker)
+	// SetFallback sets a fallback action which will be triggered if itself has
+	// already been triggered.
+	SetFallback(a ActionOnExceed)
+}
+
+// LogOnExceed logs a warning only once when memory usage exceeds memory quota.
+type LogOnExceed struct {
+	mutex  sync.Mutex // For synchronization.
+	acted  bool
+	ConnID uint64
+}
+
+// Action logs a warning only once when memory usage exceeds memory quota.
+func (a *LogOnExceed) Action(t *Tracker) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if !a.acted {
+		a.acted = true
+		logutil.BgLogger().Warn("memory exceeds quota",
+			zap.Uint64("connID", a.ConnID),
+			zap.String("tracker", t.String()))
+	}
+}
+
+// SetFallback sets a fallback action.
+func (a *LogOnExceed) SetFallback(ActionOnExceed) {}
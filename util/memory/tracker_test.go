@@ -0,0 +1,100 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/stringutil"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func TestT(t *testing.T) {
+	CustomVerboseFlag = true
+	TestingT(t)
+}
+
+var _ = Suite(&testSuite{})
+
+type testSuite struct{}
+
+func (s *testSuite) TestConsume(c *C) {
+	defer testleak.AfterTest(c)()
+
+	tracker := NewTracker(stringutil.StringerStr("root"), -1)
+	c.Assert(tracker.BytesConsumed(), Equals, int64(0))
+	tracker.Consume(100)
+	c.Assert(tracker.BytesConsumed(), Equals, int64(100))
+	tracker.Consume(-50)
+	c.Assert(tracker.BytesConsumed(), Equals, int64(50))
+	c.Assert(tracker.MaxConsumed(), Equals, int64(100))
+}
+
+func (s *testSuite) TestAttachTo(c *C) {
+	defer testleak.AfterTest(c)()
+
+	oldParent := NewTracker(stringutil.StringerStr("old parent"), -1)
+	newParent := NewTracker(stringutil.StringerStr("new parent"), -1)
+	child := NewTracker(stringutil.StringerStr("child"), -1)
+	child.Consume(100)
+	child.AttachTo(oldParent)
+	c.Assert(oldParent.BytesConsumed(), Equals, int64(100))
+
+	child.AttachTo(newParent)
+	c.Assert(oldParent.BytesConsumed(), Equals, int64(0))
+	c.Assert(newParent.BytesConsumed(), Equals, int64(100))
+
+	child.Consume(50)
+	c.Assert(newParent.BytesConsumed(), Equals, int64(150))
+
+	child.Detach()
+	c.Assert(newParent.BytesConsumed(), Equals, int64(0))
+	c.Assert(child.BytesConsumed(), Equals, int64(150))
+}
+
+type countOnExceed struct {
+	count    int
+	fallback ActionOnExceed
+}
+
+func (a *countOnExceed) Action(t *Tracker) {
+	a.count++
+}
+
+func (a *countOnExceed) SetFallback(fallback ActionOnExceed) {
+	a.fallback = fallback
+}
+
+func (s *testSuite) TestActionOnExceed(c *C) {
+	defer testleak.AfterTest(c)()
+
+	parent := NewTracker(stringutil.StringerStr("parent"), 100)
+	child := NewTracker(stringutil.StringerStr("child"), -1)
+	child.AttachTo(parent)
+
+	action := &countOnExceed{}
+	parent.FallbackOldAndSetNewAction(action)
+	c.Assert(action.fallback, NotNil)
+
+	child.Consume(99)
+	c.Assert(action.count, Equals, 0)
+	child.Consume(1)
+	c.Assert(action.count, Equals, 1)
+	// Releasing memory never triggers the action.
+	child.Consume(-1)
+	c.Assert(action.count, Equals, 1)
+	child.Consume(10)
+	c.Assert(action.count, Equals, 2)
+}
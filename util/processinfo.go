@@ -0,0 +1,29 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+// ProcessInfo is a struct describing a client connection, it is used by the kill statement.
+type ProcessInfo struct {
+	ID   uint64
+	User string
+	Host string
+}
+
+// SessionManager is an interface for session manage. Kill statement relies on this interface.
+type SessionManager interface {
+	// GetProcessInfo returns the information of the connection with the given id.
+	GetProcessInfo(id uint64) (*ProcessInfo, bool)
+	// Kill terminates the current statement of the connection, and the connection itself if query is false.
+	Kill(connectionID uint64, query bool)
+}
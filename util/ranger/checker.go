@@ -56,7 +56,11 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 	case ast.IsNull:
 		return c.checkColumn(scalar.GetArgs()[0])
 	case ast.UnaryNot:
-		if _, ok := scalar.GetArgs()[0].(*expression.ScalarFunction); ok {
+		if s, ok := scalar.GetArgs()[0].(*expression.ScalarFunction); ok {
+			// "not like" can't lead to a range.
+			if s.FuncName.L == ast.Like {
+				return false
+			}
 			return c.check(scalar.GetArgs()[0])
 		}
 		// "not column" or "not constant" can't lead to a range.
@@ -71,10 +75,50 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 			}
 		}
 		return true
+	case ast.Like:
+		return c.checkLikeFunc(scalar)
 	}
 	return false
 }
 
+// checkLikeFunc checks if `column LIKE pattern` can be converted to a range. The pattern must be a
+// constant string which doesn't start with a wildcard, and the prefix before the first wildcard is
+// used to build the range. The condition should be reserved in the filter conditions if the prefix
+// range is not equal to the pattern, e.g. `a like 'abc_'` or `a like 'abc%d'`.
+func (c *conditionChecker) checkLikeFunc(scalar *expression.ScalarFunction) bool {
+	if !c.checkColumn(scalar.GetArgs()[0]) || scalar.GetArgs()[0].GetType().EvalType() != types.ETString {
+		return false
+	}
+	pattern, ok := scalar.GetArgs()[1].(*expression.Constant)
+	if !ok || pattern.Value.IsNull() {
+		return false
+	}
+	if _, ok := scalar.GetArgs()[2].(*expression.Constant); !ok {
+		return false
+	}
+	patternStr, err := pattern.Value.ToString()
+	if err != nil {
+		return false
+	}
+	escape := byte(scalar.GetArgs()[2].(*expression.Constant).Value.GetInt64())
+	for i := 0; i < len(patternStr); i++ {
+		if patternStr[i] == escape {
+			i++
+			continue
+		}
+		if patternStr[i] == '%' || patternStr[i] == '_' {
+			if i == 0 {
+				return false
+			}
+			if patternStr[i] == '_' || i != len(patternStr)-1 {
+				c.shouldReserve = true
+			}
+			break
+		}
+	}
+	return true
+}
+
 func (c *conditionChecker) checkColumn(expr expression.Expression) bool {
 	col, ok := expr.(*expression.Column)
 	if !ok {
@@ -407,6 +407,74 @@ func (r *builder) buildFromIn(expr *expression.ScalarFunction) ([]point, bool) {
 	return rangePoints[:curPos], hasNull
 }
 
+// buildFromPatternLike builds the range of `column LIKE pattern` from the prefix of the pattern before the
+// first wildcard. e.g. the range of `a like 'abc%'` is ["abc", "abd").
+func (r *builder) buildFromPatternLike(expr *expression.ScalarFunction) []point {
+	pdt, err := expr.GetArgs()[1].(*expression.Constant).Eval(chunk.Row{})
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	pattern, err := pdt.ToString()
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	edt, err := expr.GetArgs()[2].(*expression.Constant).Eval(chunk.Row{})
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	escape := byte(edt.GetInt64())
+	lowValue := make([]byte, 0, len(pattern))
+	// exclude is true if the pattern contains '_' right after the prefix, e.g. `abc_x` doesn't match "abc".
+	var exclude bool
+	isExactMatch := true
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == escape {
+			i++
+			if i < len(pattern) {
+				lowValue = append(lowValue, pattern[i])
+			} else {
+				lowValue = append(lowValue, escape)
+			}
+			continue
+		}
+		if pattern[i] == '%' {
+			isExactMatch = false
+			break
+		} else if pattern[i] == '_' {
+			exclude = true
+			isExactMatch = false
+			break
+		}
+		lowValue = append(lowValue, pattern[i])
+	}
+	if isExactMatch {
+		val := types.NewStringDatum(string(lowValue))
+		return []point{{value: val, start: true}, {value: val}}
+	}
+	if len(lowValue) == 0 {
+		return []point{{value: types.MinNotNullDatum(), start: true}, {value: types.MaxValueDatum()}}
+	}
+	startPoint := point{start: true, excl: exclude}
+	startPoint.value.SetBytesAsString(lowValue)
+	// The end point is the smallest string which is larger than all the strings with the prefix,
+	// e.g. "abd" for "abc". It is +inf if all the bytes of the prefix are 0xff.
+	endPoint := point{value: types.MaxValueDatum()}
+	highValue := make([]byte, len(lowValue))
+	copy(highValue, lowValue)
+	for i := len(highValue) - 1; i >= 0; i-- {
+		highValue[i]++
+		if highValue[i] != 0 {
+			endPoint = point{excl: true}
+			endPoint.value.SetBytesAsString(highValue[:i+1])
+			break
+		}
+	}
+	return []point{startPoint, endPoint}
+}
+
 func (r *builder) buildFromNot(expr *expression.ScalarFunction) []point {
 	switch n := expr.FuncName.L; n {
 	case ast.In:
@@ -464,6 +532,8 @@ func (r *builder) buildFromScalarFunc(expr *expression.ScalarFunction) []point {
 		startPoint := point{start: true}
 		endPoint := point{}
 		return []point{startPoint, endPoint}
+	case ast.Like:
+		return r.buildFromPatternLike(expr)
 	case ast.UnaryNot:
 		return r.buildFromNot(expr.GetArgs()[0].(*expression.ScalarFunction))
 	}
@@ -460,6 +460,69 @@ func (s *testRangerSuite) TestIndexRange(c *C) {
 			filterConds: "[or(lt(test.t.d, 你好), gt(test.t.d, 你好))]",
 			resultStr:   "[[-inf,\"你好\") (\"你好\",+inf]]",
 		},
+		{
+			indexPos:    0,
+			exprStr:     `a LIKE 'abc%'`,
+			accessConds: "[like(test.t.a, abc%, 92)]",
+			filterConds: "[]",
+			resultStr:   "[[\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc_'",
+			accessConds: "[like(test.t.a, abc_, 92)]",
+			filterConds: "[like(test.t.a, abc_, 92)]",
+			resultStr:   "[(\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc%d'",
+			accessConds: "[like(test.t.a, abc%d, 92)]",
+			filterConds: "[like(test.t.a, abc%d, 92)]",
+			resultStr:   "[[\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'ab\\%c%'",
+			accessConds: "[like(test.t.a, ab\\%c%, 92)]",
+			filterConds: "[]",
+			resultStr:   "[[\"ab%c\",\"ab%d\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'ab|%c%' escape '|'",
+			accessConds: "[like(test.t.a, ab|%c%, 124)]",
+			filterConds: "[]",
+			resultStr:   "[[\"ab%c\",\"ab%d\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc'",
+			accessConds: "[eq(test.t.a, abc)]",
+			filterConds: "[]",
+			resultStr:   "[[\"abc\",\"abc\"]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE '%abc'",
+			accessConds: "[]",
+			filterConds: "[like(test.t.a, %abc, 92)]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a NOT LIKE 'abc%'",
+			accessConds: "[]",
+			filterConds: "[not(like(test.t.a, abc%, 92))]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    2,
+			exprStr:     "d LIKE 'abc%'",
+			accessConds: "[like(test.t.d, abc%, 92)]",
+			filterConds: "[like(test.t.d, abc%, 92)]",
+			resultStr:   "[[\"ab\",\"ab\"]]",
+		},
 	}
 
 	ctx := context.Background()
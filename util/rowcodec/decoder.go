@@ -124,18 +124,61 @@ func (decoder *DatumMapDecoder) decodeColDatum(col *ColInfo, colData []byte) (ty
 			return d, err
 		}
 		d.SetFloat64(fVal)
+	case mysql.TypeNewDecimal:
+		_, dec, precision, frac, err := codec.DecodeDecimal(colData)
+		if err != nil {
+			return d, err
+		}
+		d.SetMysqlDecimal(dec)
+		d.SetLength(precision)
+		d.SetFrac(frac)
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		t, err := decoder.decodeTime(col, colData)
+		if err != nil {
+			return d, err
+		}
+		d.SetMysqlTime(t)
+	case mysql.TypeDuration:
+		var dur types.Duration
+		dur.Duration = time.Duration(decodeInt(colData))
+		dur.Fsp = int8(col.Decimal)
+		d.SetMysqlDuration(dur)
 	case mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeString,
 		mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		d.SetBytes(colData)
 	case mysql.TypeBit:
 		byteSize := (col.Flen + 7) >> 3
 		d.SetMysqlBit(types.NewBinaryLiteralFromUint(decodeUint(colData), byteSize))
+	case mysql.TypeJSON:
+		var j types.BinaryJSON
+		j.TypeCode = colData[0]
+		j.Value = colData[1:]
+		d.SetMysqlJSON(j)
 	default:
 		return d, errors.Errorf("unknown type %d", col.Tp)
 	}
 	return d, nil
 }
 
+// decodeTime decodes a packed time value, TIMESTAMP values are stored in UTC
+// and converted back to the time zone of the decoder.
+func (decoder *decoder) decodeTime(col *ColInfo, colData []byte) (types.Time, error) {
+	var t types.Time
+	t.SetType(uint8(col.Tp))
+	t.SetFsp(int8(col.Decimal))
+	err := t.FromPackedUint(decodeUint(colData))
+	if err != nil {
+		return t, err
+	}
+	if byte(col.Tp) == mysql.TypeTimestamp && decoder.loc != nil && !t.IsZero() {
+		err = t.ConvertTimeZone(time.UTC, decoder.loc)
+		if err != nil {
+			return t, err
+		}
+	}
+	return t, nil
+}
+
 // ChunkDecoder decodes the row to chunk.Chunk.
 type ChunkDecoder struct {
 	decoder
@@ -217,12 +260,42 @@ func (decoder *ChunkDecoder) decodeColToChunk(colIdx int, col *ColInfo, colData
 			return err
 		}
 		chk.AppendFloat64(colIdx, fVal)
+	case mysql.TypeNewDecimal:
+		_, dec, _, frac, err := codec.DecodeDecimal(colData)
+		if err != nil {
+			return err
+		}
+		if col.Decimal != types.UnspecifiedLength && frac > col.Decimal {
+			to := new(types.MyDecimal)
+			err := dec.Round(to, col.Decimal, types.ModeHalfEven)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			dec = to
+		}
+		chk.AppendMyDecimal(colIdx, dec)
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		t, err := decoder.decodeTime(col, colData)
+		if err != nil {
+			return err
+		}
+		chk.AppendTime(colIdx, t)
+	case mysql.TypeDuration:
+		var dur types.Duration
+		dur.Duration = time.Duration(decodeInt(colData))
+		dur.Fsp = int8(col.Decimal)
+		chk.AppendDuration(colIdx, dur)
 	case mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeString,
 		mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		chk.AppendBytes(colIdx, colData)
 	case mysql.TypeBit:
 		byteSize := (col.Flen + 7) >> 3
 		chk.AppendBytes(colIdx, types.NewBinaryLiteralFromUint(decodeUint(colData), byteSize))
+	case mysql.TypeJSON:
+		var j types.BinaryJSON
+		j.TypeCode = colData[0]
+		j.Value = colData[1:]
+		chk.AppendJSON(colIdx, j)
 	default:
 		return errors.Errorf("unknown type %d", col.Tp)
 	}
@@ -67,6 +67,9 @@ type Statement interface {
 
 	// IsReadOnly returns if the statement is read only. For example: SelectStmt without lock.
 	IsReadOnly() bool
+
+	// RebuildPlan rebuilds the plan of the statement.
+	RebuildPlan(ctx context.Context) (schemaVersion int64, err error)
 }
 
 // RecordSet is an abstract result set interface to help get data from Plan.